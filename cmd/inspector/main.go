@@ -23,6 +23,7 @@ import (
 	"storj.io/common/rpc"
 	"storj.io/common/storj"
 	"storj.io/storj/pkg/process"
+	"storj.io/storj/satellite/internalpb"
 	"storj.io/uplink/eestream"
 )
 
@@ -48,7 +49,9 @@ var (
 	// ErrArgs throws when there are errors with CLI args
 	ErrArgs = errs.Class("error with CLI args:")
 
-	irreparableLimit int32
+	irreparableLimit  int32
+	repairQueueLimit  int32
+	retainFilterLimit int32
 
 	// Commander CLI
 	rootCmd = &cobra.Command{
@@ -80,6 +83,42 @@ var (
 		Args:  cobra.MinimumNArgs(4),
 		RunE:  SegmentHealth,
 	}
+	repairQueueCmd = &cobra.Command{
+		Use:   "repair-queue",
+		Short: "commands for inspecting and manipulating the repair queue",
+	}
+	listRepairQueueCmd = &cobra.Command{
+		Use:   "list",
+		Short: "list segments in the repair queue",
+		RunE:  listRepairQueue,
+	}
+	addRepairQueueCmd = &cobra.Command{
+		Use:   "add <segment-path>",
+		Short: "force a segment into the repair queue",
+		Args:  cobra.ExactArgs(1),
+		RunE:  addRepairQueue,
+	}
+	removeRepairQueueCmd = &cobra.Command{
+		Use:   "remove <segment-path>",
+		Short: "remove a segment from the repair queue",
+		Args:  cobra.ExactArgs(1),
+		RunE:  removeRepairQueue,
+	}
+	repairSegmentCmd = &cobra.Command{
+		Use:   "repair <segment-path>",
+		Short: "repair a segment immediately",
+		Args:  cobra.ExactArgs(1),
+		RunE:  repairSegment,
+	}
+	gcCmd = &cobra.Command{
+		Use:   "gc",
+		Short: "commands for inspecting garbage collection",
+	}
+	listRetainFiltersCmd = &cobra.Command{
+		Use:   "filters",
+		Short: "list which garbage collection filter generation every node has and whether it was sent",
+		RunE:  listRetainFilters,
+	}
 	paymentsCmd = &cobra.Command{
 		Use:   "payments",
 		Short: "commands for payments",
@@ -109,12 +148,14 @@ var (
 
 // Inspector gives access to overlay.
 type Inspector struct {
-	conn           *rpc.Conn
-	identity       *identity.FullIdentity
-	overlayclient  pb.DRPCOverlayInspectorClient
-	irrdbclient    pb.DRPCIrreparableInspectorClient
-	healthclient   pb.DRPCHealthInspectorClient
-	paymentsClient pb.DRPCPaymentsClient
+	conn              *rpc.Conn
+	identity          *identity.FullIdentity
+	overlayclient     pb.DRPCOverlayInspectorClient
+	irrdbclient       pb.DRPCIrreparableInspectorClient
+	healthclient      pb.DRPCHealthInspectorClient
+	paymentsClient    pb.DRPCPaymentsClient
+	repairQueueClient internalpb.DRPCRepairQueueInspectorClient
+	gcClient          internalpb.DRPCGarbageCollectionInspectorClient
 }
 
 // NewInspector creates a new gRPC inspector client for access to overlay.
@@ -135,12 +176,14 @@ func NewInspector(address, path string) (*Inspector, error) {
 	}
 
 	return &Inspector{
-		conn:           conn,
-		identity:       id,
-		overlayclient:  pb.NewDRPCOverlayInspectorClient(conn.Raw()),
-		irrdbclient:    pb.NewDRPCIrreparableInspectorClient(conn.Raw()),
-		healthclient:   pb.NewDRPCHealthInspectorClient(conn.Raw()),
-		paymentsClient: pb.NewDRPCPaymentsClient(conn.Raw()),
+		conn:              conn,
+		identity:          id,
+		overlayclient:     pb.NewDRPCOverlayInspectorClient(conn.Raw()),
+		irrdbclient:       pb.NewDRPCIrreparableInspectorClient(conn.Raw()),
+		healthclient:      pb.NewDRPCHealthInspectorClient(conn.Raw()),
+		paymentsClient:    pb.NewDRPCPaymentsClient(conn.Raw()),
+		repairQueueClient: internalpb.NewDRPCRepairQueueInspectorClient(conn.Raw()),
+		gcClient:          internalpb.NewDRPCGarbageCollectionInspectorClient(conn.Raw()),
 	}, nil
 }
 
@@ -451,6 +494,174 @@ func sortSegments(segments []*pb.IrreparableSegment) map[string][]*pb.Irreparabl
 	return objects
 }
 
+func listRepairQueue(cmd *cobra.Command, args []string) (err error) {
+	if repairQueueLimit <= int32(0) {
+		return ErrArgs.New("limit must be greater than 0")
+	}
+
+	i, err := NewInspector(*Addr, *IdentityPath)
+	if err != nil {
+		return ErrInspectorDial.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, i.Close()) }()
+
+	var cursorPath []byte
+
+	// query DB and paginate results
+	for {
+		req := &internalpb.ListRepairQueueRequest{
+			Limit:      repairQueueLimit,
+			CursorPath: cursorPath,
+		}
+		res, err := i.repairQueueClient.ListRepairQueue(context.Background(), req)
+		if err != nil {
+			return ErrRequest.Wrap(err)
+		}
+
+		if len(res.Segments) == 0 {
+			break
+		}
+		cursorPath = res.Segments[len(res.Segments)-1].Path
+
+		// format and print segments
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(res.Segments)
+		if err != nil {
+			return err
+		}
+
+		if !res.More || !prompt.Confirm("\nNext page? (y/n)") {
+			break
+		}
+	}
+	return nil
+}
+
+func listRetainFilters(cmd *cobra.Command, args []string) (err error) {
+	if retainFilterLimit <= int32(0) {
+		return ErrArgs.New("limit must be greater than 0")
+	}
+
+	i, err := NewInspector(*Addr, *IdentityPath)
+	if err != nil {
+		return ErrInspectorDial.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, i.Close()) }()
+
+	type retainFilter struct {
+		NodeID        storj.NodeID
+		CreationDate  time.Time
+		PieceCount    int64
+		FilterSize    int64
+		SentAt        *time.Time
+		SendAttempts  int32
+		LastAttemptAt *time.Time
+	}
+
+	var cursor []byte
+	for {
+		res, err := i.gcClient.ListRetainFilters(context.Background(), &internalpb.ListRetainFiltersRequest{
+			Limit:        retainFilterLimit,
+			CursorNodeId: cursor,
+		})
+		if err != nil {
+			return ErrRequest.Wrap(err)
+		}
+
+		if len(res.Filters) == 0 {
+			break
+		}
+		cursor = res.Filters[len(res.Filters)-1].NodeId
+
+		var filters []retainFilter
+		for _, filter := range res.Filters {
+			nodeID, err := storj.NodeIDFromBytes(filter.NodeId)
+			if err != nil {
+				return err
+			}
+			filters = append(filters, retainFilter{
+				NodeID:        nodeID,
+				CreationDate:  filter.CreationDate,
+				PieceCount:    filter.PieceCount,
+				FilterSize:    filter.FilterSize,
+				SentAt:        filter.SentAt,
+				SendAttempts:  filter.SendAttempts,
+				LastAttemptAt: filter.LastAttemptAt,
+			})
+		}
+
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(filters)
+		if err != nil {
+			return err
+		}
+
+		if !res.More || !prompt.Confirm("\nNext page? (y/n)") {
+			break
+		}
+	}
+	return nil
+}
+
+func addRepairQueue(cmd *cobra.Command, args []string) (err error) {
+	ctx, _ := process.Ctx(cmd)
+	i, err := NewInspector(*Addr, *IdentityPath)
+	if err != nil {
+		return ErrInspectorDial.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, i.Close()) }()
+
+	res, err := i.repairQueueClient.AddRepairQueue(ctx, &internalpb.AddRepairQueueRequest{
+		Path: []byte(args[0]),
+	})
+	if err != nil {
+		return ErrRequest.Wrap(err)
+	}
+
+	fmt.Printf("successfully added segment to repair queue with health %f\n", res.SegmentHealth)
+	return nil
+}
+
+func removeRepairQueue(cmd *cobra.Command, args []string) (err error) {
+	ctx, _ := process.Ctx(cmd)
+	i, err := NewInspector(*Addr, *IdentityPath)
+	if err != nil {
+		return ErrInspectorDial.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, i.Close()) }()
+
+	_, err = i.repairQueueClient.RemoveRepairQueue(ctx, &internalpb.RemoveRepairQueueRequest{
+		Path: []byte(args[0]),
+	})
+	if err != nil {
+		return ErrRequest.Wrap(err)
+	}
+
+	fmt.Println("successfully removed segment from repair queue")
+	return nil
+}
+
+func repairSegment(cmd *cobra.Command, args []string) (err error) {
+	ctx, _ := process.Ctx(cmd)
+	i, err := NewInspector(*Addr, *IdentityPath)
+	if err != nil {
+		return ErrInspectorDial.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, i.Close()) }()
+
+	_, err = i.repairQueueClient.RepairSegment(ctx, &internalpb.RepairSegmentRequest{
+		Path: []byte(args[0]),
+	})
+	if err != nil {
+		return ErrRequest.Wrap(err)
+	}
+
+	fmt.Println("successfully repaired segment")
+	return nil
+}
+
 func prepareInvoiceRecords(cmd *cobra.Command, args []string) error {
 	ctx, _ := process.Ctx(cmd)
 	i, err := NewInspector(*Addr, *IdentityPath)
@@ -561,10 +772,19 @@ func init() {
 	rootCmd.AddCommand(irreparableCmd)
 	rootCmd.AddCommand(healthCmd)
 	rootCmd.AddCommand(paymentsCmd)
+	rootCmd.AddCommand(repairQueueCmd)
+	rootCmd.AddCommand(gcCmd)
 
 	healthCmd.AddCommand(objectHealthCmd)
 	healthCmd.AddCommand(segmentHealthCmd)
 
+	repairQueueCmd.AddCommand(listRepairQueueCmd)
+	repairQueueCmd.AddCommand(addRepairQueueCmd)
+	repairQueueCmd.AddCommand(removeRepairQueueCmd)
+	repairQueueCmd.AddCommand(repairSegmentCmd)
+
+	gcCmd.AddCommand(listRetainFiltersCmd)
+
 	paymentsCmd.AddCommand(prepareInvoiceRecordsCmd)
 	paymentsCmd.AddCommand(createInvoiceItemsCmd)
 	paymentsCmd.AddCommand(createInvoiceCouponsCmd)
@@ -573,6 +793,8 @@ func init() {
 	objectHealthCmd.Flags().StringVar(&CSVPath, "csv-path", "stdout", "csv path where command output is written")
 
 	irreparableCmd.Flags().Int32Var(&irreparableLimit, "limit", 50, "max number of results per page")
+	listRepairQueueCmd.Flags().Int32Var(&repairQueueLimit, "limit", 50, "max number of results per page")
+	listRetainFiltersCmd.Flags().Int32Var(&retainFilterLimit, "limit", 50, "max number of results per page")
 
 	flag.Parse()
 }
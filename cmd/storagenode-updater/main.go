@@ -8,6 +8,9 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"io"
 	"io/ioutil"
@@ -22,6 +25,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/spacemonkeygo/monkit/v3"
 	"github.com/spf13/cobra"
 	"github.com/zeebo/errs"
 	"go.uber.org/zap"
@@ -43,6 +47,12 @@ const (
 )
 
 var (
+	mon = monkit.Package()
+
+	// releasePublicKey is the hex-encoded ed25519 public key which signs the
+	// release binaries. It's set by linker flags in scripts/release.sh.
+	releasePublicKey string
+
 	cancel context.CancelFunc
 	// TODO: replace with config value of random bytes in storagenode config.
 	nodeID storj.NodeID
@@ -65,6 +75,8 @@ var (
 
 		BinaryLocation string `help:"the storage node executable binary location" default:"storagenode.exe"`
 		ServiceName    string `help:"storage node OS service name" default:"storagenode"`
+
+		Restart RestartConfig
 		// deprecated
 		Log string `help:"deprecated, use --log.output" default:""`
 	}
@@ -105,6 +117,12 @@ func cmdRun(cmd *cobra.Command, args []string) (err error) {
 		zap.S().Fatal("Empty node ID")
 	}
 
+	if runtime.GOOS != "windows" {
+		if err := runCfg.Restart.Validate(runCfg.ServiceName, updaterServiceName); err != nil {
+			zap.S().Fatalf("Invalid restart configuration: %v", err)
+		}
+	}
+
 	var ctx context.Context
 	ctx, cancel = process.Ctx(cmd)
 	c := make(chan os.Signal, 1)
@@ -187,27 +205,20 @@ func update(ctx context.Context, binPath, serviceName string) (err error) {
 		return nil
 	}
 
-	tempArchive, err := ioutil.TempFile("", serviceName)
-	if err != nil {
-		return errs.New("cannot create temporary archive: %v", err)
+	platform := version.Platform(runtime.GOOS, runtime.GOARCH)
+	binary, ok := processVersion.Suggested.Binaries[platform]
+	if !ok {
+		return errs.New("no signed %s binary published for %s", serviceName, platform)
 	}
-	defer func() {
-		err = errs.Combine(err,
-			tempArchive.Close(),
-			os.Remove(tempArchive.Name()),
-		)
-	}()
 
-	downloadURL := parseDownloadURL(processVersion.Suggested.URL)
-	zap.S().Infof("Start downloading %s to %s", downloadURL, tempArchive.Name())
-	err = downloadArchive(ctx, tempArchive, downloadURL)
+	publicKey, err := binaryPublicKey()
 	if err != nil {
 		return errs.Wrap(err)
 	}
-	zap.S().Infof("Finished downloading %s to %s", downloadURL, tempArchive.Name())
 
+	downloadURL := parseDownloadURL(processVersion.Suggested.URL)
 	newVersionPath := prependExtension(binPath, suggestedVersion.String())
-	err = unpackBinary(ctx, tempArchive.Name(), newVersionPath)
+	err = downloadBinary(ctx, serviceName, downloadURL, binary, publicKey, suggestedVersion, platform, newVersionPath)
 	if err != nil {
 		return errs.Wrap(err)
 	}
@@ -216,11 +227,14 @@ func update(ctx context.Context, binPath, serviceName string) (err error) {
 
 	downloadedVersion, err := binaryVersion(newVersionPath)
 	if err != nil {
-		return errs.Wrap(err)
+		return errs.Combine(errs.Wrap(err), os.Remove(newVersionPath))
 	}
 
 	if suggestedVersion.Compare(downloadedVersion) != 0 {
-		return errs.New("invalid version downloaded: wants %s got %s", suggestedVersion.String(), downloadedVersion.String())
+		return errs.Combine(
+			errs.New("invalid version downloaded: wants %s got %s", suggestedVersion.String(), downloadedVersion.String()),
+			os.Remove(newVersionPath),
+		)
 	}
 
 	// backup original binary
@@ -237,14 +251,20 @@ func update(ctx context.Context, binPath, serviceName string) (err error) {
 
 	// rename new binary to replace original
 	if err := os.Rename(newVersionPath, binPath); err != nil {
-		return errs.Wrap(err)
+		return errs.Combine(errs.Wrap(err), os.Rename(backupPath, binPath))
 	}
 
 	zap.S().Infof("Restarting service %s", serviceName)
-	err = restartService(serviceName)
+	err = restartService(ctx, serviceName, binPath)
 	if err != nil {
-		// TODO: should we try to recover from this?
-		return errs.New("Unable to restart service: %v", err)
+		rollbackErr := rollback(ctx, binPath, backupPath, serviceName)
+		return errs.Combine(errs.New("Unable to restart service: %v", err), rollbackErr)
+	}
+
+	err = verifyRestart(ctx, serviceName, binPath, suggestedVersion)
+	if err != nil {
+		rollbackErr := rollback(ctx, binPath, backupPath, serviceName)
+		return errs.Combine(errs.New("Restarted service failed: %v", err), rollbackErr)
 	}
 	zap.S().Infof("Service %s restarted successfully", serviceName)
 
@@ -252,6 +272,89 @@ func update(ctx context.Context, binPath, serviceName string) (err error) {
 	return nil
 }
 
+// verifyRestart checks that the restarted service runs the expected version
+// and keeps running for the health delay.
+func verifyRestart(ctx context.Context, serviceName, binPath string, expected version.SemVer) error {
+	restartedVersion, err := binaryVersion(binPath)
+	if err != nil {
+		return errs.New("unable to check version: %v", err)
+	}
+	if expected.Compare(restartedVersion) != 0 {
+		return errs.New("invalid version restarted: wants %s got %s", expected.String(), restartedVersion.String())
+	}
+
+	if !sync2.Sleep(ctx, runCfg.Restart.HealthDelay) {
+		return ctx.Err()
+	}
+	return checkService(ctx, serviceName)
+}
+
+// rollback replaces the failed new binary with the backup of the previous one
+// and restarts the service.
+func rollback(ctx context.Context, binPath, backupPath, serviceName string) error {
+	mon.Event("binary_rollback")
+	zap.S().Errorf("New %s binary failed, rolling back to %s", serviceName, backupPath)
+
+	if err := os.Remove(binPath); err != nil {
+		return errs.New("Unable to remove failed binary: %v", err)
+	}
+	if err := os.Rename(backupPath, binPath); err != nil {
+		return errs.New("Unable to restore previous binary: %v", err)
+	}
+
+	if err := restartService(ctx, serviceName, binPath); err != nil {
+		mon.Event("binary_rollback_failed")
+		return errs.New("Unable to restart service with previous binary: %v", err)
+	}
+	zap.S().Infof("Service %s rolled back successfully", serviceName)
+	return nil
+}
+
+// binaryPublicKey returns the pinned public key which verifies the binaries.
+func binaryPublicKey() (ed25519.PublicKey, error) {
+	if releasePublicKey == "" {
+		return nil, version.ErrVerification.New("no public key to verify binaries")
+	}
+
+	publicKey, err := hex.DecodeString(releasePublicKey)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return nil, version.ErrVerification.New("invalid public key %q", releasePublicKey)
+	}
+	return ed25519.PublicKey(publicKey), nil
+}
+
+// downloadBinary downloads the archive of the binary, verifies it for the
+// version and the platform and unpacks the binary to the target.
+func downloadBinary(ctx context.Context, serviceName, downloadURL string, binary version.Binary, publicKey ed25519.PublicKey, release version.SemVer, platform, target string) (err error) {
+	tempArchive, err := ioutil.TempFile("", serviceName)
+	if err != nil {
+		return errs.New("cannot create temporary archive: %v", err)
+	}
+	defer func() {
+		err = errs.Combine(err,
+			tempArchive.Close(),
+			os.Remove(tempArchive.Name()),
+		)
+	}()
+
+	zap.S().Infof("Start downloading %s to %s", downloadURL, tempArchive.Name())
+	digest := sha256.New()
+	err = downloadArchive(ctx, io.MultiWriter(tempArchive, digest), downloadURL)
+	if err != nil {
+		return err
+	}
+	zap.S().Infof("Finished downloading %s to %s", downloadURL, tempArchive.Name())
+
+	// verify the archive before unpacking anything from it
+	if err := binary.Verify(publicKey, release, platform, digest.Sum(nil)); err != nil {
+		mon.Event("binary_verification_failed")
+		return err
+	}
+	zap.S().Infof("Verified %s digest %s", downloadURL, binary.SHA256)
+
+	return unpackBinary(ctx, tempArchive.Name(), target)
+}
+
 func prependExtension(path, ext string) string {
 	originalExt := filepath.Ext(path)
 	dir, base := filepath.Split(path)
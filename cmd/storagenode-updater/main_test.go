@@ -6,6 +6,9 @@ package main_test
 import (
 	"archive/zip"
 	"compress/flate"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -14,6 +17,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"testing"
 	"time"
@@ -55,8 +59,11 @@ func TestAutoUpdater(t *testing.T) {
 		Release:    false,
 	}
 
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
 	// build real bin with old version, will be used for both storagenode and updater
-	oldBin := CompileWithVersion(ctx, "storj.io/storj/cmd/storagenode-updater", oldInfo)
+	oldBin := CompileWithVersion(ctx, "storj.io/storj/cmd/storagenode-updater", oldInfo, publicKey)
 	storagenodePath := ctx.File("fake", "storagenode.exe")
 	copyBin(ctx, t, oldBin, storagenodePath)
 
@@ -70,7 +77,7 @@ func TestAutoUpdater(t *testing.T) {
 		Version:    newSemVer,
 		Release:    false,
 	}
-	newBin := CompileWithVersion(ctx, "storj.io/storj/cmd/storagenode-updater", newInfo)
+	newBin := CompileWithVersion(ctx, "storj.io/storj/cmd/storagenode-updater", newInfo, publicKey)
 
 	updateBins := map[string]string{
 		"storagenode":         newBin,
@@ -78,7 +85,7 @@ func TestAutoUpdater(t *testing.T) {
 	}
 
 	// run versioncontrol and update zips http servers
-	versionControlPeer, cleanupVersionControl := testVersionControlWithUpdates(ctx, t, updateBins)
+	versionControlPeer, cleanupVersionControl := testVersionControlWithUpdates(ctx, t, updateBins, privateKey)
 	defer cleanupVersionControl()
 
 	logPath := ctx.File("storagenode-updater.log")
@@ -94,6 +101,8 @@ func TestAutoUpdater(t *testing.T) {
 		"--check-interval", "0s",
 		"--identity.cert-path", identConfig.CertPath,
 		"--identity.key-path", identConfig.KeyPath,
+		"--restart.method", "none",
+		"--restart.health-delay", "0s",
 		"--log", logPath,
 	}
 
@@ -104,6 +113,9 @@ func TestAutoUpdater(t *testing.T) {
 	if assert.NoError(t, logErr) {
 		logStr := string(logData)
 		t.Log(logStr)
+		if !assert.Contains(t, logStr, "Verified") {
+			t.Log(logStr)
+		}
 		if !assert.Contains(t, logStr, "storagenode restarted successfully") {
 			t.Log(logStr)
 		}
@@ -130,10 +142,88 @@ func TestAutoUpdater(t *testing.T) {
 	require.NotZero(t, backupUpdaterInfo.Size())
 }
 
+func TestAutoUpdater_InvalidSignature(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	oldSemVer, err := version.NewSemVer(oldVersion)
+	require.NoError(t, err)
+
+	newSemVer, err := version.NewSemVer(newVersion)
+	require.NoError(t, err)
+
+	// the binaries are signed with a different key than the pinned one
+	publicKey, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	_, otherPrivateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	oldBin := CompileWithVersion(ctx, "storj.io/storj/cmd/storagenode-updater", version.Info{
+		Timestamp: time.Now(),
+		Version:   oldSemVer,
+	}, publicKey)
+	storagenodePath := ctx.File("fake", "storagenode.exe")
+	copyBin(ctx, t, oldBin, storagenodePath)
+
+	updaterPath := ctx.File("fake", "storagenode-updater.exe")
+	move(t, oldBin, updaterPath)
+
+	newBin := CompileWithVersion(ctx, "storj.io/storj/cmd/storagenode-updater", version.Info{
+		Timestamp: time.Now(),
+		Version:   newSemVer,
+	}, publicKey)
+
+	updateBins := map[string]string{
+		"storagenode":         newBin,
+		"storagenode-updater": newBin,
+	}
+
+	versionControlPeer, cleanupVersionControl := testVersionControlWithUpdates(ctx, t, updateBins, otherPrivateKey)
+	defer cleanupVersionControl()
+
+	logPath := ctx.File("storagenode-updater.log")
+	identConfig := testIdentityFiles(ctx, t)
+
+	args := []string{"run",
+		"--config-dir", ctx.Dir(),
+		"--server-address", "http://" + versionControlPeer.Addr(),
+		"--binary-location", storagenodePath,
+		"--check-interval", "0s",
+		"--identity.cert-path", identConfig.CertPath,
+		"--identity.key-path", identConfig.KeyPath,
+		"--restart.method", "none",
+		"--restart.health-delay", "0s",
+		"--log", logPath,
+	}
+
+	out, err := exec.Command(updaterPath, args...).CombinedOutput()
+	if !assert.NoError(t, err) {
+		t.Log(string(out))
+	}
+
+	logData, err := ioutil.ReadFile(logPath)
+	require.NoError(t, err)
+	logStr := string(logData)
+	assert.Contains(t, logStr, "binary verification error", logStr)
+	assert.NotContains(t, logStr, "restarted successfully", logStr)
+
+	// nothing was replaced
+	_, err = os.Stat(ctx.File("fake", "storagenode"+".old."+oldVersion+".exe"))
+	require.True(t, os.IsNotExist(err))
+	_, err = os.Stat(ctx.File("fake", "storagenode"+"."+newVersion+".exe"))
+	require.True(t, os.IsNotExist(err))
+
+	currentVersion, err := exec.Command(storagenodePath, "version").CombinedOutput()
+	require.NoError(t, err)
+	require.Contains(t, string(currentVersion), oldVersion)
+}
+
 // CompileWithVersion compiles the specified package with the version variables set
-// to the passed version info values and returns the executable name.
-func CompileWithVersion(ctx *testcontext.Context, pkg string, info version.Info) string {
+// to the passed version info values and the release key pinned to publicKey, and
+// returns the executable name.
+func CompileWithVersion(ctx *testcontext.Context, pkg string, info version.Info, publicKey ed25519.PublicKey) string {
 	ldFlagsX := map[string]string{
+		"main.releasePublicKey":                          hex.EncodeToString(publicKey),
 		"storj.io/storj/private/version.buildTimestamp":  strconv.Itoa(int(info.Timestamp.Unix())),
 		"storj.io/storj/private/version.buildCommitHash": info.CommitHash,
 		"storj.io/storj/private/version.buildVersion":    info.Version.String(),
@@ -184,16 +274,26 @@ func testIdentityFiles(ctx *testcontext.Context, t *testing.T) identity.Config {
 	return identConfig
 }
 
-func testVersionControlWithUpdates(ctx *testcontext.Context, t *testing.T, updateBins map[string]string) (peer *versioncontrol.Peer, cleanup func()) {
+func testVersionControlWithUpdates(ctx *testcontext.Context, t *testing.T, updateBins map[string]string, privateKey ed25519.PrivateKey) (peer *versioncontrol.Peer, cleanup func()) {
 	t.Helper()
 
 	var mux http.ServeMux
+	newSemVer, err := version.NewSemVer(newVersion)
+	require.NoError(t, err)
+
+	binaries := map[string]version.Binaries{}
 	for name, src := range updateBins {
 		dst := ctx.File("updates", name+".zip")
 		zipBin(ctx, t, dst, src)
 		zipData, err := ioutil.ReadFile(dst)
 		require.NoError(t, err)
 
+		digest := sha256.Sum256(zipData)
+		platform := version.Platform(runtime.GOOS, runtime.GOARCH)
+		binaries[name] = version.Binaries{
+			platform: version.SignBinary(privateKey, newSemVer, platform, digest[:]),
+		}
+
 		mux.HandleFunc("/"+name, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			_, err := w.Write(zipData)
 			require.NoError(t, err)
@@ -222,8 +322,9 @@ func testVersionControlWithUpdates(ctx *testcontext.Context, t *testing.T, updat
 		Binary: versioncontrol.ProcessesConfig{
 			Storagenode: versioncontrol.ProcessConfig{
 				Suggested: versioncontrol.VersionConfig{
-					Version: newVersion,
-					URL:     ts.URL + "/storagenode",
+					Version:  newVersion,
+					URL:      ts.URL + "/storagenode",
+					Binaries: binaries["storagenode"],
 				},
 				Rollout: versioncontrol.RolloutConfig{
 					Seed:   storagenodeSeed,
@@ -232,8 +333,9 @@ func testVersionControlWithUpdates(ctx *testcontext.Context, t *testing.T, updat
 			},
 			StoragenodeUpdater: versioncontrol.ProcessConfig{
 				Suggested: versioncontrol.VersionConfig{
-					Version: newVersion,
-					URL:     ts.URL + "/storagenode-updater",
+					Version:  newVersion,
+					URL:      ts.URL + "/storagenode-updater",
+					Binaries: binaries["storagenode-updater"],
 				},
 				Rollout: versioncontrol.RolloutConfig{
 					Seed:   updaterSeed,
@@ -242,7 +344,7 @@ func testVersionControlWithUpdates(ctx *testcontext.Context, t *testing.T, updat
 			},
 		},
 	}
-	peer, err := versioncontrol.New(zaptest.NewLogger(t), config)
+	peer, err = versioncontrol.New(zaptest.NewLogger(t), config)
 	require.NoError(t, err)
 	ctx.Go(func() error {
 		return peer.Run(ctx)
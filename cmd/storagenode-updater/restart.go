@@ -6,16 +6,18 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strings"
 
 	"github.com/zeebo/errs"
 )
 
-func restartService(name string) error {
+func restartService(ctx context.Context, name, binPath string) error {
 	switch runtime.GOOS {
 	case "windows":
 		// TODO: cleanup temp .bat file
@@ -39,7 +41,31 @@ func restartService(name string) error {
 			return errs.New("%s", string(out))
 		}
 	default:
-		return nil
+		restarter, err := newRestarter(runCfg.Restart, name)
+		if err != nil {
+			return err
+		}
+		return restarter.Restart(ctx, name, binPath)
+	}
+	return nil
+}
+
+func checkService(ctx context.Context, name string) error {
+	switch runtime.GOOS {
+	case "windows":
+		out, err := exec.Command("sc", "query", name).CombinedOutput()
+		if err != nil {
+			return errs.New("%s", string(out))
+		}
+		if !strings.Contains(string(out), "RUNNING") {
+			return errs.New("service %s isn't running: %s", name, string(out))
+		}
+	default:
+		restarter, err := newRestarter(runCfg.Restart, name)
+		if err != nil {
+			return err
+		}
+		return restarter.Check(ctx, name)
 	}
 	return nil
 }
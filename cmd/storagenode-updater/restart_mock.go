@@ -5,4 +5,8 @@
 
 package main
 
-func restartService(name string) error { return nil }
+import "context"
+
+func restartService(ctx context.Context, name, binPath string) error { return nil }
+
+func checkService(ctx context.Context, name string) error { return nil }
@@ -0,0 +1,286 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+)
+
+// RestartError is the error class for failed service restarts.
+var RestartError = errs.Class("restart error")
+
+// Restart methods.
+const (
+	restartAuto    = "auto"
+	restartSystemd = "systemd"
+	restartSignal  = "signal"
+	restartExec    = "exec"
+	restartNone    = "none"
+)
+
+// systemdRuntimeDir only exists when the system was booted with systemd.
+var systemdRuntimeDir = "/run/systemd/system"
+
+// RestartConfig defines how services are restarted on systems other than
+// Windows, where the service manager is always used.
+type RestartConfig struct {
+	Method       string        `help:"how to restart services after an update: auto (systemd when the system was booted with it, signal when a PID file is configured, an error otherwise), systemd (restart the unit), signal (signal the PID in the PID file and wait for the supervisor to restart it, the updater replaces its own process), exec (for containers, the updater replaces its own process with the new binary and signals the storage node like the signal method) or none" default:"auto"`
+	Systemctl    string        `help:"systemctl binary which restarts the units for the systemd method" default:"systemctl"`
+	PIDFile      string        `help:"file containing the PID of the storage node for the signal method" default:""`
+	Signal       string        `help:"signal which stops the storage node for the signal method" default:"SIGTERM"`
+	Timeout      time.Duration `help:"how long to wait until a restarted service runs again" default:"1m0s"`
+	PollInterval time.Duration `help:"how often to check whether a restarted service runs again" default:"1s"`
+	HealthDelay  time.Duration `help:"how long a restarted service has to keep running before the update is kept, it's rolled back otherwise" default:"30s"`
+}
+
+// method returns the configured restart method, detecting it for auto. It
+// fails when auto can't detect a method, so that the services aren't left
+// running the old binaries unnoticed.
+func (config RestartConfig) method() (string, error) {
+	if config.Method != restartAuto {
+		return config.Method, nil
+	}
+	if info, err := os.Stat(systemdRuntimeDir); err == nil && info.IsDir() {
+		return restartSystemd, nil
+	}
+	if config.PIDFile != "" {
+		return restartSignal, nil
+	}
+	return "", RestartError.New("unable to detect the restart method, the system wasn't booted with systemd and no PID file is configured, set the restart method")
+}
+
+// Validate checks that the services can be restarted with the configured
+// method.
+func (config RestartConfig) Validate(services ...string) error {
+	for _, service := range services {
+		if _, err := newRestarter(config, service); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restarter restarts a service after its binary was replaced.
+type restarter interface {
+	// Restart restarts the service and waits until it runs again.
+	Restart(ctx context.Context, service, binPath string) error
+	// Check returns an error when the restarted service doesn't run anymore.
+	Check(ctx context.Context, service string) error
+}
+
+// newRestarter creates the restarter of the service for the configured method.
+// The signal and the exec methods restart the updater by replacing its process
+// with the new binary, so that the supervisor or the container keeps tracking
+// the same PID. The storage node runs in a process the updater can't replace,
+// so both methods signal it to stop and wait until it's started again.
+func newRestarter(config RestartConfig, service string) (restarter, error) {
+	method, err := config.method()
+	if err != nil {
+		return nil, err
+	}
+
+	switch method {
+	case restartSystemd:
+		return &systemdRestarter{config: config}, nil
+	case restartSignal, restartExec:
+		if service == updaterServiceName {
+			return newExecRestarter(), nil
+		}
+		if config.PIDFile == "" {
+			return nil, RestartError.New("no PID file configured to restart %s with the %s method", service, method)
+		}
+		signal, err := parseSignal(config.Signal)
+		if err != nil {
+			return nil, err
+		}
+		return newSignalRestarter(config, signal), nil
+	case restartNone:
+		return noneRestarter{}, nil
+	default:
+		return nil, RestartError.New("invalid restart method %q, expected auto, systemd, signal, exec or none", method)
+	}
+}
+
+// systemdRestarter restarts the systemd unit of the service with systemctl,
+// which talks to systemd over D-Bus.
+type systemdRestarter struct {
+	config RestartConfig
+}
+
+// Restart implements restarter.
+func (restarter *systemdRestarter) Restart(ctx context.Context, service, binPath string) error {
+	unit := service + ".service"
+
+	out, err := exec.CommandContext(ctx, restarter.config.Systemctl, "restart", unit).CombinedOutput()
+	if err != nil {
+		return RestartError.New("systemctl restart %s: %v: %s", unit, err, strings.TrimSpace(string(out)))
+	}
+
+	return waitFor(ctx, restarter.config, func() bool {
+		return restarter.active(ctx, unit)
+	}, "unit %s isn't active", unit)
+}
+
+// Check implements restarter.
+func (restarter *systemdRestarter) Check(ctx context.Context, service string) error {
+	unit := service + ".service"
+	if !restarter.active(ctx, unit) {
+		return RestartError.New("unit %s isn't active", unit)
+	}
+	return nil
+}
+
+// active returns whether the unit is active.
+func (restarter *systemdRestarter) active(ctx context.Context, unit string) bool {
+	return exec.CommandContext(ctx, restarter.config.Systemctl, "is-active", "--quiet", unit).Run() == nil
+}
+
+// signalRestarter signals the process in the PID file to stop and waits until
+// its supervisor started a new one.
+type signalRestarter struct {
+	config RestartConfig
+	signal syscall.Signal
+
+	kill  func(pid int, signal syscall.Signal) error
+	alive func(pid int) bool
+}
+
+// newSignalRestarter creates a signal restarter sending the signal to the
+// processes.
+func newSignalRestarter(config RestartConfig, signal syscall.Signal) *signalRestarter {
+	return &signalRestarter{
+		config: config,
+		signal: signal,
+		kill:   killProcess,
+		alive:  processAlive,
+	}
+}
+
+// Restart implements restarter.
+func (restarter *signalRestarter) Restart(ctx context.Context, service, binPath string) error {
+	if restarter.config.PIDFile == "" {
+		return RestartError.New("no PID file configured for %s", service)
+	}
+
+	pid, err := readPID(restarter.config.PIDFile)
+	if err != nil {
+		return err
+	}
+
+	zap.S().Infof("Sending %v to %s with PID %d", restarter.signal, service, pid)
+	if err := restarter.kill(pid, restarter.signal); err != nil {
+		return RestartError.New("unable to signal PID %d: %v", pid, err)
+	}
+
+	return waitFor(ctx, restarter.config, func() bool {
+		newPID, err := readPID(restarter.config.PIDFile)
+		return err == nil && newPID != pid && restarter.alive(newPID)
+	}, "%s wasn't restarted by its supervisor", service)
+}
+
+// Check implements restarter.
+func (restarter *signalRestarter) Check(ctx context.Context, service string) error {
+	pid, err := readPID(restarter.config.PIDFile)
+	if err != nil {
+		return err
+	}
+	if !restarter.alive(pid) {
+		return RestartError.New("%s with PID %d doesn't run", service, pid)
+	}
+	return nil
+}
+
+// readPID reads the PID from the file.
+func readPID(path string) (int, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, RestartError.New("unable to read PID file: %v", err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return 0, RestartError.New("invalid PID file %q", path)
+	}
+	return pid, nil
+}
+
+// execRestarter replaces the updater process with its new binary, keeping the
+// arguments and the environment. It only restarts the updater itself.
+type execRestarter struct {
+	args []string
+	env  []string
+	exec func(path string, args []string, env []string) error
+}
+
+// newExecRestarter creates an exec restarter for the current process.
+func newExecRestarter() *execRestarter {
+	return &execRestarter{
+		args: os.Args,
+		env:  os.Environ(),
+		exec: execProcess,
+	}
+}
+
+// Restart implements restarter.
+func (restarter *execRestarter) Restart(ctx context.Context, service, binPath string) error {
+	if service != updaterServiceName {
+		return RestartError.New("exec method can't restart %s, only %s", service, updaterServiceName)
+	}
+
+	zap.S().Infof("Replacing %s process with %s", service, binPath)
+	// on success exec doesn't return
+	if err := restarter.exec(binPath, restarter.args, restarter.env); err != nil {
+		return RestartError.New("unable to exec %s: %v", binPath, err)
+	}
+	return nil
+}
+
+// Check implements restarter. The process is replaced on restart, so there's
+// nothing left to check.
+func (restarter *execRestarter) Check(ctx context.Context, service string) error {
+	return nil
+}
+
+// noneRestarter doesn't restart the services.
+type noneRestarter struct{}
+
+// Restart implements restarter.
+func (noneRestarter) Restart(ctx context.Context, service, binPath string) error {
+	zap.S().Infof("Not restarting %s, restart it to use the new binary", service)
+	return nil
+}
+
+// Check implements restarter.
+func (noneRestarter) Check(ctx context.Context, service string) error {
+	return nil
+}
+
+// waitFor polls the check until it succeeds or the restart timeout is over.
+func waitFor(ctx context.Context, config RestartConfig, check func() bool, format string, args ...interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, config.Timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if check() {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return RestartError.New(format, args...)
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,302 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// +build !windows
+
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/testcontext"
+)
+
+func testRestartConfig(method string) RestartConfig {
+	return RestartConfig{
+		Method:       method,
+		Signal:       "SIGTERM",
+		Timeout:      10 * time.Second,
+		PollInterval: 10 * time.Millisecond,
+	}
+}
+
+func TestNewRestarter(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	defaultSystemdRuntimeDir := systemdRuntimeDir
+	defer func() { systemdRuntimeDir = defaultSystemdRuntimeDir }()
+	systemdRuntimeDir = filepath.Join(ctx.Dir(), "missing")
+
+	for _, test := range []struct {
+		method   string
+		service  string
+		expected restarter
+	}{
+		{restartSystemd, "storagenode", &systemdRestarter{}},
+		{restartSignal, "storagenode", &signalRestarter{}},
+		{restartSignal, updaterServiceName, &execRestarter{}},
+		{restartExec, "storagenode", &signalRestarter{}},
+		{restartExec, updaterServiceName, &execRestarter{}},
+		{restartNone, "storagenode", noneRestarter{}},
+	} {
+		config := testRestartConfig(test.method)
+		config.PIDFile = "storagenode.pid"
+		restarter, err := newRestarter(config, test.service)
+		require.NoError(t, err, test.method)
+		require.IsType(t, test.expected, restarter, test.method)
+	}
+
+	_, err := newRestarter(testRestartConfig("supervisord"), "storagenode")
+	require.True(t, RestartError.Has(err))
+
+	config := testRestartConfig(restartSignal)
+	config.PIDFile = "storagenode.pid"
+	config.Signal = "SIGFOO"
+	_, err = newRestarter(config, "storagenode")
+	require.True(t, RestartError.Has(err))
+
+	// the storage node can't be signaled without a PID file
+	config = testRestartConfig(restartSignal)
+	require.True(t, RestartError.Has(config.Validate("storagenode", updaterServiceName)))
+
+	// the storage node runs in its own process, which is signaled with exec
+	config = testRestartConfig(restartExec)
+	require.True(t, RestartError.Has(config.Validate("storagenode", updaterServiceName)))
+	config.PIDFile = "storagenode.pid"
+	require.NoError(t, config.Validate("storagenode", updaterServiceName))
+
+	// auto doesn't use systemd when the system wasn't booted with it and
+	// fails when it can't detect a method
+	config = testRestartConfig(restartAuto)
+	_, err = config.method()
+	require.True(t, RestartError.Has(err))
+	require.True(t, RestartError.Has(config.Validate("storagenode", updaterServiceName)))
+
+	config.PIDFile = "storagenode.pid"
+	method, err := config.method()
+	require.NoError(t, err)
+	require.Equal(t, restartSignal, method)
+	require.NoError(t, config.Validate("storagenode", updaterServiceName))
+
+	systemdRuntimeDir = ctx.Dir("systemd")
+	method, err = config.method()
+	require.NoError(t, err)
+	require.Equal(t, restartSystemd, method)
+}
+
+func TestSystemdRestarter(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	// fake systemctl which records its arguments and marks the unit as
+	// active after restarting it
+	callsPath := ctx.File("calls")
+	activePath := ctx.File("active")
+	systemctl := ctx.File("systemctl")
+	script := `#!/bin/sh
+echo "$@" >> ` + callsPath + `
+case "$1" in
+	restart) [ -z "$FAIL_RESTART" ] && touch ` + activePath + ` ;;
+	is-active) [ -f ` + activePath + ` ] ;;
+esac
+`
+	require.NoError(t, ioutil.WriteFile(systemctl, []byte(script), 0755))
+
+	config := testRestartConfig(restartSystemd)
+	config.Systemctl = systemctl
+	restarter := &systemdRestarter{config: config}
+
+	require.NoError(t, restarter.Restart(ctx, "storagenode", "storagenode"))
+	require.NoError(t, restarter.Check(ctx, "storagenode"))
+
+	calls, err := ioutil.ReadFile(callsPath)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"restart storagenode.service",
+		"is-active --quiet storagenode.service",
+		"is-active --quiet storagenode.service",
+	}, strings.Split(strings.TrimSpace(string(calls)), "\n"))
+
+	// the unit stopped after the restart
+	require.NoError(t, os.Remove(activePath))
+	require.True(t, RestartError.Has(restarter.Check(ctx, "storagenode")))
+
+	// the unit doesn't become active
+	require.NoError(t, os.Setenv("FAIL_RESTART", "1"))
+	defer func() { require.NoError(t, os.Unsetenv("FAIL_RESTART")) }()
+
+	restarter.config.Timeout = 100 * time.Millisecond
+	err = restarter.Restart(ctx, "storagenode", "storagenode")
+	require.True(t, RestartError.Has(err))
+
+	// systemctl fails
+	restarter.config.Systemctl = ctx.File("missing-systemctl")
+	err = restarter.Restart(ctx, "storagenode", "storagenode")
+	require.True(t, RestartError.Has(err))
+}
+
+// fakeSupervisor restarts a process once when it exits and writes the PID of
+// the running process to a file.
+type fakeSupervisor struct {
+	pidFile string
+	restart bool
+
+	// pid is the PID of the first process
+	pid  int
+	done chan struct{}
+	cmd  *exec.Cmd
+	err  error
+}
+
+func startFakeSupervisor(t *testing.T, pidFile string, restart bool) *fakeSupervisor {
+	supervisor := &fakeSupervisor{
+		pidFile: pidFile,
+		restart: restart,
+		done:    make(chan struct{}),
+	}
+	require.NoError(t, supervisor.start())
+	supervisor.pid = supervisor.cmd.Process.Pid
+	go supervisor.supervise()
+	return supervisor
+}
+
+func (supervisor *fakeSupervisor) start() error {
+	cmd := exec.Command("sleep", "60")
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	supervisor.cmd = cmd
+	return ioutil.WriteFile(supervisor.pidFile, []byte(strconv.Itoa(cmd.Process.Pid)+"\n"), 0644)
+}
+
+func (supervisor *fakeSupervisor) supervise() {
+	defer close(supervisor.done)
+	_ = supervisor.cmd.Wait()
+	if supervisor.restart {
+		supervisor.err = supervisor.start()
+	}
+}
+
+func (supervisor *fakeSupervisor) stop(t *testing.T) {
+	if !supervisor.restart {
+		_ = supervisor.cmd.Process.Kill()
+	}
+	<-supervisor.done
+	require.NoError(t, supervisor.err)
+	if supervisor.restart {
+		_ = supervisor.cmd.Process.Kill()
+		_ = supervisor.cmd.Wait()
+	}
+}
+
+func TestSignalRestarter(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	pidFile := ctx.File("storagenode.pid")
+	supervisor := startFakeSupervisor(t, pidFile, true)
+	oldPID := supervisor.pid
+
+	config := testRestartConfig(restartSignal)
+	config.PIDFile = pidFile
+	restarter := newSignalRestarter(config, syscall.SIGTERM)
+	require.NoError(t, restarter.Restart(ctx, "storagenode", "storagenode"))
+	require.NoError(t, restarter.Check(ctx, "storagenode"))
+	supervisor.stop(t)
+
+	// the restarted process stopped
+	require.True(t, RestartError.Has(restarter.Check(ctx, "storagenode")))
+
+	newPID, err := readPID(pidFile)
+	require.NoError(t, err)
+	require.NotEqual(t, oldPID, newPID)
+	require.False(t, processAlive(oldPID))
+}
+
+func TestSignalRestarter_NotRestarted(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	// the supervisor doesn't restart the process
+	pidFile := ctx.File("storagenode.pid")
+	supervisor := startFakeSupervisor(t, pidFile, false)
+
+	config := testRestartConfig(restartSignal)
+	config.PIDFile = pidFile
+	config.Timeout = 200 * time.Millisecond
+	restarter := newSignalRestarter(config, syscall.SIGTERM)
+	err := restarter.Restart(ctx, "storagenode", "storagenode")
+	require.True(t, RestartError.Has(err))
+	supervisor.stop(t)
+
+	// no PID file
+	restarter.config.PIDFile = filepath.Join(ctx.Dir(), "missing.pid")
+	err = restarter.Restart(ctx, "storagenode", "storagenode")
+	require.True(t, RestartError.Has(err))
+}
+
+func TestExecRestarter(t *testing.T) {
+	ctx := context.Background()
+
+	var execPath string
+	var execArgs, execEnv []string
+	restarter := &execRestarter{
+		args: []string{"storagenode-updater", "run", "--check-interval", "1h"},
+		env:  []string{"HOME=/app"},
+		exec: func(path string, args []string, env []string) error {
+			execPath, execArgs, execEnv = path, args, env
+			return nil
+		},
+	}
+
+	require.NoError(t, restarter.Restart(ctx, updaterServiceName, "/app/storagenode-updater"))
+	require.Equal(t, "/app/storagenode-updater", execPath)
+	require.Equal(t, restarter.args, execArgs)
+	require.Equal(t, restarter.env, execEnv)
+
+	err := restarter.Restart(ctx, "storagenode", "/app/storagenode")
+	require.True(t, RestartError.Has(err))
+
+	restarter.exec = func(path string, args []string, env []string) error { return syscall.ENOEXEC }
+	err = restarter.Restart(ctx, updaterServiceName, "/app/storagenode-updater")
+	require.True(t, RestartError.Has(err))
+}
+
+func TestExecRestarter_Process(t *testing.T) {
+	if pidFile := os.Getenv("STORJ_TEST_EXEC_PID_FILE"); pidFile != "" {
+		// the process started below replaces itself with a shell which writes
+		// its PID
+		sh, err := exec.LookPath("sh")
+		require.NoError(t, err)
+		restarter := newExecRestarter()
+		restarter.args = []string{"sh", "-c", "echo $$ > " + pidFile}
+		require.NoError(t, restarter.Restart(context.Background(), updaterServiceName, sh))
+		t.Fatal("exec returned")
+	}
+
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	pidFile := ctx.File("updater.pid")
+	cmd := exec.Command(os.Args[0], "-test.run=^TestExecRestarter_Process$")
+	cmd.Env = append(os.Environ(), "STORJ_TEST_EXEC_PID_FILE="+pidFile)
+	require.NoError(t, cmd.Start())
+	require.NoError(t, cmd.Wait())
+
+	// the supervisor keeps tracking the same process
+	pid, err := readPID(pidFile)
+	require.NoError(t, err)
+	require.Equal(t, cmd.Process.Pid, pid)
+}
@@ -0,0 +1,51 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// +build !windows
+
+package main
+
+import (
+	"strings"
+	"syscall"
+)
+
+// signals are the signals which can stop a service.
+var signals = map[string]syscall.Signal{
+	"SIGTERM": syscall.SIGTERM,
+	"SIGINT":  syscall.SIGINT,
+	"SIGHUP":  syscall.SIGHUP,
+	"SIGQUIT": syscall.SIGQUIT,
+	"SIGKILL": syscall.SIGKILL,
+	"SIGUSR1": syscall.SIGUSR1,
+	"SIGUSR2": syscall.SIGUSR2,
+}
+
+// parseSignal parses a signal name, e.g. SIGTERM or term.
+func parseSignal(name string) (syscall.Signal, error) {
+	name = strings.ToUpper(name)
+	if !strings.HasPrefix(name, "SIG") {
+		name = "SIG" + name
+	}
+	signal, ok := signals[name]
+	if !ok {
+		return 0, RestartError.New("invalid signal %q", name)
+	}
+	return signal, nil
+}
+
+// killProcess sends the signal to the process.
+func killProcess(pid int, signal syscall.Signal) error {
+	return syscall.Kill(pid, signal)
+}
+
+// processAlive returns whether the process exists.
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
+
+// execProcess replaces the current process.
+func execProcess(path string, args []string, env []string) error {
+	return syscall.Exec(path, args, env)
+}
@@ -0,0 +1,26 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"syscall"
+)
+
+// parseSignal is not supported on Windows.
+func parseSignal(name string) (syscall.Signal, error) {
+	return 0, RestartError.New("signals are not supported on windows")
+}
+
+// killProcess is not supported on Windows.
+func killProcess(pid int, signal syscall.Signal) error {
+	return RestartError.New("signals are not supported on windows")
+}
+
+// processAlive is not supported on Windows.
+func processAlive(pid int) bool { return false }
+
+// execProcess is not supported on Windows.
+func execProcess(path string, args []string, env []string) error {
+	return RestartError.New("exec is not supported on windows")
+}
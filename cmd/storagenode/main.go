@@ -114,6 +114,9 @@ func init() {
 	rootCmd.AddCommand(dashboardCmd)
 	rootCmd.AddCommand(gracefulExitInitCmd)
 	rootCmd.AddCommand(gracefulExitStatusCmd)
+	rootCmd.AddCommand(ordersCmd)
+	ordersCmd.AddCommand(ordersArchiveCmd)
+	ordersCmd.AddCommand(ordersTotalsCmd)
 	process.Bind(runCmd, &runCfg, defaults, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
 	process.Bind(setupCmd, &setupCfg, defaults, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir), cfgstruct.SetupMode())
 	process.Bind(configCmd, &setupCfg, defaults, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir), cfgstruct.SetupMode())
@@ -121,6 +124,8 @@ func init() {
 	process.Bind(dashboardCmd, &dashboardCfg, defaults, cfgstruct.ConfDir(defaultDiagDir))
 	process.Bind(gracefulExitInitCmd, &diagCfg, defaults, cfgstruct.ConfDir(defaultDiagDir))
 	process.Bind(gracefulExitStatusCmd, &diagCfg, defaults, cfgstruct.ConfDir(defaultDiagDir))
+	process.Bind(ordersArchiveCmd, &diagCfg, defaults, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
+	process.Bind(ordersTotalsCmd, &diagCfg, defaults, cfgstruct.ConfDir(confDir), cfgstruct.IdentityDir(identityDir))
 }
 
 func databaseConfig(config storagenode.Config) storagenodedb.Config {
@@ -129,9 +134,22 @@ func databaseConfig(config storagenode.Config) storagenodedb.Config {
 		Info:    filepath.Join(config.Storage.Path, "piecestore.db"),
 		Info2:   filepath.Join(config.Storage.Path, "info.db"),
 		Pieces:  config.Storage.Path,
+
+		PiecesAllocated:  config.Storage.AllocatedDiskSpace.Int64(),
+		AdditionalPieces: config.Storage.AdditionalDisks,
+		Packed:           config.Storage.Packed,
 	}
 }
 
+// diagDatabaseConfig returns the database configuration for commands which only
+// use the databases, so that they can run next to a running node.
+func diagDatabaseConfig(config storagenode.Config) storagenodedb.Config {
+	dbConfig := databaseConfig(config)
+	dbConfig.AdditionalPieces = nil
+	dbConfig.SkipPacked = true
+	return dbConfig
+}
+
 func cmdRun(cmd *cobra.Command, args []string) (err error) {
 	// inert constructors only ====
 
@@ -282,7 +300,7 @@ func cmdDiag(cmd *cobra.Command, args []string) (err error) {
 		return err
 	}
 
-	db, err := storagenodedb.New(zap.L().Named("db"), databaseConfig(diagCfg))
+	db, err := storagenodedb.New(zap.L().Named("db"), diagDatabaseConfig(diagCfg))
 	if err != nil {
 		return errs.New("Error starting master database on storage node: %v", err)
 	}
@@ -0,0 +1,216 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/common/memory"
+	"storj.io/common/storj"
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/storagenode/orders"
+	"storj.io/storj/storagenode/storagenodedb"
+)
+
+var (
+	ordersCmd = &cobra.Command{
+		Use:         "orders",
+		Short:       "Inspect the archive of orders sent to the satellites",
+		Annotations: map[string]string{"type": "helper"},
+	}
+	ordersArchiveCmd = &cobra.Command{
+		Use:         "archive",
+		Short:       "List or export archived orders",
+		RunE:        cmdOrdersArchive,
+		Annotations: map[string]string{"type": "helper"},
+	}
+	ordersTotalsCmd = &cobra.Command{
+		Use:         "totals",
+		Short:       "Display the accepted and rejected orders per satellite",
+		RunE:        cmdOrdersTotals,
+		Annotations: map[string]string{"type": "helper"},
+	}
+
+	ordersArchiveFlags struct {
+		Satellite string
+		Status    string
+		Action    string
+		After     string
+		Before    string
+		Limit     int
+		Format    string
+	}
+)
+
+func init() {
+	flags := ordersArchiveCmd.Flags()
+	flags.StringVar(&ordersArchiveFlags.Satellite, "satellite", "", "only list orders of the satellite")
+	flags.StringVar(&ordersArchiveFlags.Status, "status", "", "only list orders with the status: accepted or rejected")
+	flags.StringVar(&ordersArchiveFlags.Action, "action", "", "only list orders with the action, e.g. GET or PUT_REPAIR")
+	flags.StringVar(&ordersArchiveFlags.After, "after", "", "only list orders archived at or after the RFC 3339 time")
+	flags.StringVar(&ordersArchiveFlags.Before, "before", "", "only list orders archived before the RFC 3339 time")
+	flags.IntVar(&ordersArchiveFlags.Limit, "limit", 0, "maximum number of orders to list, 0 for all")
+	flags.StringVar(&ordersArchiveFlags.Format, "format", "table", "output format: table, csv or json")
+}
+
+// openOrdersDB opens the orders database of the storage node and migrates it
+// to the latest version.
+func openOrdersDB(ctx context.Context) (_ *storagenodedb.DB, err error) {
+	diagDir, err := filepath.Abs(confDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// check if the directory exists
+	_, err = os.Stat(diagDir)
+	if err != nil {
+		fmt.Println("storage node directory doesn't exist", diagDir)
+		return nil, err
+	}
+
+	db, err := storagenodedb.New(zap.L().Named("db"), diagDatabaseConfig(diagCfg))
+	if err != nil {
+		return nil, errs.New("Error starting master database on storage node: %v", err)
+	}
+
+	err = db.CreateTables(ctx)
+	if err != nil {
+		return nil, errs.Combine(errs.New("Error creating tables for master database on storagenode: %v", err), db.Close())
+	}
+	return db, nil
+}
+
+// archiveFilter creates the archive filter from the command flags.
+func archiveFilter() (filter orders.ArchiveFilter, err error) {
+	if ordersArchiveFlags.Satellite != "" {
+		satelliteID, err := storj.NodeIDFromString(ordersArchiveFlags.Satellite)
+		if err != nil {
+			return filter, errs.New("invalid satellite %q: %v", ordersArchiveFlags.Satellite, err)
+		}
+		filter.SatelliteID = &satelliteID
+	}
+	if ordersArchiveFlags.Status != "" {
+		status, err := orders.ParseStatus(ordersArchiveFlags.Status)
+		if err != nil {
+			return filter, err
+		}
+		filter.Status = &status
+	}
+	if ordersArchiveFlags.Action != "" {
+		action, err := orders.ParseAction(ordersArchiveFlags.Action)
+		if err != nil {
+			return filter, err
+		}
+		filter.Action = &action
+	}
+	if ordersArchiveFlags.After != "" {
+		filter.ArchivedAfter, err = time.Parse(time.RFC3339, ordersArchiveFlags.After)
+		if err != nil {
+			return filter, errs.New("invalid time %q: %v", ordersArchiveFlags.After, err)
+		}
+	}
+	if ordersArchiveFlags.Before != "" {
+		filter.ArchivedBefore, err = time.Parse(time.RFC3339, ordersArchiveFlags.Before)
+		if err != nil {
+			return filter, errs.New("invalid time %q: %v", ordersArchiveFlags.Before, err)
+		}
+	}
+	filter.Limit = ordersArchiveFlags.Limit
+	return filter, nil
+}
+
+func cmdOrdersArchive(cmd *cobra.Command, args []string) (err error) {
+	ctx, _ := process.Ctx(cmd)
+
+	filter, err := archiveFilter()
+	if err != nil {
+		return err
+	}
+
+	db, err := openOrdersDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errs.Combine(err, db.Close())
+	}()
+
+	archived, err := db.Orders().QueryArchived(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	switch ordersArchiveFlags.Format {
+	case "csv":
+		return orders.ExportArchiveCSV(os.Stdout, archived)
+	case "json":
+		return orders.ExportArchiveJSON(os.Stdout, archived)
+	case "table":
+	default:
+		return errs.New("invalid format %q, expected table, csv or json", ordersArchiveFlags.Format)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.AlignRight|tabwriter.Debug)
+	defer func() { err = errs.Combine(err, w.Flush()) }()
+
+	fmt.Fprint(w, "Satellite\tSerial Number\tAction\tAmount\tStatus\tReason\tArchived At\n")
+
+	for _, info := range archived {
+		order := info.Export()
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t%v\n",
+			order.SatelliteID,
+			order.SerialNumber,
+			order.Action,
+			memory.Size(order.Amount),
+			order.Status,
+			order.Reason,
+			order.ArchivedAt.Format(time.RFC3339),
+		)
+	}
+
+	return nil
+}
+
+func cmdOrdersTotals(cmd *cobra.Command, args []string) (err error) {
+	ctx, _ := process.Ctx(cmd)
+
+	db, err := openOrdersDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errs.Combine(err, db.Close())
+	}()
+
+	totals, err := db.Orders().ArchiveTotals(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.AlignRight|tabwriter.Debug)
+	defer func() { err = errs.Combine(err, w.Flush()) }()
+
+	fmt.Fprint(w, "Satellite\tAccepted Orders\tAccepted\tRejected Orders\tRejected\n")
+
+	for _, satellite := range totals {
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\n",
+			satellite.SatelliteID,
+			satellite.AcceptedOrders,
+			memory.Size(satellite.AcceptedBytes),
+			satellite.RejectedOrders,
+			memory.Size(satellite.RejectedBytes),
+		)
+	}
+
+	return nil
+}
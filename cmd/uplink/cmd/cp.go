@@ -23,9 +23,11 @@ import (
 )
 
 var (
-	progress *bool
-	expires  *string
-	metadata *string
+	progress    *bool
+	expires     *string
+	metadata    *string
+	recursive   *bool
+	parallelism *int
 )
 
 func init() {
@@ -38,8 +40,10 @@ func init() {
 	progress = cpCmd.Flags().Bool("progress", true, "if true, show progress")
 	expires = cpCmd.Flags().String("expires", "", "optional expiration date of an object. Please use format (yyyy-mm-ddThh:mm:ssZhh:mm)")
 	metadata = cpCmd.Flags().String("metadata", "", "optional metadata for the object. Please use a single level JSON object of string to string only")
+	recursive = cpCmd.Flags().BoolP("recursive", "r", false, "if true, copy a local directory or all objects under a prefix recursively")
+	parallelism = cpCmd.Flags().Int("parallelism", 1, "number of files or objects copied in parallel when copying recursively")
 
-	setBasicFlags(cpCmd.Flags(), "progress", "expires", "metadata")
+	setBasicFlags(cpCmd.Flags(), "progress", "expires", "metadata", "recursive", "parallelism")
 }
 
 // upload transfers src from local machine to s3 compatible object dst
@@ -52,15 +56,9 @@ func upload(ctx context.Context, src fpath.FPath, dst fpath.FPath, showProgress
 		return fmt.Errorf("destination must be Storj URL: %s", dst)
 	}
 
-	var expiration time.Time
-	if *expires != "" {
-		expiration, err = time.Parse(time.RFC3339, *expires)
-		if err != nil {
-			return err
-		}
-		if expiration.Before(time.Now()) {
-			return fmt.Errorf("invalid expiration date: (%s) has already passed", *expires)
-		}
+	opts, err := uploadOptions()
+	if err != nil {
+		return err
 	}
 
 	// if object name not specified, default to filename
@@ -102,9 +100,31 @@ func upload(ctx context.Context, src fpath.FPath, dst fpath.FPath, showProgress
 		bar.Start()
 	}
 
+	err = bucket.UploadObject(ctx, dst.Path(), reader, opts)
+	if bar != nil {
+		bar.Finish()
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Created %s\n", dst.String())
+
+	return nil
+}
+
+// uploadOptions returns the upload options set by the cp flags.
+func uploadOptions() (*libuplink.UploadOptions, error) {
 	opts := &libuplink.UploadOptions{}
 
 	if *expires != "" {
+		expiration, err := time.Parse(time.RFC3339, *expires)
+		if err != nil {
+			return nil, err
+		}
+		if expiration.Before(time.Now()) {
+			return nil, fmt.Errorf("invalid expiration date: (%s) has already passed", *expires)
+		}
 		opts.Expires = expiration.UTC()
 	}
 
@@ -113,7 +133,7 @@ func upload(ctx context.Context, src fpath.FPath, dst fpath.FPath, showProgress
 
 		err := json.Unmarshal([]byte(*metadata), &md)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		opts.Metadata = md
@@ -122,17 +142,7 @@ func upload(ctx context.Context, src fpath.FPath, dst fpath.FPath, showProgress
 	opts.Volatile.RedundancyScheme = cfg.GetRedundancyScheme()
 	opts.Volatile.EncryptionParameters = cfg.GetEncryptionParameters()
 
-	err = bucket.UploadObject(ctx, dst.Path(), reader, opts)
-	if bar != nil {
-		bar.Finish()
-	}
-	if err != nil {
-		return err
-	}
-
-	fmt.Printf("Created %s\n", dst.String())
-
-	return nil
+	return opts, nil
 }
 
 // download transfers s3 compatible object src to dst on local machine
@@ -237,7 +247,7 @@ func copyObject(ctx context.Context, src fpath.FPath, dst fpath.FPath) (err erro
 	var reader io.Reader
 	if *progress {
 		bar = progressbar.New64(object.Meta.Size)
-		reader = bar.NewProxyReader(reader)
+		reader = bar.NewProxyReader(rc)
 		bar.Start()
 	} else {
 		reader = rc
@@ -295,6 +305,10 @@ func copyMain(cmd *cobra.Command, args []string) (err error) {
 		return errors.New("at least one of the source or the destination must be a Storj URL")
 	}
 
+	if *recursive {
+		return copyRecursive(ctx, src, dst)
+	}
+
 	// if uploading
 	if src.IsLocal() {
 		return upload(ctx, src, dst, *progress)
@@ -0,0 +1,396 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	progressbar "github.com/cheggaaa/pb/v3"
+	"github.com/zeebo/errs"
+
+	"storj.io/common/fpath"
+	"storj.io/common/memory"
+	"storj.io/common/storj"
+	libuplink "storj.io/storj/lib/uplink"
+)
+
+// transfer is a single file or object copied by a recursive copy.
+type transfer struct {
+	// path is relative to the source directory or prefix.
+	path string
+	src  fpath.FPath
+	dst  fpath.FPath
+	size int64
+}
+
+// transferFunc copies a single file or object. The data has to be read
+// through progress, so that it's counted by the progress bar.
+type transferFunc func(ctx context.Context, t transfer, progress *transferProgress) error
+
+// transferProgress counts the copied bytes for the progress bar.
+type transferProgress struct {
+	bar *progressbar.ProgressBar
+}
+
+// Wrap returns a reader, which counts the bytes read from reader.
+func (progress *transferProgress) Wrap(reader io.Reader) io.Reader {
+	if progress.bar == nil {
+		return reader
+	}
+	return progress.bar.NewProxyReader(reader)
+}
+
+// Add counts bytes, which were copied without reading them.
+func (progress *transferProgress) Add(n int64) {
+	if progress.bar != nil {
+		progress.bar.Add64(n)
+	}
+}
+
+// copyRecursive copies a local directory or all objects under a prefix.
+func copyRecursive(ctx context.Context, src fpath.FPath, dst fpath.FPath) (err error) {
+	if *parallelism < 1 {
+		return fmt.Errorf("parallelism must be at least 1: %d", *parallelism)
+	}
+
+	if src.IsLocal() {
+		return uploadRecursive(ctx, src, dst)
+	}
+	if dst.IsLocal() {
+		return downloadRecursive(ctx, src, dst)
+	}
+	return copyObjectsRecursive(ctx, src, dst)
+}
+
+// uploadRecursive uploads all files in the local directory src under the
+// prefix dst.
+func uploadRecursive(ctx context.Context, src fpath.FPath, dst fpath.FPath) (err error) {
+	fileInfo, err := os.Stat(src.Path())
+	if err != nil {
+		return err
+	}
+	if !fileInfo.IsDir() {
+		return upload(ctx, src, dst, *progress)
+	}
+
+	// like for a single file, a directory is copied into the destination
+	// prefix when the destination ends with a slash
+	if strings.HasSuffix(dst.String(), "/") || dst.Path() == "" {
+		dst = joinBase(dst, src)
+	}
+
+	opts, err := uploadOptions()
+	if err != nil {
+		return err
+	}
+
+	var transfers []transfer
+	err = walkFiles(src.Path(), func(rel string, info os.FileInfo) error {
+		transfers = append(transfers, transfer{
+			path: rel,
+			src:  src.Join(filepath.FromSlash(rel)),
+			dst:  dst.Join(rel),
+			size: info.Size(),
+		})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	project, bucket, err := cfg.GetProjectAndBucket(ctx, dst.Bucket())
+	if err != nil {
+		return err
+	}
+	defer closeProjectAndBucket(project, bucket)
+
+	return runTransfers(ctx, transfers, *parallelism, *progress, func(ctx context.Context, t transfer, progress *transferProgress) (err error) {
+		file, err := os.Open(t.src.Path())
+		if err != nil {
+			return err
+		}
+		defer func() { err = errs.Combine(err, file.Close()) }()
+
+		// every upload needs its own options, because uploading fills in
+		// the defaults of the bucket
+		uploadOpts := *opts
+		return bucket.UploadObject(ctx, t.dst.Path(), progress.Wrap(file), &uploadOpts)
+	})
+}
+
+// downloadRecursive downloads all objects under the prefix src into the local
+// directory dst.
+func downloadRecursive(ctx context.Context, src fpath.FPath, dst fpath.FPath) (err error) {
+	project, bucket, err := cfg.GetProjectAndBucket(ctx, src.Bucket())
+	if err != nil {
+		return convertError(err, src)
+	}
+	defer closeProjectAndBucket(project, bucket)
+
+	objects, err := listRecursive(ctx, bucket, src)
+	if err != nil {
+		return convertError(err, src)
+	}
+	if len(objects) == 0 {
+		// the source may be a single object rather than a prefix
+		return download(ctx, src, dst, *progress)
+	}
+
+	// like for a single object, a prefix is copied into the destination
+	// directory when it already exists
+	if fileInfo, err := os.Stat(dst.Path()); err == nil && fileInfo.IsDir() {
+		dst = joinBase(dst, src)
+	}
+
+	transfers := make([]transfer, 0, len(objects))
+	for _, object := range objects {
+		transfers = append(transfers, transfer{
+			path: object.Path,
+			src:  src.Join(object.Path),
+			dst:  dst.Join(object.Path),
+			size: object.Size,
+		})
+	}
+
+	return runTransfers(ctx, transfers, *parallelism, *progress, func(ctx context.Context, t transfer, progress *transferProgress) (err error) {
+		target, err := localTarget(dst, t.path)
+		if err != nil {
+			return err
+		}
+
+		err = os.MkdirAll(filepath.Dir(target.Path()), 0755)
+		if err != nil {
+			return err
+		}
+
+		rc, err := bucket.Download(ctx, t.src.Path())
+		if err != nil {
+			return convertError(err, t.src)
+		}
+		defer func() { err = errs.Combine(err, rc.Close()) }()
+
+		file, err := os.Create(target.Path())
+		if err != nil {
+			return err
+		}
+		defer func() { err = errs.Combine(err, file.Close()) }()
+
+		_, err = io.Copy(file, progress.Wrap(rc))
+		return err
+	})
+}
+
+// copyObjectsRecursive copies all objects under the prefix src under the
+// prefix dst. New objects are copied by the satellite without transferring data.
+func copyObjectsRecursive(ctx context.Context, src fpath.FPath, dst fpath.FPath) (err error) {
+	project, srcBucket, err := cfg.GetProjectAndBucket(ctx, src.Bucket())
+	if err != nil {
+		return convertError(err, src)
+	}
+	defer closeProjectAndBucket(project, srcBucket)
+
+	objects, err := listRecursive(ctx, srcBucket, src)
+	if err != nil {
+		return convertError(err, src)
+	}
+	if len(objects) == 0 {
+		return copyObject(ctx, src, dst)
+	}
+
+	if strings.HasSuffix(dst.Path(), "/") || dst.Path() == "" {
+		dst = joinBase(dst, src)
+	}
+
+	dstProject, dstBucket, err := cfg.GetProjectAndBucket(ctx, dst.Bucket())
+	if err != nil {
+		return convertError(err, dst)
+	}
+	defer closeProjectAndBucket(dstProject, dstBucket)
+
+	transfers := make([]transfer, 0, len(objects))
+	for _, object := range objects {
+		transfers = append(transfers, transfer{
+			path: object.Path,
+			src:  src.Join(object.Path),
+			dst:  dst.Join(object.Path),
+			size: object.Size,
+		})
+	}
+
+	return runTransfers(ctx, transfers, *parallelism, *progress, func(ctx context.Context, t transfer, progress *transferProgress) (err error) {
+		// the objects are in the same project, so the satellite can copy
+		// them without transferring any data
+		err = srcBucket.CopyObject(ctx, t.src.Path(), dstBucket, t.dst.Path())
+		if err == nil {
+			progress.Add(t.size)
+			return nil
+		}
+		if !libuplink.ErrObjectExists.Has(err) {
+			return convertError(err, t.src)
+		}
+
+		// the server-side copy doesn't overwrite objects, so existing
+		// objects are replaced by downloading and uploading the data
+		object, err := srcBucket.OpenObject(ctx, t.src.Path())
+		if err != nil {
+			return convertError(err, t.src)
+		}
+
+		rc, err := object.DownloadRange(ctx, 0, object.Meta.Size)
+		if err != nil {
+			return err
+		}
+		defer func() { err = errs.Combine(err, rc.Close()) }()
+
+		opts := &libuplink.UploadOptions{
+			Expires:     object.Meta.Expires,
+			ContentType: object.Meta.ContentType,
+			Metadata:    object.Meta.Metadata,
+		}
+		opts.Volatile.RedundancyScheme = cfg.GetRedundancyScheme()
+		opts.Volatile.EncryptionParameters = cfg.GetEncryptionParameters()
+
+		return dstBucket.UploadObject(ctx, t.dst.Path(), progress.Wrap(rc), opts)
+	})
+}
+
+// runTransfers runs the transfers with the given parallelism. A failed
+// transfer doesn't stop the others, the failures are reported at the end.
+func runTransfers(ctx context.Context, transfers []transfer, parallelism int, showProgress bool, fn transferFunc) error {
+	var total int64
+	for _, t := range transfers {
+		total += t.size
+	}
+
+	var bar *progressbar.ProgressBar
+	if showProgress {
+		bar = progressbar.New64(total)
+		bar.Start()
+	}
+	progress := &transferProgress{bar: bar}
+
+	type failure struct {
+		transfer transfer
+		err      error
+	}
+
+	var mu sync.Mutex
+	var failures []failure
+
+	queue := make(chan transfer)
+	var wg sync.WaitGroup
+	for i := 0; i < parallelism && i < len(transfers); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range queue {
+				err := fn(ctx, t, progress)
+				if err != nil {
+					mu.Lock()
+					failures = append(failures, failure{transfer: t, err: err})
+					mu.Unlock()
+					continue
+				}
+				if bar == nil {
+					fmt.Printf("%s copied to %s\n", t.src.String(), t.dst.String())
+				}
+			}
+		}()
+	}
+
+	for _, t := range transfers {
+		queue <- t
+	}
+	close(queue)
+	wg.Wait()
+
+	if bar != nil {
+		bar.Finish()
+	}
+
+	for _, failed := range failures {
+		fmt.Fprintf(os.Stderr, "failed to copy %s to %s: %v\n", failed.transfer.src.String(), failed.transfer.dst.String(), failed.err)
+	}
+
+	fmt.Printf("Copied %d of %d files (%s)\n", len(transfers)-len(failures), len(transfers), memory.Size(total).Base10String())
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%d of %d files failed to copy", len(failures), len(transfers))
+	}
+	return nil
+}
+
+// walkFiles calls fn for every regular file under the local directory root
+// with the slash separated path relative to root.
+func walkFiles(root string, fn func(rel string, info os.FileInfo) error) error {
+	return filepath.Walk(root, func(name string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, name)
+		if err != nil {
+			return err
+		}
+		return fn(filepath.ToSlash(rel), info)
+	})
+}
+
+// listRecursive lists all objects under the prefix.
+func listRecursive(ctx context.Context, bucket *libuplink.Bucket, prefix fpath.FPath) (objects []storj.Object, err error) {
+	opts := &storj.ListOptions{
+		Direction: storj.After,
+		Prefix:    prefix.Path(),
+		Recursive: true,
+	}
+	if opts.Prefix != "" && !strings.HasSuffix(opts.Prefix, "/") {
+		opts.Prefix += "/"
+	}
+
+	for {
+		list, err := bucket.ListObjects(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		objects = append(objects, list.Items...)
+
+		if !list.More || len(list.Items) == 0 {
+			return objects, nil
+		}
+		opts.Cursor = list.Items[len(list.Items)-1].Path
+	}
+}
+
+// localTarget returns the local path for the object path relative to the
+// directory dir. Object paths which would end up outside of dir are refused.
+func localTarget(dir fpath.FPath, objectPath string) (fpath.FPath, error) {
+	rel := filepath.FromSlash(path.Clean("/" + objectPath))[1:]
+	if rel == "" || rel != filepath.FromSlash(objectPath) {
+		return fpath.FPath{}, fmt.Errorf("object path can't be copied to a local file: %s", objectPath)
+	}
+	return dir.Join(rel), nil
+}
+
+// joinBase joins the last segment of src to dst, unless src doesn't have one.
+func joinBase(dst fpath.FPath, src fpath.FPath) fpath.FPath {
+	base := src.Base()
+	if !src.IsLocal() && base == "" {
+		base = src.Bucket()
+	}
+	if base == "" || base == "." || base == string(filepath.Separator) {
+		return dst
+	}
+	return dst.Join(base)
+}
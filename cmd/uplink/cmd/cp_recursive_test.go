@@ -0,0 +1,93 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/memory"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/private/testplanet"
+)
+
+func TestCopyRecursive(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 4,
+		UplinkCount:      1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		uplinkExe := ctx.Compile("storj.io/storj/cmd/uplink")
+
+		uplink := func(args ...string) ([]byte, error) {
+			output, err := exec.Command(uplinkExe, append([]string{"--config-dir", ctx.Dir("uplink")}, args...)...).CombinedOutput()
+			t.Log(string(output))
+			return output, err
+		}
+
+		_, err := uplink("import", planet.Uplinks[0].GetConfig(planet.Satellites[0]).Access)
+		require.NoError(t, err)
+
+		bucketName := testrand.BucketName()
+		_, err = uplink("mb", "sj://"+bucketName)
+		require.NoError(t, err)
+
+		files := map[string][]byte{
+			"a.txt":         testrand.Bytes(memory.KiB),
+			"sub/b.txt":     testrand.Bytes(10 * memory.KiB),
+			"sub/deep/c.go": testrand.Bytes(5 * memory.KiB),
+			"sub/deep/d":    {},
+		}
+
+		source := ctx.Dir("source", "dataset")
+		for name, data := range files {
+			path := filepath.Join(source, filepath.FromSlash(name))
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+			require.NoError(t, ioutil.WriteFile(path, data, 0644))
+		}
+
+		// the directory is copied into the prefix, because the destination ends with a slash
+		_, err = uplink("cp", "--recursive", "--parallelism", "3", "--progress=false", source, "sj://"+bucketName+"/")
+		require.NoError(t, err)
+
+		for name, data := range files {
+			downloaded, err := planet.Uplinks[0].Download(ctx, planet.Satellites[0], bucketName, "dataset/"+name)
+			require.NoError(t, err)
+			require.Equal(t, data, downloaded)
+		}
+
+		_, err = uplink("cp", "-r", "--parallelism", "2", "sj://"+bucketName+"/dataset", "sj://"+bucketName+"/copy")
+		require.NoError(t, err)
+
+		// the destination doesn't exist, so it receives the contents of the prefix
+		target := filepath.Join(ctx.Dir("target"), "copy")
+		_, err = uplink("cp", "-r", "--parallelism", "4", "sj://"+bucketName+"/copy", target)
+		require.NoError(t, err)
+
+		for name, data := range files {
+			downloaded, err := ioutil.ReadFile(filepath.Join(target, filepath.FromSlash(name)))
+			require.NoError(t, err)
+			require.Equal(t, data, downloaded)
+		}
+
+		// a single failure doesn't stop the other transfers, but fails the command
+		failing := ctx.Dir("failing")
+		require.NoError(t, os.Mkdir(filepath.Join(failing, "dataset"), 0755))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(failing, "dataset", "sub"), nil, 0644))
+
+		output, err := uplink("cp", "-r", "--progress=false", "sj://"+bucketName+"/dataset", failing)
+		require.Error(t, err)
+		require.Contains(t, string(output), "3 of 4 files failed to copy")
+
+		downloaded, err := ioutil.ReadFile(filepath.Join(failing, "dataset", "a.txt"))
+		require.NoError(t, err)
+		require.Equal(t, files["a.txt"], downloaded)
+	})
+}
@@ -0,0 +1,94 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"storj.io/common/fpath"
+	libuplink "storj.io/storj/lib/uplink"
+	"storj.io/storj/pkg/process"
+)
+
+func init() {
+	addCmd(&cobra.Command{
+		Use:   "mv SOURCE DESTINATION",
+		Short: "Moves a Storj object to another location in Storj",
+		RunE:  moveObject,
+	}, RootCmd)
+}
+
+// moveObject moves the object without transferring its data.
+func moveObject(cmd *cobra.Command, args []string) (err error) {
+	ctx, _ := process.Ctx(cmd)
+
+	if len(args) == 0 {
+		return fmt.Errorf("no object specified for move")
+	}
+	if len(args) == 1 {
+		return fmt.Errorf("no destination specified")
+	}
+
+	src, err := fpath.New(args[0])
+	if err != nil {
+		return err
+	}
+
+	dst, err := fpath.New(args[1])
+	if err != nil {
+		return err
+	}
+
+	if src.IsLocal() {
+		return fmt.Errorf("source must be Storj URL: %s", src)
+	}
+
+	if dst.IsLocal() {
+		return fmt.Errorf("destination must be Storj URL: %s", dst)
+	}
+
+	// if destination object name not specified, default to source object name
+	if dst.Path() == "" || strings.HasSuffix(dst.Path(), "/") {
+		dst = dst.Join(src.Base())
+	}
+
+	project, bucket, err := cfg.GetProjectAndBucket(ctx, src.Bucket())
+	if err != nil {
+		return convertError(err, src)
+	}
+	defer closeProjectAndBucket(project, bucket)
+
+	dstBucket := bucket
+	if dst.Bucket() != src.Bucket() {
+		access, err := cfg.GetAccess()
+		if err != nil {
+			return err
+		}
+
+		dstBucket, err = project.OpenBucket(ctx, dst.Bucket(), access.EncryptionAccess)
+		if err != nil {
+			return convertError(err, dst)
+		}
+		defer func() {
+			if err := dstBucket.Close(); err != nil {
+				fmt.Printf("error closing bucket: %+v\n", err)
+			}
+		}()
+	}
+
+	err = bucket.MoveObject(ctx, src.Path(), dstBucket, dst.Path())
+	if err != nil {
+		if libuplink.ErrObjectExists.Has(err) {
+			return fmt.Errorf("object already exists: %s", dst)
+		}
+		return convertError(err, src)
+	}
+
+	fmt.Printf("%s moved to %s\n", src, dst)
+
+	return nil
+}
@@ -0,0 +1,313 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zeebo/errs"
+
+	"storj.io/common/fpath"
+	"storj.io/common/storj"
+	libuplink "storj.io/storj/lib/uplink"
+	"storj.io/storj/pkg/process"
+)
+
+const (
+	// syncMtimeKey is the metadata key with the modification time of the
+	// uploaded file.
+	syncMtimeKey = "uplink-sync-mtime"
+	// syncSHA256Key is the metadata key with the hex encoded SHA-256 hash of
+	// the uploaded file.
+	syncSHA256Key = "uplink-sync-sha256"
+)
+
+var (
+	syncDelete      *bool
+	syncDryRun      *bool
+	syncChecksum    *bool
+	syncInclude     *[]string
+	syncExclude     *[]string
+	syncProgress    *bool
+	syncParallelism *int
+)
+
+func init() {
+	syncCmd := addCmd(&cobra.Command{
+		Use:   "sync SOURCE sj://BUCKET[/PREFIX]",
+		Short: "Uploads the new and changed files of a local directory",
+		RunE:  syncMain,
+	}, RootCmd)
+
+	syncDelete = syncCmd.Flags().Bool("delete", false, "if true, delete objects which don't exist in the source")
+	syncDryRun = syncCmd.Flags().Bool("dry-run", false, "if true, only show what would be uploaded and deleted")
+	syncChecksum = syncCmd.Flags().Bool("checksum", false, "if true, compare files by their SHA-256 hash instead of their modification time")
+	syncInclude = syncCmd.Flags().StringArray("include", nil, "only sync files matching the glob pattern, can be repeated")
+	syncExclude = syncCmd.Flags().StringArray("exclude", nil, "don't sync files matching the glob pattern, can be repeated")
+	syncProgress = syncCmd.Flags().Bool("progress", true, "if true, show progress")
+	syncParallelism = syncCmd.Flags().Int("parallelism", 1, "number of files uploaded in parallel")
+
+	setBasicFlags(syncCmd.Flags(), "delete", "dry-run", "checksum", "include", "exclude", "progress", "parallelism")
+}
+
+// syncFilter selects the files and objects handled by sync.
+type syncFilter struct {
+	include []string
+	exclude []string
+}
+
+// newSyncFilter checks the glob patterns and returns a filter using them.
+func newSyncFilter(include, exclude []string) (*syncFilter, error) {
+	for _, pattern := range append(append([]string{}, include...), exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %v", pattern, err)
+		}
+	}
+	return &syncFilter{include: include, exclude: exclude}, nil
+}
+
+// Matches returns whether the slash separated relative path is synced. A
+// pattern matches either the whole path or its last element.
+func (filter *syncFilter) Matches(rel string) bool {
+	matches := func(patterns []string) bool {
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, rel); ok {
+				return true
+			}
+			if ok, _ := path.Match(pattern, path.Base(rel)); ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(filter.include) > 0 && !matches(filter.include) {
+		return false
+	}
+	return !matches(filter.exclude)
+}
+
+// syncFile is a local file which is compared against the existing objects.
+type syncFile struct {
+	rel     string
+	size    int64
+	modTime time.Time
+	sha256  string
+}
+
+// needsUpload returns the reason for uploading the file, or an empty string
+// when the object is up to date.
+func (file *syncFile) needsUpload(object *storj.Object, checksum bool) string {
+	switch {
+	case object == nil:
+		return "new"
+	case object.Size != file.size:
+		return "size changed"
+	case checksum && object.Metadata[syncSHA256Key] != file.sha256:
+		return "checksum changed"
+	case !checksum && object.Metadata[syncMtimeKey] != formatSyncMtime(file.modTime):
+		return "modification time changed"
+	}
+	return ""
+}
+
+func formatSyncMtime(modTime time.Time) string {
+	return modTime.UTC().Format(time.RFC3339Nano)
+}
+
+// syncMain is the function executed when syncCmd is called.
+func syncMain(cmd *cobra.Command, args []string) (err error) {
+	if len(args) == 0 {
+		return fmt.Errorf("no source specified for sync")
+	}
+	if len(args) == 1 {
+		return fmt.Errorf("no destination specified")
+	}
+	if *syncParallelism < 1 {
+		return fmt.Errorf("parallelism must be at least 1: %d", *syncParallelism)
+	}
+
+	ctx, _ := process.Ctx(cmd)
+
+	src, err := fpath.New(args[0])
+	if err != nil {
+		return err
+	}
+	if !src.IsLocal() {
+		return fmt.Errorf("source must be local path: %s", src)
+	}
+
+	dst, err := fpath.New(args[1])
+	if err != nil {
+		return err
+	}
+	if dst.IsLocal() {
+		return fmt.Errorf("destination must be Storj URL: %s", dst)
+	}
+
+	fileInfo, err := os.Stat(src.Path())
+	if err != nil {
+		return err
+	}
+	if !fileInfo.IsDir() {
+		return fmt.Errorf("source must be a directory: %s", src)
+	}
+
+	filter, err := newSyncFilter(*syncInclude, *syncExclude)
+	if err != nil {
+		return err
+	}
+
+	var files []*syncFile
+	err = walkFiles(src.Path(), func(rel string, info os.FileInfo) error {
+		if filter.Matches(rel) {
+			files = append(files, &syncFile{rel: rel, size: info.Size(), modTime: info.ModTime()})
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	project, bucket, err := cfg.GetProjectAndBucket(ctx, dst.Bucket())
+	if err != nil {
+		return convertError(err, dst)
+	}
+	defer closeProjectAndBucket(project, bucket)
+
+	list, err := listRecursive(ctx, bucket, dst)
+	if err != nil {
+		return convertError(err, dst)
+	}
+	objects := make(map[string]*storj.Object, len(list))
+	for i := range list {
+		objects[list[i].Path] = &list[i]
+	}
+
+	var transfers []transfer
+	for _, file := range files {
+		object := objects[file.rel]
+		delete(objects, file.rel)
+
+		if *syncChecksum {
+			file.sha256, err = hashFile(filepath.Join(src.Path(), filepath.FromSlash(file.rel)))
+			if err != nil {
+				return err
+			}
+		}
+
+		reason := file.needsUpload(object, *syncChecksum)
+		if reason == "" {
+			continue
+		}
+
+		if *syncDryRun {
+			fmt.Printf("would upload %s (%s)\n", file.rel, reason)
+		}
+		transfers = append(transfers, transfer{
+			path: file.rel,
+			src:  src.Join(filepath.FromSlash(file.rel)),
+			dst:  dst.Join(file.rel),
+			size: file.size,
+		})
+	}
+
+	// the remaining objects don't exist in the source
+	var extraneous []string
+	if *syncDelete {
+		for rel := range objects {
+			if filter.Matches(rel) {
+				extraneous = append(extraneous, rel)
+			}
+		}
+		sort.Strings(extraneous)
+	}
+
+	if *syncDryRun {
+		for _, rel := range extraneous {
+			fmt.Printf("would delete %s\n", rel)
+		}
+		fmt.Printf("%d files to upload, %d objects to delete\n", len(transfers), len(extraneous))
+		return nil
+	}
+
+	hashes := make(map[string]string, len(files))
+	for _, file := range files {
+		hashes[file.rel] = file.sha256
+	}
+
+	uploadErr := runTransfers(ctx, transfers, *syncParallelism, *syncProgress, func(ctx context.Context, t transfer, progress *transferProgress) (err error) {
+		file, err := os.Open(t.src.Path())
+		if err != nil {
+			return err
+		}
+		defer func() { err = errs.Combine(err, file.Close()) }()
+
+		// the modification time is read again, because the file may have
+		// changed since it was compared
+		info, err := file.Stat()
+		if err != nil {
+			return err
+		}
+
+		opts := &libuplink.UploadOptions{
+			Metadata: map[string]string{
+				syncMtimeKey: formatSyncMtime(info.ModTime()),
+			},
+		}
+		if hash := hashes[t.path]; hash != "" {
+			opts.Metadata[syncSHA256Key] = hash
+		}
+		opts.Volatile.RedundancyScheme = cfg.GetRedundancyScheme()
+		opts.Volatile.EncryptionParameters = cfg.GetEncryptionParameters()
+
+		return bucket.UploadObject(ctx, t.dst.Path(), progress.Wrap(file), opts)
+	})
+
+	return errs.Combine(uploadErr, deleteExtraneous(ctx, bucket, dst, extraneous))
+}
+
+// deleteExtraneous deletes the objects which don't exist in the source.
+func deleteExtraneous(ctx context.Context, bucket *libuplink.Bucket, dst fpath.FPath, extraneous []string) error {
+	var failed int
+	for _, rel := range extraneous {
+		object := dst.Join(rel)
+		if err := bucket.DeleteObject(ctx, object.Path()); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to delete %s: %v\n", object.String(), err)
+			failed++
+			continue
+		}
+		fmt.Printf("Deleted %s\n", object.String())
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d objects failed to delete", failed, len(extraneous))
+	}
+	return nil
+}
+
+// hashFile returns the hex encoded SHA-256 hash of the file.
+func hashFile(name string) (_ string, err error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer func() { err = errs.Combine(err, file.Close()) }()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
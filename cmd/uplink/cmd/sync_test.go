@@ -0,0 +1,99 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd_test
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/memory"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/private/testplanet"
+)
+
+func TestSync(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 4,
+		UplinkCount:      1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		uplinkExe := ctx.Compile("storj.io/storj/cmd/uplink")
+
+		uplink := func(args ...string) string {
+			output, err := exec.Command(uplinkExe, append([]string{"--config-dir", ctx.Dir("uplink")}, args...)...).CombinedOutput()
+			t.Log(string(output))
+			require.NoError(t, err)
+			return string(output)
+		}
+
+		uplink("import", planet.Uplinks[0].GetConfig(planet.Satellites[0]).Access)
+
+		bucketName := testrand.BucketName()
+		uplink("mb", "sj://"+bucketName)
+
+		source := ctx.Dir("source")
+		write := func(name string, data []byte) {
+			path := filepath.Join(source, filepath.FromSlash(name))
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+			require.NoError(t, ioutil.WriteFile(path, data, 0644))
+		}
+		download := func(name string) []byte {
+			data, err := planet.Uplinks[0].Download(ctx, planet.Satellites[0], bucketName, "backup/"+name)
+			require.NoError(t, err)
+			return data
+		}
+
+		unchanged := testrand.Bytes(memory.KiB)
+		write("unchanged.txt", unchanged)
+		write("changed.txt", testrand.Bytes(memory.KiB))
+		write("removed.txt", testrand.Bytes(memory.KiB))
+		write("logs/skipped.log", testrand.Bytes(memory.KiB))
+
+		dst := "sj://" + bucketName + "/backup"
+		uplink("sync", "--progress=false", "--exclude", "*.log", source, dst)
+
+		_, err := planet.Uplinks[0].Download(ctx, planet.Satellites[0], bucketName, "backup/logs/skipped.log")
+		require.True(t, storj.ErrObjectNotFound.Has(err), err)
+
+		changed := testrand.Bytes(memory.KiB)
+		write("changed.txt", changed)
+		later := time.Now().Add(time.Minute)
+		require.NoError(t, os.Chtimes(filepath.Join(source, "changed.txt"), later, later))
+		require.NoError(t, os.Remove(filepath.Join(source, "removed.txt")))
+		added := testrand.Bytes(2 * memory.KiB)
+		write("dir/added.txt", added)
+
+		output := uplink("sync", "--dry-run", "--delete", "--exclude", "*.log", source, dst)
+		require.Contains(t, output, "would upload changed.txt (modification time changed)")
+		require.Contains(t, output, "would upload dir/added.txt (new)")
+		require.Contains(t, output, "would delete removed.txt")
+		require.NotContains(t, output, "unchanged.txt")
+		require.NotEmpty(t, download("removed.txt"), "dry run doesn't delete anything")
+
+		uplink("sync", "--progress=false", "--delete", "--parallelism", "2", "--exclude", "*.log", source, dst)
+		require.Equal(t, unchanged, download("unchanged.txt"))
+		require.Equal(t, changed, download("changed.txt"))
+		require.Equal(t, added, download("dir/added.txt"))
+
+		_, err = planet.Uplinks[0].Download(ctx, planet.Satellites[0], bucketName, "backup/removed.txt")
+		require.True(t, storj.ErrObjectNotFound.Has(err), err)
+
+		// with checksums, touching a file doesn't upload it again
+		uplink("sync", "--progress=false", "--checksum", "--exclude", "*.log", source, dst)
+
+		earlier := time.Now().Add(-time.Hour)
+		require.NoError(t, os.Chtimes(filepath.Join(source, "unchanged.txt"), earlier, earlier))
+
+		output = uplink("sync", "--dry-run", "--checksum", "--exclude", "*.log", source, dst)
+		require.Contains(t, output, "0 files to upload, 0 objects to delete")
+	})
+}
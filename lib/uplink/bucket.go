@@ -5,12 +5,20 @@ package uplink
 
 import (
 	"context"
+	"crypto/rand"
 	"io"
 	"time"
 
+	"github.com/gogo/protobuf/proto"
 	"github.com/zeebo/errs"
 
+	"storj.io/common/encryption"
+	"storj.io/common/errs2"
+	"storj.io/common/paths"
+	"storj.io/common/pb"
+	"storj.io/common/rpc/rpcstatus"
 	"storj.io/common/storj"
+	"storj.io/storj/pkg/metainfopb"
 	"storj.io/uplink/metainfo/kvmetainfo"
 	"storj.io/uplink/storage/streams"
 	"storj.io/uplink/stream"
@@ -25,6 +33,8 @@ type Bucket struct {
 	bucket   storj.Bucket
 	metainfo *kvmetainfo.DB
 	streams  streams.Store
+	project  *Project
+	encStore *encryption.Store
 }
 
 // TODO: move the object related OpenObject to object.go
@@ -116,6 +126,156 @@ func (b *Bucket) DeleteObject(ctx context.Context, path storj.Path) (err error)
 	return b.metainfo.DeleteObject(ctx, b.bucket, path)
 }
 
+// CopyObject copies an object to newPath in the destination bucket, which
+// must belong to the same project. No data is transferred, the copy shares the
+// pieces of the original object on the storage nodes.
+func (b *Bucket) CopyObject(ctx context.Context, path storj.Path, dst *Bucket, newPath storj.Path) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return b.copyObject(ctx, path, dst, newPath, false)
+}
+
+// MoveObject moves an object to newPath in the destination bucket, which
+// must belong to the same project. No data is transferred.
+func (b *Bucket) MoveObject(ctx context.Context, path storj.Path, dst *Bucket, newPath storj.Path) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return b.copyObject(ctx, path, dst, newPath, true)
+}
+
+// copyObject encrypts the segment keys of the object for the new path and
+// lets the satellite copy or move the pointers of the object.
+func (b *Bucket) copyObject(ctx context.Context, path storj.Path, dst *Bucket, newPath storj.Path, move bool) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if path == "" || newPath == "" {
+		return storj.ErrNoPath.New("")
+	}
+
+	if b.encStore.EncryptionBypass || dst.encStore.EncryptionBypass {
+		return Error.New("copying objects is not supported without encryption keys")
+	}
+
+	encPath, err := encryption.EncryptPath(b.Name, paths.NewUnencrypted(path), b.PathCipher, b.encStore)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	derivedKey, err := encryption.DeriveContentKey(b.Name, paths.NewUnencrypted(path), b.encStore)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	newEncPath, err := encryption.EncryptPath(dst.Name, paths.NewUnencrypted(newPath), dst.PathCipher, dst.encStore)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	newDerivedKey, err := encryption.DeriveContentKey(dst.Name, paths.NewUnencrypted(newPath), dst.encStore)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	conn, err := b.project.dialer.DialAddressInsecureBestEffort(ctx, b.project.satelliteAddr)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, conn.Close()) }()
+
+	client := metainfopb.NewDRPCObjectCopyClient(conn.Raw())
+	apiKey := b.project.apiKey.key.SerializeRaw()
+
+	begin, err := client.BeginCopyObject(ctx, &metainfopb.BeginCopyObjectRequest{
+		ApiKey:        apiKey,
+		Bucket:        []byte(b.Name),
+		EncryptedPath: []byte(encPath.Raw()),
+	})
+	if err != nil {
+		return convertCopyError(err)
+	}
+
+	streamMeta := pb.StreamMeta{}
+	err = proto.Unmarshal(begin.EncryptedMetadata, &streamMeta)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	cipher := storj.CipherSuite(streamMeta.EncryptionType)
+	reencryptKey := func(encryptedKey, encryptedKeyNonce []byte) (newKey storj.EncryptedPrivateKey, newNonce storj.Nonce, err error) {
+		var nonce storj.Nonce
+		copy(nonce[:], encryptedKeyNonce)
+
+		contentKey, err := encryption.DecryptKey(encryptedKey, cipher, derivedKey, &nonce)
+		if err != nil {
+			return nil, storj.Nonce{}, err
+		}
+
+		_, err = rand.Read(newNonce[:])
+		if err != nil {
+			return nil, storj.Nonce{}, err
+		}
+
+		newKey, err = encryption.EncryptKey(contentKey, cipher, newDerivedKey, &newNonce)
+		return newKey, newNonce, err
+	}
+
+	// the stream info is encrypted with the content key of the last segment,
+	// so only the segment keys need to be encrypted for the new path
+	if streamMeta.LastSegmentMeta != nil {
+		newKey, newNonce, err := reencryptKey(streamMeta.LastSegmentMeta.EncryptedKey, streamMeta.LastSegmentMeta.KeyNonce)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		streamMeta.LastSegmentMeta = &pb.SegmentMeta{
+			EncryptedKey: newKey,
+			KeyNonce:     newNonce[:],
+		}
+	}
+
+	newMetadata, err := proto.Marshal(&streamMeta)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	var newSegmentKeys []*metainfopb.SegmentKey
+	for _, key := range begin.SegmentKeys {
+		newKey, newNonce, err := reencryptKey(key.EncryptedKey, key.EncryptedKeyNonce)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		newSegmentKeys = append(newSegmentKeys, &metainfopb.SegmentKey{
+			Index:             key.Index,
+			EncryptedKey:      newKey,
+			EncryptedKeyNonce: newNonce[:],
+		})
+	}
+
+	_, err = client.FinishCopyObject(ctx, &metainfopb.FinishCopyObjectRequest{
+		ApiKey:               apiKey,
+		Bucket:               []byte(b.Name),
+		EncryptedPath:        []byte(encPath.Raw()),
+		CreatedAt:            begin.CreatedAt,
+		NewBucket:            []byte(dst.Name),
+		NewEncryptedPath:     []byte(newEncPath.Raw()),
+		NewEncryptedMetadata: newMetadata,
+		NewSegmentKeys:       newSegmentKeys,
+		Move:                 move,
+	})
+	if err != nil {
+		return convertCopyError(err)
+	}
+	return nil
+}
+
+// convertCopyError converts the RPC status of a failed copy to the errors
+// returned by the other object operations.
+func convertCopyError(err error) error {
+	switch {
+	case errs2.IsRPC(err, rpcstatus.NotFound):
+		return storj.ErrObjectNotFound.Wrap(err)
+	case errs2.IsRPC(err, rpcstatus.AlreadyExists):
+		return ErrObjectExists.Wrap(err)
+	default:
+		return Error.Wrap(err)
+	}
+}
+
 // ListOptions controls options for the ListObjects() call.
 type ListOptions = storj.ListOptions
 
@@ -13,4 +13,7 @@ var (
 
 	// Error is the toplevel class of errors for the uplink library.
 	Error = errs.Class("libuplink")
+
+	// ErrObjectExists is returned when copying or moving an object to a path which already has an object.
+	ErrObjectExists = errs.Class("object already exists")
 )
@@ -21,10 +21,12 @@ import (
 
 // Project represents a specific project access session.
 type Project struct {
-	uplinkCfg *Config
-	dialer    rpc.Dialer
-	metainfo  *metainfo.Client
-	project   *kvmetainfo.Project
+	uplinkCfg     *Config
+	dialer        rpc.Dialer
+	satelliteAddr string
+	apiKey        APIKey
+	metainfo      *metainfo.Client
+	project       *kvmetainfo.Project
 }
 
 // BucketConfig holds information about a bucket's configuration. This is
@@ -194,6 +196,8 @@ func (p *Project) OpenBucket(ctx context.Context, bucketName string, access *Enc
 		bucket:       bucketInfo,
 		metainfo:     kvmetainfo.New(p.project, p.metainfo, streamStore, segmentStore, access.store),
 		streams:      streamStore,
+		project:      p,
+		encStore:     access.store,
 	}, nil
 }
 
@@ -0,0 +1,9 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package metainfopb contains protobuf definitions for metainfo services which
+// are not part of the storj.io/common protocol, such as copying objects, bucket
+// versioning and bucket lifecycle rules.
+package metainfopb
+
+//go:generate protoc -I=. --drpc_out=plugins=grpc+drpc:. objectcopy.proto objectversions.proto lifecycle.proto
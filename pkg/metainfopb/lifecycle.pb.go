@@ -0,0 +1,552 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: lifecycle.proto
+
+package metainfopb
+
+import (
+	context "context"
+	fmt "fmt"
+	math "math"
+
+	proto "github.com/gogo/protobuf/proto"
+	grpc "google.golang.org/grpc"
+
+	drpc "storj.io/drpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
+
+type LifecycleRule struct {
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// encrypted_prefix limits the rule to the objects under the prefix
+	EncryptedPrefix []byte `protobuf:"bytes,2,opt,name=encrypted_prefix,json=encryptedPrefix,proto3" json:"encrypted_prefix,omitempty"`
+	// expire_after_days removes objects the number of days after they were created, 0 disables it
+	ExpireAfterDays int32 `protobuf:"varint,3,opt,name=expire_after_days,json=expireAfterDays,proto3" json:"expire_after_days,omitempty"`
+	// abort_incomplete_uploads_after_hours removes the segments of uploads which
+	// haven't been committed the number of hours after they started, 0 disables it
+	AbortIncompleteUploadsAfterHours int32    `protobuf:"varint,4,opt,name=abort_incomplete_uploads_after_hours,json=abortIncompleteUploadsAfterHours,proto3" json:"abort_incomplete_uploads_after_hours,omitempty"`
+	XXX_NoUnkeyedLiteral             struct{} `json:"-"`
+	XXX_unrecognized                 []byte   `json:"-"`
+	XXX_sizecache                    int32    `json:"-"`
+}
+
+func (m *LifecycleRule) Reset()         { *m = LifecycleRule{} }
+func (m *LifecycleRule) String() string { return proto.CompactTextString(m) }
+func (*LifecycleRule) ProtoMessage()    {}
+func (*LifecycleRule) Descriptor() ([]byte, []int) {
+	return fileDescriptor_84f7c7eee8484930, []int{0}
+}
+func (m *LifecycleRule) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LifecycleRule.Unmarshal(m, b)
+}
+func (m *LifecycleRule) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LifecycleRule.Marshal(b, m, deterministic)
+}
+func (m *LifecycleRule) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LifecycleRule.Merge(m, src)
+}
+func (m *LifecycleRule) XXX_Size() int {
+	return xxx_messageInfo_LifecycleRule.Size(m)
+}
+func (m *LifecycleRule) XXX_DiscardUnknown() {
+	xxx_messageInfo_LifecycleRule.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LifecycleRule proto.InternalMessageInfo
+
+func (m *LifecycleRule) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *LifecycleRule) GetEncryptedPrefix() []byte {
+	if m != nil {
+		return m.EncryptedPrefix
+	}
+	return nil
+}
+
+func (m *LifecycleRule) GetExpireAfterDays() int32 {
+	if m != nil {
+		return m.ExpireAfterDays
+	}
+	return 0
+}
+
+func (m *LifecycleRule) GetAbortIncompleteUploadsAfterHours() int32 {
+	if m != nil {
+		return m.AbortIncompleteUploadsAfterHours
+	}
+	return 0
+}
+
+// LifecycleRules is the stored form of the rules of a bucket.
+type LifecycleRules struct {
+	Rules                []*LifecycleRule `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *LifecycleRules) Reset()         { *m = LifecycleRules{} }
+func (m *LifecycleRules) String() string { return proto.CompactTextString(m) }
+func (*LifecycleRules) ProtoMessage()    {}
+func (*LifecycleRules) Descriptor() ([]byte, []int) {
+	return fileDescriptor_84f7c7eee8484930, []int{1}
+}
+func (m *LifecycleRules) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LifecycleRules.Unmarshal(m, b)
+}
+func (m *LifecycleRules) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LifecycleRules.Marshal(b, m, deterministic)
+}
+func (m *LifecycleRules) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LifecycleRules.Merge(m, src)
+}
+func (m *LifecycleRules) XXX_Size() int {
+	return xxx_messageInfo_LifecycleRules.Size(m)
+}
+func (m *LifecycleRules) XXX_DiscardUnknown() {
+	xxx_messageInfo_LifecycleRules.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LifecycleRules proto.InternalMessageInfo
+
+func (m *LifecycleRules) GetRules() []*LifecycleRule {
+	if m != nil {
+		return m.Rules
+	}
+	return nil
+}
+
+type SetBucketLifecycleRequest struct {
+	ApiKey               []byte           `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	Bucket               []byte           `protobuf:"bytes,2,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	Rules                []*LifecycleRule `protobuf:"bytes,3,rep,name=rules,proto3" json:"rules,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *SetBucketLifecycleRequest) Reset()         { *m = SetBucketLifecycleRequest{} }
+func (m *SetBucketLifecycleRequest) String() string { return proto.CompactTextString(m) }
+func (*SetBucketLifecycleRequest) ProtoMessage()    {}
+func (*SetBucketLifecycleRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_84f7c7eee8484930, []int{2}
+}
+func (m *SetBucketLifecycleRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetBucketLifecycleRequest.Unmarshal(m, b)
+}
+func (m *SetBucketLifecycleRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetBucketLifecycleRequest.Marshal(b, m, deterministic)
+}
+func (m *SetBucketLifecycleRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetBucketLifecycleRequest.Merge(m, src)
+}
+func (m *SetBucketLifecycleRequest) XXX_Size() int {
+	return xxx_messageInfo_SetBucketLifecycleRequest.Size(m)
+}
+func (m *SetBucketLifecycleRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetBucketLifecycleRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetBucketLifecycleRequest proto.InternalMessageInfo
+
+func (m *SetBucketLifecycleRequest) GetApiKey() []byte {
+	if m != nil {
+		return m.ApiKey
+	}
+	return nil
+}
+
+func (m *SetBucketLifecycleRequest) GetBucket() []byte {
+	if m != nil {
+		return m.Bucket
+	}
+	return nil
+}
+
+func (m *SetBucketLifecycleRequest) GetRules() []*LifecycleRule {
+	if m != nil {
+		return m.Rules
+	}
+	return nil
+}
+
+type SetBucketLifecycleResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetBucketLifecycleResponse) Reset()         { *m = SetBucketLifecycleResponse{} }
+func (m *SetBucketLifecycleResponse) String() string { return proto.CompactTextString(m) }
+func (*SetBucketLifecycleResponse) ProtoMessage()    {}
+func (*SetBucketLifecycleResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_84f7c7eee8484930, []int{3}
+}
+func (m *SetBucketLifecycleResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetBucketLifecycleResponse.Unmarshal(m, b)
+}
+func (m *SetBucketLifecycleResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetBucketLifecycleResponse.Marshal(b, m, deterministic)
+}
+func (m *SetBucketLifecycleResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetBucketLifecycleResponse.Merge(m, src)
+}
+func (m *SetBucketLifecycleResponse) XXX_Size() int {
+	return xxx_messageInfo_SetBucketLifecycleResponse.Size(m)
+}
+func (m *SetBucketLifecycleResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetBucketLifecycleResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetBucketLifecycleResponse proto.InternalMessageInfo
+
+type GetBucketLifecycleRequest struct {
+	ApiKey               []byte   `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	Bucket               []byte   `protobuf:"bytes,2,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetBucketLifecycleRequest) Reset()         { *m = GetBucketLifecycleRequest{} }
+func (m *GetBucketLifecycleRequest) String() string { return proto.CompactTextString(m) }
+func (*GetBucketLifecycleRequest) ProtoMessage()    {}
+func (*GetBucketLifecycleRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_84f7c7eee8484930, []int{4}
+}
+func (m *GetBucketLifecycleRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBucketLifecycleRequest.Unmarshal(m, b)
+}
+func (m *GetBucketLifecycleRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBucketLifecycleRequest.Marshal(b, m, deterministic)
+}
+func (m *GetBucketLifecycleRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBucketLifecycleRequest.Merge(m, src)
+}
+func (m *GetBucketLifecycleRequest) XXX_Size() int {
+	return xxx_messageInfo_GetBucketLifecycleRequest.Size(m)
+}
+func (m *GetBucketLifecycleRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBucketLifecycleRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBucketLifecycleRequest proto.InternalMessageInfo
+
+func (m *GetBucketLifecycleRequest) GetApiKey() []byte {
+	if m != nil {
+		return m.ApiKey
+	}
+	return nil
+}
+
+func (m *GetBucketLifecycleRequest) GetBucket() []byte {
+	if m != nil {
+		return m.Bucket
+	}
+	return nil
+}
+
+type GetBucketLifecycleResponse struct {
+	Rules                []*LifecycleRule `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *GetBucketLifecycleResponse) Reset()         { *m = GetBucketLifecycleResponse{} }
+func (m *GetBucketLifecycleResponse) String() string { return proto.CompactTextString(m) }
+func (*GetBucketLifecycleResponse) ProtoMessage()    {}
+func (*GetBucketLifecycleResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_84f7c7eee8484930, []int{5}
+}
+func (m *GetBucketLifecycleResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBucketLifecycleResponse.Unmarshal(m, b)
+}
+func (m *GetBucketLifecycleResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBucketLifecycleResponse.Marshal(b, m, deterministic)
+}
+func (m *GetBucketLifecycleResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBucketLifecycleResponse.Merge(m, src)
+}
+func (m *GetBucketLifecycleResponse) XXX_Size() int {
+	return xxx_messageInfo_GetBucketLifecycleResponse.Size(m)
+}
+func (m *GetBucketLifecycleResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBucketLifecycleResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBucketLifecycleResponse proto.InternalMessageInfo
+
+func (m *GetBucketLifecycleResponse) GetRules() []*LifecycleRule {
+	if m != nil {
+		return m.Rules
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*LifecycleRule)(nil), "satellite.metainfo.LifecycleRule")
+	proto.RegisterType((*LifecycleRules)(nil), "satellite.metainfo.LifecycleRules")
+	proto.RegisterType((*SetBucketLifecycleRequest)(nil), "satellite.metainfo.SetBucketLifecycleRequest")
+	proto.RegisterType((*SetBucketLifecycleResponse)(nil), "satellite.metainfo.SetBucketLifecycleResponse")
+	proto.RegisterType((*GetBucketLifecycleRequest)(nil), "satellite.metainfo.GetBucketLifecycleRequest")
+	proto.RegisterType((*GetBucketLifecycleResponse)(nil), "satellite.metainfo.GetBucketLifecycleResponse")
+}
+
+func init() { proto.RegisterFile("lifecycle.proto", fileDescriptor_84f7c7eee8484930) }
+
+var fileDescriptor_84f7c7eee8484930 = []byte{
+	// 371 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x93, 0x41, 0x8b, 0xda, 0x40,
+	0x14, 0xc7, 0x19, 0xad, 0x96, 0xbe, 0x5a, 0xd3, 0xce, 0xa1, 0x8d, 0xd2, 0x43, 0x1a, 0x7a, 0x48,
+	0x0b, 0xcd, 0xc1, 0x1e, 0x7a, 0xae, 0x14, 0x52, 0xa9, 0x94, 0x92, 0xe2, 0x65, 0x2f, 0x61, 0x92,
+	0xbc, 0xb0, 0x83, 0x31, 0x33, 0x3b, 0x33, 0x01, 0xf3, 0x01, 0xf6, 0xab, 0xed, 0x97, 0xda, 0xcb,
+	0x62, 0x8c, 0x8a, 0xab, 0x82, 0x2e, 0x7b, 0x4b, 0xde, 0xfc, 0xde, 0x7b, 0xff, 0xff, 0x7f, 0x18,
+	0xb0, 0x72, 0x9e, 0x61, 0x52, 0x25, 0x39, 0xfa, 0x52, 0x09, 0x23, 0x28, 0xd5, 0xcc, 0x60, 0x9e,
+	0x73, 0x83, 0xfe, 0x02, 0x0d, 0xe3, 0x45, 0x26, 0xdc, 0x3b, 0x02, 0x6f, 0xa6, 0x1b, 0x2e, 0x2c,
+	0x73, 0xa4, 0x7d, 0x68, 0xf1, 0xd4, 0x26, 0x0e, 0xf1, 0x5e, 0x85, 0x2d, 0x9e, 0xd2, 0x2f, 0xf0,
+	0x16, 0x8b, 0x44, 0x55, 0xd2, 0x60, 0x1a, 0x49, 0x85, 0x19, 0x5f, 0xda, 0x2d, 0x87, 0x78, 0xbd,
+	0xd0, 0xda, 0xd6, 0xff, 0xd5, 0x65, 0xfa, 0x15, 0xde, 0xe1, 0x52, 0x72, 0x85, 0x11, 0xcb, 0x0c,
+	0xaa, 0x28, 0x65, 0x95, 0xb6, 0xdb, 0x0e, 0xf1, 0x3a, 0xa1, 0xb5, 0x3e, 0xf8, 0xb9, 0xaa, 0xff,
+	0x62, 0x95, 0xa6, 0x7f, 0xe1, 0x33, 0x8b, 0x85, 0x32, 0x11, 0x2f, 0x12, 0xb1, 0x90, 0x39, 0x1a,
+	0x8c, 0x4a, 0x99, 0x0b, 0x96, 0xea, 0xa6, 0xfb, 0x5a, 0x94, 0x4a, 0xdb, 0x2f, 0xea, 0x76, 0xa7,
+	0x66, 0x27, 0x5b, 0x74, 0xb6, 0x26, 0xeb, 0x71, 0xbf, 0x57, 0x9c, 0x3b, 0x81, 0xfe, 0x9e, 0x0f,
+	0x4d, 0x7f, 0x40, 0x47, 0xad, 0x3e, 0x6c, 0xe2, 0xb4, 0xbd, 0xd7, 0xa3, 0x4f, 0xfe, 0xa1, 0x7d,
+	0x7f, 0xaf, 0x25, 0x5c, 0xf3, 0xee, 0x2d, 0x81, 0xc1, 0x7f, 0x34, 0xe3, 0x32, 0x99, 0xa3, 0xd9,
+	0x11, 0x78, 0x53, 0xa2, 0x36, 0xf4, 0x03, 0xbc, 0x64, 0x92, 0x47, 0x73, 0xac, 0xea, 0x90, 0x7a,
+	0x61, 0x97, 0x49, 0xfe, 0x07, 0x2b, 0xfa, 0x1e, 0xba, 0x71, 0xdd, 0xd2, 0xc4, 0xd3, 0xfc, 0xed,
+	0x74, 0xb4, 0x2f, 0xd4, 0xf1, 0x11, 0x86, 0xc7, 0x64, 0x68, 0x29, 0x0a, 0x8d, 0xee, 0x14, 0x06,
+	0xc1, 0xb3, 0x89, 0x74, 0x67, 0x30, 0x0c, 0x4e, 0xee, 0x7a, 0x72, 0x94, 0xa3, 0x7b, 0x02, 0xd6,
+	0xa3, 0xa1, 0x54, 0x03, 0x3d, 0xb4, 0x45, 0xbf, 0x1d, 0x9b, 0x79, 0xf2, 0x16, 0x86, 0xfe, 0xb9,
+	0x78, 0xe3, 0x40, 0x03, 0x0d, 0xce, 0x5c, 0x1a, 0x5c, 0xb6, 0xf4, 0x74, 0x6c, 0xe3, 0xde, 0x15,
+	0x6c, 0x30, 0x19, 0xc7, 0xdd, 0xfa, 0x15, 0x7e, 0x7f, 0x18, 0x00, 0x87, 0x64, 0x60, 0xab, 0x98,
+	0x03, 0x00, 0x00,
+}
+
+type DRPCBucketLifecycleClient interface {
+	DRPCConn() drpc.Conn
+
+	// SetBucketLifecycle replaces the lifecycle rules of a bucket, no rules removes them
+	SetBucketLifecycle(ctx context.Context, in *SetBucketLifecycleRequest) (*SetBucketLifecycleResponse, error)
+	GetBucketLifecycle(ctx context.Context, in *GetBucketLifecycleRequest) (*GetBucketLifecycleResponse, error)
+}
+
+type drpcBucketLifecycleClient struct {
+	cc drpc.Conn
+}
+
+func NewDRPCBucketLifecycleClient(cc drpc.Conn) DRPCBucketLifecycleClient {
+	return &drpcBucketLifecycleClient{cc}
+}
+
+func (c *drpcBucketLifecycleClient) DRPCConn() drpc.Conn { return c.cc }
+
+func (c *drpcBucketLifecycleClient) SetBucketLifecycle(ctx context.Context, in *SetBucketLifecycleRequest) (*SetBucketLifecycleResponse, error) {
+	out := new(SetBucketLifecycleResponse)
+	err := c.cc.Invoke(ctx, "/satellite.metainfo.BucketLifecycle/SetBucketLifecycle", in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcBucketLifecycleClient) GetBucketLifecycle(ctx context.Context, in *GetBucketLifecycleRequest) (*GetBucketLifecycleResponse, error) {
+	out := new(GetBucketLifecycleResponse)
+	err := c.cc.Invoke(ctx, "/satellite.metainfo.BucketLifecycle/GetBucketLifecycle", in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+type DRPCBucketLifecycleServer interface {
+	// SetBucketLifecycle replaces the lifecycle rules of a bucket, no rules removes them
+	SetBucketLifecycle(context.Context, *SetBucketLifecycleRequest) (*SetBucketLifecycleResponse, error)
+	GetBucketLifecycle(context.Context, *GetBucketLifecycleRequest) (*GetBucketLifecycleResponse, error)
+}
+
+type DRPCBucketLifecycleDescription struct{}
+
+func (DRPCBucketLifecycleDescription) NumMethods() int { return 2 }
+
+func (DRPCBucketLifecycleDescription) Method(n int) (string, drpc.Handler, interface{}, bool) {
+	switch n {
+	case 0:
+		return "/satellite.metainfo.BucketLifecycle/SetBucketLifecycle",
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCBucketLifecycleServer).
+					SetBucketLifecycle(
+						ctx,
+						in1.(*SetBucketLifecycleRequest),
+					)
+			}, DRPCBucketLifecycleServer.SetBucketLifecycle, true
+	case 1:
+		return "/satellite.metainfo.BucketLifecycle/GetBucketLifecycle",
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCBucketLifecycleServer).
+					GetBucketLifecycle(
+						ctx,
+						in1.(*GetBucketLifecycleRequest),
+					)
+			}, DRPCBucketLifecycleServer.GetBucketLifecycle, true
+	default:
+		return "", nil, nil, false
+	}
+}
+
+func DRPCRegisterBucketLifecycle(srv drpc.Server, impl DRPCBucketLifecycleServer) {
+	srv.Register(impl, DRPCBucketLifecycleDescription{})
+}
+
+type DRPCBucketLifecycle_SetBucketLifecycleStream interface {
+	drpc.Stream
+	SendAndClose(*SetBucketLifecycleResponse) error
+}
+
+type drpcBucketLifecycleSetBucketLifecycleStream struct {
+	drpc.Stream
+}
+
+func (x *drpcBucketLifecycleSetBucketLifecycleStream) SendAndClose(m *SetBucketLifecycleResponse) error {
+	if err := x.MsgSend(m); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCBucketLifecycle_GetBucketLifecycleStream interface {
+	drpc.Stream
+	SendAndClose(*GetBucketLifecycleResponse) error
+}
+
+type drpcBucketLifecycleGetBucketLifecycleStream struct {
+	drpc.Stream
+}
+
+func (x *drpcBucketLifecycleGetBucketLifecycleStream) SendAndClose(m *GetBucketLifecycleResponse) error {
+	if err := x.MsgSend(m); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// BucketLifecycleClient is the client API for BucketLifecycle service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type BucketLifecycleClient interface {
+	// SetBucketLifecycle replaces the lifecycle rules of a bucket, no rules removes them
+	SetBucketLifecycle(ctx context.Context, in *SetBucketLifecycleRequest, opts ...grpc.CallOption) (*SetBucketLifecycleResponse, error)
+	GetBucketLifecycle(ctx context.Context, in *GetBucketLifecycleRequest, opts ...grpc.CallOption) (*GetBucketLifecycleResponse, error)
+}
+
+type bucketLifecycleClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewBucketLifecycleClient(cc *grpc.ClientConn) BucketLifecycleClient {
+	return &bucketLifecycleClient{cc}
+}
+
+func (c *bucketLifecycleClient) SetBucketLifecycle(ctx context.Context, in *SetBucketLifecycleRequest, opts ...grpc.CallOption) (*SetBucketLifecycleResponse, error) {
+	out := new(SetBucketLifecycleResponse)
+	err := c.cc.Invoke(ctx, "/satellite.metainfo.BucketLifecycle/SetBucketLifecycle", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bucketLifecycleClient) GetBucketLifecycle(ctx context.Context, in *GetBucketLifecycleRequest, opts ...grpc.CallOption) (*GetBucketLifecycleResponse, error) {
+	out := new(GetBucketLifecycleResponse)
+	err := c.cc.Invoke(ctx, "/satellite.metainfo.BucketLifecycle/GetBucketLifecycle", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BucketLifecycleServer is the server API for BucketLifecycle service.
+type BucketLifecycleServer interface {
+	// SetBucketLifecycle replaces the lifecycle rules of a bucket, no rules removes them
+	SetBucketLifecycle(context.Context, *SetBucketLifecycleRequest) (*SetBucketLifecycleResponse, error)
+	GetBucketLifecycle(context.Context, *GetBucketLifecycleRequest) (*GetBucketLifecycleResponse, error)
+}
+
+func RegisterBucketLifecycleServer(s *grpc.Server, srv BucketLifecycleServer) {
+	s.RegisterService(&_BucketLifecycle_serviceDesc, srv)
+}
+
+func _BucketLifecycle_SetBucketLifecycle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetBucketLifecycleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BucketLifecycleServer).SetBucketLifecycle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/satellite.metainfo.BucketLifecycle/SetBucketLifecycle",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BucketLifecycleServer).SetBucketLifecycle(ctx, req.(*SetBucketLifecycleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BucketLifecycle_GetBucketLifecycle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBucketLifecycleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BucketLifecycleServer).GetBucketLifecycle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/satellite.metainfo.BucketLifecycle/GetBucketLifecycle",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BucketLifecycleServer).GetBucketLifecycle(ctx, req.(*GetBucketLifecycleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _BucketLifecycle_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "satellite.metainfo.BucketLifecycle",
+	HandlerType: (*BucketLifecycleServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SetBucketLifecycle",
+			Handler:    _BucketLifecycle_SetBucketLifecycle_Handler,
+		},
+		{
+			MethodName: "GetBucketLifecycle",
+			Handler:    _BucketLifecycle_GetBucketLifecycle_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "lifecycle.proto",
+}
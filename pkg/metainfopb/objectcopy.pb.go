@@ -0,0 +1,595 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: objectcopy.proto
+
+package metainfopb
+
+import (
+	context "context"
+	fmt "fmt"
+	math "math"
+	time "time"
+
+	_ "github.com/gogo/protobuf/gogoproto"
+	proto "github.com/gogo/protobuf/proto"
+	_ "github.com/golang/protobuf/ptypes/timestamp"
+	grpc "google.golang.org/grpc"
+
+	drpc "storj.io/drpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+var _ = time.Kitchen
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
+
+type BeginCopyObjectRequest struct {
+	ApiKey               []byte   `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	Bucket               []byte   `protobuf:"bytes,2,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	EncryptedPath        []byte   `protobuf:"bytes,3,opt,name=encrypted_path,json=encryptedPath,proto3" json:"encrypted_path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BeginCopyObjectRequest) Reset()         { *m = BeginCopyObjectRequest{} }
+func (m *BeginCopyObjectRequest) String() string { return proto.CompactTextString(m) }
+func (*BeginCopyObjectRequest) ProtoMessage()    {}
+func (*BeginCopyObjectRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_91bdfc70e2f2e852, []int{0}
+}
+func (m *BeginCopyObjectRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BeginCopyObjectRequest.Unmarshal(m, b)
+}
+func (m *BeginCopyObjectRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BeginCopyObjectRequest.Marshal(b, m, deterministic)
+}
+func (m *BeginCopyObjectRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BeginCopyObjectRequest.Merge(m, src)
+}
+func (m *BeginCopyObjectRequest) XXX_Size() int {
+	return xxx_messageInfo_BeginCopyObjectRequest.Size(m)
+}
+func (m *BeginCopyObjectRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BeginCopyObjectRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BeginCopyObjectRequest proto.InternalMessageInfo
+
+func (m *BeginCopyObjectRequest) GetApiKey() []byte {
+	if m != nil {
+		return m.ApiKey
+	}
+	return nil
+}
+
+func (m *BeginCopyObjectRequest) GetBucket() []byte {
+	if m != nil {
+		return m.Bucket
+	}
+	return nil
+}
+
+func (m *BeginCopyObjectRequest) GetEncryptedPath() []byte {
+	if m != nil {
+		return m.EncryptedPath
+	}
+	return nil
+}
+
+type SegmentKey struct {
+	Index                int64    `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	EncryptedKey         []byte   `protobuf:"bytes,2,opt,name=encrypted_key,json=encryptedKey,proto3" json:"encrypted_key,omitempty"`
+	EncryptedKeyNonce    []byte   `protobuf:"bytes,3,opt,name=encrypted_key_nonce,json=encryptedKeyNonce,proto3" json:"encrypted_key_nonce,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SegmentKey) Reset()         { *m = SegmentKey{} }
+func (m *SegmentKey) String() string { return proto.CompactTextString(m) }
+func (*SegmentKey) ProtoMessage()    {}
+func (*SegmentKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_91bdfc70e2f2e852, []int{1}
+}
+func (m *SegmentKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SegmentKey.Unmarshal(m, b)
+}
+func (m *SegmentKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SegmentKey.Marshal(b, m, deterministic)
+}
+func (m *SegmentKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SegmentKey.Merge(m, src)
+}
+func (m *SegmentKey) XXX_Size() int {
+	return xxx_messageInfo_SegmentKey.Size(m)
+}
+func (m *SegmentKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_SegmentKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SegmentKey proto.InternalMessageInfo
+
+func (m *SegmentKey) GetIndex() int64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *SegmentKey) GetEncryptedKey() []byte {
+	if m != nil {
+		return m.EncryptedKey
+	}
+	return nil
+}
+
+func (m *SegmentKey) GetEncryptedKeyNonce() []byte {
+	if m != nil {
+		return m.EncryptedKeyNonce
+	}
+	return nil
+}
+
+type BeginCopyObjectResponse struct {
+	CreatedAt time.Time `protobuf:"bytes,1,opt,name=created_at,json=createdAt,proto3,stdtime" json:"created_at"`
+	// encrypted_metadata is the stream metadata, which contains the key of the last segment
+	EncryptedMetadata []byte `protobuf:"bytes,2,opt,name=encrypted_metadata,json=encryptedMetadata,proto3" json:"encrypted_metadata,omitempty"`
+	// segment_keys contains the keys of the other segments, ordered by index
+	SegmentKeys          []*SegmentKey `protobuf:"bytes,3,rep,name=segment_keys,json=segmentKeys,proto3" json:"segment_keys,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *BeginCopyObjectResponse) Reset()         { *m = BeginCopyObjectResponse{} }
+func (m *BeginCopyObjectResponse) String() string { return proto.CompactTextString(m) }
+func (*BeginCopyObjectResponse) ProtoMessage()    {}
+func (*BeginCopyObjectResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_91bdfc70e2f2e852, []int{2}
+}
+func (m *BeginCopyObjectResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BeginCopyObjectResponse.Unmarshal(m, b)
+}
+func (m *BeginCopyObjectResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BeginCopyObjectResponse.Marshal(b, m, deterministic)
+}
+func (m *BeginCopyObjectResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BeginCopyObjectResponse.Merge(m, src)
+}
+func (m *BeginCopyObjectResponse) XXX_Size() int {
+	return xxx_messageInfo_BeginCopyObjectResponse.Size(m)
+}
+func (m *BeginCopyObjectResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BeginCopyObjectResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BeginCopyObjectResponse proto.InternalMessageInfo
+
+func (m *BeginCopyObjectResponse) GetCreatedAt() time.Time {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return time.Time{}
+}
+
+func (m *BeginCopyObjectResponse) GetEncryptedMetadata() []byte {
+	if m != nil {
+		return m.EncryptedMetadata
+	}
+	return nil
+}
+
+func (m *BeginCopyObjectResponse) GetSegmentKeys() []*SegmentKey {
+	if m != nil {
+		return m.SegmentKeys
+	}
+	return nil
+}
+
+type FinishCopyObjectRequest struct {
+	ApiKey               []byte        `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	Bucket               []byte        `protobuf:"bytes,2,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	EncryptedPath        []byte        `protobuf:"bytes,3,opt,name=encrypted_path,json=encryptedPath,proto3" json:"encrypted_path,omitempty"`
+	CreatedAt            time.Time     `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3,stdtime" json:"created_at"`
+	NewBucket            []byte        `protobuf:"bytes,5,opt,name=new_bucket,json=newBucket,proto3" json:"new_bucket,omitempty"`
+	NewEncryptedPath     []byte        `protobuf:"bytes,6,opt,name=new_encrypted_path,json=newEncryptedPath,proto3" json:"new_encrypted_path,omitempty"`
+	NewEncryptedMetadata []byte        `protobuf:"bytes,7,opt,name=new_encrypted_metadata,json=newEncryptedMetadata,proto3" json:"new_encrypted_metadata,omitempty"`
+	NewSegmentKeys       []*SegmentKey `protobuf:"bytes,8,rep,name=new_segment_keys,json=newSegmentKeys,proto3" json:"new_segment_keys,omitempty"`
+	// move deletes the object from the old path
+	Move                 bool     `protobuf:"varint,9,opt,name=move,proto3" json:"move,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FinishCopyObjectRequest) Reset()         { *m = FinishCopyObjectRequest{} }
+func (m *FinishCopyObjectRequest) String() string { return proto.CompactTextString(m) }
+func (*FinishCopyObjectRequest) ProtoMessage()    {}
+func (*FinishCopyObjectRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_91bdfc70e2f2e852, []int{3}
+}
+func (m *FinishCopyObjectRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FinishCopyObjectRequest.Unmarshal(m, b)
+}
+func (m *FinishCopyObjectRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FinishCopyObjectRequest.Marshal(b, m, deterministic)
+}
+func (m *FinishCopyObjectRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FinishCopyObjectRequest.Merge(m, src)
+}
+func (m *FinishCopyObjectRequest) XXX_Size() int {
+	return xxx_messageInfo_FinishCopyObjectRequest.Size(m)
+}
+func (m *FinishCopyObjectRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_FinishCopyObjectRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FinishCopyObjectRequest proto.InternalMessageInfo
+
+func (m *FinishCopyObjectRequest) GetApiKey() []byte {
+	if m != nil {
+		return m.ApiKey
+	}
+	return nil
+}
+
+func (m *FinishCopyObjectRequest) GetBucket() []byte {
+	if m != nil {
+		return m.Bucket
+	}
+	return nil
+}
+
+func (m *FinishCopyObjectRequest) GetEncryptedPath() []byte {
+	if m != nil {
+		return m.EncryptedPath
+	}
+	return nil
+}
+
+func (m *FinishCopyObjectRequest) GetCreatedAt() time.Time {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return time.Time{}
+}
+
+func (m *FinishCopyObjectRequest) GetNewBucket() []byte {
+	if m != nil {
+		return m.NewBucket
+	}
+	return nil
+}
+
+func (m *FinishCopyObjectRequest) GetNewEncryptedPath() []byte {
+	if m != nil {
+		return m.NewEncryptedPath
+	}
+	return nil
+}
+
+func (m *FinishCopyObjectRequest) GetNewEncryptedMetadata() []byte {
+	if m != nil {
+		return m.NewEncryptedMetadata
+	}
+	return nil
+}
+
+func (m *FinishCopyObjectRequest) GetNewSegmentKeys() []*SegmentKey {
+	if m != nil {
+		return m.NewSegmentKeys
+	}
+	return nil
+}
+
+func (m *FinishCopyObjectRequest) GetMove() bool {
+	if m != nil {
+		return m.Move
+	}
+	return false
+}
+
+type FinishCopyObjectResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FinishCopyObjectResponse) Reset()         { *m = FinishCopyObjectResponse{} }
+func (m *FinishCopyObjectResponse) String() string { return proto.CompactTextString(m) }
+func (*FinishCopyObjectResponse) ProtoMessage()    {}
+func (*FinishCopyObjectResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_91bdfc70e2f2e852, []int{4}
+}
+func (m *FinishCopyObjectResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FinishCopyObjectResponse.Unmarshal(m, b)
+}
+func (m *FinishCopyObjectResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FinishCopyObjectResponse.Marshal(b, m, deterministic)
+}
+func (m *FinishCopyObjectResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FinishCopyObjectResponse.Merge(m, src)
+}
+func (m *FinishCopyObjectResponse) XXX_Size() int {
+	return xxx_messageInfo_FinishCopyObjectResponse.Size(m)
+}
+func (m *FinishCopyObjectResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_FinishCopyObjectResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FinishCopyObjectResponse proto.InternalMessageInfo
+
+func init() {
+	proto.RegisterType((*BeginCopyObjectRequest)(nil), "satellite.metainfo.BeginCopyObjectRequest")
+	proto.RegisterType((*SegmentKey)(nil), "satellite.metainfo.SegmentKey")
+	proto.RegisterType((*BeginCopyObjectResponse)(nil), "satellite.metainfo.BeginCopyObjectResponse")
+	proto.RegisterType((*FinishCopyObjectRequest)(nil), "satellite.metainfo.FinishCopyObjectRequest")
+	proto.RegisterType((*FinishCopyObjectResponse)(nil), "satellite.metainfo.FinishCopyObjectResponse")
+}
+
+func init() { proto.RegisterFile("objectcopy.proto", fileDescriptor_91bdfc70e2f2e852) }
+
+var fileDescriptor_91bdfc70e2f2e852 = []byte{
+	// 502 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x53, 0xcd, 0x6e, 0xd3, 0x40,
+	0x10, 0xc6, 0x4d, 0x9b, 0x26, 0x93, 0x50, 0xc2, 0x52, 0x25, 0x96, 0x25, 0x48, 0x14, 0x84, 0x14,
+	0x41, 0x71, 0xa5, 0xc0, 0x0b, 0x34, 0x15, 0x08, 0x09, 0xf1, 0x23, 0xc3, 0x89, 0x4b, 0xb4, 0x76,
+	0xa6, 0x8e, 0xdb, 0x78, 0x77, 0xc9, 0x6e, 0x30, 0x7e, 0x0b, 0x6e, 0xbc, 0x12, 0x47, 0x9e, 0x00,
+	0x8e, 0xbc, 0x06, 0xf2, 0xae, 0xe3, 0x34, 0x4e, 0x90, 0x22, 0x0e, 0xbd, 0xed, 0xcc, 0x7c, 0x33,
+	0xf3, 0xf9, 0xfb, 0xc6, 0xd0, 0xe2, 0xfe, 0x25, 0x06, 0x2a, 0xe0, 0x22, 0x75, 0xc5, 0x9c, 0x2b,
+	0x4e, 0x88, 0xa4, 0x0a, 0x67, 0xb3, 0x48, 0xa1, 0x1b, 0xa3, 0xa2, 0x11, 0xbb, 0xe0, 0x0e, 0x84,
+	0x3c, 0xe4, 0xa6, 0xee, 0x74, 0x43, 0xce, 0xc3, 0x19, 0x9e, 0xea, 0xc8, 0x5f, 0x5c, 0x9c, 0xaa,
+	0x28, 0x46, 0xa9, 0x68, 0x2c, 0x0c, 0xa0, 0x2f, 0xa0, 0x3d, 0xc2, 0x30, 0x62, 0xe7, 0x5c, 0xa4,
+	0xef, 0xf4, 0x74, 0x0f, 0x3f, 0x2f, 0x50, 0x2a, 0xd2, 0x81, 0x43, 0x2a, 0xa2, 0xf1, 0x15, 0xa6,
+	0xb6, 0xd5, 0xb3, 0x06, 0x4d, 0xaf, 0x4a, 0x45, 0xf4, 0x1a, 0x53, 0xd2, 0x86, 0xaa, 0xbf, 0x08,
+	0xae, 0x50, 0xd9, 0x7b, 0x26, 0x6f, 0x22, 0xf2, 0x08, 0x8e, 0x90, 0x05, 0xf3, 0x54, 0x28, 0x9c,
+	0x8c, 0x05, 0x55, 0x53, 0xbb, 0xa2, 0xeb, 0xb7, 0x8b, 0xec, 0x7b, 0xaa, 0xa6, 0xfd, 0x04, 0xe0,
+	0x03, 0x86, 0x31, 0x32, 0x95, 0x0d, 0x3b, 0x86, 0x83, 0x88, 0x4d, 0xf0, 0xab, 0xde, 0x51, 0xf1,
+	0x4c, 0x40, 0x1e, 0xc2, 0xaa, 0x49, 0x33, 0x30, 0x9b, 0x9a, 0x45, 0x32, 0x6b, 0x75, 0xe1, 0xde,
+	0x1a, 0x68, 0xcc, 0x38, 0x0b, 0x30, 0x5f, 0x7a, 0xf7, 0x3a, 0xf4, 0x6d, 0x56, 0xe8, 0xff, 0xb4,
+	0xa0, 0xb3, 0xf1, 0xad, 0x52, 0x70, 0x26, 0x91, 0x9c, 0x03, 0x04, 0x73, 0xa4, 0xd9, 0x24, 0xaa,
+	0x34, 0x97, 0xc6, 0xd0, 0x71, 0x8d, 0x78, 0xee, 0x52, 0x3c, 0xf7, 0xe3, 0x52, 0xbc, 0x51, 0xed,
+	0xc7, 0xaf, 0xee, 0xad, 0x6f, 0xbf, 0xbb, 0x96, 0x57, 0xcf, 0xfb, 0xce, 0x14, 0x79, 0x0a, 0x64,
+	0x45, 0x28, 0xb3, 0x63, 0x42, 0x15, 0xb5, 0xf7, 0x4a, 0x7c, 0xde, 0xe4, 0x05, 0x72, 0x06, 0x4d,
+	0x69, 0x84, 0xc8, 0xd8, 0x4b, 0xbb, 0xd2, 0xab, 0x0c, 0x1a, 0xc3, 0x07, 0xee, 0xa6, 0xa5, 0xee,
+	0x4a, 0x30, 0xaf, 0x21, 0x8b, 0xb7, 0xec, 0x7f, 0xaf, 0x40, 0xe7, 0x65, 0xc4, 0x22, 0x39, 0xbd,
+	0x31, 0xff, 0x4a, 0x52, 0xed, 0xff, 0x9f, 0x54, 0xf7, 0x01, 0x18, 0x26, 0xe3, 0x9c, 0xc7, 0x81,
+	0xde, 0x53, 0x67, 0x98, 0x8c, 0x0c, 0x95, 0x13, 0x20, 0x59, 0xb9, 0x44, 0xa7, 0xaa, 0x61, 0x2d,
+	0x86, 0xc9, 0x8b, 0x35, 0x46, 0xcf, 0xa1, 0xbd, 0x8e, 0x2e, 0xb4, 0x3f, 0xd4, 0x1d, 0xc7, 0xd7,
+	0x3b, 0x0a, 0xf9, 0x5f, 0x41, 0x36, 0x69, 0xbc, 0x66, 0x41, 0x6d, 0x27, 0x0b, 0x8e, 0x18, 0x26,
+	0xab, 0x50, 0x12, 0x02, 0xfb, 0x31, 0xff, 0x82, 0x76, 0xbd, 0x67, 0x0d, 0x6a, 0x9e, 0x7e, 0xf7,
+	0x1d, 0xb0, 0x37, 0x8d, 0x31, 0xc7, 0x36, 0xfc, 0x63, 0x01, 0x98, 0x54, 0x56, 0x24, 0x97, 0x70,
+	0xa7, 0x74, 0x96, 0xe4, 0xf1, 0x36, 0x06, 0xdb, 0xff, 0x53, 0xe7, 0xc9, 0x4e, 0xd8, 0xfc, 0xce,
+	0x63, 0x68, 0x95, 0x69, 0x91, 0xad, 0x03, 0xfe, 0x71, 0x55, 0xce, 0xc9, 0x6e, 0x60, 0xb3, 0x6e,
+	0xd4, 0xfc, 0x04, 0x4b, 0x90, 0xf0, 0xfd, 0xaa, 0xbe, 0x8e, 0x67, 0x7f, 0x07, 0x00, 0x44, 0x0b,
+	0xf3, 0xbe, 0xc7, 0x04, 0x00, 0x00,
+}
+
+type DRPCObjectCopyClient interface {
+	DRPCConn() drpc.Conn
+
+	// BeginCopyObject returns the encrypted keys of all segments of an object,
+	// so the client can encrypt them for the new path
+	BeginCopyObject(ctx context.Context, in *BeginCopyObjectRequest) (*BeginCopyObjectResponse, error)
+	// FinishCopyObject copies or moves the object to the new path with the re-encrypted keys
+	FinishCopyObject(ctx context.Context, in *FinishCopyObjectRequest) (*FinishCopyObjectResponse, error)
+}
+
+type drpcObjectCopyClient struct {
+	cc drpc.Conn
+}
+
+func NewDRPCObjectCopyClient(cc drpc.Conn) DRPCObjectCopyClient {
+	return &drpcObjectCopyClient{cc}
+}
+
+func (c *drpcObjectCopyClient) DRPCConn() drpc.Conn { return c.cc }
+
+func (c *drpcObjectCopyClient) BeginCopyObject(ctx context.Context, in *BeginCopyObjectRequest) (*BeginCopyObjectResponse, error) {
+	out := new(BeginCopyObjectResponse)
+	err := c.cc.Invoke(ctx, "/satellite.metainfo.ObjectCopy/BeginCopyObject", in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcObjectCopyClient) FinishCopyObject(ctx context.Context, in *FinishCopyObjectRequest) (*FinishCopyObjectResponse, error) {
+	out := new(FinishCopyObjectResponse)
+	err := c.cc.Invoke(ctx, "/satellite.metainfo.ObjectCopy/FinishCopyObject", in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+type DRPCObjectCopyServer interface {
+	// BeginCopyObject returns the encrypted keys of all segments of an object,
+	// so the client can encrypt them for the new path
+	BeginCopyObject(context.Context, *BeginCopyObjectRequest) (*BeginCopyObjectResponse, error)
+	// FinishCopyObject copies or moves the object to the new path with the re-encrypted keys
+	FinishCopyObject(context.Context, *FinishCopyObjectRequest) (*FinishCopyObjectResponse, error)
+}
+
+type DRPCObjectCopyDescription struct{}
+
+func (DRPCObjectCopyDescription) NumMethods() int { return 2 }
+
+func (DRPCObjectCopyDescription) Method(n int) (string, drpc.Handler, interface{}, bool) {
+	switch n {
+	case 0:
+		return "/satellite.metainfo.ObjectCopy/BeginCopyObject",
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCObjectCopyServer).
+					BeginCopyObject(
+						ctx,
+						in1.(*BeginCopyObjectRequest),
+					)
+			}, DRPCObjectCopyServer.BeginCopyObject, true
+	case 1:
+		return "/satellite.metainfo.ObjectCopy/FinishCopyObject",
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCObjectCopyServer).
+					FinishCopyObject(
+						ctx,
+						in1.(*FinishCopyObjectRequest),
+					)
+			}, DRPCObjectCopyServer.FinishCopyObject, true
+	default:
+		return "", nil, nil, false
+	}
+}
+
+func DRPCRegisterObjectCopy(srv drpc.Server, impl DRPCObjectCopyServer) {
+	srv.Register(impl, DRPCObjectCopyDescription{})
+}
+
+type DRPCObjectCopy_BeginCopyObjectStream interface {
+	drpc.Stream
+	SendAndClose(*BeginCopyObjectResponse) error
+}
+
+type drpcObjectCopyBeginCopyObjectStream struct {
+	drpc.Stream
+}
+
+func (x *drpcObjectCopyBeginCopyObjectStream) SendAndClose(m *BeginCopyObjectResponse) error {
+	if err := x.MsgSend(m); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCObjectCopy_FinishCopyObjectStream interface {
+	drpc.Stream
+	SendAndClose(*FinishCopyObjectResponse) error
+}
+
+type drpcObjectCopyFinishCopyObjectStream struct {
+	drpc.Stream
+}
+
+func (x *drpcObjectCopyFinishCopyObjectStream) SendAndClose(m *FinishCopyObjectResponse) error {
+	if err := x.MsgSend(m); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// ObjectCopyClient is the client API for ObjectCopy service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ObjectCopyClient interface {
+	// BeginCopyObject returns the encrypted keys of all segments of an object,
+	// so the client can encrypt them for the new path
+	BeginCopyObject(ctx context.Context, in *BeginCopyObjectRequest, opts ...grpc.CallOption) (*BeginCopyObjectResponse, error)
+	// FinishCopyObject copies or moves the object to the new path with the re-encrypted keys
+	FinishCopyObject(ctx context.Context, in *FinishCopyObjectRequest, opts ...grpc.CallOption) (*FinishCopyObjectResponse, error)
+}
+
+type objectCopyClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewObjectCopyClient(cc *grpc.ClientConn) ObjectCopyClient {
+	return &objectCopyClient{cc}
+}
+
+func (c *objectCopyClient) BeginCopyObject(ctx context.Context, in *BeginCopyObjectRequest, opts ...grpc.CallOption) (*BeginCopyObjectResponse, error) {
+	out := new(BeginCopyObjectResponse)
+	err := c.cc.Invoke(ctx, "/satellite.metainfo.ObjectCopy/BeginCopyObject", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *objectCopyClient) FinishCopyObject(ctx context.Context, in *FinishCopyObjectRequest, opts ...grpc.CallOption) (*FinishCopyObjectResponse, error) {
+	out := new(FinishCopyObjectResponse)
+	err := c.cc.Invoke(ctx, "/satellite.metainfo.ObjectCopy/FinishCopyObject", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ObjectCopyServer is the server API for ObjectCopy service.
+type ObjectCopyServer interface {
+	// BeginCopyObject returns the encrypted keys of all segments of an object,
+	// so the client can encrypt them for the new path
+	BeginCopyObject(context.Context, *BeginCopyObjectRequest) (*BeginCopyObjectResponse, error)
+	// FinishCopyObject copies or moves the object to the new path with the re-encrypted keys
+	FinishCopyObject(context.Context, *FinishCopyObjectRequest) (*FinishCopyObjectResponse, error)
+}
+
+func RegisterObjectCopyServer(s *grpc.Server, srv ObjectCopyServer) {
+	s.RegisterService(&_ObjectCopy_serviceDesc, srv)
+}
+
+func _ObjectCopy_BeginCopyObject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BeginCopyObjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ObjectCopyServer).BeginCopyObject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/satellite.metainfo.ObjectCopy/BeginCopyObject",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ObjectCopyServer).BeginCopyObject(ctx, req.(*BeginCopyObjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ObjectCopy_FinishCopyObject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FinishCopyObjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ObjectCopyServer).FinishCopyObject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/satellite.metainfo.ObjectCopy/FinishCopyObject",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ObjectCopyServer).FinishCopyObject(ctx, req.(*FinishCopyObjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _ObjectCopy_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "satellite.metainfo.ObjectCopy",
+	HandlerType: (*ObjectCopyServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "BeginCopyObject",
+			Handler:    _ObjectCopy_BeginCopyObject_Handler,
+		},
+		{
+			MethodName: "FinishCopyObject",
+			Handler:    _ObjectCopy_FinishCopyObject_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "objectcopy.proto",
+}
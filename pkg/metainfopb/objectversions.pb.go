@@ -0,0 +1,759 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: objectversions.proto
+
+package metainfopb
+
+import (
+	context "context"
+	fmt "fmt"
+	math "math"
+	time "time"
+
+	_ "github.com/gogo/protobuf/gogoproto"
+	proto "github.com/gogo/protobuf/proto"
+	_ "github.com/golang/protobuf/ptypes/timestamp"
+	grpc "google.golang.org/grpc"
+
+	drpc "storj.io/drpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+var _ = time.Kitchen
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
+
+type Versioning int32
+
+const (
+	Versioning_UNVERSIONED Versioning = 0
+	Versioning_ENABLED     Versioning = 1
+	Versioning_SUSPENDED   Versioning = 2
+)
+
+var Versioning_name = map[int32]string{
+	0: "UNVERSIONED",
+	1: "ENABLED",
+	2: "SUSPENDED",
+}
+
+var Versioning_value = map[string]int32{
+	"UNVERSIONED": 0,
+	"ENABLED":     1,
+	"SUSPENDED":   2,
+}
+
+func (x Versioning) String() string {
+	return proto.EnumName(Versioning_name, int32(x))
+}
+
+func (Versioning) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_86ee1db033af2ad1, []int{0}
+}
+
+type SetBucketVersioningRequest struct {
+	ApiKey               []byte     `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	Bucket               []byte     `protobuf:"bytes,2,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	Versioning           Versioning `protobuf:"varint,3,opt,name=versioning,proto3,enum=satellite.metainfo.Versioning" json:"versioning,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *SetBucketVersioningRequest) Reset()         { *m = SetBucketVersioningRequest{} }
+func (m *SetBucketVersioningRequest) String() string { return proto.CompactTextString(m) }
+func (*SetBucketVersioningRequest) ProtoMessage()    {}
+func (*SetBucketVersioningRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_86ee1db033af2ad1, []int{0}
+}
+func (m *SetBucketVersioningRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetBucketVersioningRequest.Unmarshal(m, b)
+}
+func (m *SetBucketVersioningRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetBucketVersioningRequest.Marshal(b, m, deterministic)
+}
+func (m *SetBucketVersioningRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetBucketVersioningRequest.Merge(m, src)
+}
+func (m *SetBucketVersioningRequest) XXX_Size() int {
+	return xxx_messageInfo_SetBucketVersioningRequest.Size(m)
+}
+func (m *SetBucketVersioningRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetBucketVersioningRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetBucketVersioningRequest proto.InternalMessageInfo
+
+func (m *SetBucketVersioningRequest) GetApiKey() []byte {
+	if m != nil {
+		return m.ApiKey
+	}
+	return nil
+}
+
+func (m *SetBucketVersioningRequest) GetBucket() []byte {
+	if m != nil {
+		return m.Bucket
+	}
+	return nil
+}
+
+func (m *SetBucketVersioningRequest) GetVersioning() Versioning {
+	if m != nil {
+		return m.Versioning
+	}
+	return Versioning_UNVERSIONED
+}
+
+type SetBucketVersioningResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetBucketVersioningResponse) Reset()         { *m = SetBucketVersioningResponse{} }
+func (m *SetBucketVersioningResponse) String() string { return proto.CompactTextString(m) }
+func (*SetBucketVersioningResponse) ProtoMessage()    {}
+func (*SetBucketVersioningResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_86ee1db033af2ad1, []int{1}
+}
+func (m *SetBucketVersioningResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetBucketVersioningResponse.Unmarshal(m, b)
+}
+func (m *SetBucketVersioningResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetBucketVersioningResponse.Marshal(b, m, deterministic)
+}
+func (m *SetBucketVersioningResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetBucketVersioningResponse.Merge(m, src)
+}
+func (m *SetBucketVersioningResponse) XXX_Size() int {
+	return xxx_messageInfo_SetBucketVersioningResponse.Size(m)
+}
+func (m *SetBucketVersioningResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetBucketVersioningResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetBucketVersioningResponse proto.InternalMessageInfo
+
+type GetBucketVersioningRequest struct {
+	ApiKey               []byte   `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	Bucket               []byte   `protobuf:"bytes,2,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetBucketVersioningRequest) Reset()         { *m = GetBucketVersioningRequest{} }
+func (m *GetBucketVersioningRequest) String() string { return proto.CompactTextString(m) }
+func (*GetBucketVersioningRequest) ProtoMessage()    {}
+func (*GetBucketVersioningRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_86ee1db033af2ad1, []int{2}
+}
+func (m *GetBucketVersioningRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBucketVersioningRequest.Unmarshal(m, b)
+}
+func (m *GetBucketVersioningRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBucketVersioningRequest.Marshal(b, m, deterministic)
+}
+func (m *GetBucketVersioningRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBucketVersioningRequest.Merge(m, src)
+}
+func (m *GetBucketVersioningRequest) XXX_Size() int {
+	return xxx_messageInfo_GetBucketVersioningRequest.Size(m)
+}
+func (m *GetBucketVersioningRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBucketVersioningRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBucketVersioningRequest proto.InternalMessageInfo
+
+func (m *GetBucketVersioningRequest) GetApiKey() []byte {
+	if m != nil {
+		return m.ApiKey
+	}
+	return nil
+}
+
+func (m *GetBucketVersioningRequest) GetBucket() []byte {
+	if m != nil {
+		return m.Bucket
+	}
+	return nil
+}
+
+type GetBucketVersioningResponse struct {
+	Versioning           Versioning `protobuf:"varint,1,opt,name=versioning,proto3,enum=satellite.metainfo.Versioning" json:"versioning,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *GetBucketVersioningResponse) Reset()         { *m = GetBucketVersioningResponse{} }
+func (m *GetBucketVersioningResponse) String() string { return proto.CompactTextString(m) }
+func (*GetBucketVersioningResponse) ProtoMessage()    {}
+func (*GetBucketVersioningResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_86ee1db033af2ad1, []int{3}
+}
+func (m *GetBucketVersioningResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBucketVersioningResponse.Unmarshal(m, b)
+}
+func (m *GetBucketVersioningResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBucketVersioningResponse.Marshal(b, m, deterministic)
+}
+func (m *GetBucketVersioningResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBucketVersioningResponse.Merge(m, src)
+}
+func (m *GetBucketVersioningResponse) XXX_Size() int {
+	return xxx_messageInfo_GetBucketVersioningResponse.Size(m)
+}
+func (m *GetBucketVersioningResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBucketVersioningResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBucketVersioningResponse proto.InternalMessageInfo
+
+func (m *GetBucketVersioningResponse) GetVersioning() Versioning {
+	if m != nil {
+		return m.Versioning
+	}
+	return Versioning_UNVERSIONED
+}
+
+type ListObjectVersionsRequest struct {
+	ApiKey        []byte `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	Bucket        []byte `protobuf:"bytes,2,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	EncryptedPath []byte `protobuf:"bytes,3,opt,name=encrypted_path,json=encryptedPath,proto3" json:"encrypted_path,omitempty"`
+	// version_cursor lists the versions older than the cursor, 0 starts at the newest
+	VersionCursor        int32    `protobuf:"varint,4,opt,name=version_cursor,json=versionCursor,proto3" json:"version_cursor,omitempty"`
+	Limit                int32    `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListObjectVersionsRequest) Reset()         { *m = ListObjectVersionsRequest{} }
+func (m *ListObjectVersionsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListObjectVersionsRequest) ProtoMessage()    {}
+func (*ListObjectVersionsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_86ee1db033af2ad1, []int{4}
+}
+func (m *ListObjectVersionsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListObjectVersionsRequest.Unmarshal(m, b)
+}
+func (m *ListObjectVersionsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListObjectVersionsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListObjectVersionsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListObjectVersionsRequest.Merge(m, src)
+}
+func (m *ListObjectVersionsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListObjectVersionsRequest.Size(m)
+}
+func (m *ListObjectVersionsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListObjectVersionsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListObjectVersionsRequest proto.InternalMessageInfo
+
+func (m *ListObjectVersionsRequest) GetApiKey() []byte {
+	if m != nil {
+		return m.ApiKey
+	}
+	return nil
+}
+
+func (m *ListObjectVersionsRequest) GetBucket() []byte {
+	if m != nil {
+		return m.Bucket
+	}
+	return nil
+}
+
+func (m *ListObjectVersionsRequest) GetEncryptedPath() []byte {
+	if m != nil {
+		return m.EncryptedPath
+	}
+	return nil
+}
+
+func (m *ListObjectVersionsRequest) GetVersionCursor() int32 {
+	if m != nil {
+		return m.VersionCursor
+	}
+	return 0
+}
+
+func (m *ListObjectVersionsRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type ObjectVersion struct {
+	Version              int32     `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	IsLatest             bool      `protobuf:"varint,2,opt,name=is_latest,json=isLatest,proto3" json:"is_latest,omitempty"`
+	IsDeleteMarker       bool      `protobuf:"varint,3,opt,name=is_delete_marker,json=isDeleteMarker,proto3" json:"is_delete_marker,omitempty"`
+	CreatedAt            time.Time `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3,stdtime" json:"created_at"`
+	ExpiresAt            time.Time `protobuf:"bytes,5,opt,name=expires_at,json=expiresAt,proto3,stdtime" json:"expires_at"`
+	EncryptedMetadata    []byte    `protobuf:"bytes,6,opt,name=encrypted_metadata,json=encryptedMetadata,proto3" json:"encrypted_metadata,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *ObjectVersion) Reset()         { *m = ObjectVersion{} }
+func (m *ObjectVersion) String() string { return proto.CompactTextString(m) }
+func (*ObjectVersion) ProtoMessage()    {}
+func (*ObjectVersion) Descriptor() ([]byte, []int) {
+	return fileDescriptor_86ee1db033af2ad1, []int{5}
+}
+func (m *ObjectVersion) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ObjectVersion.Unmarshal(m, b)
+}
+func (m *ObjectVersion) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ObjectVersion.Marshal(b, m, deterministic)
+}
+func (m *ObjectVersion) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ObjectVersion.Merge(m, src)
+}
+func (m *ObjectVersion) XXX_Size() int {
+	return xxx_messageInfo_ObjectVersion.Size(m)
+}
+func (m *ObjectVersion) XXX_DiscardUnknown() {
+	xxx_messageInfo_ObjectVersion.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ObjectVersion proto.InternalMessageInfo
+
+func (m *ObjectVersion) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *ObjectVersion) GetIsLatest() bool {
+	if m != nil {
+		return m.IsLatest
+	}
+	return false
+}
+
+func (m *ObjectVersion) GetIsDeleteMarker() bool {
+	if m != nil {
+		return m.IsDeleteMarker
+	}
+	return false
+}
+
+func (m *ObjectVersion) GetCreatedAt() time.Time {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return time.Time{}
+}
+
+func (m *ObjectVersion) GetExpiresAt() time.Time {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return time.Time{}
+}
+
+func (m *ObjectVersion) GetEncryptedMetadata() []byte {
+	if m != nil {
+		return m.EncryptedMetadata
+	}
+	return nil
+}
+
+type ListObjectVersionsResponse struct {
+	Versions             []*ObjectVersion `protobuf:"bytes,1,rep,name=versions,proto3" json:"versions,omitempty"`
+	More                 bool             `protobuf:"varint,2,opt,name=more,proto3" json:"more,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *ListObjectVersionsResponse) Reset()         { *m = ListObjectVersionsResponse{} }
+func (m *ListObjectVersionsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListObjectVersionsResponse) ProtoMessage()    {}
+func (*ListObjectVersionsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_86ee1db033af2ad1, []int{6}
+}
+func (m *ListObjectVersionsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListObjectVersionsResponse.Unmarshal(m, b)
+}
+func (m *ListObjectVersionsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListObjectVersionsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListObjectVersionsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListObjectVersionsResponse.Merge(m, src)
+}
+func (m *ListObjectVersionsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListObjectVersionsResponse.Size(m)
+}
+func (m *ListObjectVersionsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListObjectVersionsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListObjectVersionsResponse proto.InternalMessageInfo
+
+func (m *ListObjectVersionsResponse) GetVersions() []*ObjectVersion {
+	if m != nil {
+		return m.Versions
+	}
+	return nil
+}
+
+func (m *ListObjectVersionsResponse) GetMore() bool {
+	if m != nil {
+		return m.More
+	}
+	return false
+}
+
+func init() {
+	proto.RegisterEnum("satellite.metainfo.Versioning", Versioning_name, Versioning_value)
+	proto.RegisterType((*SetBucketVersioningRequest)(nil), "satellite.metainfo.SetBucketVersioningRequest")
+	proto.RegisterType((*SetBucketVersioningResponse)(nil), "satellite.metainfo.SetBucketVersioningResponse")
+	proto.RegisterType((*GetBucketVersioningRequest)(nil), "satellite.metainfo.GetBucketVersioningRequest")
+	proto.RegisterType((*GetBucketVersioningResponse)(nil), "satellite.metainfo.GetBucketVersioningResponse")
+	proto.RegisterType((*ListObjectVersionsRequest)(nil), "satellite.metainfo.ListObjectVersionsRequest")
+	proto.RegisterType((*ObjectVersion)(nil), "satellite.metainfo.ObjectVersion")
+	proto.RegisterType((*ListObjectVersionsResponse)(nil), "satellite.metainfo.ListObjectVersionsResponse")
+}
+
+func init() { proto.RegisterFile("objectversions.proto", fileDescriptor_86ee1db033af2ad1) }
+
+var fileDescriptor_86ee1db033af2ad1 = []byte{
+	// 594 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x54, 0xd1, 0x6e, 0x12, 0x5d,
+	0x10, 0xee, 0xd2, 0x42, 0xe9, 0x50, 0xf8, 0xf9, 0x4f, 0x1b, 0x5d, 0xb7, 0xd1, 0xe2, 0x26, 0x26,
+	0xc4, 0xa4, 0xdb, 0x04, 0xaf, 0xbc, 0xd0, 0x04, 0xca, 0x86, 0x18, 0x81, 0x36, 0x8b, 0xed, 0x85,
+	0x89, 0xd9, 0x1c, 0x60, 0x4a, 0x8f, 0xec, 0x72, 0xd6, 0x3d, 0x07, 0x22, 0x0f, 0x61, 0xe2, 0x6b,
+	0x78, 0xe7, 0x63, 0xf8, 0x14, 0xfa, 0x14, 0xde, 0x1b, 0xce, 0x2e, 0xb4, 0xd8, 0xc5, 0x50, 0xe3,
+	0xdd, 0xce, 0x9c, 0xf9, 0x66, 0xbe, 0x99, 0xf9, 0x66, 0x61, 0x9f, 0x77, 0xdf, 0x63, 0x4f, 0x4e,
+	0x30, 0x14, 0x8c, 0x8f, 0x84, 0x15, 0x84, 0x5c, 0x72, 0x42, 0x04, 0x95, 0xe8, 0x79, 0x4c, 0xa2,
+	0xe5, 0xa3, 0xa4, 0x6c, 0x74, 0xc9, 0x0d, 0x18, 0xf0, 0x01, 0x8f, 0xde, 0x8d, 0xc3, 0x01, 0xe7,
+	0x03, 0x0f, 0x8f, 0x95, 0xd5, 0x1d, 0x5f, 0x1e, 0x4b, 0xe6, 0xa3, 0x90, 0xd4, 0x0f, 0xa2, 0x00,
+	0xf3, 0x93, 0x06, 0x46, 0x07, 0x65, 0x6d, 0xdc, 0x1b, 0xa2, 0xbc, 0x88, 0x92, 0xb3, 0xd1, 0xc0,
+	0xc1, 0x0f, 0x63, 0x14, 0x92, 0xdc, 0x87, 0x6d, 0x1a, 0x30, 0x77, 0x88, 0x53, 0x5d, 0x2b, 0x69,
+	0xe5, 0x5d, 0x27, 0x43, 0x03, 0xf6, 0x1a, 0xa7, 0xe4, 0x1e, 0x64, 0xba, 0x0a, 0xa3, 0xa7, 0x22,
+	0x7f, 0x64, 0x91, 0x97, 0x00, 0x93, 0x45, 0x16, 0x7d, 0xb3, 0xa4, 0x95, 0x0b, 0x95, 0x47, 0xd6,
+	0x6d, 0x96, 0xd6, 0x8d, 0x5a, 0x37, 0x10, 0xe6, 0x43, 0x38, 0x48, 0xa4, 0x23, 0x02, 0x3e, 0x12,
+	0x68, 0xb6, 0xc0, 0x68, 0xfc, 0x3b, 0xb6, 0xe6, 0x3b, 0x38, 0x68, 0xac, 0xae, 0xf6, 0x5b, 0x33,
+	0xda, 0x9d, 0x9b, 0xf9, 0xaa, 0xc1, 0x83, 0x26, 0x13, 0xf2, 0x54, 0xad, 0x2e, 0x0e, 0x12, 0x7f,
+	0x3d, 0xdb, 0x27, 0x50, 0xc0, 0x51, 0x2f, 0x9c, 0x06, 0x12, 0xfb, 0x6e, 0x40, 0xe5, 0x95, 0x9a,
+	0xef, 0xae, 0x93, 0x5f, 0x78, 0xcf, 0xa8, 0xbc, 0x9a, 0x85, 0xc5, 0x1c, 0xdc, 0xde, 0x38, 0x14,
+	0x3c, 0xd4, 0xb7, 0x4a, 0x5a, 0x39, 0xed, 0xe4, 0x63, 0xef, 0x89, 0x72, 0x92, 0x7d, 0x48, 0x7b,
+	0xcc, 0x67, 0x52, 0x4f, 0xab, 0xd7, 0xc8, 0x30, 0xbf, 0xa4, 0x20, 0xbf, 0x44, 0x97, 0xe8, 0xb0,
+	0x1d, 0x03, 0x15, 0xcd, 0xb4, 0x33, 0x37, 0xc9, 0x01, 0xec, 0x30, 0xe1, 0x7a, 0x54, 0xa2, 0x88,
+	0xa8, 0x66, 0x9d, 0x2c, 0x13, 0x4d, 0x65, 0x93, 0x32, 0x14, 0x99, 0x70, 0xfb, 0xe8, 0xa1, 0x44,
+	0xd7, 0xa7, 0xe1, 0x10, 0x43, 0x45, 0x37, 0xeb, 0x14, 0x98, 0xa8, 0x2b, 0x77, 0x4b, 0x79, 0xc9,
+	0x09, 0x40, 0x2f, 0x44, 0x3a, 0x6b, 0x8a, 0x4a, 0xc5, 0x35, 0x57, 0x31, 0xac, 0x48, 0xb8, 0xd6,
+	0x5c, 0xb8, 0xd6, 0x9b, 0xb9, 0x70, 0x6b, 0xd9, 0x6f, 0xdf, 0x0f, 0x37, 0x3e, 0xff, 0x38, 0xd4,
+	0x9c, 0x9d, 0x18, 0x57, 0x95, 0xb3, 0x24, 0xf8, 0x31, 0x60, 0x21, 0x0a, 0x97, 0x46, 0x2d, 0xad,
+	0x9d, 0x24, 0xc6, 0x55, 0x25, 0x39, 0x02, 0x72, 0x3d, 0xe0, 0xd9, 0x72, 0xfb, 0x54, 0x52, 0x3d,
+	0xa3, 0x86, 0xfc, 0xff, 0xe2, 0xa5, 0x15, 0x3f, 0x98, 0x1c, 0x8c, 0xa4, 0xed, 0xc6, 0xe2, 0x79,
+	0x01, 0xd9, 0xf9, 0xb1, 0xea, 0x5a, 0x69, 0xb3, 0x9c, 0xab, 0x3c, 0x4e, 0x92, 0xce, 0x12, 0xda,
+	0x59, 0x40, 0x08, 0x81, 0x2d, 0x9f, 0x87, 0x18, 0xcf, 0x55, 0x7d, 0x3f, 0x7d, 0x0e, 0x70, 0xad,
+	0x34, 0xf2, 0x1f, 0xe4, 0xce, 0xdb, 0x17, 0xb6, 0xd3, 0x79, 0x75, 0xda, 0xb6, 0xeb, 0xc5, 0x0d,
+	0x92, 0x83, 0x6d, 0xbb, 0x5d, 0xad, 0x35, 0xed, 0x7a, 0x51, 0x23, 0x79, 0xd8, 0xe9, 0x9c, 0x77,
+	0xce, 0xec, 0x76, 0xdd, 0xae, 0x17, 0x53, 0x95, 0x9f, 0x29, 0x28, 0x2c, 0x13, 0x25, 0x13, 0xd8,
+	0x4b, 0x38, 0x35, 0x62, 0x25, 0xb1, 0x5c, 0xfd, 0x8b, 0x30, 0x8e, 0xd7, 0x8e, 0x8f, 0x07, 0x33,
+	0x81, 0xbd, 0xc6, 0xba, 0x75, 0x1b, 0x77, 0xac, 0xfb, 0xa7, 0x6b, 0x16, 0x40, 0x6e, 0xaf, 0x8b,
+	0x1c, 0x25, 0xa5, 0x59, 0x79, 0xb4, 0x86, 0xb5, 0x6e, 0x78, 0x54, 0xb4, 0xb6, 0xfb, 0x16, 0xe6,
+	0x61, 0x41, 0xb7, 0x9b, 0x51, 0x4a, 0x7c, 0xf6, 0x6b, 0x00, 0xff, 0x6d, 0xd0, 0xbe, 0xcd, 0x05,
+	0x00, 0x00,
+}
+
+type DRPCObjectVersionsClient interface {
+	DRPCConn() drpc.Conn
+
+	SetBucketVersioning(ctx context.Context, in *SetBucketVersioningRequest) (*SetBucketVersioningResponse, error)
+	GetBucketVersioning(ctx context.Context, in *GetBucketVersioningRequest) (*GetBucketVersioningResponse, error)
+	// ListObjectVersions lists the versions and delete markers of an object, newest first
+	ListObjectVersions(ctx context.Context, in *ListObjectVersionsRequest) (*ListObjectVersionsResponse, error)
+}
+
+type drpcObjectVersionsClient struct {
+	cc drpc.Conn
+}
+
+func NewDRPCObjectVersionsClient(cc drpc.Conn) DRPCObjectVersionsClient {
+	return &drpcObjectVersionsClient{cc}
+}
+
+func (c *drpcObjectVersionsClient) DRPCConn() drpc.Conn { return c.cc }
+
+func (c *drpcObjectVersionsClient) SetBucketVersioning(ctx context.Context, in *SetBucketVersioningRequest) (*SetBucketVersioningResponse, error) {
+	out := new(SetBucketVersioningResponse)
+	err := c.cc.Invoke(ctx, "/satellite.metainfo.ObjectVersions/SetBucketVersioning", in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcObjectVersionsClient) GetBucketVersioning(ctx context.Context, in *GetBucketVersioningRequest) (*GetBucketVersioningResponse, error) {
+	out := new(GetBucketVersioningResponse)
+	err := c.cc.Invoke(ctx, "/satellite.metainfo.ObjectVersions/GetBucketVersioning", in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcObjectVersionsClient) ListObjectVersions(ctx context.Context, in *ListObjectVersionsRequest) (*ListObjectVersionsResponse, error) {
+	out := new(ListObjectVersionsResponse)
+	err := c.cc.Invoke(ctx, "/satellite.metainfo.ObjectVersions/ListObjectVersions", in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+type DRPCObjectVersionsServer interface {
+	SetBucketVersioning(context.Context, *SetBucketVersioningRequest) (*SetBucketVersioningResponse, error)
+	GetBucketVersioning(context.Context, *GetBucketVersioningRequest) (*GetBucketVersioningResponse, error)
+	// ListObjectVersions lists the versions and delete markers of an object, newest first
+	ListObjectVersions(context.Context, *ListObjectVersionsRequest) (*ListObjectVersionsResponse, error)
+}
+
+type DRPCObjectVersionsDescription struct{}
+
+func (DRPCObjectVersionsDescription) NumMethods() int { return 3 }
+
+func (DRPCObjectVersionsDescription) Method(n int) (string, drpc.Handler, interface{}, bool) {
+	switch n {
+	case 0:
+		return "/satellite.metainfo.ObjectVersions/SetBucketVersioning",
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCObjectVersionsServer).
+					SetBucketVersioning(
+						ctx,
+						in1.(*SetBucketVersioningRequest),
+					)
+			}, DRPCObjectVersionsServer.SetBucketVersioning, true
+	case 1:
+		return "/satellite.metainfo.ObjectVersions/GetBucketVersioning",
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCObjectVersionsServer).
+					GetBucketVersioning(
+						ctx,
+						in1.(*GetBucketVersioningRequest),
+					)
+			}, DRPCObjectVersionsServer.GetBucketVersioning, true
+	case 2:
+		return "/satellite.metainfo.ObjectVersions/ListObjectVersions",
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCObjectVersionsServer).
+					ListObjectVersions(
+						ctx,
+						in1.(*ListObjectVersionsRequest),
+					)
+			}, DRPCObjectVersionsServer.ListObjectVersions, true
+	default:
+		return "", nil, nil, false
+	}
+}
+
+func DRPCRegisterObjectVersions(srv drpc.Server, impl DRPCObjectVersionsServer) {
+	srv.Register(impl, DRPCObjectVersionsDescription{})
+}
+
+type DRPCObjectVersions_SetBucketVersioningStream interface {
+	drpc.Stream
+	SendAndClose(*SetBucketVersioningResponse) error
+}
+
+type drpcObjectVersionsSetBucketVersioningStream struct {
+	drpc.Stream
+}
+
+func (x *drpcObjectVersionsSetBucketVersioningStream) SendAndClose(m *SetBucketVersioningResponse) error {
+	if err := x.MsgSend(m); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCObjectVersions_GetBucketVersioningStream interface {
+	drpc.Stream
+	SendAndClose(*GetBucketVersioningResponse) error
+}
+
+type drpcObjectVersionsGetBucketVersioningStream struct {
+	drpc.Stream
+}
+
+func (x *drpcObjectVersionsGetBucketVersioningStream) SendAndClose(m *GetBucketVersioningResponse) error {
+	if err := x.MsgSend(m); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCObjectVersions_ListObjectVersionsStream interface {
+	drpc.Stream
+	SendAndClose(*ListObjectVersionsResponse) error
+}
+
+type drpcObjectVersionsListObjectVersionsStream struct {
+	drpc.Stream
+}
+
+func (x *drpcObjectVersionsListObjectVersionsStream) SendAndClose(m *ListObjectVersionsResponse) error {
+	if err := x.MsgSend(m); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// ObjectVersionsClient is the client API for ObjectVersions service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ObjectVersionsClient interface {
+	SetBucketVersioning(ctx context.Context, in *SetBucketVersioningRequest, opts ...grpc.CallOption) (*SetBucketVersioningResponse, error)
+	GetBucketVersioning(ctx context.Context, in *GetBucketVersioningRequest, opts ...grpc.CallOption) (*GetBucketVersioningResponse, error)
+	// ListObjectVersions lists the versions and delete markers of an object, newest first
+	ListObjectVersions(ctx context.Context, in *ListObjectVersionsRequest, opts ...grpc.CallOption) (*ListObjectVersionsResponse, error)
+}
+
+type objectVersionsClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewObjectVersionsClient(cc *grpc.ClientConn) ObjectVersionsClient {
+	return &objectVersionsClient{cc}
+}
+
+func (c *objectVersionsClient) SetBucketVersioning(ctx context.Context, in *SetBucketVersioningRequest, opts ...grpc.CallOption) (*SetBucketVersioningResponse, error) {
+	out := new(SetBucketVersioningResponse)
+	err := c.cc.Invoke(ctx, "/satellite.metainfo.ObjectVersions/SetBucketVersioning", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *objectVersionsClient) GetBucketVersioning(ctx context.Context, in *GetBucketVersioningRequest, opts ...grpc.CallOption) (*GetBucketVersioningResponse, error) {
+	out := new(GetBucketVersioningResponse)
+	err := c.cc.Invoke(ctx, "/satellite.metainfo.ObjectVersions/GetBucketVersioning", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *objectVersionsClient) ListObjectVersions(ctx context.Context, in *ListObjectVersionsRequest, opts ...grpc.CallOption) (*ListObjectVersionsResponse, error) {
+	out := new(ListObjectVersionsResponse)
+	err := c.cc.Invoke(ctx, "/satellite.metainfo.ObjectVersions/ListObjectVersions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ObjectVersionsServer is the server API for ObjectVersions service.
+type ObjectVersionsServer interface {
+	SetBucketVersioning(context.Context, *SetBucketVersioningRequest) (*SetBucketVersioningResponse, error)
+	GetBucketVersioning(context.Context, *GetBucketVersioningRequest) (*GetBucketVersioningResponse, error)
+	// ListObjectVersions lists the versions and delete markers of an object, newest first
+	ListObjectVersions(context.Context, *ListObjectVersionsRequest) (*ListObjectVersionsResponse, error)
+}
+
+func RegisterObjectVersionsServer(s *grpc.Server, srv ObjectVersionsServer) {
+	s.RegisterService(&_ObjectVersions_serviceDesc, srv)
+}
+
+func _ObjectVersions_SetBucketVersioning_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetBucketVersioningRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ObjectVersionsServer).SetBucketVersioning(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/satellite.metainfo.ObjectVersions/SetBucketVersioning",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ObjectVersionsServer).SetBucketVersioning(ctx, req.(*SetBucketVersioningRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ObjectVersions_GetBucketVersioning_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBucketVersioningRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ObjectVersionsServer).GetBucketVersioning(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/satellite.metainfo.ObjectVersions/GetBucketVersioning",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ObjectVersionsServer).GetBucketVersioning(ctx, req.(*GetBucketVersioningRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ObjectVersions_ListObjectVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListObjectVersionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ObjectVersionsServer).ListObjectVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/satellite.metainfo.ObjectVersions/ListObjectVersions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ObjectVersionsServer).ListObjectVersions(ctx, req.(*ListObjectVersionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _ObjectVersions_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "satellite.metainfo.ObjectVersions",
+	HandlerType: (*ObjectVersionsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SetBucketVersioning",
+			Handler:    _ObjectVersions_SetBucketVersioning_Handler,
+		},
+		{
+			MethodName: "GetBucketVersioning",
+			Handler:    _ObjectVersions_GetBucketVersioning_Handler,
+		},
+		{
+			MethodName: "ListObjectVersions",
+			Handler:    _ObjectVersions_ListObjectVersions_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "objectversions.proto",
+}
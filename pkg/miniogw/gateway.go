@@ -5,6 +5,7 @@ package miniogw
 
 import (
 	"context"
+	"crypto/rand"
 	"encoding/hex"
 	"io"
 	"strings"
@@ -403,27 +404,115 @@ func (layer *gatewayLayer) CopyObject(ctx context.Context, srcBucket, srcObject,
 	}
 	defer func() { err = errs.Combine(err, bucket.Close()) }()
 
+	if srcObject == "" {
+		return minio.ObjectInfo{}, minio.ObjectNameInvalid{Bucket: srcBucket}
+	}
+
+	destination := bucket
+	if destBucket != srcBucket {
+		destination, err = layer.gateway.project.OpenBucket(ctx, destBucket, layer.gateway.access)
+		if err != nil {
+			return minio.ObjectInfo{}, convertError(err, destBucket, "")
+		}
+		defer func() { err = errs.Combine(err, destination.Close()) }()
+	}
+
 	object, err := bucket.OpenObject(ctx, srcObject)
 	if err != nil {
 		return minio.ObjectInfo{}, convertError(err, srcBucket, srcObject)
 	}
 	defer func() { err = errs.Combine(err, object.Close()) }()
 
+	// minio passes the metadata for the destination in srcInfo, it only differs
+	// from the metadata of the source for the REPLACE directive, which is
+	// also the only way to copy an object onto itself
+	metadata := make(map[string]string, len(srcInfo.UserDefined))
+	for key, value := range srcInfo.UserDefined {
+		metadata[key] = value
+	}
+	contentType, ok := metadata["content-type"]
+	if !ok {
+		contentType = object.Meta.ContentType
+	}
+	delete(metadata, "content-type")
+
+	replace := srcBucket == destBucket && srcObject == destObject ||
+		contentType != object.Meta.ContentType || !equalMetadata(metadata, object.Meta.Metadata)
+
+	// the copy is created at a temporary path first, so that an existing
+	// destination is only replaced when the copy succeeded
+	tmpPath, err := temporaryPath(destObject)
+	if err != nil {
+		return minio.ObjectInfo{}, err
+	}
+
+	if replace {
+		// the server-side copy keeps the encrypted metadata of the source
+		err = copyObjectData(ctx, object, destination, tmpPath, contentType, metadata)
+	} else {
+		err = bucket.CopyObject(ctx, srcObject, destination, tmpPath)
+	}
+	if err != nil {
+		return minio.ObjectInfo{}, convertError(err, destBucket, destObject)
+	}
+
+	// neither the server-side copy nor move overwrite objects, S3 does
+	err = destination.DeleteObject(ctx, destObject)
+	if err != nil && !storj.ErrObjectNotFound.Has(err) {
+		err = errs.Combine(err, destination.DeleteObject(ctx, tmpPath))
+		return minio.ObjectInfo{}, convertError(err, destBucket, destObject)
+	}
+
+	err = destination.MoveObject(ctx, tmpPath, destination, destObject)
+	if err != nil {
+		return minio.ObjectInfo{}, convertError(err, destBucket, destObject)
+	}
+
+	return layer.GetObjectInfo(ctx, destBucket, destObject)
+}
+
+// copyObjectData downloads the object and uploads it with the given metadata.
+func copyObjectData(ctx context.Context, object *uplink.Object, destination *uplink.Bucket, path storj.Path, contentType string, metadata map[string]string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
 	reader, err := object.DownloadRange(ctx, 0, -1)
 	if err != nil {
-		return minio.ObjectInfo{}, convertError(err, srcBucket, srcObject)
+		return err
 	}
 	defer func() { err = errs.Combine(err, reader.Close()) }()
 
 	opts := uplink.UploadOptions{
-		ContentType: object.Meta.ContentType,
-		Metadata:    object.Meta.Metadata,
+		ContentType: contentType,
+		Metadata:    metadata,
 		Expires:     object.Meta.Expires,
 	}
 	opts.Volatile.EncryptionParameters = object.Meta.Volatile.EncryptionParameters
 	opts.Volatile.RedundancyScheme = object.Meta.Volatile.RedundancyScheme
 
-	return layer.putObject(ctx, destBucket, destObject, reader, &opts)
+	return destination.UploadObject(ctx, path, reader, &opts)
+}
+
+// temporaryPath returns a unique path next to path, which is used until an object is complete.
+func temporaryPath(path storj.Path) (storj.Path, error) {
+	var suffix [8]byte
+	_, err := rand.Read(suffix[:])
+	if err != nil {
+		return "", Error.Wrap(err)
+	}
+	return path + ".copy-" + hex.EncodeToString(suffix[:]), nil
+}
+
+// equalMetadata returns whether both metadata maps contain the same values.
+func equalMetadata(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		if other, ok := b[key]; !ok || other != value {
+			return false
+		}
+	}
+	return true
 }
 
 func (layer *gatewayLayer) putObject(ctx context.Context, bucketName, objectPath string, reader io.Reader, opts *uplink.UploadOptions) (objInfo minio.ObjectInfo, err error) {
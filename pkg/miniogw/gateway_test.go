@@ -415,6 +415,29 @@ func TestCopyObject(t *testing.T) {
 			assert.Equal(t, info.ContentType, obj.ContentType)
 			assert.Equal(t, info.UserDefined, obj.Metadata)
 		}
+
+		// Check that a failed copy keeps the existing destination object
+		_, err = layer.CopyObject(ctx, TestBucket, "non-existing-file", DestBucket, DestFile, srcInfo)
+		assert.Equal(t, minio.ObjectNotFound{Bucket: TestBucket, Object: "non-existing-file"}, err)
+
+		_, err = m.GetObject(ctx, destBucketInfo, DestFile)
+		assert.NoError(t, err)
+
+		// Check that copying onto itself replaces the metadata
+		replaced := srcInfo
+		replaced.UserDefined = map[string]string{"content-type": "text/html", "key3": "value3"}
+		info, err = layer.CopyObject(ctx, DestBucket, DestFile, DestBucket, DestFile, replaced)
+		if assert.NoError(t, err) {
+			assert.Equal(t, obj.Size, info.Size)
+			assert.Equal(t, "text/html", info.ContentType)
+			assert.Equal(t, map[string]string{"key3": "value3"}, info.UserDefined)
+		}
+
+		// Check that no temporary objects are left behind
+		list, err := layer.ListObjects(ctx, DestBucket, "", "", "", 10)
+		if assert.NoError(t, err) {
+			assert.Len(t, list.Objects, 1)
+		}
 	})
 }
 
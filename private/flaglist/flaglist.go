@@ -0,0 +1,56 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package flaglist implements parsing the entries of flags which are
+// configured as comma separated lists.
+package flaglist
+
+import (
+	"strings"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/memory"
+)
+
+// Error is the default error class for flag lists.
+var Error = errs.Class("flag list")
+
+// Parse calls fn for every comma separated entry of the list, with the
+// surrounding whitespace removed. An empty or blank list has no entries.
+func Parse(list string, fn func(entry string) error) error {
+	if strings.TrimSpace(list) == "" {
+		return nil
+	}
+	for _, entry := range strings.Split(list, ",") {
+		if err := fn(strings.TrimSpace(entry)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Split splits the entry into the key before the first separator and the
+// value after it, with the surrounding whitespace removed. It returns false
+// when the entry doesn't contain the separator.
+func Split(entry, separator string) (key, value string, ok bool) {
+	index := strings.Index(entry, separator)
+	if index < 0 {
+		return "", "", false
+	}
+	return strings.TrimSpace(entry[:index]), strings.TrimSpace(entry[index+len(separator):]), true
+}
+
+// Size parses a memory size. Unlike memory.Size.Set, it fails for values
+// without a number, such as an empty string.
+func Size(s string) (memory.Size, error) {
+	s = strings.TrimSpace(s)
+	if !strings.ContainsAny(s, "0123456789") {
+		return 0, Error.New("missing size")
+	}
+	var size memory.Size
+	if err := size.Set(s); err != nil {
+		return 0, Error.Wrap(err)
+	}
+	return size, nil
+}
@@ -0,0 +1,53 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package flaglist_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/memory"
+	"storj.io/storj/private/flaglist"
+)
+
+func TestParse(t *testing.T) {
+	var entries []string
+	collect := func(entry string) error {
+		entries = append(entries, entry)
+		return nil
+	}
+
+	require.NoError(t, flaglist.Parse(" a, b=1 ,c", collect))
+	require.Equal(t, []string{"a", "b=1", "c"}, entries)
+
+	entries = nil
+	require.NoError(t, flaglist.Parse("  ", collect))
+	require.Empty(t, entries)
+
+	failure := errors.New("failure")
+	require.Equal(t, failure, flaglist.Parse("a,b", func(string) error { return failure }))
+}
+
+func TestSplit(t *testing.T) {
+	key, value, ok := flaglist.Split("key = a=b", "=")
+	require.True(t, ok)
+	require.Equal(t, "key", key)
+	require.Equal(t, "a=b", value)
+
+	_, _, ok = flaglist.Split("key", "=")
+	require.False(t, ok)
+}
+
+func TestSize(t *testing.T) {
+	size, err := flaglist.Size(" 2TB ")
+	require.NoError(t, err)
+	require.Equal(t, 2*memory.TB, size)
+
+	for _, invalid := range []string{"", "TB", "2XB"} {
+		_, err := flaglist.Size(invalid)
+		require.Error(t, err, invalid)
+	}
+}
@@ -98,6 +98,22 @@ func (bad *BadBlobs) Trash(ctx context.Context, ref storage.BlobRef) error {
 	return bad.blobs.Trash(ctx, ref)
 }
 
+// Quarantine moves the blob with the namespace and key to the quarantine.
+func (bad *BadBlobs) Quarantine(ctx context.Context, ref storage.BlobRef) error {
+	if bad.err != nil {
+		return bad.err
+	}
+	return bad.blobs.Quarantine(ctx, ref)
+}
+
+// EmptyQuarantine empties the quarantine.
+func (bad *BadBlobs) EmptyQuarantine(ctx context.Context, namespace []byte, quarantinedBefore time.Time) (int64, [][]byte, error) {
+	if bad.err != nil {
+		return 0, nil, bad.err
+	}
+	return bad.blobs.EmptyQuarantine(ctx, namespace, quarantinedBefore)
+}
+
 // RestoreTrash restores all files in the trash.
 func (bad *BadBlobs) RestoreTrash(ctx context.Context, namespace []byte) ([][]byte, error) {
 	if bad.err != nil {
@@ -89,6 +89,18 @@ func (slow *SlowBlobs) Trash(ctx context.Context, ref storage.BlobRef) error {
 	return slow.blobs.Trash(ctx, ref)
 }
 
+// Quarantine moves the blob with the namespace and key to the quarantine.
+func (slow *SlowBlobs) Quarantine(ctx context.Context, ref storage.BlobRef) error {
+	slow.sleep()
+	return slow.blobs.Quarantine(ctx, ref)
+}
+
+// EmptyQuarantine empties the quarantine.
+func (slow *SlowBlobs) EmptyQuarantine(ctx context.Context, namespace []byte, quarantinedBefore time.Time) (int64, [][]byte, error) {
+	slow.sleep()
+	return slow.blobs.EmptyQuarantine(ctx, namespace, quarantinedBefore)
+}
+
 // RestoreTrash restores all files in the trash
 func (slow *SlowBlobs) RestoreTrash(ctx context.Context, namespace []byte) ([][]byte, error) {
 	slow.sleep()
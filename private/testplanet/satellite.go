@@ -33,7 +33,9 @@ import (
 	"storj.io/storj/satellite/accounting/reportedrollup"
 	"storj.io/storj/satellite/accounting/rollup"
 	"storj.io/storj/satellite/accounting/tally"
+	"storj.io/storj/satellite/admin"
 	"storj.io/storj/satellite/audit"
+	"storj.io/storj/satellite/bucketlifecycle"
 	"storj.io/storj/satellite/console"
 	"storj.io/storj/satellite/console/consoleweb"
 	"storj.io/storj/satellite/contact"
@@ -102,9 +104,10 @@ type SatelliteSystem struct {
 	}
 
 	Repair struct {
-		Checker   *checker.Checker
-		Repairer  *repairer.Service
-		Inspector *irreparable.Inspector
+		Checker        *checker.Checker
+		Repairer       *repairer.Service
+		Inspector      *irreparable.Inspector
+		QueueInspector *repairer.Inspector
 	}
 	Audit struct {
 		Queue    *audit.Queue
@@ -115,7 +118,12 @@ type SatelliteSystem struct {
 	}
 
 	GarbageCollection struct {
-		Service *gc.Service
+		Service   *gc.Service
+		Inspector *gc.Inspector
+	}
+
+	BucketLifecycle struct {
+		Chore *bucketlifecycle.Chore
 	}
 
 	DBCleanup struct {
@@ -147,6 +155,11 @@ type SatelliteSystem struct {
 		Endpoint *consoleweb.Server
 	}
 
+	Admin struct {
+		Listener net.Listener
+		Endpoint *admin.Server
+	}
+
 	Marketing struct {
 		Listener net.Listener
 		Endpoint *marketingweb.Server
@@ -297,6 +310,12 @@ func (planet *Planet) newSatellites(count int) ([]*SatelliteSystem, error) {
 					AuditReputationWeight:       1,
 					AuditReputationDQ:           0.6,
 				},
+				NodeSelectionCache: overlay.NodeSelectionCacheConfig{
+					// tests expect node selection to reflect the database immediately
+					Disabled:        true,
+					RefreshInterval: time.Minute,
+					Staleness:       3 * time.Minute,
+				},
 				UpdateStatsBatchSize: 100,
 			},
 			Metainfo: metainfo.Config{
@@ -364,6 +383,13 @@ func (planet *Planet) newSatellites(count int) ([]*SatelliteSystem, error) {
 				InitialPieces:     10,
 				FalsePositiveRate: 0.1,
 				ConcurrentSends:   1,
+				RetryInterval:     defaultInterval,
+				FilterExpiration:  time.Hour,
+			},
+			BucketLifecycle: bucketlifecycle.Config{
+				Enabled:     true,
+				Interval:    defaultInterval,
+				MaxRemovals: 1000,
 			},
 			DBCleanup: dbcleanup.Config{
 				SerialsInterval: defaultInterval,
@@ -393,6 +419,13 @@ func (planet *Planet) newSatellites(count int) ([]*SatelliteSystem, error) {
 				StaticDir:       filepath.Join(developmentRoot, "web/satellite"),
 				PasswordCost:    console.TestPasswordCost,
 				AuthTokenSecret: "my-suppa-secret-key",
+				MFA: console.MFAConfig{
+					MaxAttempts:  5,
+					LockDuration: 15 * time.Minute,
+				},
+			},
+			Admin: admin.Config{
+				Address: "127.0.0.1:0",
 			},
 			Marketing: marketingweb.Config{
 				Address:   "127.0.0.1:0",
@@ -517,6 +550,7 @@ func createNewSystem(log *zap.Logger, peer *satellite.Core, api *satellite.API,
 	system.Repair.Checker = peer.Repair.Checker
 	system.Repair.Repairer = repairerPeer.Repairer
 	system.Repair.Inspector = api.Repair.Inspector
+	system.Repair.QueueInspector = api.Repair.QueueInspector
 
 	system.Audit.Queue = peer.Audit.Queue
 	system.Audit.Worker = peer.Audit.Worker
@@ -525,6 +559,9 @@ func createNewSystem(log *zap.Logger, peer *satellite.Core, api *satellite.API,
 	system.Audit.Reporter = peer.Audit.Reporter
 
 	system.GarbageCollection.Service = peer.GarbageCollection.Service
+	system.GarbageCollection.Inspector = api.GarbageCollection.Inspector
+
+	system.BucketLifecycle.Chore = peer.BucketLifecycle.Chore
 
 	system.DBCleanup.Chore = peer.DBCleanup.Chore
 
@@ -533,6 +570,9 @@ func createNewSystem(log *zap.Logger, peer *satellite.Core, api *satellite.API,
 	system.Accounting.ProjectUsage = peer.Accounting.ProjectUsage
 	system.Accounting.ReportedRollup = peer.Accounting.ReportedRollupChore
 
+	system.Admin.Listener = api.Admin.Listener
+	system.Admin.Endpoint = api.Admin.Endpoint
+
 	system.Marketing.Listener = api.Marketing.Listener
 	system.Marketing.Endpoint = api.Marketing.Endpoint
 
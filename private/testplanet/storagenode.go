@@ -33,6 +33,8 @@ import (
 	"storj.io/storj/storagenode/piecestore"
 	"storj.io/storj/storagenode/preflight"
 	"storj.io/storj/storagenode/retain"
+	"storj.io/storj/storagenode/scrubber"
+	"storj.io/storj/storagenode/shaping"
 	"storj.io/storj/storagenode/storagenodedb"
 	"storj.io/storj/storagenode/trust"
 )
@@ -112,6 +114,7 @@ func (planet *Planet) newStorageNodes(count int, whitelistedSatellites storj.Nod
 			Console: consoleserver.Config{
 				Address:   "127.0.0.1:0",
 				StaticDir: filepath.Join(developmentRoot, "web/storagenode/"),
+				APIToken:  "testplanet",
 			},
 			Storage2: piecestore.Config{
 				CacheSyncInterval:      defaultInterval,
@@ -135,12 +138,23 @@ func (planet *Planet) newStorageNodes(count int, whitelistedSatellites storj.Nod
 					CachePath:       filepath.Join(storageDir, "trust-cache.json"),
 					RefreshInterval: defaultInterval,
 				},
+				Shaping: shaping.Config{
+					ProtectedShare: 0.2,
+					Burst:          256 * memory.KiB,
+					Interval:       defaultInterval,
+				},
 			},
 			Retain: retain.Config{
 				MaxTimeSkew: 10 * time.Second,
 				Status:      retain.Enabled,
 				Concurrency: 5,
 			},
+			Scrubber: scrubber.Config{
+				// tests which corrupt pieces expect them to stay in place
+				Enabled:             false,
+				Interval:            defaultInterval,
+				QuarantineRetention: 30 * 24 * time.Hour,
+			},
 			Version: planet.NewVersionConfig(),
 			Bandwidth: bandwidth.Config{
 				Interval: defaultInterval,
@@ -175,6 +189,10 @@ func (planet *Planet) newStorageNodes(count int, whitelistedSatellites storj.Nod
 			Info:    filepath.Join(config.Storage.Path, "piecestore.db"),
 			Info2:   filepath.Join(config.Storage.Path, "info.db"),
 			Pieces:  config.Storage.Path,
+
+			PiecesAllocated:  config.Storage.AllocatedDiskSpace.Int64(),
+			AdditionalPieces: config.Storage.AdditionalDisks,
+			Packed:           config.Storage.Packed,
 		}
 
 		var db storagenode.DB
@@ -0,0 +1,152 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package version
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"sort"
+	"strings"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/private/flaglist"
+)
+
+// ErrVerification is the error class for binaries failing verification.
+var ErrVerification = errs.Class("binary verification error")
+
+// Binary contains the hex-encoded SHA-256 digest of a downloadable binary
+// archive and the hex-encoded ed25519 signature of the version, the platform
+// and the digest of the archive.
+type Binary struct {
+	SHA256    string `json:"sha256"`
+	Signature string `json:"signature"`
+}
+
+// signedMessage returns the message which is signed for the archive of the
+// version and the platform. Signing the version and the platform as well
+// prevents serving a correctly signed archive of another release or another
+// platform.
+func signedMessage(version SemVer, platform string, digest []byte) []byte {
+	return []byte(strings.Join([]string{
+		"storj binary",
+		version.String(),
+		platform,
+		hex.EncodeToString(digest),
+	}, "\n"))
+}
+
+// SignBinary returns the binary of the archive digest of the version and the
+// platform signed with the private key.
+func SignBinary(privateKey ed25519.PrivateKey, version SemVer, platform string, digest []byte) Binary {
+	return Binary{
+		SHA256:    hex.EncodeToString(digest),
+		Signature: hex.EncodeToString(ed25519.Sign(privateKey, signedMessage(version, platform, digest))),
+	}
+}
+
+// Verify checks that the digest of the downloaded archive matches the
+// published one and that the published digest is signed by the public key for
+// the version and the platform.
+func (binary Binary) Verify(publicKey ed25519.PublicKey, version SemVer, platform string, digest []byte) error {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return ErrVerification.New("invalid public key length: %d", len(publicKey))
+	}
+
+	published, err := hex.DecodeString(binary.SHA256)
+	if err != nil || len(published) != sha256.Size {
+		return ErrVerification.New("invalid published digest %q", binary.SHA256)
+	}
+	signature, err := hex.DecodeString(binary.Signature)
+	if err != nil || len(signature) != ed25519.SignatureSize {
+		return ErrVerification.New("invalid published signature %q", binary.Signature)
+	}
+
+	if subtle.ConstantTimeCompare(published, digest) != 1 {
+		return ErrVerification.New("digest mismatch: published %s, downloaded %x", binary.SHA256, digest)
+	}
+	if !ed25519.Verify(publicKey, signedMessage(version, platform, published), signature) {
+		return ErrVerification.New("invalid signature of %s %s digest %s", version.String(), platform, binary.SHA256)
+	}
+	return nil
+}
+
+// validate checks the encoding of the digest and the signature.
+func (binary Binary) validate() error {
+	digest, err := hex.DecodeString(binary.SHA256)
+	if err != nil || len(digest) != sha256.Size {
+		return VerError.New("invalid SHA-256 digest %q", binary.SHA256)
+	}
+	signature, err := hex.DecodeString(binary.Signature)
+	if err != nil || len(signature) != ed25519.SignatureSize {
+		return VerError.New("invalid ed25519 signature %q", binary.Signature)
+	}
+	return nil
+}
+
+// Platform returns the key of the binaries of the operating system and the
+// architecture, e.g. linux_amd64.
+func Platform(goos, goarch string) string {
+	return goos + "_" + goarch
+}
+
+// Binaries are the binaries of a version per platform. They're configured as a
+// comma separated list of PLATFORM=SHA256:SIGNATURE entries.
+type Binaries map[string]Binary
+
+// String implements pflag.Value.
+func (binaries Binaries) String() string {
+	platforms := make([]string, 0, len(binaries))
+	for platform := range binaries {
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+
+	values := make([]string, 0, len(binaries))
+	for _, platform := range platforms {
+		binary := binaries[platform]
+		values = append(values, platform+"="+binary.SHA256+":"+binary.Signature)
+	}
+	return strings.Join(values, ",")
+}
+
+// Set implements pflag.Value.
+func (binaries *Binaries) Set(s string) error {
+	*binaries = nil
+
+	parsed := Binaries{}
+	err := flaglist.Parse(s, func(value string) error {
+		platform, hashes, ok := flaglist.Split(value, "=")
+		if !ok {
+			return VerError.New("invalid binaries %q, expected PLATFORM=SHA256:SIGNATURE", value)
+		}
+		if _, ok := parsed[platform]; ok {
+			return VerError.New("invalid binaries %q, platform has several binaries", value)
+		}
+
+		digest, signature, ok := flaglist.Split(hashes, ":")
+		if !ok || strings.Contains(signature, ":") {
+			return VerError.New("invalid binaries %q, expected PLATFORM=SHA256:SIGNATURE", value)
+		}
+
+		binary := Binary{SHA256: digest, Signature: signature}
+		if err := binary.validate(); err != nil {
+			return err
+		}
+		parsed[platform] = binary
+		return nil
+	})
+	if err != nil || len(parsed) == 0 {
+		return err
+	}
+
+	*binaries = parsed
+	return nil
+}
+
+// Type implements pflag.Value.
+func (*Binaries) Type() string { return "version.Binaries" }
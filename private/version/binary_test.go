@@ -0,0 +1,84 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package version_test
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/testrand"
+	"storj.io/storj/private/version"
+)
+
+func TestBinary_Verify(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	otherPublicKey, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	release, err := version.NewSemVer("v1.2.3")
+	require.NoError(t, err)
+	nextRelease, err := version.NewSemVer("v1.2.4")
+	require.NoError(t, err)
+	platform := version.Platform("linux", "amd64")
+
+	digest := sha256.Sum256(testrand.BytesInt(1024))
+	binary := version.SignBinary(privateKey, release, platform, digest[:])
+
+	require.NoError(t, binary.Verify(publicKey, release, platform, digest[:]))
+
+	otherDigest := sha256.Sum256(testrand.BytesInt(1024))
+	err = binary.Verify(publicKey, release, platform, otherDigest[:])
+	require.True(t, version.ErrVerification.Has(err))
+
+	err = binary.Verify(otherPublicKey, release, platform, digest[:])
+	require.True(t, version.ErrVerification.Has(err))
+
+	forged := version.SignBinary(privateKey, release, platform, otherDigest[:])
+	forged.SHA256 = binary.SHA256
+	err = forged.Verify(publicKey, release, platform, digest[:])
+	require.True(t, version.ErrVerification.Has(err))
+
+	// the archive of another release or platform isn't accepted
+	err = binary.Verify(publicKey, nextRelease, platform, digest[:])
+	require.True(t, version.ErrVerification.Has(err))
+
+	err = binary.Verify(publicKey, release, version.Platform("windows", "amd64"), digest[:])
+	require.True(t, version.ErrVerification.Has(err))
+}
+
+func TestBinaries(t *testing.T) {
+	_, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	linuxDigest := sha256.Sum256([]byte("linux"))
+	windowsDigest := sha256.Sum256([]byte("windows"))
+	release, err := version.NewSemVer("v1.2.3")
+	require.NoError(t, err)
+	expected := version.Binaries{
+		"linux_amd64":   version.SignBinary(privateKey, release, "linux_amd64", linuxDigest[:]),
+		"windows_amd64": version.SignBinary(privateKey, release, "windows_amd64", windowsDigest[:]),
+	}
+
+	var binaries version.Binaries
+	require.NoError(t, binaries.Set(expected.String()))
+	require.Equal(t, expected, binaries)
+
+	require.NoError(t, binaries.Set(""))
+	require.Empty(t, binaries)
+
+	linux := expected["linux_amd64"]
+	for _, invalid := range []string{
+		"linux_amd64",
+		"linux_amd64=" + linux.SHA256,
+		"linux_amd64=" + linux.SHA256[2:] + ":" + linux.Signature,
+		"linux_amd64=" + linux.SHA256 + ":" + linux.Signature[2:],
+		"linux_amd64=" + linux.SHA256 + ":" + linux.Signature + ",linux_amd64=" + linux.SHA256 + ":" + linux.Signature,
+	} {
+		require.Error(t, binaries.Set(invalid), invalid)
+	}
+}
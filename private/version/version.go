@@ -99,6 +99,9 @@ type Process struct {
 type Version struct {
 	Version string `json:"version"`
 	URL     string `json:"url"`
+	// Binaries are the digests and signatures of the downloadable binaries
+	// per platform.
+	Binaries Binaries `json:"binaries,omitempty"`
 }
 
 // Rollout represents the state of a version rollout.
@@ -168,7 +168,8 @@ func (service *Service) Tally(ctx context.Context) (err error) {
 	return errs.Combine(errAtRest, errBucketInfo)
 }
 
-var _ metainfo.Observer = (*Observer)(nil)
+var _ metainfo.ForkingObserver = (*Observer)(nil)
+var _ metainfo.CheckpointObserver = (*Observer)(nil)
 
 // Observer observes metainfo and adds up tallies for nodes and buckets
 type Observer struct {
@@ -243,6 +244,82 @@ func (observer *Observer) RemoteSegment(ctx context.Context, path metainfo.Scope
 	return nil
 }
 
+// Fork returns an empty observer for a range of the metainfo loop.
+func (observer *Observer) Fork(ctx context.Context) (_ metainfo.Observer, err error) {
+	return NewObserver(observer.Log), nil
+}
+
+// Join adds the tallies of a forked observer to the observer.
+func (observer *Observer) Join(ctx context.Context, forked metainfo.Observer) (err error) {
+	other, ok := forked.(*Observer)
+	if !ok {
+		return Error.New("unexpected observer type %T", forked)
+	}
+
+	for nodeID, size := range other.Node {
+		observer.Node[nodeID] += size
+	}
+	for bucketID, tally := range other.Bucket {
+		bucket, exists := observer.Bucket[bucketID]
+		if !exists {
+			observer.Bucket[bucketID] = tally
+			continue
+		}
+		bucket.Combine(tally)
+		bucket.MetadataSize += tally.MetadataSize
+	}
+	return nil
+}
+
+// CheckpointName identifies the state of the tally observer.
+func (observer *Observer) CheckpointName() string {
+	return "tally"
+}
+
+// observerState is the checkpointed state of the tally observer.
+type observerState struct {
+	Node   map[storj.NodeID]float64
+	Bucket map[string]*accounting.BucketTally
+}
+
+// Checkpoint returns a copy of the tallies collected so far.
+func (observer *Observer) Checkpoint(ctx context.Context) (_ interface{}, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	state := observerState{
+		Node:   make(map[storj.NodeID]float64, len(observer.Node)),
+		Bucket: make(map[string]*accounting.BucketTally, len(observer.Bucket)),
+	}
+	for nodeID, size := range observer.Node {
+		state.Node[nodeID] = size
+	}
+	for bucketID, tally := range observer.Bucket {
+		bucket := *tally
+		state.Bucket[bucketID] = &bucket
+	}
+	return state, nil
+}
+
+// Restore replaces the tallies with the ones of a checkpoint.
+func (observer *Observer) Restore(ctx context.Context, decode func(state interface{}) error) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var restored observerState
+	if err := decode(&restored); err != nil {
+		return Error.Wrap(err)
+	}
+
+	observer.Node = restored.Node
+	if observer.Node == nil {
+		observer.Node = make(map[storj.NodeID]float64)
+	}
+	observer.Bucket = restored.Bucket
+	if observer.Bucket == nil {
+		observer.Bucket = make(map[string]*accounting.BucketTally)
+	}
+	return nil
+}
+
 func projectTotalsFromBuckets(buckets map[string]*accounting.BucketTally) map[uuid.UUID]int64 {
 	projectTallyTotals := make(map[uuid.UUID]int64)
 	for _, bucket := range buckets {
@@ -4,15 +4,19 @@
 package tally_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
 
+	"github.com/gogo/protobuf/proto"
 	"github.com/skyrings/skyring-common/tools/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
 
 	"storj.io/common/encryption"
+	"storj.io/common/errs2"
 	"storj.io/common/memory"
 	"storj.io/common/pb"
 	"storj.io/common/storj"
@@ -22,6 +26,9 @@ import (
 	"storj.io/storj/private/teststorj"
 	"storj.io/storj/satellite/accounting"
 	"storj.io/storj/satellite/accounting/tally"
+	"storj.io/storj/satellite/metainfo"
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/teststore"
 	"storj.io/storj/storagenode"
 )
 
@@ -325,6 +332,108 @@ func makePointer(storageNodes []*storagenode.Peer, rs storj.RedundancyScheme, se
 	}
 }
 
+func TestObserverParallel(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	db := teststore.New()
+	nodes := make([]storj.NodeID, 6)
+	for i := range nodes {
+		nodes[i] = testrand.NodeID()
+	}
+
+	for i := 0; i < 32; i++ {
+		projectID := testrand.UUID().String()
+		for k := 0; k < 3; k++ {
+			bucketName := fmt.Sprintf("bucket%d", k%2)
+			objectPath := fmt.Sprintf("object%d", k)
+
+			remote := &pb.Pointer{
+				Type: pb.Pointer_REMOTE,
+				Remote: &pb.RemoteSegment{
+					Redundancy: &pb.RedundancyScheme{MinReq: 2, Total: 4, RepairThreshold: 3, SuccessThreshold: 4},
+				},
+				SegmentSize: int64(1024 * (i + k + 1)),
+				Metadata:    []byte("metadata"),
+			}
+			for n := 0; n < 4; n++ {
+				remote.Remote.RemotePieces = append(remote.Remote.RemotePieces, &pb.RemotePiece{
+					PieceNum: int32(n),
+					NodeId:   nodes[(i+k+n)%len(nodes)],
+				})
+			}
+			inline := &pb.Pointer{Type: pb.Pointer_INLINE, InlineSegment: testrand.Bytes(memory.Size(10 + k))}
+
+			putPointer(ctx, t, db, storj.JoinPaths(projectID, "s0", bucketName, objectPath), remote)
+			putPointer(ctx, t, db, storj.JoinPaths(projectID, "l", bucketName, objectPath), inline)
+		}
+	}
+
+	serial := tally.NewObserver(zaptest.NewLogger(t))
+	require.NoError(t, metainfo.IterateDatabase(ctx, 0, db, serial))
+	require.Len(t, serial.Node, len(nodes))
+	require.Len(t, serial.Bucket, 64)
+
+	metaLoop := metainfo.NewLoop(metainfo.LoopConfig{Workers: 8}, db, nil)
+	loopCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ctx.Go(func() error {
+		return errs2.IgnoreCanceled(metaLoop.Run(loopCtx))
+	})
+
+	parallel := tally.NewObserver(zaptest.NewLogger(t))
+	require.NoError(t, metaLoop.Join(ctx, parallel))
+
+	assert.Equal(t, serial.Node, parallel.Node)
+	assert.Equal(t, serial.Bucket, parallel.Bucket)
+}
+
+func TestObserverCheckpoint(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	db := teststore.New()
+	nodeID := testrand.NodeID()
+	for i := 0; i < 4; i++ {
+		projectID := testrand.UUID().String()
+		remote := &pb.Pointer{
+			Type: pb.Pointer_REMOTE,
+			Remote: &pb.RemoteSegment{
+				Redundancy:   &pb.RedundancyScheme{MinReq: 1, Total: 1, RepairThreshold: 1, SuccessThreshold: 1},
+				RemotePieces: []*pb.RemotePiece{{NodeId: nodeID}},
+			},
+			SegmentSize: 1024,
+		}
+		putPointer(ctx, t, db, storj.JoinPaths(projectID, "l", "bucket", "object"), remote)
+	}
+
+	observer := tally.NewObserver(zaptest.NewLogger(t))
+	require.NoError(t, metainfo.IterateDatabase(ctx, 0, db, observer))
+
+	state, err := observer.Checkpoint(ctx)
+	require.NoError(t, err)
+	data, err := metainfo.EncodeCheckpoint(state)
+	require.NoError(t, err)
+
+	// the checkpoint doesn't change with the observer
+	observer.Node[nodeID]++
+
+	restored := tally.NewObserver(zaptest.NewLogger(t))
+	require.NoError(t, restored.Restore(ctx, metainfo.DecodeCheckpoint(data)))
+	observer.Node[nodeID]--
+	assert.Equal(t, observer.Node, restored.Node)
+	assert.Equal(t, observer.Bucket, restored.Bucket)
+	assert.Equal(t, 4096.0, restored.Node[nodeID])
+
+	require.Error(t, restored.Restore(ctx, metainfo.DecodeCheckpoint([]byte("invalid"))))
+}
+
+func putPointer(ctx context.Context, t *testing.T, db storage.KeyValueStore, path string, pointer *pb.Pointer) {
+	data, err := proto.Marshal(pointer)
+	require.NoError(t, err)
+	require.NoError(t, db.Put(ctx, storage.Key(path), data))
+}
+
 func correctRedundencyScheme(shareCount int, uplinkRS storj.RedundancyScheme) bool {
 	// The shareCount should be a value between RequiredShares and TotalShares where
 	// RequiredShares is the min number of shares required to recover a segment and
@@ -0,0 +1,78 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package admin
+
+import (
+	"context"
+	"time"
+
+	"github.com/skyrings/skyring-common/tools/uuid"
+)
+
+// AuditRecords stores a record of every change made through the admin API.
+// Records can only be added and are never changed or removed, so every change
+// has an attempted record, which is stored before the change is made, and a
+// succeeded or failed record afterwards.
+//
+// architecture: Database
+type AuditRecords interface {
+	// Insert adds a new record.
+	Insert(ctx context.Context, record AuditRecord) error
+	// List returns at most limit records created before the provided time, newest first.
+	List(ctx context.Context, before time.Time, limit int) ([]AuditRecord, error)
+}
+
+// AuditRecord describes a change made through the admin API.
+type AuditRecord struct {
+	ID uuid.UUID `json:"id"`
+	// Operator identifies the member of the staff who made the change. It's
+	// provided by the client and isn't authenticated, see Server.
+	Operator string `json:"operator"`
+	Action   Action `json:"action"`
+	// Target is the changed entity, e.g. "user/<id>".
+	Target string `json:"target"`
+	// Details contains the parameters of the change as JSON. The details of
+	// an outcome contain the id of the attempted record and the error of a
+	// failed change.
+	Details   string    `json:"details"`
+	Outcome   Outcome   `json:"outcome"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Outcome is the state of a change made through the admin API.
+type Outcome string
+
+const (
+	// OutcomeAttempted is recorded before a change is made.
+	OutcomeAttempted Outcome = "attempted"
+	// OutcomeSucceeded is recorded after a change has been made.
+	OutcomeSucceeded Outcome = "succeeded"
+	// OutcomeFailed is recorded after a change has failed.
+	OutcomeFailed Outcome = "failed"
+)
+
+// Action is a kind of change made through the admin API.
+type Action string
+
+const (
+	// ActionFreezeUser blocks the account of a user.
+	ActionFreezeUser Action = "freeze_user"
+	// ActionUnfreezeUser activates the account of a frozen user.
+	ActionUnfreezeUser Action = "unfreeze_user"
+	// ActionResetUserMFA disables the two-factor authentication of a locked-out user.
+	ActionResetUserMFA Action = "reset_user_mfa"
+	// ActionUpdateProjectLimits changes the storage and bandwidth limit of a project.
+	ActionUpdateProjectLimits Action = "update_project_limits"
+	// ActionRevokeAPIKey deletes an API key.
+	ActionRevokeAPIKey Action = "revoke_api_key"
+)
+
+// UserTarget returns the audit target for a user.
+func UserTarget(id uuid.UUID) string { return "user/" + id.String() }
+
+// ProjectTarget returns the audit target for a project.
+func ProjectTarget(id uuid.UUID) string { return "project/" + id.String() }
+
+// APIKeyTarget returns the audit target for an API key.
+func APIKeyTarget(id uuid.UUID) string { return "apikey/" + id.String() }
@@ -0,0 +1,66 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package admin_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/satellite"
+	"storj.io/storj/satellite/admin"
+	"storj.io/storj/satellite/satellitedb/satellitedbtest"
+)
+
+func TestAuditRecords(t *testing.T) {
+	satellitedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db satellite.DB) {
+		records := db.AdminAuditRecords()
+
+		list, err := records.List(ctx, time.Now(), 10)
+		require.NoError(t, err)
+		require.Empty(t, list)
+
+		start := time.Now().Add(-time.Hour).UTC()
+
+		var inserted []admin.AuditRecord
+		for i, action := range []admin.Action{admin.ActionFreezeUser, admin.ActionUnfreezeUser, admin.ActionRevokeAPIKey} {
+			record := admin.AuditRecord{
+				ID:        testrand.UUID(),
+				Operator:  "support",
+				Action:    action,
+				Target:    admin.UserTarget(testrand.UUID()),
+				Details:   `{"reason":"test"}`,
+				Outcome:   admin.OutcomeAttempted,
+				CreatedAt: start.Add(time.Duration(i) * time.Minute),
+			}
+			require.NoError(t, records.Insert(ctx, record))
+			inserted = append(inserted, record)
+		}
+
+		// records are immutable, so an existing id can't be reused
+		require.Error(t, records.Insert(ctx, inserted[0]))
+
+		list, err = records.List(ctx, time.Now(), 10)
+		require.NoError(t, err)
+		require.Len(t, list, 3)
+		for i, record := range list {
+			expected := inserted[len(inserted)-1-i]
+			require.Equal(t, expected.ID, record.ID)
+			require.Equal(t, expected.Operator, record.Operator)
+			require.Equal(t, expected.Action, record.Action)
+			require.Equal(t, expected.Target, record.Target)
+			require.Equal(t, expected.Details, record.Details)
+			require.Equal(t, expected.Outcome, record.Outcome)
+			require.WithinDuration(t, expected.CreatedAt, record.CreatedAt, time.Second)
+		}
+
+		list, err = records.List(ctx, inserted[2].CreatedAt, 1)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		require.Equal(t, inserted[1].ID, list[0].ID)
+	})
+}
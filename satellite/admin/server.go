@@ -0,0 +1,681 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/skyrings/skyring-common/tools/uuid"
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"storj.io/common/memory"
+	"storj.io/storj/satellite/accounting"
+	"storj.io/storj/satellite/console"
+)
+
+var (
+	// Error is satellite admin error type
+	Error = errs.Class("satellite admin error")
+
+	mon = monkit.Package()
+)
+
+const (
+	// operatorHeader identifies the member of the staff who makes a change.
+	// It isn't authenticated by the server, see Server.
+	operatorHeader = "X-Admin-Operator"
+
+	// maxAuditRecords is the maximum number of audit records returned at once.
+	maxAuditRecords = 100
+)
+
+// Config contains configuration for the satellite administration server.
+type Config struct {
+	Address   string `help:"server address of the admin API, the server is disabled when empty" default:""`
+	AuthToken string `help:"auth token needed for access to the admin API" default:""`
+}
+
+// Server provides the HTTP API, which is used by the satellite staff to
+// inspect and change users, projects and API keys.
+//
+// Every change is preceded by an audit record, so that no change can be made
+// without being recorded, and followed by a record of its outcome.
+//
+// Every member of the staff shares the same auth token, so the operator of a
+// change is identified by the X-Admin-Operator header, which is only as
+// trustworthy as the clients holding the token. The server must only be
+// reachable through a gateway, which authenticates the staff and sets the
+// header.
+//
+// architecture: Endpoint
+type Server struct {
+	log    *zap.Logger
+	config Config
+
+	listener net.Listener
+	server   http.Server
+
+	service *console.Service
+	usage   *accounting.Service
+	apiKeys console.APIKeys
+	audit   AuditRecords
+}
+
+// NewServer creates new instance of the admin server.
+func NewServer(log *zap.Logger, config Config, service *console.Service, usage *accounting.Service, apiKeys console.APIKeys, audit AuditRecords, listener net.Listener) *Server {
+	server := &Server{
+		log:      log,
+		config:   config,
+		listener: listener,
+		service:  service,
+		usage:    usage,
+		apiKeys:  apiKeys,
+		audit:    audit,
+	}
+
+	router := mux.NewRouter()
+	api := router.PathPrefix("/api").Subrouter()
+	api.Use(server.withAuth)
+	api.HandleFunc("/user", server.getUser).Methods(http.MethodGet)
+	api.HandleFunc("/user/{userID}/projects", server.getUserProjects).Methods(http.MethodGet)
+	api.HandleFunc("/user/{userID}/freeze", server.freezeUser).Methods(http.MethodPut)
+	api.HandleFunc("/user/{userID}/freeze", server.unfreezeUser).Methods(http.MethodDelete)
+	api.HandleFunc("/user/{userID}/mfa", server.resetUserMFA).Methods(http.MethodDelete)
+	api.HandleFunc("/project/{projectID}/limits", server.getProjectLimits).Methods(http.MethodGet)
+	api.HandleFunc("/project/{projectID}/limits", server.updateProjectLimits).Methods(http.MethodPut)
+	api.HandleFunc("/project/{projectID}/apikeys", server.getProjectAPIKeys).Methods(http.MethodGet)
+	api.HandleFunc("/apikey/{apikeyID}", server.revokeAPIKey).Methods(http.MethodDelete)
+	api.HandleFunc("/audit", server.getAuditRecords).Methods(http.MethodGet)
+
+	server.server = http.Server{
+		Handler: router,
+	}
+
+	return server
+}
+
+// Run starts the admin server.
+func (server *Server) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	ctx, cancel := context.WithCancel(ctx)
+	var group errgroup.Group
+	group.Go(func() error {
+		<-ctx.Done()
+		return server.server.Shutdown(context.Background())
+	})
+	group.Go(func() error {
+		defer cancel()
+		err := server.server.Serve(server.listener)
+		if err == http.ErrServerClosed {
+			return nil
+		}
+		return Error.Wrap(err)
+	})
+
+	return group.Wait()
+}
+
+// Close closes server and underlying listener.
+func (server *Server) Close() error {
+	return Error.Wrap(server.server.Close())
+}
+
+// withAuth rejects requests without the admin auth token.
+func (server *Server) withAuth(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		equality := subtle.ConstantTimeCompare(
+			[]byte(r.Header.Get("Authorization")),
+			[]byte(server.config.AuthToken),
+		)
+		if server.config.AuthToken == "" || equality != 1 {
+			server.serveJSONError(w, http.StatusUnauthorized, Error.New("unauthorized"))
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// user is the user information returned by the admin API.
+type user struct {
+	ID         uuid.UUID          `json:"id"`
+	FullName   string             `json:"fullName"`
+	ShortName  string             `json:"shortName"`
+	Email      string             `json:"email"`
+	Status     console.UserStatus `json:"status"`
+	MFAEnabled bool               `json:"mfaEnabled"`
+	CreatedAt  time.Time          `json:"createdAt"`
+}
+
+func userFromConsole(u *console.User) user {
+	return user{
+		ID:         u.ID,
+		FullName:   u.FullName,
+		ShortName:  u.ShortName,
+		Email:      u.Email,
+		Status:     u.Status,
+		MFAEnabled: u.MFAEnabled,
+		CreatedAt:  u.CreatedAt,
+	}
+}
+
+// getUser returns the user with the email of the query.
+func (server *Server) getUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	email := r.URL.Query().Get("email")
+	if email == "" {
+		server.serveJSONError(w, http.StatusBadRequest, Error.New("email is required"))
+		return
+	}
+
+	found, err := server.service.GetUserByEmail(ctx, email)
+	if err != nil {
+		server.serveError(w, err)
+		return
+	}
+
+	server.serveJSON(w, userFromConsole(found))
+}
+
+// getUserProjects returns the projects of a user.
+func (server *Server) getUserProjects(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		server.serveJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	projects, err := server.service.GetUserProjects(ctx, userID)
+	if err != nil {
+		server.serveError(w, err)
+		return
+	}
+	if projects == nil {
+		projects = []console.Project{}
+	}
+
+	server.serveJSON(w, projects)
+}
+
+// freezeUser blocks the account of a user.
+func (server *Server) freezeUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		server.serveJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if _, err = server.service.GetUser(ctx, userID); err != nil {
+		server.serveError(w, err)
+		return
+	}
+
+	attempt, err := server.record(ctx, r, ActionFreezeUser, UserTarget(userID), nil)
+	if err != nil {
+		server.serveError(w, err)
+		return
+	}
+
+	frozen, err := server.service.FreezeUser(ctx, userID)
+	server.recordOutcome(ctx, attempt, err)
+	if err != nil {
+		server.serveError(w, err)
+		return
+	}
+
+	server.serveJSON(w, userFromConsole(frozen))
+}
+
+// unfreezeUser activates the account of a frozen user.
+func (server *Server) unfreezeUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		server.serveJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	found, err := server.service.GetUser(ctx, userID)
+	if err != nil {
+		server.serveError(w, err)
+		return
+	}
+	if found.Status != console.Frozen {
+		server.serveJSONError(w, http.StatusConflict, Error.New("user is not frozen"))
+		return
+	}
+
+	attempt, err := server.record(ctx, r, ActionUnfreezeUser, UserTarget(userID), nil)
+	if err != nil {
+		server.serveError(w, err)
+		return
+	}
+
+	unfrozen, err := server.service.UnfreezeUser(ctx, userID)
+	server.recordOutcome(ctx, attempt, err)
+	if err != nil {
+		server.serveError(w, err)
+		return
+	}
+
+	server.serveJSON(w, userFromConsole(unfrozen))
+}
+
+// resetUserMFA disables the two-factor authentication of a user, who has lost
+// access to the second factor.
+func (server *Server) resetUserMFA(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	userID, err := uuidParam(r, "userID")
+	if err != nil {
+		server.serveJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	found, err := server.service.GetUser(ctx, userID)
+	if err != nil {
+		server.serveError(w, err)
+		return
+	}
+	if !found.MFAEnabled {
+		server.serveJSONError(w, http.StatusConflict, Error.New("user has no MFA enabled"))
+		return
+	}
+
+	attempt, err := server.record(ctx, r, ActionResetUserMFA, UserTarget(userID), nil)
+	if err != nil {
+		server.serveError(w, err)
+		return
+	}
+
+	reset, err := server.service.ResetUserMFA(ctx, userID)
+	server.recordOutcome(ctx, attempt, err)
+	if err != nil {
+		server.serveError(w, err)
+		return
+	}
+
+	server.serveJSON(w, userFromConsole(reset))
+}
+
+// projectLimits are the usage limits of a project.
+type projectLimits struct {
+	Storage   memory.Size `json:"storage"`
+	Bandwidth memory.Size `json:"bandwidth"`
+}
+
+// getProjectLimits returns the usage limits of a project.
+func (server *Server) getProjectLimits(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	projectID, err := uuidParam(r, "projectID")
+	if err != nil {
+		server.serveJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	limits, err := server.projectLimits(ctx, projectID)
+	if err != nil {
+		server.serveError(w, err)
+		return
+	}
+
+	server.serveJSON(w, limits)
+}
+
+// updateProjectLimits changes the usage limit of a project. Storage and
+// bandwidth share the same limit.
+func (server *Server) updateProjectLimits(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	projectID, err := uuidParam(r, "projectID")
+	if err != nil {
+		server.serveJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var request struct {
+		Usage string `json:"usage"`
+	}
+	if err = json.NewDecoder(r.Body).Decode(&request); err != nil {
+		server.serveJSONError(w, http.StatusBadRequest, Error.Wrap(err))
+		return
+	}
+
+	var limit memory.Size
+	if err = limit.Set(request.Usage); err != nil || limit < 0 {
+		server.serveJSONError(w, http.StatusBadRequest, Error.New("invalid usage limit %q", request.Usage))
+		return
+	}
+
+	// fails when the project doesn't exist
+	previous, err := server.projectLimits(ctx, projectID)
+	if err != nil {
+		server.serveError(w, err)
+		return
+	}
+
+	details := struct {
+		Previous projectLimits `json:"previous"`
+		Usage    memory.Size   `json:"usage"`
+	}{previous, limit}
+	attempt, err := server.record(ctx, r, ActionUpdateProjectLimits, ProjectTarget(projectID), details)
+	if err != nil {
+		server.serveError(w, err)
+		return
+	}
+
+	err = server.usage.UpdateProjectLimits(ctx, projectID, limit)
+	server.recordOutcome(ctx, attempt, err)
+	if err != nil {
+		server.serveError(w, err)
+		return
+	}
+
+	limits, err := server.projectLimits(ctx, projectID)
+	if err != nil {
+		server.serveError(w, err)
+		return
+	}
+
+	server.serveJSON(w, limits)
+}
+
+func (server *Server) projectLimits(ctx context.Context, projectID uuid.UUID) (_ projectLimits, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var limits projectLimits
+	limits.Storage, err = server.usage.GetProjectStorageLimit(ctx, projectID)
+	if err != nil {
+		return projectLimits{}, err
+	}
+	limits.Bandwidth, err = server.usage.GetProjectBandwidthLimit(ctx, projectID)
+	if err != nil {
+		return projectLimits{}, err
+	}
+	return limits, nil
+}
+
+// getProjectAPIKeys returns a page of the API keys of a project.
+func (server *Server) getProjectAPIKeys(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	projectID, err := uuidParam(r, "projectID")
+	if err != nil {
+		server.serveJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	page := uint64(1)
+	if value := r.URL.Query().Get("page"); value != "" {
+		page, err = strconv.ParseUint(value, 10, 32)
+		if err != nil || page == 0 {
+			server.serveJSONError(w, http.StatusBadRequest, Error.New("invalid page %q", value))
+			return
+		}
+	}
+
+	keys, err := server.apiKeys.GetPagedByProjectID(ctx, projectID, console.APIKeyCursor{
+		Limit:          50,
+		Page:           uint(page),
+		Order:          console.CreationDate,
+		OrderDirection: console.Ascending,
+	})
+	if err != nil {
+		server.serveError(w, err)
+		return
+	}
+
+	server.serveJSON(w, keys)
+}
+
+// revokeAPIKey deletes an API key.
+func (server *Server) revokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	keyID, err := uuidParam(r, "apikeyID")
+	if err != nil {
+		server.serveJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	key, err := server.apiKeys.Get(ctx, keyID)
+	if err != nil {
+		server.serveError(w, err)
+		return
+	}
+
+	details := struct {
+		ProjectID uuid.UUID `json:"projectId"`
+		Name      string    `json:"name"`
+	}{key.ProjectID, key.Name}
+	attempt, err := server.record(ctx, r, ActionRevokeAPIKey, APIKeyTarget(keyID), details)
+	if err != nil {
+		server.serveError(w, err)
+		return
+	}
+
+	err = server.apiKeys.Delete(ctx, keyID)
+	server.recordOutcome(ctx, attempt, err)
+	if err != nil {
+		server.serveError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getAuditRecords returns the newest audit records created before the
+// optional "before" time of the query.
+func (server *Server) getAuditRecords(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	query := r.URL.Query()
+
+	before := time.Now()
+	if value := query.Get("before"); value != "" {
+		before, err = time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			server.serveJSONError(w, http.StatusBadRequest, Error.Wrap(err))
+			return
+		}
+	}
+
+	limit := maxAuditRecords
+	if value := query.Get("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit <= 0 || limit > maxAuditRecords {
+			server.serveJSONError(w, http.StatusBadRequest, Error.New("limit must be between 1 and %d", maxAuditRecords))
+			return
+		}
+	}
+
+	records, err := server.audit.List(ctx, before, limit)
+	if err != nil {
+		server.serveError(w, err)
+		return
+	}
+	if records == nil {
+		records = []AuditRecord{}
+	}
+
+	server.serveJSON(w, records)
+}
+
+// record stores the attempted audit record of a change, which is about to
+// be made.
+func (server *Server) record(ctx context.Context, r *http.Request, action Action, target string, details interface{}) (_ AuditRecord, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	operator := r.Header.Get(operatorHeader)
+	if operator == "" {
+		return AuditRecord{}, errOperatorMissing
+	}
+
+	data := []byte("{}")
+	if details != nil {
+		data, err = json.Marshal(details)
+		if err != nil {
+			return AuditRecord{}, Error.Wrap(err)
+		}
+	}
+
+	record := AuditRecord{
+		Operator: operator,
+		Action:   action,
+		Target:   target,
+		Details:  string(data),
+		Outcome:  OutcomeAttempted,
+	}
+	if err := server.insertRecord(ctx, &record); err != nil {
+		return AuditRecord{}, err
+	}
+	return record, nil
+}
+
+// recordOutcome stores the outcome of an attempted change. The change has
+// already been made, so failing to store the outcome is only logged.
+func (server *Server) recordOutcome(ctx context.Context, attempt AuditRecord, changeErr error) {
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	details := struct {
+		Attempt uuid.UUID `json:"attempt"`
+		Error   string    `json:"error,omitempty"`
+	}{Attempt: attempt.ID}
+
+	outcome := OutcomeSucceeded
+	if changeErr != nil {
+		outcome = OutcomeFailed
+		details.Error = changeErr.Error()
+	}
+
+	data, err := json.Marshal(details)
+	if err == nil {
+		err = server.insertRecord(ctx, &AuditRecord{
+			Operator: attempt.Operator,
+			Action:   attempt.Action,
+			Target:   attempt.Target,
+			Details:  string(data),
+			Outcome:  outcome,
+		})
+	}
+	if err != nil {
+		server.log.Error("failed to record the outcome of an admin change",
+			zap.Stringer("attempt", attempt.ID),
+			zap.String("outcome", string(outcome)),
+			zap.Error(Error.Wrap(err)))
+	}
+}
+
+// insertRecord assigns a new id and the creation time to the record and
+// stores it.
+func (server *Server) insertRecord(ctx context.Context, record *AuditRecord) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	id, err := uuid.New()
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	record.ID = *id
+	record.CreatedAt = time.Now()
+
+	if err := server.audit.Insert(ctx, *record); err != nil {
+		return Error.Wrap(err)
+	}
+
+	server.log.Info("admin change",
+		zap.String("operator", record.Operator),
+		zap.String("action", string(record.Action)),
+		zap.String("target", record.Target),
+		zap.String("outcome", string(record.Outcome)),
+		zap.String("details", record.Details))
+	return nil
+}
+
+// errOperatorMissing is returned for changes without an operator.
+var errOperatorMissing = Error.New("the %s header is required for changes", operatorHeader)
+
+func uuidParam(r *http.Request, name string) (uuid.UUID, error) {
+	id, err := uuid.Parse(mux.Vars(r)[name])
+	if err != nil {
+		return uuid.UUID{}, Error.New("invalid %s", name)
+	}
+	return *id, nil
+}
+
+// serveJSON writes the value as JSON response.
+func (server *Server) serveJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		server.log.Error("failed to write json response", zap.Error(Error.Wrap(err)))
+	}
+}
+
+// serveError writes the JSON error response with the status matching the error.
+func (server *Server) serveError(w http.ResponseWriter, err error) {
+	switch {
+	case err == errOperatorMissing:
+		server.serveJSONError(w, http.StatusBadRequest, err)
+	case errs.Is(err, sql.ErrNoRows):
+		server.serveJSONError(w, http.StatusNotFound, Error.New("not found"))
+	default:
+		server.log.Error("admin request failed", zap.Error(err))
+		server.serveJSONError(w, http.StatusInternalServerError, err)
+	}
+}
+
+// serveJSONError writes JSON error to response output stream.
+func (server *Server) serveJSONError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	var response struct {
+		Error string `json:"error"`
+	}
+	response.Error = err.Error()
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		server.log.Error("failed to write json error response", zap.Error(Error.Wrap(err)))
+	}
+}
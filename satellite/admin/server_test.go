@@ -0,0 +1,203 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package admin_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"storj.io/common/memory"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/private/testplanet"
+	"storj.io/storj/satellite"
+	"storj.io/storj/satellite/admin"
+	"storj.io/storj/satellite/console"
+)
+
+func TestServer(t *testing.T) {
+	const token = "very-secret-admin-token"
+
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 1,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.Admin.AuthToken = token
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		sat := planet.Satellites[0]
+		address := "http://" + sat.Admin.Listener.Addr().String()
+		projectID := planet.Uplinks[0].ProjectID[sat.ID()]
+
+		user, err := sat.DB.Console().Users().Insert(ctx, &console.User{
+			ID:           testrand.UUID(),
+			FullName:     "Abusive User",
+			Email:        "abuse@example.test",
+			PasswordHash: []byte("password"),
+		})
+		require.NoError(t, err)
+		user.Status = console.Active
+		require.NoError(t, sat.DB.Console().Users().Update(ctx, user))
+
+		request := func(method, path, operator string, body string) (int, []byte) {
+			req, err := http.NewRequest(method, address+path, strings.NewReader(body))
+			require.NoError(t, err)
+			req.Header.Set("Authorization", token)
+			if operator != "" {
+				req.Header.Set("X-Admin-Operator", operator)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer func() { require.NoError(t, resp.Body.Close()) }()
+
+			data, err := ioutil.ReadAll(resp.Body)
+			require.NoError(t, err)
+			return resp.StatusCode, data
+		}
+
+		t.Run("unauthorized", func(t *testing.T) {
+			resp, err := http.Get(address + "/api/user?email=" + user.Email)
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+			require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		})
+
+		t.Run("user", func(t *testing.T) {
+			status, data := request(http.MethodGet, "/api/user?email="+user.Email, "", "")
+			require.Equal(t, http.StatusOK, status, string(data))
+
+			var found struct {
+				ID     string             `json:"id"`
+				Status console.UserStatus `json:"status"`
+			}
+			require.NoError(t, json.Unmarshal(data, &found))
+			require.Equal(t, user.ID.String(), found.ID)
+			require.Equal(t, console.Active, found.Status)
+
+			status, _ = request(http.MethodGet, "/api/user?email=missing@example.test", "", "")
+			require.Equal(t, http.StatusNotFound, status)
+		})
+
+		t.Run("freeze", func(t *testing.T) {
+			path := "/api/user/" + user.ID.String() + "/freeze"
+
+			status, _ := request(http.MethodPut, path, "", "")
+			require.Equal(t, http.StatusBadRequest, status)
+
+			status, data := request(http.MethodPut, path, "support", "")
+			require.Equal(t, http.StatusOK, status, string(data))
+
+			frozen, err := sat.DB.Console().Users().Get(ctx, user.ID)
+			require.NoError(t, err)
+			require.Equal(t, console.Frozen, frozen.Status)
+
+			status, data = request(http.MethodDelete, path, "support", "")
+			require.Equal(t, http.StatusOK, status, string(data))
+
+			unfrozen, err := sat.DB.Console().Users().Get(ctx, user.ID)
+			require.NoError(t, err)
+			require.Equal(t, console.Active, unfrozen.Status)
+
+			status, _ = request(http.MethodDelete, path, "support", "")
+			require.Equal(t, http.StatusConflict, status)
+		})
+
+		t.Run("reset mfa", func(t *testing.T) {
+			path := "/api/user/" + user.ID.String() + "/mfa"
+
+			status, _ := request(http.MethodDelete, path, "support", "")
+			require.Equal(t, http.StatusConflict, status)
+
+			key, err := console.NewMFASecretKey()
+			require.NoError(t, err)
+			stored, err := sat.DB.Console().Users().SetMFASecretKey(ctx, user.ID, key)
+			require.NoError(t, err)
+			require.True(t, stored)
+			enabled, err := sat.DB.Console().Users().EnableMFA(ctx, user.ID, key, []string{"code"})
+			require.NoError(t, err)
+			require.True(t, enabled)
+
+			status, data := request(http.MethodDelete, path, "support", "")
+			require.Equal(t, http.StatusOK, status, string(data))
+
+			reset, err := sat.DB.Console().Users().Get(ctx, user.ID)
+			require.NoError(t, err)
+			require.False(t, reset.MFAEnabled)
+			require.Empty(t, reset.MFASecretKey)
+		})
+
+		t.Run("project limits", func(t *testing.T) {
+			path := "/api/project/" + projectID.String() + "/limits"
+
+			status, data := request(http.MethodPut, path, "support", `{"usage":"5GB"}`)
+			require.Equal(t, http.StatusOK, status, string(data))
+
+			limit, err := sat.Accounting.ProjectUsage.GetProjectStorageLimit(ctx, projectID)
+			require.NoError(t, err)
+			require.Equal(t, 5*memory.GB, limit)
+
+			status, _ = request(http.MethodPut, path, "support", `{"usage":"lots"}`)
+			require.Equal(t, http.StatusBadRequest, status)
+
+			status, _ = request(http.MethodPut, "/api/project/"+testrand.UUID().String()+"/limits", "support", `{"usage":"5GB"}`)
+			require.Equal(t, http.StatusNotFound, status)
+		})
+
+		t.Run("revoke api key", func(t *testing.T) {
+			head := planet.Uplinks[0].APIKey[sat.ID()].Head()
+			key, err := sat.DB.Console().APIKeys().GetByHead(ctx, head)
+			require.NoError(t, err)
+
+			status, data := request(http.MethodDelete, "/api/apikey/"+key.ID.String(), "support", "")
+			require.Equal(t, http.StatusNoContent, status, string(data))
+
+			_, err = sat.DB.Console().APIKeys().GetByHead(ctx, head)
+			require.Error(t, err)
+
+			status, _ = request(http.MethodDelete, "/api/apikey/"+key.ID.String(), "support", "")
+			require.Equal(t, http.StatusNotFound, status)
+		})
+
+		t.Run("audit", func(t *testing.T) {
+			status, data := request(http.MethodGet, "/api/audit", "", "")
+			require.Equal(t, http.StatusOK, status, string(data))
+
+			var records []admin.AuditRecord
+			require.NoError(t, json.Unmarshal(data, &records))
+
+			// every change is recorded before and after it's made
+			var actions []admin.Action
+			for i := 0; i < len(records); i += 2 {
+				outcome, attempt := records[i], records[i+1]
+				require.Equal(t, "support", attempt.Operator)
+				require.Equal(t, admin.OutcomeAttempted, attempt.Outcome)
+				require.Equal(t, admin.OutcomeSucceeded, outcome.Outcome)
+				require.Equal(t, attempt.Action, outcome.Action)
+				require.Equal(t, attempt.Target, outcome.Target)
+				require.Contains(t, outcome.Details, attempt.ID.String())
+				actions = append(actions, attempt.Action)
+			}
+			require.Equal(t, []admin.Action{
+				admin.ActionRevokeAPIKey,
+				admin.ActionUpdateProjectLimits,
+				admin.ActionResetUserMFA,
+				admin.ActionUnfreezeUser,
+				admin.ActionFreezeUser,
+			}, actions)
+
+			status, data = request(http.MethodGet, "/api/audit?limit=1&before="+time.Now().Add(-time.Hour).Format(time.RFC3339Nano), "", "")
+			require.Equal(t, http.StatusOK, status, string(data))
+			require.Equal(t, "[]\n", string(data))
+		})
+	})
+}
@@ -24,6 +24,7 @@ import (
 	"storj.io/common/storj"
 	"storj.io/storj/pkg/auth/grpcauth"
 	"storj.io/storj/pkg/debug"
+	"storj.io/storj/pkg/metainfopb"
 	"storj.io/storj/pkg/server"
 	"storj.io/storj/private/lifecycle"
 	"storj.io/storj/private/post"
@@ -31,12 +32,15 @@ import (
 	"storj.io/storj/private/version"
 	"storj.io/storj/private/version/checker"
 	"storj.io/storj/satellite/accounting"
+	"storj.io/storj/satellite/admin"
 	"storj.io/storj/satellite/console"
 	"storj.io/storj/satellite/console/consoleauth"
 	"storj.io/storj/satellite/console/consoleweb"
 	"storj.io/storj/satellite/contact"
+	"storj.io/storj/satellite/gc"
 	"storj.io/storj/satellite/gracefulexit"
 	"storj.io/storj/satellite/inspector"
+	"storj.io/storj/satellite/internalpb"
 	"storj.io/storj/satellite/mailservice"
 	"storj.io/storj/satellite/mailservice/simulate"
 	"storj.io/storj/satellite/marketingweb"
@@ -49,6 +53,7 @@ import (
 	"storj.io/storj/satellite/payments/stripecoinpayments"
 	"storj.io/storj/satellite/referrals"
 	"storj.io/storj/satellite/repair/irreparable"
+	"storj.io/storj/satellite/repair/repairer"
 	"storj.io/storj/satellite/rewards"
 	"storj.io/storj/satellite/vouchers"
 )
@@ -110,7 +115,12 @@ type API struct {
 	}
 
 	Repair struct {
-		Inspector *irreparable.Inspector
+		Inspector      *irreparable.Inspector
+		QueueInspector *repairer.Inspector
+	}
+
+	GarbageCollection struct {
+		Inspector *gc.Inspector
 	}
 
 	Accounting struct {
@@ -141,6 +151,11 @@ type API struct {
 		Endpoint *consoleweb.Server
 	}
 
+	Admin struct {
+		Listener net.Listener
+		Endpoint *admin.Server
+	}
+
 	Marketing struct {
 		PartnersService *rewards.PartnersService
 
@@ -246,6 +261,14 @@ func NewAPI(log *zap.Logger, full *identity.FullIdentity, db DB,
 			Close: peer.Overlay.Service.Close,
 		})
 
+		peer.Services.Add(lifecycle.Item{
+			Name:  "overlay:selection-cache",
+			Run:   peer.Overlay.Service.SelectionCache.Run,
+			Close: peer.Overlay.Service.SelectionCache.Close,
+		})
+		peer.Debug.Server.Panel.Add(
+			debug.Cycle("Overlay Node Selection Cache", peer.Overlay.Service.SelectionCache.Loop))
+
 		peer.Overlay.Inspector = overlay.NewInspector(peer.Overlay.Service)
 		pb.RegisterOverlayInspectorServer(peer.Server.PrivateGRPC(), peer.Overlay.Inspector)
 		pb.DRPCRegisterOverlayInspector(peer.Server.PrivateDRPC(), peer.Overlay.Inspector)
@@ -272,7 +295,16 @@ func NewAPI(log *zap.Logger, full *identity.FullIdentity, db DB,
 			Type:    pb.NodeType_SATELLITE,
 			Version: *pbVersion,
 		}
-		peer.Contact.Service = contact.NewService(peer.Log.Named("contact:service"), self, peer.Overlay.Service, peer.DB.PeerIdentities(), peer.Dialer)
+		var locator overlay.Locator
+		if config.Overlay.LocationDB != "" {
+			locationDB, err := overlay.LoadLocationDB(config.Overlay.LocationDB)
+			if err != nil {
+				return nil, errs.Combine(err, peer.Close())
+			}
+			locator = locationDB
+		}
+
+		peer.Contact.Service = contact.NewService(peer.Log.Named("contact:service"), self, peer.Overlay.Service, peer.DB.PeerIdentities(), peer.Dialer, locator)
 		peer.Contact.Endpoint = contact.NewEndpoint(peer.Log.Named("contact:endpoint"), peer.Contact.Service)
 		pb.RegisterNodeServer(peer.Server.GRPC(), peer.Contact.Endpoint)
 		pb.DRPCRegisterNode(peer.Server.DRPC(), peer.Contact.Endpoint)
@@ -401,6 +433,8 @@ func NewAPI(log *zap.Logger, full *identity.FullIdentity, db DB,
 			peer.DB.Console().APIKeys(),
 			peer.Accounting.ProjectUsage,
 			peer.DB.Console().Projects(),
+			peer.DB.SharedSegments(),
+			peer.DB.ObjectVersions(),
 			config.Metainfo.RS,
 			signing.SignerFromFullIdentity(peer.Identity),
 			config.Metainfo.MaxCommitInterval,
@@ -408,6 +442,12 @@ func NewAPI(log *zap.Logger, full *identity.FullIdentity, db DB,
 		)
 		pb.RegisterMetainfoServer(peer.Server.GRPC(), peer.Metainfo.Endpoint2)
 		pb.DRPCRegisterMetainfo(peer.Server.DRPC(), peer.Metainfo.Endpoint2)
+		metainfopb.RegisterObjectCopyServer(peer.Server.GRPC(), peer.Metainfo.Endpoint2)
+		metainfopb.DRPCRegisterObjectCopy(peer.Server.DRPC(), peer.Metainfo.Endpoint2)
+		metainfopb.RegisterObjectVersionsServer(peer.Server.GRPC(), peer.Metainfo.Endpoint2)
+		metainfopb.DRPCRegisterObjectVersions(peer.Server.DRPC(), peer.Metainfo.Endpoint2)
+		metainfopb.RegisterBucketLifecycleServer(peer.Server.GRPC(), peer.Metainfo.Endpoint2)
+		metainfopb.DRPCRegisterBucketLifecycle(peer.Server.DRPC(), peer.Metainfo.Endpoint2)
 
 		peer.Services.Add(lifecycle.Item{
 			Name:  "metainfo:endpoint",
@@ -419,6 +459,34 @@ func NewAPI(log *zap.Logger, full *identity.FullIdentity, db DB,
 		peer.Repair.Inspector = irreparable.NewInspector(peer.DB.Irreparable())
 		pb.RegisterIrreparableInspectorServer(peer.Server.PrivateGRPC(), peer.Repair.Inspector)
 		pb.DRPCRegisterIrreparableInspector(peer.Server.PrivateDRPC(), peer.Repair.Inspector)
+
+		segmentRepairer := repairer.NewSegmentRepairer(
+			peer.Log.Named("inspector:segment-repair"),
+			peer.Metainfo.Service,
+			peer.Orders.Service,
+			peer.Overlay.Service,
+			peer.Dialer,
+			config.Repairer.Timeout,
+			config.Repairer.MaxExcessRateOptimalThreshold,
+			config.Checker.RepairOverride,
+			config.Repairer.DownloadTimeout,
+			signing.SigneeFromPeerIdentity(peer.Identity.PeerIdentity()),
+		)
+		peer.Repair.QueueInspector = repairer.NewInspector(
+			peer.Log.Named("repair:inspector"),
+			peer.DB.RepairQueue(),
+			peer.Metainfo.Service,
+			peer.Overlay.Service,
+			segmentRepairer,
+		)
+		internalpb.RegisterRepairQueueInspectorServer(peer.Server.PrivateGRPC(), peer.Repair.QueueInspector)
+		internalpb.DRPCRegisterRepairQueueInspector(peer.Server.PrivateDRPC(), peer.Repair.QueueInspector)
+	}
+
+	{ // setup garbage collection inspector
+		peer.GarbageCollection.Inspector = gc.NewInspector(peer.DB.GarbageCollection())
+		internalpb.RegisterGarbageCollectionInspectorServer(peer.Server.PrivateGRPC(), peer.GarbageCollection.Inspector)
+		internalpb.DRPCRegisterGarbageCollectionInspector(peer.Server.PrivateDRPC(), peer.GarbageCollection.Inspector)
 	}
 
 	{ // setup inspector
@@ -571,6 +639,7 @@ func NewAPI(log *zap.Logger, full *identity.FullIdentity, db DB,
 			peer.Marketing.PartnersService,
 			peer.Payments.Accounts,
 			consoleConfig.PasswordCost,
+			consoleConfig.MFA,
 		)
 		if err != nil {
 			return nil, errs.Combine(err, peer.Close())
@@ -593,6 +662,31 @@ func NewAPI(log *zap.Logger, full *identity.FullIdentity, db DB,
 		})
 	}
 
+	{ // setup admin endpoint
+		if config.Admin.Address != "" {
+			peer.Admin.Listener, err = net.Listen("tcp", config.Admin.Address)
+			if err != nil {
+				return nil, errs.Combine(err, peer.Close())
+			}
+
+			peer.Admin.Endpoint = admin.NewServer(
+				peer.Log.Named("admin:endpoint"),
+				config.Admin,
+				peer.Console.Service,
+				peer.Accounting.ProjectUsage,
+				peer.DB.Console().APIKeys(),
+				peer.DB.AdminAuditRecords(),
+				peer.Admin.Listener,
+			)
+
+			peer.Servers.Add(lifecycle.Item{
+				Name:  "admin:endpoint",
+				Run:   peer.Admin.Endpoint.Run,
+				Close: peer.Admin.Endpoint.Close,
+			})
+		}
+	}
+
 	{ // setup node stats endpoint
 		peer.NodeStats.Endpoint = nodestats.NewEndpoint(
 			peer.Log.Named("nodestats:endpoint"),
@@ -12,7 +12,7 @@ import (
 	"storj.io/storj/satellite/metainfo"
 )
 
-var _ metainfo.Observer = (*PathCollector)(nil)
+var _ metainfo.ForkingObserver = (*PathCollector)(nil)
 
 // PathCollector uses the metainfo loop to add paths to node reservoirs
 //
@@ -52,3 +52,26 @@ func (collector *PathCollector) Object(ctx context.Context, path metainfo.Scoped
 func (collector *PathCollector) InlineSegment(ctx context.Context, path metainfo.ScopedPath, pointer *pb.Pointer) (err error) {
 	return nil
 }
+
+// Fork returns an empty path collector for a range of the metainfo loop.
+func (collector *PathCollector) Fork(ctx context.Context) (_ metainfo.Observer, err error) {
+	return NewPathCollector(collector.slotCount, rand.New(rand.NewSource(collector.rand.Int63()))), nil
+}
+
+// Join merges the reservoirs of a forked path collector into the path collector.
+func (collector *PathCollector) Join(ctx context.Context, forked metainfo.Observer) (err error) {
+	other, ok := forked.(*PathCollector)
+	if !ok {
+		return Error.New("unexpected observer type %T", forked)
+	}
+
+	for nodeID, reservoir := range other.Reservoirs {
+		existing, ok := collector.Reservoirs[nodeID]
+		if !ok {
+			collector.Reservoirs[nodeID] = reservoir
+			continue
+		}
+		existing.Merge(collector.rand, reservoir)
+	}
+	return nil
+}
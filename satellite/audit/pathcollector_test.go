@@ -4,19 +4,27 @@
 package audit_test
 
 import (
+	"context"
+	"fmt"
 	"math/rand"
 	"testing"
 	"time"
 
+	"github.com/gogo/protobuf/proto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"storj.io/common/errs2"
 	"storj.io/common/memory"
+	"storj.io/common/pb"
 	"storj.io/common/storj"
 	"storj.io/common/testcontext"
 	"storj.io/common/testrand"
 	"storj.io/storj/private/testplanet"
 	"storj.io/storj/satellite/audit"
+	"storj.io/storj/satellite/metainfo"
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/teststore"
 )
 
 // TestAuditPathCollector does the following:
@@ -71,3 +79,97 @@ func TestAuditPathCollector(t *testing.T) {
 		}
 	})
 }
+
+func TestAuditPathCollectorParallel(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	db := teststore.New()
+	nodes := make([]storj.NodeID, 5)
+	for i := range nodes {
+		nodes[i] = testrand.NodeID()
+	}
+
+	nodePaths := make(map[storj.NodeID]map[storj.Path]bool)
+	for i := 0; i < 40; i++ {
+		path := storj.JoinPaths(testrand.UUID().String(), "l", "bucket", "object")
+		pointer := &pb.Pointer{Type: pb.Pointer_REMOTE, Remote: &pb.RemoteSegment{}}
+		for n := 0; n < 3; n++ {
+			nodeID := nodes[(i+n)%len(nodes)]
+			pointer.Remote.RemotePieces = append(pointer.Remote.RemotePieces, &pb.RemotePiece{
+				PieceNum: int32(n),
+				NodeId:   nodeID,
+			})
+			if nodePaths[nodeID] == nil {
+				nodePaths[nodeID] = make(map[storj.Path]bool)
+			}
+			nodePaths[nodeID][path] = true
+		}
+
+		data, err := proto.Marshal(pointer)
+		require.NoError(t, err)
+		require.NoError(t, db.Put(ctx, storage.Key(path), data))
+	}
+
+	r := rand.New(rand.NewSource(time.Now().Unix()))
+	serial := audit.NewPathCollector(3, r)
+	require.NoError(t, metainfo.IterateDatabase(ctx, 0, db, serial))
+
+	metaLoop := metainfo.NewLoop(metainfo.LoopConfig{Workers: 8}, db, nil)
+	loopCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ctx.Go(func() error {
+		return errs2.IgnoreCanceled(metaLoop.Run(loopCtx))
+	})
+
+	parallel := audit.NewPathCollector(3, r)
+	require.NoError(t, metaLoop.Join(ctx, parallel))
+
+	require.Len(t, parallel.Reservoirs, len(serial.Reservoirs))
+	for nodeID := range serial.Reservoirs {
+		reservoir := parallel.Reservoirs[nodeID]
+		require.NotNil(t, reservoir)
+
+		repeats := make(map[storj.Path]bool)
+		for _, path := range reservoir.Paths {
+			if path == "" {
+				continue
+			}
+			assert.True(t, nodePaths[nodeID][path], "expected path of the node")
+			assert.False(t, repeats[path], "expected every item in reservoir to be unique")
+			repeats[path] = true
+		}
+		assert.NotEmpty(t, repeats)
+	}
+}
+
+func TestReservoirMerge(t *testing.T) {
+	r := rand.New(rand.NewSource(time.Now().Unix()))
+
+	a, b := audit.NewReservoir(3), audit.NewReservoir(3)
+	a.Sample(r, "a1")
+	b.Sample(r, "b1")
+
+	// both reservoirs have free slots, so every path is kept
+	a.Merge(r, b)
+	assert.ElementsMatch(t, []storj.Path{"a1", "b1", ""}, a.Paths[:])
+
+	// a full reservoir only keeps paths which have been sampled
+	c := audit.NewReservoir(3)
+	for i := 0; i < 100; i++ {
+		c.Sample(r, fmt.Sprintf("c%d", i))
+	}
+	a.Merge(r, c)
+
+	sampled := map[storj.Path]bool{"a1": true, "b1": true}
+	for _, path := range c.Paths {
+		sampled[path] = true
+	}
+	repeats := make(map[storj.Path]bool)
+	for _, path := range a.Paths {
+		require.NotEmpty(t, path)
+		assert.True(t, sampled[path])
+		assert.False(t, repeats[path])
+		repeats[path] = true
+	}
+}
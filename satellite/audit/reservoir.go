@@ -44,3 +44,36 @@ func (reservoir *Reservoir) Sample(r *rand.Rand, path storj.Path) {
 		}
 	}
 }
+
+// Merge merges the sample of another reservoir, which has seen other paths,
+// into the reservoir. Every sampled path is kept with a probability
+// proportional to the number of paths its reservoir has seen.
+func (reservoir *Reservoir) Merge(r *rand.Rand, other *Reservoir) {
+	ours, theirs := reservoir.sampled(), other.sampled()
+	oursSeen, theirsSeen := reservoir.index, other.index
+
+	var merged [maxReservoirSize]storj.Path
+	for i := 0; i < int(reservoir.size) && len(ours)+len(theirs) > 0; i++ {
+		if len(theirs) == 0 || (len(ours) > 0 && r.Int63n(oursSeen+theirsSeen) < oursSeen) {
+			merged[i], ours = ours[0], ours[1:]
+			oursSeen--
+		} else {
+			merged[i], theirs = theirs[0], theirs[1:]
+			theirsSeen--
+		}
+	}
+
+	reservoir.Paths = merged
+	reservoir.index += other.index
+}
+
+// sampled returns the paths in the reservoir.
+func (reservoir *Reservoir) sampled() []storj.Path {
+	var paths []storj.Path
+	for _, path := range reservoir.Paths[:reservoir.size] {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
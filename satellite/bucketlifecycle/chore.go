@@ -0,0 +1,474 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package bucketlifecycle
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/skyrings/skyring-common/tools/uuid"
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/common/pb"
+	"storj.io/common/storj"
+	"storj.io/common/sync2"
+	"storj.io/storj/satellite/metainfo"
+	"storj.io/storj/satellite/overlay"
+	"storj.io/storj/storage"
+)
+
+var (
+	// Error defines the bucket lifecycle chore errors class.
+	Error = errs.Class("bucket lifecycle chore error")
+	mon   = monkit.Package()
+)
+
+// deletePiecesSuccessThreshold is the fraction of pieces which have to be
+// deleted before DeletePieces returns. The remaining pieces are collected by
+// garbage collection.
+const deletePiecesSuccessThreshold = 0.75
+
+// Config contains configurable values for bucket lifecycle rules.
+type Config struct {
+	Enabled  bool          `help:"set if bucket lifecycle rules are enforced" releaseDefault:"true" devDefault:"true"`
+	Interval time.Duration `help:"the time between each evaluation of bucket lifecycle rules" releaseDefault:"24h" devDefault:"1m"`
+	DryRun   bool          `help:"only log and count the objects and uploads which bucket lifecycle rules would remove" default:"false"`
+
+	MaxRemovals int `help:"the maximum number of objects and uploads removed by one evaluation, the remaining ones are removed by the next evaluations" default:"100000"`
+}
+
+// RuleKey identifies a lifecycle rule of a bucket.
+type RuleKey struct {
+	ProjectID  uuid.UUID
+	BucketName string
+	RuleID     string
+}
+
+// RuleStats contains what a lifecycle rule removed in a cycle.
+type RuleStats struct {
+	ExpiredObjects int64
+	ExpiredBytes   int64
+	AbortedUploads int64
+	AbortedBytes   int64
+}
+
+// Chore evaluates bucket lifecycle rules during the metainfo loop and removes
+// the expired objects and incomplete uploads.
+//
+// architecture: Chore
+type Chore struct {
+	log            *zap.Logger
+	config         Config
+	Loop           *sync2.Cycle
+	metainfoLoop   *metainfo.Loop
+	metainfo       *metainfo.Service
+	deletePieces   *metainfo.DeletePiecesService
+	sharedSegments metainfo.SharedSegmentsDB
+	overlay        *overlay.Service
+
+	mu        sync.Mutex
+	ruleStats map[RuleKey]RuleStats
+}
+
+// NewChore creates a new bucket lifecycle chore.
+func NewChore(log *zap.Logger, config Config, loop *metainfo.Loop, metainfo *metainfo.Service, deletePieces *metainfo.DeletePiecesService, sharedSegments metainfo.SharedSegmentsDB, overlay *overlay.Service) *Chore {
+	chore := &Chore{
+		log:            log,
+		config:         config,
+		Loop:           sync2.NewCycle(config.Interval),
+		metainfoLoop:   loop,
+		metainfo:       metainfo,
+		deletePieces:   deletePieces,
+		sharedSegments: sharedSegments,
+		overlay:        overlay,
+		ruleStats:      map[RuleKey]RuleStats{},
+	}
+	mon.Chain(chore)
+	return chore
+}
+
+// Run starts the bucket lifecycle chore.
+func (chore *Chore) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return chore.Loop.Run(ctx, func(ctx context.Context) (err error) {
+		defer mon.Task()(&ctx)(&err)
+
+		err = chore.evaluate(ctx)
+		if err != nil {
+			chore.log.Error("error evaluating bucket lifecycle rules", zap.Error(err))
+		}
+		return nil
+	})
+}
+
+// evaluate joins the metainfo loop to find what the lifecycle rules remove and
+// removes it afterwards.
+func (chore *Chore) evaluate(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	lifecycles, err := chore.metainfo.ListBucketLifecycles(ctx)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	if len(lifecycles) == 0 {
+		chore.setRuleStats(map[RuleKey]RuleStats{})
+		return nil
+	}
+
+	lifecycles, err = chore.withoutVersionedExpiration(ctx, lifecycles)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	evaluator := NewEvaluator(lifecycles, time.Now(), chore.config.MaxRemovals)
+	err = chore.metainfoLoop.Join(ctx, evaluator)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	mon.IntVal("lifecycle_postponed_removals").Observe(evaluator.Postponed)
+	if evaluator.Postponed > 0 {
+		chore.log.Info("bucket lifecycle removals postponed to the next evaluation",
+			zap.Int("removals", len(evaluator.Expired)+len(evaluator.Incomplete)),
+			zap.Int64("postponed", evaluator.Postponed),
+		)
+	}
+
+	ruleStats := map[RuleKey]RuleStats{}
+	var expiredObjects, expiredBytes, abortedUploads, abortedBytes int64
+
+	for _, removal := range evaluator.Expired {
+		removed, size, err := chore.removeObject(ctx, removal)
+		if err != nil {
+			chore.log.Error("unable to remove expired object", zap.Stringer("Project ID", removal.ProjectID), zap.String("rule", removal.RuleID), zap.Error(err))
+			continue
+		}
+		if !removed {
+			continue
+		}
+
+		key := RuleKey{ProjectID: removal.ProjectID, BucketName: string(removal.BucketName), RuleID: removal.RuleID}
+		stats := ruleStats[key]
+		stats.ExpiredObjects++
+		stats.ExpiredBytes += size
+		ruleStats[key] = stats
+
+		expiredObjects++
+		expiredBytes += size
+	}
+
+	for _, removal := range evaluator.Incomplete {
+		removed, size, err := chore.abortUpload(ctx, removal)
+		if err != nil {
+			chore.log.Error("unable to remove incomplete upload", zap.Stringer("Project ID", removal.ProjectID), zap.String("rule", removal.RuleID), zap.Error(err))
+			continue
+		}
+		if !removed {
+			continue
+		}
+
+		key := RuleKey{ProjectID: removal.ProjectID, BucketName: string(removal.BucketName), RuleID: removal.RuleID}
+		stats := ruleStats[key]
+		stats.AbortedUploads++
+		stats.AbortedBytes += size
+		ruleStats[key] = stats
+
+		abortedUploads++
+		abortedBytes += size
+	}
+
+	chore.setRuleStats(ruleStats)
+
+	mon.IntVal("lifecycle_expired_objects").Observe(expiredObjects)
+	mon.IntVal("lifecycle_expired_bytes").Observe(expiredBytes)
+	mon.IntVal("lifecycle_aborted_uploads").Observe(abortedUploads)
+	mon.IntVal("lifecycle_aborted_bytes").Observe(abortedBytes)
+
+	for key, stats := range ruleStats {
+		chore.log.Info("bucket lifecycle rule evaluated",
+			zap.Bool("dry run", chore.config.DryRun),
+			zap.Stringer("Project ID", key.ProjectID),
+			zap.String("rule", key.RuleID),
+			zap.Int64("expired objects", stats.ExpiredObjects),
+			zap.Int64("expired bytes", stats.ExpiredBytes),
+			zap.Int64("aborted uploads", stats.AbortedUploads),
+			zap.Int64("aborted bytes", stats.AbortedBytes),
+		)
+	}
+	return nil
+}
+
+// withoutVersionedExpiration drops the expiration of the rules of buckets with
+// versioning, because removing their objects has to keep a version and add a
+// delete marker. Incomplete uploads are still aborted. The endpoints reject
+// combining expiration and versioning, so this only happens for rules which
+// were set before, these are reported as errors.
+func (chore *Chore) withoutVersionedExpiration(ctx context.Context, lifecycles []metainfo.BucketLifecycle) (_ []metainfo.BucketLifecycle, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	for i, lifecycle := range lifecycles {
+		versioning, err := chore.metainfo.GetBucketVersioning(ctx, lifecycle.BucketName, lifecycle.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		if versioning == metainfo.VersioningUnversioned {
+			continue
+		}
+
+		rules := make([]metainfo.LifecycleRule, 0, len(lifecycle.Rules))
+		for _, rule := range lifecycle.Rules {
+			if rule.ExpireAfterDays > 0 {
+				chore.log.Error("expiration rule of bucket with versioning is not applied", zap.Stringer("Project ID", lifecycle.ProjectID), zap.String("rule", rule.ID))
+				mon.Event("lifecycle_versioned_expiration_ignored")
+				rule.ExpireAfterDays = 0
+			}
+			rules = append(rules, rule)
+		}
+		lifecycles[i].Rules = rules
+	}
+	return lifecycles, nil
+}
+
+// removeObject removes an expired object unless it has been replaced since the
+// metainfo loop saw it. It returns whether the object has been removed and the
+// number of removed bytes.
+func (chore *Chore) removeObject(ctx context.Context, removal Removal) (removed bool, size int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	path, err := metainfo.CreatePath(ctx, removal.ProjectID, -1, removal.BucketName, removal.EncryptedPath)
+	if err != nil {
+		return false, 0, Error.Wrap(err)
+	}
+
+	pointerBytes, pointer, err := chore.metainfo.GetWithBytes(ctx, path)
+	if err != nil {
+		if storj.ErrObjectNotFound.Has(err) {
+			return false, 0, nil
+		}
+		return false, 0, Error.Wrap(err)
+	}
+	if !pointer.CreationDate.Equal(removal.CreationDate) {
+		return false, 0, nil
+	}
+
+	streamMeta := pb.StreamMeta{}
+	err = proto.Unmarshal(pointer.Metadata, &streamMeta)
+	if err != nil {
+		return false, 0, Error.Wrap(err)
+	}
+
+	if !chore.config.DryRun {
+		err = chore.metainfo.Delete(ctx, path, pointerBytes)
+		if err != nil {
+			// the object has been deleted or replaced concurrently
+			if storj.ErrObjectNotFound.Has(err) || storage.ErrValueChanged.Has(err) {
+				return false, 0, nil
+			}
+			return false, 0, Error.Wrap(err)
+		}
+	}
+
+	pointers := []*pb.Pointer{pointer}
+	// NumberOfSegments is 0 when the number of segments is encrypted, in
+	// which case the segments are removed until one doesn't exist.
+	for index := int64(0); streamMeta.NumberOfSegments == 0 || index < streamMeta.NumberOfSegments-1; index++ {
+		pointer, err := chore.removeSegment(ctx, removal, index)
+		if err != nil {
+			return true, sumSegmentSizes(pointers), err
+		}
+		if pointer == nil {
+			break
+		}
+		pointers = append(pointers, pointer)
+	}
+
+	chore.deleteRemotePieces(ctx, pointers)
+	return true, sumSegmentSizes(pointers), nil
+}
+
+// abortUpload removes the segments of an upload which hasn't been committed.
+// It returns whether the upload has been removed and the number of removed
+// bytes.
+func (chore *Chore) abortUpload(ctx context.Context, removal Removal) (removed bool, size int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	path, err := metainfo.CreateVersionPath(ctx, removal.ProjectID, -1, removal.BucketName, removal.EncryptedPath, removal.Version)
+	if err != nil {
+		return false, 0, Error.Wrap(err)
+	}
+
+	// the first segment belongs to a committed object
+	_, err = chore.metainfo.Get(ctx, path)
+	if err == nil {
+		return false, 0, nil
+	}
+	if !storj.ErrObjectNotFound.Has(err) {
+		return false, 0, Error.Wrap(err)
+	}
+
+	path, err = metainfo.CreateVersionPath(ctx, removal.ProjectID, 0, removal.BucketName, removal.EncryptedPath, removal.Version)
+	if err != nil {
+		return false, 0, Error.Wrap(err)
+	}
+
+	// the upload has been restarted
+	first, err := chore.metainfo.Get(ctx, path)
+	if err != nil {
+		if storj.ErrObjectNotFound.Has(err) {
+			return false, 0, nil
+		}
+		return false, 0, Error.Wrap(err)
+	}
+	if !first.CreationDate.Equal(removal.CreationDate) {
+		return false, 0, nil
+	}
+
+	var pointers []*pb.Pointer
+	for index := int64(0); ; index++ {
+		pointer, err := chore.removeSegment(ctx, removal, index)
+		if err != nil {
+			return len(pointers) > 0, sumSegmentSizes(pointers), err
+		}
+		if pointer == nil {
+			break
+		}
+		pointers = append(pointers, pointer)
+	}
+
+	chore.deleteRemotePieces(ctx, pointers)
+	return len(pointers) > 0, sumSegmentSizes(pointers), nil
+}
+
+// removeSegment removes the pointer of a segment which isn't the last one and
+// returns it. It returns nil when the segment doesn't exist.
+func (chore *Chore) removeSegment(ctx context.Context, removal Removal, index int64) (_ *pb.Pointer, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	path, err := metainfo.CreateVersionPath(ctx, removal.ProjectID, index, removal.BucketName, removal.EncryptedPath, removal.Version)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	pointerBytes, pointer, err := chore.metainfo.GetWithBytes(ctx, path)
+	if err != nil {
+		if storj.ErrObjectNotFound.Has(err) {
+			return nil, nil
+		}
+		return nil, Error.Wrap(err)
+	}
+
+	if !chore.config.DryRun {
+		err = chore.metainfo.Delete(ctx, path, pointerBytes)
+		if err != nil {
+			if storj.ErrObjectNotFound.Has(err) || storage.ErrValueChanged.Has(err) {
+				return nil, nil
+			}
+			return nil, Error.Wrap(err)
+		}
+	}
+	return pointer, nil
+}
+
+// deleteRemotePieces deletes the pieces of the removed remote segments from
+// the storage nodes. Pieces which can't be deleted, e.g. because a node is
+// offline, are left to garbage collection.
+func (chore *Chore) deleteRemotePieces(ctx context.Context, pointers []*pb.Pointer) {
+	defer mon.Task()(&ctx)(nil)
+
+	if chore.config.DryRun {
+		return
+	}
+
+	nodesPieces := make(map[storj.NodeID][]storj.PieceID)
+	var nodeIDs storj.NodeIDList
+	for _, pointer := range pointers {
+		if pointer.Type != pb.Pointer_REMOTE || metainfo.IsSharedSegment(ctx, chore.log, chore.sharedSegments, pointer) {
+			continue
+		}
+
+		rootPieceID := pointer.GetRemote().RootPieceId
+		for _, piece := range pointer.GetRemote().GetRemotePieces() {
+			if _, ok := nodesPieces[piece.NodeId]; !ok {
+				nodeIDs = append(nodeIDs, piece.NodeId)
+			}
+			nodesPieces[piece.NodeId] = append(nodesPieces[piece.NodeId], rootPieceID.Derive(piece.NodeId, piece.PieceNum))
+		}
+	}
+
+	if len(nodeIDs) == 0 {
+		return
+	}
+
+	nodes, err := chore.overlay.KnownReliable(ctx, nodeIDs)
+	if err != nil {
+		chore.log.Warn("unable to look up nodes from overlay", zap.Error(err))
+		return
+	}
+
+	var nodesPiecesList metainfo.NodesPieces
+	for _, node := range nodes {
+		nodesPiecesList = append(nodesPiecesList, metainfo.NodePieces{
+			Node:   node,
+			Pieces: nodesPieces[node.Id],
+		})
+	}
+
+	err = chore.deletePieces.DeletePieces(ctx, nodesPiecesList, deletePiecesSuccessThreshold)
+	if err != nil {
+		chore.log.Warn("unable to delete pieces", zap.Error(err))
+	}
+}
+
+func (chore *Chore) setRuleStats(ruleStats map[RuleKey]RuleStats) {
+	chore.mu.Lock()
+	defer chore.mu.Unlock()
+	chore.ruleStats = ruleStats
+}
+
+// RuleStats returns what each lifecycle rule removed in the last cycle. In a
+// dry run it's what the rules would have removed.
+func (chore *Chore) RuleStats() map[RuleKey]RuleStats {
+	chore.mu.Lock()
+	defer chore.mu.Unlock()
+
+	ruleStats := make(map[RuleKey]RuleStats, len(chore.ruleStats))
+	for key, stats := range chore.ruleStats {
+		ruleStats[key] = stats
+	}
+	return ruleStats
+}
+
+// Stats implements monkit.StatSource and reports what each lifecycle rule
+// removed in the last cycle.
+func (chore *Chore) Stats(cb func(key monkit.SeriesKey, field string, val float64)) {
+	for key, stats := range chore.RuleStats() {
+		series := monkit.NewSeriesKey("lifecycle_rule").
+			WithTag("project_id", key.ProjectID.String()).
+			WithTag("bucket", key.BucketName).
+			WithTag("rule", key.RuleID)
+
+		cb(series, "expired_objects", float64(stats.ExpiredObjects))
+		cb(series, "expired_bytes", float64(stats.ExpiredBytes))
+		cb(series, "aborted_uploads", float64(stats.AbortedUploads))
+		cb(series, "aborted_bytes", float64(stats.AbortedBytes))
+	}
+}
+
+// Close closes the bucket lifecycle chore.
+func (chore *Chore) Close() error {
+	chore.Loop.Close()
+	return nil
+}
+
+func sumSegmentSizes(pointers []*pb.Pointer) (size int64) {
+	for _, pointer := range pointers {
+		size += pointer.SegmentSize
+	}
+	return size
+}
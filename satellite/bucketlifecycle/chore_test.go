@@ -0,0 +1,135 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package bucketlifecycle_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/common/memory"
+	"storj.io/common/pb"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/pkg/metainfopb"
+	"storj.io/storj/private/testplanet"
+	"storj.io/storj/satellite/bucketlifecycle"
+	"storj.io/storj/storage"
+)
+
+func TestChore(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		uplink := planet.Uplinks[0]
+		apiKey := uplink.APIKey[satellite.ID()]
+		projectID := uplink.ProjectID[satellite.ID()]
+
+		satellite.BucketLifecycle.Chore.Loop.Pause()
+
+		err := uplink.Upload(ctx, satellite, "testbucket", "expired", testrand.Bytes(10*memory.KiB))
+		require.NoError(t, err)
+
+		// the pointers are aged, because the rules don't remove anything younger than an hour
+		expiredKeys := pointerKeys(ctx, t, satellite.Metainfo.Database)
+		require.Len(t, expiredKeys, 1)
+		agePointer(ctx, t, satellite.Metainfo.Database, expiredKeys[0], 8*24*time.Hour)
+
+		err = uplink.Upload(ctx, satellite, "testbucket", "recent", testrand.Bytes(10*memory.KiB))
+		require.NoError(t, err)
+
+		// an upload which hasn't been committed only has segments without a last segment
+		incompleteKey := storage.Key(storj.JoinPaths(projectID.String(), "s0", "testbucket", "incomplete"))
+		pointerBytes, err := proto.Marshal(&pb.Pointer{
+			Type:           pb.Pointer_INLINE,
+			InlineSegment:  testrand.Bytes(memory.KiB),
+			SegmentSize:    memory.KiB.Int64(),
+			CreationDate:   time.Now().Add(-3 * time.Hour),
+			ExpirationDate: time.Now().Add(time.Hour),
+		})
+		require.NoError(t, err)
+		require.NoError(t, satellite.Metainfo.Database.Put(ctx, incompleteKey, pointerBytes))
+
+		_, err = satellite.Metainfo.Endpoint2.SetBucketLifecycle(ctx, &metainfopb.SetBucketLifecycleRequest{
+			ApiKey: apiKey.SerializeRaw(),
+			Bucket: []byte("testbucket"),
+			Rules: []*metainfopb.LifecycleRule{
+				{Id: "expire", ExpireAfterDays: 7},
+				{Id: "abort", AbortIncompleteUploadsAfterHours: 2},
+			},
+		})
+		require.NoError(t, err)
+
+		keysBefore := pointerKeys(ctx, t, satellite.Metainfo.Database)
+		require.Len(t, keysBefore, 3)
+
+		// a dry run only counts what would be removed
+		dryRun := bucketlifecycle.NewChore(zaptest.NewLogger(t), bucketlifecycle.Config{
+			Interval:    time.Hour,
+			DryRun:      true,
+			MaxRemovals: 1000,
+		}, satellite.Metainfo.Loop, satellite.Metainfo.Service, nil, satellite.DB.SharedSegments(), satellite.Overlay.Service)
+		defer ctx.Check(dryRun.Close)
+		ctx.Go(func() error {
+			return dryRun.Run(ctx)
+		})
+		dryRun.Loop.TriggerWait()
+
+		require.Equal(t, keysBefore, pointerKeys(ctx, t, satellite.Metainfo.Database))
+		expectStats(t, dryRun.RuleStats())
+
+		satellite.BucketLifecycle.Chore.Loop.TriggerWait()
+		expectStats(t, satellite.BucketLifecycle.Chore.RuleStats())
+
+		keysAfter := pointerKeys(ctx, t, satellite.Metainfo.Database)
+		require.Len(t, keysAfter, 1)
+		require.NotEqual(t, expiredKeys[0], keysAfter[0])
+
+		data, err := uplink.Download(ctx, satellite, "testbucket", "recent")
+		require.NoError(t, err)
+		require.Len(t, data, 10*memory.KiB.Int())
+	})
+}
+
+func expectStats(t *testing.T, ruleStats map[bucketlifecycle.RuleKey]bucketlifecycle.RuleStats) {
+	require.Len(t, ruleStats, 2)
+	for key, stats := range ruleStats {
+		switch key.RuleID {
+		case "expire":
+			require.EqualValues(t, 1, stats.ExpiredObjects)
+			require.EqualValues(t, 10*memory.KiB, stats.ExpiredBytes)
+			require.Zero(t, stats.AbortedUploads)
+		case "abort":
+			require.EqualValues(t, 1, stats.AbortedUploads)
+			require.EqualValues(t, memory.KiB, stats.AbortedBytes)
+			require.Zero(t, stats.ExpiredObjects)
+		default:
+			t.Fatalf("unexpected rule %q", key.RuleID)
+		}
+	}
+}
+
+func pointerKeys(ctx *testcontext.Context, t *testing.T, db storage.KeyValueStore) storage.Keys {
+	keys, err := db.List(ctx, nil, -1)
+	require.NoError(t, err)
+	return keys
+}
+
+func agePointer(ctx *testcontext.Context, t *testing.T, db storage.KeyValueStore, key storage.Key, age time.Duration) {
+	pointerBytes, err := db.Get(ctx, key)
+	require.NoError(t, err)
+
+	pointer := &pb.Pointer{}
+	require.NoError(t, proto.Unmarshal(pointerBytes, pointer))
+	pointer.CreationDate = pointer.CreationDate.Add(-age)
+
+	agedBytes, err := proto.Marshal(pointer)
+	require.NoError(t, err)
+	require.NoError(t, db.CompareAndSwap(ctx, key, pointerBytes, agedBytes))
+}
@@ -0,0 +1,166 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package bucketlifecycle
+
+import (
+	"context"
+	"time"
+
+	"github.com/skyrings/skyring-common/tools/uuid"
+
+	"storj.io/common/pb"
+	"storj.io/storj/satellite/metainfo"
+)
+
+// firstSegment is the segment of an upload which is written first, so its
+// creation date is when the upload started. Uploads to buckets with versioning
+// write it as firstVersionSegment until they are committed.
+const (
+	firstSegment        = "s0"
+	firstVersionSegment = "vs0"
+)
+
+// Removal is an expired object or an incomplete upload which a lifecycle rule
+// removes.
+type Removal struct {
+	ProjectID     uuid.UUID
+	BucketName    []byte
+	EncryptedPath []byte
+	RuleID        string
+
+	// Version is the version under which the segments of an upload to a
+	// bucket with versioning are written until the upload is committed.
+	Version int32
+
+	// CreationDate is the creation date of the last segment of an object or
+	// the first segment of an upload. It's used to detect that the object has
+	// been replaced after the metainfo loop saw it.
+	CreationDate time.Time
+}
+
+type bucketKey struct {
+	projectID  uuid.UUID
+	bucketName string
+}
+
+// Evaluator evaluates the lifecycle rules of buckets during the metainfo loop
+// and collects the objects and incomplete uploads the rules remove. At most
+// maxRemovals are collected, the removals beyond it are only counted as
+// postponed, so that they are collected by a later evaluation.
+//
+// Non-current object versions are never removed by lifecycle rules.
+//
+// architecture: Observer
+type Evaluator struct {
+	now         time.Time
+	rules       map[bucketKey][]metainfo.LifecycleRule
+	maxRemovals int
+
+	Expired    []Removal
+	Incomplete []Removal
+	Postponed  int64
+}
+
+// NewEvaluator creates a new evaluator of the lifecycle rules at the time now,
+// which collects at most maxRemovals removals.
+func NewEvaluator(lifecycles []metainfo.BucketLifecycle, now time.Time, maxRemovals int) *Evaluator {
+	rules := make(map[bucketKey][]metainfo.LifecycleRule, len(lifecycles))
+	for _, lifecycle := range lifecycles {
+		key := bucketKey{projectID: lifecycle.ProjectID, bucketName: string(lifecycle.BucketName)}
+		rules[key] = lifecycle.Rules
+	}
+
+	return &Evaluator{
+		now:         now,
+		rules:       rules,
+		maxRemovals: maxRemovals,
+	}
+}
+
+// Object collects the object when a rule of its bucket expires it.
+func (evaluator *Evaluator) Object(ctx context.Context, path metainfo.ScopedPath, pointer *pb.Pointer) (err error) {
+	if path.Version != 0 {
+		return nil
+	}
+
+	encryptedPath := []byte(path.EncryptedObjectPath)
+	for _, rule := range evaluator.bucketRules(path) {
+		if rule.ExpireAfterDays <= 0 || !rule.Matches(encryptedPath) {
+			continue
+		}
+
+		expiration := pointer.CreationDate.Add(time.Duration(rule.ExpireAfterDays) * 24 * time.Hour)
+		if expiration.After(evaluator.now) {
+			continue
+		}
+
+		if evaluator.full() {
+			evaluator.Postponed++
+			return nil
+		}
+		evaluator.Expired = append(evaluator.Expired, newRemoval(path, rule, pointer))
+		return nil
+	}
+	return nil
+}
+
+// RemoteSegment collects the upload when a rule of its bucket aborts it.
+func (evaluator *Evaluator) RemoteSegment(ctx context.Context, path metainfo.ScopedPath, pointer *pb.Pointer) (err error) {
+	evaluator.segment(path, pointer)
+	return nil
+}
+
+// InlineSegment collects the upload when a rule of its bucket aborts it.
+func (evaluator *Evaluator) InlineSegment(ctx context.Context, path metainfo.ScopedPath, pointer *pb.Pointer) (err error) {
+	evaluator.segment(path, pointer)
+	return nil
+}
+
+// segment collects the first segment of an upload which has been started
+// before a rule aborts it. The segment may belong to a committed object or
+// object version, which is checked before the upload is removed.
+func (evaluator *Evaluator) segment(path metainfo.ScopedPath, pointer *pb.Pointer) {
+	if path.Segment != firstSegment && path.Segment != firstVersionSegment {
+		return
+	}
+
+	encryptedPath := []byte(path.EncryptedObjectPath)
+	for _, rule := range evaluator.bucketRules(path) {
+		if rule.AbortIncompleteUploadsAfterHours <= 0 || !rule.Matches(encryptedPath) {
+			continue
+		}
+
+		abortion := pointer.CreationDate.Add(time.Duration(rule.AbortIncompleteUploadsAfterHours) * time.Hour)
+		if abortion.After(evaluator.now) {
+			continue
+		}
+
+		if evaluator.full() {
+			evaluator.Postponed++
+			return
+		}
+		evaluator.Incomplete = append(evaluator.Incomplete, newRemoval(path, rule, pointer))
+		return
+	}
+}
+
+// full returns whether the evaluator collected the maximum number of removals.
+func (evaluator *Evaluator) full() bool {
+	return len(evaluator.Expired)+len(evaluator.Incomplete) >= evaluator.maxRemovals
+}
+
+func (evaluator *Evaluator) bucketRules(path metainfo.ScopedPath) []metainfo.LifecycleRule {
+	return evaluator.rules[bucketKey{projectID: path.ProjectID, bucketName: path.BucketName}]
+}
+
+func newRemoval(path metainfo.ScopedPath, rule metainfo.LifecycleRule, pointer *pb.Pointer) Removal {
+	return Removal{
+		ProjectID:     path.ProjectID,
+		BucketName:    []byte(path.BucketName),
+		EncryptedPath: []byte(path.EncryptedObjectPath),
+		RuleID:        rule.ID,
+		Version:       path.Version,
+		CreationDate:  pointer.CreationDate,
+	}
+}
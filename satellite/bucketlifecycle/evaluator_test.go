@@ -0,0 +1,106 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package bucketlifecycle_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/pb"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/satellite/bucketlifecycle"
+	"storj.io/storj/satellite/metainfo"
+)
+
+func TestEvaluator(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	now := time.Now()
+	projectID := testrand.UUID()
+
+	evaluator := bucketlifecycle.NewEvaluator([]metainfo.BucketLifecycle{
+		{
+			ProjectID:  projectID,
+			BucketName: []byte("bucket"),
+			Rules: []metainfo.LifecycleRule{
+				{ID: "logs", EncryptedPrefix: []byte("logs/"), ExpireAfterDays: 7},
+				{ID: "uploads", AbortIncompleteUploadsAfterHours: 2},
+			},
+		},
+	}, now, 10)
+
+	path := func(segment, bucket, encryptedPath string, version int32) metainfo.ScopedPath {
+		return metainfo.ScopedPath{
+			ProjectID:           projectID,
+			Segment:             segment,
+			BucketName:          bucket,
+			EncryptedObjectPath: encryptedPath,
+			Version:             version,
+		}
+	}
+	createdAgo := func(age time.Duration) *pb.Pointer {
+		return &pb.Pointer{CreationDate: now.Add(-age)}
+	}
+
+	week := 7 * 24 * time.Hour
+	for _, object := range []struct {
+		path    metainfo.ScopedPath
+		pointer *pb.Pointer
+	}{
+		{path("l", "bucket", "logs/expired", 0), createdAgo(week + time.Minute)},
+		{path("l", "bucket", "logs/recent", 0), createdAgo(week - time.Minute)},
+		{path("l", "bucket", "data/old", 0), createdAgo(2 * week)},
+		{path("l", "other", "logs/expired", 0), createdAgo(2 * week)},
+		{path("vl", "bucket", "logs/version", 3), createdAgo(2 * week)},
+	} {
+		require.NoError(t, evaluator.Object(ctx, object.path, object.pointer))
+	}
+
+	for _, segment := range []struct {
+		path    metainfo.ScopedPath
+		pointer *pb.Pointer
+	}{
+		{path("s0", "bucket", "upload/old", 0), createdAgo(3 * time.Hour)},
+		{path("s1", "bucket", "upload/old", 0), createdAgo(3 * time.Hour)},
+		{path("s0", "bucket", "upload/recent", 0), createdAgo(time.Hour)},
+		{path("s0", "other", "upload/old", 0), createdAgo(3 * time.Hour)},
+		{path("vs0", "bucket", "upload/versioned", 4), createdAgo(3 * time.Hour)},
+		{path("vs1", "bucket", "upload/versioned", 4), createdAgo(3 * time.Hour)},
+	} {
+		require.NoError(t, evaluator.RemoteSegment(ctx, segment.path, segment.pointer))
+	}
+
+	require.Len(t, evaluator.Expired, 1)
+	require.Equal(t, "logs", evaluator.Expired[0].RuleID)
+	require.Equal(t, []byte("logs/expired"), evaluator.Expired[0].EncryptedPath)
+	require.Equal(t, []byte("bucket"), evaluator.Expired[0].BucketName)
+
+	require.Len(t, evaluator.Incomplete, 2)
+	require.Equal(t, "uploads", evaluator.Incomplete[0].RuleID)
+	require.Equal(t, []byte("upload/old"), evaluator.Incomplete[0].EncryptedPath)
+	require.Zero(t, evaluator.Incomplete[0].Version)
+
+	// uploads to buckets with versioning are written as a version until they are committed
+	require.Equal(t, []byte("upload/versioned"), evaluator.Incomplete[1].EncryptedPath)
+	require.Equal(t, int32(4), evaluator.Incomplete[1].Version)
+
+	// removals beyond the maximum are postponed to the next evaluation
+	evaluator = bucketlifecycle.NewEvaluator([]metainfo.BucketLifecycle{
+		{
+			ProjectID:  projectID,
+			BucketName: []byte("bucket"),
+			Rules:      []metainfo.LifecycleRule{{ID: "uploads", AbortIncompleteUploadsAfterHours: 2}},
+		},
+	}, now, 2)
+	for i := 0; i < 5; i++ {
+		require.NoError(t, evaluator.RemoteSegment(ctx, path("s0", "bucket", fmt.Sprintf("upload/%d", i), 0), createdAgo(3*time.Hour)))
+	}
+	require.Len(t, evaluator.Incomplete, 2)
+	require.Equal(t, int64(3), evaluator.Postponed)
+}
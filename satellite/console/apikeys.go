@@ -18,7 +18,8 @@ type APIKeys interface {
 	GetPagedByProjectID(ctx context.Context, projectID uuid.UUID, cursor APIKeyCursor) (akp *APIKeyPage, err error)
 	// Get retrieves APIKeyInfo with given ID
 	Get(ctx context.Context, id uuid.UUID) (*APIKeyInfo, error)
-	// GetByHead retrieves APIKeyInfo for given key head
+	// GetByHead retrieves APIKeyInfo for given key head. Keys of projects,
+	// whose owner is frozen, are rejected.
 	GetByHead(ctx context.Context, head []byte) (*APIKeyInfo, error)
 	// GetByNameAndProjectID retrieves APIKeyInfo for given key name and projectID
 	GetByNameAndProjectID(ctx context.Context, name string, projectID uuid.UUID) (*APIKeyInfo, error)
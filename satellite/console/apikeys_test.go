@@ -8,9 +8,11 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"storj.io/common/macaroon"
 	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
 	"storj.io/storj/satellite"
 	"storj.io/storj/satellite/console"
 	"storj.io/storj/satellite/satellitedb/satellitedbtest"
@@ -158,3 +160,48 @@ func TestApiKeysRepository(t *testing.T) {
 
 	})
 }
+
+func TestApiKeysOfFrozenOwner(t *testing.T) {
+	satellitedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db satellite.DB) {
+		consoleDB := db.Console()
+
+		owner, err := consoleDB.Users().Insert(ctx, &console.User{
+			ID:           testrand.UUID(),
+			FullName:     "Project Owner",
+			Email:        "owner@mail.test",
+			PasswordHash: []byte("hash"),
+			Status:       console.Active,
+		})
+		require.NoError(t, err)
+
+		project, err := consoleDB.Projects().Insert(ctx, &console.Project{
+			Name:    "ProjectName",
+			OwnerID: owner.ID,
+		})
+		require.NoError(t, err)
+
+		createKey := func(name string) []byte {
+			key, err := macaroon.NewAPIKey([]byte("testSecret"))
+			require.NoError(t, err)
+			_, err = consoleDB.APIKeys().Create(ctx, key.Head(), console.APIKeyInfo{
+				Name:      name,
+				ProjectID: project.ID,
+				Secret:    []byte("testSecret"),
+			})
+			require.NoError(t, err)
+			return key.Head()
+		}
+
+		head := createKey("active")
+		_, err = consoleDB.APIKeys().GetByHead(ctx, head)
+		require.NoError(t, err)
+
+		owner.Status = console.Frozen
+		require.NoError(t, consoleDB.Users().Update(ctx, owner))
+
+		// the key isn't cached yet
+		head = createKey("frozen")
+		_, err = consoleDB.APIKeys().GetByHead(ctx, head)
+		require.Error(t, err)
+	})
+}
@@ -29,19 +29,21 @@ type Auth struct {
 	LetUsKnowURL          string
 	TermsAndConditionsURL string
 	ContactInfoURL        string
+	SatelliteName         string
 	service               *console.Service
 	mailService           *mailservice.Service
 	cookieAuth            *consolewebauth.CookieAuth
 }
 
 // NewAuth is a constructor for api auth controller.
-func NewAuth(log *zap.Logger, service *console.Service, mailService *mailservice.Service, cookieAuth *consolewebauth.CookieAuth, externalAddress string, letUsKnowURL string, termsAndConditionsURL string, contactInfoURL string) *Auth {
+func NewAuth(log *zap.Logger, service *console.Service, mailService *mailservice.Service, cookieAuth *consolewebauth.CookieAuth, externalAddress string, letUsKnowURL string, termsAndConditionsURL string, contactInfoURL string, satelliteName string) *Auth {
 	return &Auth{
 		log:                   log,
 		ExternalAddress:       externalAddress,
 		LetUsKnowURL:          letUsKnowURL,
 		TermsAndConditionsURL: termsAndConditionsURL,
 		ContactInfoURL:        contactInfoURL,
+		SatelliteName:         satelliteName,
 		service:               service,
 		mailService:           mailService,
 		cookieAuth:            cookieAuth,
@@ -54,10 +56,7 @@ func (a *Auth) Token(w http.ResponseWriter, r *http.Request) {
 	var err error
 	defer mon.Task()(&ctx)(&err)
 
-	var tokenRequest struct {
-		Email    string `json:"email"`
-		Password string `json:"password"`
-	}
+	var tokenRequest console.AuthUser
 
 	err = json.NewDecoder(r.Body).Decode(&tokenRequest)
 	if err != nil {
@@ -65,7 +64,7 @@ func (a *Auth) Token(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, err := a.service.Token(ctx, tokenRequest.Email, tokenRequest.Password)
+	token, err := a.service.Token(ctx, tokenRequest)
 	if err != nil {
 		a.serveJSONError(w, err)
 		return
@@ -194,11 +193,12 @@ func (a *Auth) GetAccount(w http.ResponseWriter, r *http.Request) {
 	defer mon.Task()(&ctx)(&err)
 
 	var user struct {
-		ID        uuid.UUID `json:"id"`
-		FullName  string    `json:"fullName"`
-		ShortName string    `json:"shortName"`
-		Email     string    `json:"email"`
-		PartnerID uuid.UUID `json:"partnerId"`
+		ID         uuid.UUID `json:"id"`
+		FullName   string    `json:"fullName"`
+		ShortName  string    `json:"shortName"`
+		Email      string    `json:"email"`
+		PartnerID  uuid.UUID `json:"partnerId"`
+		MFAEnabled bool      `json:"isMFAEnabled"`
 	}
 
 	auth, err := console.GetAuth(ctx)
@@ -212,6 +212,7 @@ func (a *Auth) GetAccount(w http.ResponseWriter, r *http.Request) {
 	user.Email = auth.User.Email
 	user.ID = auth.User.ID
 	user.PartnerID = auth.User.PartnerID
+	user.MFAEnabled = auth.User.MFAEnabled
 
 	w.Header().Set("Content-Type", "application/json")
 	err = json.NewEncoder(w).Encode(&user)
@@ -372,6 +373,128 @@ func (a *Auth) ResendEmail(w http.ResponseWriter, r *http.Request) {
 	)
 }
 
+// GenerateMFASecretKey creates a new MFA secret key for the user and returns
+// it together with the provisioning URI for the QR code.
+func (a *Auth) GenerateMFASecretKey(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	auth, err := console.GetAuth(ctx)
+	if err != nil {
+		a.serveJSONError(w, err)
+		return
+	}
+
+	key, err := a.service.ResetMFASecretKey(ctx)
+	if err != nil {
+		a.serveJSONError(w, err)
+		return
+	}
+
+	var response struct {
+		SecretKey       string `json:"secretKey"`
+		ProvisioningURI string `json:"provisioningUri"`
+	}
+	response.SecretKey = key
+	response.ProvisioningURI = console.NewMFAProvisioningURI(a.SatelliteName, auth.User.Email, key)
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(&response)
+	if err != nil {
+		a.log.Error("could not encode MFA secret key", zap.Error(ErrAuthAPI.Wrap(err)))
+		return
+	}
+}
+
+// EnableUserMFA enables MFA for the user and returns the recovery codes.
+func (a *Auth) EnableUserMFA(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	var enableRequest struct {
+		Passcode string `json:"passcode"`
+	}
+
+	err = json.NewDecoder(r.Body).Decode(&enableRequest)
+	if err != nil {
+		a.serveJSONError(w, err)
+		return
+	}
+
+	codes, err := a.service.EnableUserMFA(ctx, enableRequest.Passcode)
+	if err != nil {
+		a.serveJSONError(w, err)
+		return
+	}
+
+	a.serveRecoveryCodes(w, codes)
+}
+
+// DisableUserMFA disables MFA for the user.
+func (a *Auth) DisableUserMFA(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	var disableRequest struct {
+		Passcode     string `json:"passcode"`
+		RecoveryCode string `json:"recoveryCode"`
+	}
+
+	err = json.NewDecoder(r.Body).Decode(&disableRequest)
+	if err != nil {
+		a.serveJSONError(w, err)
+		return
+	}
+
+	err = a.service.DisableUserMFA(ctx, disableRequest.Passcode, disableRequest.RecoveryCode)
+	if err != nil {
+		a.serveJSONError(w, err)
+		return
+	}
+}
+
+// GenerateMFARecoveryCodes replaces the recovery codes of the user.
+func (a *Auth) GenerateMFARecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	var codesRequest struct {
+		Passcode string `json:"passcode"`
+	}
+
+	err = json.NewDecoder(r.Body).Decode(&codesRequest)
+	if err != nil {
+		a.serveJSONError(w, err)
+		return
+	}
+
+	codes, err := a.service.ResetMFARecoveryCodes(ctx, codesRequest.Passcode)
+	if err != nil {
+		a.serveJSONError(w, err)
+		return
+	}
+
+	a.serveRecoveryCodes(w, codes)
+}
+
+// serveRecoveryCodes writes the MFA recovery codes to response output stream.
+func (a *Auth) serveRecoveryCodes(w http.ResponseWriter, codes []string) {
+	var response struct {
+		RecoveryCodes []string `json:"recoveryCodes"`
+	}
+	response.RecoveryCodes = codes
+
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(&response)
+	if err != nil {
+		a.log.Error("could not encode MFA recovery codes", zap.Error(ErrAuthAPI.Wrap(err)))
+	}
+}
+
 // serveJSONError writes JSON error to response output stream.
 func (a *Auth) serveJSONError(w http.ResponseWriter, err error) {
 	w.WriteHeader(a.getStatusCode(err))
@@ -391,8 +514,10 @@ func (a *Auth) serveJSONError(w http.ResponseWriter, err error) {
 // getStatusCode returns http.StatusCode depends on console error class.
 func (a *Auth) getStatusCode(err error) int {
 	switch {
-	case console.ErrValidation.Has(err):
+	case console.ErrValidation.Has(err), console.ErrMFAMissing.Has(err):
 		return http.StatusBadRequest
+	case console.ErrMFAConflict.Has(err):
+		return http.StatusConflict
 	case console.ErrUnauthorized.Has(err):
 		return http.StatusUnauthorized
 	default:
@@ -28,6 +28,8 @@ const (
 	AddProjectMembersMutation = "addProjectMembers"
 	// DeleteProjectMembersMutation is a mutation name for deleting project members
 	DeleteProjectMembersMutation = "deleteProjectMembers"
+	// UpdateProjectMemberRoleMutation is a mutation name for changing the role of a project member
+	UpdateProjectMemberRoleMutation = "updateProjectMemberRole"
 
 	// CreateAPIKeyMutation is a mutation name for api key creation
 	CreateAPIKeyMutation = "createAPIKey"
@@ -142,16 +144,26 @@ func rootMutation(log *zap.Logger, service *console.Service, mailService *mailse
 					FieldEmail: &graphql.ArgumentConfig{
 						Type: graphql.NewNonNull(graphql.NewList(graphql.String)),
 					},
+					FieldRole: &graphql.ArgumentConfig{
+						Type:         graphql.String,
+						DefaultValue: console.RoleDeveloper.String(),
+					},
 				},
 				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
 					pID, _ := p.Args[FieldProjectID].(string)
 					emails, _ := p.Args[FieldEmail].([]interface{})
+					roleName, _ := p.Args[FieldRole].(string)
 
 					projectID, err := uuid.Parse(pID)
 					if err != nil {
 						return nil, err
 					}
 
+					role, err := console.ProjectRoleFromString(roleName)
+					if err != nil {
+						return nil, err
+					}
+
 					var userEmails []string
 					for _, email := range emails {
 						userEmails = append(userEmails, email.(string))
@@ -162,7 +174,7 @@ func rootMutation(log *zap.Logger, service *console.Service, mailService *mailse
 						return nil, err
 					}
 
-					users, err := service.AddProjectMembers(p.Context, *projectID, userEmails)
+					users, err := service.AddProjectMembers(p.Context, *projectID, userEmails, role)
 					if err != nil {
 						return nil, err
 					}
@@ -237,6 +249,48 @@ func rootMutation(log *zap.Logger, service *console.Service, mailService *mailse
 					return project, nil
 				},
 			},
+			// change the role of a project member
+			UpdateProjectMemberRoleMutation: &graphql.Field{
+				Type: types.project,
+				Args: graphql.FieldConfigArgument{
+					FieldProjectID: &graphql.ArgumentConfig{
+						Type: graphql.NewNonNull(graphql.String),
+					},
+					FieldEmail: &graphql.ArgumentConfig{
+						Type: graphql.NewNonNull(graphql.String),
+					},
+					FieldRole: &graphql.ArgumentConfig{
+						Type: graphql.NewNonNull(graphql.String),
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					pID, _ := p.Args[FieldProjectID].(string)
+					email, _ := p.Args[FieldEmail].(string)
+					roleName, _ := p.Args[FieldRole].(string)
+
+					projectID, err := uuid.Parse(pID)
+					if err != nil {
+						return nil, err
+					}
+
+					role, err := console.ProjectRoleFromString(roleName)
+					if err != nil {
+						return nil, err
+					}
+
+					_, err = service.UpdateProjectMemberRole(p.Context, *projectID, email, role)
+					if err != nil {
+						return nil, err
+					}
+
+					project, err := service.GetProject(p.Context, *projectID)
+					if err != nil {
+						return nil, err
+					}
+
+					return project, nil
+				},
+			},
 			// creates new api key
 			CreateAPIKeyMutation: &graphql.Field{
 				Type: types.createAPIKey,
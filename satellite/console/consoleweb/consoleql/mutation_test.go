@@ -87,6 +87,7 @@ func TestGrapqhlMutation(t *testing.T) {
 			partnersService,
 			payments.Accounts(),
 			console.TestPasswordCost,
+			console.MFAConfig{},
 		)
 		require.NoError(t, err)
 
@@ -127,7 +128,7 @@ func TestGrapqhlMutation(t *testing.T) {
 		err = service.ActivateAccount(ctx, activationToken)
 		require.NoError(t, err)
 
-		token, err := service.Token(ctx, createUser.Email, createUser.Password)
+		token, err := service.Token(ctx, console.AuthUser{Email: createUser.Email, Password: createUser.Password})
 		require.NoError(t, err)
 
 		sauth, err := service.Authorize(auth.WithAPIKey(ctx, []byte(token)))
@@ -151,7 +152,7 @@ func TestGrapqhlMutation(t *testing.T) {
 			return result.Data
 		}
 
-		token, err = service.Token(ctx, rootUser.Email, createUser.Password)
+		token, err = service.Token(ctx, console.AuthUser{Email: rootUser.Email, Password: createUser.Password})
 		require.NoError(t, err)
 
 		sauth, err = service.Authorize(auth.WithAPIKey(ctx, []byte(token)))
@@ -257,10 +258,11 @@ func TestGrapqhlMutation(t *testing.T) {
 
 		t.Run("Add project members mutation", func(t *testing.T) {
 			query := fmt.Sprintf(
-				"mutation {addProjectMembers(projectID:\"%s\",email:[\"%s\",\"%s\"]){id,name,members(cursor: { limit: 50, search: \"\", page: 1, order: 1, orderDirection: 2 }){projectMembers{joinedAt}}}}",
+				"mutation {addProjectMembers(projectID:\"%s\",email:[\"%s\",\"%s\"],role:\"%s\"){id,name,members(cursor: { limit: 50, search: \"\", page: 1, order: 1, orderDirection: 2 }){projectMembers{joinedAt,role}}}}",
 				project.ID.String(),
 				user1.Email,
 				user2.Email,
+				console.RoleAdmin,
 			)
 
 			result := testQuery(t, query)
@@ -274,6 +276,36 @@ func TestGrapqhlMutation(t *testing.T) {
 			assert.Equal(t, project.ID.String(), proj[consoleql.FieldID])
 			assert.Equal(t, project.Name, proj[consoleql.FieldName])
 			assert.Equal(t, 3, len(projectMembers))
+
+			var admins int
+			for _, projectMember := range projectMembers {
+				if projectMember.(map[string]interface{})[consoleql.FieldRole] == console.RoleAdmin.String() {
+					admins++
+				}
+			}
+			assert.Equal(t, 2, admins)
+		})
+
+		t.Run("Update project member role mutation", func(t *testing.T) {
+			query := fmt.Sprintf(
+				"mutation {updateProjectMemberRole(projectID:\"%s\",email:\"%s\",role:\"%s\"){id,members(cursor: { limit: 50, search: \"%s\", page: 1, order: 1, orderDirection: 2 }){projectMembers{role,user{email}}}}}",
+				project.ID.String(),
+				user1.Email,
+				console.RoleBillingViewer,
+				user1.Email,
+			)
+
+			result := testQuery(t, query)
+
+			data := result.(map[string]interface{})
+			proj := data[consoleql.UpdateProjectMemberRoleMutation].(map[string]interface{})
+
+			members := proj[consoleql.FieldMembers].(map[string]interface{})
+			projectMembers := members[consoleql.FieldProjectMembers].([]interface{})
+			require.Len(t, projectMembers, 1)
+
+			member := projectMembers[0].(map[string]interface{})
+			assert.Equal(t, console.RoleBillingViewer.String(), member[consoleql.FieldRole])
 		})
 
 		t.Run("Delete project members mutation", func(t *testing.T) {
@@ -16,6 +16,8 @@ const (
 	ProjectMemberType = "projectMember"
 	// FieldJoinedAt is a field name for joined at timestamp
 	FieldJoinedAt = "joinedAt"
+	// FieldRole is a field name for the role of a project member
+	FieldRole = "role"
 )
 
 // graphqlProjectMember creates projectMember type
@@ -34,6 +36,9 @@ func graphqlProjectMember(service *console.Service, types *TypeCreator) *graphql
 			FieldJoinedAt: &graphql.Field{
 				Type: graphql.DateTime,
 			},
+			FieldRole: &graphql.Field{
+				Type: graphql.String,
+			},
 		},
 	})
 }
@@ -96,10 +101,11 @@ func graphqlProjectMembersPage(types *TypeCreator) *graphql.Object {
 	})
 }
 
-// projectMember encapsulates User and joinedAt
+// projectMember encapsulates User, joinedAt and role
 type projectMember struct {
 	User     *console.User
 	JoinedAt time.Time
+	Role     string
 }
 
 type projectMembersPage struct {
@@ -72,6 +72,7 @@ func TestGraphqlQuery(t *testing.T) {
 			partnersService,
 			payments.Accounts(),
 			console.TestPasswordCost,
+			console.MFAConfig{},
 		)
 		require.NoError(t, err)
 
@@ -122,7 +123,7 @@ func TestGraphqlQuery(t *testing.T) {
 			rootUser.Email = "mtest@mail.test"
 		})
 
-		token, err := service.Token(ctx, createUser.Email, createUser.Password)
+		token, err := service.Token(ctx, console.AuthUser{Email: createUser.Email, Password: createUser.Password})
 		require.NoError(t, err)
 
 		sauth, err := service.Authorize(auth.WithAPIKey(ctx, []byte(token)))
@@ -224,7 +225,7 @@ func TestGraphqlQuery(t *testing.T) {
 		users, err := service.AddProjectMembers(authCtx, createdProject.ID, []string{
 			user1.Email,
 			user2.Email,
-		})
+		}, console.RoleDeveloper)
 		require.NoError(t, err)
 		assert.Equal(t, 2, len(users))
 
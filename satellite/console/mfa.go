@@ -0,0 +1,173 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package console
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/skyrings/skyring-common/tools/uuid"
+	"github.com/zeebo/errs"
+)
+
+// MFA passcodes are TOTP codes as described in RFC 6238, using the
+// parameters, which are supported by all authenticator apps.
+const (
+	// MFAPasscodeDigits is the number of digits of a passcode.
+	MFAPasscodeDigits = 6
+	// MFAPasscodePeriod is the duration a passcode is valid for.
+	MFAPasscodePeriod = 30 * time.Second
+	// MFARecoveryCodeCount is the number of recovery codes generated at once.
+	MFARecoveryCodeCount = 10
+
+	// mfaSecretKeyLength is the length of the secret key in bytes.
+	mfaSecretKeyLength = 20
+	// mfaRecoveryCodeLength is the length of a recovery code in characters.
+	mfaRecoveryCodeLength = 10
+	// mfaPasscodeSkew is the number of periods before and after the current one,
+	// in which passcodes are accepted, to allow for clock drift.
+	mfaPasscodeSkew = 1
+)
+
+// MFAConfig contains the configuration of two-factor authentication.
+type MFAConfig struct {
+	RequiredForAPIKeys bool          `help:"whether users have to enable two-factor authentication for creating API keys" default:"false"`
+	MaxAttempts        int           `help:"number of failed two-factor authentication attempts, after which the second factor of the user is locked (0 is unlimited)" default:"5"`
+	LockDuration       time.Duration `help:"how long the second factor of a user is locked after too many failed attempts" default:"15m0s"`
+}
+
+// mfaEncoding is used for secret keys and recovery codes.
+var mfaEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewMFASecretKey returns a new random secret key for generating passcodes.
+func NewMFASecretKey() (string, error) {
+	var key [mfaSecretKeyLength]byte
+
+	_, err := rand.Read(key[:])
+	if err != nil {
+		return "", errs.New("error creating MFA secret key")
+	}
+
+	return mfaEncoding.EncodeToString(key[:]), nil
+}
+
+// NewMFARecoveryCodes returns new random one-time recovery codes.
+func NewMFARecoveryCodes() ([]string, error) {
+	codes := make([]string, MFARecoveryCodeCount)
+	for i := range codes {
+		var b [mfaRecoveryCodeLength * 5 / 8]byte
+
+		_, err := rand.Read(b[:])
+		if err != nil {
+			return nil, errs.New("error creating MFA recovery code")
+		}
+
+		codes[i] = mfaEncoding.EncodeToString(b[:])
+	}
+
+	return codes, nil
+}
+
+// NewMFAProvisioningURI returns the URI, which is encoded into a QR code for
+// adding the secret key to an authenticator app.
+func NewMFAProvisioningURI(issuer, email, secretKey string) string {
+	params := url.Values{}
+	params.Set("secret", secretKey)
+	params.Set("issuer", issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprint(MFAPasscodeDigits))
+	params.Set("period", fmt.Sprint(int(MFAPasscodePeriod.Seconds())))
+
+	uri := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + email,
+		RawQuery: params.Encode(),
+	}
+
+	return uri.String()
+}
+
+// NewMFAPasscode returns the passcode for the secret key at time t.
+func NewMFAPasscode(secretKey string, t time.Time) (string, error) {
+	key, err := mfaEncoding.DecodeString(strings.ToUpper(secretKey))
+	if err != nil {
+		return "", errs.New("invalid MFA secret key")
+	}
+
+	return mfaPasscode(key, uint64(t.Unix())/uint64(MFAPasscodePeriod.Seconds())), nil
+}
+
+// ValidateMFAPasscode checks whether the passcode is valid for the secret key at time t.
+func ValidateMFAPasscode(passcode, secretKey string, t time.Time) (bool, error) {
+	_, valid, err := validateMFAPasscode(passcode, secretKey, t)
+	return valid, err
+}
+
+// validateMFAPasscode checks whether the passcode is valid for the secret key
+// at time t and returns the counter of the passcode, so that it can only be
+// used once.
+func validateMFAPasscode(passcode, secretKey string, t time.Time) (counter int64, valid bool, err error) {
+	key, err := mfaEncoding.DecodeString(strings.ToUpper(secretKey))
+	if err != nil {
+		return 0, false, errs.New("invalid MFA secret key")
+	}
+
+	passcode = strings.TrimSpace(passcode)
+	if len(passcode) != MFAPasscodeDigits {
+		return 0, false, nil
+	}
+
+	current := uint64(t.Unix()) / uint64(MFAPasscodePeriod.Seconds())
+	for skew := -mfaPasscodeSkew; skew <= mfaPasscodeSkew; skew++ {
+		expected := mfaPasscode(key, current+uint64(skew))
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(passcode)) == 1 {
+			return int64(current) + int64(skew), true, nil
+		}
+	}
+
+	return 0, false, nil
+}
+
+// hashMFARecoveryCode returns the hash of a recovery code of the user, which
+// is stored instead of the recovery code. The recovery codes are random, so
+// they don't need a slow password hash.
+func hashMFARecoveryCode(userID uuid.UUID, code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+
+	hash := sha256.New()
+	_, _ = hash.Write(userID[:])
+	_, _ = hash.Write([]byte(code))
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// mfaPasscode computes the HOTP value of the key for the counter, as described in RFC 4226.
+func mfaPasscode(key []byte, counter uint64) string {
+	var message [8]byte
+	binary.BigEndian.PutUint64(message[:], counter)
+
+	mac := hmac.New(sha1.New, key)
+	_, _ = mac.Write(message[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0xf
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for i := 0; i < MFAPasscodeDigits; i++ {
+		modulo *= 10
+	}
+
+	return fmt.Sprintf("%0*d", MFAPasscodeDigits, value%modulo)
+}
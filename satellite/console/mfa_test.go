@@ -0,0 +1,99 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package console_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/satellite/console"
+)
+
+// rfcSecretKey is the base32 encoded secret "12345678901234567890" used by the
+// SHA1 test vectors of RFC 6238.
+const rfcSecretKey = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestMFAPasscode(t *testing.T) {
+	for _, test := range []struct {
+		unix     int64
+		passcode string
+	}{
+		{unix: 59, passcode: "287082"},
+		{unix: 1111111109, passcode: "081804"},
+		{unix: 1111111111, passcode: "050471"},
+		{unix: 1234567890, passcode: "005924"},
+		{unix: 2000000000, passcode: "279037"},
+		{unix: 20000000000, passcode: "353130"},
+	} {
+		passcode, err := console.NewMFAPasscode(rfcSecretKey, time.Unix(test.unix, 0))
+		require.NoError(t, err)
+		assert.Equal(t, test.passcode, passcode, test.unix)
+	}
+
+	_, err := console.NewMFAPasscode("not base32!", time.Now())
+	require.Error(t, err)
+}
+
+func TestValidateMFAPasscode(t *testing.T) {
+	key, err := console.NewMFASecretKey()
+	require.NoError(t, err)
+
+	now := time.Now()
+	passcode, err := console.NewMFAPasscode(key, now)
+	require.NoError(t, err)
+
+	for _, test := range []struct {
+		time  time.Time
+		valid bool
+	}{
+		{time: now, valid: true},
+		{time: now.Add(console.MFAPasscodePeriod), valid: true},
+		{time: now.Add(-console.MFAPasscodePeriod), valid: true},
+		{time: now.Add(3 * console.MFAPasscodePeriod), valid: false},
+		{time: now.Add(-3 * console.MFAPasscodePeriod), valid: false},
+	} {
+		valid, err := console.ValidateMFAPasscode(passcode, key, test.time)
+		require.NoError(t, err)
+		assert.Equal(t, test.valid, valid, test.time.Sub(now))
+	}
+
+	valid, err := console.ValidateMFAPasscode("12345", key, now)
+	require.NoError(t, err)
+	assert.False(t, valid)
+
+	_, err = console.ValidateMFAPasscode(passcode, "not base32!", now)
+	require.Error(t, err)
+}
+
+func TestNewMFARecoveryCodes(t *testing.T) {
+	codes, err := console.NewMFARecoveryCodes()
+	require.NoError(t, err)
+	require.Len(t, codes, console.MFARecoveryCodeCount)
+
+	unique := map[string]bool{}
+	for _, code := range codes {
+		assert.Len(t, code, 10)
+		unique[code] = true
+	}
+	assert.Len(t, unique, len(codes))
+}
+
+func TestNewMFAProvisioningURI(t *testing.T) {
+	uri, err := url.Parse(console.NewMFAProvisioningURI("Storj", "user@mail.test", rfcSecretKey))
+	require.NoError(t, err)
+
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/Storj:user@mail.test", uri.Path)
+
+	query := uri.Query()
+	assert.Equal(t, rfcSecretKey, query.Get("secret"))
+	assert.Equal(t, "Storj", query.Get("issuer"))
+	assert.Equal(t, "6", query.Get("digits"))
+	assert.Equal(t, "30", query.Get("period"))
+}
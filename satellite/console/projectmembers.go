@@ -19,7 +19,9 @@ type ProjectMembers interface {
 	// GetPagedByProjectID is a method for querying project members from the database by projectID and cursor
 	GetPagedByProjectID(ctx context.Context, projectID uuid.UUID, cursor ProjectMembersCursor) (*ProjectMembersPage, error)
 	// Insert is a method for inserting project member into the database.
-	Insert(ctx context.Context, memberID, projectID uuid.UUID) (*ProjectMember, error)
+	Insert(ctx context.Context, memberID, projectID uuid.UUID, role ProjectRole) (*ProjectMember, error)
+	// UpdateRole is a method for changing the role of a project member.
+	UpdateRole(ctx context.Context, memberID, projectID uuid.UUID, role ProjectRole) (*ProjectMember, error)
 	// Delete is a method for deleting project member by memberID and projectID from the database.
 	Delete(ctx context.Context, memberID, projectID uuid.UUID) error
 }
@@ -30,10 +32,88 @@ type ProjectMember struct {
 	MemberID uuid.UUID
 	// FK on Projects table.
 	ProjectID uuid.UUID
+	// Role defines what the member is allowed to do in the project.
+	Role ProjectRole
 
 	CreatedAt time.Time
 }
 
+// ProjectRole is the role of a member in a project.
+type ProjectRole int
+
+const (
+	// RoleOwner is the role of the user who created the project, it allows everything.
+	RoleOwner ProjectRole = 1
+	// RoleAdmin allows managing the project, its members, API keys and seeing billing.
+	RoleAdmin ProjectRole = 2
+	// RoleDeveloper allows managing API keys.
+	RoleDeveloper ProjectRole = 3
+	// RoleBillingViewer allows seeing the usage and charges of the project.
+	RoleBillingViewer ProjectRole = 4
+)
+
+// ProjectPermission is an action, which is allowed only for some project roles.
+type ProjectPermission int
+
+const (
+	// PermissionView allows seeing the project, its members and limits.
+	PermissionView ProjectPermission = iota
+	// PermissionManageProject allows changing the project and adding or removing members.
+	PermissionManageProject
+	// PermissionManageRoles allows changing the roles of members.
+	PermissionManageRoles
+	// PermissionDeleteProject allows deleting the project.
+	PermissionDeleteProject
+	// PermissionManageAPIKeys allows listing, creating and deleting API keys.
+	PermissionManageAPIKeys
+	// PermissionViewBilling allows seeing the usage and charges of the project.
+	PermissionViewBilling
+)
+
+// String returns the name of the role.
+func (role ProjectRole) String() string {
+	switch role {
+	case RoleOwner:
+		return "owner"
+	case RoleAdmin:
+		return "admin"
+	case RoleDeveloper:
+		return "developer"
+	case RoleBillingViewer:
+		return "billingViewer"
+	default:
+		return "unknown"
+	}
+}
+
+// ProjectRoleFromString parses the name of a role.
+func ProjectRoleFromString(name string) (ProjectRole, error) {
+	for _, role := range []ProjectRole{RoleOwner, RoleAdmin, RoleDeveloper, RoleBillingViewer} {
+		if role.String() == name {
+			return role, nil
+		}
+	}
+	return 0, ErrValidation.New("unknown project role %q", name)
+}
+
+// Allows returns whether the role allows the permission.
+func (role ProjectRole) Allows(permission ProjectPermission) bool {
+	switch permission {
+	case PermissionView:
+		return role >= RoleOwner && role <= RoleBillingViewer
+	case PermissionManageProject:
+		return role == RoleOwner || role == RoleAdmin
+	case PermissionManageRoles, PermissionDeleteProject:
+		return role == RoleOwner
+	case PermissionManageAPIKeys:
+		return role == RoleOwner || role == RoleAdmin || role == RoleDeveloper
+	case PermissionViewBilling:
+		return role == RoleOwner || role == RoleAdmin || role == RoleBillingViewer
+	default:
+		return false
+	}
+}
+
 // ProjectMembersCursor holds info for project members cursor pagination
 type ProjectMembersCursor struct {
 	Search         string
@@ -38,14 +38,27 @@ const (
 
 // Error messages
 const (
-	unauthorizedErrMsg                   = "You are not authorized to perform this action"
-	emailUsedErrMsg                      = "This email is already in use, try another"
-	passwordRecoveryTokenIsExpiredErrMsg = "Your password recovery link has expired, please request another one"
-	credentialsErrMsg                    = "Your email or password was incorrect, please try again"
-	passwordIncorrectErrMsg              = "Your password needs at least %d characters long"
-	projectOwnerDeletionForbiddenErrMsg  = "%s is a project owner and can not be deleted"
-	apiKeyWithNameExistsErrMsg           = "An API Key with this name already exists in this project, please use a different name"
-	teamMemberDoesNotExistErrMsg         = `There is no account on this Satellite for the user(s) you have entered.
+	unauthorizedErrMsg                    = "You are not authorized to perform this action"
+	emailUsedErrMsg                       = "This email is already in use, try another"
+	passwordRecoveryTokenIsExpiredErrMsg  = "Your password recovery link has expired, please request another one"
+	credentialsErrMsg                     = "Your email or password was incorrect, please try again"
+	accountFrozenErrMsg                   = "Your account has been frozen, please contact support"
+	mfaRequiredErrMsg                     = "A passcode or recovery code is required for two-factor authentication"
+	mfaPasscodeInvalidErrMsg              = "The two-factor authentication passcode is invalid, please try again"
+	mfaRecoveryCodeInvalidErrMsg          = "The two-factor authentication recovery code is invalid"
+	mfaEnabledErrMsg                      = "Two-factor authentication is already enabled"
+	mfaDisabledErrMsg                     = "Two-factor authentication is not enabled"
+	mfaSecretKeyMissingErrMsg             = "A two-factor authentication secret key has to be generated first"
+	mfaLockedErrMsg                       = "Too many failed two-factor authentication attempts, please try again later"
+	mfaChangedErrMsg                      = "The two-factor authentication secret key has been changed, please try again"
+	mfaRequiredForAPIKeysErrMsg           = "Two-factor authentication has to be enabled for creating API keys"
+	passwordIncorrectErrMsg               = "Your password needs at least %d characters long"
+	projectOwnerDeletionForbiddenErrMsg   = "%s is a project owner and can not be deleted"
+	projectOwnerRoleChangeForbiddenErrMsg = "%s is a project owner and the role can not be changed"
+	projectRoleForbiddenErrMsg            = "Your role %s in this project does not allow this action"
+	projectRoleInvalidErrMsg              = "The project role is invalid"
+	apiKeyWithNameExistsErrMsg            = "An API Key with this name already exists in this project, please use a different name"
+	teamMemberDoesNotExistErrMsg          = `There is no account on this Satellite for the user(s) you have entered.
 									     Please add team members with active accounts`
 
 	// TODO: remove after vanguard release
@@ -65,6 +78,12 @@ var ErrTokenExpiration = errs.Class("token expiration error")
 // ErrProjLimit is error type of project limit.
 var ErrProjLimit = errs.Class("project limit error")
 
+// ErrMFAMissing is error type of a missing second factor for a user with MFA enabled.
+var ErrMFAMissing = errs.Class("MFA credentials missing")
+
+// ErrMFAConflict is error type of a change, which doesn't match the MFA state of the user.
+var ErrMFAConflict = errs.Class("MFA conflict")
+
 // Service is handling accounts related logic
 //
 // architecture: Service
@@ -80,6 +99,7 @@ type Service struct {
 	accounts          payments.Accounts
 
 	passwordCost int
+	mfa          MFAConfig
 }
 
 // PaymentsService separates all payment related functionality
@@ -88,7 +108,7 @@ type PaymentsService struct {
 }
 
 // NewService returns new instance of Service.
-func NewService(log *zap.Logger, signer Signer, store DB, projectAccounting accounting.ProjectAccounting, projectUsage *accounting.Service, rewards rewards.DB, partners *rewards.PartnersService, accounts payments.Accounts, passwordCost int) (*Service, error) {
+func NewService(log *zap.Logger, signer Signer, store DB, projectAccounting accounting.ProjectAccounting, projectUsage *accounting.Service, rewards rewards.DB, partners *rewards.PartnersService, accounts payments.Accounts, passwordCost int, mfa MFAConfig) (*Service, error) {
 	if signer == nil {
 		return nil, errs.New("signer can't be nil")
 	}
@@ -112,6 +132,7 @@ func NewService(log *zap.Logger, signer Signer, store DB, projectAccounting acco
 		partners:          partners,
 		accounts:          accounts,
 		passwordCost:      passwordCost,
+		mfa:               mfa,
 	}, nil
 }
 
@@ -530,6 +551,10 @@ func (s *Service) ActivateAccount(ctx context.Context, activationToken string) (
 		return errs.New("account is already active")
 	}
 
+	if user.Status == Frozen {
+		return ErrUnauthorized.New(accountFrozenErrMsg)
+	}
+
 	if now.After(user.CreatedAt.Add(tokenExpirationTime)) {
 		return ErrTokenExpiration.Wrap(err)
 	}
@@ -606,15 +631,15 @@ func (s *Service) RevokeResetPasswordToken(ctx context.Context, resetPasswordTok
 }
 
 // Token authenticates User by credentials and returns auth token
-func (s *Service) Token(ctx context.Context, email, password string) (token string, err error) {
+func (s *Service) Token(ctx context.Context, request AuthUser) (token string, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	user, err := s.store.Users().GetByEmail(ctx, email)
+	user, err := s.store.Users().GetByEmail(ctx, request.Email)
 	if err != nil {
 		return "", ErrUnauthorized.New(credentialsErrMsg)
 	}
 
-	err = bcrypt.CompareHashAndPassword(user.PasswordHash, []byte(password))
+	err = bcrypt.CompareHashAndPassword(user.PasswordHash, []byte(request.Password))
 	if err != nil {
 		if err == bcrypt.ErrMismatchedHashAndPassword {
 			return "", ErrUnauthorized.New(credentialsErrMsg)
@@ -623,6 +648,17 @@ func (s *Service) Token(ctx context.Context, email, password string) (token stri
 		return "", Error.Wrap(err)
 	}
 
+	if user.Status == Frozen {
+		return "", ErrUnauthorized.New(accountFrozenErrMsg)
+	}
+
+	if user.MFAEnabled {
+		err = s.verifyMFA(ctx, user, request.MFAPasscode, request.MFARecoveryCode)
+		if err != nil {
+			return "", err
+		}
+	}
+
 	claims := consoleauth.Claims{
 		ID:         user.ID,
 		Expiration: time.Now().Add(tokenExpirationTime),
@@ -660,6 +696,63 @@ func (s *Service) GetUserByEmail(ctx context.Context, email string) (u *User, er
 	return result, nil
 }
 
+// FreezeUser blocks the account of the user, until it's unfrozen. The API
+// keys of the projects owned by the user are rejected as well, once they have
+// expired from the API key cache.
+// It's used by the satellite administration and doesn't authorize the caller.
+func (s *Service) FreezeUser(ctx context.Context, id uuid.UUID) (u *User, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	user, err := s.store.Users().Get(ctx, id)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	user.Status = Frozen
+	err = s.store.Users().Update(ctx, user)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	return user, nil
+}
+
+// UnfreezeUser activates the account of a frozen user.
+// It's used by the satellite administration and doesn't authorize the caller.
+func (s *Service) UnfreezeUser(ctx context.Context, id uuid.UUID) (u *User, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	user, err := s.store.Users().Get(ctx, id)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	if user.Status != Frozen {
+		return nil, Error.New("account is not frozen")
+	}
+
+	user.Status = Active
+	err = s.store.Users().Update(ctx, user)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	return user, nil
+}
+
+// GetUserProjects returns the projects of the user.
+// It's used by the satellite administration and doesn't authorize the caller.
+func (s *Service) GetUserProjects(ctx context.Context, userID uuid.UUID) (ps []Project, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	projects, err := s.store.Projects().GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	return projects, nil
+}
+
 // UpdateAccount updates User
 func (s *Service) UpdateAccount(ctx context.Context, fullName string, shortName string) (err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -724,6 +817,240 @@ func (s *Service) ChangePassword(ctx context.Context, pass, newPass string) (err
 	return nil
 }
 
+// ResetMFASecretKey generates a new MFA secret key for the user. MFA is only
+// enabled after a passcode generated from the key has been verified.
+func (s *Service) ResetMFASecretKey(ctx context.Context) (key string, err error) {
+	defer mon.Task()(&ctx)(&err)
+	auth, err := GetAuth(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if auth.User.MFAEnabled {
+		return "", ErrMFAConflict.New(mfaEnabledErrMsg)
+	}
+
+	key, err = NewMFASecretKey()
+	if err != nil {
+		return "", Error.Wrap(err)
+	}
+
+	stored, err := s.store.Users().SetMFASecretKey(ctx, auth.User.ID, key)
+	if err != nil {
+		return "", Error.Wrap(err)
+	}
+	if !stored {
+		return "", ErrMFAConflict.New(mfaEnabledErrMsg)
+	}
+
+	return key, nil
+}
+
+// EnableUserMFA enables MFA for the user, after verifying a passcode generated
+// from the secret key. It returns the recovery codes of the user, only their
+// hashes are stored.
+func (s *Service) EnableUserMFA(ctx context.Context, passcode string) (codes []string, err error) {
+	defer mon.Task()(&ctx)(&err)
+	auth, err := GetAuth(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if auth.User.MFAEnabled {
+		return nil, ErrMFAConflict.New(mfaEnabledErrMsg)
+	}
+	if auth.User.MFASecretKey == "" {
+		return nil, ErrMFAConflict.New(mfaSecretKeyMissingErrMsg)
+	}
+
+	counter, valid, err := validateMFAPasscode(passcode, auth.User.MFASecretKey, time.Now())
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	if valid {
+		// the passcode can't be used again for logging in
+		valid, err = s.store.Users().UseMFAPasscode(ctx, auth.User.ID, counter)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+	}
+	if !valid {
+		return nil, ErrValidation.New(mfaPasscodeInvalidErrMsg)
+	}
+
+	codes, err = NewMFARecoveryCodes()
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	// the secret key doesn't match anymore, when it's reset concurrently
+	enabled, err := s.store.Users().EnableMFA(ctx, auth.User.ID, auth.User.MFASecretKey, hashMFARecoveryCodes(auth.User.ID, codes))
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	if !enabled {
+		return nil, ErrMFAConflict.New(mfaChangedErrMsg)
+	}
+
+	return codes, nil
+}
+
+// DisableUserMFA disables MFA for the user, after verifying a passcode or a recovery code.
+func (s *Service) DisableUserMFA(ctx context.Context, passcode, recoveryCode string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	auth, err := GetAuth(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !auth.User.MFAEnabled {
+		return ErrMFAConflict.New(mfaDisabledErrMsg)
+	}
+
+	err = s.verifyMFA(ctx, &auth.User, passcode, recoveryCode)
+	if err != nil {
+		return err
+	}
+
+	err = s.store.Users().DisableMFA(ctx, auth.User.ID)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	return nil
+}
+
+// ResetMFARecoveryCodes replaces the recovery codes of the user, after verifying a passcode.
+func (s *Service) ResetMFARecoveryCodes(ctx context.Context, passcode string) (codes []string, err error) {
+	defer mon.Task()(&ctx)(&err)
+	auth, err := GetAuth(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !auth.User.MFAEnabled {
+		return nil, ErrMFAConflict.New(mfaDisabledErrMsg)
+	}
+
+	err = s.verifyMFA(ctx, &auth.User, passcode, "")
+	if err != nil {
+		return nil, err
+	}
+
+	codes, err = NewMFARecoveryCodes()
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	replaced, err := s.store.Users().SetMFARecoveryCodes(ctx, auth.User.ID, hashMFARecoveryCodes(auth.User.ID, codes))
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	if !replaced {
+		return nil, ErrMFAConflict.New(mfaDisabledErrMsg)
+	}
+
+	return codes, nil
+}
+
+// ResetUserMFA disables MFA for a user, who has lost access to the second factor.
+// It's used by the satellite administration and doesn't authorize the caller.
+func (s *Service) ResetUserMFA(ctx context.Context, id uuid.UUID) (u *User, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	user, err := s.store.Users().Get(ctx, id)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	err = s.store.Users().DisableMFA(ctx, user.ID)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	user.MFAEnabled = false
+	user.MFASecretKey = ""
+	user.MFARecoveryCodes = nil
+
+	return user, nil
+}
+
+// verifyMFA checks the passcode or, when no passcode is provided, the recovery
+// code of a user with MFA enabled. Passcodes and recovery codes are accepted
+// only once, and the second factor is locked after too many failed attempts.
+func (s *Service) verifyMFA(ctx context.Context, user *User, passcode, recoveryCode string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if passcode == "" && recoveryCode == "" {
+		return ErrMFAMissing.New(mfaRequiredErrMsg)
+	}
+
+	// the attempt is counted before it's checked, so that concurrent attempts
+	// can't exceed the limit, an accepted attempt resets the count
+	now := time.Now()
+	if s.mfa.MaxAttempts > 0 {
+		counted, err := s.store.Users().CountMFAAttempt(ctx, user.ID, now, s.mfa.LockDuration, s.mfa.MaxAttempts)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		if !counted {
+			return ErrUnauthorized.New(mfaLockedErrMsg)
+		}
+	}
+
+	if passcode != "" {
+		counter, valid, err := validateMFAPasscode(passcode, user.MFASecretKey, now)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		if valid {
+			// a passcode, which has already been accepted, is rejected
+			valid, err = s.store.Users().UseMFAPasscode(ctx, user.ID, counter)
+			if err != nil {
+				return Error.Wrap(err)
+			}
+		}
+		if !valid {
+			return ErrUnauthorized.New(mfaPasscodeInvalidErrMsg)
+		}
+
+		user.MFAPasscodeCounter = counter
+		user.MFAFailedAttempts = 0
+		return nil
+	}
+
+	hash := hashMFARecoveryCode(user.ID, recoveryCode)
+	for i, code := range user.MFARecoveryCodes {
+		if subtle.ConstantTimeCompare([]byte(code), []byte(hash)) != 1 {
+			continue
+		}
+
+		// the recovery codes don't match anymore, when the code is used concurrently
+		remaining := append(user.MFARecoveryCodes[:i:i], user.MFARecoveryCodes[i+1:]...)
+		used, err := s.store.Users().UseMFARecoveryCode(ctx, user.ID, user.MFARecoveryCodes, remaining)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		if !used {
+			break
+		}
+
+		user.MFARecoveryCodes = remaining
+		user.MFAFailedAttempts = 0
+		return nil
+	}
+
+	return ErrUnauthorized.New(mfaRecoveryCodeInvalidErrMsg)
+}
+
+// hashMFARecoveryCodes returns the hashes of the recovery codes of the user.
+func hashMFARecoveryCodes(userID uuid.UUID, codes []string) []string {
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i] = hashMFARecoveryCode(userID, code)
+	}
+	return hashes
+}
+
 // DeleteAccount deletes User
 func (s *Service) DeleteAccount(ctx context.Context, password string) (err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -752,17 +1079,17 @@ func (s *Service) DeleteAccount(ctx context.Context, password string) (err error
 // GetProject is a method for querying project by id
 func (s *Service) GetProject(ctx context.Context, projectID uuid.UUID) (p *Project, err error) {
 	defer mon.Task()(&ctx)(&err)
-	_, err = GetAuth(ctx)
+	auth, err := GetAuth(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	p, err = s.store.Projects().Get(ctx, projectID)
+	isMember, err := s.checkProjectPermission(ctx, auth.User.ID, projectID, PermissionView)
 	if err != nil {
-		return nil, Error.Wrap(err)
+		return nil, ErrUnauthorized.Wrap(err)
 	}
 
-	return
+	return isMember.project, nil
 }
 
 // GetUsersProjects is a method for querying all projects
@@ -842,7 +1169,7 @@ func (s *Service) CreateProject(ctx context.Context, projectInfo ProjectInfo) (p
 			return Error.Wrap(err)
 		}
 
-		_, err = tx.ProjectMembers().Insert(ctx, auth.User.ID, p.ID)
+		_, err = tx.ProjectMembers().Insert(ctx, auth.User.ID, p.ID, RoleOwner)
 		if err != nil {
 			return Error.Wrap(err)
 		}
@@ -879,7 +1206,7 @@ func (s *Service) DeleteProject(ctx context.Context, projectID uuid.UUID) (err e
 		return err
 	}
 
-	if _, err = s.isProjectMember(ctx, auth.User.ID, projectID); err != nil {
+	if _, err = s.checkProjectPermission(ctx, auth.User.ID, projectID, PermissionDeleteProject); err != nil {
 		return ErrUnauthorized.Wrap(err)
 	}
 
@@ -899,7 +1226,7 @@ func (s *Service) UpdateProject(ctx context.Context, projectID uuid.UUID, descri
 		return nil, err
 	}
 
-	isMember, err := s.isProjectMember(ctx, auth.User.ID, projectID)
+	isMember, err := s.checkProjectPermission(ctx, auth.User.ID, projectID, PermissionManageProject)
 	if err != nil {
 		if ErrUnauthorized.Has(err) {
 			return nil, ErrUnauthorized.Wrap(err)
@@ -919,15 +1246,21 @@ func (s *Service) UpdateProject(ctx context.Context, projectID uuid.UUID, descri
 	return project, nil
 }
 
-// AddProjectMembers adds users by email to given project
-func (s *Service) AddProjectMembers(ctx context.Context, projectID uuid.UUID, emails []string) (users []*User, err error) {
+// AddProjectMembers adds users by email to given project with the given role.
+// Only members that can change roles may add members with a role other than developer.
+func (s *Service) AddProjectMembers(ctx context.Context, projectID uuid.UUID, emails []string, role ProjectRole) (users []*User, err error) {
 	defer mon.Task()(&ctx)(&err)
 	auth, err := GetAuth(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	if _, err = s.isProjectMember(ctx, auth.User.ID, projectID); err != nil {
+	permission := PermissionManageProject
+	if role != RoleDeveloper {
+		permission = PermissionManageRoles
+	}
+
+	if _, err = s.checkProjectPermission(ctx, auth.User.ID, projectID, permission); err != nil {
 		if ErrUnauthorized.Has(err) {
 			return nil, ErrUnauthorized.Wrap(err)
 		}
@@ -935,6 +1268,10 @@ func (s *Service) AddProjectMembers(ctx context.Context, projectID uuid.UUID, em
 		return nil, Error.Wrap(err)
 	}
 
+	if role == RoleOwner || !role.Allows(PermissionView) {
+		return nil, ErrValidation.New(projectRoleInvalidErrMsg)
+	}
+
 	var userErr errs.Group
 
 	// collect user querying errors
@@ -955,7 +1292,7 @@ func (s *Service) AddProjectMembers(ctx context.Context, projectID uuid.UUID, em
 	// add project members in transaction scope
 	err = s.store.WithTx(ctx, func(ctx context.Context, tx DBTx) error {
 		for _, user := range users {
-			if _, err := tx.ProjectMembers().Insert(ctx, user.ID, projectID); err != nil {
+			if _, err := tx.ProjectMembers().Insert(ctx, user.ID, projectID, role); err != nil {
 				return err
 			}
 		}
@@ -971,11 +1308,15 @@ func (s *Service) AddProjectMembers(ctx context.Context, projectID uuid.UUID, em
 // DeleteProjectMembers removes users by email from given project
 func (s *Service) DeleteProjectMembers(ctx context.Context, projectID uuid.UUID, emails []string) (err error) {
 	defer mon.Task()(&ctx)(&err)
-	_, err = GetAuth(ctx)
+	auth, err := GetAuth(ctx)
 	if err != nil {
 		return err
 	}
 
+	if _, err = s.checkProjectPermission(ctx, auth.User.ID, projectID, PermissionManageProject); err != nil {
+		return ErrUnauthorized.Wrap(err)
+	}
+
 	var userIDs []uuid.UUID
 	var userErr errs.Group
 
@@ -1018,6 +1359,49 @@ func (s *Service) DeleteProjectMembers(ctx context.Context, projectID uuid.UUID,
 	return Error.Wrap(err)
 }
 
+// UpdateProjectMemberRole changes the role of the project member with the given email.
+// Only the project owner is allowed to change roles and the owner role can't be transferred.
+func (s *Service) UpdateProjectMemberRole(ctx context.Context, projectID uuid.UUID, email string, role ProjectRole) (_ *ProjectMember, err error) {
+	defer mon.Task()(&ctx)(&err)
+	auth, err := GetAuth(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = s.checkProjectPermission(ctx, auth.User.ID, projectID, PermissionManageRoles); err != nil {
+		return nil, ErrUnauthorized.Wrap(err)
+	}
+
+	if role == RoleOwner || !role.Allows(PermissionView) {
+		return nil, ErrValidation.New(projectRoleInvalidErrMsg)
+	}
+
+	user, err := s.store.Users().GetByEmail(ctx, email)
+	if err != nil {
+		return nil, errs.New(teamMemberDoesNotExistErrMsg)
+	}
+
+	isMember, err := s.isProjectMember(ctx, user.ID, projectID)
+	if err != nil {
+		if ErrNoMembership.Has(err) {
+			return nil, ErrValidation.New(teamMemberDoesNotExistErrMsg)
+		}
+
+		return nil, Error.Wrap(err)
+	}
+
+	if isMember.project.OwnerID == user.ID {
+		return nil, ErrValidation.New(projectOwnerRoleChangeForbiddenErrMsg, user.Email)
+	}
+
+	member, err := s.store.ProjectMembers().UpdateRole(ctx, user.ID, projectID, role)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	return member, nil
+}
+
 // GetProjectMembers returns ProjectMembers for given Project
 func (s *Service) GetProjectMembers(ctx context.Context, projectID uuid.UUID, cursor ProjectMembersCursor) (pmp *ProjectMembersPage, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -1056,7 +1440,11 @@ func (s *Service) CreateAPIKey(ctx context.Context, projectID uuid.UUID, name st
 		return nil, nil, err
 	}
 
-	_, err = s.isProjectMember(ctx, auth.User.ID, projectID)
+	if s.mfa.RequiredForAPIKeys && !auth.User.MFAEnabled {
+		return nil, nil, ErrUnauthorized.New(mfaRequiredForAPIKeysErrMsg)
+	}
+
+	_, err = s.checkProjectPermission(ctx, auth.User.ID, projectID, PermissionManageAPIKeys)
 	if err != nil {
 		return nil, nil, ErrUnauthorized.Wrap(err)
 	}
@@ -1105,7 +1493,7 @@ func (s *Service) GetAPIKeyInfo(ctx context.Context, id uuid.UUID) (_ *APIKeyInf
 		return nil, Error.Wrap(err)
 	}
 
-	_, err = s.isProjectMember(ctx, auth.User.ID, key.ProjectID)
+	_, err = s.checkProjectPermission(ctx, auth.User.ID, key.ProjectID, PermissionManageAPIKeys)
 	if err != nil {
 		return nil, ErrUnauthorized.Wrap(err)
 	}
@@ -1130,7 +1518,7 @@ func (s *Service) DeleteAPIKeys(ctx context.Context, ids []uuid.UUID) (err error
 			continue
 		}
 
-		_, err = s.isProjectMember(ctx, auth.User.ID, key.ProjectID)
+		_, err = s.checkProjectPermission(ctx, auth.User.ID, key.ProjectID, PermissionManageAPIKeys)
 		if err != nil {
 			keysErr.Add(ErrUnauthorized.Wrap(err))
 			continue
@@ -1163,7 +1551,7 @@ func (s *Service) GetAPIKeys(ctx context.Context, projectID uuid.UUID, cursor AP
 		return nil, err
 	}
 
-	_, err = s.isProjectMember(ctx, auth.User.ID, projectID)
+	_, err = s.checkProjectPermission(ctx, auth.User.ID, projectID, PermissionManageAPIKeys)
 	if err != nil {
 		return nil, ErrUnauthorized.Wrap(err)
 	}
@@ -1189,7 +1577,7 @@ func (s *Service) GetProjectUsage(ctx context.Context, projectID uuid.UUID, sinc
 		return nil, err
 	}
 
-	_, err = s.isProjectMember(ctx, auth.User.ID, projectID)
+	_, err = s.checkProjectPermission(ctx, auth.User.ID, projectID, PermissionViewBilling)
 	if err != nil {
 		return nil, ErrUnauthorized.Wrap(err)
 	}
@@ -1211,7 +1599,7 @@ func (s *Service) GetBucketTotals(ctx context.Context, projectID uuid.UUID, curs
 		return nil, err
 	}
 
-	isMember, err := s.isProjectMember(ctx, auth.User.ID, projectID)
+	isMember, err := s.checkProjectPermission(ctx, auth.User.ID, projectID, PermissionViewBilling)
 	if err != nil {
 		return nil, ErrUnauthorized.Wrap(err)
 	}
@@ -1233,7 +1621,7 @@ func (s *Service) GetBucketUsageRollups(ctx context.Context, projectID uuid.UUID
 		return nil, err
 	}
 
-	_, err = s.isProjectMember(ctx, auth.User.ID, projectID)
+	_, err = s.checkProjectPermission(ctx, auth.User.ID, projectID, PermissionViewBilling)
 	if err != nil {
 		return nil, ErrUnauthorized.Wrap(err)
 	}
@@ -1250,11 +1638,16 @@ func (s *Service) GetBucketUsageRollups(ctx context.Context, projectID uuid.UUID
 func (s *Service) GetProjectUsageLimits(ctx context.Context, projectID uuid.UUID) (_ *ProjectUsageLimits, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	_, err = GetAuth(ctx)
+	auth, err := GetAuth(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	_, err = s.checkProjectPermission(ctx, auth.User.ID, projectID, PermissionViewBilling)
+	if err != nil {
+		return nil, ErrUnauthorized.Wrap(err)
+	}
+
 	storageLimit, err := s.projectUsage.GetProjectStorageLimit(ctx, projectID)
 	if err != nil {
 		return nil, err
@@ -1392,6 +1785,10 @@ func (s *Service) authorize(ctx context.Context, claims *consoleauth.Claims) (_
 		return nil, errs.New("authorization failed. no user with id: %s", claims.ID.String())
 	}
 
+	if user.Status == Frozen {
+		return nil, errs.New(accountFrozenErrMsg)
+	}
+
 	return user, nil
 }
 
@@ -1439,3 +1836,18 @@ func (s *Service) isProjectMember(ctx context.Context, userID uuid.UUID, project
 
 	return isProjectMember{}, ErrNoMembership.New(unauthorizedErrMsg)
 }
+
+// checkProjectPermission checks if the user is a member of given project with a role, which allows the permission.
+func (s *Service) checkProjectPermission(ctx context.Context, userID uuid.UUID, projectID uuid.UUID, permission ProjectPermission) (result isProjectMember, err error) {
+	defer mon.Task()(&ctx)(&err)
+	result, err = s.isProjectMember(ctx, userID, projectID)
+	if err != nil {
+		return result, err
+	}
+
+	if !result.membership.Role.Allows(permission) {
+		return isProjectMember{}, ErrUnauthorized.New(projectRoleForbiddenErrMsg, result.membership.Role)
+	}
+
+	return result, nil
+}
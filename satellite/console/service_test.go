@@ -0,0 +1,292 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package console_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/skyrings/skyring-common/tools/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"storj.io/common/testcontext"
+	"storj.io/storj/pkg/auth"
+	"storj.io/storj/private/testplanet"
+	"storj.io/storj/satellite"
+	"storj.io/storj/satellite/console"
+)
+
+func TestMFA(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 0,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.Console.MFA.RequiredForAPIKeys = true
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		service := planet.Satellites[0].API.Console.Service
+
+		createUser := console.CreateUser{
+			FullName: "MFA User",
+			Email:    "mfa@mail.test",
+			Password: "123a123",
+		}
+
+		regToken, err := service.CreateRegToken(ctx, 1)
+		require.NoError(t, err)
+		user, err := service.CreateUser(ctx, createUser, regToken.Secret, "")
+		require.NoError(t, err)
+		activationToken, err := service.GenerateActivationToken(ctx, user.ID, user.Email)
+		require.NoError(t, err)
+		require.NoError(t, service.ActivateAccount(ctx, activationToken))
+
+		credentials := console.AuthUser{Email: createUser.Email, Password: createUser.Password}
+
+		authorize := func(t *testing.T, request console.AuthUser) context.Context {
+			token, err := service.Token(ctx, request)
+			require.NoError(t, err)
+			authorization, err := service.Authorize(auth.WithAPIKey(ctx, []byte(token)))
+			require.NoError(t, err)
+			return console.WithAuth(ctx, authorization)
+		}
+
+		authCtx := authorize(t, credentials)
+
+		project, err := service.CreateProject(authCtx, console.ProjectInfo{Name: "MFA Project"})
+		require.NoError(t, err)
+
+		// API keys require MFA
+		_, _, err = service.CreateAPIKey(authCtx, project.ID, "without MFA")
+		require.True(t, console.ErrUnauthorized.Has(err))
+
+		_, err = service.EnableUserMFA(authCtx, "123456")
+		require.True(t, console.ErrMFAConflict.Has(err))
+
+		key, err := service.ResetMFASecretKey(authCtx)
+		require.NoError(t, err)
+
+		// the auth context contains the user without the secret key
+		authCtx = authorize(t, credentials)
+
+		_, err = service.EnableUserMFA(authCtx, "000000x")
+		require.True(t, console.ErrValidation.Has(err))
+
+		passcode, err := console.NewMFAPasscode(key, time.Now())
+		require.NoError(t, err)
+		codes, err := service.EnableUserMFA(authCtx, passcode)
+		require.NoError(t, err)
+		require.Len(t, codes, console.MFARecoveryCodeCount)
+
+		// only the hashes of the recovery codes are stored
+		mfaUser, err := service.GetUser(ctx, user.ID)
+		require.NoError(t, err)
+		require.Len(t, mfaUser.MFARecoveryCodes, console.MFARecoveryCodeCount)
+		for _, code := range codes {
+			require.NotContains(t, mfaUser.MFARecoveryCodes, code)
+		}
+
+		t.Run("login", func(t *testing.T) {
+			_, err := service.Token(ctx, credentials)
+			require.True(t, console.ErrMFAMissing.Has(err))
+
+			request := credentials
+			request.MFAPasscode = "000000"
+			if valid, _ := console.ValidateMFAPasscode(request.MFAPasscode, key, time.Now()); !valid {
+				_, err = service.Token(ctx, request)
+				require.True(t, console.ErrUnauthorized.Has(err))
+			}
+
+			// the passcode used for enabling MFA has already been accepted
+			request.MFAPasscode = passcode
+			_, err = service.Token(ctx, request)
+			require.True(t, console.ErrUnauthorized.Has(err))
+
+			request.MFAPasscode, err = console.NewMFAPasscode(key, time.Now().Add(console.MFAPasscodePeriod))
+			require.NoError(t, err)
+			_, err = service.Token(ctx, request)
+			require.NoError(t, err)
+
+			// passcodes can only be used once
+			_, err = service.Token(ctx, request)
+			require.True(t, console.ErrUnauthorized.Has(err))
+		})
+
+		t.Run("recovery code", func(t *testing.T) {
+			request := credentials
+			request.MFARecoveryCode = codes[0]
+			authCtx := authorize(t, request)
+
+			// recovery codes can only be used once
+			_, err = service.Token(ctx, request)
+			require.True(t, console.ErrUnauthorized.Has(err))
+
+			mfaUser, err := service.GetUser(ctx, user.ID)
+			require.NoError(t, err)
+			require.Len(t, mfaUser.MFARecoveryCodes, console.MFARecoveryCodeCount-1)
+
+			_, _, err = service.CreateAPIKey(authCtx, project.ID, "with MFA")
+			require.NoError(t, err)
+		})
+
+		t.Run("locked", func(t *testing.T) {
+			request := credentials
+			request.MFARecoveryCode = "invalid"
+			for i := 0; i < 5; i++ {
+				_, err := service.Token(ctx, request)
+				require.True(t, console.ErrUnauthorized.Has(err))
+			}
+
+			// valid recovery codes are rejected as well until the lock expires
+			request.MFARecoveryCode = codes[1]
+			_, err := service.Token(ctx, request)
+			require.True(t, console.ErrUnauthorized.Has(err))
+
+			mfaUser, err := service.GetUser(ctx, user.ID)
+			require.NoError(t, err)
+			require.Len(t, mfaUser.MFARecoveryCodes, console.MFARecoveryCodeCount-1)
+		})
+
+		t.Run("admin reset", func(t *testing.T) {
+			reset, err := service.ResetUserMFA(ctx, user.ID)
+			require.NoError(t, err)
+			require.False(t, reset.MFAEnabled)
+			require.Empty(t, reset.MFASecretKey)
+			require.Empty(t, reset.MFARecoveryCodes)
+
+			_, err = service.Token(ctx, credentials)
+			require.NoError(t, err)
+		})
+	})
+}
+
+func TestProjectRoles(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 0,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		service := planet.Satellites[0].API.Console.Service
+
+		authorize := func(t *testing.T, email string) context.Context {
+			regToken, err := service.CreateRegToken(ctx, 1)
+			require.NoError(t, err)
+			user, err := service.CreateUser(ctx, console.CreateUser{
+				FullName: "Role User",
+				Email:    email,
+				Password: "123a123",
+			}, regToken.Secret, "")
+			require.NoError(t, err)
+			activationToken, err := service.GenerateActivationToken(ctx, user.ID, user.Email)
+			require.NoError(t, err)
+			require.NoError(t, service.ActivateAccount(ctx, activationToken))
+
+			token, err := service.Token(ctx, console.AuthUser{Email: email, Password: "123a123"})
+			require.NoError(t, err)
+			authorization, err := service.Authorize(auth.WithAPIKey(ctx, []byte(token)))
+			require.NoError(t, err)
+			return console.WithAuth(ctx, authorization)
+		}
+
+		ownerCtx := authorize(t, "owner@mail.test")
+		adminCtx := authorize(t, "admin@mail.test")
+		developerCtx := authorize(t, "developer@mail.test")
+		billingCtx := authorize(t, "billing@mail.test")
+
+		project, err := service.CreateProject(ownerCtx, console.ProjectInfo{Name: "roles"})
+		require.NoError(t, err)
+
+		_, err = service.AddProjectMembers(ownerCtx, project.ID, []string{"admin@mail.test", "developer@mail.test"}, console.RoleDeveloper)
+		require.NoError(t, err)
+		_, err = service.AddProjectMembers(ownerCtx, project.ID, []string{"billing@mail.test"}, console.RoleOwner)
+		require.True(t, console.ErrValidation.Has(err))
+		_, err = service.AddProjectMembers(ownerCtx, project.ID, []string{"billing@mail.test"}, console.RoleBillingViewer)
+		require.NoError(t, err)
+
+		_, err = service.AddProjectMembers(developerCtx, project.ID, []string{"owner@mail.test"}, console.RoleDeveloper)
+		require.True(t, console.ErrUnauthorized.Has(err))
+		_, err = service.UpdateProjectMemberRole(developerCtx, project.ID, "admin@mail.test", console.RoleAdmin)
+		require.True(t, console.ErrUnauthorized.Has(err))
+
+		member, err := service.UpdateProjectMemberRole(ownerCtx, project.ID, "admin@mail.test", console.RoleAdmin)
+		require.NoError(t, err)
+		require.Equal(t, console.RoleAdmin, member.Role)
+
+		t.Run("role changes", func(t *testing.T) {
+			_, err := service.UpdateProjectMemberRole(adminCtx, project.ID, "billing@mail.test", console.RoleAdmin)
+			require.True(t, console.ErrUnauthorized.Has(err))
+
+			_, err = service.UpdateProjectMemberRole(ownerCtx, project.ID, "admin@mail.test", console.RoleOwner)
+			require.True(t, console.ErrValidation.Has(err))
+			_, err = service.UpdateProjectMemberRole(ownerCtx, project.ID, "admin@mail.test", console.ProjectRole(0))
+			require.True(t, console.ErrValidation.Has(err))
+			_, err = service.UpdateProjectMemberRole(ownerCtx, project.ID, "owner@mail.test", console.RoleAdmin)
+			require.True(t, console.ErrValidation.Has(err))
+		})
+
+		t.Run("api keys", func(t *testing.T) {
+			_, _, err := service.CreateAPIKey(billingCtx, project.ID, "billing")
+			require.True(t, console.ErrUnauthorized.Has(err))
+
+			info, _, err := service.CreateAPIKey(developerCtx, project.ID, "developer")
+			require.NoError(t, err)
+
+			_, err = service.GetAPIKeyInfo(billingCtx, info.ID)
+			require.True(t, console.ErrUnauthorized.Has(err))
+			require.NoError(t, service.DeleteAPIKeys(adminCtx, []uuid.UUID{info.ID}))
+		})
+
+		t.Run("billing", func(t *testing.T) {
+			since := time.Now().Add(-time.Hour)
+			before := time.Now()
+
+			_, err := service.GetProjectUsage(developerCtx, project.ID, since, before)
+			require.True(t, console.ErrUnauthorized.Has(err))
+
+			_, err = service.GetProjectUsage(billingCtx, project.ID, since, before)
+			require.NoError(t, err)
+			_, err = service.GetBucketUsageRollups(adminCtx, project.ID, since, before)
+			require.NoError(t, err)
+
+			_, err = service.GetProjectUsageLimits(developerCtx, project.ID)
+			require.True(t, console.ErrUnauthorized.Has(err))
+			_, err = service.GetProjectUsageLimits(billingCtx, project.ID)
+			require.NoError(t, err)
+		})
+
+		t.Run("members", func(t *testing.T) {
+			page, err := service.GetProjectMembers(billingCtx, project.ID, console.ProjectMembersCursor{Limit: 10, Page: 1})
+			require.NoError(t, err)
+			require.Len(t, page.ProjectMembers, 4)
+
+			err = service.DeleteProjectMembers(developerCtx, project.ID, []string{"billing@mail.test"})
+			require.True(t, console.ErrUnauthorized.Has(err))
+			err = service.DeleteProjectMembers(adminCtx, project.ID, []string{"billing@mail.test"})
+			require.NoError(t, err)
+
+			// only the owner can add members with a role other than developer
+			_, err = service.AddProjectMembers(adminCtx, project.ID, []string{"billing@mail.test"}, console.RoleAdmin)
+			require.True(t, console.ErrUnauthorized.Has(err))
+			_, err = service.AddProjectMembers(adminCtx, project.ID, []string{"billing@mail.test"}, console.RoleDeveloper)
+			require.NoError(t, err)
+		})
+
+		t.Run("project", func(t *testing.T) {
+			_, err := service.GetProject(authorize(t, "outsider@mail.test"), project.ID)
+			require.True(t, console.ErrUnauthorized.Has(err))
+			got, err := service.GetProject(billingCtx, project.ID)
+			require.NoError(t, err)
+			require.Equal(t, project.ID, got.ID)
+
+			_, err = service.UpdateProject(developerCtx, project.ID, "developer")
+			require.True(t, console.ErrUnauthorized.Has(err))
+			_, err = service.UpdateProject(adminCtx, project.ID, "admin")
+			require.NoError(t, err)
+
+			err = service.DeleteProject(adminCtx, project.ID)
+			require.True(t, console.ErrUnauthorized.Has(err))
+			require.NoError(t, service.DeleteProject(ownerCtx, project.ID))
+		})
+	})
+}
@@ -25,6 +25,33 @@ type Users interface {
 	Delete(ctx context.Context, id uuid.UUID) error
 	// Update is a method for updating user entity.
 	Update(ctx context.Context, user *User) error
+	// UseMFAPasscode stores the counter of an accepted MFA passcode and resets
+	// the failed MFA attempts, unless a passcode with the same or a later
+	// counter has been accepted before. It returns whether the counter has
+	// been stored.
+	UseMFAPasscode(ctx context.Context, id uuid.UUID, counter int64) (bool, error)
+	// UseMFARecoveryCode replaces the recovery codes with the remaining ones
+	// and resets the failed MFA attempts, unless the recovery codes aren't
+	// the expected ones anymore. It returns whether the recovery codes have
+	// been replaced.
+	UseMFARecoveryCode(ctx context.Context, id uuid.UUID, codes, remaining []string) (bool, error)
+	// CountMFAAttempt records an MFA attempt at the provided time, unless
+	// maxAttempts attempts have been recorded within the window. Attempts,
+	// which are further apart than the window, aren't counted together. It
+	// returns whether the attempt has been recorded.
+	CountMFAAttempt(ctx context.Context, id uuid.UUID, now time.Time, window time.Duration, maxAttempts int) (bool, error)
+	// SetMFASecretKey stores a new MFA secret key, unless MFA is enabled. It
+	// returns whether the key has been stored.
+	SetMFASecretKey(ctx context.Context, id uuid.UUID, key string) (bool, error)
+	// EnableMFA enables MFA with the recovery codes, unless MFA is enabled or
+	// the secret key isn't the expected one anymore. It returns whether MFA
+	// has been enabled.
+	EnableMFA(ctx context.Context, id uuid.UUID, key string, recoveryCodes []string) (bool, error)
+	// SetMFARecoveryCodes replaces the recovery codes, unless MFA is disabled.
+	// It returns whether the recovery codes have been replaced.
+	SetMFARecoveryCodes(ctx context.Context, id uuid.UUID, recoveryCodes []string) (bool, error)
+	// DisableMFA disables MFA and removes the secret key and the recovery codes.
+	DisableMFA(ctx context.Context, id uuid.UUID) error
 }
 
 // UserInfo holds User updatable data.
@@ -45,6 +72,15 @@ func (user *UserInfo) IsValid() error {
 	return errs.Combine()
 }
 
+// AuthUser holds the credentials of a user for authentication.
+type AuthUser struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	// MFAPasscode or MFARecoveryCode is required when the user has enabled MFA.
+	MFAPasscode     string `json:"mfaPasscode"`
+	MFARecoveryCode string `json:"mfaRecoveryCode"`
+}
+
 // CreateUser struct holds info for User creation.
 type CreateUser struct {
 	FullName  string `json:"fullName"`
@@ -85,6 +121,8 @@ const (
 	Active UserStatus = 1
 	// Deleted is a user status that he receives after deleting account
 	Deleted UserStatus = 2
+	// Frozen is a user status that is set by the satellite administration to block the account
+	Frozen UserStatus = 3
 )
 
 // User is a database object that describes User entity.
@@ -101,4 +139,16 @@ type User struct {
 	PartnerID uuid.UUID  `json:"partnerId"`
 
 	CreatedAt time.Time `json:"createdAt"`
+
+	MFAEnabled   bool   `json:"mfaEnabled"`
+	MFASecretKey string `json:"-"`
+	// MFARecoveryCodes are the hashes of the unused recovery codes.
+	MFARecoveryCodes []string `json:"-"`
+	// MFAPasscodeCounter is the counter of the last accepted passcode.
+	MFAPasscodeCounter int64 `json:"-"`
+	// MFAFailedAttempts is the number of MFA attempts since the last
+	// accepted one, the last of them at MFAFailedAt. Attempts are counted
+	// before they're checked.
+	MFAFailedAttempts int       `json:"-"`
+	MFAFailedAt       time.Time `json:"-"`
 }
@@ -96,6 +96,120 @@ func TestUserEmailCase(t *testing.T) {
 	})
 }
 
+func TestUserMFAState(t *testing.T) {
+	satellitedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db satellite.DB) {
+		repository := db.Console().Users()
+
+		user, err := repository.Insert(ctx, &console.User{
+			ID:               testrand.UUID(),
+			FullName:         name,
+			Email:            email,
+			PasswordHash:     []byte(passValid),
+			MFAEnabled:       true,
+			MFASecretKey:     "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ",
+			MFARecoveryCodes: []string{"a", "b"},
+		})
+		assert.NoError(t, err)
+
+		now := time.Now()
+		for i := 0; i < 2; i++ {
+			counted, err := repository.CountMFAAttempt(ctx, user.ID, now, time.Hour, 2)
+			assert.NoError(t, err)
+			assert.True(t, counted)
+		}
+		user, err = repository.Get(ctx, user.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, user.MFAFailedAttempts)
+		assert.WithinDuration(t, now, user.MFAFailedAt, time.Second)
+
+		// no more attempts are counted within the window
+		counted, err := repository.CountMFAAttempt(ctx, user.ID, now.Add(time.Minute), time.Hour, 2)
+		assert.NoError(t, err)
+		assert.False(t, counted)
+
+		// attempts outside of the window aren't counted together
+		counted, err = repository.CountMFAAttempt(ctx, user.ID, now.Add(2*time.Hour), time.Hour, 2)
+		assert.NoError(t, err)
+		assert.True(t, counted)
+		user, err = repository.Get(ctx, user.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, user.MFAFailedAttempts)
+
+		used, err := repository.UseMFAPasscode(ctx, user.ID, 10)
+		assert.NoError(t, err)
+		assert.True(t, used)
+		for _, counter := range []int64{10, 9} {
+			used, err = repository.UseMFAPasscode(ctx, user.ID, counter)
+			assert.NoError(t, err)
+			assert.False(t, used, counter)
+		}
+
+		user, err = repository.Get(ctx, user.ID)
+		assert.NoError(t, err)
+		assert.EqualValues(t, 10, user.MFAPasscodeCounter)
+		assert.Zero(t, user.MFAFailedAttempts)
+
+		used, err = repository.UseMFARecoveryCode(ctx, user.ID, []string{"a", "b"}, []string{"b"})
+		assert.NoError(t, err)
+		assert.True(t, used)
+
+		// the same code can't be used with outdated recovery codes
+		used, err = repository.UseMFARecoveryCode(ctx, user.ID, []string{"a", "b"}, []string{"b"})
+		assert.NoError(t, err)
+		assert.False(t, used)
+
+		used, err = repository.UseMFARecoveryCode(ctx, user.ID, []string{"b"}, nil)
+		assert.NoError(t, err)
+		assert.True(t, used)
+
+		user, err = repository.Get(ctx, user.ID)
+		assert.NoError(t, err)
+		assert.Empty(t, user.MFARecoveryCodes)
+
+		// the generic update doesn't change the MFA state
+		assert.NoError(t, repository.Update(ctx, &console.User{ID: user.ID, FullName: "MFA User", Email: user.Email, Status: user.Status}))
+		user, err = repository.Get(ctx, user.ID)
+		assert.NoError(t, err)
+		assert.True(t, user.MFAEnabled)
+		assert.NotEmpty(t, user.MFASecretKey)
+
+		// the secret key can't be changed while MFA is enabled
+		stored, err := repository.SetMFASecretKey(ctx, user.ID, "NEWKEY")
+		assert.NoError(t, err)
+		assert.False(t, stored)
+		replaced, err := repository.SetMFARecoveryCodes(ctx, user.ID, []string{"c"})
+		assert.NoError(t, err)
+		assert.True(t, replaced)
+
+		assert.NoError(t, repository.DisableMFA(ctx, user.ID))
+		user, err = repository.Get(ctx, user.ID)
+		assert.NoError(t, err)
+		assert.False(t, user.MFAEnabled)
+		assert.Empty(t, user.MFASecretKey)
+		assert.Empty(t, user.MFARecoveryCodes)
+
+		replaced, err = repository.SetMFARecoveryCodes(ctx, user.ID, []string{"c"})
+		assert.NoError(t, err)
+		assert.False(t, replaced)
+
+		// MFA is only enabled with the secret key, which has been verified
+		stored, err = repository.SetMFASecretKey(ctx, user.ID, "NEWKEY")
+		assert.NoError(t, err)
+		assert.True(t, stored)
+		enabled, err := repository.EnableMFA(ctx, user.ID, "OLDKEY", []string{"c"})
+		assert.NoError(t, err)
+		assert.False(t, enabled)
+		enabled, err = repository.EnableMFA(ctx, user.ID, "NEWKEY", []string{"c"})
+		assert.NoError(t, err)
+		assert.True(t, enabled)
+
+		user, err = repository.Get(ctx, user.ID)
+		assert.NoError(t, err)
+		assert.True(t, user.MFAEnabled)
+		assert.Equal(t, []string{"c"}, user.MFARecoveryCodes)
+	})
+}
+
 func testUsers(ctx context.Context, t *testing.T, repository console.Users, user *console.User) {
 
 	t.Run("User insertion success", func(t *testing.T) {
@@ -63,6 +63,11 @@ func (endpoint *Endpoint) CheckIn(ctx context.Context, req *pb.CheckInRequest) (
 		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, errCheckInNetwork.New("failed to resolve IP from address: %s, err: %v", req.Address, err).Error())
 	}
 
+	location, err := overlay.GetLocation(ctx, req.Address, endpoint.service.locator)
+	if err != nil {
+		endpoint.log.Info("failed to determine location from address", zap.String("node address", req.Address), zap.Stringer("Node ID", nodeID), zap.Error(err))
+	}
+
 	pingNodeSuccess, pingErrorMessage, err := endpoint.service.PingBack(ctx, req.Address, nodeID)
 	if err != nil {
 		endpoint.log.Info("failed to ping back address", zap.String("node address", req.Address), zap.Stringer("Node ID", nodeID), zap.Error(err))
@@ -84,6 +89,7 @@ func (endpoint *Endpoint) CheckIn(ctx context.Context, req *pb.CheckInRequest) (
 		Capacity: req.Capacity,
 		Operator: req.Operator,
 		Version:  req.Version,
+		Location: location,
 	}
 	err = endpoint.service.overlay.UpdateCheckIn(ctx, nodeInfo, time.Now().UTC())
 	if err != nil {
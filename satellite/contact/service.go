@@ -37,16 +37,20 @@ type Service struct {
 	overlay *overlay.Service
 	peerIDs overlay.PeerIdentities
 	dialer  rpc.Dialer
+	locator overlay.Locator
 }
 
 // NewService creates a new contact service.
-func NewService(log *zap.Logger, self *overlay.NodeDossier, overlay *overlay.Service, peerIDs overlay.PeerIdentities, dialer rpc.Dialer) *Service {
+//
+// locator is used to determine the location of checked in nodes, it may be nil.
+func NewService(log *zap.Logger, self *overlay.NodeDossier, overlay *overlay.Service, peerIDs overlay.PeerIdentities, dialer rpc.Dialer, locator overlay.Locator) *Service {
 	return &Service{
 		log:     log,
 		self:    self,
 		overlay: overlay,
 		peerIDs: peerIDs,
 		dialer:  dialer,
+		locator: locator,
 	}
 }
 
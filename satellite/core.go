@@ -29,6 +29,7 @@ import (
 	"storj.io/storj/satellite/accounting/rollup"
 	"storj.io/storj/satellite/accounting/tally"
 	"storj.io/storj/satellite/audit"
+	"storj.io/storj/satellite/bucketlifecycle"
 	"storj.io/storj/satellite/contact"
 	"storj.io/storj/satellite/dbcleanup"
 	"storj.io/storj/satellite/downtime"
@@ -102,6 +103,11 @@ type Core struct {
 		Service *gc.Service
 	}
 
+	BucketLifecycle struct {
+		DeletePiecesService *metainfo.DeletePiecesService
+		Chore               *bucketlifecycle.Chore
+	}
+
 	DBCleanup struct {
 		Chore *dbcleanup.Chore
 	}
@@ -214,7 +220,7 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB,
 			Type:    pb.NodeType_SATELLITE,
 			Version: *pbVersion,
 		}
-		peer.Contact.Service = contact.NewService(peer.Log.Named("contact:service"), self, peer.Overlay.Service, peer.DB.PeerIdentities(), peer.Dialer)
+		peer.Contact.Service = contact.NewService(peer.Log.Named("contact:service"), self, peer.Overlay.Service, peer.DB.PeerIdentities(), peer.Dialer, nil)
 		peer.Services.Add(lifecycle.Item{
 			Name:  "contact:service",
 			Close: peer.Contact.Service.Close,
@@ -271,7 +277,7 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB,
 			peer.Metainfo.Database,
 			peer.DB.Buckets(),
 		)
-		peer.Metainfo.Loop = metainfo.NewLoop(config.Metainfo.Loop, peer.Metainfo.Database)
+		peer.Metainfo.Loop = metainfo.NewLoop(config.Metainfo.Loop, peer.Metainfo.Database, peer.DB.LoopCheckpoints())
 		peer.Services.Add(lifecycle.Item{
 			Name:  "metainfo:loop",
 			Run:   peer.Metainfo.Loop.Run,
@@ -360,15 +366,55 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB,
 			peer.Log.Named("garbage-collection"),
 			config.GarbageCollection,
 			peer.Dialer,
+			peer.DB.GarbageCollection(),
 			peer.Overlay.DB,
 			peer.Metainfo.Loop,
 		)
 		peer.Services.Add(lifecycle.Item{
-			Name: "garbage-collection",
-			Run:  peer.GarbageCollection.Service.Run,
+			Name:  "garbage-collection",
+			Run:   peer.GarbageCollection.Service.Run,
+			Close: peer.GarbageCollection.Service.Close,
 		})
 		peer.Debug.Server.Panel.Add(
 			debug.Cycle("Garbage Collection", peer.GarbageCollection.Service.Loop))
+		peer.Debug.Server.Panel.Add(
+			debug.Cycle("Garbage Collection Retry", peer.GarbageCollection.Service.RetryLoop))
+	}
+
+	{ // setup bucket lifecycle
+		if config.BucketLifecycle.Enabled {
+			peer.BucketLifecycle.DeletePiecesService, err = metainfo.NewDeletePiecesService(
+				peer.Log.Named("bucket-lifecycle:delete-pieces"),
+				peer.Dialer,
+				metainfoDeletePiecesConcurrencyLimit,
+			)
+			if err != nil {
+				return nil, errs.Combine(err, peer.Close())
+			}
+			peer.Services.Add(lifecycle.Item{
+				Name:  "bucket-lifecycle:delete-pieces",
+				Close: peer.BucketLifecycle.DeletePiecesService.Close,
+			})
+
+			peer.BucketLifecycle.Chore = bucketlifecycle.NewChore(
+				peer.Log.Named("bucket-lifecycle"),
+				config.BucketLifecycle,
+				peer.Metainfo.Loop,
+				peer.Metainfo.Service,
+				peer.BucketLifecycle.DeletePiecesService,
+				peer.DB.SharedSegments(),
+				peer.Overlay.Service,
+			)
+			peer.Services.Add(lifecycle.Item{
+				Name:  "bucket-lifecycle",
+				Run:   peer.BucketLifecycle.Chore.Run,
+				Close: peer.BucketLifecycle.Chore.Close,
+			})
+			peer.Debug.Server.Panel.Add(
+				debug.Cycle("Bucket Lifecycle", peer.BucketLifecycle.Chore.Loop))
+		} else {
+			peer.Log.Named("bucket-lifecycle").Info("disabled")
+		}
 	}
 
 	{ // setup db cleanup
@@ -0,0 +1,48 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gc
+
+import (
+	"context"
+	"time"
+
+	"storj.io/common/storj"
+)
+
+// DB stores the garbage collection retain filters until they are sent to the storage nodes.
+//
+// architecture: Database
+type DB interface {
+	// SetRetainFilter stores the filter for a node, replacing the filter of an older generation.
+	SetRetainFilter(ctx context.Context, filter RetainFilter) error
+	// ListUnsentRetainFilters returns filters that have not been sent yet and were created after createdAfter,
+	// ordered by node ID and starting after cursor.
+	ListUnsentRetainFilters(ctx context.Context, cursor storj.NodeID, createdAfter time.Time, limit int) ([]RetainFilter, error)
+	// RecordRetainFilterSend records an attempt to send the filter of the given generation to a node.
+	RecordRetainFilterSend(ctx context.Context, nodeID storj.NodeID, creationDate time.Time, success bool, attemptedAt time.Time) error
+	// ListRetainFilterStatus returns the status of the stored filters, without the filters themselves,
+	// ordered by node ID and starting after cursor.
+	ListRetainFilterStatus(ctx context.Context, cursor storj.NodeID, limit int) (statuses []RetainFilterStatus, more bool, err error)
+	// DeleteExpiredRetainFilters deletes filters created before the given time.
+	DeleteExpiredRetainFilters(ctx context.Context, createdBefore time.Time) (int64, error)
+}
+
+// RetainFilter is a stored retain filter for a single node.
+type RetainFilter struct {
+	NodeID       storj.NodeID
+	CreationDate time.Time
+	PieceCount   int
+	Filter       []byte
+}
+
+// RetainFilterStatus describes which filter generation a node has and whether it was sent.
+type RetainFilterStatus struct {
+	NodeID        storj.NodeID
+	CreationDate  time.Time
+	PieceCount    int
+	FilterSize    int
+	SentAt        *time.Time
+	SendAttempts  int
+	LastAttemptAt *time.Time
+}
@@ -0,0 +1,96 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gc_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/satellite"
+	"storj.io/storj/satellite/gc"
+	"storj.io/storj/satellite/satellitedb/satellitedbtest"
+)
+
+func TestRetainFiltersDB(t *testing.T) {
+	satellitedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db satellite.DB) {
+		filters := db.GarbageCollection()
+
+		now := time.Now().UTC().Truncate(time.Millisecond)
+		old := now.Add(-48 * time.Hour)
+
+		nodeA, nodeB := storj.NodeID{1}, storj.NodeID{2}
+		require.NoError(t, filters.SetRetainFilter(ctx, gc.RetainFilter{
+			NodeID: nodeA, CreationDate: old, PieceCount: 1, Filter: testrand.Bytes(10),
+		}))
+		require.NoError(t, filters.SetRetainFilter(ctx, gc.RetainFilter{
+			NodeID: nodeB, CreationDate: now, PieceCount: 2, Filter: testrand.Bytes(20),
+		}))
+
+		// expired filters are not listed for sending
+		unsent, err := filters.ListUnsentRetainFilters(ctx, storj.NodeID{}, now.Add(-time.Hour), 10)
+		require.NoError(t, err)
+		require.Len(t, unsent, 1)
+		require.Equal(t, nodeB, unsent[0].NodeID)
+		require.Len(t, unsent[0].Filter, 20)
+
+		// failed sends are retried
+		require.NoError(t, filters.RecordRetainFilterSend(ctx, nodeB, now, false, now))
+		unsent, err = filters.ListUnsentRetainFilters(ctx, storj.NodeID{}, now.Add(-time.Hour), 10)
+		require.NoError(t, err)
+		require.Len(t, unsent, 1)
+
+		// successful sends are not
+		require.NoError(t, filters.RecordRetainFilterSend(ctx, nodeB, now, true, now))
+		unsent, err = filters.ListUnsentRetainFilters(ctx, storj.NodeID{}, now.Add(-time.Hour), 10)
+		require.NoError(t, err)
+		require.Len(t, unsent, 0)
+
+		statuses, more, err := filters.ListRetainFilterStatus(ctx, storj.NodeID{}, 1)
+		require.NoError(t, err)
+		require.True(t, more)
+		require.Len(t, statuses, 1)
+		require.Equal(t, nodeA, statuses[0].NodeID)
+		require.Nil(t, statuses[0].SentAt)
+
+		statuses, more, err = filters.ListRetainFilterStatus(ctx, nodeA, 1)
+		require.NoError(t, err)
+		require.False(t, more)
+		require.Len(t, statuses, 1)
+		require.Equal(t, nodeB, statuses[0].NodeID)
+		require.Equal(t, 2, statuses[0].PieceCount)
+		require.Equal(t, 20, statuses[0].FilterSize)
+		require.Equal(t, 2, statuses[0].SendAttempts)
+		require.NotNil(t, statuses[0].SentAt)
+		require.NotNil(t, statuses[0].LastAttemptAt)
+
+		// a new generation replaces the old one
+		require.NoError(t, filters.SetRetainFilter(ctx, gc.RetainFilter{
+			NodeID: nodeB, CreationDate: now.Add(time.Minute), PieceCount: 3, Filter: testrand.Bytes(30),
+		}))
+		unsent, err = filters.ListUnsentRetainFilters(ctx, storj.NodeID{}, now.Add(-time.Hour), 10)
+		require.NoError(t, err)
+		require.Len(t, unsent, 1)
+		require.Equal(t, 3, unsent[0].PieceCount)
+
+		// recording a send of an old generation does not mark the new one as sent
+		require.NoError(t, filters.RecordRetainFilterSend(ctx, nodeB, now, true, now))
+		unsent, err = filters.ListUnsentRetainFilters(ctx, storj.NodeID{}, now.Add(-time.Hour), 10)
+		require.NoError(t, err)
+		require.Len(t, unsent, 1)
+
+		deleted, err := filters.DeleteExpiredRetainFilters(ctx, now.Add(-time.Hour))
+		require.NoError(t, err)
+		require.EqualValues(t, 1, deleted)
+
+		statuses, _, err = filters.ListRetainFilterStatus(ctx, storj.NodeID{}, 10)
+		require.NoError(t, err)
+		require.Len(t, statuses, 1)
+		require.Equal(t, nodeB, statuses[0].NodeID)
+	})
+}
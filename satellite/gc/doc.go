@@ -12,9 +12,14 @@ The gc.PieceTracker handling functions are used by the gc.Service to periodicall
 account for all existing pieces on storage nodes and create "retain requests"
 which contain a bloom filter of all pieces that possibly exist on a storage node.
 
-The gc.Service will send that request to the storagenode after a full metaloop
-iteration, and the storage node will use that request to delete the "garbage" pieces
-that are not in the bloom filter.
+The gc.Service stores the filters in the database after a full metaloop iteration
+and then sends the retain requests to the storage nodes, which use them to delete
+the "garbage" pieces that are not in the bloom filter. Filters that could not be
+sent are resent periodically until they expire or a newer filter replaces them.
+
+To limit memory usage, the nodes can be split into shards by their ID. The filters
+of every shard are built in a separate metainfo loop iteration and are stored
+before the next shard is started, so only the filters of one shard are held in memory.
 
 See storj/docs/design/garbage-collection.md for more info.
 */
@@ -11,14 +11,17 @@ import (
 	"go.uber.org/zap"
 
 	"storj.io/common/encryption"
+	"storj.io/common/errs2"
 	"storj.io/common/memory"
 	"storj.io/common/paths"
 	"storj.io/common/pb"
+	"storj.io/common/rpc/rpcstatus"
 	"storj.io/common/storj"
 	"storj.io/common/testcontext"
 	"storj.io/common/testrand"
 	"storj.io/storj/private/testplanet"
 	"storj.io/storj/satellite"
+	"storj.io/storj/satellite/internalpb"
 	"storj.io/storj/storage"
 	"storj.io/storj/storagenode"
 )
@@ -118,6 +121,17 @@ func TestGarbageCollection(t *testing.T) {
 		})
 		require.NoError(t, err)
 		require.NotNil(t, pieceAccess)
+
+		// Check that the filter was stored and sent
+		resp, err := satellite.GarbageCollection.Inspector.ListRetainFilters(ctx, &internalpb.ListRetainFiltersRequest{Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, resp.Filters, 1)
+		require.Equal(t, targetNode.ID().Bytes(), resp.Filters[0].NodeId)
+		require.NotNil(t, resp.Filters[0].SentAt)
+		require.EqualValues(t, 1, resp.Filters[0].SendAttempts)
+
+		_, err = satellite.GarbageCollection.Inspector.ListRetainFilters(ctx, &internalpb.ListRetainFiltersRequest{Limit: 0})
+		require.True(t, errs2.IsRPC(err, rpcstatus.InvalidArgument))
 	})
 }
 
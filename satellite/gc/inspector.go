@@ -0,0 +1,60 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gc
+
+import (
+	"context"
+
+	"storj.io/common/rpc/rpcstatus"
+	"storj.io/common/storj"
+	"storj.io/storj/satellite/internalpb"
+)
+
+// Inspector is a private endpoint for inspecting the stored garbage collection filters.
+//
+// architecture: Endpoint
+type Inspector struct {
+	db DB
+}
+
+// NewInspector creates a garbage collection Inspector.
+func NewInspector(db DB) *Inspector {
+	return &Inspector{db: db}
+}
+
+// ListRetainFilters returns which filter generation every node has and whether it was sent.
+func (inspector *Inspector) ListRetainFilters(ctx context.Context, req *internalpb.ListRetainFiltersRequest) (_ *internalpb.ListRetainFiltersResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if req.Limit <= 0 {
+		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, "limit must be positive")
+	}
+
+	var cursor storj.NodeID
+	if len(req.CursorNodeId) > 0 {
+		cursor, err = storj.NodeIDFromBytes(req.CursorNodeId)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+	}
+
+	statuses, more, err := inspector.db.ListRetainFilterStatus(ctx, cursor, int(req.Limit))
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	resp := &internalpb.ListRetainFiltersResponse{More: more}
+	for _, status := range statuses {
+		resp.Filters = append(resp.Filters, &internalpb.RetainFilterStatus{
+			NodeId:        status.NodeID.Bytes(),
+			CreationDate:  status.CreationDate,
+			PieceCount:    int64(status.PieceCount),
+			FilterSize:    int64(status.FilterSize),
+			SentAt:        status.SentAt,
+			SendAttempts:  int32(status.SendAttempts),
+			LastAttemptAt: status.LastAttemptAt,
+		})
+	}
+	return resp, nil
+}
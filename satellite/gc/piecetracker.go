@@ -18,7 +18,10 @@ import (
 
 var _ metainfo.Observer = (*PieceTracker)(nil)
 
-// PieceTracker implements the metainfo loop observer interface for garbage collection
+// PieceTracker implements the metainfo loop observer interface for garbage collection.
+// It isn't forked for the parallel ranges of the metainfo loop: every fork
+// would hold full size filters of all nodes and the filters couldn't be merged
+// without patching their encoding.
 //
 // architecture: Observer
 type PieceTracker struct {
@@ -27,6 +30,8 @@ type PieceTracker struct {
 	creationDate time.Time
 	// TODO: should we use int or int64 consistently for piece count (db type is int64)?
 	pieceCounts map[storj.NodeID]int
+	// shard and shards select the nodes, for which filters are built.
+	shard, shards int
 
 	retainInfos map[storj.NodeID]*RetainInfo
 }
@@ -51,6 +56,9 @@ func (pieceTracker *PieceTracker) RemoteSegment(ctx context.Context, path metain
 	pieces := remote.GetRemotePieces()
 
 	for _, piece := range pieces {
+		if !pieceTracker.inShard(piece.NodeId) {
+			continue
+		}
 		pieceID := remote.RootPieceId.Derive(piece.NodeId, piece.PieceNum)
 		pieceTracker.add(piece.NodeId, pieceID)
 	}
@@ -67,6 +75,15 @@ func (pieceTracker *PieceTracker) InlineSegment(ctx context.Context, path metain
 	return nil
 }
 
+// inShard returns whether the filter of the node is built by the piece tracker.
+func (pieceTracker *PieceTracker) inShard(nodeID storj.NodeID) bool {
+	if pieceTracker.shards <= 1 {
+		return true
+	}
+	// node IDs are hashes, so the first byte distributes the nodes evenly
+	return int(nodeID[0])%pieceTracker.shards == pieceTracker.shard
+}
+
 // adds a pieceID to the relevant node's RetainInfo
 func (pieceTracker *PieceTracker) add(nodeID storj.NodeID, pieceID storj.PieceID) {
 	if _, ok := pieceTracker.retainInfos[nodeID]; !ok {
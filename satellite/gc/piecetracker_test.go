@@ -0,0 +1,132 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/common/errs2"
+	"storj.io/common/pb"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/satellite/metainfo"
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/teststore"
+)
+
+func TestPieceTrackerParallel(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	db := teststore.New()
+	nodes := make([]storj.NodeID, 5)
+	for i := range nodes {
+		nodes[i] = testrand.NodeID()
+	}
+	pieces := putPointers(ctx, t, db, nodes)
+
+	config := Config{InitialPieces: 100, FalsePositiveRate: 0.1}
+	pieceCounts := map[storj.NodeID]int{nodes[0]: 50}
+
+	serial := NewPieceTracker(zaptest.NewLogger(t), config, pieceCounts)
+	require.NoError(t, metainfo.IterateDatabase(ctx, 0, db, serial))
+	require.Len(t, serial.retainInfos, len(nodes))
+
+	metaLoop := metainfo.NewLoop(metainfo.LoopConfig{Workers: 8}, db, nil)
+	loopCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ctx.Go(func() error {
+		return errs2.IgnoreCanceled(metaLoop.Run(loopCtx))
+	})
+
+	parallel := NewPieceTracker(zaptest.NewLogger(t), config, pieceCounts)
+	require.NoError(t, metaLoop.Join(ctx, parallel))
+
+	require.Len(t, parallel.retainInfos, len(serial.retainInfos))
+	for nodeID, expected := range serial.retainInfos {
+		info := parallel.retainInfos[nodeID]
+		require.NotNil(t, info)
+		require.Equal(t, expected.Count, info.Count)
+		require.Equal(t, len(pieces[nodeID]), info.Count)
+
+		expectedHashCount, expectedSize := expected.Filter.Parameters()
+		hashCount, size := info.Filter.Parameters()
+		require.Equal(t, expectedHashCount, hashCount)
+		require.Equal(t, expectedSize, size)
+
+		for _, pieceID := range pieces[nodeID] {
+			require.True(t, info.Filter.Contains(pieceID))
+		}
+	}
+}
+
+func TestPieceTrackerShards(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	db := teststore.New()
+	nodes := make([]storj.NodeID, 20)
+	for i := range nodes {
+		nodes[i] = testrand.NodeID()
+	}
+	putPointers(ctx, t, db, nodes)
+
+	config := Config{InitialPieces: 100, FalsePositiveRate: 0.1}
+
+	all := NewPieceTracker(zaptest.NewLogger(t), config, nil)
+	require.NoError(t, metainfo.IterateDatabase(ctx, 0, db, all))
+
+	const shards = 3
+	sharded := map[storj.NodeID]int{}
+	for shard := 0; shard < shards; shard++ {
+		tracker := NewPieceTracker(zaptest.NewLogger(t), config, nil)
+		tracker.shard, tracker.shards = shard, shards
+		require.NoError(t, metainfo.IterateDatabase(ctx, 0, db, tracker))
+
+		for nodeID, info := range tracker.retainInfos {
+			require.Equal(t, shard, int(nodeID[0])%shards)
+			require.NotContains(t, sharded, nodeID)
+			sharded[nodeID] = info.Count
+		}
+	}
+
+	require.Len(t, sharded, len(all.retainInfos))
+	for nodeID, info := range all.retainInfos {
+		require.Equal(t, info.Count, sharded[nodeID])
+	}
+}
+
+// putPointers stores remote segments, whose pieces are spread over the nodes,
+// and returns the piece IDs per node.
+func putPointers(ctx context.Context, t *testing.T, db storage.KeyValueStore, nodes []storj.NodeID) map[storj.NodeID][]storj.PieceID {
+	pieces := map[storj.NodeID][]storj.PieceID{}
+	for i := 0; i < 40; i++ {
+		pointer := &pb.Pointer{
+			Type: pb.Pointer_REMOTE,
+			Remote: &pb.RemoteSegment{
+				RootPieceId: testrand.PieceID(),
+			},
+		}
+		for n := 0; n < 3; n++ {
+			nodeID := nodes[(i+n)%len(nodes)]
+			pointer.Remote.RemotePieces = append(pointer.Remote.RemotePieces, &pb.RemotePiece{
+				PieceNum: int32(n),
+				NodeId:   nodeID,
+			})
+			pieces[nodeID] = append(pieces[nodeID], pointer.Remote.RootPieceId.Derive(nodeID, int32(n)))
+		}
+
+		data, err := proto.Marshal(pointer)
+		require.NoError(t, err)
+		path := storj.JoinPaths(testrand.UUID().String(), "l", "bucket", "object")
+		require.NoError(t, db.Put(ctx, storage.Key(path), data))
+	}
+	return pieces
+}
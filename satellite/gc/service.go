@@ -5,11 +5,13 @@ package gc
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/spacemonkeygo/monkit/v3"
 	"github.com/zeebo/errs"
 	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
 
 	"storj.io/common/bloomfilter"
 	"storj.io/common/pb"
@@ -36,19 +38,30 @@ type Config struct {
 	FalsePositiveRate float64       `help:"the false positive rate used for creating a garbage collection bloom filter" releaseDefault:"0.1" devDefault:"0.1"`
 	ConcurrentSends   int           `help:"the number of nodes to concurrently send garbage collection bloom filters to" releaseDefault:"1" devDefault:"1"`
 	RetainSendTimeout time.Duration `help:"the amount of time to allow a node to handle a retain request" default:"1m"`
+	RetryInterval     time.Duration `help:"the time between attempts to resend garbage collection filters to nodes that could not be reached" releaseDefault:"1h" devDefault:"1m"`
+	FilterExpiration  time.Duration `help:"how long garbage collection filters are kept and resent to unreachable nodes" releaseDefault:"120h" devDefault:"10m"`
+	NodeShards        int           `help:"the number of node ID shards, whose filters are built in separate metainfo loop iterations to limit memory usage" default:"1"`
 }
 
+// retainFilterBatchSize is the number of stored filters loaded at once for sending.
+const retainFilterBatchSize = 100
+
 // Service implements the garbage collection service
 //
 // architecture: Chore
 type Service struct {
-	log    *zap.Logger
-	config Config
-	Loop   *sync2.Cycle
+	log       *zap.Logger
+	config    Config
+	Loop      *sync2.Cycle
+	RetryLoop *sync2.Cycle
 
 	dialer       rpc.Dialer
+	db           DB
 	overlay      overlay.DB
 	metainfoLoop *metainfo.Loop
+
+	// sendMu ensures stored filters are not sent concurrently by both loops
+	sendMu sync.Mutex
 }
 
 // RetainInfo contains info needed for a storage node to retain important data and delete garbage data
@@ -59,13 +72,15 @@ type RetainInfo struct {
 }
 
 // NewService creates a new instance of the gc service
-func NewService(log *zap.Logger, config Config, dialer rpc.Dialer, overlay overlay.DB, loop *metainfo.Loop) *Service {
+func NewService(log *zap.Logger, config Config, dialer rpc.Dialer, db DB, overlay overlay.DB, loop *metainfo.Loop) *Service {
 	return &Service{
-		log:    log,
-		config: config,
-		Loop:   sync2.NewCycle(config.Interval),
+		log:       log,
+		config:    config,
+		Loop:      sync2.NewCycle(config.Interval),
+		RetryLoop: sync2.NewCycle(config.RetryInterval),
 
 		dialer:       dialer,
+		db:           db,
 		overlay:      overlay,
 		metainfoLoop: loop,
 	}
@@ -88,61 +103,171 @@ func (service *Service) Run(ctx context.Context) (err error) {
 		lastPieceCounts = make(map[storj.NodeID]int)
 	}
 
-	return service.Loop.Run(ctx, func(ctx context.Context) (err error) {
-		defer mon.Task()(&ctx)(&err)
+	group, ctx := errgroup.WithContext(ctx)
 
-		pieceTracker := NewPieceTracker(service.log.Named("gc observer"), service.config, lastPieceCounts)
+	group.Go(func() error {
+		return service.Loop.Run(ctx, func(ctx context.Context) (err error) {
+			defer mon.Task()(&ctx)(&err)
 
-		// collect things to retain
-		err = service.metainfoLoop.Join(ctx, pieceTracker)
-		if err != nil {
-			service.log.Error("error joining metainfoloop", zap.Error(err))
+			pieceCounts := make(map[storj.NodeID]int)
+
+			// only the filters of one shard are held in memory at once
+			shards := service.config.NodeShards
+			if shards < 1 {
+				shards = 1
+			}
+			for shard := 0; shard < shards; shard++ {
+				err = service.collectShard(ctx, shard, shards, lastPieceCounts, pieceCounts)
+				if err != nil {
+					service.log.Error("error joining metainfoloop", zap.Int("Shard", shard), zap.Error(err))
+					return nil
+				}
+			}
+
+			// save piece counts in memory for next iteration
+			for id := range lastPieceCounts {
+				delete(lastPieceCounts, id)
+			}
+			for id, count := range pieceCounts {
+				lastPieceCounts[id] = count
+			}
+
+			// save piece counts to db for next satellite restart
+			err = service.overlay.UpdatePieceCounts(ctx, lastPieceCounts)
+			if err != nil {
+				service.log.Error("error updating piece counts", zap.Error(err))
+			}
+
+			service.sendStoredFilters(ctx)
 			return nil
-		}
+		})
+	})
 
-		// save piece counts in memory for next iteration
-		for id := range lastPieceCounts {
-			delete(lastPieceCounts, id)
-		}
-		for id, info := range pieceTracker.retainInfos {
-			lastPieceCounts[id] = info.Count
-		}
+	group.Go(func() error {
+		return service.RetryLoop.Run(ctx, func(ctx context.Context) (err error) {
+			defer mon.Task()(&ctx)(&err)
+
+			deleted, err := service.db.DeleteExpiredRetainFilters(ctx, time.Now().Add(-service.config.FilterExpiration))
+			if err != nil {
+				service.log.Error("error deleting expired retain filters", zap.Error(err))
+			}
+			mon.IntVal("retain_filters_expired").Observe(deleted)
+
+			service.sendStoredFilters(ctx)
+			return nil
+		})
+	})
+
+	return group.Wait()
+}
+
+// collectShard builds the filters of the nodes in the shard during one metainfo
+// loop iteration and stores them, so they can be sent and resent later.
+func (service *Service) collectShard(ctx context.Context, shard, shards int, lastPieceCounts, pieceCounts map[storj.NodeID]int) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	pieceTracker := NewPieceTracker(service.log.Named("gc observer"), service.config, lastPieceCounts)
+	pieceTracker.shard, pieceTracker.shards = shard, shards
+
+	// collect things to retain
+	err = service.metainfoLoop.Join(ctx, pieceTracker)
+	if err != nil {
+		return err
+	}
+
+	for id, info := range pieceTracker.retainInfos {
+		pieceCounts[id] = info.Count
+
+		// monitor information
+		mon.IntVal("node_piece_count").Observe(int64(info.Count))
+		mon.IntVal("retain_filter_size_bytes").Observe(info.Filter.Size())
 
-		// save piece counts to db for next satellite restart
-		err = service.overlay.UpdatePieceCounts(ctx, lastPieceCounts)
+		// store the filters, so they can be resent when a node is unreachable
+		err = service.db.SetRetainFilter(ctx, RetainFilter{
+			NodeID:       id,
+			CreationDate: info.CreationDate,
+			PieceCount:   info.Count,
+			Filter:       info.Filter.Bytes(),
+		})
 		if err != nil {
-			service.log.Error("error updating piece counts", zap.Error(err))
+			service.log.Error("error storing retain filter", zap.Stringer("Node ID", id), zap.Error(err))
 		}
+		delete(pieceTracker.retainInfos, id)
+	}
 
-		// monitor information
-		for _, info := range pieceTracker.retainInfos {
-			mon.IntVal("node_piece_count").Observe(int64(info.Count))
-			mon.IntVal("retain_filter_size_bytes").Observe(info.Filter.Size())
+	return nil
+}
+
+// Close stops the gc loops.
+func (service *Service) Close() error {
+	service.Loop.Close()
+	service.RetryLoop.Close()
+	return nil
+}
+
+// sendStoredFilters sends all stored filters, which have not been sent yet and have not expired.
+func (service *Service) sendStoredFilters(ctx context.Context) {
+	defer mon.Task()(&ctx)(nil)
+
+	service.sendMu.Lock()
+	defer service.sendMu.Unlock()
+
+	createdAfter := time.Now().Add(-service.config.FilterExpiration)
+
+	var cursor storj.NodeID
+	var sent, failed int64
+	for {
+		filters, err := service.db.ListUnsentRetainFilters(ctx, cursor, createdAfter, retainFilterBatchSize)
+		if err != nil {
+			service.log.Error("error listing unsent retain filters", zap.Error(err))
+			return
+		}
+		if len(filters) == 0 {
+			break
 		}
 
-		// send retain requests
+		var mu sync.Mutex
 		limiter := sync2.NewLimiter(service.config.ConcurrentSends)
-		for id, info := range pieceTracker.retainInfos {
-			id, info := id, info
+		for _, filter := range filters {
+			filter := filter
 			limiter.Go(ctx, func() {
-				err := service.sendRetainRequest(ctx, id, info)
+				err := service.sendRetainRequest(ctx, filter)
 				if err != nil {
-					service.log.Error("error sending retain info to node", zap.Stringer("Node ID", id), zap.Error(err))
+					service.log.Error("error sending retain info to node", zap.Stringer("Node ID", filter.NodeID), zap.Error(err))
+				}
+
+				recordErr := service.db.RecordRetainFilterSend(ctx, filter.NodeID, filter.CreationDate, err == nil, time.Now().UTC())
+				if recordErr != nil {
+					service.log.Error("error recording retain filter send", zap.Stringer("Node ID", filter.NodeID), zap.Error(recordErr))
+				}
+
+				mu.Lock()
+				defer mu.Unlock()
+				if err == nil {
+					sent++
+				} else {
+					failed++
 				}
 			})
 		}
 		limiter.Wait()
 
-		return nil
-	})
+		if ctx.Err() != nil {
+			return
+		}
+		cursor = filters[len(filters)-1].NodeID
+	}
+
+	mon.IntVal("retain_filters_sent").Observe(sent)
+	mon.IntVal("retain_filters_failed").Observe(failed)
 }
 
-func (service *Service) sendRetainRequest(ctx context.Context, id storj.NodeID, info *RetainInfo) (err error) {
-	defer mon.Task()(&ctx, id.String())(&err)
+func (service *Service) sendRetainRequest(ctx context.Context, filter RetainFilter) (err error) {
+	defer mon.Task()(&ctx, filter.NodeID.String())(&err)
 
-	log := service.log.Named(id.String())
+	log := service.log.Named(filter.NodeID.String())
 
-	dossier, err := service.overlay.Get(ctx, id)
+	dossier, err := service.overlay.Get(ctx, filter.NodeID)
 	if err != nil {
 		return Error.Wrap(err)
 	}
@@ -162,8 +287,8 @@ func (service *Service) sendRetainRequest(ctx context.Context, id storj.NodeID,
 	}()
 
 	err = client.Retain(ctx, &pb.RetainRequest{
-		CreationDate: info.CreationDate,
-		Filter:       info.Filter.Bytes(),
+		CreationDate: filter.CreationDate,
+		Filter:       filter.Filter,
 	})
 	return Error.Wrap(err)
 }
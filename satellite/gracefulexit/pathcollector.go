@@ -16,7 +16,7 @@ import (
 	"storj.io/uplink/eestream"
 )
 
-var _ metainfo.Observer = (*PathCollector)(nil)
+var _ metainfo.ConcurrentObserver = (*PathCollector)(nil)
 
 // PathCollector uses the metainfo loop to add paths to node reservoirs
 //
@@ -52,9 +52,16 @@ func NewPathCollector(db DB, nodeIDs storj.NodeIDList, log *zap.Logger, batchSiz
 
 // Flush persists the current buffer items to the database.
 func (collector *PathCollector) Flush(ctx context.Context) (err error) {
+	collector.nodeIDMutex.Lock()
+	defer collector.nodeIDMutex.Unlock()
+
 	return collector.flush(ctx, 1)
 }
 
+// ConcurrentSafe marks the path collector as safe for concurrent use, since
+// the node storage and the buffer are protected by the mutex.
+func (collector *PathCollector) ConcurrentSafe() {}
+
 // RemoteSegment takes a remote segment found in metainfo and creates a graceful exit transfer queue item if it doesn't exist already
 func (collector *PathCollector) RemoteSegment(ctx context.Context, path metainfo.ScopedPath, pointer *pb.Pointer) (err error) {
 	if len(collector.nodeIDStorage) == 0 {
@@ -0,0 +1,105 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gracefulexit_test
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/common/errs2"
+	"storj.io/common/pb"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/satellite/gracefulexit"
+	"storj.io/storj/satellite/metainfo"
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/teststore"
+)
+
+func TestPathCollectorParallel(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	db := teststore.New()
+	nodes := make([]storj.NodeID, 5)
+	for i := range nodes {
+		nodes[i] = testrand.NodeID()
+	}
+
+	for i := 0; i < 40; i++ {
+		pointer := &pb.Pointer{
+			Type: pb.Pointer_REMOTE,
+			Remote: &pb.RemoteSegment{
+				Redundancy:  &pb.RedundancyScheme{MinReq: 1, Total: 3, RepairThreshold: 2, SuccessThreshold: 3, ErasureShareSize: 256},
+				RootPieceId: testrand.PieceID(),
+			},
+			SegmentSize: 1024,
+		}
+		for n := 0; n < 3; n++ {
+			pointer.Remote.RemotePieces = append(pointer.Remote.RemotePieces, &pb.RemotePiece{
+				PieceNum: int32(n),
+				NodeId:   nodes[(i+n)%len(nodes)],
+			})
+		}
+
+		data, err := proto.Marshal(pointer)
+		require.NoError(t, err)
+		path := storj.JoinPaths(testrand.UUID().String(), "l", "bucket", "object")
+		require.NoError(t, db.Put(ctx, storage.Key(path), data))
+	}
+
+	exiting := storj.NodeIDList{nodes[0], nodes[3]}
+
+	serialDB := &enqueueDB{}
+	serial := gracefulexit.NewPathCollector(serialDB, exiting, zaptest.NewLogger(t), 7)
+	require.NoError(t, metainfo.IterateDatabase(ctx, 0, db, serial))
+	require.NoError(t, serial.Flush(ctx))
+	require.Len(t, serialDB.items, 48)
+
+	metaLoop := metainfo.NewLoop(metainfo.LoopConfig{Workers: 8}, db, nil)
+	loopCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ctx.Go(func() error {
+		return errs2.IgnoreCanceled(metaLoop.Run(loopCtx))
+	})
+
+	parallelDB := &enqueueDB{}
+	parallel := gracefulexit.NewPathCollector(parallelDB, exiting, zaptest.NewLogger(t), 7)
+	require.NoError(t, metaLoop.Join(ctx, parallel))
+	require.NoError(t, parallel.Flush(ctx))
+
+	require.Equal(t, serialDB.sorted(), parallelDB.sorted())
+}
+
+// enqueueDB records the items enqueued to the transfer queue.
+type enqueueDB struct {
+	gracefulexit.DB
+
+	mu    sync.Mutex
+	items []gracefulexit.TransferQueueItem
+}
+
+func (db *enqueueDB) Enqueue(ctx context.Context, items []gracefulexit.TransferQueueItem) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.items = append(db.items, items...)
+	return nil
+}
+
+func (db *enqueueDB) sorted() []gracefulexit.TransferQueueItem {
+	sort.Slice(db.items, func(i, k int) bool {
+		if db.items[i].NodeID != db.items[k].NodeID {
+			return db.items[i].NodeID.Less(db.items[k].NodeID)
+		}
+		return string(db.items[i].Path) < string(db.items[k].Path)
+	})
+	return db.items
+}
@@ -0,0 +1,393 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: garbagecollection.proto
+
+package internalpb
+
+import (
+	context "context"
+	fmt "fmt"
+	math "math"
+	time "time"
+
+	_ "github.com/gogo/protobuf/gogoproto"
+	proto "github.com/gogo/protobuf/proto"
+	_ "github.com/golang/protobuf/ptypes/timestamp"
+	grpc "google.golang.org/grpc"
+
+	drpc "storj.io/drpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+var _ = time.Kitchen
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
+
+type ListRetainFiltersRequest struct {
+	CursorNodeId         []byte   `protobuf:"bytes,1,opt,name=cursor_node_id,json=cursorNodeId,proto3" json:"cursor_node_id,omitempty"`
+	Limit                int32    `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListRetainFiltersRequest) Reset()         { *m = ListRetainFiltersRequest{} }
+func (m *ListRetainFiltersRequest) String() string { return proto.CompactTextString(m) }
+func (*ListRetainFiltersRequest) ProtoMessage()    {}
+func (*ListRetainFiltersRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2ffeb89b94576daa, []int{0}
+}
+func (m *ListRetainFiltersRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListRetainFiltersRequest.Unmarshal(m, b)
+}
+func (m *ListRetainFiltersRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListRetainFiltersRequest.Marshal(b, m, deterministic)
+}
+func (m *ListRetainFiltersRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListRetainFiltersRequest.Merge(m, src)
+}
+func (m *ListRetainFiltersRequest) XXX_Size() int {
+	return xxx_messageInfo_ListRetainFiltersRequest.Size(m)
+}
+func (m *ListRetainFiltersRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListRetainFiltersRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListRetainFiltersRequest proto.InternalMessageInfo
+
+func (m *ListRetainFiltersRequest) GetCursorNodeId() []byte {
+	if m != nil {
+		return m.CursorNodeId
+	}
+	return nil
+}
+
+func (m *ListRetainFiltersRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type RetainFilterStatus struct {
+	NodeId               []byte     `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	CreationDate         time.Time  `protobuf:"bytes,2,opt,name=creation_date,json=creationDate,proto3,stdtime" json:"creation_date"`
+	PieceCount           int64      `protobuf:"varint,3,opt,name=piece_count,json=pieceCount,proto3" json:"piece_count,omitempty"`
+	FilterSize           int64      `protobuf:"varint,4,opt,name=filter_size,json=filterSize,proto3" json:"filter_size,omitempty"`
+	SentAt               *time.Time `protobuf:"bytes,5,opt,name=sent_at,json=sentAt,proto3,stdtime" json:"sent_at,omitempty"`
+	SendAttempts         int32      `protobuf:"varint,6,opt,name=send_attempts,json=sendAttempts,proto3" json:"send_attempts,omitempty"`
+	LastAttemptAt        *time.Time `protobuf:"bytes,7,opt,name=last_attempt_at,json=lastAttemptAt,proto3,stdtime" json:"last_attempt_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *RetainFilterStatus) Reset()         { *m = RetainFilterStatus{} }
+func (m *RetainFilterStatus) String() string { return proto.CompactTextString(m) }
+func (*RetainFilterStatus) ProtoMessage()    {}
+func (*RetainFilterStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2ffeb89b94576daa, []int{1}
+}
+func (m *RetainFilterStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RetainFilterStatus.Unmarshal(m, b)
+}
+func (m *RetainFilterStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RetainFilterStatus.Marshal(b, m, deterministic)
+}
+func (m *RetainFilterStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RetainFilterStatus.Merge(m, src)
+}
+func (m *RetainFilterStatus) XXX_Size() int {
+	return xxx_messageInfo_RetainFilterStatus.Size(m)
+}
+func (m *RetainFilterStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_RetainFilterStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RetainFilterStatus proto.InternalMessageInfo
+
+func (m *RetainFilterStatus) GetNodeId() []byte {
+	if m != nil {
+		return m.NodeId
+	}
+	return nil
+}
+
+func (m *RetainFilterStatus) GetCreationDate() time.Time {
+	if m != nil {
+		return m.CreationDate
+	}
+	return time.Time{}
+}
+
+func (m *RetainFilterStatus) GetPieceCount() int64 {
+	if m != nil {
+		return m.PieceCount
+	}
+	return 0
+}
+
+func (m *RetainFilterStatus) GetFilterSize() int64 {
+	if m != nil {
+		return m.FilterSize
+	}
+	return 0
+}
+
+func (m *RetainFilterStatus) GetSentAt() *time.Time {
+	if m != nil {
+		return m.SentAt
+	}
+	return nil
+}
+
+func (m *RetainFilterStatus) GetSendAttempts() int32 {
+	if m != nil {
+		return m.SendAttempts
+	}
+	return 0
+}
+
+func (m *RetainFilterStatus) GetLastAttemptAt() *time.Time {
+	if m != nil {
+		return m.LastAttemptAt
+	}
+	return nil
+}
+
+type ListRetainFiltersResponse struct {
+	Filters              []*RetainFilterStatus `protobuf:"bytes,1,rep,name=filters,proto3" json:"filters,omitempty"`
+	More                 bool                  `protobuf:"varint,2,opt,name=more,proto3" json:"more,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *ListRetainFiltersResponse) Reset()         { *m = ListRetainFiltersResponse{} }
+func (m *ListRetainFiltersResponse) String() string { return proto.CompactTextString(m) }
+func (*ListRetainFiltersResponse) ProtoMessage()    {}
+func (*ListRetainFiltersResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2ffeb89b94576daa, []int{2}
+}
+func (m *ListRetainFiltersResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListRetainFiltersResponse.Unmarshal(m, b)
+}
+func (m *ListRetainFiltersResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListRetainFiltersResponse.Marshal(b, m, deterministic)
+}
+func (m *ListRetainFiltersResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListRetainFiltersResponse.Merge(m, src)
+}
+func (m *ListRetainFiltersResponse) XXX_Size() int {
+	return xxx_messageInfo_ListRetainFiltersResponse.Size(m)
+}
+func (m *ListRetainFiltersResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListRetainFiltersResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListRetainFiltersResponse proto.InternalMessageInfo
+
+func (m *ListRetainFiltersResponse) GetFilters() []*RetainFilterStatus {
+	if m != nil {
+		return m.Filters
+	}
+	return nil
+}
+
+func (m *ListRetainFiltersResponse) GetMore() bool {
+	if m != nil {
+		return m.More
+	}
+	return false
+}
+
+func init() {
+	proto.RegisterType((*ListRetainFiltersRequest)(nil), "satellite.gc.ListRetainFiltersRequest")
+	proto.RegisterType((*RetainFilterStatus)(nil), "satellite.gc.RetainFilterStatus")
+	proto.RegisterType((*ListRetainFiltersResponse)(nil), "satellite.gc.ListRetainFiltersResponse")
+}
+
+func init() { proto.RegisterFile("garbagecollection.proto", fileDescriptor_2ffeb89b94576daa) }
+
+var fileDescriptor_2ffeb89b94576daa = []byte{
+	// 438 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x52, 0xc1, 0x6e, 0xd3, 0x40,
+	0x10, 0xc5, 0x4d, 0x93, 0x54, 0x13, 0x07, 0xc4, 0x0a, 0xa9, 0xc6, 0x97, 0x58, 0x01, 0x41, 0x4e,
+	0xae, 0x14, 0x6e, 0x48, 0x1c, 0xd2, 0x22, 0x50, 0xa4, 0x8a, 0x83, 0x8b, 0x38, 0x70, 0xb1, 0x36,
+	0xf6, 0xd4, 0x5a, 0xb1, 0xde, 0x35, 0x3b, 0xe3, 0x4b, 0x8f, 0x7c, 0x01, 0x9f, 0xc5, 0x57, 0xc0,
+	0x0f, 0xf0, 0x11, 0xc8, 0xde, 0x1a, 0xb5, 0x0a, 0x08, 0x7a, 0xf3, 0x3e, 0xbf, 0x79, 0x33, 0x6f,
+	0xe6, 0xc1, 0x71, 0x25, 0xdd, 0x4e, 0x56, 0x58, 0x58, 0xad, 0xb1, 0x60, 0x65, 0x4d, 0xda, 0x38,
+	0xcb, 0x56, 0x84, 0x24, 0x19, 0xb5, 0x56, 0x8c, 0x69, 0x55, 0xc4, 0x50, 0xd9, 0xca, 0xfa, 0x3f,
+	0xf1, 0xa2, 0xb2, 0xb6, 0xd2, 0x78, 0xd2, 0xbf, 0x76, 0xed, 0xe5, 0x09, 0xab, 0x1a, 0x89, 0x65,
+	0xdd, 0x78, 0xc2, 0xf2, 0x03, 0x44, 0xe7, 0x8a, 0x38, 0x43, 0x96, 0xca, 0xbc, 0x51, 0x9a, 0xd1,
+	0x51, 0x86, 0x9f, 0x5b, 0x24, 0x16, 0x4f, 0xe1, 0x7e, 0xd1, 0x3a, 0xb2, 0x2e, 0x37, 0xb6, 0xc4,
+	0x5c, 0x95, 0x51, 0x90, 0x04, 0xab, 0x30, 0x0b, 0x3d, 0xfa, 0xce, 0x96, 0xb8, 0x2d, 0xc5, 0x23,
+	0x18, 0x6b, 0x55, 0x2b, 0x8e, 0x0e, 0x92, 0x60, 0x35, 0xce, 0xfc, 0x63, 0xf9, 0xf3, 0x00, 0xc4,
+	0x4d, 0xd1, 0x0b, 0x96, 0xdc, 0x92, 0x38, 0x86, 0xe9, 0x6d, 0xad, 0x89, 0xf1, 0x2a, 0x5b, 0x98,
+	0x17, 0x0e, 0x65, 0x67, 0x2a, 0x2f, 0x25, 0x63, 0xaf, 0x36, 0x5b, 0xc7, 0xa9, 0x37, 0x90, 0x0e,
+	0x06, 0xd2, 0xf7, 0x83, 0x81, 0xd3, 0xa3, 0x6f, 0xdf, 0x17, 0xf7, 0xbe, 0xfe, 0x58, 0x04, 0x59,
+	0x38, 0x94, 0xbe, 0x96, 0x8c, 0x62, 0x01, 0xb3, 0x46, 0x61, 0x81, 0x79, 0x61, 0x5b, 0xc3, 0xd1,
+	0x28, 0x09, 0x56, 0xa3, 0x0c, 0x7a, 0xe8, 0xac, 0x43, 0x3a, 0xc2, 0x65, 0x3f, 0x54, 0x4e, 0xea,
+	0x0a, 0xa3, 0x43, 0x4f, 0xf0, 0xd0, 0x85, 0xba, 0x42, 0xf1, 0x0a, 0xa6, 0x84, 0x86, 0x73, 0xc9,
+	0xd1, 0xf8, 0xbf, 0xc6, 0x08, 0xfa, 0x31, 0x26, 0x5d, 0xd1, 0x86, 0xc5, 0x13, 0x98, 0x13, 0x9a,
+	0x32, 0x97, 0xcc, 0x58, 0x37, 0x4c, 0xd1, 0xa4, 0xdf, 0x4c, 0xd8, 0x81, 0x9b, 0x6b, 0x4c, 0x9c,
+	0xc3, 0x03, 0x2d, 0x89, 0x07, 0x52, 0xd7, 0x6b, 0x7a, 0x87, 0x5e, 0xf3, 0xae, 0xf8, 0x5a, 0x6c,
+	0xc3, 0xcb, 0x4f, 0xf0, 0xf8, 0x0f, 0x67, 0xa4, 0xc6, 0x1a, 0x42, 0xf1, 0x12, 0xa6, 0xde, 0x1c,
+	0x45, 0x41, 0x32, 0x5a, 0xcd, 0xd6, 0x49, 0x7a, 0x33, 0x30, 0xe9, 0xfe, 0x9d, 0xb2, 0xa1, 0x40,
+	0x08, 0x38, 0xac, 0xad, 0xf3, 0xe7, 0x38, 0xca, 0xfa, 0xef, 0xf5, 0x97, 0x00, 0xe2, 0xb7, 0x3e,
+	0x8a, 0x67, 0xbf, 0xa3, 0xb8, 0x35, 0xd4, 0x60, 0xc1, 0xd6, 0x89, 0x12, 0x1e, 0xee, 0xcd, 0x22,
+	0x9e, 0xdd, 0x6e, 0xf9, 0xb7, 0xcc, 0xc5, 0xcf, 0xff, 0xc9, 0xf3, 0xa6, 0x4e, 0xc3, 0x8f, 0xa0,
+	0x0c, 0xa3, 0x33, 0x52, 0x37, 0xbb, 0xdd, 0xa4, 0x5f, 0xd6, 0x8b, 0x5f, 0x03, 0x00, 0xba, 0x54,
+	0xe4, 0xfb, 0x23, 0x03, 0x00, 0x00,
+}
+
+type DRPCGarbageCollectionInspectorClient interface {
+	DRPCConn() drpc.Conn
+
+	// ListRetainFilters returns which filter generation every node has and whether it was sent
+	ListRetainFilters(ctx context.Context, in *ListRetainFiltersRequest) (*ListRetainFiltersResponse, error)
+}
+
+type drpcGarbageCollectionInspectorClient struct {
+	cc drpc.Conn
+}
+
+func NewDRPCGarbageCollectionInspectorClient(cc drpc.Conn) DRPCGarbageCollectionInspectorClient {
+	return &drpcGarbageCollectionInspectorClient{cc}
+}
+
+func (c *drpcGarbageCollectionInspectorClient) DRPCConn() drpc.Conn { return c.cc }
+
+func (c *drpcGarbageCollectionInspectorClient) ListRetainFilters(ctx context.Context, in *ListRetainFiltersRequest) (*ListRetainFiltersResponse, error) {
+	out := new(ListRetainFiltersResponse)
+	err := c.cc.Invoke(ctx, "/satellite.gc.GarbageCollectionInspector/ListRetainFilters", in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+type DRPCGarbageCollectionInspectorServer interface {
+	// ListRetainFilters returns which filter generation every node has and whether it was sent
+	ListRetainFilters(context.Context, *ListRetainFiltersRequest) (*ListRetainFiltersResponse, error)
+}
+
+type DRPCGarbageCollectionInspectorDescription struct{}
+
+func (DRPCGarbageCollectionInspectorDescription) NumMethods() int { return 1 }
+
+func (DRPCGarbageCollectionInspectorDescription) Method(n int) (string, drpc.Handler, interface{}, bool) {
+	switch n {
+	case 0:
+		return "/satellite.gc.GarbageCollectionInspector/ListRetainFilters",
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCGarbageCollectionInspectorServer).
+					ListRetainFilters(
+						ctx,
+						in1.(*ListRetainFiltersRequest),
+					)
+			}, DRPCGarbageCollectionInspectorServer.ListRetainFilters, true
+	default:
+		return "", nil, nil, false
+	}
+}
+
+func DRPCRegisterGarbageCollectionInspector(srv drpc.Server, impl DRPCGarbageCollectionInspectorServer) {
+	srv.Register(impl, DRPCGarbageCollectionInspectorDescription{})
+}
+
+type DRPCGarbageCollectionInspector_ListRetainFiltersStream interface {
+	drpc.Stream
+	SendAndClose(*ListRetainFiltersResponse) error
+}
+
+type drpcGarbageCollectionInspectorListRetainFiltersStream struct {
+	drpc.Stream
+}
+
+func (x *drpcGarbageCollectionInspectorListRetainFiltersStream) SendAndClose(m *ListRetainFiltersResponse) error {
+	if err := x.MsgSend(m); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// GarbageCollectionInspectorClient is the client API for GarbageCollectionInspector service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type GarbageCollectionInspectorClient interface {
+	// ListRetainFilters returns which filter generation every node has and whether it was sent
+	ListRetainFilters(ctx context.Context, in *ListRetainFiltersRequest, opts ...grpc.CallOption) (*ListRetainFiltersResponse, error)
+}
+
+type garbageCollectionInspectorClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewGarbageCollectionInspectorClient(cc *grpc.ClientConn) GarbageCollectionInspectorClient {
+	return &garbageCollectionInspectorClient{cc}
+}
+
+func (c *garbageCollectionInspectorClient) ListRetainFilters(ctx context.Context, in *ListRetainFiltersRequest, opts ...grpc.CallOption) (*ListRetainFiltersResponse, error) {
+	out := new(ListRetainFiltersResponse)
+	err := c.cc.Invoke(ctx, "/satellite.gc.GarbageCollectionInspector/ListRetainFilters", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GarbageCollectionInspectorServer is the server API for GarbageCollectionInspector service.
+type GarbageCollectionInspectorServer interface {
+	// ListRetainFilters returns which filter generation every node has and whether it was sent
+	ListRetainFilters(context.Context, *ListRetainFiltersRequest) (*ListRetainFiltersResponse, error)
+}
+
+func RegisterGarbageCollectionInspectorServer(s *grpc.Server, srv GarbageCollectionInspectorServer) {
+	s.RegisterService(&_GarbageCollectionInspector_serviceDesc, srv)
+}
+
+func _GarbageCollectionInspector_ListRetainFilters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRetainFiltersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GarbageCollectionInspectorServer).ListRetainFilters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/satellite.gc.GarbageCollectionInspector/ListRetainFilters",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GarbageCollectionInspectorServer).ListRetainFilters(ctx, req.(*ListRetainFiltersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _GarbageCollectionInspector_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "satellite.gc.GarbageCollectionInspector",
+	HandlerType: (*GarbageCollectionInspectorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListRetainFilters",
+			Handler:    _GarbageCollectionInspector_ListRetainFilters_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "garbagecollection.proto",
+}
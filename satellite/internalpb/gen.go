@@ -0,0 +1,8 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package internalpb contains protobuf definitions for services which are
+// internal to the satellite, such as the private inspector endpoints.
+package internalpb
+
+//go:generate protoc -I=. --drpc_out=plugins=grpc+drpc:. garbagecollection.proto repairqueue.proto
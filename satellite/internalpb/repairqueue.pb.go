@@ -0,0 +1,817 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: repairqueue.proto
+
+package internalpb
+
+import (
+	context "context"
+	fmt "fmt"
+	math "math"
+	time "time"
+
+	_ "github.com/gogo/protobuf/gogoproto"
+	proto "github.com/gogo/protobuf/proto"
+	_ "github.com/golang/protobuf/ptypes/timestamp"
+	grpc "google.golang.org/grpc"
+
+	drpc "storj.io/drpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+var _ = time.Kitchen
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
+
+type ListRepairQueueRequest struct {
+	CursorPath           []byte   `protobuf:"bytes,1,opt,name=cursor_path,json=cursorPath,proto3" json:"cursor_path,omitempty"`
+	Limit                int32    `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListRepairQueueRequest) Reset()         { *m = ListRepairQueueRequest{} }
+func (m *ListRepairQueueRequest) String() string { return proto.CompactTextString(m) }
+func (*ListRepairQueueRequest) ProtoMessage()    {}
+func (*ListRepairQueueRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_40f6419c38c04cb7, []int{0}
+}
+func (m *ListRepairQueueRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListRepairQueueRequest.Unmarshal(m, b)
+}
+func (m *ListRepairQueueRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListRepairQueueRequest.Marshal(b, m, deterministic)
+}
+func (m *ListRepairQueueRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListRepairQueueRequest.Merge(m, src)
+}
+func (m *ListRepairQueueRequest) XXX_Size() int {
+	return xxx_messageInfo_ListRepairQueueRequest.Size(m)
+}
+func (m *ListRepairQueueRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListRepairQueueRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListRepairQueueRequest proto.InternalMessageInfo
+
+func (m *ListRepairQueueRequest) GetCursorPath() []byte {
+	if m != nil {
+		return m.CursorPath
+	}
+	return nil
+}
+
+func (m *ListRepairQueueRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type QueuedSegment struct {
+	Path                 []byte     `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	LostPieces           []int32    `protobuf:"varint,2,rep,packed,name=lost_pieces,json=lostPieces,proto3" json:"lost_pieces,omitempty"`
+	SegmentHealth        float64    `protobuf:"fixed64,3,opt,name=segment_health,json=segmentHealth,proto3" json:"segment_health,omitempty"`
+	InsertedAt           time.Time  `protobuf:"bytes,4,opt,name=inserted_at,json=insertedAt,proto3,stdtime" json:"inserted_at"`
+	AttemptedAt          *time.Time `protobuf:"bytes,5,opt,name=attempted_at,json=attemptedAt,proto3,stdtime" json:"attempted_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *QueuedSegment) Reset()         { *m = QueuedSegment{} }
+func (m *QueuedSegment) String() string { return proto.CompactTextString(m) }
+func (*QueuedSegment) ProtoMessage()    {}
+func (*QueuedSegment) Descriptor() ([]byte, []int) {
+	return fileDescriptor_40f6419c38c04cb7, []int{1}
+}
+func (m *QueuedSegment) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueuedSegment.Unmarshal(m, b)
+}
+func (m *QueuedSegment) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueuedSegment.Marshal(b, m, deterministic)
+}
+func (m *QueuedSegment) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueuedSegment.Merge(m, src)
+}
+func (m *QueuedSegment) XXX_Size() int {
+	return xxx_messageInfo_QueuedSegment.Size(m)
+}
+func (m *QueuedSegment) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueuedSegment.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueuedSegment proto.InternalMessageInfo
+
+func (m *QueuedSegment) GetPath() []byte {
+	if m != nil {
+		return m.Path
+	}
+	return nil
+}
+
+func (m *QueuedSegment) GetLostPieces() []int32 {
+	if m != nil {
+		return m.LostPieces
+	}
+	return nil
+}
+
+func (m *QueuedSegment) GetSegmentHealth() float64 {
+	if m != nil {
+		return m.SegmentHealth
+	}
+	return 0
+}
+
+func (m *QueuedSegment) GetInsertedAt() time.Time {
+	if m != nil {
+		return m.InsertedAt
+	}
+	return time.Time{}
+}
+
+func (m *QueuedSegment) GetAttemptedAt() *time.Time {
+	if m != nil {
+		return m.AttemptedAt
+	}
+	return nil
+}
+
+type ListRepairQueueResponse struct {
+	Segments             []*QueuedSegment `protobuf:"bytes,1,rep,name=segments,proto3" json:"segments,omitempty"`
+	More                 bool             `protobuf:"varint,2,opt,name=more,proto3" json:"more,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *ListRepairQueueResponse) Reset()         { *m = ListRepairQueueResponse{} }
+func (m *ListRepairQueueResponse) String() string { return proto.CompactTextString(m) }
+func (*ListRepairQueueResponse) ProtoMessage()    {}
+func (*ListRepairQueueResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_40f6419c38c04cb7, []int{2}
+}
+func (m *ListRepairQueueResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListRepairQueueResponse.Unmarshal(m, b)
+}
+func (m *ListRepairQueueResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListRepairQueueResponse.Marshal(b, m, deterministic)
+}
+func (m *ListRepairQueueResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListRepairQueueResponse.Merge(m, src)
+}
+func (m *ListRepairQueueResponse) XXX_Size() int {
+	return xxx_messageInfo_ListRepairQueueResponse.Size(m)
+}
+func (m *ListRepairQueueResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListRepairQueueResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListRepairQueueResponse proto.InternalMessageInfo
+
+func (m *ListRepairQueueResponse) GetSegments() []*QueuedSegment {
+	if m != nil {
+		return m.Segments
+	}
+	return nil
+}
+
+func (m *ListRepairQueueResponse) GetMore() bool {
+	if m != nil {
+		return m.More
+	}
+	return false
+}
+
+type AddRepairQueueRequest struct {
+	Path                 []byte   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AddRepairQueueRequest) Reset()         { *m = AddRepairQueueRequest{} }
+func (m *AddRepairQueueRequest) String() string { return proto.CompactTextString(m) }
+func (*AddRepairQueueRequest) ProtoMessage()    {}
+func (*AddRepairQueueRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_40f6419c38c04cb7, []int{3}
+}
+func (m *AddRepairQueueRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddRepairQueueRequest.Unmarshal(m, b)
+}
+func (m *AddRepairQueueRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddRepairQueueRequest.Marshal(b, m, deterministic)
+}
+func (m *AddRepairQueueRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddRepairQueueRequest.Merge(m, src)
+}
+func (m *AddRepairQueueRequest) XXX_Size() int {
+	return xxx_messageInfo_AddRepairQueueRequest.Size(m)
+}
+func (m *AddRepairQueueRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddRepairQueueRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddRepairQueueRequest proto.InternalMessageInfo
+
+func (m *AddRepairQueueRequest) GetPath() []byte {
+	if m != nil {
+		return m.Path
+	}
+	return nil
+}
+
+type AddRepairQueueResponse struct {
+	SegmentHealth        float64  `protobuf:"fixed64,1,opt,name=segment_health,json=segmentHealth,proto3" json:"segment_health,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AddRepairQueueResponse) Reset()         { *m = AddRepairQueueResponse{} }
+func (m *AddRepairQueueResponse) String() string { return proto.CompactTextString(m) }
+func (*AddRepairQueueResponse) ProtoMessage()    {}
+func (*AddRepairQueueResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_40f6419c38c04cb7, []int{4}
+}
+func (m *AddRepairQueueResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddRepairQueueResponse.Unmarshal(m, b)
+}
+func (m *AddRepairQueueResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddRepairQueueResponse.Marshal(b, m, deterministic)
+}
+func (m *AddRepairQueueResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddRepairQueueResponse.Merge(m, src)
+}
+func (m *AddRepairQueueResponse) XXX_Size() int {
+	return xxx_messageInfo_AddRepairQueueResponse.Size(m)
+}
+func (m *AddRepairQueueResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddRepairQueueResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddRepairQueueResponse proto.InternalMessageInfo
+
+func (m *AddRepairQueueResponse) GetSegmentHealth() float64 {
+	if m != nil {
+		return m.SegmentHealth
+	}
+	return 0
+}
+
+type RemoveRepairQueueRequest struct {
+	Path                 []byte   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RemoveRepairQueueRequest) Reset()         { *m = RemoveRepairQueueRequest{} }
+func (m *RemoveRepairQueueRequest) String() string { return proto.CompactTextString(m) }
+func (*RemoveRepairQueueRequest) ProtoMessage()    {}
+func (*RemoveRepairQueueRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_40f6419c38c04cb7, []int{5}
+}
+func (m *RemoveRepairQueueRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RemoveRepairQueueRequest.Unmarshal(m, b)
+}
+func (m *RemoveRepairQueueRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RemoveRepairQueueRequest.Marshal(b, m, deterministic)
+}
+func (m *RemoveRepairQueueRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RemoveRepairQueueRequest.Merge(m, src)
+}
+func (m *RemoveRepairQueueRequest) XXX_Size() int {
+	return xxx_messageInfo_RemoveRepairQueueRequest.Size(m)
+}
+func (m *RemoveRepairQueueRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RemoveRepairQueueRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RemoveRepairQueueRequest proto.InternalMessageInfo
+
+func (m *RemoveRepairQueueRequest) GetPath() []byte {
+	if m != nil {
+		return m.Path
+	}
+	return nil
+}
+
+type RemoveRepairQueueResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RemoveRepairQueueResponse) Reset()         { *m = RemoveRepairQueueResponse{} }
+func (m *RemoveRepairQueueResponse) String() string { return proto.CompactTextString(m) }
+func (*RemoveRepairQueueResponse) ProtoMessage()    {}
+func (*RemoveRepairQueueResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_40f6419c38c04cb7, []int{6}
+}
+func (m *RemoveRepairQueueResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RemoveRepairQueueResponse.Unmarshal(m, b)
+}
+func (m *RemoveRepairQueueResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RemoveRepairQueueResponse.Marshal(b, m, deterministic)
+}
+func (m *RemoveRepairQueueResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RemoveRepairQueueResponse.Merge(m, src)
+}
+func (m *RemoveRepairQueueResponse) XXX_Size() int {
+	return xxx_messageInfo_RemoveRepairQueueResponse.Size(m)
+}
+func (m *RemoveRepairQueueResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RemoveRepairQueueResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RemoveRepairQueueResponse proto.InternalMessageInfo
+
+type RepairSegmentRequest struct {
+	Path                 []byte   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RepairSegmentRequest) Reset()         { *m = RepairSegmentRequest{} }
+func (m *RepairSegmentRequest) String() string { return proto.CompactTextString(m) }
+func (*RepairSegmentRequest) ProtoMessage()    {}
+func (*RepairSegmentRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_40f6419c38c04cb7, []int{7}
+}
+func (m *RepairSegmentRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RepairSegmentRequest.Unmarshal(m, b)
+}
+func (m *RepairSegmentRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RepairSegmentRequest.Marshal(b, m, deterministic)
+}
+func (m *RepairSegmentRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RepairSegmentRequest.Merge(m, src)
+}
+func (m *RepairSegmentRequest) XXX_Size() int {
+	return xxx_messageInfo_RepairSegmentRequest.Size(m)
+}
+func (m *RepairSegmentRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RepairSegmentRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RepairSegmentRequest proto.InternalMessageInfo
+
+func (m *RepairSegmentRequest) GetPath() []byte {
+	if m != nil {
+		return m.Path
+	}
+	return nil
+}
+
+type RepairSegmentResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RepairSegmentResponse) Reset()         { *m = RepairSegmentResponse{} }
+func (m *RepairSegmentResponse) String() string { return proto.CompactTextString(m) }
+func (*RepairSegmentResponse) ProtoMessage()    {}
+func (*RepairSegmentResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_40f6419c38c04cb7, []int{8}
+}
+func (m *RepairSegmentResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RepairSegmentResponse.Unmarshal(m, b)
+}
+func (m *RepairSegmentResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RepairSegmentResponse.Marshal(b, m, deterministic)
+}
+func (m *RepairSegmentResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RepairSegmentResponse.Merge(m, src)
+}
+func (m *RepairSegmentResponse) XXX_Size() int {
+	return xxx_messageInfo_RepairSegmentResponse.Size(m)
+}
+func (m *RepairSegmentResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RepairSegmentResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RepairSegmentResponse proto.InternalMessageInfo
+
+func init() {
+	proto.RegisterType((*ListRepairQueueRequest)(nil), "satellite.repair.ListRepairQueueRequest")
+	proto.RegisterType((*QueuedSegment)(nil), "satellite.repair.QueuedSegment")
+	proto.RegisterType((*ListRepairQueueResponse)(nil), "satellite.repair.ListRepairQueueResponse")
+	proto.RegisterType((*AddRepairQueueRequest)(nil), "satellite.repair.AddRepairQueueRequest")
+	proto.RegisterType((*AddRepairQueueResponse)(nil), "satellite.repair.AddRepairQueueResponse")
+	proto.RegisterType((*RemoveRepairQueueRequest)(nil), "satellite.repair.RemoveRepairQueueRequest")
+	proto.RegisterType((*RemoveRepairQueueResponse)(nil), "satellite.repair.RemoveRepairQueueResponse")
+	proto.RegisterType((*RepairSegmentRequest)(nil), "satellite.repair.RepairSegmentRequest")
+	proto.RegisterType((*RepairSegmentResponse)(nil), "satellite.repair.RepairSegmentResponse")
+}
+
+func init() { proto.RegisterFile("repairqueue.proto", fileDescriptor_40f6419c38c04cb7) }
+
+var fileDescriptor_40f6419c38c04cb7 = []byte{
+	// 493 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x93, 0xc1, 0x6e, 0xd3, 0x40,
+	0x10, 0x86, 0xd9, 0xa6, 0x41, 0xd1, 0x38, 0x29, 0x74, 0x95, 0xb6, 0xc6, 0x1c, 0x6c, 0x59, 0x82,
+	0x9a, 0x56, 0x72, 0xa5, 0x70, 0xe4, 0x80, 0x52, 0x09, 0x01, 0x12, 0x12, 0x65, 0xe1, 0xc4, 0x25,
+	0x38, 0xc9, 0xd4, 0x31, 0xb2, 0xbd, 0xae, 0x77, 0xcc, 0x73, 0xf0, 0x2e, 0xbc, 0x04, 0x4f, 0x01,
+	0x0f, 0xc2, 0x05, 0x65, 0xd7, 0xa9, 0x9a, 0xd8, 0x90, 0xdc, 0xbc, 0xb3, 0xff, 0xff, 0xcf, 0xce,
+	0x37, 0x09, 0x1c, 0x96, 0x58, 0x44, 0x49, 0x79, 0x53, 0x61, 0x85, 0x61, 0x51, 0x4a, 0x92, 0xfc,
+	0xa1, 0x8a, 0x08, 0xd3, 0x34, 0x21, 0x0c, 0xcd, 0xa5, 0x03, 0xb1, 0x8c, 0xa5, 0xb9, 0x75, 0xdc,
+	0x58, 0xca, 0x38, 0xc5, 0x0b, 0x7d, 0x9a, 0x56, 0xd7, 0x17, 0x94, 0x64, 0xa8, 0x28, 0xca, 0x0a,
+	0x23, 0xf0, 0xdf, 0xc3, 0xf1, 0xbb, 0x44, 0x91, 0xd0, 0xd6, 0x0f, 0xcb, 0x5c, 0x81, 0x37, 0x15,
+	0x2a, 0xe2, 0x2e, 0x58, 0xb3, 0xaa, 0x54, 0xb2, 0x9c, 0x14, 0x11, 0x2d, 0x6c, 0xe6, 0xb1, 0xa0,
+	0x2f, 0xc0, 0x94, 0xae, 0x22, 0x5a, 0xf0, 0x21, 0x74, 0xd3, 0x24, 0x4b, 0xc8, 0xde, 0xf3, 0x58,
+	0xd0, 0x15, 0xe6, 0xe0, 0xff, 0x61, 0x30, 0xd0, 0x39, 0xf3, 0x8f, 0x18, 0x67, 0x98, 0x13, 0xe7,
+	0xb0, 0x7f, 0x27, 0x41, 0x7f, 0x2f, 0xc3, 0x53, 0xa9, 0x68, 0x52, 0x24, 0x38, 0x43, 0x65, 0xef,
+	0x79, 0x9d, 0xa0, 0x2b, 0x60, 0x59, 0xba, 0xd2, 0x15, 0xfe, 0x04, 0x0e, 0x94, 0xf1, 0x4f, 0x16,
+	0x18, 0xa5, 0xb4, 0xb0, 0x3b, 0x1e, 0x0b, 0x98, 0x18, 0xd4, 0xd5, 0x37, 0xba, 0xc8, 0x5f, 0x81,
+	0x95, 0xe4, 0x0a, 0x4b, 0xc2, 0xf9, 0x24, 0x22, 0x7b, 0xdf, 0x63, 0x81, 0x35, 0x72, 0x42, 0x33,
+	0x75, 0xb8, 0x9a, 0x3a, 0xfc, 0xb4, 0x9a, 0xfa, 0xb2, 0xf7, 0xf3, 0x97, 0x7b, 0xef, 0xfb, 0x6f,
+	0x97, 0x09, 0x58, 0x19, 0xc7, 0xc4, 0x5f, 0x43, 0x3f, 0x22, 0xc2, 0xac, 0xa8, 0x73, 0xba, 0x3b,
+	0xe5, 0x30, 0x9d, 0x63, 0xdd, 0x3a, 0xc7, 0xe4, 0x7f, 0x85, 0x93, 0x06, 0x4e, 0x55, 0xc8, 0x5c,
+	0x21, 0x7f, 0x01, 0xbd, 0xfa, 0xed, 0xca, 0x66, 0x5e, 0x27, 0xb0, 0x46, 0x6e, 0xb8, 0xb9, 0xbb,
+	0x70, 0x8d, 0x9c, 0xb8, 0x35, 0x2c, 0x19, 0x66, 0xb2, 0x44, 0x8d, 0xba, 0x27, 0xf4, 0xb7, 0x7f,
+	0x0e, 0x47, 0xe3, 0xf9, 0xbc, 0x65, 0x73, 0x2d, 0xc0, 0xfd, 0x97, 0x70, 0xbc, 0x29, 0xae, 0xdf,
+	0xd5, 0x24, 0xcd, 0x5a, 0x48, 0xfb, 0x21, 0xd8, 0x02, 0x33, 0xf9, 0x0d, 0x77, 0x6c, 0xf8, 0x18,
+	0x1e, 0xb5, 0xe8, 0x4d, 0x4f, 0xff, 0x0c, 0x86, 0xa6, 0xbc, 0x9a, 0xf4, 0x3f, 0x41, 0x27, 0x70,
+	0xb4, 0xa1, 0x35, 0x21, 0xa3, 0x1f, 0x1d, 0x18, 0xde, 0x09, 0x7f, 0x9b, 0xab, 0x02, 0x67, 0x24,
+	0x4b, 0x7e, 0x0d, 0x0f, 0x36, 0x96, 0xc0, 0x83, 0x26, 0xea, 0xf6, 0x9f, 0xbd, 0xf3, 0x6c, 0x07,
+	0x65, 0x4d, 0x6e, 0x06, 0x07, 0xeb, 0x4c, 0xf9, 0x69, 0xd3, 0xdc, 0xba, 0x22, 0x27, 0xd8, 0x2e,
+	0xac, 0x9b, 0xa4, 0x70, 0xd8, 0xe0, 0xc8, 0xcf, 0x9a, 0xf6, 0x7f, 0x2d, 0xc7, 0x39, 0xdf, 0x49,
+	0x5b, 0x77, 0xfb, 0x02, 0x83, 0x35, 0xd8, 0xfc, 0x69, 0x9b, 0xbb, 0xb9, 0x39, 0xe7, 0x74, 0xab,
+	0xce, 0x74, 0xb8, 0xec, 0x7f, 0x86, 0x24, 0x27, 0x2c, 0xf3, 0x28, 0x2d, 0xa6, 0xd3, 0xfb, 0xfa,
+	0xaf, 0xf5, 0xfc, 0xef, 0x00, 0x5c, 0xdf, 0xe6, 0xe5, 0xd9, 0x04, 0x00, 0x00,
+}
+
+type DRPCRepairQueueInspectorClient interface {
+	DRPCConn() drpc.Conn
+
+	// ListRepairQueue returns queued injured segments ordered by path
+	ListRepairQueue(ctx context.Context, in *ListRepairQueueRequest) (*ListRepairQueueResponse, error)
+	// AddRepairQueue forces a segment into the repair queue
+	AddRepairQueue(ctx context.Context, in *AddRepairQueueRequest) (*AddRepairQueueResponse, error)
+	// RemoveRepairQueue removes a segment from the repair queue
+	RemoveRepairQueue(ctx context.Context, in *RemoveRepairQueueRequest) (*RemoveRepairQueueResponse, error)
+	// RepairSegment repairs a segment immediately, without going through the queue
+	RepairSegment(ctx context.Context, in *RepairSegmentRequest) (*RepairSegmentResponse, error)
+}
+
+type drpcRepairQueueInspectorClient struct {
+	cc drpc.Conn
+}
+
+func NewDRPCRepairQueueInspectorClient(cc drpc.Conn) DRPCRepairQueueInspectorClient {
+	return &drpcRepairQueueInspectorClient{cc}
+}
+
+func (c *drpcRepairQueueInspectorClient) DRPCConn() drpc.Conn { return c.cc }
+
+func (c *drpcRepairQueueInspectorClient) ListRepairQueue(ctx context.Context, in *ListRepairQueueRequest) (*ListRepairQueueResponse, error) {
+	out := new(ListRepairQueueResponse)
+	err := c.cc.Invoke(ctx, "/satellite.repair.RepairQueueInspector/ListRepairQueue", in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcRepairQueueInspectorClient) AddRepairQueue(ctx context.Context, in *AddRepairQueueRequest) (*AddRepairQueueResponse, error) {
+	out := new(AddRepairQueueResponse)
+	err := c.cc.Invoke(ctx, "/satellite.repair.RepairQueueInspector/AddRepairQueue", in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcRepairQueueInspectorClient) RemoveRepairQueue(ctx context.Context, in *RemoveRepairQueueRequest) (*RemoveRepairQueueResponse, error) {
+	out := new(RemoveRepairQueueResponse)
+	err := c.cc.Invoke(ctx, "/satellite.repair.RepairQueueInspector/RemoveRepairQueue", in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *drpcRepairQueueInspectorClient) RepairSegment(ctx context.Context, in *RepairSegmentRequest) (*RepairSegmentResponse, error) {
+	out := new(RepairSegmentResponse)
+	err := c.cc.Invoke(ctx, "/satellite.repair.RepairQueueInspector/RepairSegment", in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+type DRPCRepairQueueInspectorServer interface {
+	// ListRepairQueue returns queued injured segments ordered by path
+	ListRepairQueue(context.Context, *ListRepairQueueRequest) (*ListRepairQueueResponse, error)
+	// AddRepairQueue forces a segment into the repair queue
+	AddRepairQueue(context.Context, *AddRepairQueueRequest) (*AddRepairQueueResponse, error)
+	// RemoveRepairQueue removes a segment from the repair queue
+	RemoveRepairQueue(context.Context, *RemoveRepairQueueRequest) (*RemoveRepairQueueResponse, error)
+	// RepairSegment repairs a segment immediately, without going through the queue
+	RepairSegment(context.Context, *RepairSegmentRequest) (*RepairSegmentResponse, error)
+}
+
+type DRPCRepairQueueInspectorDescription struct{}
+
+func (DRPCRepairQueueInspectorDescription) NumMethods() int { return 4 }
+
+func (DRPCRepairQueueInspectorDescription) Method(n int) (string, drpc.Handler, interface{}, bool) {
+	switch n {
+	case 0:
+		return "/satellite.repair.RepairQueueInspector/ListRepairQueue",
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCRepairQueueInspectorServer).
+					ListRepairQueue(
+						ctx,
+						in1.(*ListRepairQueueRequest),
+					)
+			}, DRPCRepairQueueInspectorServer.ListRepairQueue, true
+	case 1:
+		return "/satellite.repair.RepairQueueInspector/AddRepairQueue",
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCRepairQueueInspectorServer).
+					AddRepairQueue(
+						ctx,
+						in1.(*AddRepairQueueRequest),
+					)
+			}, DRPCRepairQueueInspectorServer.AddRepairQueue, true
+	case 2:
+		return "/satellite.repair.RepairQueueInspector/RemoveRepairQueue",
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCRepairQueueInspectorServer).
+					RemoveRepairQueue(
+						ctx,
+						in1.(*RemoveRepairQueueRequest),
+					)
+			}, DRPCRepairQueueInspectorServer.RemoveRepairQueue, true
+	case 3:
+		return "/satellite.repair.RepairQueueInspector/RepairSegment",
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCRepairQueueInspectorServer).
+					RepairSegment(
+						ctx,
+						in1.(*RepairSegmentRequest),
+					)
+			}, DRPCRepairQueueInspectorServer.RepairSegment, true
+	default:
+		return "", nil, nil, false
+	}
+}
+
+func DRPCRegisterRepairQueueInspector(srv drpc.Server, impl DRPCRepairQueueInspectorServer) {
+	srv.Register(impl, DRPCRepairQueueInspectorDescription{})
+}
+
+type DRPCRepairQueueInspector_ListRepairQueueStream interface {
+	drpc.Stream
+	SendAndClose(*ListRepairQueueResponse) error
+}
+
+type drpcRepairQueueInspectorListRepairQueueStream struct {
+	drpc.Stream
+}
+
+func (x *drpcRepairQueueInspectorListRepairQueueStream) SendAndClose(m *ListRepairQueueResponse) error {
+	if err := x.MsgSend(m); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCRepairQueueInspector_AddRepairQueueStream interface {
+	drpc.Stream
+	SendAndClose(*AddRepairQueueResponse) error
+}
+
+type drpcRepairQueueInspectorAddRepairQueueStream struct {
+	drpc.Stream
+}
+
+func (x *drpcRepairQueueInspectorAddRepairQueueStream) SendAndClose(m *AddRepairQueueResponse) error {
+	if err := x.MsgSend(m); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCRepairQueueInspector_RemoveRepairQueueStream interface {
+	drpc.Stream
+	SendAndClose(*RemoveRepairQueueResponse) error
+}
+
+type drpcRepairQueueInspectorRemoveRepairQueueStream struct {
+	drpc.Stream
+}
+
+func (x *drpcRepairQueueInspectorRemoveRepairQueueStream) SendAndClose(m *RemoveRepairQueueResponse) error {
+	if err := x.MsgSend(m); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+type DRPCRepairQueueInspector_RepairSegmentStream interface {
+	drpc.Stream
+	SendAndClose(*RepairSegmentResponse) error
+}
+
+type drpcRepairQueueInspectorRepairSegmentStream struct {
+	drpc.Stream
+}
+
+func (x *drpcRepairQueueInspectorRepairSegmentStream) SendAndClose(m *RepairSegmentResponse) error {
+	if err := x.MsgSend(m); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// RepairQueueInspectorClient is the client API for RepairQueueInspector service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type RepairQueueInspectorClient interface {
+	// ListRepairQueue returns queued injured segments ordered by path
+	ListRepairQueue(ctx context.Context, in *ListRepairQueueRequest, opts ...grpc.CallOption) (*ListRepairQueueResponse, error)
+	// AddRepairQueue forces a segment into the repair queue
+	AddRepairQueue(ctx context.Context, in *AddRepairQueueRequest, opts ...grpc.CallOption) (*AddRepairQueueResponse, error)
+	// RemoveRepairQueue removes a segment from the repair queue
+	RemoveRepairQueue(ctx context.Context, in *RemoveRepairQueueRequest, opts ...grpc.CallOption) (*RemoveRepairQueueResponse, error)
+	// RepairSegment repairs a segment immediately, without going through the queue
+	RepairSegment(ctx context.Context, in *RepairSegmentRequest, opts ...grpc.CallOption) (*RepairSegmentResponse, error)
+}
+
+type repairQueueInspectorClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewRepairQueueInspectorClient(cc *grpc.ClientConn) RepairQueueInspectorClient {
+	return &repairQueueInspectorClient{cc}
+}
+
+func (c *repairQueueInspectorClient) ListRepairQueue(ctx context.Context, in *ListRepairQueueRequest, opts ...grpc.CallOption) (*ListRepairQueueResponse, error) {
+	out := new(ListRepairQueueResponse)
+	err := c.cc.Invoke(ctx, "/satellite.repair.RepairQueueInspector/ListRepairQueue", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repairQueueInspectorClient) AddRepairQueue(ctx context.Context, in *AddRepairQueueRequest, opts ...grpc.CallOption) (*AddRepairQueueResponse, error) {
+	out := new(AddRepairQueueResponse)
+	err := c.cc.Invoke(ctx, "/satellite.repair.RepairQueueInspector/AddRepairQueue", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repairQueueInspectorClient) RemoveRepairQueue(ctx context.Context, in *RemoveRepairQueueRequest, opts ...grpc.CallOption) (*RemoveRepairQueueResponse, error) {
+	out := new(RemoveRepairQueueResponse)
+	err := c.cc.Invoke(ctx, "/satellite.repair.RepairQueueInspector/RemoveRepairQueue", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repairQueueInspectorClient) RepairSegment(ctx context.Context, in *RepairSegmentRequest, opts ...grpc.CallOption) (*RepairSegmentResponse, error) {
+	out := new(RepairSegmentResponse)
+	err := c.cc.Invoke(ctx, "/satellite.repair.RepairQueueInspector/RepairSegment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RepairQueueInspectorServer is the server API for RepairQueueInspector service.
+type RepairQueueInspectorServer interface {
+	// ListRepairQueue returns queued injured segments ordered by path
+	ListRepairQueue(context.Context, *ListRepairQueueRequest) (*ListRepairQueueResponse, error)
+	// AddRepairQueue forces a segment into the repair queue
+	AddRepairQueue(context.Context, *AddRepairQueueRequest) (*AddRepairQueueResponse, error)
+	// RemoveRepairQueue removes a segment from the repair queue
+	RemoveRepairQueue(context.Context, *RemoveRepairQueueRequest) (*RemoveRepairQueueResponse, error)
+	// RepairSegment repairs a segment immediately, without going through the queue
+	RepairSegment(context.Context, *RepairSegmentRequest) (*RepairSegmentResponse, error)
+}
+
+func RegisterRepairQueueInspectorServer(s *grpc.Server, srv RepairQueueInspectorServer) {
+	s.RegisterService(&_RepairQueueInspector_serviceDesc, srv)
+}
+
+func _RepairQueueInspector_ListRepairQueue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRepairQueueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepairQueueInspectorServer).ListRepairQueue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/satellite.repair.RepairQueueInspector/ListRepairQueue",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepairQueueInspectorServer).ListRepairQueue(ctx, req.(*ListRepairQueueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RepairQueueInspector_AddRepairQueue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddRepairQueueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepairQueueInspectorServer).AddRepairQueue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/satellite.repair.RepairQueueInspector/AddRepairQueue",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepairQueueInspectorServer).AddRepairQueue(ctx, req.(*AddRepairQueueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RepairQueueInspector_RemoveRepairQueue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveRepairQueueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepairQueueInspectorServer).RemoveRepairQueue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/satellite.repair.RepairQueueInspector/RemoveRepairQueue",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepairQueueInspectorServer).RemoveRepairQueue(ctx, req.(*RemoveRepairQueueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RepairQueueInspector_RepairSegment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RepairSegmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepairQueueInspectorServer).RepairSegment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/satellite.repair.RepairQueueInspector/RepairSegment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepairQueueInspectorServer).RepairSegment(ctx, req.(*RepairSegmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _RepairQueueInspector_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "satellite.repair.RepairQueueInspector",
+	HandlerType: (*RepairQueueInspectorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListRepairQueue",
+			Handler:    _RepairQueueInspector_ListRepairQueue_Handler,
+		},
+		{
+			MethodName: "AddRepairQueue",
+			Handler:    _RepairQueueInspector_AddRepairQueue_Handler,
+		},
+		{
+			MethodName: "RemoveRepairQueue",
+			Handler:    _RepairQueueInspector_RemoveRepairQueue_Handler,
+		},
+		{
+			MethodName: "RepairSegment",
+			Handler:    _RepairQueueInspector_RepairSegment_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "repairqueue.proto",
+}
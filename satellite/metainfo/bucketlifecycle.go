@@ -0,0 +1,215 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfo
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/skyrings/skyring-common/tools/uuid"
+	"go.uber.org/zap"
+
+	"storj.io/common/pb"
+	"storj.io/common/rpc/rpcstatus"
+	"storj.io/common/storj"
+	"storj.io/storj/pkg/macaroon"
+	"storj.io/storj/pkg/metainfopb"
+)
+
+const (
+	maxLifecycleRules      = 100
+	maxLifecycleRuleIDSize = 255
+)
+
+// LifecycleRule removes the objects and incomplete uploads of a bucket under a
+// prefix after some time.
+type LifecycleRule struct {
+	ID string
+	// EncryptedPrefix limits the rule to the objects whose encrypted path
+	// starts with it.
+	EncryptedPrefix []byte
+	// ExpireAfterDays removes objects the number of days after they were
+	// created. 0 disables it.
+	ExpireAfterDays int32
+	// AbortIncompleteUploadsAfterHours removes the segments of uploads which
+	// haven't been committed the number of hours after they started. 0
+	// disables it.
+	AbortIncompleteUploadsAfterHours int32
+}
+
+// Matches returns whether the rule applies to the encrypted object path.
+func (rule *LifecycleRule) Matches(encryptedPath []byte) bool {
+	return bytes.HasPrefix(encryptedPath, rule.EncryptedPrefix)
+}
+
+// errVersionedExpiration is returned when expiration and versioning would be
+// combined, because expiring versioned objects isn't supported.
+const errVersionedExpiration = "expiration rules are not supported for buckets with versioning"
+
+// hasExpiration returns whether any of the rules expires objects.
+func hasExpiration(rules []LifecycleRule) bool {
+	for _, rule := range rules {
+		if rule.ExpireAfterDays > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// BucketLifecycle contains the lifecycle rules of a bucket.
+type BucketLifecycle struct {
+	ProjectID  uuid.UUID
+	BucketName []byte
+	Rules      []LifecycleRule
+}
+
+// GetBucketLifecycle returns the lifecycle rules of a bucket.
+func (s *Service) GetBucketLifecycle(ctx context.Context, bucketName []byte, projectID uuid.UUID) (_ []LifecycleRule, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return s.bucketsDB.GetBucketLifecycle(ctx, bucketName, projectID)
+}
+
+// SetBucketLifecycle replaces the lifecycle rules of a bucket.
+func (s *Service) SetBucketLifecycle(ctx context.Context, bucketName []byte, projectID uuid.UUID, rules []LifecycleRule) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return s.bucketsDB.SetBucketLifecycle(ctx, bucketName, projectID, rules)
+}
+
+// ListBucketLifecycles returns the lifecycle rules of all buckets which have rules.
+func (s *Service) ListBucketLifecycles(ctx context.Context) (_ []BucketLifecycle, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return s.bucketsDB.ListBucketLifecycles(ctx)
+}
+
+// SetBucketLifecycle replaces the lifecycle rules of a bucket.
+func (endpoint *Endpoint) SetBucketLifecycle(ctx context.Context, req *metainfopb.SetBucketLifecycleRequest) (resp *metainfopb.SetBucketLifecycleResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	keyInfo, err := endpoint.validateAuth(ctx, &pb.RequestHeader{ApiKey: req.ApiKey}, macaroon.Action{
+		Op:     macaroon.ActionWrite,
+		Bucket: req.Bucket,
+		Time:   time.Now(),
+	})
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.Unauthenticated, err.Error())
+	}
+
+	err = endpoint.validateBucket(ctx, req.Bucket)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
+	}
+
+	rules := make([]LifecycleRule, 0, len(req.Rules))
+	for _, rule := range req.Rules {
+		rules = append(rules, LifecycleRule{
+			ID:                               rule.Id,
+			EncryptedPrefix:                  rule.EncryptedPrefix,
+			ExpireAfterDays:                  rule.ExpireAfterDays,
+			AbortIncompleteUploadsAfterHours: rule.AbortIncompleteUploadsAfterHours,
+		})
+	}
+
+	err = validateLifecycleRules(rules)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
+	}
+
+	if hasExpiration(rules) {
+		versioning, err := endpoint.metainfo.GetBucketVersioning(ctx, req.Bucket, keyInfo.ProjectID)
+		if err != nil {
+			if storj.ErrBucketNotFound.Has(err) {
+				return nil, rpcstatus.Error(rpcstatus.NotFound, err.Error())
+			}
+			return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+		}
+		if versioning != VersioningUnversioned {
+			return nil, rpcstatus.Error(rpcstatus.FailedPrecondition, errVersionedExpiration)
+		}
+	}
+
+	err = endpoint.metainfo.SetBucketLifecycle(ctx, req.Bucket, keyInfo.ProjectID, rules)
+	if err != nil {
+		if storj.ErrBucketNotFound.Has(err) {
+			return nil, rpcstatus.Error(rpcstatus.NotFound, err.Error())
+		}
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+
+	endpoint.log.Info("Bucket Lifecycle", zap.Stringer("Project ID", keyInfo.ProjectID), zap.Int("rules", len(rules)))
+	mon.Meter("req_set_bucket_lifecycle").Mark(1)
+
+	return &metainfopb.SetBucketLifecycleResponse{}, nil
+}
+
+// GetBucketLifecycle returns the lifecycle rules of a bucket.
+func (endpoint *Endpoint) GetBucketLifecycle(ctx context.Context, req *metainfopb.GetBucketLifecycleRequest) (resp *metainfopb.GetBucketLifecycleResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	keyInfo, err := endpoint.validateAuth(ctx, &pb.RequestHeader{ApiKey: req.ApiKey}, macaroon.Action{
+		Op:     macaroon.ActionRead,
+		Bucket: req.Bucket,
+		Time:   time.Now(),
+	})
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.Unauthenticated, err.Error())
+	}
+
+	err = endpoint.validateBucket(ctx, req.Bucket)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
+	}
+
+	_, err = endpoint.metainfo.GetBucket(ctx, req.Bucket, keyInfo.ProjectID)
+	if err != nil {
+		if storj.ErrBucketNotFound.Has(err) {
+			return nil, rpcstatus.Error(rpcstatus.NotFound, err.Error())
+		}
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+
+	rules, err := endpoint.metainfo.GetBucketLifecycle(ctx, req.Bucket, keyInfo.ProjectID)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+
+	resp = &metainfopb.GetBucketLifecycleResponse{}
+	for _, rule := range rules {
+		resp.Rules = append(resp.Rules, &metainfopb.LifecycleRule{
+			Id:                               rule.ID,
+			EncryptedPrefix:                  rule.EncryptedPrefix,
+			ExpireAfterDays:                  rule.ExpireAfterDays,
+			AbortIncompleteUploadsAfterHours: rule.AbortIncompleteUploadsAfterHours,
+		})
+	}
+	return resp, nil
+}
+
+// validateLifecycleRules checks that the rules have unique IDs and that every
+// rule removes something.
+func validateLifecycleRules(rules []LifecycleRule) error {
+	if len(rules) > maxLifecycleRules {
+		return Error.New("too many lifecycle rules: %d, maximum is %d", len(rules), maxLifecycleRules)
+	}
+
+	ids := make(map[string]struct{}, len(rules))
+	for _, rule := range rules {
+		switch {
+		case rule.ID == "":
+			return Error.New("lifecycle rule ID is missing")
+		case len(rule.ID) > maxLifecycleRuleIDSize:
+			return Error.New("lifecycle rule ID %q is longer than %d bytes", rule.ID, maxLifecycleRuleIDSize)
+		case rule.ExpireAfterDays < 0 || rule.AbortIncompleteUploadsAfterHours < 0:
+			return Error.New("lifecycle rule %q has a negative period", rule.ID)
+		case rule.ExpireAfterDays == 0 && rule.AbortIncompleteUploadsAfterHours == 0:
+			return Error.New("lifecycle rule %q doesn't remove anything", rule.ID)
+		}
+
+		if _, ok := ids[rule.ID]; ok {
+			return Error.New("duplicate lifecycle rule ID %q", rule.ID)
+		}
+		ids[rule.ID] = struct{}{}
+	}
+	return nil
+}
@@ -5,11 +5,13 @@ package metainfo
 
 import (
 	"context"
+	"time"
 
 	"github.com/skyrings/skyring-common/tools/uuid"
 
 	"storj.io/common/macaroon"
 	"storj.io/common/storj"
+	"storj.io/storj/storage"
 )
 
 // BucketsDB is the interface for the database to interact with buckets
@@ -26,4 +28,72 @@ type BucketsDB interface {
 	DeleteBucket(ctx context.Context, bucketName []byte, projectID uuid.UUID) (err error)
 	// List returns all buckets for a project
 	ListBuckets(ctx context.Context, projectID uuid.UUID, listOpts storj.BucketListOptions, allowedBuckets macaroon.AllowedBuckets) (bucketList storj.BucketList, err error)
+	// GetBucketVersioning returns the versioning state of a bucket
+	GetBucketVersioning(ctx context.Context, bucketName []byte, projectID uuid.UUID) (versioning Versioning, err error)
+	// SetBucketVersioning sets the versioning state of an existing bucket
+	SetBucketVersioning(ctx context.Context, bucketName []byte, projectID uuid.UUID, versioning Versioning) (err error)
+	// GetBucketLifecycle returns the lifecycle rules of a bucket
+	GetBucketLifecycle(ctx context.Context, bucketName []byte, projectID uuid.UUID) (rules []LifecycleRule, err error)
+	// SetBucketLifecycle replaces the lifecycle rules of an existing bucket, no rules removes them
+	SetBucketLifecycle(ctx context.Context, bucketName []byte, projectID uuid.UUID, rules []LifecycleRule) (err error)
+	// ListBucketLifecycles returns the lifecycle rules of all buckets which have rules
+	ListBucketLifecycles(ctx context.Context) (lifecycles []BucketLifecycle, err error)
+}
+
+// SharedSegmentsDB keeps track of remote segments whose pieces are referenced
+// by more than one pointer after an object has been copied.
+//
+// architecture: Database
+type SharedSegmentsDB interface {
+	// AddReferences records one more reference to each of the root piece IDs.
+	// Either all references are added or none of them.
+	AddReferences(ctx context.Context, rootPieceIDs []storj.PieceID) error
+	// RemoveReference removes one reference to the root piece ID. It returns false
+	// when there are no other references, so the pieces can be deleted. When it
+	// fails no reference was removed.
+	RemoveReference(ctx context.Context, rootPieceID storj.PieceID) (shared bool, err error)
+}
+
+// ObjectVersionsDB keeps track of the version numbers of objects in buckets
+// with versioning.
+//
+// architecture: Database
+type ObjectVersionsDB interface {
+	// GetCurrent returns the version number of the current object. It is 0
+	// when the current object doesn't have a version number.
+	GetCurrent(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte) (version int32, err error)
+	// SetCurrent sets the version number of the current object.
+	SetCurrent(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte, version int32) error
+	// Allocate returns a version number which is greater than all version
+	// numbers previously allocated for the object.
+	Allocate(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte) (version int32, err error)
+}
+
+// RangeCheckpoint is the persisted progress of a range of the metainfo loop.
+type RangeCheckpoint struct {
+	// Generation identifies a full iteration of the loop.
+	Generation int64
+	Index      int
+	Count      int
+	// LastKey is the last key processed in the range, nil when the range hasn't been started.
+	LastKey storage.Key
+	Done    bool
+
+	UpdatedAt time.Time
+}
+
+// LoopCheckpointsDB stores the progress of the metainfo loop and the partial
+// state of its observers, so that an interrupted iteration can be resumed.
+//
+// architecture: Database
+type LoopCheckpointsDB interface {
+	// GetRanges returns the checkpoints of all ranges of the last generation, ordered by index.
+	GetRanges(ctx context.Context) ([]RangeCheckpoint, error)
+	// ResetRanges starts a new generation with count ranges, which haven't been started.
+	ResetRanges(ctx context.Context, generation int64, count int) error
+	// SetRange stores the checkpoint of a range together with the state of its observers,
+	// replacing all states previously stored for the range.
+	SetRange(ctx context.Context, checkpoint RangeCheckpoint, states map[string][]byte) error
+	// GetObserverState returns the state an observer stored for a range of the generation, or nil.
+	GetObserverState(ctx context.Context, generation int64, name string, index int) ([]byte, error)
 }
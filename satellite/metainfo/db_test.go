@@ -5,6 +5,7 @@ package metainfo_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/skyrings/skyring-common/tools/uuid"
 	"github.com/stretchr/testify/require"
@@ -15,7 +16,9 @@ import (
 	"storj.io/common/testrand"
 	"storj.io/storj/satellite"
 	"storj.io/storj/satellite/console"
+	"storj.io/storj/satellite/metainfo"
 	"storj.io/storj/satellite/satellitedb/satellitedbtest"
+	"storj.io/storj/storage"
 )
 
 func newTestBucket(name string, projectID uuid.UUID) storj.Bucket {
@@ -194,3 +197,138 @@ func TestListBucketsNotAllowed(t *testing.T) {
 		}
 	})
 }
+
+func TestSharedSegmentReferences(t *testing.T) {
+	satellitedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db satellite.DB) {
+		sharedSegments := db.SharedSegments()
+
+		shared, sole := testrand.PieceID(), testrand.PieceID()
+
+		// pieces without references aren't shared
+		isShared, err := sharedSegments.RemoveReference(ctx, sole)
+		require.NoError(t, err)
+		require.False(t, isShared)
+
+		require.NoError(t, sharedSegments.AddReferences(ctx, []storj.PieceID{shared}))
+		require.NoError(t, sharedSegments.AddReferences(ctx, []storj.PieceID{shared}))
+
+		// two copies were added, so all three objects share the pieces until the last one is deleted
+		for _, expected := range []bool{true, true, false} {
+			isShared, err = sharedSegments.RemoveReference(ctx, shared)
+			require.NoError(t, err)
+			require.Equal(t, expected, isShared)
+		}
+	})
+}
+
+func TestObjectVersionsDB(t *testing.T) {
+	satellitedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db satellite.DB) {
+		project, err := db.Console().Projects().Insert(ctx, &console.Project{Name: "testproject1"})
+		require.NoError(t, err)
+
+		bucketsDB := db.Buckets()
+		_, err = bucketsDB.CreateBucket(ctx, newTestBucket("testbucket", project.ID))
+		require.NoError(t, err)
+
+		bucket := []byte("testbucket")
+
+		// buckets are unversioned until versioning is enabled
+		versioning, err := bucketsDB.GetBucketVersioning(ctx, bucket, project.ID)
+		require.NoError(t, err)
+		require.Equal(t, metainfo.VersioningUnversioned, versioning)
+
+		for _, expected := range []metainfo.Versioning{metainfo.VersioningEnabled, metainfo.VersioningSuspended} {
+			require.NoError(t, bucketsDB.SetBucketVersioning(ctx, bucket, project.ID, expected))
+
+			versioning, err = bucketsDB.GetBucketVersioning(ctx, bucket, project.ID)
+			require.NoError(t, err)
+			require.Equal(t, expected, versioning)
+		}
+
+		err = bucketsDB.SetBucketVersioning(ctx, []byte("missing"), project.ID, metainfo.VersioningEnabled)
+		require.True(t, storj.ErrBucketNotFound.Has(err), err)
+
+		versions := db.ObjectVersions()
+		path := []byte("encrypted/path")
+
+		current, err := versions.GetCurrent(ctx, project.ID, bucket, path)
+		require.NoError(t, err)
+		require.Zero(t, current)
+
+		for _, expected := range []int32{1, 2, 3} {
+			version, err := versions.Allocate(ctx, project.ID, bucket, path)
+			require.NoError(t, err)
+			require.Equal(t, expected, version)
+		}
+
+		require.NoError(t, versions.SetCurrent(ctx, project.ID, bucket, path, 2))
+		current, err = versions.GetCurrent(ctx, project.ID, bucket, path)
+		require.NoError(t, err)
+		require.EqualValues(t, 2, current)
+
+		// the version numbers are removed with the bucket
+		require.NoError(t, bucketsDB.DeleteBucket(ctx, bucket, project.ID))
+		current, err = versions.GetCurrent(ctx, project.ID, bucket, path)
+		require.NoError(t, err)
+		require.Zero(t, current)
+	})
+}
+
+func TestLoopCheckpointsDB(t *testing.T) {
+	satellitedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db satellite.DB) {
+		checkpoints := db.LoopCheckpoints()
+
+		ranges, err := checkpoints.GetRanges(ctx)
+		require.NoError(t, err)
+		require.Empty(t, ranges)
+
+		require.NoError(t, checkpoints.ResetRanges(ctx, 1, 2))
+		ranges, err = checkpoints.GetRanges(ctx)
+		require.NoError(t, err)
+		require.Len(t, ranges, 2)
+		for index, checkpoint := range ranges {
+			require.Equal(t, index, checkpoint.Index)
+			require.Equal(t, 2, checkpoint.Count)
+			require.EqualValues(t, 1, checkpoint.Generation)
+			require.Nil(t, checkpoint.LastKey)
+			require.False(t, checkpoint.Done)
+		}
+
+		checkpoint := metainfo.RangeCheckpoint{
+			Generation: 1,
+			Index:      1,
+			Count:      2,
+			LastKey:    storage.Key("80/l/bucket/object"),
+			UpdatedAt:  time.Now(),
+		}
+		require.NoError(t, checkpoints.SetRange(ctx, checkpoint, map[string][]byte{"a": {1}, "b": {2}}))
+
+		ranges, err = checkpoints.GetRanges(ctx)
+		require.NoError(t, err)
+		require.Equal(t, checkpoint.LastKey, ranges[1].LastKey)
+		require.Nil(t, ranges[0].LastKey)
+
+		state, err := checkpoints.GetObserverState(ctx, 1, "a", 1)
+		require.NoError(t, err)
+		require.Equal(t, []byte{1}, state)
+
+		// states which aren't part of the checkpoint are removed
+		checkpoint.Done = true
+		require.NoError(t, checkpoints.SetRange(ctx, checkpoint, map[string][]byte{"a": {3}}))
+
+		state, err = checkpoints.GetObserverState(ctx, 1, "a", 1)
+		require.NoError(t, err)
+		require.Equal(t, []byte{3}, state)
+		state, err = checkpoints.GetObserverState(ctx, 1, "b", 1)
+		require.NoError(t, err)
+		require.Nil(t, state)
+
+		// ranges of an older generation can't be updated
+		require.NoError(t, checkpoints.ResetRanges(ctx, 2, 1))
+		require.Error(t, checkpoints.SetRange(ctx, checkpoint, nil))
+
+		state, err = checkpoints.GetObserverState(ctx, 1, "a", 1)
+		require.NoError(t, err)
+		require.Nil(t, state)
+	})
+}
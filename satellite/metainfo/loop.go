@@ -5,15 +5,21 @@ package metainfo
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/skyrings/skyring-common/tools/uuid"
 	"github.com/zeebo/errs"
+	"golang.org/x/sync/errgroup"
 	"golang.org/x/time/rate"
 
 	"storj.io/common/pb"
 	"storj.io/common/storj"
+	"storj.io/common/sync2"
 	"storj.io/storj/storage"
 )
 
@@ -33,6 +39,30 @@ type Observer interface {
 	InlineSegment(context.Context, ScopedPath, *pb.Pointer) error
 }
 
+// ConcurrentObserver is an observer which is safe for concurrent use.
+//
+// When the loop iterates several ranges in parallel, it calls the same
+// instance from all of them.
+type ConcurrentObserver interface {
+	Observer
+	// ConcurrentSafe marks the observer as safe for concurrent use.
+	ConcurrentSafe()
+}
+
+// ForkingObserver is an observer which gets a separate instance for every
+// range, when the loop iterates several ranges in parallel.
+//
+// The instances are joined back in the order of the ranges, after all ranges
+// have been iterated. Observers which neither fork nor are concurrent are
+// called from one range at a time.
+type ForkingObserver interface {
+	Observer
+	// Fork returns a new observer for a single range.
+	Fork(ctx context.Context) (Observer, error)
+	// Join merges the results of an observer returned by Fork.
+	Join(ctx context.Context, forked Observer) error
+}
+
 // ScopedPath contains full expanded information about the path.
 type ScopedPath struct {
 	ProjectID           uuid.UUID
@@ -40,6 +70,9 @@ type ScopedPath struct {
 	Segment             string
 	BucketName          string
 	EncryptedObjectPath string
+	// Version is the version number of a non-current object version and 0
+	// for the current object.
+	Version int32
 
 	// TODO: should these be a []byte?
 
@@ -51,19 +84,156 @@ type observerContext struct {
 	Observer
 	ctx  context.Context
 	done chan error
+
+	once     sync.Once
+	finished int32
 }
 
 func (observer *observerContext) HandleError(err error) bool {
 	if err != nil {
-		observer.done <- err
-		observer.Finish()
+		observer.finish(err)
 		return true
 	}
 	return false
 }
 
 func (observer *observerContext) Finish() {
-	close(observer.done)
+	observer.finish(nil)
+}
+
+// finish reports the result only once, since the ranges of a parallel
+// iteration may fail concurrently.
+func (observer *observerContext) finish(err error) {
+	observer.once.Do(func() {
+		atomic.StoreInt32(&observer.finished, 1)
+		if err != nil {
+			observer.done <- err
+		}
+		close(observer.done)
+	})
+}
+
+// Finished returns whether the observer has already finished.
+func (observer *observerContext) Finished() bool {
+	return atomic.LoadInt32(&observer.finished) != 0
+}
+
+// fork returns the observers which receive the segments of count ranges.
+func (observer *observerContext) fork(ctx context.Context, count int) (_ []Observer, err error) {
+	targets := make([]Observer, count)
+	switch target := observer.Observer.(type) {
+	case ConcurrentObserver:
+		for i := range targets {
+			targets[i] = target
+		}
+	case ForkingObserver:
+		for i := range targets {
+			targets[i], err = target.Fork(ctx)
+			if err != nil {
+				return nil, LoopError.Wrap(err)
+			}
+		}
+	default:
+		locked := &lockedObserver{observer: target}
+		for i := range targets {
+			targets[i] = locked
+		}
+	}
+	return targets, nil
+}
+
+// forks returns whether fork returns separate observers for every range.
+func (observer *observerContext) forks() bool {
+	if _, ok := observer.Observer.(ConcurrentObserver); ok {
+		return false
+	}
+	_, ok := observer.Observer.(ForkingObserver)
+	return ok
+}
+
+// join merges the observers returned by fork.
+func (observer *observerContext) join(ctx context.Context, targets []Observer) error {
+	if !observer.forks() {
+		return nil
+	}
+	forking := observer.Observer.(ForkingObserver)
+	for _, target := range targets {
+		if err := forking.Join(ctx, target); err != nil {
+			return LoopError.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// lockedObserver calls an observer from one range at a time.
+type lockedObserver struct {
+	mu       sync.Mutex
+	observer Observer
+}
+
+func (locked *lockedObserver) Object(ctx context.Context, path ScopedPath, pointer *pb.Pointer) error {
+	locked.mu.Lock()
+	defer locked.mu.Unlock()
+	return locked.observer.Object(ctx, path, pointer)
+}
+
+func (locked *lockedObserver) RemoteSegment(ctx context.Context, path ScopedPath, pointer *pb.Pointer) error {
+	locked.mu.Lock()
+	defer locked.mu.Unlock()
+	return locked.observer.RemoteSegment(ctx, path, pointer)
+}
+
+func (locked *lockedObserver) InlineSegment(ctx context.Context, path ScopedPath, pointer *pb.Pointer) error {
+	locked.mu.Lock()
+	defer locked.mu.Unlock()
+	return locked.observer.InlineSegment(ctx, path, pointer)
+}
+
+// rangeObserver delivers the segments of a single range to an observer.
+type rangeObserver struct {
+	observer *observerContext
+	target   Observer
+
+	// checkpoint is the target, when its state can be checkpointed for the range.
+	checkpoint CheckpointObserver
+	// restored is set when the target has been restored from a checkpoint.
+	restored bool
+}
+
+// maxRanges is the maximum number of ranges, since the keyspace is split on
+// the first two hex characters of the project ID.
+const maxRanges = 256
+
+// keyRange is a range of the pointer DB keyspace, which starts at first and
+// ends before end. A nil end means that the range extends to the end of the
+// keyspace.
+type keyRange struct {
+	first storage.Key
+	end   storage.Key
+}
+
+// Contains returns whether the key is before the end of the range.
+func (r keyRange) Contains(key storage.Key) bool {
+	return r.end == nil || key.Less(r.end)
+}
+
+// partitionKeyspace splits the keyspace into count ranges of project IDs,
+// which together cover every key.
+func partitionKeyspace(count int) []keyRange {
+	if count < 1 {
+		count = 1
+	}
+	if count > maxRanges {
+		count = maxRanges
+	}
+
+	ranges := make([]keyRange, count)
+	for i := 1; i < count; i++ {
+		boundary := storage.Key(fmt.Sprintf("%02x", i*maxRanges/count))
+		ranges[i-1].end = boundary
+		ranges[i].first = boundary
+	}
+	return ranges
 }
 
 func (observer *observerContext) Wait() error {
@@ -74,26 +244,40 @@ func (observer *observerContext) Wait() error {
 type LoopConfig struct {
 	CoalesceDuration time.Duration `help:"how long to wait for new observers before starting iteration" releaseDefault:"5s" devDefault:"5s"`
 	RateLimit        float64       `help:"metainfo loop rate limit (default is 0 which is unlimited segments per second)" default:"0"`
+	Workers          int           `help:"number of project ID ranges which are iterated concurrently (1 iterates serially)" default:"1"`
+
+	CheckpointInterval time.Duration `help:"how often the progress of the metainfo loop is persisted, so that it can be resumed after a restart (0 disables checkpoints)" releaseDefault:"1m" devDefault:"10s"`
+	CheckpointMaxAge   time.Duration `help:"how long after its last checkpoint an interrupted iteration is resumed, older iterations start over (0 resumes at any age)" default:"24h0m0s"`
+	RetryLimit         int           `help:"how many times an iteration is resumed after a database error, before the observers fail" default:"3"`
+	RetryInterval      time.Duration `help:"how long to wait before resuming an iteration after a database error" releaseDefault:"10s" devDefault:"1s"`
 }
 
 // Loop is a metainfo loop service.
 //
 // architecture: Service
 type Loop struct {
-	config LoopConfig
-	db     PointerDB
-	join   chan *observerContext
-	done   chan struct{}
+	config      LoopConfig
+	db          PointerDB
+	checkpoints LoopCheckpointsDB
+	join        chan *observerContext
+	done        chan struct{}
+
+	// status contains the *loopStatus of the current iteration.
+	status atomic.Value
 }
 
-// NewLoop creates a new metainfo loop service.
-func NewLoop(config LoopConfig, db PointerDB) *Loop {
-	return &Loop{
-		db:     db,
-		config: config,
-		join:   make(chan *observerContext),
-		done:   make(chan struct{}),
+// NewLoop creates a new metainfo loop service. The progress of the loop is
+// only persisted when checkpoints is not nil.
+func NewLoop(config LoopConfig, db PointerDB, checkpoints LoopCheckpointsDB) *Loop {
+	loop := &Loop{
+		db:          db,
+		config:      config,
+		checkpoints: checkpoints,
+		join:        make(chan *observerContext),
+		done:        make(chan struct{}),
 	}
+	mon.Chain(loop)
+	return loop
 }
 
 // Join will join the looper for one full cycle until completion and then returns.
@@ -167,7 +351,15 @@ waitformore:
 			return ctx.Err()
 		}
 	}
-	return iterateDatabase(ctx, loop.db, observers, rate.NewLimiter(rate.Limit(loop.config.RateLimit), 1))
+
+	iteration := &iteration{
+		db:          loop.db,
+		config:      loop.config,
+		rateLimiter: rate.NewLimiter(rate.Limit(loop.config.RateLimit), 1),
+		checkpoints: loop.checkpoints,
+		status:      &loop.status,
+	}
+	return iteration.run(ctx, observers)
 }
 
 // IterateDatabase iterates over PointerDB and notifies specified observers about results.
@@ -180,26 +372,38 @@ func IterateDatabase(ctx context.Context, rateLimit float64, db PointerDB, obser
 			done:     make(chan error),
 		}
 	}
-	return iterateDatabase(ctx, db, obsContexts, rate.NewLimiter(rate.Limit(rateLimit), 1))
+
+	iteration := &iteration{
+		db:          db,
+		config:      LoopConfig{Workers: 1},
+		rateLimiter: rate.NewLimiter(rate.Limit(rateLimit), 1),
+	}
+	return iteration.run(ctx, obsContexts)
 }
 
 // handlePointer deals with a pointer for a single observer
 // if there is some error on the observer, handles the error and returns false. Otherwise, returns true.
-func handlePointer(ctx context.Context, observer *observerContext, path ScopedPath, isLastSegment bool, pointer *pb.Pointer) bool {
+func handlePointer(ctx context.Context, ranged *rangeObserver, path ScopedPath, isLastSegment bool, pointer *pb.Pointer) bool {
+	observer, target := ranged.observer, ranged.target
+	if observer.Finished() {
+		// the observer has failed in another range
+		return false
+	}
+
 	switch pointer.GetType() {
 	case pb.Pointer_REMOTE:
-		if observer.HandleError(observer.RemoteSegment(ctx, path, pointer)) {
+		if observer.HandleError(target.RemoteSegment(ctx, path, pointer)) {
 			return false
 		}
 	case pb.Pointer_INLINE:
-		if observer.HandleError(observer.InlineSegment(ctx, path, pointer)) {
+		if observer.HandleError(target.InlineSegment(ctx, path, pointer)) {
 			return false
 		}
 	default:
 		return false
 	}
 	if isLastSegment {
-		if observer.HandleError(observer.Object(ctx, path, pointer)) {
+		if observer.HandleError(target.Object(ctx, path, pointer)) {
 			return false
 		}
 	}
@@ -220,7 +424,20 @@ func (loop *Loop) Wait() {
 	<-loop.done
 }
 
-func iterateDatabase(ctx context.Context, db PointerDB, observers []*observerContext, rateLimiter *rate.Limiter) (err error) {
+// iteration is a single pass over the pointer DB.
+type iteration struct {
+	db          PointerDB
+	config      LoopConfig
+	rateLimiter *rate.Limiter
+
+	// checkpoints is nil, when the progress isn't persisted.
+	checkpoints LoopCheckpointsDB
+	// status is updated with the progress of the iteration, when not nil.
+	status *atomic.Value
+}
+
+// run iterates over the pointer DB and notifies the observers about the results.
+func (it *iteration) run(ctx context.Context, observers []*observerContext) (err error) {
 	defer func() {
 		if err != nil {
 			for _, observer := range observers {
@@ -231,20 +448,258 @@ func iterateDatabase(ctx context.Context, db PointerDB, observers []*observerCon
 		finishObservers(observers)
 	}()
 
-	err = db.Iterate(ctx, storage.IterateOptions{Recurse: true},
-		func(ctx context.Context, it storage.Iterator) error {
+	keyRanges := partitionKeyspace(it.config.Workers)
+	ranges := make([]*rangeIteration, len(keyRanges))
+	progress := make([]*rangeProgress, len(keyRanges))
+	for k, keyRange := range keyRanges {
+		progress[k] = newRangeProgress(keyRange)
+		ranges[k] = &rangeIteration{
+			index:    k,
+			count:    len(keyRanges),
+			keyRange: keyRange,
+			progress: progress[k],
+			names:    map[string]bool{},
+		}
+	}
+
+	// with several ranges every range gets its own observers, which are
+	// joined back once all ranges have been iterated
+	targets := make([][]Observer, len(observers))
+	for i, observer := range observers {
+		if len(ranges) == 1 {
+			targets[i] = []Observer{observer.Observer}
+		} else {
+			targets[i], err = observer.fork(ctx, len(ranges))
+			if err != nil {
+				observer.HandleError(err)
+				continue
+			}
+		}
+
+		// shared observers can't be checkpointed for a single range
+		separate := len(ranges) == 1 || observer.forks()
+		for k, target := range targets[i] {
+			ranged := &rangeObserver{observer: observer, target: target}
+			if separate {
+				ranged.checkpoint, _ = target.(CheckpointObserver)
+			}
+			ranges[k].add(ranged)
+		}
+	}
+
+	generation := it.restore(ctx, ranges)
+	if it.status != nil {
+		it.status.Store(&loopStatus{generation: generation, ranges: progress})
+	}
+
+	if len(ranges) == 1 {
+		return it.iterateRange(ctx, generation, ranges[0])
+	}
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	for _, r := range ranges {
+		r := r
+		group.Go(func() error {
+			return it.iterateRange(groupCtx, generation, r)
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return err
+	}
+
+	for i, observer := range observers {
+		if observer.Finished() {
+			continue
+		}
+		observer.HandleError(observer.join(ctx, targets[i]))
+	}
+	return nil
+}
+
+// restore loads the checkpoints of the last iteration and restores the
+// observers when the iteration can be resumed, otherwise it starts a new
+// generation. An iteration, whose last checkpoint is older than the maximum
+// age, isn't resumed. It returns the generation of the iteration, which is 0
+// when the progress isn't persisted.
+//
+// Only observers which can be checkpointed are restored. Every other
+// observer, e.g. audit, garbage collection or graceful exit, starts its range
+// from the beginning, so a resumed range is iterated from its first key as
+// long as it has such an observer.
+func (it *iteration) restore(ctx context.Context, ranges []*rangeIteration) (generation int64) {
+	defer mon.Task()(&ctx)(nil)
+
+	if it.checkpoints == nil || it.config.CheckpointInterval <= 0 {
+		return 0
+	}
+
+	checkpoints, err := it.checkpoints.GetRanges(ctx)
+	if err != nil {
+		mon.Event("metainfo_loop_checkpoint_failed")
+		return 0
+	}
+
+	expired := expiredCheckpoints(checkpoints, it.config.CheckpointMaxAge, time.Now())
+	if expired {
+		mon.Event("metainfo_loop_checkpoint_expired")
+	}
+
+	if expired || !resumable(checkpoints, len(ranges)) {
+		generation = 1
+		for _, checkpoint := range checkpoints {
+			if checkpoint.Generation >= generation {
+				generation = checkpoint.Generation + 1
+			}
+		}
+
+		err := it.checkpoints.ResetRanges(ctx, generation, len(ranges))
+		if err != nil {
+			mon.Event("metainfo_loop_checkpoint_failed")
+			return 0
+		}
+		return generation
+	}
+
+	mon.Event("metainfo_loop_resumed")
+	for k, r := range ranges {
+		r.restore(ctx, it.checkpoints, checkpoints[k])
+	}
+	return checkpoints[0].Generation
+}
+
+// checkpoint persists the progress of the range together with the state of
+// the observers, which can be checkpointed. The states are copied on the
+// iteration path, but unless wait is set they're encoded and persisted in the
+// background, one checkpoint of the range at a time; a background checkpoint
+// is skipped while the previous one is still being persisted. Failing to
+// persist the progress doesn't affect the iteration.
+func (it *iteration) checkpoint(ctx context.Context, generation int64, r *rangeIteration, observers []*rangeObserver, done, wait bool) {
+	defer mon.Task()(&ctx)(nil)
+
+	if generation == 0 || !r.caughtUp(observers) {
+		r.lastCheckpoint = time.Now()
+		return
+	}
+	if r.writer.busy() {
+		if !wait {
+			return
+		}
+		r.writer.wait()
+	}
+	r.lastCheckpoint = time.Now()
+
+	snapshots := map[string]interface{}{}
+	for _, ranged := range observers {
+		if ranged.checkpoint == nil || ranged.observer.Finished() {
+			continue
+		}
+		snapshot, err := ranged.checkpoint.Checkpoint(ctx)
+		if ranged.observer.HandleError(LoopError.Wrap(err)) {
+			continue
+		}
+		snapshots[ranged.checkpoint.CheckpointName()] = snapshot
+	}
+
+	checkpoint := RangeCheckpoint{
+		Generation: generation,
+		Index:      r.index,
+		Count:      r.count,
+		LastKey:    append(storage.Key(nil), r.lastKey...),
+		Done:       done,
+		UpdatedAt:  r.lastCheckpoint,
+	}
+	write := func() {
+		// observers without a state start the range over when it's resumed
+		states := map[string][]byte{}
+		for name, snapshot := range snapshots {
+			state, err := EncodeCheckpoint(snapshot)
+			if err != nil {
+				mon.Event("metainfo_loop_checkpoint_failed")
+				continue
+			}
+			states[name] = state
+		}
+
+		if err := it.checkpoints.SetRange(ctx, checkpoint, states); err != nil {
+			mon.Event("metainfo_loop_checkpoint_failed")
+		}
+	}
+
+	if wait {
+		write()
+		return
+	}
+	r.writer.start(write)
+}
+
+// iterateRange iterates over a single range of the pointer DB and resumes
+// the iteration after database errors.
+func (it *iteration) iterateRange(ctx context.Context, generation int64, r *rangeIteration) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	// the next iteration must not see checkpoints of this one afterwards
+	defer r.writer.wait()
+
+	for retries := 0; ; retries++ {
+		err = it.iterateRangeOnce(ctx, generation, r)
+		if err == nil || LoopError.Has(err) || ctx.Err() != nil || retries >= it.config.RetryLimit {
+			return err
+		}
+
+		mon.Event("metainfo_loop_retried")
+		if !sync2.Sleep(ctx, it.config.RetryInterval) {
+			return ctx.Err()
+		}
+	}
+}
+
+// iterateRangeOnce iterates over a single range of the pointer DB, starting
+// after the last key delivered to the observers, and notifies the observers
+// about results.
+func (it *iteration) iterateRangeOnce(ctx context.Context, generation int64, r *rangeIteration) (err error) {
+	var observers []*rangeObserver
+	for _, observer := range r.observers {
+		if !observer.observer.Finished() {
+			observers = append(observers, observer)
+		}
+	}
+	if len(observers) == 0 {
+		return nil
+	}
+
+	first, ok := r.start(observers)
+	if !ok {
+		r.progress.Finish()
+		return nil
+	}
+	if r.lastCheckpoint.IsZero() {
+		r.lastCheckpoint = time.Now()
+	}
+
+	err = it.db.Iterate(ctx, storage.IterateOptions{First: first, Recurse: true},
+		func(ctx context.Context, iterator storage.Iterator) error {
 			var item storage.ListItem
 
-			// iterate over every segment in metainfo
+			// iterate over every segment in the range
 		nextSegment:
-			for it.Next(ctx, &item) {
-				if err := rateLimiter.Wait(ctx); err != nil {
-					// We don't really execute concurrent batches so we should never
+			for iterator.Next(ctx, &item) {
+				if !r.keyRange.Contains(item.Key) {
+					return nil
+				}
+
+				if err := it.rateLimiter.Wait(ctx); err != nil {
+					// Every range waits for a single token, so we should never
 					// exceed the burst size of 1 and this should never happen.
 					// We can also enter here if the context is cancelled.
 					return LoopError.Wrap(err)
 				}
 
+				// the observers have seen every key before this one
+				if it.config.CheckpointInterval > 0 && time.Since(r.lastCheckpoint) >= it.config.CheckpointInterval {
+					it.checkpoint(ctx, generation, r, observers, false, false)
+				}
+				r.lastKey = append(r.lastKey[:0], item.Key...)
+				r.progress.Update(item.Key)
+
 				rawPath := item.Key.String()
 				pointer := &pb.Pointer{}
 
@@ -261,16 +716,37 @@ func iterateDatabase(ctx context.Context, db PointerDB, observers []*observerCon
 					continue nextSegment
 				}
 
-				isLastSegment := pathElements[1] == "l"
+				segment := pathElements[1]
+				if segment == deleteMarkerSegment {
+					// delete markers of versioned objects don't have any data
+					continue nextSegment
+				}
 
 				path := ScopedPath{
 					Raw:                 rawPath,
 					ProjectIDString:     pathElements[0],
-					Segment:             pathElements[1],
+					Segment:             segment,
 					BucketName:          pathElements[2],
 					EncryptedObjectPath: storj.JoinPaths(pathElements[3:]...),
 				}
 
+				// non-current versions have the version number appended to the path
+				if strings.HasPrefix(segment, versionSegmentPrefix) {
+					if len(pathElements) < 5 {
+						continue nextSegment
+					}
+
+					version, err := parseVersion(pathElements[len(pathElements)-1])
+					if err != nil {
+						return LoopError.Wrap(err)
+					}
+					path.Version = version
+					path.EncryptedObjectPath = storj.JoinPaths(pathElements[3 : len(pathElements)-1]...)
+					segment = strings.TrimPrefix(segment, versionSegmentPrefix)
+				}
+
+				isLastSegment := segment == "l"
+
 				projectID, err := uuid.Parse(path.ProjectIDString)
 				if err != nil {
 					return LoopError.Wrap(err)
@@ -279,6 +755,10 @@ func iterateDatabase(ctx context.Context, db PointerDB, observers []*observerCon
 
 				nextObservers := observers[:0]
 				for _, observer := range observers {
+					if observer.restored && r.seen(item.Key) {
+						nextObservers = append(nextObservers, observer)
+						continue
+					}
 					keepObserver := handlePointer(ctx, observer, path, isLastSegment, pointer)
 					if keepObserver {
 						nextObservers = append(nextObservers, observer)
@@ -299,7 +779,17 @@ func iterateDatabase(ctx context.Context, db PointerDB, observers []*observerCon
 			}
 			return nil
 		})
-	return err
+	if err != nil && !LoopError.Has(err) && ctx.Err() == nil {
+		// the observers have seen every key up to the database error
+		it.checkpoint(ctx, generation, r, observers, false, true)
+	}
+	if err != nil || len(observers) == 0 {
+		return err
+	}
+
+	r.progress.Finish()
+	it.checkpoint(ctx, generation, r, observers, true, true)
+	return nil
 }
 
 func finishObservers(observers []*observerContext) {
@@ -6,11 +6,14 @@ package metainfo_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/gogo/protobuf/proto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
@@ -19,11 +22,14 @@ import (
 	"storj.io/common/errs2"
 	"storj.io/common/memory"
 	"storj.io/common/pb"
+	"storj.io/common/storj"
 	"storj.io/common/testcontext"
 	"storj.io/common/testrand"
 	"storj.io/storj/private/testplanet"
 	"storj.io/storj/satellite"
 	"storj.io/storj/satellite/metainfo"
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/teststore"
 )
 
 // TestLoop does the following
@@ -222,7 +228,7 @@ func TestLoopCancel(t *testing.T) {
 		// create a new metainfo loop
 		metaLoop := metainfo.NewLoop(metainfo.LoopConfig{
 			CoalesceDuration: 1 * time.Second,
-		}, satellite.Metainfo.Database)
+		}, satellite.Metainfo.Database, nil)
 
 		// create a cancelable context to pass into metaLoop.Run
 		loopCtx, cancel := context.WithCancel(ctx)
@@ -333,3 +339,488 @@ func (obs *testObserver) InlineSegment(ctx context.Context, path metainfo.Scoped
 	obs.uniquePaths[path.Raw] = path
 	return nil
 }
+
+// TestLoopParallel does the following:
+// * store segments of many projects directly in a pointer DB
+// * iterate serially with metainfo.IterateDatabase
+// * iterate with several workers, joining a serial, a forking and a concurrent observer
+// * expect that every observer has seen the same segments as the serial iteration
+func TestLoopParallel(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	db := teststore.New()
+
+	for i := 0; i < 40; i++ {
+		projectID := testrand.UUID().String()
+
+		// bucket metadata isn't an object
+		require.NoError(t, db.Put(ctx, storage.Key(storj.JoinPaths(projectID, "bucket")), marshalPointer(t, &pb.Pointer{Type: pb.Pointer_INLINE})))
+
+		for k := 0; k < 3; k++ {
+			object := fmt.Sprintf("object%d", k)
+			remote := &pb.Pointer{Type: pb.Pointer_REMOTE, Remote: &pb.RemoteSegment{}}
+			inline := &pb.Pointer{Type: pb.Pointer_INLINE, InlineSegment: testrand.Bytes(10)}
+
+			require.NoError(t, db.Put(ctx, storage.Key(storj.JoinPaths(projectID, "s0", "bucket", object)), marshalPointer(t, remote)))
+			require.NoError(t, db.Put(ctx, storage.Key(storj.JoinPaths(projectID, "l", "bucket", object)), marshalPointer(t, inline)))
+		}
+	}
+
+	expected := newTestObserver(nil)
+	require.NoError(t, metainfo.IterateDatabase(ctx, 0, db, expected))
+	require.Equal(t, 120, expected.objectCount)
+	require.Equal(t, 120, expected.remoteSegCount)
+	require.Equal(t, 120, expected.inlineSegCount)
+
+	for _, workers := range []int{2, 3, 16, 1000} {
+		workers := workers
+		t.Run(fmt.Sprintf("workers=%d", workers), func(t *testing.T) {
+			metaLoop := metainfo.NewLoop(metainfo.LoopConfig{
+				CoalesceDuration: 100 * time.Millisecond,
+				Workers:          workers,
+			}, db, nil)
+			loopCtx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			ctx.Go(func() error {
+				return errs2.IgnoreCanceled(metaLoop.Run(loopCtx))
+			})
+
+			serial := newTestObserver(nil)
+			forking := &forkingTestObserver{newTestObserver(nil)}
+			concurrent := &concurrentTestObserver{testObserver: newTestObserver(nil)}
+
+			var group errgroup.Group
+			for _, observer := range []metainfo.Observer{serial, forking, concurrent} {
+				observer := observer
+				group.Go(func() error {
+					return metaLoop.Join(ctx, observer)
+				})
+			}
+			require.NoError(t, group.Wait())
+
+			for _, obs := range []*testObserver{serial, forking.testObserver, concurrent.testObserver} {
+				assert.Equal(t, expected.objectCount, obs.objectCount)
+				assert.Equal(t, expected.remoteSegCount, obs.remoteSegCount)
+				assert.Equal(t, expected.inlineSegCount, obs.inlineSegCount)
+				assert.Equal(t, expected.uniquePaths, obs.uniquePaths)
+			}
+		})
+	}
+}
+
+// TestLoopParallelObserverError checks that an observer which fails in one range
+// gets the error while the other observers see every segment.
+func TestLoopParallelObserverError(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	db := teststore.New()
+	for i := 0; i < 20; i++ {
+		key := storj.JoinPaths(testrand.UUID().String(), "l", "bucket", "object")
+		require.NoError(t, db.Put(ctx, storage.Key(key), marshalPointer(t, &pb.Pointer{Type: pb.Pointer_REMOTE, Remote: &pb.RemoteSegment{}})))
+	}
+
+	metaLoop := metainfo.NewLoop(metainfo.LoopConfig{
+		CoalesceDuration: 100 * time.Millisecond,
+		Workers:          4,
+	}, db, nil)
+	loopCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ctx.Go(func() error {
+		return errs2.IgnoreCanceled(metaLoop.Run(loopCtx))
+	})
+
+	healthy := &forkingTestObserver{newTestObserver(nil)}
+	failing := &concurrentTestObserver{testObserver: newTestObserver(func(ctx context.Context) error {
+		return errors.New("test error")
+	})}
+
+	var group errgroup.Group
+	group.Go(func() error {
+		return metaLoop.Join(ctx, healthy)
+	})
+	group.Go(func() error {
+		err := metaLoop.Join(ctx, failing)
+		if err == nil || !strings.Contains(err.Error(), "test error") {
+			return errors.New("expected test error")
+		}
+		return nil
+	})
+	require.NoError(t, group.Wait())
+
+	assert.Equal(t, 20, healthy.objectCount)
+	assert.Equal(t, 20, healthy.remoteSegCount)
+}
+
+func marshalPointer(t *testing.T, pointer *pb.Pointer) storage.Value {
+	data, err := proto.Marshal(pointer)
+	require.NoError(t, err)
+	return data
+}
+
+// forkingTestObserver forks a test observer for every range.
+type forkingTestObserver struct {
+	*testObserver
+}
+
+func (obs *forkingTestObserver) Fork(ctx context.Context) (metainfo.Observer, error) {
+	return newTestObserver(obs.onSegment), nil
+}
+
+func (obs *forkingTestObserver) Join(ctx context.Context, forked metainfo.Observer) error {
+	other := forked.(*testObserver)
+	obs.objectCount += other.objectCount
+	obs.remoteSegCount += other.remoteSegCount
+	obs.inlineSegCount += other.inlineSegCount
+	for raw, path := range other.uniquePaths {
+		if _, ok := obs.uniquePaths[raw]; ok {
+			return errors.New("path seen by several forks")
+		}
+		obs.uniquePaths[raw] = path
+	}
+	return nil
+}
+
+// concurrentTestObserver protects a test observer with a mutex.
+type concurrentTestObserver struct {
+	mu sync.Mutex
+	*testObserver
+}
+
+func (obs *concurrentTestObserver) ConcurrentSafe() {}
+
+func (obs *concurrentTestObserver) RemoteSegment(ctx context.Context, path metainfo.ScopedPath, pointer *pb.Pointer) error {
+	obs.mu.Lock()
+	defer obs.mu.Unlock()
+	return obs.testObserver.RemoteSegment(ctx, path, pointer)
+}
+
+func (obs *concurrentTestObserver) Object(ctx context.Context, path metainfo.ScopedPath, pointer *pb.Pointer) error {
+	obs.mu.Lock()
+	defer obs.mu.Unlock()
+	return obs.testObserver.Object(ctx, path, pointer)
+}
+
+func (obs *concurrentTestObserver) InlineSegment(ctx context.Context, path metainfo.ScopedPath, pointer *pb.Pointer) error {
+	obs.mu.Lock()
+	defer obs.mu.Unlock()
+	return obs.testObserver.InlineSegment(ctx, path, pointer)
+}
+
+// TestLoopRetry checks that an iteration is resumed after transient database
+// errors without delivering a segment twice.
+func TestLoopRetry(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	db := &flakyPointerDB{KeyValueStore: newTestPointerDB(ctx, t, 20), failures: 3, failAfter: 5}
+
+	metaLoop := metainfo.NewLoop(metainfo.LoopConfig{
+		CoalesceDuration: 100 * time.Millisecond,
+		Workers:          4,
+		RetryLimit:       3,
+	}, db, nil)
+	loopCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ctx.Go(func() error {
+		return errs2.IgnoreCanceled(metaLoop.Run(loopCtx))
+	})
+
+	observer := &forkingTestObserver{newTestObserver(nil)}
+	require.NoError(t, metaLoop.Join(ctx, observer))
+	require.EqualValues(t, 0, atomic.LoadInt64(&db.failures))
+
+	assert.Equal(t, 20, observer.objectCount)
+	assert.Equal(t, 20, observer.remoteSegCount)
+	assert.Len(t, observer.uniquePaths, 40)
+}
+
+// TestLoopCheckpoint does the following:
+// * iterate with a database, which fails permanently in every range
+// * iterate with a new loop, which resumes from the persisted checkpoints
+// * expect that the restored observer has seen every segment exactly once
+// * expect that the next iteration starts a new generation from the beginning
+func TestLoopCheckpoint(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	pointers := newTestPointerDB(ctx, t, 40)
+	checkpoints := newTestCheckpointsDB()
+	config := metainfo.LoopConfig{
+		CoalesceDuration:   100 * time.Millisecond,
+		Workers:            4,
+		CheckpointInterval: time.Nanosecond,
+	}
+
+	{ // interrupted iteration
+		db := &flakyPointerDB{KeyValueStore: pointers, failures: 4, failAfter: 7}
+
+		metaLoop := metainfo.NewLoop(config, db, checkpoints)
+		loopErr := make(chan error, 1)
+		go func() {
+			loopErr <- metaLoop.Run(ctx)
+		}()
+
+		observer := &checkpointTestObserver{newTestObserver(nil)}
+		require.Error(t, metaLoop.Join(ctx, observer))
+		require.Error(t, <-loopErr)
+		require.Equal(t, 1, checkpoints.generations())
+	}
+
+	{ // resumed iteration
+		metaLoop := metainfo.NewLoop(config, pointers, checkpoints)
+		loopCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		ctx.Go(func() error {
+			return errs2.IgnoreCanceled(metaLoop.Run(loopCtx))
+		})
+
+		resumed := &checkpointTestObserver{newTestObserver(nil)}
+		require.NoError(t, metaLoop.Join(ctx, resumed))
+		require.Equal(t, 1, checkpoints.generations())
+		require.NotZero(t, checkpoints.restoredStates())
+		require.InDelta(t, 100, metaLoop.Progress(), 0.001)
+
+		assert.Equal(t, 40, resumed.objectCount)
+		assert.Equal(t, 40, resumed.remoteSegCount)
+		assert.Len(t, resumed.uniquePaths, 80)
+
+		// the resumed iteration has finished, so the next one starts over
+		restored := checkpoints.restoredStates()
+		next := &checkpointTestObserver{newTestObserver(nil)}
+		require.NoError(t, metaLoop.Join(ctx, next))
+		require.Equal(t, 2, checkpoints.generations())
+		require.Equal(t, restored, checkpoints.restoredStates())
+
+		assert.Equal(t, 40, next.objectCount)
+		assert.Equal(t, 40, next.remoteSegCount)
+		assert.Len(t, next.uniquePaths, 80)
+	}
+}
+
+// TestLoopCheckpointExpired does the following:
+// * iterate with a database, which fails permanently in every range
+// * age the persisted checkpoints beyond the maximum age
+// * expect that the next iteration starts a new generation from the beginning
+func TestLoopCheckpointExpired(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	pointers := newTestPointerDB(ctx, t, 40)
+	checkpoints := newTestCheckpointsDB()
+	config := metainfo.LoopConfig{
+		CoalesceDuration:   100 * time.Millisecond,
+		Workers:            4,
+		CheckpointInterval: time.Nanosecond,
+		CheckpointMaxAge:   time.Hour,
+	}
+
+	{ // interrupted iteration
+		db := &flakyPointerDB{KeyValueStore: pointers, failures: 4, failAfter: 7}
+
+		metaLoop := metainfo.NewLoop(config, db, checkpoints)
+		loopErr := make(chan error, 1)
+		go func() {
+			loopErr <- metaLoop.Run(ctx)
+		}()
+
+		observer := &checkpointTestObserver{newTestObserver(nil)}
+		require.Error(t, metaLoop.Join(ctx, observer))
+		require.Error(t, <-loopErr)
+		require.Equal(t, 1, checkpoints.generations())
+	}
+
+	checkpoints.age(2 * time.Hour)
+
+	{ // expired iteration
+		metaLoop := metainfo.NewLoop(config, pointers, checkpoints)
+		loopCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		ctx.Go(func() error {
+			return errs2.IgnoreCanceled(metaLoop.Run(loopCtx))
+		})
+
+		next := &checkpointTestObserver{newTestObserver(nil)}
+		require.NoError(t, metaLoop.Join(ctx, next))
+		require.Equal(t, 2, checkpoints.generations())
+		require.Zero(t, checkpoints.restoredStates())
+
+		assert.Equal(t, 40, next.objectCount)
+		assert.Equal(t, 40, next.remoteSegCount)
+		assert.Len(t, next.uniquePaths, 80)
+	}
+}
+
+// newTestPointerDB returns a pointer DB with a remote and an inline segment
+// for an object in each of count projects.
+func newTestPointerDB(ctx context.Context, t *testing.T, count int) storage.KeyValueStore {
+	db := teststore.New()
+	for i := 0; i < count; i++ {
+		projectID := testrand.UUID().String()
+		remote := &pb.Pointer{Type: pb.Pointer_REMOTE, Remote: &pb.RemoteSegment{}}
+		inline := &pb.Pointer{Type: pb.Pointer_INLINE, InlineSegment: testrand.Bytes(10)}
+
+		require.NoError(t, db.Put(ctx, storage.Key(storj.JoinPaths(projectID, "s0", "bucket", "object")), marshalPointer(t, remote)))
+		require.NoError(t, db.Put(ctx, storage.Key(storj.JoinPaths(projectID, "l", "bucket", "object")), marshalPointer(t, inline)))
+	}
+	return db
+}
+
+// flakyPointerDB fails the first iterations after some items.
+type flakyPointerDB struct {
+	storage.KeyValueStore
+	failures  int64
+	failAfter int
+}
+
+func (db *flakyPointerDB) Iterate(ctx context.Context, opts storage.IterateOptions, fn func(context.Context, storage.Iterator) error) error {
+	if atomic.AddInt64(&db.failures, -1) < 0 {
+		atomic.AddInt64(&db.failures, 1)
+		return db.KeyValueStore.Iterate(ctx, opts, fn)
+	}
+	return db.KeyValueStore.Iterate(ctx, opts, func(ctx context.Context, it storage.Iterator) error {
+		if err := fn(ctx, &limitedIterator{Iterator: it, limit: db.failAfter}); err != nil {
+			return err
+		}
+		return errors.New("connection lost")
+	})
+}
+
+// limitedIterator stops after limit items.
+type limitedIterator struct {
+	storage.Iterator
+	limit int
+}
+
+func (it *limitedIterator) Next(ctx context.Context, item *storage.ListItem) bool {
+	if it.limit <= 0 {
+		return false
+	}
+	it.limit--
+	return it.Iterator.Next(ctx, item)
+}
+
+// checkpointTestObserver forks test observers, which can be checkpointed.
+type checkpointTestObserver struct {
+	*testObserver
+}
+
+type testObserverState struct {
+	ObjectCount    int
+	RemoteSegCount int
+	InlineSegCount int
+	UniquePaths    map[string]metainfo.ScopedPath
+}
+
+func (obs *checkpointTestObserver) Fork(ctx context.Context) (metainfo.Observer, error) {
+	return &checkpointTestObserver{newTestObserver(nil)}, nil
+}
+
+func (obs *checkpointTestObserver) Join(ctx context.Context, forked metainfo.Observer) error {
+	return (&forkingTestObserver{obs.testObserver}).Join(ctx, forked.(*checkpointTestObserver).testObserver)
+}
+
+func (obs *checkpointTestObserver) CheckpointName() string { return "test" }
+
+func (obs *checkpointTestObserver) Checkpoint(ctx context.Context) (interface{}, error) {
+	uniquePaths := make(map[string]metainfo.ScopedPath, len(obs.uniquePaths))
+	for path, scoped := range obs.uniquePaths {
+		uniquePaths[path] = scoped
+	}
+	return testObserverState{
+		ObjectCount:    obs.objectCount,
+		RemoteSegCount: obs.remoteSegCount,
+		InlineSegCount: obs.inlineSegCount,
+		UniquePaths:    uniquePaths,
+	}, nil
+}
+
+func (obs *checkpointTestObserver) Restore(ctx context.Context, decode func(state interface{}) error) error {
+	var state testObserverState
+	if err := decode(&state); err != nil {
+		return err
+	}
+	obs.objectCount = state.ObjectCount
+	obs.remoteSegCount = state.RemoteSegCount
+	obs.inlineSegCount = state.InlineSegCount
+	obs.uniquePaths = state.UniquePaths
+	return nil
+}
+
+// testCheckpointsDB keeps the checkpoints of the metainfo loop in memory.
+type testCheckpointsDB struct {
+	mu       sync.Mutex
+	ranges   []metainfo.RangeCheckpoint
+	states   map[int]map[string][]byte
+	resets   int
+	restored int
+}
+
+func newTestCheckpointsDB() *testCheckpointsDB {
+	return &testCheckpointsDB{states: map[int]map[string][]byte{}}
+}
+
+func (db *testCheckpointsDB) GetRanges(ctx context.Context) ([]metainfo.RangeCheckpoint, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return append([]metainfo.RangeCheckpoint(nil), db.ranges...), nil
+}
+
+func (db *testCheckpointsDB) ResetRanges(ctx context.Context, generation int64, count int) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.resets++
+	db.ranges = make([]metainfo.RangeCheckpoint, count)
+	for index := range db.ranges {
+		db.ranges[index] = metainfo.RangeCheckpoint{Generation: generation, Index: index, Count: count, UpdatedAt: time.Now()}
+	}
+	db.states = map[int]map[string][]byte{}
+	return nil
+}
+
+func (db *testCheckpointsDB) SetRange(ctx context.Context, checkpoint metainfo.RangeCheckpoint, states map[string][]byte) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if checkpoint.Index >= len(db.ranges) || db.ranges[checkpoint.Index].Generation != checkpoint.Generation {
+		return errors.New("range not found")
+	}
+	checkpoint.LastKey = append(storage.Key(nil), checkpoint.LastKey...)
+	db.ranges[checkpoint.Index] = checkpoint
+	db.states[checkpoint.Index] = states
+	return nil
+}
+
+func (db *testCheckpointsDB) GetObserverState(ctx context.Context, generation int64, name string, index int) ([]byte, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if index >= len(db.ranges) || db.ranges[index].Generation != generation {
+		return nil, nil
+	}
+	state := db.states[index][name]
+	if state != nil {
+		db.restored++
+	}
+	return state, nil
+}
+
+// age moves the checkpoints into the past.
+func (db *testCheckpointsDB) age(duration time.Duration) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	for index := range db.ranges {
+		db.ranges[index].UpdatedAt = db.ranges[index].UpdatedAt.Add(-duration)
+	}
+}
+
+func (db *testCheckpointsDB) generations() int {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return db.resets
+}
+
+func (db *testCheckpointsDB) restoredStates() int {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return db.restored
+}
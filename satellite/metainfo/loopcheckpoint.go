@@ -0,0 +1,338 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfo
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+
+	"storj.io/storj/storage"
+)
+
+// CheckpointObserver is an observer which can persist its partial state, so
+// that an interrupted iteration can be resumed without starting over.
+//
+// When the loop iterates several ranges in parallel, only the instances
+// returned by Fork are checkpointed; every range is checkpointed separately.
+// A checkpoint only contains what the observer has seen up to and including
+// the last key of the range, so a restored observer doesn't get those
+// segments again. Observers which can't be checkpointed get every segment of
+// a resumed range again.
+type CheckpointObserver interface {
+	Observer
+	// CheckpointName identifies the state of the observer across restarts.
+	CheckpointName() string
+	// Checkpoint returns a copy of the current state of the observer. It's
+	// encoded with encoding/gob and persisted while the iteration continues,
+	// so it must not share memory, which the observer modifies afterwards.
+	Checkpoint(ctx context.Context) (interface{}, error)
+	// Restore replaces the state of the observer with a state returned by
+	// Checkpoint, which decode decodes into the value the pointer points to.
+	Restore(ctx context.Context, decode func(state interface{}) error) error
+}
+
+// EncodeCheckpoint encodes a state returned by CheckpointObserver.Checkpoint.
+func EncodeCheckpoint(state interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(state)
+	return buf.Bytes(), LoopError.Wrap(err)
+}
+
+// DecodeCheckpoint returns the decode function of an encoded state for
+// CheckpointObserver.Restore.
+func DecodeCheckpoint(data []byte) func(state interface{}) error {
+	return func(state interface{}) error {
+		return LoopError.Wrap(gob.NewDecoder(bytes.NewReader(data)).Decode(state))
+	}
+}
+
+// rangeIteration is the progress of a single range in an iteration.
+type rangeIteration struct {
+	index    int
+	count    int
+	keyRange keyRange
+	progress *rangeProgress
+
+	observers []*rangeObserver
+	names     map[string]bool
+
+	// cursor is the last key of the restored checkpoint and done whether
+	// the restored observers have already seen the whole range.
+	cursor     storage.Key
+	cursorDone bool
+
+	// lastKey is the last key which has been delivered to the observers.
+	lastKey        storage.Key
+	lastCheckpoint time.Time
+	writer         checkpointWriter
+}
+
+// checkpointWriter persists the checkpoints of a range in the background,
+// one at a time. It's only used by the goroutine iterating the range.
+type checkpointWriter struct {
+	// done is closed when the checkpoint being persisted has been
+	// persisted, it's nil when there's none.
+	done chan struct{}
+}
+
+// busy returns whether a checkpoint is still being persisted.
+func (writer *checkpointWriter) busy() bool {
+	if writer.done == nil {
+		return false
+	}
+	select {
+	case <-writer.done:
+		writer.done = nil
+		return false
+	default:
+		return true
+	}
+}
+
+// wait waits until the checkpoint being persisted has been persisted.
+func (writer *checkpointWriter) wait() {
+	if writer.done != nil {
+		<-writer.done
+		writer.done = nil
+	}
+}
+
+// start persists a checkpoint in the background with write.
+func (writer *checkpointWriter) start(write func()) {
+	done := make(chan struct{})
+	writer.done = done
+	go func() {
+		defer close(done)
+		write()
+	}()
+}
+
+// add adds an observer to the range. Only the first observer with a
+// checkpoint name is checkpointed.
+func (r *rangeIteration) add(ranged *rangeObserver) {
+	if ranged.checkpoint != nil {
+		name := ranged.checkpoint.CheckpointName()
+		if r.names[name] {
+			ranged.checkpoint = nil
+		} else {
+			r.names[name] = true
+		}
+	}
+	r.observers = append(r.observers, ranged)
+}
+
+// restore restores the state of the observers from the checkpoint of the range.
+func (r *rangeIteration) restore(ctx context.Context, db LoopCheckpointsDB, checkpoint RangeCheckpoint) {
+	r.cursor, r.cursorDone = checkpoint.LastKey, checkpoint.Done
+	if r.cursor == nil && !r.cursorDone {
+		return
+	}
+
+	for _, ranged := range r.observers {
+		if ranged.checkpoint == nil || ranged.observer.Finished() {
+			continue
+		}
+
+		state, err := db.GetObserverState(ctx, checkpoint.Generation, ranged.checkpoint.CheckpointName(), r.index)
+		if err != nil {
+			mon.Event("metainfo_loop_checkpoint_failed")
+			continue
+		}
+		if state == nil {
+			continue
+		}
+
+		if ranged.observer.HandleError(LoopError.Wrap(ranged.checkpoint.Restore(ctx, DecodeCheckpoint(state)))) {
+			continue
+		}
+		ranged.restored = true
+	}
+}
+
+// seen returns whether restored observers have already seen the key.
+func (r *rangeIteration) seen(key storage.Key) bool {
+	return r.cursorDone || !r.cursor.Less(key)
+}
+
+// start returns the first key, which has to be iterated for the observers.
+// It returns false, when the observers have already seen the whole range.
+func (r *rangeIteration) start(observers []*rangeObserver) (_ storage.Key, ok bool) {
+	first := r.keyRange.first
+
+	restored := true
+	for _, ranged := range observers {
+		restored = restored && ranged.restored
+	}
+	if restored {
+		if r.cursorDone {
+			return nil, false
+		}
+		if r.cursor != nil {
+			first = keyAfter(r.cursor)
+		}
+	}
+
+	if r.lastKey != nil {
+		// resuming after an error
+		if after := keyAfter(r.lastKey); first.Less(after) {
+			first = after
+		}
+	}
+	return first, true
+}
+
+// caughtUp returns whether every restored observer has seen the same keys as
+// the other observers, which is required before the range can be checkpointed.
+func (r *rangeIteration) caughtUp(observers []*rangeObserver) bool {
+	for _, ranged := range observers {
+		if ranged.restored && (r.cursorDone || r.lastKey.Less(r.cursor)) {
+			return false
+		}
+	}
+	return true
+}
+
+// keyAfter returns the smallest key, which is greater than key.
+func keyAfter(key storage.Key) storage.Key {
+	after := make(storage.Key, len(key)+1)
+	copy(after, key)
+	return after
+}
+
+// resumable returns whether the checkpoints are an unfinished iteration
+// of count ranges.
+func resumable(checkpoints []RangeCheckpoint, count int) bool {
+	if len(checkpoints) != count {
+		return false
+	}
+
+	done := true
+	for k, checkpoint := range checkpoints {
+		if checkpoint.Index != k || checkpoint.Count != count || checkpoint.Generation != checkpoints[0].Generation {
+			return false
+		}
+		done = done && checkpoint.Done
+	}
+	return !done
+}
+
+// expiredCheckpoints returns whether the last checkpoint of the iteration is
+// older than the maximum age, a maximum age of 0 never expires.
+func expiredCheckpoints(checkpoints []RangeCheckpoint, maxAge time.Duration, now time.Time) bool {
+	if maxAge <= 0 || len(checkpoints) == 0 {
+		return false
+	}
+
+	var updatedAt time.Time
+	for _, checkpoint := range checkpoints {
+		if checkpoint.UpdatedAt.After(updatedAt) {
+			updatedAt = checkpoint.UpdatedAt
+		}
+	}
+	return now.Sub(updatedAt) > maxAge
+}
+
+// rangeProgress tracks the position of a range in the keyspace.
+type rangeProgress struct {
+	start, end float64
+	// position is the float64 bits of the current position.
+	position uint64
+}
+
+func newRangeProgress(keyRange keyRange) *rangeProgress {
+	progress := &rangeProgress{
+		start: keyPosition(keyRange.first),
+		end:   1,
+	}
+	if keyRange.end != nil {
+		progress.end = keyPosition(keyRange.end)
+	}
+	progress.position = math.Float64bits(progress.start)
+	return progress
+}
+
+// Update moves the position to the key.
+func (progress *rangeProgress) Update(key storage.Key) {
+	position := math.Max(progress.start, math.Min(progress.end, keyPosition(key)))
+	atomic.StoreUint64(&progress.position, math.Float64bits(position))
+}
+
+// Finish moves the position to the end of the range.
+func (progress *rangeProgress) Finish() {
+	atomic.StoreUint64(&progress.position, math.Float64bits(progress.end))
+}
+
+// Iterated returns the part of the keyspace the range has iterated.
+func (progress *rangeProgress) Iterated() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&progress.position)) - progress.start
+}
+
+// keyPositionDigits is the number of hex characters of the project ID, which
+// are used to estimate the position of a key in the keyspace.
+const keyPositionDigits = 8
+
+// keyPosition estimates the position of a key in the keyspace as a number
+// between 0 and 1, assuming that project IDs are evenly distributed.
+func keyPosition(key storage.Key) float64 {
+	var value uint64
+	digits := 0
+	for _, c := range key {
+		if digits == keyPositionDigits {
+			break
+		}
+
+		var digit byte
+		switch {
+		case '0' <= c && c <= '9':
+			digit = c - '0'
+		case 'a' <= c && c <= 'f':
+			digit = c - 'a' + 10
+		default:
+			return float64(value) / math.Pow(16, float64(digits))
+		}
+		value = value<<4 | uint64(digit)
+		digits++
+	}
+	return float64(value) / math.Pow(16, float64(digits))
+}
+
+// loopStatus is the progress of the current iteration.
+type loopStatus struct {
+	generation int64
+	ranges     []*rangeProgress
+}
+
+// Progress returns the percentage of the estimated keyspace iterated by the
+// current iteration.
+func (loop *Loop) Progress() float64 {
+	status, ok := loop.status.Load().(*loopStatus)
+	if !ok {
+		return 0
+	}
+
+	var iterated float64
+	for _, progress := range status.ranges {
+		iterated += progress.Iterated()
+	}
+	return 100 * iterated
+}
+
+// Stats implements monkit.StatSource and reports the progress of the current iteration.
+func (loop *Loop) Stats(cb func(key monkit.SeriesKey, field string, val float64)) {
+	series := monkit.NewSeriesKey("metainfo_loop")
+
+	var generation int64
+	if status, ok := loop.status.Load().(*loopStatus); ok {
+		generation = status.generation
+	}
+
+	cb(series, "progress_percent", loop.Progress())
+	cb(series, "generation", float64(generation))
+}
@@ -52,6 +52,10 @@ var (
 	Error = errs.Class("metainfo error")
 	// ErrNodeAlreadyExists pointer already has a piece for a node err
 	ErrNodeAlreadyExists = errs.Class("metainfo error: node already exists")
+	// ErrObjectExists is returned when copying an object to a path which already has an object
+	ErrObjectExists = errs.Class("metainfo error: object already exists")
+	// ErrObjectChanged is returned when an object has been replaced while copying it
+	ErrObjectChanged = errs.Class("metainfo error: object has been replaced")
 )
 
 // APIKeys is api keys store methods used by endpoint
@@ -82,6 +86,8 @@ type Endpoint struct {
 	peerIdentities    overlay.PeerIdentities
 	projectUsage      *accounting.Service
 	projects          console.Projects
+	sharedSegments    SharedSegmentsDB
+	versions          ObjectVersionsDB
 	apiKeys           APIKeys
 	createRequests    *createRequests
 	requiredRSConfig  RSConfig
@@ -96,7 +102,7 @@ func NewEndpoint(log *zap.Logger, metainfo *Service, deletePieces *DeletePiecesS
 	orders *orders.Service, cache *overlay.Service, attributions attribution.DB,
 	partners *rewards.PartnersService, peerIdentities overlay.PeerIdentities,
 	apiKeys APIKeys, projectUsage *accounting.Service, projects console.Projects,
-	rsConfig RSConfig, satellite signing.Signer, maxCommitInterval time.Duration,
+	sharedSegments SharedSegmentsDB, versions ObjectVersionsDB, rsConfig RSConfig, satellite signing.Signer, maxCommitInterval time.Duration,
 	limiterConfig RateLimiterConfig) *Endpoint {
 	// TODO do something with too many params
 	return &Endpoint{
@@ -111,6 +117,8 @@ func NewEndpoint(log *zap.Logger, metainfo *Service, deletePieces *DeletePiecesS
 		apiKeys:           apiKeys,
 		projectUsage:      projectUsage,
 		projects:          projects,
+		sharedSegments:    sharedSegments,
+		versions:          versions,
 		createRequests:    newCreateRequests(),
 		requiredRSConfig:  rsConfig,
 		satellite:         satellite,
@@ -204,7 +212,7 @@ func (endpoint *Endpoint) CreateSegmentOld(ctx context.Context, req *pb.SegmentW
 		FreeBandwidth:  maxPieceSize,
 		FreeDisk:       maxPieceSize,
 	}
-	nodes, err := endpoint.overlay.FindStorageNodes(ctx, request)
+	nodes, err := endpoint.overlay.FindStorageNodesForUpload(ctx, request)
 	if err != nil {
 		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
 	}
@@ -433,7 +441,7 @@ func (endpoint *Endpoint) DeleteSegmentOld(ctx context.Context, req *pb.SegmentD
 		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
 	}
 
-	if pointer.Type == pb.Pointer_REMOTE && pointer.Remote != nil {
+	if pointer.Type == pb.Pointer_REMOTE && pointer.Remote != nil && !endpoint.isSharedSegment(ctx, pointer) {
 		bucketID := createBucketID(keyInfo.ProjectID, req.Bucket)
 		limits, privateKey, err := endpoint.orders.CreateDeleteOrderLimits(ctx, bucketID, pointer)
 		if err != nil {
@@ -834,6 +842,20 @@ func (endpoint *Endpoint) DeleteBucket(ctx context.Context, req *pb.BucketDelete
 		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
 	}
 
+	versioning, err := endpoint.metainfo.GetBucketVersioning(ctx, req.Name, keyInfo.ProjectID)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+	if versioning != VersioningUnversioned {
+		hasVersions, err := endpoint.metainfo.HasObjectVersions(ctx, keyInfo.ProjectID, req.Name)
+		if err != nil {
+			return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+		}
+		if hasVersions {
+			return nil, rpcstatus.Error(rpcstatus.FailedPrecondition, "bucket has object versions")
+		}
+	}
+
 	err = endpoint.metainfo.DeleteBucket(ctx, req.Name, keyInfo.ProjectID)
 	if err != nil {
 		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
@@ -1088,13 +1110,34 @@ func (endpoint *Endpoint) BeginObject(ctx context.Context, req *pb.ObjectBeginRe
 		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, "Invalid expiration time")
 	}
 
+	versioning, err := endpoint.metainfo.GetBucketVersioning(ctx, req.Bucket, keyInfo.ProjectID)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+
+	// in buckets with versioning the segments of the upload are written as a
+	// version of their own, the current object is only replaced when the
+	// upload is committed
+	var version int32
+	if versioning == VersioningUnversioned {
+		_, err = endpoint.removeCurrentObject(ctx, keyInfo.ProjectID, req.Bucket, req.EncryptedPath, versioning)
+		if err != nil && !errs2.IsRPC(err, rpcstatus.NotFound) {
+			return nil, err
+		}
+	} else {
+		version, err = endpoint.allocateUploadVersion(ctx, keyInfo.ProjectID, req.Bucket, req.EncryptedPath, versioning)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// use only satellite values for Redundancy Scheme
 	pbRS := endpoint.redundancyScheme()
 
 	streamID, err := endpoint.packStreamID(ctx, &pb.SatStreamID{
 		Bucket:         req.Bucket,
 		EncryptedPath:  req.EncryptedPath,
-		Version:        req.Version,
+		Version:        version,
 		Redundancy:     pbRS,
 		CreationDate:   time.Now(),
 		ExpirationDate: req.ExpiresAt,
@@ -1103,18 +1146,19 @@ func (endpoint *Endpoint) BeginObject(ctx context.Context, req *pb.ObjectBeginRe
 		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
 	}
 
-	err = endpoint.DeleteObjectPieces(ctx, keyInfo.ProjectID, req.Bucket, req.EncryptedPath)
-	if err != nil && !errs2.IsRPC(err, rpcstatus.NotFound) {
-		return nil, err
-	}
-
 	endpoint.log.Info("Object Upload", zap.Stringer("Project ID", keyInfo.ProjectID), zap.String("operation", "put"), zap.String("type", "object"))
 	mon.Meter("req_put_object").Mark(1)
 
+	// objects uploaded while versioning is suspended don't get a version number
+	objectVersion := version
+	if versioning != VersioningEnabled {
+		objectVersion = 0
+	}
+
 	return &pb.ObjectBeginResponse{
 		Bucket:           req.Bucket,
 		EncryptedPath:    req.EncryptedPath,
-		Version:          req.Version,
+		Version:          objectVersion,
 		StreamId:         streamID,
 		RedundancyScheme: pbRS,
 	}, nil
@@ -1157,7 +1201,7 @@ func (endpoint *Endpoint) CommitObject(ctx context.Context, req *pb.ObjectCommit
 	}
 
 	lastSegmentIndex := streamMeta.NumberOfSegments - 1
-	lastSegmentPath, err := CreatePath(ctx, keyInfo.ProjectID, lastSegmentIndex, streamID.Bucket, streamID.EncryptedPath)
+	lastSegmentPath, err := CreateVersionPath(ctx, keyInfo.ProjectID, lastSegmentIndex, streamID.Bucket, streamID.EncryptedPath, streamID.Version)
 	if err != nil {
 		return nil, rpcstatus.Errorf(rpcstatus.InvalidArgument, "unable to create segment path: %s", err.Error())
 	}
@@ -1185,7 +1229,7 @@ func (endpoint *Endpoint) CommitObject(ctx context.Context, req *pb.ObjectCommit
 	}
 
 	lastSegmentIndex = -1
-	lastSegmentPath, err = CreatePath(ctx, keyInfo.ProjectID, lastSegmentIndex, streamID.Bucket, streamID.EncryptedPath)
+	lastSegmentPath, err = CreateVersionPath(ctx, keyInfo.ProjectID, lastSegmentIndex, streamID.Bucket, streamID.EncryptedPath, streamID.Version)
 	if err != nil {
 		endpoint.log.Error("unable to create path", zap.Error(err))
 		return nil, rpcstatus.Error(rpcstatus.Internal, "unable to commit object")
@@ -1197,6 +1241,13 @@ func (endpoint *Endpoint) CommitObject(ctx context.Context, req *pb.ObjectCommit
 		return nil, rpcstatus.Error(rpcstatus.Internal, "unable to commit object")
 	}
 
+	if streamID.Version > 0 {
+		err = endpoint.replaceCurrentObject(ctx, keyInfo.ProjectID, streamID.Bucket, streamID.EncryptedPath, streamID.Version)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &pb.ObjectCommitResponse{}, nil
 }
 
@@ -1219,7 +1270,12 @@ func (endpoint *Endpoint) GetObject(ctx context.Context, req *pb.ObjectGetReques
 		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
 	}
 
-	pointer, _, err := endpoint.getPointer(ctx, keyInfo.ProjectID, -1, req.Bucket, req.EncryptedPath)
+	version, err := endpoint.storedVersion(ctx, keyInfo.ProjectID, req.Bucket, req.EncryptedPath, req.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	pointer, _, err := endpoint.getVersionPointer(ctx, keyInfo.ProjectID, -1, req.Bucket, req.EncryptedPath, version)
 	if err != nil {
 		return nil, err
 	}
@@ -1233,17 +1289,22 @@ func (endpoint *Endpoint) GetObject(ctx context.Context, req *pb.ObjectGetReques
 	streamID, err := endpoint.packStreamID(ctx, &pb.SatStreamID{
 		Bucket:        req.Bucket,
 		EncryptedPath: req.EncryptedPath,
-		Version:       req.Version,
+		Version:       version,
 		CreationDate:  time.Now(),
 	})
 	if err != nil {
 		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
 	}
 
+	objectVersion := int32(-1)
+	if req.Version > 0 {
+		objectVersion = req.Version
+	}
+
 	object := &pb.Object{
 		Bucket:            req.Bucket,
 		EncryptedPath:     req.EncryptedPath,
-		Version:           -1,
+		Version:           objectVersion,
 		StreamId:          streamID,
 		ExpiresAt:         pointer.ExpirationDate,
 		CreatedAt:         pointer.CreationDate,
@@ -1269,7 +1330,7 @@ func (endpoint *Endpoint) GetObject(ctx context.Context, req *pb.ObjectGetReques
 
 		index := int64(0)
 		for {
-			path, err := CreatePath(ctx, keyInfo.ProjectID, index, req.Bucket, req.EncryptedPath)
+			path, err := CreateVersionPath(ctx, keyInfo.ProjectID, index, req.Bucket, req.EncryptedPath, version)
 			if err != nil {
 				endpoint.log.Error("unable to get pointer path", zap.Error(err))
 				return nil, rpcstatus.Error(rpcstatus.Internal, "unable to get object")
@@ -1391,7 +1452,11 @@ func (endpoint *Endpoint) BeginDeleteObject(ctx context.Context, req *pb.ObjectB
 		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
 	}
 
-	err = endpoint.DeleteObjectPieces(ctx, keyInfo.ProjectID, satStreamID.Bucket, satStreamID.EncryptedPath)
+	if req.Version > 0 {
+		err = endpoint.deleteObjectVersion(ctx, keyInfo.ProjectID, satStreamID.Bucket, satStreamID.EncryptedPath, req.Version)
+	} else {
+		err = endpoint.deleteObject(ctx, keyInfo.ProjectID, satStreamID.Bucket, satStreamID.EncryptedPath)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -1486,7 +1551,7 @@ func (endpoint *Endpoint) BeginSegment(ctx context.Context, req *pb.SegmentBegin
 		FreeBandwidth:  maxPieceSize,
 		FreeDisk:       maxPieceSize,
 	}
-	nodes, err := endpoint.overlay.FindStorageNodes(ctx, request)
+	nodes, err := endpoint.overlay.FindStorageNodesForUpload(ctx, request)
 	if err != nil {
 		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
 	}
@@ -1598,7 +1663,7 @@ func (endpoint *Endpoint) CommitSegment(ctx context.Context, req *pb.SegmentComm
 		return nil, err
 	}
 
-	path, err := CreatePath(ctx, keyInfo.ProjectID, int64(segmentID.Index), streamID.Bucket, streamID.EncryptedPath)
+	path, err := CreateVersionPath(ctx, keyInfo.ProjectID, int64(segmentID.Index), streamID.Bucket, streamID.EncryptedPath, streamID.Version)
 	if err != nil {
 		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
 	}
@@ -1679,7 +1744,7 @@ func (endpoint *Endpoint) MakeInlineSegment(ctx context.Context, req *pb.Segment
 		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, "segment index must be greater then 0")
 	}
 
-	path, err := CreatePath(ctx, keyInfo.ProjectID, int64(req.Position.Index), streamID.Bucket, streamID.EncryptedPath)
+	path, err := CreateVersionPath(ctx, keyInfo.ProjectID, int64(req.Position.Index), streamID.Bucket, streamID.EncryptedPath, streamID.Version)
 	if err != nil {
 		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
 	}
@@ -1752,14 +1817,21 @@ func (endpoint *Endpoint) BeginDeleteSegment(ctx context.Context, req *pb.Segmen
 		return nil, rpcstatus.Error(rpcstatus.Unauthenticated, err.Error())
 	}
 
-	pointer, path, err := endpoint.getPointer(ctx, keyInfo.ProjectID, int64(req.Position.Index), streamID.Bucket, streamID.EncryptedPath)
+	pointer, path, err := endpoint.getVersionPointer(ctx, keyInfo.ProjectID, int64(req.Position.Index), streamID.Bucket, streamID.EncryptedPath, streamID.Version)
 	if err != nil {
 		return nil, err
 	}
 
+	// moved from FinishDeleteSegment to avoid inconsistency if someone will not
+	// call FinishDeleteSegment on uplink side
+	err = endpoint.metainfo.UnsynchronizedDelete(ctx, path)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+
 	var limits []*pb.AddressedOrderLimit
 	var privateKey storj.PiecePrivateKey
-	if pointer.Type == pb.Pointer_REMOTE && pointer.Remote != nil {
+	if pointer.Type == pb.Pointer_REMOTE && pointer.Remote != nil && !endpoint.isSharedSegment(ctx, pointer) {
 		bucketID := createBucketID(keyInfo.ProjectID, streamID.Bucket)
 		limits, privateKey, err = endpoint.orders.CreateDeleteOrderLimits(ctx, bucketID, pointer)
 		if err != nil {
@@ -1767,13 +1839,6 @@ func (endpoint *Endpoint) BeginDeleteSegment(ctx context.Context, req *pb.Segmen
 		}
 	}
 
-	// moved from FinishDeleteSegment to avoid inconsistency if someone will not
-	// call FinishDeleteSegment on uplink side
-	err = endpoint.metainfo.UnsynchronizedDelete(ctx, path)
-	if err != nil {
-		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
-	}
-
 	segmentID, err := endpoint.packSegmentID(ctx, &pb.SatSegmentID{
 		StreamId:            streamID,
 		OriginalOrderLimits: limits,
@@ -1841,7 +1906,7 @@ func (endpoint *Endpoint) ListSegments(ctx context.Context, req *pb.SegmentListR
 		limit = listLimit
 	}
 
-	pointer, _, err := endpoint.getPointer(ctx, keyInfo.ProjectID, lastSegment, streamID.Bucket, streamID.EncryptedPath)
+	pointer, _, err := endpoint.getVersionPointer(ctx, keyInfo.ProjectID, lastSegment, streamID.Bucket, streamID.EncryptedPath, streamID.Version)
 	if err != nil {
 		if rpcstatus.Code(err) == rpcstatus.NotFound {
 			return &pb.SegmentListResponse{}, nil
@@ -1946,7 +2011,7 @@ func (endpoint *Endpoint) listSegmentsManually(ctx context.Context, projectID uu
 	more := false
 
 	for {
-		_, _, err := endpoint.getPointer(ctx, projectID, index, streamID.Bucket, streamID.EncryptedPath)
+		_, _, err := endpoint.getVersionPointer(ctx, projectID, index, streamID.Bucket, streamID.EncryptedPath, streamID.Version)
 		if err != nil {
 			if rpcstatus.Code(err) != rpcstatus.NotFound {
 				return nil, err
@@ -2016,7 +2081,7 @@ func (endpoint *Endpoint) DownloadSegment(ctx context.Context, req *pb.SegmentDo
 		return nil, rpcstatus.Error(rpcstatus.ResourceExhausted, "Exceeded Usage Limit")
 	}
 
-	pointer, _, err := endpoint.getPointer(ctx, keyInfo.ProjectID, int64(req.CursorPosition.Index), streamID.Bucket, streamID.EncryptedPath)
+	pointer, _, err := endpoint.getVersionPointer(ctx, keyInfo.ProjectID, int64(req.CursorPosition.Index), streamID.Bucket, streamID.EncryptedPath, streamID.Version)
 	if err != nil {
 		return nil, err
 	}
@@ -2109,7 +2174,17 @@ func (endpoint *Endpoint) getPointer(
 	ctx context.Context, projectID uuid.UUID, segmentIndex int64, bucket, encryptedPath []byte,
 ) (_ *pb.Pointer, _ string, err error) {
 	defer mon.Task()(&ctx, projectID.String(), segmentIndex, bucket, encryptedPath)(&err)
-	path, err := CreatePath(ctx, projectID, segmentIndex, bucket, encryptedPath)
+	return endpoint.getVersionPointer(ctx, projectID, segmentIndex, bucket, encryptedPath, 0)
+}
+
+// getVersionPointer returns the pointer and the segment path of an object
+// version. Version 0 is the current object. It returns an error with a
+// specific RPC status.
+func (endpoint *Endpoint) getVersionPointer(
+	ctx context.Context, projectID uuid.UUID, segmentIndex int64, bucket, encryptedPath []byte, version int32,
+) (_ *pb.Pointer, _ string, err error) {
+	defer mon.Task()(&ctx, projectID.String(), segmentIndex, bucket, encryptedPath, version)(&err)
+	path, err := CreateVersionPath(ctx, projectID, segmentIndex, bucket, encryptedPath, version)
 	if err != nil {
 		return nil, "", rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
 	}
@@ -2127,13 +2202,13 @@ func (endpoint *Endpoint) getPointer(
 }
 
 // getObjectNumberOfSegments returns the number of segments of the indicated
-// object by projectID, bucket and encryptedPath.
+// object version by projectID, bucket, encryptedPath and version.
 //
 // It returns 0 if the number is unknown.
-func (endpoint *Endpoint) getObjectNumberOfSegments(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte) (_ int64, err error) {
-	defer mon.Task()(&ctx, projectID.String(), bucket, encryptedPath)(&err)
+func (endpoint *Endpoint) getObjectNumberOfSegments(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte, version int32) (_ int64, err error) {
+	defer mon.Task()(&ctx, projectID.String(), bucket, encryptedPath, version)(&err)
 
-	pointer, _, err := endpoint.getPointer(ctx, projectID, lastSegment, bucket, encryptedPath)
+	pointer, _, err := endpoint.getVersionPointer(ctx, projectID, lastSegment, bucket, encryptedPath, version)
 	if err != nil {
 		return 0, err
 	}
@@ -2264,6 +2339,15 @@ func (endpoint *Endpoint) DeleteObjectPieces(
 	ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte,
 ) (err error) {
 	defer mon.Task()(&ctx, projectID.String(), bucket, encryptedPath)(&err)
+	return endpoint.deleteObjectVersionPieces(ctx, projectID, bucket, encryptedPath, 0)
+}
+
+// deleteObjectVersionPieces deletes all the pieces of the storage nodes that
+// belongs to the specified object version. Version 0 is the current object.
+func (endpoint *Endpoint) deleteObjectVersionPieces(
+	ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte, version int32,
+) (err error) {
+	defer mon.Task()(&ctx, projectID.String(), bucket, encryptedPath, version)(&err)
 
 	// We should ignore client cancelling and always try to delete segments.
 	ctx = context2.WithoutCancellation(ctx)
@@ -2273,7 +2357,7 @@ func (endpoint *Endpoint) DeleteObjectPieces(
 		prevLastSegmentIndex int64
 	)
 	{
-		numOfSegments, err := endpoint.getObjectNumberOfSegments(ctx, projectID, bucket, encryptedPath)
+		numOfSegments, err := endpoint.getObjectNumberOfSegments(ctx, projectID, bucket, encryptedPath, version)
 		if err != nil {
 			if !errs2.IsRPC(err, rpcstatus.NotFound) {
 				return err
@@ -2285,7 +2369,7 @@ func (endpoint *Endpoint) DeleteObjectPieces(
 			{
 				var err error
 				prevLastSegmentIndex, err = endpoint.findIndexPreviousLastSegmentWhenNotKnowingNumSegments(
-					ctx, projectID, bucket, encryptedPath,
+					ctx, projectID, bucket, encryptedPath, version,
 				)
 				if err != nil {
 					endpoint.log.Error("unexpected error while finding last segment index previous to the last segment",
@@ -2317,7 +2401,7 @@ func (endpoint *Endpoint) DeleteObjectPieces(
 
 	if !lastSegmentNotFound {
 		// first delete the last segment
-		pointer, err := endpoint.deletePointer(ctx, projectID, lastSegment, bucket, encryptedPath)
+		pointer, err := endpoint.deletePointer(ctx, projectID, lastSegment, bucket, encryptedPath, version)
 		if err != nil {
 			if storj.ErrObjectNotFound.Has(err) {
 				endpoint.log.Warn(
@@ -2338,7 +2422,7 @@ func (endpoint *Endpoint) DeleteObjectPieces(
 			}
 		}
 
-		if err == nil && pointer.Type == pb.Pointer_REMOTE {
+		if err == nil && pointer.Type == pb.Pointer_REMOTE && !endpoint.isSharedSegment(ctx, pointer) {
 			rootPieceID := pointer.GetRemote().RootPieceId
 			for _, piece := range pointer.GetRemote().GetRemotePieces() {
 				pieceID := rootPieceID.Derive(piece.NodeId, piece.PieceNum)
@@ -2355,7 +2439,7 @@ func (endpoint *Endpoint) DeleteObjectPieces(
 	}
 
 	for segmentIdx := prevLastSegmentIndex; segmentIdx >= 0; segmentIdx-- {
-		pointer, err := endpoint.deletePointer(ctx, projectID, segmentIdx, bucket, encryptedPath)
+		pointer, err := endpoint.deletePointer(ctx, projectID, segmentIdx, bucket, encryptedPath, version)
 		if err != nil {
 			segment := "s" + strconv.FormatInt(segmentIdx, 10)
 			if storj.ErrObjectNotFound.Has(err) {
@@ -2382,7 +2466,7 @@ func (endpoint *Endpoint) DeleteObjectPieces(
 			continue
 		}
 
-		if pointer.Type != pb.Pointer_REMOTE {
+		if pointer.Type != pb.Pointer_REMOTE || endpoint.isSharedSegment(ctx, pointer) {
 			continue
 		}
 
@@ -2432,11 +2516,11 @@ func (endpoint *Endpoint) DeleteObjectPieces(
 // If the pointer isn't found when getting or deleting it, it returns
 // storj.ErrObjectNotFound error.
 func (endpoint *Endpoint) deletePointer(
-	ctx context.Context, projectID uuid.UUID, segmentIndex int64, bucket, encryptedPath []byte,
+	ctx context.Context, projectID uuid.UUID, segmentIndex int64, bucket, encryptedPath []byte, version int32,
 ) (_ *pb.Pointer, err error) {
-	defer mon.Task()(&ctx, projectID, segmentIndex, bucket, encryptedPath)(&err)
+	defer mon.Task()(&ctx, projectID, segmentIndex, bucket, encryptedPath, version)(&err)
 
-	pointer, path, err := endpoint.getPointer(ctx, projectID, segmentIndex, bucket, encryptedPath)
+	pointer, path, err := endpoint.getVersionPointer(ctx, projectID, segmentIndex, bucket, encryptedPath, version)
 	if err != nil {
 		if errs2.IsRPC(err, rpcstatus.NotFound) {
 			return nil, storj.ErrObjectNotFound.New("%s", err.Error())
@@ -2459,13 +2543,13 @@ func (endpoint *Endpoint) deletePointer(
 // It returns -1 index if none is found and error if there is some error getting
 // the segments' pointers.
 func (endpoint *Endpoint) findIndexPreviousLastSegmentWhenNotKnowingNumSegments(
-	ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte,
+	ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte, version int32,
 ) (index int64, err error) {
-	defer mon.Task()(&ctx, projectID, bucket, encryptedPath)(&err)
+	defer mon.Task()(&ctx, projectID, bucket, encryptedPath, version)(&err)
 
 	lastIdxFound := int64(-1)
 	for {
-		_, _, err := endpoint.getPointer(ctx, projectID, lastIdxFound+1, bucket, encryptedPath)
+		_, _, err := endpoint.getVersionPointer(ctx, projectID, lastIdxFound+1, bucket, encryptedPath, version)
 		if err != nil {
 			if errs2.IsRPC(err, rpcstatus.NotFound) {
 				break
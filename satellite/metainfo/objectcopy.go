@@ -0,0 +1,272 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfo
+
+import (
+	"context"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"go.uber.org/zap"
+
+	"storj.io/common/pb"
+	"storj.io/common/rpc/rpcstatus"
+	"storj.io/common/storj"
+	"storj.io/storj/pkg/macaroon"
+	"storj.io/storj/pkg/metainfopb"
+)
+
+// BeginCopyObject returns the encrypted keys of all segments of an object, so
+// the client can encrypt them for the new path.
+func (endpoint *Endpoint) BeginCopyObject(ctx context.Context, req *metainfopb.BeginCopyObjectRequest) (resp *metainfopb.BeginCopyObjectResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	keyInfo, err := endpoint.validateAuth(ctx, &pb.RequestHeader{ApiKey: req.ApiKey}, macaroon.Action{
+		Op:            macaroon.ActionRead,
+		Bucket:        req.Bucket,
+		EncryptedPath: req.EncryptedPath,
+		Time:          time.Now(),
+	})
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.Unauthenticated, err.Error())
+	}
+
+	err = endpoint.validateBucket(ctx, req.Bucket)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
+	}
+
+	segments, err := endpoint.metainfo.GetObjectSegments(ctx, keyInfo.ProjectID, req.Bucket, req.EncryptedPath)
+	if err != nil {
+		return nil, endpoint.convertCopyError(err)
+	}
+
+	resp = &metainfopb.BeginCopyObjectResponse{
+		CreatedAt:         segments.LastSegment.CreationDate,
+		EncryptedMetadata: segments.LastSegment.Metadata,
+	}
+	for index, pointer := range segments.Segments {
+		segmentMeta := pb.SegmentMeta{}
+		err = proto.Unmarshal(pointer.Metadata, &segmentMeta)
+		if err != nil {
+			return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+		}
+
+		resp.SegmentKeys = append(resp.SegmentKeys, &metainfopb.SegmentKey{
+			Index:             int64(index),
+			EncryptedKey:      segmentMeta.EncryptedKey,
+			EncryptedKeyNonce: segmentMeta.KeyNonce,
+		})
+	}
+
+	return resp, nil
+}
+
+// FinishCopyObject copies or moves an object to the new path with the segment
+// keys encrypted for the new path. No piece data is transferred, the pieces of
+// a copied object are shared by both objects.
+func (endpoint *Endpoint) FinishCopyObject(ctx context.Context, req *metainfopb.FinishCopyObjectRequest) (resp *metainfopb.FinishCopyObjectResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	header := &pb.RequestHeader{ApiKey: req.ApiKey}
+	now := time.Now()
+
+	keyInfo, err := endpoint.validateAuth(ctx, header, macaroon.Action{
+		Op:            macaroon.ActionRead,
+		Bucket:        req.Bucket,
+		EncryptedPath: req.EncryptedPath,
+		Time:          now,
+	})
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.Unauthenticated, err.Error())
+	}
+
+	_, err = endpoint.validateAuth(ctx, header, macaroon.Action{
+		Op:            macaroon.ActionWrite,
+		Bucket:        req.NewBucket,
+		EncryptedPath: req.NewEncryptedPath,
+		Time:          now,
+	})
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.Unauthenticated, err.Error())
+	}
+
+	if req.Move {
+		_, err = endpoint.validateAuth(ctx, header, macaroon.Action{
+			Op:            macaroon.ActionDelete,
+			Bucket:        req.Bucket,
+			EncryptedPath: req.EncryptedPath,
+			Time:          now,
+		})
+		if err != nil {
+			return nil, rpcstatus.Error(rpcstatus.Unauthenticated, err.Error())
+		}
+	}
+
+	for _, bucket := range [][]byte{req.Bucket, req.NewBucket} {
+		err = endpoint.validateBucket(ctx, bucket)
+		if err != nil {
+			return nil, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
+		}
+	}
+
+	if len(req.NewEncryptedPath) == 0 {
+		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, "new encrypted path is missing")
+	}
+
+	_, err = endpoint.metainfo.GetBucket(ctx, req.NewBucket, keyInfo.ProjectID)
+	if err != nil {
+		if storj.ErrBucketNotFound.Has(err) {
+			return nil, rpcstatus.Error(rpcstatus.NotFound, err.Error())
+		}
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+
+	streamMeta := pb.StreamMeta{}
+	err = proto.Unmarshal(req.NewEncryptedMetadata, &streamMeta)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, "invalid metadata structure")
+	}
+
+	segmentMetadata := make([][]byte, len(req.NewSegmentKeys))
+	for index, key := range req.NewSegmentKeys {
+		if key.Index != int64(index) {
+			return nil, rpcstatus.Error(rpcstatus.InvalidArgument, "segment keys are not ordered by index")
+		}
+
+		segmentMetadata[index], err = proto.Marshal(&pb.SegmentMeta{
+			EncryptedKey: key.EncryptedKey,
+			KeyNonce:     key.EncryptedKeyNonce,
+		})
+		if err != nil {
+			return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+		}
+	}
+
+	segments, err := endpoint.metainfo.GetObjectSegments(ctx, keyInfo.ProjectID, req.Bucket, req.EncryptedPath)
+	if err != nil {
+		return nil, endpoint.convertCopyError(err)
+	}
+	if !segments.LastSegment.CreationDate.Equal(req.CreatedAt) {
+		return nil, rpcstatus.Error(rpcstatus.Aborted, "object has been replaced")
+	}
+	if len(segmentMetadata) != len(segments.Segments) {
+		return nil, rpcstatus.Errorf(rpcstatus.InvalidArgument, "expected keys for %d segments, got %d", len(segments.Segments), len(segmentMetadata))
+	}
+
+	objectCopy := ObjectCopy{
+		ProjectID:           keyInfo.ProjectID,
+		Bucket:              req.Bucket,
+		EncryptedPath:       req.EncryptedPath,
+		CreationDate:        req.CreatedAt,
+		NewBucket:           req.NewBucket,
+		NewEncryptedPath:    req.NewEncryptedPath,
+		LastSegmentMetadata: req.NewEncryptedMetadata,
+		SegmentMetadata:     segmentMetadata,
+	}
+
+	if req.Move {
+		err = endpoint.metainfo.MoveObject(ctx, objectCopy)
+		if err != nil {
+			return nil, endpoint.convertCopyError(err)
+		}
+
+		endpoint.log.Info("Object Move", zap.Stringer("Project ID", keyInfo.ProjectID), zap.String("operation", "move"), zap.String("type", "object"))
+		mon.Meter("req_move_object").Mark(1)
+
+		return &metainfopb.FinishCopyObjectResponse{}, nil
+	}
+
+	exceeded, limit, err := endpoint.projectUsage.ExceedsStorageUsage(ctx, keyInfo.ProjectID)
+	if err != nil {
+		endpoint.log.Error("retrieving project storage totals", zap.Error(err))
+	}
+	if exceeded {
+		endpoint.log.Sugar().Errorf("monthly project limits are %s of storage and bandwidth usage. This limit has been exceeded for storage for projectID %s",
+			limit, keyInfo.ProjectID,
+		)
+		return nil, rpcstatus.Error(rpcstatus.ResourceExhausted, "Exceeded Usage Limit")
+	}
+
+	// the references are added before the copy exists, so deleting either
+	// object never deletes pieces which are still used by the other one
+	rootPieceIDs := segments.RootPieceIDs()
+	err = endpoint.sharedSegments.AddReferences(ctx, rootPieceIDs)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+
+	// when the copy fails the references are kept, so the pieces are deleted
+	// by garbage collection instead of when the last object is deleted
+	err = endpoint.metainfo.CopyObject(ctx, objectCopy)
+	if err != nil {
+		return nil, endpoint.convertCopyError(err)
+	}
+
+	var copiedSize int64
+	for _, pointer := range append([]*pb.Pointer{segments.LastSegment}, segments.Segments...) {
+		segmentSize, _ := calculateSpaceUsed(pointer)
+		copiedSize += segmentSize
+	}
+	if err := endpoint.projectUsage.AddProjectStorageUsage(ctx, keyInfo.ProjectID, copiedSize); err != nil {
+		endpoint.log.Error("Could not track new storage usage by project",
+			zap.Stringer("Project ID", keyInfo.ProjectID),
+			zap.Error(err),
+		)
+	}
+
+	endpoint.log.Info("Object Copy", zap.Stringer("Project ID", keyInfo.ProjectID), zap.String("operation", "copy"), zap.String("type", "object"))
+	mon.Meter("req_copy_object").Mark(1)
+
+	return &metainfopb.FinishCopyObjectResponse{}, nil
+}
+
+// convertCopyError converts an error of copying or moving an object to an error with an RPC status.
+func (endpoint *Endpoint) convertCopyError(err error) error {
+	switch {
+	case storj.ErrObjectNotFound.Has(err):
+		return rpcstatus.Error(rpcstatus.NotFound, err.Error())
+	case ErrObjectExists.Has(err):
+		return rpcstatus.Error(rpcstatus.AlreadyExists, err.Error())
+	case ErrObjectChanged.Has(err):
+		return rpcstatus.Error(rpcstatus.Aborted, err.Error())
+	default:
+		endpoint.log.Error("unable to copy object", zap.Error(err))
+		return rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+}
+
+// removeReferenceAttempts is how often removing a reference to a shared
+// segment is tried before its pieces are left to garbage collection.
+const removeReferenceAttempts = 3
+
+// IsSharedSegment removes a reference to the pieces of a deleted remote
+// pointer and reports whether a copy of the object still uses them. A failed
+// removal doesn't remove the reference, so it's retried. When it keeps
+// failing the pieces are reported as shared and left to garbage collection,
+// since deleting pieces which a copy still uses would lose its data.
+func IsSharedSegment(ctx context.Context, log *zap.Logger, sharedSegments SharedSegmentsDB, pointer *pb.Pointer) bool {
+	rootPieceID := pointer.GetRemote().RootPieceId
+
+	var err error
+	for attempt := 0; attempt < removeReferenceAttempts; attempt++ {
+		var shared bool
+		shared, err = sharedSegments.RemoveReference(ctx, rootPieceID)
+		if err == nil {
+			return shared
+		}
+	}
+
+	mon.Meter("shared_segment_reference_errors").Mark(1)
+	log.Warn("unable to remove shared segment reference, leaving the pieces to garbage collection",
+		zap.Stringer("Root Piece ID", rootPieceID),
+		zap.Error(err),
+	)
+	return true
+}
+
+// isSharedSegment calls IsSharedSegment with the shared segments of the endpoint.
+func (endpoint *Endpoint) isSharedSegment(ctx context.Context, pointer *pb.Pointer) bool {
+	return IsSharedSegment(ctx, endpoint.log, endpoint.sharedSegments, pointer)
+}
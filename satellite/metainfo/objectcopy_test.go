@@ -0,0 +1,141 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/common/memory"
+	"storj.io/common/pb"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/cmd/uplink/cmd"
+	libuplink "storj.io/storj/lib/uplink"
+	"storj.io/storj/private/testplanet"
+	"storj.io/storj/satellite/metainfo"
+)
+
+func TestCopyObject(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: testplanet.ReconfigureRS(2, 2, 4, 4),
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		uplnk := planet.Uplinks[0]
+		satellite := planet.Satellites[0]
+
+		config := cmd.Config{
+			Client: cmd.ClientConfig{
+				SegmentSize: 10 * memory.KiB,
+			},
+		}
+
+		var testCases = []struct {
+			caseDescription string
+			objData         []byte
+		}{
+			{caseDescription: "one inline segment", objData: testrand.Bytes(3 * memory.KiB)},
+			{caseDescription: "one remote segment", objData: testrand.Bytes(10 * memory.KiB)},
+			{caseDescription: "several segments (remote + inline)", objData: testrand.Bytes(33 * memory.KiB)},
+		}
+
+		for _, tc := range testCases {
+			tc := tc
+			t.Run(tc.caseDescription, func(t *testing.T) {
+				err := uplnk.UploadWithClientConfig(ctx, satellite, config, "src-bucket", "object", tc.objData)
+				require.NoError(t, err)
+
+				project, srcBucket, err := uplnk.GetProjectAndBucket(ctx, satellite, "src-bucket", config)
+				require.NoError(t, err)
+				defer ctx.Check(project.Close)
+				defer ctx.Check(srcBucket.Close)
+
+				dstProject, dstBucket, err := uplnk.GetProjectAndBucket(ctx, satellite, "dst-bucket", config)
+				require.NoError(t, err)
+				defer ctx.Check(dstProject.Close)
+				defer ctx.Check(dstBucket.Close)
+
+				err = srcBucket.CopyObject(ctx, "object", srcBucket, "copy")
+				require.NoError(t, err)
+				err = srcBucket.CopyObject(ctx, "object", dstBucket, "copy")
+				require.NoError(t, err)
+
+				// copying over an existing object isn't allowed
+				err = srcBucket.CopyObject(ctx, "object", srcBucket, "copy")
+				require.True(t, libuplink.ErrObjectExists.Has(err), err)
+
+				// the pieces are shared, so deleting the original keeps the copies
+				err = uplnk.Delete(ctx, satellite, "src-bucket", "object")
+				require.NoError(t, err)
+
+				for _, bucketName := range []string{"src-bucket", "dst-bucket"} {
+					data, err := uplnk.Download(ctx, satellite, bucketName, "copy")
+					require.NoError(t, err)
+					require.Equal(t, tc.objData, data)
+				}
+
+				err = srcBucket.MoveObject(ctx, "copy", dstBucket, "moved")
+				require.NoError(t, err)
+
+				_, err = uplnk.Download(ctx, satellite, "src-bucket", "copy")
+				require.True(t, storj.ErrObjectNotFound.Has(err), err)
+
+				data, err := uplnk.Download(ctx, satellite, "dst-bucket", "moved")
+				require.NoError(t, err)
+				require.Equal(t, tc.objData, data)
+
+				for _, path := range []string{"copy", "moved"} {
+					err = uplnk.Delete(ctx, satellite, "dst-bucket", path)
+					require.NoError(t, err)
+				}
+
+				// moving a missing object fails
+				err = srcBucket.MoveObject(ctx, "object", dstBucket, "moved")
+				require.True(t, storj.ErrObjectNotFound.Has(err), err)
+			})
+		}
+	})
+}
+
+// flakySharedSegments fails to remove references until failures is zero.
+type flakySharedSegments struct {
+	metainfo.SharedSegmentsDB
+	failures int
+	shared   bool
+}
+
+func (db *flakySharedSegments) RemoveReference(ctx context.Context, rootPieceID storj.PieceID) (bool, error) {
+	if db.failures > 0 {
+		db.failures--
+		return false, errs.New("removing failed")
+	}
+	return db.shared, nil
+}
+
+func TestIsSharedSegment(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	log := zaptest.NewLogger(t)
+	pointer := &pb.Pointer{Type: pb.Pointer_REMOTE, Remote: &pb.RemoteSegment{RootPieceId: testrand.PieceID()}}
+
+	// failed removals are retried
+	db := &flakySharedSegments{failures: 2}
+	require.False(t, metainfo.IsSharedSegment(ctx, log, db, pointer))
+	require.Zero(t, db.failures)
+
+	db = &flakySharedSegments{shared: true}
+	require.True(t, metainfo.IsSharedSegment(ctx, log, db, pointer))
+
+	// the pieces aren't deleted when the reference can't be removed
+	db = &flakySharedSegments{failures: 10}
+	require.True(t, metainfo.IsSharedSegment(ctx, log, db, pointer))
+}
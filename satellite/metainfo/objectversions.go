@@ -0,0 +1,469 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfo
+
+import (
+	"context"
+	"time"
+
+	"github.com/skyrings/skyring-common/tools/uuid"
+	"go.uber.org/zap"
+
+	"storj.io/common/errs2"
+	"storj.io/common/pb"
+	"storj.io/common/rpc/rpcstatus"
+	"storj.io/common/storj"
+	"storj.io/storj/pkg/macaroon"
+	"storj.io/storj/pkg/metainfopb"
+)
+
+// SetBucketVersioning enables or suspends the versioning of a bucket.
+func (endpoint *Endpoint) SetBucketVersioning(ctx context.Context, req *metainfopb.SetBucketVersioningRequest) (resp *metainfopb.SetBucketVersioningResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	keyInfo, err := endpoint.validateAuth(ctx, &pb.RequestHeader{ApiKey: req.ApiKey}, macaroon.Action{
+		Op:     macaroon.ActionWrite,
+		Bucket: req.Bucket,
+		Time:   time.Now(),
+	})
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.Unauthenticated, err.Error())
+	}
+
+	err = endpoint.validateBucket(ctx, req.Bucket)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
+	}
+
+	versioning := Versioning(req.Versioning)
+	switch versioning {
+	case VersioningUnversioned, VersioningEnabled, VersioningSuspended:
+	default:
+		return nil, rpcstatus.Errorf(rpcstatus.InvalidArgument, "invalid versioning %d", req.Versioning)
+	}
+
+	current, err := endpoint.metainfo.GetBucketVersioning(ctx, req.Bucket, keyInfo.ProjectID)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+	if versioning == VersioningUnversioned && current != VersioningUnversioned {
+		return nil, rpcstatus.Error(rpcstatus.FailedPrecondition, "versioning can only be suspended once it has been enabled")
+	}
+
+	if versioning != VersioningUnversioned {
+		rules, err := endpoint.metainfo.GetBucketLifecycle(ctx, req.Bucket, keyInfo.ProjectID)
+		if err != nil {
+			return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+		}
+		if hasExpiration(rules) {
+			return nil, rpcstatus.Error(rpcstatus.FailedPrecondition, errVersionedExpiration)
+		}
+	}
+
+	err = endpoint.metainfo.SetBucketVersioning(ctx, req.Bucket, keyInfo.ProjectID, versioning)
+	if err != nil {
+		if storj.ErrBucketNotFound.Has(err) {
+			return nil, rpcstatus.Error(rpcstatus.NotFound, err.Error())
+		}
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+
+	endpoint.log.Info("Bucket Versioning", zap.Stringer("Project ID", keyInfo.ProjectID), zap.Stringer("versioning", versioning))
+	mon.Meter("req_set_bucket_versioning").Mark(1)
+
+	return &metainfopb.SetBucketVersioningResponse{}, nil
+}
+
+// GetBucketVersioning returns the versioning state of a bucket.
+func (endpoint *Endpoint) GetBucketVersioning(ctx context.Context, req *metainfopb.GetBucketVersioningRequest) (resp *metainfopb.GetBucketVersioningResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	keyInfo, err := endpoint.validateAuth(ctx, &pb.RequestHeader{ApiKey: req.ApiKey}, macaroon.Action{
+		Op:     macaroon.ActionRead,
+		Bucket: req.Bucket,
+		Time:   time.Now(),
+	})
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.Unauthenticated, err.Error())
+	}
+
+	err = endpoint.validateBucket(ctx, req.Bucket)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
+	}
+
+	_, err = endpoint.metainfo.GetBucket(ctx, req.Bucket, keyInfo.ProjectID)
+	if err != nil {
+		if storj.ErrBucketNotFound.Has(err) {
+			return nil, rpcstatus.Error(rpcstatus.NotFound, err.Error())
+		}
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+
+	versioning, err := endpoint.metainfo.GetBucketVersioning(ctx, req.Bucket, keyInfo.ProjectID)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+
+	return &metainfopb.GetBucketVersioningResponse{
+		Versioning: metainfopb.Versioning(versioning),
+	}, nil
+}
+
+// ListObjectVersions lists the current object, the non-current versions and
+// the delete markers of an object, newest first.
+func (endpoint *Endpoint) ListObjectVersions(ctx context.Context, req *metainfopb.ListObjectVersionsRequest) (resp *metainfopb.ListObjectVersionsResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	keyInfo, err := endpoint.validateAuth(ctx, &pb.RequestHeader{ApiKey: req.ApiKey}, macaroon.Action{
+		Op:            macaroon.ActionList,
+		Bucket:        req.Bucket,
+		EncryptedPath: req.EncryptedPath,
+		Time:          time.Now(),
+	})
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.Unauthenticated, err.Error())
+	}
+
+	err = endpoint.validateBucket(ctx, req.Bucket)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
+	}
+
+	if len(req.EncryptedPath) == 0 {
+		return nil, rpcstatus.Error(rpcstatus.InvalidArgument, "encrypted path is missing")
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 || limit > listLimit {
+		limit = listLimit
+	}
+
+	versions, err := endpoint.metainfo.ListObjectVersions(ctx, keyInfo.ProjectID, req.Bucket, req.EncryptedPath)
+	if err != nil {
+		return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+
+	resp = &metainfopb.ListObjectVersionsResponse{}
+
+	currentPointer, _, err := endpoint.getPointer(ctx, keyInfo.ProjectID, lastSegment, req.Bucket, req.EncryptedPath)
+	if err != nil && !errs2.IsRPC(err, rpcstatus.NotFound) {
+		return nil, err
+	}
+	hasCurrent := currentPointer != nil
+
+	// the current object is newer than all stored versions, so it's only
+	// listed on the first page
+	if hasCurrent && req.VersionCursor <= 0 {
+		current, err := endpoint.versions.GetCurrent(ctx, keyInfo.ProjectID, req.Bucket, req.EncryptedPath)
+		if err != nil {
+			return nil, rpcstatus.Error(rpcstatus.Internal, err.Error())
+		}
+
+		resp.Versions = append(resp.Versions, &metainfopb.ObjectVersion{
+			Version:           current,
+			IsLatest:          true,
+			CreatedAt:         currentPointer.CreationDate,
+			ExpiresAt:         currentPointer.ExpirationDate,
+			EncryptedMetadata: currentPointer.Metadata,
+		})
+	}
+
+	for index, version := range versions {
+		if req.VersionCursor > 0 && version.Version >= req.VersionCursor {
+			continue
+		}
+		if len(resp.Versions) >= limit {
+			resp.More = true
+			break
+		}
+
+		resp.Versions = append(resp.Versions, &metainfopb.ObjectVersion{
+			Version:           version.Version,
+			IsLatest:          !hasCurrent && index == 0,
+			IsDeleteMarker:    version.IsDeleteMarker,
+			CreatedAt:         version.CreationDate,
+			ExpiresAt:         version.ExpirationDate,
+			EncryptedMetadata: version.Metadata,
+		})
+	}
+
+	endpoint.log.Info("Object Versions List", zap.Stringer("Project ID", keyInfo.ProjectID), zap.String("operation", "list"), zap.String("type", "object"))
+	mon.Meter("req_list_object_versions").Mark(1)
+
+	return resp, nil
+}
+
+// storedVersion returns the version under which the pointers of the requested
+// version are stored. It's 0 when the current object is requested.
+func (endpoint *Endpoint) storedVersion(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte, version int32) (_ int32, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if version <= 0 {
+		return 0, nil
+	}
+
+	current, err := endpoint.versions.GetCurrent(ctx, projectID, bucket, encryptedPath)
+	if err != nil {
+		return 0, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+	if version == current {
+		return 0, nil
+	}
+	return version, nil
+}
+
+// removeCurrentObject removes the current object before it's overwritten or
+// deleted. The object is kept as a non-current version when versioning is
+// enabled, or when it's suspended and the object already has a version
+// number. Otherwise its pieces are deleted. It returns the version number the
+// object is kept as, which is 0 when it's deleted.
+func (endpoint *Endpoint) removeCurrentObject(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte, versioning Versioning) (kept int32, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if versioning == VersioningUnversioned {
+		return 0, endpoint.DeleteObjectPieces(ctx, projectID, bucket, encryptedPath)
+	}
+
+	current, err := endpoint.versions.GetCurrent(ctx, projectID, bucket, encryptedPath)
+	if err != nil {
+		return 0, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+
+	if versioning == VersioningSuspended && current == 0 {
+		return 0, endpoint.DeleteObjectPieces(ctx, projectID, bucket, encryptedPath)
+	}
+
+	_, _, err = endpoint.getPointer(ctx, projectID, lastSegment, bucket, encryptedPath)
+	if err != nil {
+		if errs2.IsRPC(err, rpcstatus.NotFound) {
+			// there may be segments of an upload which hasn't been committed
+			return 0, endpoint.DeleteObjectPieces(ctx, projectID, bucket, encryptedPath)
+		}
+		return 0, err
+	}
+
+	if current == 0 {
+		// the object has been uploaded before versioning was enabled
+		current, err = endpoint.versions.Allocate(ctx, projectID, bucket, encryptedPath)
+		if err != nil {
+			return 0, rpcstatus.Error(rpcstatus.Internal, err.Error())
+		}
+	}
+
+	err = endpoint.metainfo.MoveObjectVersion(ctx, projectID, bucket, encryptedPath, 0, current)
+	if err != nil {
+		if storj.ErrObjectNotFound.Has(err) {
+			return 0, rpcstatus.Error(rpcstatus.NotFound, err.Error())
+		}
+		endpoint.log.Error("unable to keep object version", zap.Error(err))
+		return 0, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+
+	err = endpoint.versions.SetCurrent(ctx, projectID, bucket, encryptedPath, 0)
+	if err != nil {
+		return 0, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+	return current, nil
+}
+
+// allocateUploadVersion allocates the version under which the segments of an
+// upload are written until it's committed. When versioning is enabled, a
+// current object without a version number gets one first, so the upload gets
+// a higher version number than the object it replaces.
+func (endpoint *Endpoint) allocateUploadVersion(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte, versioning Versioning) (_ int32, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if versioning == VersioningEnabled {
+		current, err := endpoint.versions.GetCurrent(ctx, projectID, bucket, encryptedPath)
+		if err != nil {
+			return 0, rpcstatus.Error(rpcstatus.Internal, err.Error())
+		}
+
+		if current == 0 {
+			_, _, err = endpoint.getPointer(ctx, projectID, lastSegment, bucket, encryptedPath)
+			switch {
+			case err == nil:
+				current, err = endpoint.versions.Allocate(ctx, projectID, bucket, encryptedPath)
+				if err != nil {
+					return 0, rpcstatus.Error(rpcstatus.Internal, err.Error())
+				}
+				err = endpoint.versions.SetCurrent(ctx, projectID, bucket, encryptedPath, current)
+				if err != nil {
+					return 0, rpcstatus.Error(rpcstatus.Internal, err.Error())
+				}
+			case !errs2.IsRPC(err, rpcstatus.NotFound):
+				return 0, err
+			}
+		}
+	}
+
+	version, err := endpoint.versions.Allocate(ctx, projectID, bucket, encryptedPath)
+	if err != nil {
+		return 0, rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+	return version, nil
+}
+
+// replaceCurrentObject makes a committed upload, whose segments have been
+// written as the given version, the current object. The current object is
+// removed only now, so an upload which is never committed leaves it in place.
+func (endpoint *Endpoint) replaceCurrentObject(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte, version int32) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	versioning, err := endpoint.metainfo.GetBucketVersioning(ctx, bucket, projectID)
+	if err != nil {
+		return rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+
+	kept, err := endpoint.removeCurrentObject(ctx, projectID, bucket, encryptedPath, versioning)
+	if err != nil && !errs2.IsRPC(err, rpcstatus.NotFound) {
+		return err
+	}
+
+	err = endpoint.metainfo.MoveObjectVersion(ctx, projectID, bucket, encryptedPath, version, 0)
+	if err != nil {
+		endpoint.log.Error("unable to make object version current", zap.Error(err))
+		// the object must not be left without a current version, so the
+		// newest version becomes the current object instead
+		if restoreErr := endpoint.restoreLatestVersion(ctx, projectID, bucket, encryptedPath); restoreErr != nil {
+			endpoint.log.Error("unable to restore object version", zap.Error(restoreErr))
+		}
+		return rpcstatus.Error(rpcstatus.Internal, "unable to commit object")
+	}
+
+	switch {
+	case versioning != VersioningEnabled:
+		// objects uploaded while versioning is suspended don't get a version number
+		version = 0
+	case kept >= version:
+		// an upload which has been started later has been committed first
+		version, err = endpoint.versions.Allocate(ctx, projectID, bucket, encryptedPath)
+		if err != nil {
+			return rpcstatus.Error(rpcstatus.Internal, err.Error())
+		}
+	}
+
+	err = endpoint.versions.SetCurrent(ctx, projectID, bucket, encryptedPath, version)
+	if err != nil {
+		endpoint.log.Error("unable to set current version", zap.Error(err))
+		return rpcstatus.Error(rpcstatus.Internal, "unable to commit object")
+	}
+	return nil
+}
+
+// deleteObject deletes the current object. In a bucket with versioning the
+// object is kept as a non-current version and a delete marker is added.
+func (endpoint *Endpoint) deleteObject(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	versioning, err := endpoint.metainfo.GetBucketVersioning(ctx, bucket, projectID)
+	if err != nil {
+		return rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+
+	_, err = endpoint.removeCurrentObject(ctx, projectID, bucket, encryptedPath, versioning)
+	if versioning == VersioningUnversioned || (err != nil && !errs2.IsRPC(err, rpcstatus.NotFound)) {
+		return err
+	}
+
+	version, err := endpoint.versions.Allocate(ctx, projectID, bucket, encryptedPath)
+	if err != nil {
+		return rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+
+	path, err := CreateDeleteMarkerPath(ctx, projectID, bucket, encryptedPath, version)
+	if err != nil {
+		return rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
+	}
+
+	err = endpoint.metainfo.Put(ctx, path, &pb.Pointer{Type: pb.Pointer_INLINE})
+	if err != nil {
+		endpoint.log.Error("unable to put delete marker", zap.Error(err))
+		return rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+	return nil
+}
+
+// deleteObjectVersion permanently deletes a version or a delete marker of an
+// object. When the latest version is deleted, the newest remaining version
+// becomes the current object.
+func (endpoint *Endpoint) deleteObjectVersion(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte, version int32) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	current, err := endpoint.versions.GetCurrent(ctx, projectID, bucket, encryptedPath)
+	if err != nil {
+		return rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+
+	if version == current {
+		err = endpoint.DeleteObjectPieces(ctx, projectID, bucket, encryptedPath)
+		if err != nil {
+			return err
+		}
+
+		err = endpoint.versions.SetCurrent(ctx, projectID, bucket, encryptedPath, 0)
+		if err != nil {
+			return rpcstatus.Error(rpcstatus.Internal, err.Error())
+		}
+	} else {
+		_, _, err = endpoint.getVersionPointer(ctx, projectID, lastSegment, bucket, encryptedPath, version)
+		switch {
+		case err == nil:
+			err = endpoint.deleteObjectVersionPieces(ctx, projectID, bucket, encryptedPath, version)
+			if err != nil {
+				return err
+			}
+		case errs2.IsRPC(err, rpcstatus.NotFound):
+			path, err := CreateDeleteMarkerPath(ctx, projectID, bucket, encryptedPath, version)
+			if err != nil {
+				return rpcstatus.Error(rpcstatus.InvalidArgument, err.Error())
+			}
+
+			err = endpoint.metainfo.UnsynchronizedDelete(ctx, path)
+			if err != nil {
+				if storj.ErrObjectNotFound.Has(err) {
+					return rpcstatus.Errorf(rpcstatus.NotFound, "version %d not found", version)
+				}
+				return rpcstatus.Error(rpcstatus.Internal, err.Error())
+			}
+		default:
+			return err
+		}
+	}
+
+	return endpoint.restoreLatestVersion(ctx, projectID, bucket, encryptedPath)
+}
+
+// restoreLatestVersion makes the newest version the current object when there
+// is no current object and the newest version isn't a delete marker.
+func (endpoint *Endpoint) restoreLatestVersion(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	_, _, err = endpoint.getPointer(ctx, projectID, lastSegment, bucket, encryptedPath)
+	if !errs2.IsRPC(err, rpcstatus.NotFound) {
+		return err
+	}
+
+	versions, err := endpoint.metainfo.ListObjectVersions(ctx, projectID, bucket, encryptedPath)
+	if err != nil {
+		return rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+	if len(versions) == 0 || versions[0].IsDeleteMarker {
+		return nil
+	}
+
+	latest := versions[0].Version
+	err = endpoint.metainfo.MoveObjectVersion(ctx, projectID, bucket, encryptedPath, latest, 0)
+	if err != nil {
+		endpoint.log.Error("unable to restore object version", zap.Error(err))
+		return rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+
+	err = endpoint.versions.SetCurrent(ctx, projectID, bucket, encryptedPath, latest)
+	if err != nil {
+		return rpcstatus.Error(rpcstatus.Internal, err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfo_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/errs2"
+	"storj.io/common/memory"
+	"storj.io/common/pb"
+	"storj.io/common/rpc/rpcstatus"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/pkg/metainfopb"
+	"storj.io/storj/private/testplanet"
+	"storj.io/uplink/metainfo"
+)
+
+func TestObjectVersions(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		uplink := planet.Uplinks[0]
+		apiKey := uplink.APIKey[satellite.ID()]
+		endpoint := satellite.Metainfo.Endpoint2
+
+		bucket := []byte("testbucket")
+		first, second := testrand.Bytes(1*memory.KiB), testrand.Bytes(2*memory.KiB)
+
+		err := uplink.Upload(ctx, satellite, "testbucket", "object", first)
+		require.NoError(t, err)
+
+		_, err = endpoint.SetBucketVersioning(ctx, &metainfopb.SetBucketVersioningRequest{
+			ApiKey:     apiKey.SerializeRaw(),
+			Bucket:     bucket,
+			Versioning: metainfopb.Versioning_ENABLED,
+		})
+		require.NoError(t, err)
+
+		versioning, err := endpoint.GetBucketVersioning(ctx, &metainfopb.GetBucketVersioningRequest{
+			ApiKey: apiKey.SerializeRaw(),
+			Bucket: bucket,
+		})
+		require.NoError(t, err)
+		require.Equal(t, metainfopb.Versioning_ENABLED, versioning.Versioning)
+
+		// versioning can't be disabled once it has been enabled
+		_, err = endpoint.SetBucketVersioning(ctx, &metainfopb.SetBucketVersioningRequest{
+			ApiKey:     apiKey.SerializeRaw(),
+			Bucket:     bucket,
+			Versioning: metainfopb.Versioning_UNVERSIONED,
+		})
+		require.True(t, errs2.IsRPC(err, rpcstatus.FailedPrecondition), err)
+
+		// objects of buckets with versioning can't expire, incomplete uploads can be aborted
+		_, err = endpoint.SetBucketLifecycle(ctx, &metainfopb.SetBucketLifecycleRequest{
+			ApiKey: apiKey.SerializeRaw(),
+			Bucket: bucket,
+			Rules:  []*metainfopb.LifecycleRule{{Id: "expire", ExpireAfterDays: 7}},
+		})
+		require.True(t, errs2.IsRPC(err, rpcstatus.FailedPrecondition), err)
+		_, err = endpoint.SetBucketLifecycle(ctx, &metainfopb.SetBucketLifecycleRequest{
+			ApiKey: apiKey.SerializeRaw(),
+			Bucket: bucket,
+			Rules:  []*metainfopb.LifecycleRule{{Id: "abort", AbortIncompleteUploadsAfterHours: 2}},
+		})
+		require.NoError(t, err)
+
+		// versioning can't be enabled for buckets with expiration rules
+		require.NoError(t, uplink.CreateBucket(ctx, satellite, "expiring"))
+		_, err = endpoint.SetBucketLifecycle(ctx, &metainfopb.SetBucketLifecycleRequest{
+			ApiKey: apiKey.SerializeRaw(),
+			Bucket: []byte("expiring"),
+			Rules:  []*metainfopb.LifecycleRule{{Id: "expire", ExpireAfterDays: 7}},
+		})
+		require.NoError(t, err)
+		_, err = endpoint.SetBucketVersioning(ctx, &metainfopb.SetBucketVersioningRequest{
+			ApiKey:     apiKey.SerializeRaw(),
+			Bucket:     []byte("expiring"),
+			Versioning: metainfopb.Versioning_ENABLED,
+		})
+		require.True(t, errs2.IsRPC(err, rpcstatus.FailedPrecondition), err)
+
+		metainfoClient, err := uplink.DialMetainfo(ctx, satellite, apiKey)
+		require.NoError(t, err)
+		defer ctx.Check(metainfoClient.Close)
+
+		items, _, err := metainfoClient.ListObjects(ctx, metainfo.ListObjectsParams{Bucket: bucket})
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		encryptedPath := items[0].EncryptedPath
+
+		listVersions := func() []*metainfopb.ObjectVersion {
+			resp, err := endpoint.ListObjectVersions(ctx, &metainfopb.ListObjectVersionsRequest{
+				ApiKey:        apiKey.SerializeRaw(),
+				Bucket:        bucket,
+				EncryptedPath: encryptedPath,
+			})
+			require.NoError(t, err)
+			return resp.Versions
+		}
+		deleteVersion := func(version int32) {
+			_, err := metainfoClient.BeginDeleteObject(ctx, metainfo.BeginDeleteObjectParams{
+				Bucket:        bucket,
+				EncryptedPath: encryptedPath,
+				Version:       version,
+			})
+			require.NoError(t, err)
+		}
+
+		// overwriting keeps the object which was uploaded before versioning
+		err = uplink.Upload(ctx, satellite, "testbucket", "object", second)
+		require.NoError(t, err)
+
+		versions := listVersions()
+		require.Len(t, versions, 2)
+		require.Equal(t, int32(2), versions[0].Version)
+		require.True(t, versions[0].IsLatest)
+		require.Equal(t, int32(1), versions[1].Version)
+		require.False(t, versions[1].IsLatest)
+
+		data, err := uplink.Download(ctx, satellite, "testbucket", "object")
+		require.NoError(t, err)
+		require.Equal(t, second, data)
+
+		object, err := endpoint.GetObject(ctx, &pb.ObjectGetRequest{
+			Header:        &pb.RequestHeader{ApiKey: apiKey.SerializeRaw()},
+			Bucket:        bucket,
+			EncryptedPath: encryptedPath,
+			Version:       1,
+		})
+		require.NoError(t, err)
+		require.Equal(t, int32(1), object.Object.Version)
+		require.True(t, versions[1].CreatedAt.Equal(object.Object.CreatedAt))
+
+		// deleting adds a delete marker and keeps the object
+		err = uplink.Delete(ctx, satellite, "testbucket", "object")
+		require.NoError(t, err)
+
+		_, err = uplink.Download(ctx, satellite, "testbucket", "object")
+		require.True(t, storj.ErrObjectNotFound.Has(err), err)
+
+		versions = listVersions()
+		require.Len(t, versions, 3)
+		require.Equal(t, int32(3), versions[0].Version)
+		require.True(t, versions[0].IsDeleteMarker)
+		require.True(t, versions[0].IsLatest)
+
+		_, err = endpoint.GetObject(ctx, &pb.ObjectGetRequest{
+			Header:        &pb.RequestHeader{ApiKey: apiKey.SerializeRaw()},
+			Bucket:        bucket,
+			EncryptedPath: encryptedPath,
+			Version:       3,
+		})
+		require.True(t, errs2.IsRPC(err, rpcstatus.NotFound), err)
+
+		// a bucket with versions can't be deleted
+		_, err = endpoint.DeleteBucket(ctx, &pb.BucketDeleteRequest{
+			Header: &pb.RequestHeader{ApiKey: apiKey.SerializeRaw()},
+			Name:   bucket,
+		})
+		require.True(t, errs2.IsRPC(err, rpcstatus.FailedPrecondition), err)
+
+		// deleting the delete marker restores the newest version
+		deleteVersion(3)
+
+		data, err = uplink.Download(ctx, satellite, "testbucket", "object")
+		require.NoError(t, err)
+		require.Equal(t, second, data)
+
+		// deleting the current version permanently restores the previous one
+		deleteVersion(2)
+
+		data, err = uplink.Download(ctx, satellite, "testbucket", "object")
+		require.NoError(t, err)
+		require.Equal(t, first, data)
+
+		versions = listVersions()
+		require.Len(t, versions, 1)
+		require.Equal(t, int32(1), versions[0].Version)
+		require.True(t, versions[0].IsLatest)
+
+		_, err = metainfoClient.BeginDeleteObject(ctx, metainfo.BeginDeleteObjectParams{
+			Bucket:        bucket,
+			EncryptedPath: encryptedPath,
+			Version:       2,
+		})
+		require.True(t, storj.ErrObjectNotFound.Has(err), err)
+
+		// an upload which is never committed doesn't replace the current object
+		_, err = metainfoClient.BeginObject(ctx, metainfo.BeginObjectParams{
+			Bucket:        bucket,
+			EncryptedPath: encryptedPath,
+		})
+		require.NoError(t, err)
+
+		data, err = uplink.Download(ctx, satellite, "testbucket", "object")
+		require.NoError(t, err)
+		require.Equal(t, first, data)
+		require.Len(t, listVersions(), 1)
+	})
+}
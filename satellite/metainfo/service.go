@@ -330,3 +330,160 @@ func (s *Service) ListBuckets(ctx context.Context, projectID uuid.UUID, listOpts
 	defer mon.Task()(&ctx)(&err)
 	return s.bucketsDB.ListBuckets(ctx, projectID, listOpts, allowedBuckets)
 }
+
+// ObjectSegments contains the pointers of all segments of an object.
+type ObjectSegments struct {
+	// LastSegment is the pointer of the last segment, which contains the stream metadata.
+	LastSegment *pb.Pointer
+	// Segments are the pointers of the other segments, ordered by index.
+	Segments []*pb.Pointer
+}
+
+// RootPieceIDs returns the root piece IDs of the remote segments.
+func (segments ObjectSegments) RootPieceIDs() []storj.PieceID {
+	var rootPieceIDs []storj.PieceID
+	for _, pointer := range append([]*pb.Pointer{segments.LastSegment}, segments.Segments...) {
+		if pointer.Type == pb.Pointer_REMOTE && pointer.Remote != nil {
+			rootPieceIDs = append(rootPieceIDs, pointer.Remote.RootPieceId)
+		}
+	}
+	return rootPieceIDs
+}
+
+// ObjectCopy describes copying or moving an object to a new path inside a project.
+type ObjectCopy struct {
+	ProjectID     uuid.UUID
+	Bucket        []byte
+	EncryptedPath []byte
+	// CreationDate is the creation date of the last segment the new metadata was
+	// created for. The copy fails when the object has been replaced since.
+	CreationDate time.Time
+
+	NewBucket        []byte
+	NewEncryptedPath []byte
+	// LastSegmentMetadata is the stream metadata of the last segment, with the
+	// segment key encrypted for the new path.
+	LastSegmentMetadata []byte
+	// SegmentMetadata is the segment metadata of the other segments, ordered by
+	// index, with the segment keys encrypted for the new path.
+	SegmentMetadata [][]byte
+}
+
+// GetObjectSegments returns the pointers of all segments of an object.
+func (s *Service) GetObjectSegments(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte) (_ ObjectSegments, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return s.GetObjectVersionSegments(ctx, projectID, bucket, encryptedPath, 0)
+}
+
+// CopyObject copies the pointers of all segments of an object to the new path
+// with the new metadata. The pieces are not copied, so they are referenced by
+// the pointers of both objects afterwards.
+func (s *Service) CopyObject(ctx context.Context, objectCopy ObjectCopy) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	_, err = s.copyObject(ctx, objectCopy)
+	return err
+}
+
+// MoveObject moves the pointers of all segments of an object to the new path
+// with the new metadata.
+func (s *Service) MoveObject(ctx context.Context, objectCopy ObjectCopy) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	segments, err := s.copyObject(ctx, objectCopy)
+	if err != nil {
+		return err
+	}
+
+	// the last segment is deleted first, so the object disappears at once
+	for index := int64(lastSegment); index < int64(len(segments.Segments)); index++ {
+		path, err := CreatePath(ctx, objectCopy.ProjectID, index, objectCopy.Bucket, objectCopy.EncryptedPath)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+
+		err = s.UnsynchronizedDelete(ctx, path)
+		if err != nil && !storj.ErrObjectNotFound.Has(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyObject writes the pointers of the object under the new path and returns
+// the segments of the source object.
+func (s *Service) copyObject(ctx context.Context, objectCopy ObjectCopy) (_ ObjectSegments, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	segments, err := s.GetObjectSegments(ctx, objectCopy.ProjectID, objectCopy.Bucket, objectCopy.EncryptedPath)
+	if err != nil {
+		return ObjectSegments{}, err
+	}
+
+	if !segments.LastSegment.CreationDate.Equal(objectCopy.CreationDate) {
+		return ObjectSegments{}, ErrObjectChanged.New("%q/%q", objectCopy.Bucket, objectCopy.EncryptedPath)
+	}
+	if len(objectCopy.SegmentMetadata) != len(segments.Segments) {
+		return ObjectSegments{}, Error.New("expected metadata for %d segments, got %d", len(segments.Segments), len(objectCopy.SegmentMetadata))
+	}
+
+	newLastSegmentPath, err := CreatePath(ctx, objectCopy.ProjectID, lastSegment, objectCopy.NewBucket, objectCopy.NewEncryptedPath)
+	if err != nil {
+		return ObjectSegments{}, Error.Wrap(err)
+	}
+
+	_, err = s.Get(ctx, newLastSegmentPath)
+	if err == nil {
+		return ObjectSegments{}, ErrObjectExists.New("%q/%q", objectCopy.NewBucket, objectCopy.NewEncryptedPath)
+	}
+	if !storj.ErrObjectNotFound.Has(err) {
+		return ObjectSegments{}, err
+	}
+
+	var written []string
+	defer func() {
+		if err == nil {
+			return
+		}
+		for _, path := range written {
+			if deleteErr := s.UnsynchronizedDelete(ctx, path); deleteErr != nil {
+				s.logger.Warn("unable to delete pointer of failed copy", zap.String("path", path), zap.Error(deleteErr))
+			}
+		}
+	}()
+
+	// the last segment is written last, so the copy appears only when complete
+	for index, pointer := range segments.Segments {
+		path, err := CreatePath(ctx, objectCopy.ProjectID, int64(index), objectCopy.NewBucket, objectCopy.NewEncryptedPath)
+		if err != nil {
+			return ObjectSegments{}, Error.Wrap(err)
+		}
+
+		err = s.putCopy(ctx, path, pointer, objectCopy.SegmentMetadata[index])
+		if err != nil {
+			return ObjectSegments{}, err
+		}
+		written = append(written, path)
+	}
+
+	err = s.putCopy(ctx, newLastSegmentPath, segments.LastSegment, objectCopy.LastSegmentMetadata)
+	if err != nil {
+		return ObjectSegments{}, err
+	}
+
+	return segments, nil
+}
+
+// putCopy puts a copy of the pointer with the new metadata under path.
+func (s *Service) putCopy(ctx context.Context, path string, pointer *pb.Pointer, metadata []byte) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	copied := proto.Clone(pointer).(*pb.Pointer)
+	copied.Metadata = metadata
+
+	err = s.Put(ctx, path, copied)
+	if storage.ErrValueChanged.Has(err) {
+		return ErrObjectExists.New("%s", path)
+	}
+	return err
+}
@@ -0,0 +1,335 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/skyrings/skyring-common/tools/uuid"
+	"go.uber.org/zap"
+
+	"storj.io/common/pb"
+	"storj.io/common/storj"
+	"storj.io/storj/storage"
+	"storj.io/uplink/storage/meta"
+)
+
+// Versioning is the versioning state of a bucket.
+type Versioning int
+
+const (
+	// VersioningUnversioned keeps only the current object of every path.
+	VersioningUnversioned = Versioning(0)
+	// VersioningEnabled keeps overwritten and deleted objects as non-current
+	// versions and adds a delete marker when an object is deleted.
+	VersioningEnabled = Versioning(1)
+	// VersioningSuspended doesn't give new objects a version number. Objects
+	// which already have a version number are still kept as non-current
+	// versions when they are overwritten or deleted.
+	VersioningSuspended = Versioning(2)
+)
+
+// String returns the name of the versioning state.
+func (versioning Versioning) String() string {
+	switch versioning {
+	case VersioningUnversioned:
+		return "unversioned"
+	case VersioningEnabled:
+		return "enabled"
+	case VersioningSuspended:
+		return "suspended"
+	default:
+		return fmt.Sprintf("Versioning(%d)", int(versioning))
+	}
+}
+
+// The pointers of non-current versions are stored under the segment of the
+// current object prefixed with versionSegmentPrefix, e.g. "vl" and "vs0", with
+// the version number appended to the object path. Delete markers don't have
+// segments and are stored under deleteMarkerSegment.
+const (
+	versionSegmentPrefix = "v"
+	deleteMarkerSegment  = "vd"
+)
+
+// ObjectVersion is a non-current version or a delete marker of an object.
+type ObjectVersion struct {
+	Version        int32
+	IsDeleteMarker bool
+
+	CreationDate   time.Time
+	ExpirationDate time.Time
+	Metadata       []byte
+}
+
+// CreateVersionPath creates a segment path of an object version. Version 0 is
+// the current object.
+func CreateVersionPath(ctx context.Context, projectID uuid.UUID, segmentIndex int64, bucket, path []byte, version int32) (_ storj.Path, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if version == 0 {
+		return CreatePath(ctx, projectID, segmentIndex, bucket, path)
+	}
+
+	if segmentIndex < -1 {
+		return "", Error.New("invalid segment index")
+	}
+	segment := versionSegmentPrefix + "l"
+	if segmentIndex > -1 {
+		segment = versionSegmentPrefix + "s" + strconv.FormatInt(segmentIndex, 10)
+	}
+	return createVersionedPath(projectID, segment, bucket, path, version)
+}
+
+// CreateDeleteMarkerPath creates the path of a delete marker of an object.
+func CreateDeleteMarkerPath(ctx context.Context, projectID uuid.UUID, bucket, path []byte, version int32) (_ storj.Path, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return createVersionedPath(projectID, deleteMarkerSegment, bucket, path, version)
+}
+
+func createVersionedPath(projectID uuid.UUID, segment string, bucket, path []byte, version int32) (storj.Path, error) {
+	if version <= 0 {
+		return "", Error.New("invalid version %d", version)
+	}
+	if len(bucket) == 0 || len(path) == 0 {
+		return "", Error.New("versions require a bucket and an object path")
+	}
+	return storj.JoinPaths(projectID.String(), segment, string(bucket), string(path), formatVersion(version)), nil
+}
+
+// formatVersion zero pads the version number, so versions are sorted by
+// number in the pointer key space.
+func formatVersion(version int32) string {
+	return fmt.Sprintf("%010d", version)
+}
+
+func parseVersion(s string) (int32, error) {
+	version, err := strconv.ParseInt(s, 10, 32)
+	if err != nil {
+		return 0, Error.Wrap(err)
+	}
+	if version <= 0 {
+		return 0, Error.New("invalid version %d", version)
+	}
+	return int32(version), nil
+}
+
+// GetBucketVersioning returns the versioning state of a bucket.
+func (s *Service) GetBucketVersioning(ctx context.Context, bucketName []byte, projectID uuid.UUID) (_ Versioning, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return s.bucketsDB.GetBucketVersioning(ctx, bucketName, projectID)
+}
+
+// SetBucketVersioning sets the versioning state of a bucket.
+func (s *Service) SetBucketVersioning(ctx context.Context, bucketName []byte, projectID uuid.UUID, versioning Versioning) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return s.bucketsDB.SetBucketVersioning(ctx, bucketName, projectID, versioning)
+}
+
+// GetObjectVersionSegments returns the pointers of all segments of an object
+// version. Version 0 is the current object.
+func (s *Service) GetObjectVersionSegments(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte, version int32) (_ ObjectSegments, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	path, err := CreateVersionPath(ctx, projectID, lastSegment, bucket, encryptedPath, version)
+	if err != nil {
+		return ObjectSegments{}, Error.Wrap(err)
+	}
+
+	segments := ObjectSegments{}
+	segments.LastSegment, err = s.Get(ctx, path)
+	if err != nil {
+		return ObjectSegments{}, err
+	}
+
+	streamMeta := pb.StreamMeta{}
+	err = proto.Unmarshal(segments.LastSegment.Metadata, &streamMeta)
+	if err != nil {
+		return ObjectSegments{}, Error.Wrap(err)
+	}
+
+	// NumberOfSegments is 0 when the number of segments is encrypted,
+	// in which case the segments are read until one doesn't exist.
+	for index := int64(0); streamMeta.NumberOfSegments == 0 || index < streamMeta.NumberOfSegments-1; index++ {
+		path, err := CreateVersionPath(ctx, projectID, index, bucket, encryptedPath, version)
+		if err != nil {
+			return ObjectSegments{}, Error.Wrap(err)
+		}
+
+		pointer, err := s.Get(ctx, path)
+		if err != nil {
+			if streamMeta.NumberOfSegments == 0 && storj.ErrObjectNotFound.Has(err) {
+				break
+			}
+			return ObjectSegments{}, err
+		}
+		segments.Segments = append(segments.Segments, pointer)
+	}
+
+	return segments, nil
+}
+
+// MoveObjectVersion moves the pointers of all segments of an object version
+// to another version of the same object, e.g. to keep the current object
+// (version 0) as a non-current version. The pointers are not changed, so they
+// keep their creation date and pieces.
+func (s *Service) MoveObjectVersion(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte, from, to int32) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	segments, err := s.GetObjectVersionSegments(ctx, projectID, bucket, encryptedPath, from)
+	if err != nil {
+		return err
+	}
+
+	var written []string
+	defer func() {
+		if err == nil {
+			return
+		}
+		for _, path := range written {
+			if deleteErr := s.UnsynchronizedDelete(ctx, path); deleteErr != nil {
+				s.logger.Warn("unable to delete pointer of failed version move", zap.String("path", path), zap.Error(deleteErr))
+			}
+		}
+	}()
+
+	// the last segment is written last, so the version appears only when complete
+	for index, pointer := range segments.Segments {
+		path, err := CreateVersionPath(ctx, projectID, int64(index), bucket, encryptedPath, to)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+
+		err = s.putUnchanged(ctx, path, pointer)
+		if err != nil {
+			return err
+		}
+		written = append(written, path)
+	}
+
+	path, err := CreateVersionPath(ctx, projectID, lastSegment, bucket, encryptedPath, to)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	err = s.putUnchanged(ctx, path, segments.LastSegment)
+	if err != nil {
+		return err
+	}
+	written = append(written, path)
+
+	// the last segment is deleted first, so the old version disappears at once
+	for index := int64(lastSegment); index < int64(len(segments.Segments)); index++ {
+		path, err := CreateVersionPath(ctx, projectID, index, bucket, encryptedPath, from)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+
+		err = s.UnsynchronizedDelete(ctx, path)
+		if err != nil && !storj.ErrObjectNotFound.Has(err) {
+			if index == lastSegment {
+				return err
+			}
+			// the new version is complete, so it must not be rolled back
+			// because it references the same pieces as the leftover segment
+			s.logger.Error("unable to delete pointer of moved version", zap.String("path", path), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// putUnchanged puts the pointer under path without updating its creation date.
+func (s *Service) putUnchanged(ctx context.Context, path string, pointer *pb.Pointer) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	pointerBytes, err := proto.Marshal(pointer)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	err = s.db.CompareAndSwap(ctx, []byte(path), nil, pointerBytes)
+	if storage.ErrValueChanged.Has(err) {
+		return ErrObjectExists.New("%s", path)
+	}
+	return Error.Wrap(err)
+}
+
+// ListObjectVersions returns the non-current versions and the delete markers
+// of an object, newest first.
+func (s *Service) ListObjectVersions(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte) (versions []ObjectVersion, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if len(bucket) == 0 || len(encryptedPath) == 0 {
+		return nil, Error.New("versions require a bucket and an object path")
+	}
+
+	for _, segment := range []string{versionSegmentPrefix + "l", deleteMarkerSegment} {
+		prefix := storj.JoinPaths(projectID.String(), segment, string(bucket), string(encryptedPath))
+
+		startAfter := ""
+		for {
+			items, more, err := s.List(ctx, prefix, startAfter, false, 0, meta.All)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, item := range items {
+				startAfter = item.Path
+				// prefixes contain the versions of objects with a longer path
+				if item.IsPrefix {
+					continue
+				}
+
+				version, err := parseVersion(item.Path)
+				if err != nil {
+					return nil, err
+				}
+
+				objectVersion := ObjectVersion{
+					Version:        version,
+					IsDeleteMarker: segment == deleteMarkerSegment,
+				}
+				if item.Pointer != nil {
+					objectVersion.CreationDate = item.Pointer.CreationDate
+					objectVersion.ExpirationDate = item.Pointer.ExpirationDate
+					objectVersion.Metadata = item.Pointer.Metadata
+				}
+				versions = append(versions, objectVersion)
+			}
+
+			if !more {
+				break
+			}
+		}
+	}
+
+	sort.Slice(versions, func(i, k int) bool {
+		return versions[i].Version > versions[k].Version
+	})
+	return versions, nil
+}
+
+// HasObjectVersions returns whether there are non-current versions or delete
+// markers in a bucket.
+func (s *Service) HasObjectVersions(ctx context.Context, projectID uuid.UUID, bucket []byte) (_ bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	for _, segment := range []string{versionSegmentPrefix + "l", deleteMarkerSegment} {
+		prefix := storj.JoinPaths(projectID.String(), segment, string(bucket))
+
+		items, _, err := s.List(ctx, prefix, "", true, 1, meta.None)
+		if err != nil {
+			return false, err
+		}
+		if len(items) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
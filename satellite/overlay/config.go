@@ -19,7 +19,9 @@ var (
 // Config is a configuration for overlay service.
 type Config struct {
 	Node                 NodeSelectionConfig
-	UpdateStatsBatchSize int `help:"number of update requests to process per transaction" default:"100"`
+	NodeSelectionCache   NodeSelectionCacheConfig
+	UpdateStatsBatchSize int    `help:"number of update requests to process per transaction" default:"100"`
+	LocationDB           string `help:"path to a CSV file mapping IP networks to autonomous system numbers and regions" default:""`
 }
 
 // NodeSelectionConfig is a configuration struct to determine the minimum
@@ -31,6 +33,7 @@ type NodeSelectionConfig struct {
 	MinimumVersion    string        `help:"the minimum node software version for node selection queries" default:""`
 	OnlineWindow      time.Duration `help:"the amount of time without seeing a node before its considered offline" default:"4h"`
 	DistinctIP        bool          `help:"require distinct IPs when choosing nodes for upload" releaseDefault:"true" devDefault:"false"`
+	Placement         PlacementConfig
 
 	AuditReputationRepairWeight float64 `help:"weight to apply to audit reputation for total repair reputation calculation" default:"1.0"`
 	AuditReputationUplinkWeight float64 `help:"weight to apply to audit reputation for total uplink reputation calculation" default:"1.0"`
@@ -0,0 +1,205 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package overlay
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/zeebo/errs"
+)
+
+// ErrLocationDB is returned when the location database cannot be loaded.
+var ErrLocationDB = errs.Class("location database")
+
+// PlacementConfig limits how many pieces of a single segment can be stored
+// within one failure domain. Zero means unlimited.
+type PlacementConfig struct {
+	MaxPerSubnet int `help:"the maximum number of pieces of a segment stored in one /16 subnet (0 is unlimited)" default:"0"`
+	MaxPerASN    int `help:"the maximum number of pieces of a segment stored in one autonomous system (0 is unlimited)" default:"0"`
+	MaxPerRegion int `help:"the maximum number of pieces of a segment stored in one region (0 is unlimited)" default:"0"`
+}
+
+// IsUnlimited returns true when the placement does not restrict node selection.
+func (config PlacementConfig) IsUnlimited() bool {
+	return config.MaxPerSubnet <= 0 && config.MaxPerASN <= 0 && config.MaxPerRegion <= 0
+}
+
+// NodeLocation describes the failure domains a node belongs to.
+// Zero values mean the failure domain is unknown.
+type NodeLocation struct {
+	// Subnet is the /16 network of an IPv4 node, or the /32 network of an IPv6 node.
+	Subnet string
+	ASN    uint32
+	Region string
+}
+
+// PlacementTracker counts how many pieces have been placed in each failure domain.
+type PlacementTracker struct {
+	config  PlacementConfig
+	subnets map[string]int
+	asns    map[uint32]int
+	regions map[string]int
+}
+
+// NewPlacementTracker creates a tracker for the placement limits in config.
+func NewPlacementTracker(config PlacementConfig) *PlacementTracker {
+	return &PlacementTracker{
+		config:  config,
+		subnets: map[string]int{},
+		asns:    map[uint32]int{},
+		regions: map[string]int{},
+	}
+}
+
+// Allowed returns whether a piece can be placed in location without exceeding any limit.
+// Unknown failure domains are never limited.
+func (tracker *PlacementTracker) Allowed(location NodeLocation) bool {
+	if tracker.config.MaxPerSubnet > 0 && location.Subnet != "" && tracker.subnets[location.Subnet] >= tracker.config.MaxPerSubnet {
+		return false
+	}
+	if tracker.config.MaxPerASN > 0 && location.ASN != 0 && tracker.asns[location.ASN] >= tracker.config.MaxPerASN {
+		return false
+	}
+	if tracker.config.MaxPerRegion > 0 && location.Region != "" && tracker.regions[location.Region] >= tracker.config.MaxPerRegion {
+		return false
+	}
+	return true
+}
+
+// Add records a piece placed in location.
+func (tracker *PlacementTracker) Add(location NodeLocation) {
+	if location.Subnet != "" {
+		tracker.subnets[location.Subnet]++
+	}
+	if location.ASN != 0 {
+		tracker.asns[location.ASN]++
+	}
+	if location.Region != "" {
+		tracker.regions[location.Region]++
+	}
+}
+
+// Locator looks up the autonomous system and region an IP address belongs to.
+type Locator interface {
+	// Locate returns the ASN and region of ip, or zero values when they are unknown.
+	Locate(ip net.IP) (asn uint32, region string)
+}
+
+// GetLocation resolves the target address and determines its failure domains.
+// locator may be nil, in which case only the subnet is determined.
+func GetLocation(ctx context.Context, target string, locator Locator) (location NodeLocation, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	addr, err := getIP(ctx, target)
+	if err != nil {
+		return NodeLocation{}, err
+	}
+
+	if ipv4 := addr.IP.To4(); ipv4 != nil {
+		location.Subnet = ipv4.Mask(net.CIDRMask(16, 32)).String()
+	} else if ipv6 := addr.IP.To16(); ipv6 != nil {
+		location.Subnet = ipv6.Mask(net.CIDRMask(32, 128)).String()
+	} else {
+		return NodeLocation{}, errors.New("unable to get location for address " + addr.String())
+	}
+
+	if locator != nil {
+		location.ASN, location.Region = locator.Locate(addr.IP)
+	}
+	return location, nil
+}
+
+// LocationDB is an offline IP to location database, which maps networks
+// to autonomous system numbers and regions.
+type LocationDB struct {
+	// networks contains the entries for every prefix length, keyed by the masked network
+	networks [129]map[string]locationEntry
+}
+
+type locationEntry struct {
+	asn    uint32
+	region string
+}
+
+var _ Locator = (*LocationDB)(nil)
+
+// LoadLocationDB loads a location database from a CSV file, where every line
+// has the form "network,asn,region", for example "192.0.2.0/24,64496,eu-north".
+// Empty lines and lines starting with '#' are ignored.
+func LoadLocationDB(path string) (_ *LocationDB, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, ErrLocationDB.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, ErrLocationDB.Wrap(file.Close())) }()
+
+	db := &LocationDB{}
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, ",")
+		if len(fields) != 3 {
+			return nil, ErrLocationDB.New("line %d: expected 3 fields, got %d", lineNumber, len(fields))
+		}
+
+		_, network, err := net.ParseCIDR(strings.TrimSpace(fields[0]))
+		if err != nil {
+			return nil, ErrLocationDB.New("line %d: %v", lineNumber, err)
+		}
+		asn, err := strconv.ParseUint(strings.TrimSpace(fields[1]), 10, 32)
+		if err != nil {
+			return nil, ErrLocationDB.New("line %d: invalid asn: %v", lineNumber, err)
+		}
+
+		db.add(network, locationEntry{
+			asn:    uint32(asn),
+			region: strings.TrimSpace(fields[2]),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, ErrLocationDB.Wrap(err)
+	}
+
+	return db, nil
+}
+
+func (db *LocationDB) add(network *net.IPNet, entry locationEntry) {
+	ones, bits := network.Mask.Size()
+	if bits == 32 {
+		// IPv4 networks are stored in their IPv6 mapped form
+		ones += 96
+	}
+	if db.networks[ones] == nil {
+		db.networks[ones] = map[string]locationEntry{}
+	}
+	key := network.IP.To16().Mask(net.CIDRMask(ones, 128))
+	db.networks[ones][string(key)] = entry
+}
+
+// Locate returns the ASN and region of the most specific network containing ip.
+func (db *LocationDB) Locate(ip net.IP) (asn uint32, region string) {
+	ip = ip.To16()
+	if ip == nil {
+		return 0, ""
+	}
+	for ones := len(db.networks) - 1; ones >= 0; ones-- {
+		if db.networks[ones] == nil {
+			continue
+		}
+		if entry, ok := db.networks[ones][string(ip.Mask(net.CIDRMask(ones, 128)))]; ok {
+			return entry.asn, entry.region
+		}
+	}
+	return 0, ""
+}
@@ -0,0 +1,187 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package overlay_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/common/pb"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/storj/satellite"
+	"storj.io/storj/satellite/overlay"
+	"storj.io/storj/satellite/satellitedb/satellitedbtest"
+)
+
+func TestPlacementTracker(t *testing.T) {
+	tracker := overlay.NewPlacementTracker(overlay.PlacementConfig{
+		MaxPerSubnet: 2,
+		MaxPerASN:    3,
+		MaxPerRegion: 4,
+	})
+
+	a := overlay.NodeLocation{Subnet: "10.1.0.0", ASN: 1, Region: "eu"}
+	b := overlay.NodeLocation{Subnet: "10.2.0.0", ASN: 1, Region: "eu"}
+	c := overlay.NodeLocation{Subnet: "10.3.0.0", ASN: 2, Region: "eu"}
+	unknown := overlay.NodeLocation{}
+
+	// subnet limit
+	require.True(t, tracker.Allowed(a))
+	tracker.Add(a)
+	require.True(t, tracker.Allowed(a))
+	tracker.Add(a)
+	require.False(t, tracker.Allowed(a))
+
+	// asn limit
+	require.True(t, tracker.Allowed(b))
+	tracker.Add(b)
+	require.False(t, tracker.Allowed(b))
+
+	// region limit
+	require.True(t, tracker.Allowed(c))
+	tracker.Add(c)
+	require.False(t, tracker.Allowed(c))
+
+	// unknown failure domains are never limited
+	for i := 0; i < 10; i++ {
+		require.True(t, tracker.Allowed(unknown))
+		tracker.Add(unknown)
+	}
+
+	unlimited := overlay.NewPlacementTracker(overlay.PlacementConfig{})
+	for i := 0; i < 10; i++ {
+		require.True(t, unlimited.Allowed(a))
+		unlimited.Add(a)
+	}
+}
+
+func TestLocationDB(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	path := ctx.File("locations.csv")
+	err := ioutil.WriteFile(path, []byte(`# network,asn,region
+10.0.0.0/8,64496,eu-north
+10.1.0.0/16,64497,eu-west
+
+2001:db8::/32,64498,us-east
+`), 0644)
+	require.NoError(t, err)
+
+	db, err := overlay.LoadLocationDB(path)
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		ip     string
+		asn    uint32
+		region string
+	}{
+		{"10.2.3.4", 64496, "eu-north"},
+		{"10.1.3.4", 64497, "eu-west"},
+		{"2001:db8::1", 64498, "us-east"},
+		{"192.0.2.1", 0, ""},
+	} {
+		asn, region := db.Locate(net.ParseIP(tt.ip))
+		require.Equal(t, tt.asn, asn, tt.ip)
+		require.Equal(t, tt.region, region, tt.ip)
+	}
+
+	invalid := ctx.File("invalid.csv")
+	require.NoError(t, ioutil.WriteFile(invalid, []byte("10.0.0.0/8,notanumber,eu-north\n"), 0644))
+	_, err = overlay.LoadLocationDB(invalid)
+	require.True(t, overlay.ErrLocationDB.Has(err))
+}
+
+func TestGetLocation(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	location, err := overlay.GetLocation(ctx, "8.8.8.8:28967", nil)
+	require.NoError(t, err)
+	require.Equal(t, overlay.NodeLocation{Subnet: "8.8.0.0"}, location)
+
+	location, err = overlay.GetLocation(ctx, "[fc00::1:200]:28967", staticLocator{asn: 1, region: "eu"})
+	require.NoError(t, err)
+	require.Equal(t, overlay.NodeLocation{Subnet: "fc00::", ASN: 1, Region: "eu"}, location)
+}
+
+func TestNodeSelectionPlacement(t *testing.T) {
+	satellitedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db satellite.DB) {
+		cache := db.OverlayCache()
+
+		// 3 regions with 4 nodes each
+		var nodeIDs storj.NodeIDList
+		for i := 0; i < 12; i++ {
+			nodeID := storj.NodeID{byte(i + 1)}
+			nodeIDs = append(nodeIDs, nodeID)
+			err := cache.UpdateCheckIn(ctx, overlay.NodeCheckInInfo{
+				NodeID:   nodeID,
+				Address:  &pb.NodeAddress{Address: fmt.Sprintf("10.%d.0.1:7777", i)},
+				LastIP:   fmt.Sprintf("10.%d.0.0", i),
+				IsUp:     true,
+				Capacity: &pb.NodeCapacity{FreeBandwidth: 1000, FreeDisk: 1000},
+				Version:  &pb.NodeVersion{Version: "v1.0.0"},
+				Location: overlay.NodeLocation{
+					Subnet: fmt.Sprintf("10.%d.0.0", i),
+					ASN:    uint32(64496 + i%3),
+					Region: fmt.Sprintf("region-%d", i%3),
+				},
+			}, time.Now(), overlay.NodeSelectionConfig{})
+			require.NoError(t, err)
+		}
+
+		service := overlay.NewService(zaptest.NewLogger(t), cache, overlay.Config{})
+		preferences := &overlay.NodeSelectionConfig{
+			OnlineWindow: time.Hour,
+			Placement:    overlay.PlacementConfig{MaxPerRegion: 2},
+		}
+
+		for i := 0; i < 10; i++ {
+			nodes, err := service.FindStorageNodesWithPreferences(ctx, overlay.FindStorageNodesRequest{
+				RequestedCount: 6,
+			}, preferences)
+			require.NoError(t, err)
+			require.Len(t, nodes, 6)
+
+			regions := map[byte]int{}
+			for _, node := range nodes {
+				regions[(node.Id[0]-1)%3]++
+			}
+			for _, count := range regions {
+				require.Equal(t, 2, count)
+			}
+		}
+
+		// excluded nodes count towards the limits
+		nodes, err := service.FindStorageNodesWithPreferences(ctx, overlay.FindStorageNodesRequest{
+			RequestedCount: 6,
+			ExcludedNodes:  nodeIDs[:1],
+		}, preferences)
+		require.True(t, overlay.ErrNotEnoughNodes.Has(err))
+		require.Len(t, nodes, 5)
+
+		regions := map[byte]int{}
+		for _, node := range nodes {
+			require.NotEqual(t, nodeIDs[0], node.Id)
+			regions[(node.Id[0]-1)%3]++
+		}
+		require.Equal(t, map[byte]int{0: 1, 1: 2, 2: 2}, regions)
+	})
+}
+
+type staticLocator struct {
+	asn    uint32
+	region string
+}
+
+func (locator staticLocator) Locate(ip net.IP) (uint32, string) {
+	return locator.asn, locator.region
+}
@@ -0,0 +1,246 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package overlay
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/common/pb"
+	"storj.io/common/storj"
+	"storj.io/common/sync2"
+)
+
+// errSelectionCacheStale is returned when the node selection cache is too old to be used.
+var errSelectionCacheStale = errs.Class("node selection cache stale")
+
+// NodeSelectionCacheConfig is a configuration for the node selection cache.
+type NodeSelectionCacheConfig struct {
+	Disabled        bool          `help:"disable the node selection cache and select upload nodes directly from the database" default:"false"`
+	RefreshInterval time.Duration `help:"how often the node selection cache is refreshed from the database" default:"3m"`
+	Staleness       time.Duration `help:"how old the node selection cache can be before uploads select nodes from the database" default:"10m"`
+}
+
+// SelectedNode is a node that qualifies to store data, as cached by the node selection cache.
+type SelectedNode struct {
+	ID            storj.NodeID
+	Address       *pb.NodeAddress
+	LastNet       string
+	FreeBandwidth int64
+	FreeDisk      int64
+	Location      NodeLocation
+}
+
+// NodeSelectionCache keeps the nodes that qualify to store data in memory,
+// so that uploads can select nodes without querying the database.
+//
+// architecture: Service
+type NodeSelectionCache struct {
+	log       *zap.Logger
+	db        DB
+	selection NodeSelectionConfig
+	config    NodeSelectionCacheConfig
+	Loop      *sync2.Cycle
+
+	mu   sync.RWMutex
+	data *selectionCacheData
+}
+
+// selectionCacheData is an immutable snapshot of the nodes that qualify to store data.
+type selectionCacheData struct {
+	refreshed time.Time
+	reputable []*SelectedNode
+	new       []*SelectedNode
+	byID      map[storj.NodeID]*SelectedNode
+}
+
+// NewNodeSelectionCache creates a new node selection cache, which selects nodes using the selection config.
+func NewNodeSelectionCache(log *zap.Logger, db DB, selection NodeSelectionConfig, config NodeSelectionCacheConfig) *NodeSelectionCache {
+	return &NodeSelectionCache{
+		log:       log,
+		db:        db,
+		selection: selection,
+		config:    config,
+		Loop:      sync2.NewCycle(config.RefreshInterval),
+	}
+}
+
+// Run periodically refreshes the node selection cache.
+func (cache *NodeSelectionCache) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if cache.config.Disabled {
+		return nil
+	}
+
+	return cache.Loop.Run(ctx, func(ctx context.Context) error {
+		if err := cache.Refresh(ctx); err != nil {
+			cache.log.Error("failed to refresh node selection cache", zap.Error(err))
+		}
+		return nil
+	})
+}
+
+// Close stops refreshing the node selection cache.
+func (cache *NodeSelectionCache) Close() error {
+	cache.Loop.Close()
+	return nil
+}
+
+// Refresh replaces the cached nodes with the nodes currently in the database.
+func (cache *NodeSelectionCache) Refresh(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	refreshed := time.Now()
+	reputable, new, err := cache.db.SelectAllStorageNodesUpload(ctx, cache.selection)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	data := &selectionCacheData{
+		refreshed: refreshed,
+		reputable: reputable,
+		new:       new,
+		byID:      make(map[storj.NodeID]*SelectedNode, len(reputable)+len(new)),
+	}
+	for _, node := range reputable {
+		data.byID[node.ID] = node
+	}
+	for _, node := range new {
+		data.byID[node.ID] = node
+	}
+
+	cache.mu.Lock()
+	cache.data = data
+	cache.mu.Unlock()
+
+	mon.IntVal("node_selection_cache_reputable_nodes").Observe(int64(len(reputable)))
+	mon.IntVal("node_selection_cache_new_nodes").Observe(int64(len(new)))
+
+	return nil
+}
+
+// Select selects nodes from the cache, with the same semantics as
+// Service.FindStorageNodesWithPreferences using the cache selection config.
+func (cache *NodeSelectionCache) Select(ctx context.Context, req FindStorageNodesRequest) (nodes []*pb.Node, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	cache.mu.RLock()
+	data := cache.data
+	cache.mu.RUnlock()
+
+	if data == nil {
+		return nil, errSelectionCacheStale.New("not refreshed yet")
+	}
+	age := time.Since(data.refreshed)
+	mon.FloatVal("node_selection_cache_age_seconds").Observe(age.Seconds())
+	if age > cache.config.Staleness {
+		return nil, errSelectionCacheStale.New("last refreshed %v ago", age)
+	}
+
+	preferences := &cache.selection
+
+	reputableNodeCount := req.MinimumRequiredNodes
+	if reputableNodeCount <= 0 {
+		reputableNodeCount = req.RequestedCount
+	}
+
+	selector := &cachedNodeSelector{
+		req:      req,
+		excluded: make(map[storj.NodeID]bool, len(req.ExcludedNodes)),
+		nets:     map[string]bool{},
+	}
+	if !preferences.Placement.IsUnlimited() {
+		selector.placement = NewPlacementTracker(preferences.Placement)
+	}
+
+	selector.distinctIP = preferences.DistinctIP
+
+	// the networks and locations of excluded nodes are taken from the cache,
+	// excluded nodes that aren't cached don't qualify to store data anymore
+	for _, id := range req.ExcludedNodes {
+		selector.excluded[id] = true
+		node, ok := data.byID[id]
+		if !ok {
+			continue
+		}
+		if selector.distinctIP && node.LastNet != "" {
+			selector.nets[node.LastNet] = true
+		}
+		if selector.placement != nil {
+			selector.placement.Add(node.Location)
+		}
+	}
+
+	newNodeCount := 0
+	if preferences.NewNodePercentage > 0 {
+		newNodeCount = int(float64(reputableNodeCount) * preferences.NewNodePercentage)
+	}
+
+	nodes = selector.selectFrom(data.new, newNodeCount, nil)
+	nodes = selector.selectFrom(data.reputable, reputableNodeCount-len(nodes), nodes)
+
+	if len(nodes) < reputableNodeCount {
+		return nodes, ErrNotEnoughNodes.New("requested %d found %d; %+v ", reputableNodeCount, len(nodes), req)
+	}
+
+	return nodes, nil
+}
+
+// cachedNodeSelector keeps track of the nodes and networks already used by a selection.
+type cachedNodeSelector struct {
+	req        FindStorageNodesRequest
+	excluded   map[storj.NodeID]bool
+	distinctIP bool
+	nets       map[string]bool
+	placement  *PlacementTracker
+}
+
+// selectFrom appends up to count randomly chosen nodes from candidates to nodes.
+func (selector *cachedNodeSelector) selectFrom(candidates []*SelectedNode, count int, nodes []*pb.Node) []*pb.Node {
+	if count <= 0 {
+		return nodes
+	}
+
+	selected := 0
+	for _, i := range rand.Perm(len(candidates)) {
+		if selected >= count {
+			break
+		}
+
+		node := candidates[i]
+		if selector.excluded[node.ID] {
+			continue
+		}
+		if node.FreeBandwidth < selector.req.FreeBandwidth || node.FreeDisk < selector.req.FreeDisk {
+			continue
+		}
+		if selector.distinctIP && (node.LastNet == "" || selector.nets[node.LastNet]) {
+			continue
+		}
+		if selector.placement != nil {
+			if !selector.placement.Allowed(node.Location) {
+				continue
+			}
+			selector.placement.Add(node.Location)
+		}
+
+		selector.excluded[node.ID] = true
+		selector.nets[node.LastNet] = true
+		selected++
+
+		address := *node.Address
+		nodes = append(nodes, &pb.Node{
+			Id:      node.ID,
+			Address: &address,
+			LastIp:  node.LastNet,
+		})
+	}
+	return nodes
+}
@@ -0,0 +1,287 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package overlay_test
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/common/pb"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/storj/satellite"
+	"storj.io/storj/satellite/overlay"
+	"storj.io/storj/satellite/satellitedb/satellitedbtest"
+)
+
+// selectionDB returns a fixed set of nodes for the node selection cache and
+// a marker node when nodes are selected from the database.
+type selectionDB struct {
+	overlay.DB
+
+	reputable []*overlay.SelectedNode
+	new       []*overlay.SelectedNode
+}
+
+var databaseNode = &pb.Node{Id: storj.NodeID{0xff}}
+
+func (db *selectionDB) SelectAllStorageNodesUpload(ctx context.Context, selectionConfig overlay.NodeSelectionConfig) (reputable, new []*overlay.SelectedNode, err error) {
+	return db.reputable, db.new, nil
+}
+
+func (db *selectionDB) SelectStorageNodes(ctx context.Context, count int, criteria *overlay.NodeCriteria) ([]*pb.Node, error) {
+	var nodes []*pb.Node
+	for i := 0; i < count; i++ {
+		nodes = append(nodes, databaseNode)
+	}
+	return nodes, nil
+}
+
+func (db *selectionDB) SelectNewStorageNodes(ctx context.Context, count int, criteria *overlay.NodeCriteria) ([]*pb.Node, error) {
+	return nil, nil
+}
+
+// GetNodeIPs fails, since the cache must take the networks of excluded nodes from the cached nodes.
+func (db *selectionDB) GetNodeIPs(ctx context.Context, nodeIDs []storj.NodeID) ([]string, error) {
+	return nil, errs.New("unexpected query of node IPs")
+}
+
+func newSelectedNodes(first, count int, net func(i int) string) []*overlay.SelectedNode {
+	var nodes []*overlay.SelectedNode
+	for i := first; i < first+count; i++ {
+		nodes = append(nodes, &overlay.SelectedNode{
+			ID:            storj.NodeID{byte(i)},
+			Address:       &pb.NodeAddress{Address: fmt.Sprintf("10.0.0.%d:7777", i)},
+			LastNet:       net(i),
+			FreeBandwidth: int64(i),
+			FreeDisk:      int64(i),
+		})
+	}
+	return nodes
+}
+
+func TestNodeSelectionCacheFallback(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	db := &selectionDB{
+		reputable: newSelectedNodes(1, 10, func(i int) string { return fmt.Sprintf("10.0.%d.0", i) }),
+	}
+	service := overlay.NewService(zaptest.NewLogger(t), db, overlay.Config{
+		NodeSelectionCache: overlay.NodeSelectionCacheConfig{
+			RefreshInterval: time.Minute,
+			Staleness:       time.Hour,
+		},
+	})
+
+	// not refreshed yet, so the nodes come from the database
+	nodes, err := service.FindStorageNodesForUpload(ctx, overlay.FindStorageNodesRequest{RequestedCount: 5})
+	require.NoError(t, err)
+	require.Len(t, nodes, 5)
+	for _, node := range nodes {
+		require.Equal(t, databaseNode.Id, node.Id)
+	}
+
+	require.NoError(t, service.SelectionCache.Refresh(ctx))
+
+	nodes, err = service.FindStorageNodesForUpload(ctx, overlay.FindStorageNodesRequest{RequestedCount: 5})
+	require.NoError(t, err)
+	require.Len(t, nodes, 5)
+	for _, node := range nodes {
+		require.NotEqual(t, databaseNode.Id, node.Id)
+	}
+
+	// not enough nodes in the cache, so the nodes come from the database
+	nodes, err = service.FindStorageNodesForUpload(ctx, overlay.FindStorageNodesRequest{RequestedCount: 11})
+	require.NoError(t, err)
+	require.Len(t, nodes, 11)
+	for _, node := range nodes {
+		require.Equal(t, databaseNode.Id, node.Id)
+	}
+
+	// a stale cache is not used
+	stale := overlay.NewNodeSelectionCache(zaptest.NewLogger(t), db, overlay.NodeSelectionConfig{}, overlay.NodeSelectionCacheConfig{
+		Staleness: -time.Second,
+	})
+	require.NoError(t, stale.Refresh(ctx))
+	_, err = stale.Select(ctx, overlay.FindStorageNodesRequest{RequestedCount: 5})
+	require.Error(t, err)
+	require.False(t, overlay.ErrNotEnoughNodes.Has(err))
+}
+
+func TestNodeSelectionCacheSelect(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	// nodes 1-20 are reputable and nodes 21-30 are new, every two nodes share a network
+	db := &selectionDB{
+		reputable: newSelectedNodes(1, 20, func(i int) string { return fmt.Sprintf("10.0.%d.0", i/2) }),
+		new:       newSelectedNodes(21, 10, func(i int) string { return fmt.Sprintf("10.0.%d.0", i/2) }),
+	}
+
+	for _, distinctIP := range []bool{false, true} {
+		cache := overlay.NewNodeSelectionCache(zaptest.NewLogger(t), db, overlay.NodeSelectionConfig{
+			NewNodePercentage: 0.2,
+			DistinctIP:        distinctIP,
+		}, overlay.NodeSelectionCacheConfig{Staleness: time.Hour})
+		require.NoError(t, cache.Refresh(ctx))
+
+		for i := 0; i < 20; i++ {
+			excluded := storj.NodeIDList{{1}, {22}}
+			nodes, err := cache.Select(ctx, overlay.FindStorageNodesRequest{
+				RequestedCount: 10,
+				FreeDisk:       3,
+				FreeBandwidth:  3,
+				ExcludedNodes:  excluded,
+			})
+			require.NoError(t, err)
+			require.Len(t, nodes, 10)
+
+			seen := map[storj.NodeID]bool{}
+			nets := map[string]bool{}
+			newNodes := 0
+			for _, node := range nodes {
+				require.False(t, seen[node.Id], "duplicate node")
+				seen[node.Id] = true
+
+				require.NotContains(t, excluded, node.Id)
+				require.True(t, node.Id[0] >= 3, "node without enough free space")
+				if node.Id[0] > 20 {
+					newNodes++
+				}
+
+				if distinctIP {
+					require.False(t, nets[node.LastIp], "duplicate network")
+					require.NotEqual(t, "10.0.0.0", node.LastIp, "network of excluded node")
+					require.NotEqual(t, "10.0.11.0", node.LastIp, "network of excluded node")
+				}
+				nets[node.LastIp] = true
+			}
+			require.Equal(t, 2, newNodes)
+		}
+	}
+
+	cache := overlay.NewNodeSelectionCache(zaptest.NewLogger(t), db, overlay.NodeSelectionConfig{
+		DistinctIP: true,
+	}, overlay.NodeSelectionCacheConfig{Staleness: time.Hour})
+	require.NoError(t, cache.Refresh(ctx))
+
+	// there are only 11 distinct reputable networks
+	nodes, err := cache.Select(ctx, overlay.FindStorageNodesRequest{RequestedCount: 12})
+	require.True(t, overlay.ErrNotEnoughNodes.Has(err))
+	require.Len(t, nodes, 11)
+}
+
+func TestNodeSelectionCacheMatchesDatabase(t *testing.T) {
+	satellitedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db satellite.DB) {
+		cache := db.OverlayCache()
+
+		selectionConfig := overlay.NodeSelectionConfig{
+			AuditCount:   1,
+			OnlineWindow: time.Hour,
+		}
+
+		var all storj.NodeIDList
+		for i := 0; i < 20; i++ {
+			nodeID := storj.NodeID{byte(i + 1)}
+			all = append(all, nodeID)
+			err := cache.UpdateCheckIn(ctx, overlay.NodeCheckInInfo{
+				NodeID:   nodeID,
+				Address:  &pb.NodeAddress{Address: fmt.Sprintf("10.0.%d.1:7777", i)},
+				LastIP:   fmt.Sprintf("10.0.%d.0", i/2),
+				IsUp:     true,
+				Capacity: &pb.NodeCapacity{FreeBandwidth: int64(i), FreeDisk: int64(i)},
+				Version:  &pb.NodeVersion{Version: "v1.0.0"},
+			}, time.Now(), overlay.NodeSelectionConfig{})
+			require.NoError(t, err)
+
+			// odd nodes are reputable
+			if i%2 == 1 {
+				_, err = cache.UpdateStats(ctx, &overlay.UpdateRequest{
+					NodeID:       nodeID,
+					IsUp:         true,
+					AuditSuccess: true,
+					AuditLambda:  1, AuditWeight: 1, AuditDQ: 0.5,
+				})
+				require.NoError(t, err)
+			}
+		}
+
+		// disqualified and exiting nodes are never selected
+		require.NoError(t, cache.DisqualifyNode(ctx, all[0]))
+		_, err := cache.UpdateExitStatus(ctx, &overlay.ExitStatusRequest{
+			NodeID:          all[1],
+			ExitInitiatedAt: time.Now(),
+		})
+		require.NoError(t, err)
+
+		service := overlay.NewService(zaptest.NewLogger(t), cache, overlay.Config{Node: selectionConfig})
+
+		for _, tt := range []struct {
+			newNodePercentage float64
+			distinctIP        bool
+			freeDisk          int64
+			excluded          storj.NodeIDList
+		}{
+			{0, false, 0, nil},
+			{0, true, 0, nil},
+			{0, false, 10, nil},
+			{0, false, 0, all[2:6]},
+			{0, true, 5, all[2:3]},
+			{1, false, 0, nil},
+			{1, true, 0, nil},
+		} {
+			preferences := selectionConfig
+			preferences.NewNodePercentage = tt.newNodePercentage
+			preferences.DistinctIP = tt.distinctIP
+
+			nodeSelectionCache := overlay.NewNodeSelectionCache(zaptest.NewLogger(t), cache, preferences,
+				overlay.NodeSelectionCacheConfig{Staleness: time.Hour})
+			require.NoError(t, nodeSelectionCache.Refresh(ctx))
+
+			// request every node, so both selections must return all qualifying nodes
+			req := overlay.FindStorageNodesRequest{
+				RequestedCount: len(all),
+				FreeBandwidth:  tt.freeDisk,
+				FreeDisk:       tt.freeDisk,
+				ExcludedNodes:  tt.excluded,
+			}
+
+			fromDatabase, databaseErr := service.FindStorageNodesWithPreferences(ctx, req, &preferences)
+			fromCache, cacheErr := nodeSelectionCache.Select(ctx, req)
+			require.Equal(t, databaseErr != nil, cacheErr != nil, "%+v", tt)
+
+			if tt.distinctIP {
+				// the chosen node of every network is random, so compare the networks
+				require.ElementsMatch(t, lastNets(fromDatabase), lastNets(fromCache), "%+v", tt)
+			} else {
+				require.Equal(t, sortedIDs(fromDatabase), sortedIDs(fromCache), "%+v", tt)
+			}
+		}
+	})
+}
+
+func sortedIDs(nodes []*pb.Node) storj.NodeIDList {
+	var ids storj.NodeIDList
+	for _, node := range nodes {
+		ids = append(ids, node.Id)
+	}
+	sort.Sort(ids)
+	return ids
+}
+
+func lastNets(nodes []*pb.Node) []string {
+	var nets []string
+	for _, node := range nodes {
+		nets = append(nets, node.LastIp)
+	}
+	return nets
+}
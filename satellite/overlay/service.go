@@ -40,6 +40,8 @@ type DB interface {
 	SelectStorageNodes(ctx context.Context, count int, criteria *NodeCriteria) ([]*pb.Node, error)
 	// SelectNewStorageNodes looks up nodes based on new node criteria
 	SelectNewStorageNodes(ctx context.Context, count int, criteria *NodeCriteria) ([]*pb.Node, error)
+	// SelectAllStorageNodesUpload returns all nodes that qualify to store data, organized as reputable nodes and new nodes
+	SelectAllStorageNodesUpload(ctx context.Context, selectionConfig NodeSelectionConfig) (reputable, new []*SelectedNode, err error)
 
 	// Get looks up the node by nodeID
 	Get(ctx context.Context, nodeID storj.NodeID) (*NodeDossier, error)
@@ -51,6 +53,8 @@ type DB interface {
 	KnownReliable(ctx context.Context, onlineWindow time.Duration, nodeIDs storj.NodeIDList) ([]*pb.Node, error)
 	// Reliable returns all nodes that are reliable
 	Reliable(context.Context, *NodeCriteria) (storj.NodeIDList, error)
+	// Disqualified returns all nodes that are disqualified
+	Disqualified(context.Context) (storj.NodeIDList, error)
 	// Paginate will page through the database nodes
 	Paginate(ctx context.Context, offset int64, limit int) ([]*NodeDossier, bool, error)
 	// PaginateQualified will page through the qualified nodes
@@ -105,6 +109,7 @@ type NodeCheckInInfo struct {
 	Operator *pb.NodeOperator
 	Capacity *pb.NodeCapacity
 	Version  *pb.NodeVersion
+	Location NodeLocation
 }
 
 // FindStorageNodesRequest defines easy request parameters.
@@ -128,6 +133,7 @@ type NodeCriteria struct {
 	MinimumVersion string // semver or empty
 	OnlineWindow   time.Duration
 	DistinctIP     bool
+	Placement      PlacementConfig
 }
 
 // UpdateRequest is used to update a node status.
@@ -205,6 +211,8 @@ type Service struct {
 	log    *zap.Logger
 	db     DB
 	config Config
+
+	SelectionCache *NodeSelectionCache
 }
 
 // NewService returns a new Service
@@ -213,6 +221,8 @@ func NewService(log *zap.Logger, db DB, config Config) *Service {
 		log:    log,
 		db:     db,
 		config: config,
+
+		SelectionCache: NewNodeSelectionCache(log.Named("selection-cache"), db, config.Node, config.NodeSelectionCache),
 	}
 }
 
@@ -258,6 +268,31 @@ func (service *Service) FindStorageNodes(ctx context.Context, req FindStorageNod
 	return service.FindStorageNodesWithPreferences(ctx, req, &service.config.Node)
 }
 
+// FindStorageNodesForUpload searches for nodes that meet the provided requirements for an upload.
+// Nodes are selected from the node selection cache, unless it is disabled or stale. When the
+// cache does not contain enough nodes, for example because nodes joined after the last refresh,
+// the nodes are selected from the database as well.
+func (service *Service) FindStorageNodesForUpload(ctx context.Context, req FindStorageNodesRequest) (_ []*pb.Node, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if service.config.NodeSelectionCache.Disabled {
+		return service.FindStorageNodes(ctx, req)
+	}
+
+	nodes, err := service.SelectionCache.Select(ctx, req)
+	switch {
+	case errSelectionCacheStale.Has(err):
+		mon.Event("node_selection_cache_stale")
+		service.log.Debug("selecting nodes from the database", zap.Error(err))
+		return service.FindStorageNodes(ctx, req)
+	case ErrNotEnoughNodes.Has(err):
+		mon.Event("node_selection_cache_not_enough_nodes")
+		return service.FindStorageNodes(ctx, req)
+	}
+	mon.Event("node_selection_cache_hit")
+	return nodes, err
+}
+
 // FindStorageNodesWithPreferences searches the overlay network for nodes that meet the provided criteria
 func (service *Service) FindStorageNodesWithPreferences(ctx context.Context, req FindStorageNodesRequest, preferences *NodeSelectionConfig) (nodes []*pb.Node, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -294,6 +329,7 @@ func (service *Service) FindStorageNodesWithPreferences(ctx context.Context, req
 			MinimumVersion: preferences.MinimumVersion,
 			OnlineWindow:   preferences.OnlineWindow,
 			DistinctIP:     preferences.DistinctIP,
+			Placement:      preferences.Placement,
 		})
 		if err != nil {
 			return nil, Error.Wrap(err)
@@ -318,6 +354,7 @@ func (service *Service) FindStorageNodesWithPreferences(ctx context.Context, req
 		MinimumVersion: preferences.MinimumVersion,
 		OnlineWindow:   preferences.OnlineWindow,
 		DistinctIP:     preferences.DistinctIP,
+		Placement:      preferences.Placement,
 	}
 	reputableNodes, err := service.db.SelectStorageNodes(ctx, reputableNodeCount-len(newNodes), &criteria)
 	if err != nil {
@@ -367,6 +404,12 @@ func (service *Service) Reliable(ctx context.Context) (nodes storj.NodeIDList, e
 	return service.db.Reliable(ctx, criteria)
 }
 
+// Disqualified returns all nodes that are disqualified.
+func (service *Service) Disqualified(ctx context.Context) (nodes storj.NodeIDList, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return service.db.Disqualified(ctx)
+}
+
 // Put adds a node id and proto definition into the overlay.
 func (service *Service) Put(ctx context.Context, nodeID storj.NodeID, value pb.Node) (err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -17,8 +17,10 @@ import (
 	"storj.io/storj/satellite/accounting/reportedrollup"
 	"storj.io/storj/satellite/accounting/rollup"
 	"storj.io/storj/satellite/accounting/tally"
+	"storj.io/storj/satellite/admin"
 	"storj.io/storj/satellite/attribution"
 	"storj.io/storj/satellite/audit"
+	"storj.io/storj/satellite/bucketlifecycle"
 	"storj.io/storj/satellite/console"
 	"storj.io/storj/satellite/console/consoleweb"
 	"storj.io/storj/satellite/contact"
@@ -82,12 +84,22 @@ type DB interface {
 	Containment() audit.Containment
 	// Buckets returns the database to interact with buckets
 	Buckets() metainfo.BucketsDB
+	// SharedSegments returns the database for segments shared between copied objects
+	SharedSegments() metainfo.SharedSegmentsDB
+	// LoopCheckpoints returns the database for the progress of the metainfo loop
+	LoopCheckpoints() metainfo.LoopCheckpointsDB
+	// ObjectVersions returns the database for version numbers of versioned objects
+	ObjectVersions() metainfo.ObjectVersionsDB
 	// GracefulExit returns database for graceful exit
 	GracefulExit() gracefulexit.DB
+	// AdminAuditRecords returns database for the audit records of the satellite admin
+	AdminAuditRecords() admin.AuditRecords
 	// StripeCoinPayments returns stripecoinpayments database.
 	StripeCoinPayments() stripecoinpayments.DB
 	// DowntimeTracking returns database for downtime tracking
 	DowntimeTracking() downtime.DB
+	// GarbageCollection returns database for garbage collection retain filters
+	GarbageCollection() gc.DB
 }
 
 // Config is the global config satellite
@@ -108,6 +120,8 @@ type Config struct {
 
 	GarbageCollection gc.Config
 
+	BucketLifecycle bucketlifecycle.Config
+
 	DBCleanup dbcleanup.Config
 
 	Tally          tally.Config
@@ -123,6 +137,8 @@ type Config struct {
 
 	Console consoleweb.Config
 
+	Admin admin.Config
+
 	Marketing marketingweb.Config
 
 	Version version_checker.Config
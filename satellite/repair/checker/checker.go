@@ -18,6 +18,7 @@ import (
 	"storj.io/common/sync2"
 	"storj.io/storj/satellite/metainfo"
 	"storj.io/storj/satellite/overlay"
+	"storj.io/storj/satellite/repair"
 	"storj.io/storj/satellite/repair/irreparable"
 	"storj.io/storj/satellite/repair/queue"
 )
@@ -43,7 +44,15 @@ type durabilityStats struct {
 	remoteSegmentsChecked       int64
 	remoteSegmentsNeedingRepair int64
 	remoteSegmentsLost          int64
-	remoteSegmentInfo           []string
+	remoteSegmentInfo           map[string]struct{}
+}
+
+// addLostSegment adds the lost segment to the set of lost segments.
+func (stats *durabilityStats) addLostSegment(lostSegInfo string) {
+	if stats.remoteSegmentInfo == nil {
+		stats.remoteSegmentInfo = make(map[string]struct{})
+	}
+	stats.remoteSegmentInfo[lostSegInfo] = struct{}{}
 }
 
 // Checker contains the information needed to do checks for missing pieces
@@ -135,16 +144,6 @@ func (checker *Checker) IdentifyInjuredSegments(ctx context.Context) (err error)
 	return nil
 }
 
-// checks for a string in slice
-func contains(a []string, x string) bool {
-	for _, n := range a {
-		if x == n {
-			return true
-		}
-	}
-	return false
-}
-
 func (checker *Checker) updateIrreparableSegmentStatus(ctx context.Context, pointer *pb.Pointer, path string) (err error) {
 	// TODO figure out how to reduce duplicate code between here and checkerObs.RemoteSegment
 	defer mon.Task()(&ctx)(&err)
@@ -171,11 +170,17 @@ func (checker *Checker) updateIrreparableSegmentStatus(ctx context.Context, poin
 	// minimum required pieces in redundancy
 	// except for the case when the repair and success thresholds are the same (a case usually seen during testing)
 	if numHealthy >= redundancy.MinReq && numHealthy <= redundancy.RepairThreshold && numHealthy < redundancy.SuccessThreshold {
+		numOffline, err := checker.nodestate.OfflinePieces(ctx, pointer.CreationDate, pieces)
+		if err != nil {
+			return errs.Combine(Error.New("error getting offline pieces"), err)
+		}
+		segmentHealth := repair.SegmentHealth(int(numHealthy), numOffline, int(redundancy.MinReq), int(redundancy.SuccessThreshold))
+
 		err = checker.repairQueue.Insert(ctx, &pb.InjuredSegment{
 			Path:         []byte(path),
 			LostPieces:   missingPieces,
 			InsertedTime: time.Now().UTC(),
-		})
+		}, segmentHealth)
 		if err != nil {
 			return errs.Combine(Error.New("error adding injured segment to queue"), err)
 		}
@@ -205,7 +210,8 @@ func (checker *Checker) updateIrreparableSegmentStatus(ctx context.Context, poin
 	return nil
 }
 
-var _ metainfo.Observer = (*checkerObserver)(nil)
+var _ metainfo.ForkingObserver = (*checkerObserver)(nil)
+var _ metainfo.CheckpointObserver = (*checkerObserver)(nil)
 
 // checkerObserver implements the metainfo loop Observer interface
 //
@@ -255,11 +261,19 @@ func (obs *checkerObserver) RemoteSegment(ctx context.Context, path metainfo.Sco
 	// except for the case when the repair and success thresholds are the same (a case usually seen during testing)
 	if numHealthy >= redundancy.MinReq && numHealthy <= repairThreshold && numHealthy < redundancy.SuccessThreshold {
 		obs.monStats.remoteSegmentsNeedingRepair++
+
+		numOffline, err := obs.nodestate.OfflinePieces(ctx, pointer.CreationDate, pieces)
+		if err != nil {
+			return errs.Combine(Error.New("error getting offline pieces"), err)
+		}
+		segmentHealth := repair.SegmentHealth(int(numHealthy), numOffline, int(redundancy.MinReq), int(redundancy.SuccessThreshold))
+		mon.FloatVal("checker_injured_segment_health").Observe(segmentHealth)
+
 		err = obs.repairQueue.Insert(ctx, &pb.InjuredSegment{
 			Path:         []byte(path.Raw),
 			LostPieces:   missingPieces,
 			InsertedTime: time.Now().UTC(),
-		})
+		}, segmentHealth)
 		if err != nil {
 			obs.log.Error("error adding injured segment to queue", zap.Error(err))
 			return nil
@@ -283,9 +297,7 @@ func (obs *checkerObserver) RemoteSegment(ctx context.Context, path metainfo.Sco
 
 			// TODO: is this correct? split splits all path components, but it's only using the third.
 			lostSegInfo := storj.JoinPaths(project, bucketName, segmentpath)
-			if !contains(obs.monStats.remoteSegmentInfo, lostSegInfo) {
-				obs.monStats.remoteSegmentInfo = append(obs.monStats.remoteSegmentInfo, lostSegInfo)
-			}
+			obs.monStats.addLostSegment(lostSegInfo)
 		}
 
 		var segmentAge time.Duration
@@ -330,6 +342,92 @@ func (obs *checkerObserver) InlineSegment(ctx context.Context, path metainfo.Sco
 	return nil
 }
 
+// Fork returns an observer with empty stats for a range of the metainfo loop.
+func (obs *checkerObserver) Fork(ctx context.Context) (_ metainfo.Observer, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return &checkerObserver{
+		repairQueue:    obs.repairQueue,
+		irrdb:          obs.irrdb,
+		nodestate:      obs.nodestate,
+		overrideRepair: obs.overrideRepair,
+		log:            obs.log,
+	}, nil
+}
+
+// Join adds the stats of a forked observer to the observer.
+func (obs *checkerObserver) Join(ctx context.Context, forked metainfo.Observer) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	other, ok := forked.(*checkerObserver)
+	if !ok {
+		return Error.New("unexpected observer type %T", forked)
+	}
+
+	obs.monStats.objectsChecked += other.monStats.objectsChecked
+	obs.monStats.remoteSegmentsChecked += other.monStats.remoteSegmentsChecked
+	obs.monStats.remoteSegmentsNeedingRepair += other.monStats.remoteSegmentsNeedingRepair
+	obs.monStats.remoteSegmentsLost += other.monStats.remoteSegmentsLost
+	for lostSegInfo := range other.monStats.remoteSegmentInfo {
+		obs.monStats.addLostSegment(lostSegInfo)
+	}
+	return nil
+}
+
+// CheckpointName identifies the state of the checker observer.
+func (obs *checkerObserver) CheckpointName() string {
+	return "checker"
+}
+
+// checkerState is the checkpointed state of the checker observer. Segments
+// which need repair have already been added to the repair queue.
+type checkerState struct {
+	ObjectsChecked              int64
+	RemoteSegmentsChecked       int64
+	RemoteSegmentsNeedingRepair int64
+	RemoteSegmentsLost          int64
+	RemoteSegmentInfo           []string
+}
+
+// Checkpoint returns a copy of the stats collected so far.
+func (obs *checkerObserver) Checkpoint(ctx context.Context) (_ interface{}, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	remoteSegmentInfo := make([]string, 0, len(obs.monStats.remoteSegmentInfo))
+	for lostSegInfo := range obs.monStats.remoteSegmentInfo {
+		remoteSegmentInfo = append(remoteSegmentInfo, lostSegInfo)
+	}
+
+	return checkerState{
+		ObjectsChecked:              obs.monStats.objectsChecked,
+		RemoteSegmentsChecked:       obs.monStats.remoteSegmentsChecked,
+		RemoteSegmentsNeedingRepair: obs.monStats.remoteSegmentsNeedingRepair,
+		RemoteSegmentsLost:          obs.monStats.remoteSegmentsLost,
+		RemoteSegmentInfo:           remoteSegmentInfo,
+	}, nil
+}
+
+// Restore replaces the stats with the ones of a checkpoint.
+func (obs *checkerObserver) Restore(ctx context.Context, decode func(state interface{}) error) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var restored checkerState
+	if err := decode(&restored); err != nil {
+		return Error.Wrap(err)
+	}
+
+	obs.monStats = durabilityStats{
+		objectsChecked:              restored.ObjectsChecked,
+		remoteSegmentsChecked:       restored.RemoteSegmentsChecked,
+		remoteSegmentsNeedingRepair: restored.RemoteSegmentsNeedingRepair,
+		remoteSegmentsLost:          restored.RemoteSegmentsLost,
+	}
+	for _, lostSegInfo := range restored.RemoteSegmentInfo {
+		obs.monStats.addLostSegment(lostSegInfo)
+	}
+	return nil
+}
+
 // IrreparableProcess iterates over all items in the irreparabledb. If an item can
 // now be repaired then it is added to a worker queue.
 func (checker *Checker) IrreparableProcess(ctx context.Context) (err error) {
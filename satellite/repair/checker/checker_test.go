@@ -10,12 +10,14 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 
 	"storj.io/common/pb"
 	"storj.io/common/storj"
 	"storj.io/common/testcontext"
 	"storj.io/common/testrand"
 	"storj.io/storj/private/testplanet"
+	"storj.io/storj/satellite"
 	"storj.io/storj/storage"
 )
 
@@ -72,6 +74,53 @@ func TestIdentifyInjuredSegments(t *testing.T) {
 	})
 }
 
+func TestIdentifyInjuredSegmentsParallel(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 0,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: func(log *zap.Logger, index int, config *satellite.Config) {
+				config.Metainfo.Loop.Workers = 4
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		checker := planet.Satellites[0].Repair.Checker
+		repairQueue := planet.Satellites[0].DB.RepairQueue()
+
+		checker.Loop.Pause()
+		planet.Satellites[0].Repair.Repairer.Loop.Pause()
+
+		rs := &pb.RedundancyScheme{
+			MinReq:           int32(2),
+			RepairThreshold:  int32(3),
+			SuccessThreshold: int32(4),
+			Total:            int32(5),
+			ErasureShareSize: int32(256),
+		}
+
+		// spread the pointers over projects in different ranges
+		expected := map[string]bool{}
+		for x := 0; x < 16; x++ {
+			projectID := testrand.UUID()
+			pointerPathPrefix := storj.JoinPaths(projectID.String(), "l", "bucket") + "/"
+
+			insertPointer(ctx, t, planet, rs, pointerPathPrefix+"a", false)
+			if x%2 == 0 {
+				insertPointer(ctx, t, planet, rs, pointerPathPrefix+"b", true)
+				expected[pointerPathPrefix+"b"] = true
+			}
+		}
+
+		checker.Loop.TriggerWait()
+
+		injuredSegments, err := repairQueue.SelectN(ctx, 100)
+		require.NoError(t, err)
+		require.Len(t, injuredSegments, len(expected))
+		for _, injuredSegment := range injuredSegments {
+			require.True(t, expected[string(injuredSegment.Path)], string(injuredSegment.Path))
+		}
+	})
+}
+
 func TestIdentifyIrreparableSegments(t *testing.T) {
 	testplanet.Run(t, testplanet.Config{
 		SatelliteCount: 1, StorageNodeCount: 3, UplinkCount: 0,
@@ -27,8 +27,9 @@ type ReliabilityCache struct {
 
 // reliabilityState
 type reliabilityState struct {
-	reliable map[storj.NodeID]struct{}
-	created  time.Time
+	reliable     map[storj.NodeID]struct{}
+	disqualified map[storj.NodeID]struct{}
+	created      time.Time
 }
 
 // NewReliabilityCache creates a new reliability checking cache.
@@ -51,6 +52,44 @@ func (cache *ReliabilityCache) LastUpdate() time.Time {
 func (cache *ReliabilityCache) MissingPieces(ctx context.Context, created time.Time, pieces []*pb.RemotePiece) (_ []int32, err error) {
 	defer mon.Task()(&ctx)(&err)
 
+	state, err := cache.loadFresh(ctx, created)
+	if err != nil {
+		return nil, err
+	}
+
+	var unreliable []int32
+	for _, piece := range pieces {
+		if _, ok := state.reliable[piece.NodeId]; !ok {
+			unreliable = append(unreliable, piece.PieceNum)
+		}
+	}
+	return unreliable, nil
+}
+
+// OfflinePieces returns the number of pieces that are unreliable only because
+// their nodes are offline. Those nodes are not disqualified and may come back.
+func (cache *ReliabilityCache) OfflinePieces(ctx context.Context, created time.Time, pieces []*pb.RemotePiece) (_ int, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	state, err := cache.loadFresh(ctx, created)
+	if err != nil {
+		return 0, err
+	}
+
+	var offline int
+	for _, piece := range pieces {
+		if _, ok := state.reliable[piece.NodeId]; ok {
+			continue
+		}
+		if _, ok := state.disqualified[piece.NodeId]; !ok {
+			offline++
+		}
+	}
+	return offline, nil
+}
+
+// loadFresh returns a state that is not older than created and the staleness period.
+func (cache *ReliabilityCache) loadFresh(ctx context.Context, created time.Time) (_ *reliabilityState, err error) {
 	// This code is designed to be very fast in the case where a refresh is not needed: just an
 	// atomic load from rarely written to bit of shared memory. The general strategy is to first
 	// read if the state suffices to answer the query. If not (due to it not existing, being
@@ -71,14 +110,7 @@ func (cache *ReliabilityCache) MissingPieces(ctx context.Context, created time.T
 			return nil, err
 		}
 	}
-
-	var unreliable []int32
-	for _, piece := range pieces {
-		if _, ok := state.reliable[piece.NodeId]; !ok {
-			unreliable = append(unreliable, piece.PieceNum)
-		}
-	}
-	return unreliable, nil
+	return state, nil
 }
 
 // Refresh refreshes the cache.
@@ -101,13 +133,22 @@ func (cache *ReliabilityCache) refreshLocked(ctx context.Context) (_ *reliabilit
 		return nil, Error.Wrap(err)
 	}
 
+	disqualified, err := cache.overlay.Disqualified(ctx)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
 	state := &reliabilityState{
-		created:  time.Now(),
-		reliable: make(map[storj.NodeID]struct{}, len(nodes)),
+		created:      time.Now(),
+		reliable:     make(map[storj.NodeID]struct{}, len(nodes)),
+		disqualified: make(map[storj.NodeID]struct{}, len(disqualified)),
 	}
 	for _, id := range nodes {
 		state.reliable[id] = struct{}{}
 	}
+	for _, id := range disqualified {
+		state.disqualified[id] = struct{}{}
+	}
 
 	cache.state.Store(state)
 	return state, nil
@@ -50,3 +50,9 @@ func (fakeOverlayDB) Reliable(context.Context, *overlay.NodeCriteria) (storj.Nod
 		testrand.NodeID(),
 	}, nil
 }
+
+func (fakeOverlayDB) Disqualified(context.Context) (storj.NodeIDList, error) {
+	return storj.NodeIDList{
+		testrand.NodeID(),
+	}, nil
+}
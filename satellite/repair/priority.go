@@ -0,0 +1,24 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package repair
+
+// offlinePieceWeight is how much a piece on an offline, but not disqualified,
+// node counts towards segment health compared to a piece on a reliable node.
+// Such nodes may come back online, so their pieces are not entirely lost.
+const offlinePieceWeight = 0.5
+
+// SegmentHealth returns a value describing how close a segment is to being lost.
+// Lower values are riskier. A segment with exactly minPieces healthy pieces has
+// health 0, a segment at the success threshold has health 1, and a segment below
+// minPieces has a negative health.
+//
+// numOffline is the number of pieces stored on nodes that are offline, but not
+// disqualified.
+func SegmentHealth(numHealthy, numOffline, minPieces, successThreshold int) float64 {
+	span := successThreshold - minPieces
+	if span <= 0 {
+		span = 1
+	}
+	return (float64(numHealthy-minPieces) + offlinePieceWeight*float64(numOffline)) / float64(span)
+}
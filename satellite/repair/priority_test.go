@@ -0,0 +1,34 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package repair_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/storj/satellite/repair"
+)
+
+func TestSegmentHealth(t *testing.T) {
+	const minPieces, successThreshold = 29, 80
+
+	assert.Equal(t, 0.0, repair.SegmentHealth(29, 0, minPieces, successThreshold))
+	assert.Equal(t, 1.0, repair.SegmentHealth(80, 0, minPieces, successThreshold))
+	assert.True(t, repair.SegmentHealth(28, 0, minPieces, successThreshold) < 0)
+
+	// a segment one piece above the minimum is riskier than one barely below the repair threshold
+	assert.True(t, repair.SegmentHealth(30, 0, minPieces, successThreshold) < repair.SegmentHealth(52, 0, minPieces, successThreshold))
+
+	// pieces on offline nodes make a segment healthier than lost pieces,
+	// but less healthy than pieces on reliable nodes
+	lost := repair.SegmentHealth(35, 0, minPieces, successThreshold)
+	offline := repair.SegmentHealth(35, 5, minPieces, successThreshold)
+	reliable := repair.SegmentHealth(40, 0, minPieces, successThreshold)
+	assert.True(t, lost < offline)
+	assert.True(t, offline < reliable)
+
+	// degenerate redundancy schemes do not divide by zero
+	assert.Equal(t, 1.0, repair.SegmentHealth(5, 0, 4, 4))
+}
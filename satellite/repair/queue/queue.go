@@ -5,6 +5,7 @@ package queue
 
 import (
 	"context"
+	"time"
 
 	"storj.io/common/pb"
 )
@@ -14,9 +15,10 @@ import (
 //
 // architecture: Database
 type RepairQueue interface {
-	// Insert adds an injured segment.
-	Insert(ctx context.Context, s *pb.InjuredSegment) error
-	// Select gets an injured segment.
+	// Insert adds an injured segment with the given health, or updates the
+	// health of the segment when it is already queued.
+	Insert(ctx context.Context, s *pb.InjuredSegment, segmentHealth float64) error
+	// Select gets the injured segment with the lowest health.
 	Select(ctx context.Context) (*pb.InjuredSegment, error)
 	// Delete removes an injured segment.
 	Delete(ctx context.Context, s *pb.InjuredSegment) error
@@ -24,4 +26,13 @@ type RepairQueue interface {
 	SelectN(ctx context.Context, limit int) ([]pb.InjuredSegment, error)
 	// Count counts the number of segments in the repair queue.
 	Count(ctx context.Context) (count int, err error)
+	// List lists queued segments ordered by path, starting after cursor.
+	List(ctx context.Context, cursor []byte, limit int) (segments []QueuedSegment, more bool, err error)
+}
+
+// QueuedSegment is an injured segment together with its repair queue state.
+type QueuedSegment struct {
+	Segment       pb.InjuredSegment
+	SegmentHealth float64
+	Attempted     *time.Time
 }
@@ -28,7 +28,7 @@ func TestUntilEmpty(t *testing.T) {
 		for i := 0; i < 100; i++ {
 			path := "/path/" + string(i)
 			injuredSeg := &pb.InjuredSegment{Path: []byte(path)}
-			err := repairQueue.Insert(ctx, injuredSeg)
+			err := repairQueue.Insert(ctx, injuredSeg, 10)
 			require.NoError(t, err)
 			pathsMap[path] = 0
 		}
@@ -60,7 +60,7 @@ func TestOrder(t *testing.T) {
 
 		for _, path := range [][]byte{oldRepairPath, recentRepairPath, nullPath, olderRepairPath} {
 			injuredSeg := &pb.InjuredSegment{Path: path}
-			err := repairQueue.Insert(ctx, injuredSeg)
+			err := repairQueue.Insert(ctx, injuredSeg, 10)
 			require.NoError(t, err)
 		}
 
@@ -113,6 +113,69 @@ func TestOrder(t *testing.T) {
 	})
 }
 
+// TestOrderHealth ensures that segments with lower health are selected first,
+// regardless of insertion order, and that reinserting updates the health.
+func TestOrderHealth(t *testing.T) {
+	satellitedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db satellite.DB) {
+		repairQueue := db.RepairQueue()
+
+		barelyInjuredPath := []byte("/path/barely")
+		riskyPath := []byte("/path/risky")
+		lostSoonPath := []byte("/path/lostsoon")
+
+		require.NoError(t, repairQueue.Insert(ctx, &pb.InjuredSegment{Path: barelyInjuredPath}, 0.9))
+		require.NoError(t, repairQueue.Insert(ctx, &pb.InjuredSegment{Path: riskyPath}, 0.5))
+		require.NoError(t, repairQueue.Insert(ctx, &pb.InjuredSegment{Path: lostSoonPath}, 0.6))
+
+		// the checker found lostSoonPath in a worse state on the next iteration
+		require.NoError(t, repairQueue.Insert(ctx, &pb.InjuredSegment{Path: lostSoonPath}, 0.1))
+
+		count, err := repairQueue.Count(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 3, count)
+
+		for _, expected := range [][]byte{lostSoonPath, riskyPath, barelyInjuredPath} {
+			injuredSeg, err := repairQueue.Select(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, string(expected), string(injuredSeg.Path))
+		}
+	})
+}
+
+func TestList(t *testing.T) {
+	satellitedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db satellite.DB) {
+		repairQueue := db.RepairQueue()
+
+		paths := []string{"/path/a", "/path/b", "/path/c", "/path/d", "/path/e"}
+		for i, path := range paths {
+			err := repairQueue.Insert(ctx, &pb.InjuredSegment{Path: []byte(path)}, float64(i))
+			require.NoError(t, err)
+		}
+
+		// the most at risk segment is marked as attempted
+		_, err := repairQueue.Select(ctx)
+		require.NoError(t, err)
+
+		segments, more, err := repairQueue.List(ctx, nil, 3)
+		require.NoError(t, err)
+		require.True(t, more)
+		require.Len(t, segments, 3)
+		for i, segment := range segments {
+			assert.Equal(t, paths[i], string(segment.Segment.Path))
+			assert.Equal(t, float64(i), segment.SegmentHealth)
+		}
+		assert.NotNil(t, segments[0].Attempted)
+		assert.Nil(t, segments[1].Attempted)
+
+		segments, more, err = repairQueue.List(ctx, segments[2].Segment.Path, 3)
+		require.NoError(t, err)
+		require.False(t, more)
+		require.Len(t, segments, 2)
+		assert.Equal(t, paths[3], string(segments[0].Segment.Path))
+		assert.Equal(t, paths[4], string(segments[1].Segment.Path))
+	})
+}
+
 func TestCount(t *testing.T) {
 	satellitedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db satellite.DB) {
 		repairQueue := db.RepairQueue()
@@ -123,7 +186,7 @@ func TestCount(t *testing.T) {
 		for i := 0; i < numSegments; i++ {
 			path := "/path/" + string(i)
 			injuredSeg := &pb.InjuredSegment{Path: []byte(path)}
-			err := repairQueue.Insert(ctx, injuredSeg)
+			err := repairQueue.Insert(ctx, injuredSeg, 10)
 			require.NoError(t, err)
 			pathsMap[path] = 0
 		}
@@ -0,0 +1,140 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package repairer
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/common/pb"
+	"storj.io/storj/satellite/internalpb"
+	"storj.io/storj/satellite/metainfo"
+	"storj.io/storj/satellite/overlay"
+	"storj.io/storj/satellite/repair"
+	"storj.io/storj/satellite/repair/queue"
+)
+
+// Inspector is a private endpoint for inspecting and manipulating the repair queue.
+//
+// architecture: Endpoint
+type Inspector struct {
+	log      *zap.Logger
+	queue    queue.RepairQueue
+	metainfo *metainfo.Service
+	overlay  *overlay.Service
+	repairer *SegmentRepairer
+}
+
+// NewInspector creates a repair queue Inspector.
+func NewInspector(log *zap.Logger, queue queue.RepairQueue, metainfo *metainfo.Service, overlay *overlay.Service, repairer *SegmentRepairer) *Inspector {
+	return &Inspector{
+		log:      log,
+		queue:    queue,
+		metainfo: metainfo,
+		overlay:  overlay,
+		repairer: repairer,
+	}
+}
+
+// ListRepairQueue returns a page of queued injured segments ordered by path.
+func (inspector *Inspector) ListRepairQueue(ctx context.Context, req *internalpb.ListRepairQueueRequest) (_ *internalpb.ListRepairQueueResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	segments, more, err := inspector.queue.List(ctx, req.CursorPath, int(req.Limit))
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	resp := &internalpb.ListRepairQueueResponse{More: more}
+	for _, segment := range segments {
+		resp.Segments = append(resp.Segments, &internalpb.QueuedSegment{
+			Path:          segment.Segment.Path,
+			LostPieces:    segment.Segment.LostPieces,
+			SegmentHealth: segment.SegmentHealth,
+			InsertedAt:    segment.Segment.InsertedTime,
+			AttemptedAt:   segment.Attempted,
+		})
+	}
+	return resp, nil
+}
+
+// AddRepairQueue forces a segment into the repair queue, regardless of its health.
+//
+// Pieces on offline nodes are not taken into account when calculating the health
+// of a manually queued segment, so it is repaired sooner rather than later.
+func (inspector *Inspector) AddRepairQueue(ctx context.Context, req *internalpb.AddRepairQueueRequest) (_ *internalpb.AddRepairQueueResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	path := string(req.Path)
+	pointer, err := inspector.metainfo.Get(ctx, path)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	if pointer.GetType() != pb.Pointer_REMOTE {
+		return nil, Error.New("cannot repair inline segment")
+	}
+
+	pieces := pointer.GetRemote().GetRemotePieces()
+	missingPieces, err := inspector.overlay.GetMissingPieces(ctx, pieces)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	redundancy := pointer.GetRemote().GetRedundancy()
+	numHealthy := len(pieces) - len(missingPieces)
+	segmentHealth := repair.SegmentHealth(numHealthy, 0, int(redundancy.GetMinReq()), int(redundancy.GetSuccessThreshold()))
+
+	err = inspector.queue.Insert(ctx, &pb.InjuredSegment{
+		Path:         req.Path,
+		LostPieces:   missingPieces,
+		InsertedTime: time.Now().UTC(),
+	}, segmentHealth)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	inspector.log.Info("segment manually added to repair queue",
+		zap.Binary("Segment", req.Path),
+		zap.Float64("Segment Health", segmentHealth))
+
+	return &internalpb.AddRepairQueueResponse{SegmentHealth: segmentHealth}, nil
+}
+
+// RemoveRepairQueue removes a segment from the repair queue.
+func (inspector *Inspector) RemoveRepairQueue(ctx context.Context, req *internalpb.RemoveRepairQueueRequest) (_ *internalpb.RemoveRepairQueueResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = inspector.queue.Delete(ctx, &pb.InjuredSegment{Path: req.Path})
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	inspector.log.Info("segment manually removed from repair queue", zap.Binary("Segment", req.Path))
+
+	return &internalpb.RemoveRepairQueueResponse{}, nil
+}
+
+// RepairSegment repairs a segment immediately. The segment is removed from the
+// repair queue when the repair does not need to be retried.
+func (inspector *Inspector) RepairSegment(ctx context.Context, req *internalpb.RepairSegmentRequest) (_ *internalpb.RepairSegmentResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	inspector.log.Info("manually repairing segment", zap.Binary("Segment", req.Path))
+
+	// note that shouldDelete is used even in the case where err is not null
+	shouldDelete, err := inspector.repairer.Repair(ctx, string(req.Path))
+	if shouldDelete {
+		delErr := inspector.queue.Delete(ctx, &pb.InjuredSegment{Path: req.Path})
+		if delErr != nil {
+			inspector.log.Error("deleting repaired segment from the queue", zap.Binary("Segment", req.Path), zap.Error(delErr))
+		}
+	}
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	return &internalpb.RepairSegmentResponse{}, nil
+}
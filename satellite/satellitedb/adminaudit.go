@@ -0,0 +1,74 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package satellitedb
+
+import (
+	"context"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/private/dbutil"
+	"storj.io/storj/satellite/admin"
+)
+
+var _ admin.AuditRecords = (*adminAuditRecords)(nil)
+
+type adminAuditRecords struct {
+	db *satelliteDB
+}
+
+// AdminAuditRecords returns database for the audit records of the admin API
+func (db *satelliteDB) AdminAuditRecords() admin.AuditRecords {
+	return &adminAuditRecords{db: db}
+}
+
+// Insert adds a new record.
+func (db *adminAuditRecords) Insert(ctx context.Context, record admin.AuditRecord) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	_, err = db.db.ExecContext(ctx, db.db.Rebind(`
+		INSERT INTO admin_audit_records (id, operator, action, target, details, outcome, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`), record.ID[:], record.Operator, string(record.Action), record.Target, record.Details, string(record.Outcome), record.CreatedAt.UTC())
+	return Error.Wrap(err)
+}
+
+// List returns at most limit records created before the provided time, newest first.
+func (db *adminAuditRecords) List(ctx context.Context, before time.Time, limit int) (_ []admin.AuditRecord, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := db.db.QueryContext(ctx, db.db.Rebind(`
+		SELECT id, operator, action, target, details, outcome, created_at
+		FROM admin_audit_records
+		WHERE created_at < ?
+		ORDER BY created_at DESC
+		LIMIT ?
+	`), before.UTC(), limit)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	var records []admin.AuditRecord
+	for rows.Next() {
+		var record admin.AuditRecord
+		var id []byte
+		var action, outcome string
+		err = rows.Scan(&id, &record.Operator, &action, &record.Target, &record.Details, &outcome, &record.CreatedAt)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+
+		parsed, err := dbutil.BytesToUUID(id)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		record.ID = parsed
+		record.Action = admin.Action(action)
+		record.Outcome = admin.Outcome(outcome)
+		records = append(records, record)
+	}
+	return records, Error.Wrap(rows.Err())
+}
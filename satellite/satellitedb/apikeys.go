@@ -5,6 +5,7 @@ package satellitedb
 
 import (
 	"context"
+	"database/sql"
 	"strings"
 
 	"github.com/skyrings/skyring-common/tools/uuid"
@@ -147,17 +148,56 @@ func (keys *apikeys) Get(ctx context.Context, id uuid.UUID) (_ *console.APIKeyIn
 func (keys *apikeys) GetByHead(ctx context.Context, head []byte) (_ *console.APIKeyInfo, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	dbKeyI, err := keys.lru.Get(string(head), func() (interface{}, error) {
-		return keys.methods.Get_ApiKey_By_Head(ctx, dbx.ApiKey_Head(head))
+	cachedI, err := keys.lru.Get(string(head), func() (interface{}, error) {
+		dbKey, err := keys.methods.Get_ApiKey_By_Head(ctx, dbx.ApiKey_Head(head))
+		if err != nil {
+			return nil, err
+		}
+		frozen, err := keys.ownerFrozen(ctx, dbKey.ProjectId)
+		if err != nil {
+			return nil, err
+		}
+		return &cachedAPIKey{key: dbKey, frozen: frozen}, nil
 	})
 	if err != nil {
 		return nil, err
 	}
-	dbKey, ok := dbKeyI.(*dbx.ApiKey)
+	cached, ok := cachedI.(*cachedAPIKey)
 	if !ok {
-		return nil, Error.New("invalid key type: %T", dbKeyI)
+		return nil, Error.New("invalid key type: %T", cachedI)
 	}
-	return fromDBXAPIKey(ctx, dbKey)
+	if cached.frozen {
+		return nil, Error.New("the owner of the project is frozen")
+	}
+	return fromDBXAPIKey(ctx, cached.key)
+}
+
+// cachedAPIKey is an API key in the cache together with the status of the
+// project owner, so that the keys of frozen users are rejected until the
+// cache expires.
+type cachedAPIKey struct {
+	key    *dbx.ApiKey
+	frozen bool
+}
+
+// ownerFrozen returns whether the owner of the project is frozen.
+func (keys *apikeys) ownerFrozen(ctx context.Context, projectID []byte) (_ bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var status console.UserStatus
+	err = keys.db.QueryRowContext(ctx, keys.db.Rebind(`
+		SELECT users.status
+		FROM projects
+		JOIN users ON users.id = projects.owner_id
+		WHERE projects.id = ?
+	`), projectID).Scan(&status)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, Error.Wrap(err)
+	}
+	return status == console.Frozen, nil
 }
 
 // GetByNameAndProjectID implements satellite.APIKeys
@@ -218,8 +258,23 @@ func (keys *apikeys) Update(ctx context.Context, key console.APIKeyInfo) (err er
 // Delete implements satellite.APIKeys
 func (keys *apikeys) Delete(ctx context.Context, id uuid.UUID) (err error) {
 	defer mon.Task()(&ctx)(&err)
+
+	dbKey, err := keys.methods.Get_ApiKey_By_Id(ctx, dbx.ApiKey_Id(id[:]))
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
 	_, err = keys.methods.Delete_ApiKey_By_Id(ctx, dbx.ApiKey_Id(id[:]))
-	return err
+	if err != nil {
+		return err
+	}
+
+	// the key must not be accepted anymore by this process
+	keys.lru.Delete(string(dbKey.Head))
+	return nil
 }
 
 // fromDBXAPIKey converts dbx.ApiKey to satellite.APIKeyInfo
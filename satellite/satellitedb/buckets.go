@@ -8,10 +8,13 @@ import (
 	"database/sql"
 	"errors"
 
+	"github.com/gogo/protobuf/proto"
 	"github.com/skyrings/skyring-common/tools/uuid"
+	"github.com/zeebo/errs"
 
 	"storj.io/common/macaroon"
 	"storj.io/common/storj"
+	"storj.io/storj/pkg/metainfopb"
 	"storj.io/storj/private/dbutil"
 	"storj.io/storj/satellite/metainfo"
 	"storj.io/storj/satellite/satellitedb/dbx"
@@ -108,6 +111,27 @@ func (db *bucketsDB) DeleteBucket(ctx context.Context, bucketName []byte, projec
 	if err != nil {
 		return storj.ErrBucket.Wrap(err)
 	}
+
+	_, err = db.db.ExecContext(ctx, db.db.Rebind(`
+		DELETE FROM bucket_versionings WHERE project_id = ? AND bucket_name = ?
+	`), projectID[:], bucketName)
+	if err != nil {
+		return storj.ErrBucket.Wrap(err)
+	}
+
+	_, err = db.db.ExecContext(ctx, db.db.Rebind(`
+		DELETE FROM versioned_objects WHERE project_id = ? AND bucket_name = ?
+	`), projectID[:], bucketName)
+	if err != nil {
+		return storj.ErrBucket.Wrap(err)
+	}
+
+	_, err = db.db.ExecContext(ctx, db.db.Rebind(`
+		DELETE FROM bucket_lifecycles WHERE project_id = ? AND bucket_name = ?
+	`), projectID[:], bucketName)
+	if err != nil {
+		return storj.ErrBucket.Wrap(err)
+	}
 	return nil
 }
 
@@ -188,6 +212,172 @@ func (db *bucketsDB) ListBuckets(ctx context.Context, projectID uuid.UUID, listO
 	return bucketList, nil
 }
 
+// GetBucketVersioning returns the versioning state of a bucket
+func (db *bucketsDB) GetBucketVersioning(ctx context.Context, bucketName []byte, projectID uuid.UUID) (_ metainfo.Versioning, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var versioning int
+	err = db.db.QueryRowContext(ctx, db.db.Rebind(`
+		SELECT versioning FROM bucket_versionings
+		WHERE project_id = ? AND bucket_name = ?
+	`), projectID[:], bucketName).Scan(&versioning)
+	if err == sql.ErrNoRows {
+		return metainfo.VersioningUnversioned, nil
+	}
+	if err != nil {
+		return metainfo.VersioningUnversioned, storj.ErrBucket.Wrap(err)
+	}
+	return metainfo.Versioning(versioning), nil
+}
+
+// SetBucketVersioning sets the versioning state of an existing bucket
+func (db *bucketsDB) SetBucketVersioning(ctx context.Context, bucketName []byte, projectID uuid.UUID, versioning metainfo.Versioning) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	result, err := db.db.ExecContext(ctx, db.db.Rebind(`
+		INSERT INTO bucket_versionings (project_id, bucket_name, versioning)
+		SELECT project_id, name, ? FROM bucket_metainfos
+		WHERE project_id = ? AND name = ?
+		ON CONFLICT (project_id, bucket_name)
+		DO UPDATE SET versioning = EXCLUDED.versioning
+	`), int(versioning), projectID[:], bucketName)
+	if err != nil {
+		return storj.ErrBucket.Wrap(err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return storj.ErrBucket.Wrap(err)
+	}
+	if affected == 0 {
+		return storj.ErrBucketNotFound.New("%s", bucketName)
+	}
+	return nil
+}
+
+// GetBucketLifecycle returns the lifecycle rules of a bucket
+func (db *bucketsDB) GetBucketLifecycle(ctx context.Context, bucketName []byte, projectID uuid.UUID) (_ []metainfo.LifecycleRule, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var rulesBytes []byte
+	err = db.db.QueryRowContext(ctx, db.db.Rebind(`
+		SELECT rules FROM bucket_lifecycles
+		WHERE project_id = ? AND bucket_name = ?
+	`), projectID[:], bucketName).Scan(&rulesBytes)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, storj.ErrBucket.Wrap(err)
+	}
+	return unmarshalLifecycleRules(rulesBytes)
+}
+
+// SetBucketLifecycle replaces the lifecycle rules of an existing bucket, no rules removes them
+func (db *bucketsDB) SetBucketLifecycle(ctx context.Context, bucketName []byte, projectID uuid.UUID, rules []metainfo.LifecycleRule) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if len(rules) == 0 {
+		_, err = db.db.ExecContext(ctx, db.db.Rebind(`
+			DELETE FROM bucket_lifecycles WHERE project_id = ? AND bucket_name = ?
+		`), projectID[:], bucketName)
+		return storj.ErrBucket.Wrap(err)
+	}
+
+	rulesBytes, err := marshalLifecycleRules(rules)
+	if err != nil {
+		return err
+	}
+
+	result, err := db.db.ExecContext(ctx, db.db.Rebind(`
+		INSERT INTO bucket_lifecycles (project_id, bucket_name, rules)
+		SELECT project_id, name, ? FROM bucket_metainfos
+		WHERE project_id = ? AND name = ?
+		ON CONFLICT (project_id, bucket_name)
+		DO UPDATE SET rules = EXCLUDED.rules
+	`), rulesBytes, projectID[:], bucketName)
+	if err != nil {
+		return storj.ErrBucket.Wrap(err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return storj.ErrBucket.Wrap(err)
+	}
+	if affected == 0 {
+		return storj.ErrBucketNotFound.New("%s", bucketName)
+	}
+	return nil
+}
+
+// ListBucketLifecycles returns the lifecycle rules of all buckets which have rules
+func (db *bucketsDB) ListBucketLifecycles(ctx context.Context) (lifecycles []metainfo.BucketLifecycle, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := db.db.QueryContext(ctx, `
+		SELECT project_id, bucket_name, rules FROM bucket_lifecycles
+		ORDER BY project_id, bucket_name
+	`)
+	if err != nil {
+		return nil, storj.ErrBucket.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	for rows.Next() {
+		var projectID, bucketName, rulesBytes []byte
+		err = rows.Scan(&projectID, &bucketName, &rulesBytes)
+		if err != nil {
+			return nil, storj.ErrBucket.Wrap(err)
+		}
+
+		lifecycle := metainfo.BucketLifecycle{BucketName: bucketName}
+		lifecycle.ProjectID, err = dbutil.BytesToUUID(projectID)
+		if err != nil {
+			return nil, storj.ErrBucket.Wrap(err)
+		}
+		lifecycle.Rules, err = unmarshalLifecycleRules(rulesBytes)
+		if err != nil {
+			return nil, err
+		}
+		lifecycles = append(lifecycles, lifecycle)
+	}
+	return lifecycles, storj.ErrBucket.Wrap(rows.Err())
+}
+
+func marshalLifecycleRules(rules []metainfo.LifecycleRule) ([]byte, error) {
+	stored := &metainfopb.LifecycleRules{}
+	for _, rule := range rules {
+		stored.Rules = append(stored.Rules, &metainfopb.LifecycleRule{
+			Id:                               rule.ID,
+			EncryptedPrefix:                  rule.EncryptedPrefix,
+			ExpireAfterDays:                  rule.ExpireAfterDays,
+			AbortIncompleteUploadsAfterHours: rule.AbortIncompleteUploadsAfterHours,
+		})
+	}
+
+	rulesBytes, err := proto.Marshal(stored)
+	return rulesBytes, storj.ErrBucket.Wrap(err)
+}
+
+func unmarshalLifecycleRules(rulesBytes []byte) ([]metainfo.LifecycleRule, error) {
+	stored := &metainfopb.LifecycleRules{}
+	err := proto.Unmarshal(rulesBytes, stored)
+	if err != nil {
+		return nil, storj.ErrBucket.Wrap(err)
+	}
+
+	rules := make([]metainfo.LifecycleRule, 0, len(stored.Rules))
+	for _, rule := range stored.Rules {
+		rules = append(rules, metainfo.LifecycleRule{
+			ID:                               rule.Id,
+			EncryptedPrefix:                  rule.EncryptedPrefix,
+			ExpireAfterDays:                  rule.ExpireAfterDays,
+			AbortIncompleteUploadsAfterHours: rule.AbortIncompleteUploadsAfterHours,
+		})
+	}
+	return rules, nil
+}
+
 func convertDBXtoBucket(dbxBucket *dbx.BucketMetainfo) (bucket storj.Bucket, err error) {
 	id, err := dbutil.BytesToUUID(dbxBucket.Id)
 	if err != nil {
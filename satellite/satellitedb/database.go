@@ -18,6 +18,7 @@ import (
 	"storj.io/storj/satellite/audit"
 	"storj.io/storj/satellite/console"
 	"storj.io/storj/satellite/downtime"
+	"storj.io/storj/satellite/gc"
 	"storj.io/storj/satellite/gracefulexit"
 	"storj.io/storj/satellite/orders"
 	"storj.io/storj/satellite/overlay"
@@ -176,3 +177,8 @@ func (db *satelliteDB) StripeCoinPayments() stripecoinpayments.DB {
 func (db *satelliteDB) DowntimeTracking() downtime.DB {
 	return &downtimeTrackingDB{db: db}
 }
+
+// GarbageCollection returns database for garbage collection retain filters
+func (db *satelliteDB) GarbageCollection() gc.DB {
+	return &retainFiltersDB{db: db}
+}
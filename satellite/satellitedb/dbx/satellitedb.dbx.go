@@ -289,6 +289,16 @@ CREATE TABLE accounting_timestamps (
 	value timestamp with time zone NOT NULL,
 	PRIMARY KEY ( name )
 );
+CREATE TABLE admin_audit_records (
+	id bytea NOT NULL,
+	operator text NOT NULL,
+	action text NOT NULL,
+	target text NOT NULL,
+	details text NOT NULL,
+	outcome text NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	PRIMARY KEY ( id )
+);
 CREATE TABLE bucket_bandwidth_rollups (
 	bucket_name bytea NOT NULL,
 	project_id bytea NOT NULL,
@@ -300,6 +310,12 @@ CREATE TABLE bucket_bandwidth_rollups (
 	settled bigint NOT NULL,
 	PRIMARY KEY ( bucket_name, project_id, interval_start, action )
 );
+CREATE TABLE bucket_lifecycles (
+	project_id bytea NOT NULL,
+	bucket_name bytea NOT NULL,
+	rules bytea NOT NULL,
+	PRIMARY KEY ( project_id, bucket_name )
+);
 CREATE TABLE bucket_storage_tallies (
 	bucket_name bytea NOT NULL,
 	project_id bytea NOT NULL,
@@ -312,6 +328,12 @@ CREATE TABLE bucket_storage_tallies (
 	metadata_size bigint NOT NULL,
 	PRIMARY KEY ( bucket_name, project_id, interval_start )
 );
+CREATE TABLE bucket_versionings (
+	project_id bytea NOT NULL,
+	bucket_name bytea NOT NULL,
+	versioning integer NOT NULL,
+	PRIMARY KEY ( project_id, bucket_name )
+);
 CREATE TABLE coinpayments_transactions (
 	id text NOT NULL,
 	user_id bytea NOT NULL,
@@ -370,6 +392,7 @@ CREATE TABLE injuredsegments (
 	path bytea NOT NULL,
 	data bytea NOT NULL,
 	attempted timestamp,
+	segment_health double precision NOT NULL DEFAULT 1,
 	PRIMARY KEY ( path )
 );
 CREATE TABLE irreparabledbs (
@@ -380,6 +403,30 @@ CREATE TABLE irreparabledbs (
 	repair_attempt_count bigint NOT NULL,
 	PRIMARY KEY ( segmentpath )
 );
+CREATE TABLE metainfo_loop_observer_states (
+	name text NOT NULL,
+	range_index integer NOT NULL,
+	generation bigint NOT NULL,
+	state bytea NOT NULL,
+	PRIMARY KEY ( name, range_index )
+);
+CREATE TABLE metainfo_loop_ranges (
+	range_index integer NOT NULL,
+	range_count integer NOT NULL,
+	generation bigint NOT NULL,
+	last_key bytea,
+	done boolean NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	PRIMARY KEY ( range_index )
+);
+CREATE TABLE node_locations (
+	node_id bytea NOT NULL,
+	subnet text NOT NULL,
+	asn bigint NOT NULL,
+	region text NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	PRIMARY KEY ( node_id )
+);
 CREATE TABLE nodes (
 	id bytea NOT NULL,
 	address text NOT NULL,
@@ -492,6 +539,16 @@ CREATE TABLE reset_password_tokens (
 	PRIMARY KEY ( secret ),
 	UNIQUE ( owner_id )
 );
+CREATE TABLE retain_filters (
+	node_id bytea NOT NULL,
+	creation_date timestamp with time zone NOT NULL,
+	piece_count bigint NOT NULL,
+	filter bytea NOT NULL,
+	sent_at timestamp with time zone,
+	send_attempts integer NOT NULL DEFAULT 0,
+	last_attempt_at timestamp with time zone,
+	PRIMARY KEY ( node_id )
+);
 CREATE TABLE serial_numbers (
 	id serial NOT NULL,
 	serial_number bytea NOT NULL,
@@ -499,6 +556,11 @@ CREATE TABLE serial_numbers (
 	expires_at timestamp NOT NULL,
 	PRIMARY KEY ( id )
 );
+CREATE TABLE shared_segments (
+	root_piece_id bytea NOT NULL,
+	reference_count integer NOT NULL,
+	PRIMARY KEY ( root_piece_id )
+);
 CREATE TABLE storagenode_bandwidth_rollups (
 	storagenode_id bytea NOT NULL,
 	interval_start timestamp NOT NULL,
@@ -551,6 +613,12 @@ CREATE TABLE users (
 	status integer NOT NULL,
 	partner_id bytea,
 	created_at timestamp with time zone NOT NULL,
+	mfa_enabled boolean NOT NULL,
+	mfa_secret_key text,
+	mfa_recovery_codes text,
+	mfa_passcode_counter bigint NOT NULL,
+	mfa_failed_attempts integer NOT NULL,
+	mfa_failed_at timestamp with time zone,
 	PRIMARY KEY ( id )
 );
 CREATE TABLE value_attributions (
@@ -560,6 +628,14 @@ CREATE TABLE value_attributions (
 	last_updated timestamp NOT NULL,
 	PRIMARY KEY ( project_id, bucket_name )
 );
+CREATE TABLE versioned_objects (
+	project_id bytea NOT NULL,
+	bucket_name bytea NOT NULL,
+	encrypted_path bytea NOT NULL,
+	current_version integer NOT NULL,
+	latest_version integer NOT NULL,
+	PRIMARY KEY ( project_id, bucket_name, encrypted_path )
+);
 CREATE TABLE api_keys (
 	id bytea NOT NULL,
 	project_id bytea NOT NULL REFERENCES projects( id ) ON DELETE CASCADE,
@@ -604,6 +680,7 @@ CREATE TABLE project_members (
 	member_id bytea NOT NULL REFERENCES users( id ) ON DELETE CASCADE,
 	project_id bytea NOT NULL REFERENCES projects( id ) ON DELETE CASCADE,
 	created_at timestamp with time zone NOT NULL,
+	role integer NOT NULL,
 	PRIMARY KEY ( member_id, project_id )
 );
 CREATE TABLE stripecoinpayments_apply_balance_intents (
@@ -630,7 +707,9 @@ CREATE TABLE user_credits (
 	PRIMARY KEY ( id ),
 	UNIQUE ( id, offer_id )
 );
+CREATE INDEX admin_audit_records_created_at_index ON admin_audit_records ( created_at );
 CREATE INDEX injuredsegments_attempted_index ON injuredsegments ( attempted );
+CREATE INDEX injuredsegments_segment_health_index ON injuredsegments ( segment_health );
 CREATE INDEX node_last_ip ON nodes ( last_net );
 CREATE INDEX nodes_offline_times_node_id_index ON nodes_offline_times ( node_id );
 CREATE UNIQUE INDEX serial_number ON serial_numbers ( serial_number );
@@ -716,6 +795,16 @@ CREATE TABLE accounting_timestamps (
 	value timestamp with time zone NOT NULL,
 	PRIMARY KEY ( name )
 );
+CREATE TABLE admin_audit_records (
+	id bytea NOT NULL,
+	operator text NOT NULL,
+	action text NOT NULL,
+	target text NOT NULL,
+	details text NOT NULL,
+	outcome text NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	PRIMARY KEY ( id )
+);
 CREATE TABLE bucket_bandwidth_rollups (
 	bucket_name bytea NOT NULL,
 	project_id bytea NOT NULL,
@@ -727,6 +816,12 @@ CREATE TABLE bucket_bandwidth_rollups (
 	settled bigint NOT NULL,
 	PRIMARY KEY ( bucket_name, project_id, interval_start, action )
 );
+CREATE TABLE bucket_lifecycles (
+	project_id bytea NOT NULL,
+	bucket_name bytea NOT NULL,
+	rules bytea NOT NULL,
+	PRIMARY KEY ( project_id, bucket_name )
+);
 CREATE TABLE bucket_storage_tallies (
 	bucket_name bytea NOT NULL,
 	project_id bytea NOT NULL,
@@ -739,6 +834,12 @@ CREATE TABLE bucket_storage_tallies (
 	metadata_size bigint NOT NULL,
 	PRIMARY KEY ( bucket_name, project_id, interval_start )
 );
+CREATE TABLE bucket_versionings (
+	project_id bytea NOT NULL,
+	bucket_name bytea NOT NULL,
+	versioning integer NOT NULL,
+	PRIMARY KEY ( project_id, bucket_name )
+);
 CREATE TABLE coinpayments_transactions (
 	id text NOT NULL,
 	user_id bytea NOT NULL,
@@ -797,6 +898,7 @@ CREATE TABLE injuredsegments (
 	path bytea NOT NULL,
 	data bytea NOT NULL,
 	attempted timestamp,
+	segment_health double precision NOT NULL DEFAULT 1,
 	PRIMARY KEY ( path )
 );
 CREATE TABLE irreparabledbs (
@@ -807,6 +909,30 @@ CREATE TABLE irreparabledbs (
 	repair_attempt_count bigint NOT NULL,
 	PRIMARY KEY ( segmentpath )
 );
+CREATE TABLE metainfo_loop_observer_states (
+	name text NOT NULL,
+	range_index integer NOT NULL,
+	generation bigint NOT NULL,
+	state bytea NOT NULL,
+	PRIMARY KEY ( name, range_index )
+);
+CREATE TABLE metainfo_loop_ranges (
+	range_index integer NOT NULL,
+	range_count integer NOT NULL,
+	generation bigint NOT NULL,
+	last_key bytea,
+	done boolean NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	PRIMARY KEY ( range_index )
+);
+CREATE TABLE node_locations (
+	node_id bytea NOT NULL,
+	subnet text NOT NULL,
+	asn bigint NOT NULL,
+	region text NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	PRIMARY KEY ( node_id )
+);
 CREATE TABLE nodes (
 	id bytea NOT NULL,
 	address text NOT NULL,
@@ -919,6 +1045,16 @@ CREATE TABLE reset_password_tokens (
 	PRIMARY KEY ( secret ),
 	UNIQUE ( owner_id )
 );
+CREATE TABLE retain_filters (
+	node_id bytea NOT NULL,
+	creation_date timestamp with time zone NOT NULL,
+	piece_count bigint NOT NULL,
+	filter bytea NOT NULL,
+	sent_at timestamp with time zone,
+	send_attempts integer NOT NULL DEFAULT 0,
+	last_attempt_at timestamp with time zone,
+	PRIMARY KEY ( node_id )
+);
 CREATE TABLE serial_numbers (
 	id serial NOT NULL,
 	serial_number bytea NOT NULL,
@@ -926,6 +1062,11 @@ CREATE TABLE serial_numbers (
 	expires_at timestamp NOT NULL,
 	PRIMARY KEY ( id )
 );
+CREATE TABLE shared_segments (
+	root_piece_id bytea NOT NULL,
+	reference_count integer NOT NULL,
+	PRIMARY KEY ( root_piece_id )
+);
 CREATE TABLE storagenode_bandwidth_rollups (
 	storagenode_id bytea NOT NULL,
 	interval_start timestamp NOT NULL,
@@ -978,6 +1119,12 @@ CREATE TABLE users (
 	status integer NOT NULL,
 	partner_id bytea,
 	created_at timestamp with time zone NOT NULL,
+	mfa_enabled boolean NOT NULL,
+	mfa_secret_key text,
+	mfa_recovery_codes text,
+	mfa_passcode_counter bigint NOT NULL,
+	mfa_failed_attempts integer NOT NULL,
+	mfa_failed_at timestamp with time zone,
 	PRIMARY KEY ( id )
 );
 CREATE TABLE value_attributions (
@@ -987,6 +1134,14 @@ CREATE TABLE value_attributions (
 	last_updated timestamp NOT NULL,
 	PRIMARY KEY ( project_id, bucket_name )
 );
+CREATE TABLE versioned_objects (
+	project_id bytea NOT NULL,
+	bucket_name bytea NOT NULL,
+	encrypted_path bytea NOT NULL,
+	current_version integer NOT NULL,
+	latest_version integer NOT NULL,
+	PRIMARY KEY ( project_id, bucket_name, encrypted_path )
+);
 CREATE TABLE api_keys (
 	id bytea NOT NULL,
 	project_id bytea NOT NULL REFERENCES projects( id ) ON DELETE CASCADE,
@@ -1031,6 +1186,7 @@ CREATE TABLE project_members (
 	member_id bytea NOT NULL REFERENCES users( id ) ON DELETE CASCADE,
 	project_id bytea NOT NULL REFERENCES projects( id ) ON DELETE CASCADE,
 	created_at timestamp with time zone NOT NULL,
+	role integer NOT NULL,
 	PRIMARY KEY ( member_id, project_id )
 );
 CREATE TABLE stripecoinpayments_apply_balance_intents (
@@ -1057,7 +1213,9 @@ CREATE TABLE user_credits (
 	PRIMARY KEY ( id ),
 	UNIQUE ( id, offer_id )
 );
+CREATE INDEX admin_audit_records_created_at_index ON admin_audit_records ( created_at );
 CREATE INDEX injuredsegments_attempted_index ON injuredsegments ( attempted );
+CREATE INDEX injuredsegments_segment_health_index ON injuredsegments ( segment_health );
 CREATE INDEX node_last_ip ON nodes ( last_net );
 CREATE INDEX nodes_offline_times_node_id_index ON nodes_offline_times ( node_id );
 CREATE UNIQUE INDEX serial_number ON serial_numbers ( serial_number );
@@ -1362,6 +1520,154 @@ func (f AccountingTimestamps_Value_Field) value() interface{} {
 
 func (AccountingTimestamps_Value_Field) _Column() string { return "value" }
 
+type AdminAuditRecord struct {
+	Id        []byte
+	Operator  string
+	Action    string
+	Target    string
+	Details   string
+	Outcome   string
+	CreatedAt time.Time
+}
+
+func (AdminAuditRecord) _Table() string { return "admin_audit_records" }
+
+type AdminAuditRecord_Update_Fields struct {
+}
+
+type AdminAuditRecord_Id_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func AdminAuditRecord_Id(v []byte) AdminAuditRecord_Id_Field {
+	return AdminAuditRecord_Id_Field{_set: true, _value: v}
+}
+
+func (f AdminAuditRecord_Id_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (AdminAuditRecord_Id_Field) _Column() string { return "id" }
+
+type AdminAuditRecord_Operator_Field struct {
+	_set   bool
+	_null  bool
+	_value string
+}
+
+func AdminAuditRecord_Operator(v string) AdminAuditRecord_Operator_Field {
+	return AdminAuditRecord_Operator_Field{_set: true, _value: v}
+}
+
+func (f AdminAuditRecord_Operator_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (AdminAuditRecord_Operator_Field) _Column() string { return "operator" }
+
+type AdminAuditRecord_Action_Field struct {
+	_set   bool
+	_null  bool
+	_value string
+}
+
+func AdminAuditRecord_Action(v string) AdminAuditRecord_Action_Field {
+	return AdminAuditRecord_Action_Field{_set: true, _value: v}
+}
+
+func (f AdminAuditRecord_Action_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (AdminAuditRecord_Action_Field) _Column() string { return "action" }
+
+type AdminAuditRecord_Target_Field struct {
+	_set   bool
+	_null  bool
+	_value string
+}
+
+func AdminAuditRecord_Target(v string) AdminAuditRecord_Target_Field {
+	return AdminAuditRecord_Target_Field{_set: true, _value: v}
+}
+
+func (f AdminAuditRecord_Target_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (AdminAuditRecord_Target_Field) _Column() string { return "target" }
+
+type AdminAuditRecord_Details_Field struct {
+	_set   bool
+	_null  bool
+	_value string
+}
+
+func AdminAuditRecord_Details(v string) AdminAuditRecord_Details_Field {
+	return AdminAuditRecord_Details_Field{_set: true, _value: v}
+}
+
+func (f AdminAuditRecord_Details_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (AdminAuditRecord_Details_Field) _Column() string { return "details" }
+
+type AdminAuditRecord_Outcome_Field struct {
+	_set   bool
+	_null  bool
+	_value string
+}
+
+func AdminAuditRecord_Outcome(v string) AdminAuditRecord_Outcome_Field {
+	return AdminAuditRecord_Outcome_Field{_set: true, _value: v}
+}
+
+func (f AdminAuditRecord_Outcome_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (AdminAuditRecord_Outcome_Field) _Column() string { return "outcome" }
+
+type AdminAuditRecord_CreatedAt_Field struct {
+	_set   bool
+	_null  bool
+	_value time.Time
+}
+
+func AdminAuditRecord_CreatedAt(v time.Time) AdminAuditRecord_CreatedAt_Field {
+	return AdminAuditRecord_CreatedAt_Field{_set: true, _value: v}
+}
+
+func (f AdminAuditRecord_CreatedAt_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (AdminAuditRecord_CreatedAt_Field) _Column() string { return "created_at" }
+
 type BucketBandwidthRollup struct {
 	BucketName      []byte
 	ProjectId       []byte
@@ -1534,6 +1840,75 @@ func (f BucketBandwidthRollup_Settled_Field) value() interface{} {
 
 func (BucketBandwidthRollup_Settled_Field) _Column() string { return "settled" }
 
+type BucketLifecycle struct {
+	ProjectId  []byte
+	BucketName []byte
+	Rules      []byte
+}
+
+func (BucketLifecycle) _Table() string { return "bucket_lifecycles" }
+
+type BucketLifecycle_Update_Fields struct {
+	Rules BucketLifecycle_Rules_Field
+}
+
+type BucketLifecycle_ProjectId_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func BucketLifecycle_ProjectId(v []byte) BucketLifecycle_ProjectId_Field {
+	return BucketLifecycle_ProjectId_Field{_set: true, _value: v}
+}
+
+func (f BucketLifecycle_ProjectId_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (BucketLifecycle_ProjectId_Field) _Column() string { return "project_id" }
+
+type BucketLifecycle_BucketName_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func BucketLifecycle_BucketName(v []byte) BucketLifecycle_BucketName_Field {
+	return BucketLifecycle_BucketName_Field{_set: true, _value: v}
+}
+
+func (f BucketLifecycle_BucketName_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (BucketLifecycle_BucketName_Field) _Column() string { return "bucket_name" }
+
+type BucketLifecycle_Rules_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func BucketLifecycle_Rules(v []byte) BucketLifecycle_Rules_Field {
+	return BucketLifecycle_Rules_Field{_set: true, _value: v}
+}
+
+func (f BucketLifecycle_Rules_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (BucketLifecycle_Rules_Field) _Column() string { return "rules" }
+
 type BucketStorageTally struct {
 	BucketName          []byte
 	ProjectId           []byte
@@ -1723,6 +2098,75 @@ func (f BucketStorageTally_MetadataSize_Field) value() interface{} {
 
 func (BucketStorageTally_MetadataSize_Field) _Column() string { return "metadata_size" }
 
+type BucketVersioning struct {
+	ProjectId  []byte
+	BucketName []byte
+	Versioning int
+}
+
+func (BucketVersioning) _Table() string { return "bucket_versionings" }
+
+type BucketVersioning_Update_Fields struct {
+	Versioning BucketVersioning_Versioning_Field
+}
+
+type BucketVersioning_ProjectId_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func BucketVersioning_ProjectId(v []byte) BucketVersioning_ProjectId_Field {
+	return BucketVersioning_ProjectId_Field{_set: true, _value: v}
+}
+
+func (f BucketVersioning_ProjectId_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (BucketVersioning_ProjectId_Field) _Column() string { return "project_id" }
+
+type BucketVersioning_BucketName_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func BucketVersioning_BucketName(v []byte) BucketVersioning_BucketName_Field {
+	return BucketVersioning_BucketName_Field{_set: true, _value: v}
+}
+
+func (f BucketVersioning_BucketName_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (BucketVersioning_BucketName_Field) _Column() string { return "bucket_name" }
+
+type BucketVersioning_Versioning_Field struct {
+	_set   bool
+	_null  bool
+	_value int
+}
+
+func BucketVersioning_Versioning(v int) BucketVersioning_Versioning_Field {
+	return BucketVersioning_Versioning_Field{_set: true, _value: v}
+}
+
+func (f BucketVersioning_Versioning_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (BucketVersioning_Versioning_Field) _Column() string { return "versioning" }
+
 type CoinpaymentsTransaction struct {
 	Id        string
 	UserId    []byte
@@ -2664,15 +3108,17 @@ func (GracefulExitTransferQueue_OrderLimitSendCount_Field) _Column() string {
 }
 
 type Injuredsegment struct {
-	Path      []byte
-	Data      []byte
-	Attempted *time.Time
+	Path          []byte
+	Data          []byte
+	Attempted     *time.Time
+	SegmentHealth float64
 }
 
 func (Injuredsegment) _Table() string { return "injuredsegments" }
 
 type Injuredsegment_Create_Fields struct {
-	Attempted Injuredsegment_Attempted_Field
+	Attempted     Injuredsegment_Attempted_Field
+	SegmentHealth Injuredsegment_SegmentHealth_Field
 }
 
 type Injuredsegment_Update_Fields struct {
@@ -2750,6 +3196,25 @@ func (f Injuredsegment_Attempted_Field) value() interface{} {
 
 func (Injuredsegment_Attempted_Field) _Column() string { return "attempted" }
 
+type Injuredsegment_SegmentHealth_Field struct {
+	_set   bool
+	_null  bool
+	_value float64
+}
+
+func Injuredsegment_SegmentHealth(v float64) Injuredsegment_SegmentHealth_Field {
+	return Injuredsegment_SegmentHealth_Field{_set: true, _value: v}
+}
+
+func (f Injuredsegment_SegmentHealth_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (Injuredsegment_SegmentHealth_Field) _Column() string { return "segment_health" }
+
 type Irreparabledb struct {
 	Segmentpath        []byte
 	Segmentdetail      []byte
@@ -2862,6 +3327,355 @@ func (f Irreparabledb_RepairAttemptCount_Field) value() interface{} {
 
 func (Irreparabledb_RepairAttemptCount_Field) _Column() string { return "repair_attempt_count" }
 
+type MetainfoLoopObserverState struct {
+	Name       string
+	RangeIndex int
+	Generation int64
+	State      []byte
+}
+
+func (MetainfoLoopObserverState) _Table() string { return "metainfo_loop_observer_states" }
+
+type MetainfoLoopObserverState_Update_Fields struct {
+	Generation MetainfoLoopObserverState_Generation_Field
+	State      MetainfoLoopObserverState_State_Field
+}
+
+type MetainfoLoopObserverState_Name_Field struct {
+	_set   bool
+	_null  bool
+	_value string
+}
+
+func MetainfoLoopObserverState_Name(v string) MetainfoLoopObserverState_Name_Field {
+	return MetainfoLoopObserverState_Name_Field{_set: true, _value: v}
+}
+
+func (f MetainfoLoopObserverState_Name_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (MetainfoLoopObserverState_Name_Field) _Column() string { return "name" }
+
+type MetainfoLoopObserverState_RangeIndex_Field struct {
+	_set   bool
+	_null  bool
+	_value int
+}
+
+func MetainfoLoopObserverState_RangeIndex(v int) MetainfoLoopObserverState_RangeIndex_Field {
+	return MetainfoLoopObserverState_RangeIndex_Field{_set: true, _value: v}
+}
+
+func (f MetainfoLoopObserverState_RangeIndex_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (MetainfoLoopObserverState_RangeIndex_Field) _Column() string { return "range_index" }
+
+type MetainfoLoopObserverState_Generation_Field struct {
+	_set   bool
+	_null  bool
+	_value int64
+}
+
+func MetainfoLoopObserverState_Generation(v int64) MetainfoLoopObserverState_Generation_Field {
+	return MetainfoLoopObserverState_Generation_Field{_set: true, _value: v}
+}
+
+func (f MetainfoLoopObserverState_Generation_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (MetainfoLoopObserverState_Generation_Field) _Column() string { return "generation" }
+
+type MetainfoLoopObserverState_State_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func MetainfoLoopObserverState_State(v []byte) MetainfoLoopObserverState_State_Field {
+	return MetainfoLoopObserverState_State_Field{_set: true, _value: v}
+}
+
+func (f MetainfoLoopObserverState_State_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (MetainfoLoopObserverState_State_Field) _Column() string { return "state" }
+
+type MetainfoLoopRange struct {
+	RangeIndex int
+	RangeCount int
+	Generation int64
+	LastKey    []byte
+	Done       bool
+	UpdatedAt  time.Time
+}
+
+func (MetainfoLoopRange) _Table() string { return "metainfo_loop_ranges" }
+
+type MetainfoLoopRange_Create_Fields struct {
+	LastKey MetainfoLoopRange_LastKey_Field
+}
+
+type MetainfoLoopRange_Update_Fields struct {
+	LastKey   MetainfoLoopRange_LastKey_Field
+	Done      MetainfoLoopRange_Done_Field
+	UpdatedAt MetainfoLoopRange_UpdatedAt_Field
+}
+
+type MetainfoLoopRange_RangeIndex_Field struct {
+	_set   bool
+	_null  bool
+	_value int
+}
+
+func MetainfoLoopRange_RangeIndex(v int) MetainfoLoopRange_RangeIndex_Field {
+	return MetainfoLoopRange_RangeIndex_Field{_set: true, _value: v}
+}
+
+func (f MetainfoLoopRange_RangeIndex_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (MetainfoLoopRange_RangeIndex_Field) _Column() string { return "range_index" }
+
+type MetainfoLoopRange_RangeCount_Field struct {
+	_set   bool
+	_null  bool
+	_value int
+}
+
+func MetainfoLoopRange_RangeCount(v int) MetainfoLoopRange_RangeCount_Field {
+	return MetainfoLoopRange_RangeCount_Field{_set: true, _value: v}
+}
+
+func (f MetainfoLoopRange_RangeCount_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (MetainfoLoopRange_RangeCount_Field) _Column() string { return "range_count" }
+
+type MetainfoLoopRange_Generation_Field struct {
+	_set   bool
+	_null  bool
+	_value int64
+}
+
+func MetainfoLoopRange_Generation(v int64) MetainfoLoopRange_Generation_Field {
+	return MetainfoLoopRange_Generation_Field{_set: true, _value: v}
+}
+
+func (f MetainfoLoopRange_Generation_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (MetainfoLoopRange_Generation_Field) _Column() string { return "generation" }
+
+type MetainfoLoopRange_LastKey_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func MetainfoLoopRange_LastKey(v []byte) MetainfoLoopRange_LastKey_Field {
+	return MetainfoLoopRange_LastKey_Field{_set: true, _value: v}
+}
+
+func MetainfoLoopRange_LastKey_Raw(v []byte) MetainfoLoopRange_LastKey_Field {
+	if v == nil {
+		return MetainfoLoopRange_LastKey_Null()
+	}
+	return MetainfoLoopRange_LastKey(v)
+}
+
+func MetainfoLoopRange_LastKey_Null() MetainfoLoopRange_LastKey_Field {
+	return MetainfoLoopRange_LastKey_Field{_set: true, _null: true}
+}
+
+func (f MetainfoLoopRange_LastKey_Field) isnull() bool { return !f._set || f._null || f._value == nil }
+
+func (f MetainfoLoopRange_LastKey_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (MetainfoLoopRange_LastKey_Field) _Column() string { return "last_key" }
+
+type MetainfoLoopRange_Done_Field struct {
+	_set   bool
+	_null  bool
+	_value bool
+}
+
+func MetainfoLoopRange_Done(v bool) MetainfoLoopRange_Done_Field {
+	return MetainfoLoopRange_Done_Field{_set: true, _value: v}
+}
+
+func (f MetainfoLoopRange_Done_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (MetainfoLoopRange_Done_Field) _Column() string { return "done" }
+
+type MetainfoLoopRange_UpdatedAt_Field struct {
+	_set   bool
+	_null  bool
+	_value time.Time
+}
+
+func MetainfoLoopRange_UpdatedAt(v time.Time) MetainfoLoopRange_UpdatedAt_Field {
+	return MetainfoLoopRange_UpdatedAt_Field{_set: true, _value: v}
+}
+
+func (f MetainfoLoopRange_UpdatedAt_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (MetainfoLoopRange_UpdatedAt_Field) _Column() string { return "updated_at" }
+
+type NodeLocation struct {
+	NodeId    []byte
+	Subnet    string
+	Asn       int64
+	Region    string
+	UpdatedAt time.Time
+}
+
+func (NodeLocation) _Table() string { return "node_locations" }
+
+type NodeLocation_Update_Fields struct {
+	Subnet NodeLocation_Subnet_Field
+	Asn    NodeLocation_Asn_Field
+	Region NodeLocation_Region_Field
+}
+
+type NodeLocation_NodeId_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func NodeLocation_NodeId(v []byte) NodeLocation_NodeId_Field {
+	return NodeLocation_NodeId_Field{_set: true, _value: v}
+}
+
+func (f NodeLocation_NodeId_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (NodeLocation_NodeId_Field) _Column() string { return "node_id" }
+
+type NodeLocation_Subnet_Field struct {
+	_set   bool
+	_null  bool
+	_value string
+}
+
+func NodeLocation_Subnet(v string) NodeLocation_Subnet_Field {
+	return NodeLocation_Subnet_Field{_set: true, _value: v}
+}
+
+func (f NodeLocation_Subnet_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (NodeLocation_Subnet_Field) _Column() string { return "subnet" }
+
+type NodeLocation_Asn_Field struct {
+	_set   bool
+	_null  bool
+	_value int64
+}
+
+func NodeLocation_Asn(v int64) NodeLocation_Asn_Field {
+	return NodeLocation_Asn_Field{_set: true, _value: v}
+}
+
+func (f NodeLocation_Asn_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (NodeLocation_Asn_Field) _Column() string { return "asn" }
+
+type NodeLocation_Region_Field struct {
+	_set   bool
+	_null  bool
+	_value string
+}
+
+func NodeLocation_Region(v string) NodeLocation_Region_Field {
+	return NodeLocation_Region_Field{_set: true, _value: v}
+}
+
+func (f NodeLocation_Region_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (NodeLocation_Region_Field) _Column() string { return "region" }
+
+type NodeLocation_UpdatedAt_Field struct {
+	_set   bool
+	_null  bool
+	_value time.Time
+}
+
+func NodeLocation_UpdatedAt(v time.Time) NodeLocation_UpdatedAt_Field {
+	return NodeLocation_UpdatedAt_Field{_set: true, _value: v}
+}
+
+func (f NodeLocation_UpdatedAt_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (NodeLocation_UpdatedAt_Field) _Column() string { return "updated_at" }
+
 type Node struct {
 	Id                    []byte
 	Address               string
@@ -4803,6 +5617,192 @@ func (f ResetPasswordToken_CreatedAt_Field) value() interface{} {
 
 func (ResetPasswordToken_CreatedAt_Field) _Column() string { return "created_at" }
 
+type RetainFilter struct {
+	NodeId        []byte
+	CreationDate  time.Time
+	PieceCount    int64
+	Filter        []byte
+	SentAt        *time.Time
+	SendAttempts  int
+	LastAttemptAt *time.Time
+}
+
+func (RetainFilter) _Table() string { return "retain_filters" }
+
+type RetainFilter_Create_Fields struct {
+	SentAt        RetainFilter_SentAt_Field
+	SendAttempts  RetainFilter_SendAttempts_Field
+	LastAttemptAt RetainFilter_LastAttemptAt_Field
+}
+
+type RetainFilter_Update_Fields struct {
+	CreationDate  RetainFilter_CreationDate_Field
+	PieceCount    RetainFilter_PieceCount_Field
+	Filter        RetainFilter_Filter_Field
+	SentAt        RetainFilter_SentAt_Field
+	SendAttempts  RetainFilter_SendAttempts_Field
+	LastAttemptAt RetainFilter_LastAttemptAt_Field
+}
+
+type RetainFilter_NodeId_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func RetainFilter_NodeId(v []byte) RetainFilter_NodeId_Field {
+	return RetainFilter_NodeId_Field{_set: true, _value: v}
+}
+
+func (f RetainFilter_NodeId_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (RetainFilter_NodeId_Field) _Column() string { return "node_id" }
+
+type RetainFilter_CreationDate_Field struct {
+	_set   bool
+	_null  bool
+	_value time.Time
+}
+
+func RetainFilter_CreationDate(v time.Time) RetainFilter_CreationDate_Field {
+	return RetainFilter_CreationDate_Field{_set: true, _value: v}
+}
+
+func (f RetainFilter_CreationDate_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (RetainFilter_CreationDate_Field) _Column() string { return "creation_date" }
+
+type RetainFilter_PieceCount_Field struct {
+	_set   bool
+	_null  bool
+	_value int64
+}
+
+func RetainFilter_PieceCount(v int64) RetainFilter_PieceCount_Field {
+	return RetainFilter_PieceCount_Field{_set: true, _value: v}
+}
+
+func (f RetainFilter_PieceCount_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (RetainFilter_PieceCount_Field) _Column() string { return "piece_count" }
+
+type RetainFilter_Filter_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func RetainFilter_Filter(v []byte) RetainFilter_Filter_Field {
+	return RetainFilter_Filter_Field{_set: true, _value: v}
+}
+
+func (f RetainFilter_Filter_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (RetainFilter_Filter_Field) _Column() string { return "filter" }
+
+type RetainFilter_SentAt_Field struct {
+	_set   bool
+	_null  bool
+	_value *time.Time
+}
+
+func RetainFilter_SentAt(v time.Time) RetainFilter_SentAt_Field {
+	return RetainFilter_SentAt_Field{_set: true, _value: &v}
+}
+
+func RetainFilter_SentAt_Raw(v *time.Time) RetainFilter_SentAt_Field {
+	if v == nil {
+		return RetainFilter_SentAt_Null()
+	}
+	return RetainFilter_SentAt(*v)
+}
+
+func RetainFilter_SentAt_Null() RetainFilter_SentAt_Field {
+	return RetainFilter_SentAt_Field{_set: true, _null: true}
+}
+
+func (f RetainFilter_SentAt_Field) isnull() bool { return !f._set || f._null || f._value == nil }
+
+func (f RetainFilter_SentAt_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (RetainFilter_SentAt_Field) _Column() string { return "sent_at" }
+
+type RetainFilter_SendAttempts_Field struct {
+	_set   bool
+	_null  bool
+	_value int
+}
+
+func RetainFilter_SendAttempts(v int) RetainFilter_SendAttempts_Field {
+	return RetainFilter_SendAttempts_Field{_set: true, _value: v}
+}
+
+func (f RetainFilter_SendAttempts_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (RetainFilter_SendAttempts_Field) _Column() string { return "send_attempts" }
+
+type RetainFilter_LastAttemptAt_Field struct {
+	_set   bool
+	_null  bool
+	_value *time.Time
+}
+
+func RetainFilter_LastAttemptAt(v time.Time) RetainFilter_LastAttemptAt_Field {
+	return RetainFilter_LastAttemptAt_Field{_set: true, _value: &v}
+}
+
+func RetainFilter_LastAttemptAt_Raw(v *time.Time) RetainFilter_LastAttemptAt_Field {
+	if v == nil {
+		return RetainFilter_LastAttemptAt_Null()
+	}
+	return RetainFilter_LastAttemptAt(*v)
+}
+
+func RetainFilter_LastAttemptAt_Null() RetainFilter_LastAttemptAt_Field {
+	return RetainFilter_LastAttemptAt_Field{_set: true, _null: true}
+}
+
+func (f RetainFilter_LastAttemptAt_Field) isnull() bool { return !f._set || f._null || f._value == nil }
+
+func (f RetainFilter_LastAttemptAt_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (RetainFilter_LastAttemptAt_Field) _Column() string { return "last_attempt_at" }
+
 type SerialNumber struct {
 	Id           int
 	SerialNumber []byte
@@ -4863,34 +5863,83 @@ func SerialNumber_BucketId(v []byte) SerialNumber_BucketId_Field {
 	return SerialNumber_BucketId_Field{_set: true, _value: v}
 }
 
-func (f SerialNumber_BucketId_Field) value() interface{} {
+func (f SerialNumber_BucketId_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (SerialNumber_BucketId_Field) _Column() string { return "bucket_id" }
+
+type SerialNumber_ExpiresAt_Field struct {
+	_set   bool
+	_null  bool
+	_value time.Time
+}
+
+func SerialNumber_ExpiresAt(v time.Time) SerialNumber_ExpiresAt_Field {
+	v = toUTC(v)
+	return SerialNumber_ExpiresAt_Field{_set: true, _value: v}
+}
+
+func (f SerialNumber_ExpiresAt_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (SerialNumber_ExpiresAt_Field) _Column() string { return "expires_at" }
+
+type SharedSegment struct {
+	RootPieceId    []byte
+	ReferenceCount int
+}
+
+func (SharedSegment) _Table() string { return "shared_segments" }
+
+type SharedSegment_Update_Fields struct {
+	ReferenceCount SharedSegment_ReferenceCount_Field
+}
+
+type SharedSegment_RootPieceId_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func SharedSegment_RootPieceId(v []byte) SharedSegment_RootPieceId_Field {
+	return SharedSegment_RootPieceId_Field{_set: true, _value: v}
+}
+
+func (f SharedSegment_RootPieceId_Field) value() interface{} {
 	if !f._set || f._null {
 		return nil
 	}
 	return f._value
 }
 
-func (SerialNumber_BucketId_Field) _Column() string { return "bucket_id" }
+func (SharedSegment_RootPieceId_Field) _Column() string { return "root_piece_id" }
 
-type SerialNumber_ExpiresAt_Field struct {
+type SharedSegment_ReferenceCount_Field struct {
 	_set   bool
 	_null  bool
-	_value time.Time
+	_value int
 }
 
-func SerialNumber_ExpiresAt(v time.Time) SerialNumber_ExpiresAt_Field {
-	v = toUTC(v)
-	return SerialNumber_ExpiresAt_Field{_set: true, _value: v}
+func SharedSegment_ReferenceCount(v int) SharedSegment_ReferenceCount_Field {
+	return SharedSegment_ReferenceCount_Field{_set: true, _value: v}
 }
 
-func (f SerialNumber_ExpiresAt_Field) value() interface{} {
+func (f SharedSegment_ReferenceCount_Field) value() interface{} {
 	if !f._set || f._null {
 		return nil
 	}
 	return f._value
 }
 
-func (SerialNumber_ExpiresAt_Field) _Column() string { return "expires_at" }
+func (SharedSegment_ReferenceCount_Field) _Column() string { return "reference_count" }
 
 type StoragenodeBandwidthRollup struct {
 	StoragenodeId   []byte
@@ -5462,31 +6511,46 @@ func (f StripecoinpaymentsTxConversionRate_CreatedAt_Field) value() interface{}
 func (StripecoinpaymentsTxConversionRate_CreatedAt_Field) _Column() string { return "created_at" }
 
 type User struct {
-	Id              []byte
-	Email           string
-	NormalizedEmail string
-	FullName        string
-	ShortName       *string
-	PasswordHash    []byte
-	Status          int
-	PartnerId       []byte
-	CreatedAt       time.Time
+	Id                 []byte
+	Email              string
+	NormalizedEmail    string
+	FullName           string
+	ShortName          *string
+	PasswordHash       []byte
+	Status             int
+	PartnerId          []byte
+	CreatedAt          time.Time
+	MfaEnabled         bool
+	MfaSecretKey       *string
+	MfaRecoveryCodes   *string
+	MfaPasscodeCounter int64
+	MfaFailedAttempts  int
+	MfaFailedAt        *time.Time
 }
 
 func (User) _Table() string { return "users" }
 
 type User_Create_Fields struct {
-	ShortName User_ShortName_Field
-	PartnerId User_PartnerId_Field
+	ShortName        User_ShortName_Field
+	PartnerId        User_PartnerId_Field
+	MfaSecretKey     User_MfaSecretKey_Field
+	MfaRecoveryCodes User_MfaRecoveryCodes_Field
+	MfaFailedAt      User_MfaFailedAt_Field
 }
 
 type User_Update_Fields struct {
-	Email           User_Email_Field
-	NormalizedEmail User_NormalizedEmail_Field
-	FullName        User_FullName_Field
-	ShortName       User_ShortName_Field
-	PasswordHash    User_PasswordHash_Field
-	Status          User_Status_Field
+	Email              User_Email_Field
+	NormalizedEmail    User_NormalizedEmail_Field
+	FullName           User_FullName_Field
+	ShortName          User_ShortName_Field
+	PasswordHash       User_PasswordHash_Field
+	Status             User_Status_Field
+	MfaEnabled         User_MfaEnabled_Field
+	MfaSecretKey       User_MfaSecretKey_Field
+	MfaRecoveryCodes   User_MfaRecoveryCodes_Field
+	MfaPasscodeCounter User_MfaPasscodeCounter_Field
+	MfaFailedAttempts  User_MfaFailedAttempts_Field
+	MfaFailedAt        User_MfaFailedAt_Field
 }
 
 type User_Id_Field struct {
@@ -5686,6 +6750,159 @@ func (f User_CreatedAt_Field) value() interface{} {
 
 func (User_CreatedAt_Field) _Column() string { return "created_at" }
 
+type User_MfaEnabled_Field struct {
+	_set   bool
+	_null  bool
+	_value bool
+}
+
+func User_MfaEnabled(v bool) User_MfaEnabled_Field {
+	return User_MfaEnabled_Field{_set: true, _value: v}
+}
+
+func (f User_MfaEnabled_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (User_MfaEnabled_Field) _Column() string { return "mfa_enabled" }
+
+type User_MfaSecretKey_Field struct {
+	_set   bool
+	_null  bool
+	_value *string
+}
+
+func User_MfaSecretKey(v string) User_MfaSecretKey_Field {
+	return User_MfaSecretKey_Field{_set: true, _value: &v}
+}
+
+func User_MfaSecretKey_Raw(v *string) User_MfaSecretKey_Field {
+	if v == nil {
+		return User_MfaSecretKey_Null()
+	}
+	return User_MfaSecretKey(*v)
+}
+
+func User_MfaSecretKey_Null() User_MfaSecretKey_Field {
+	return User_MfaSecretKey_Field{_set: true, _null: true}
+}
+
+func (f User_MfaSecretKey_Field) isnull() bool { return !f._set || f._null || f._value == nil }
+
+func (f User_MfaSecretKey_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (User_MfaSecretKey_Field) _Column() string { return "mfa_secret_key" }
+
+type User_MfaRecoveryCodes_Field struct {
+	_set   bool
+	_null  bool
+	_value *string
+}
+
+func User_MfaRecoveryCodes(v string) User_MfaRecoveryCodes_Field {
+	return User_MfaRecoveryCodes_Field{_set: true, _value: &v}
+}
+
+func User_MfaRecoveryCodes_Raw(v *string) User_MfaRecoveryCodes_Field {
+	if v == nil {
+		return User_MfaRecoveryCodes_Null()
+	}
+	return User_MfaRecoveryCodes(*v)
+}
+
+func User_MfaRecoveryCodes_Null() User_MfaRecoveryCodes_Field {
+	return User_MfaRecoveryCodes_Field{_set: true, _null: true}
+}
+
+func (f User_MfaRecoveryCodes_Field) isnull() bool { return !f._set || f._null || f._value == nil }
+
+func (f User_MfaRecoveryCodes_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (User_MfaRecoveryCodes_Field) _Column() string { return "mfa_recovery_codes" }
+
+type User_MfaPasscodeCounter_Field struct {
+	_set   bool
+	_null  bool
+	_value int64
+}
+
+func User_MfaPasscodeCounter(v int64) User_MfaPasscodeCounter_Field {
+	return User_MfaPasscodeCounter_Field{_set: true, _value: v}
+}
+
+func (f User_MfaPasscodeCounter_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (User_MfaPasscodeCounter_Field) _Column() string { return "mfa_passcode_counter" }
+
+type User_MfaFailedAttempts_Field struct {
+	_set   bool
+	_null  bool
+	_value int
+}
+
+func User_MfaFailedAttempts(v int) User_MfaFailedAttempts_Field {
+	return User_MfaFailedAttempts_Field{_set: true, _value: v}
+}
+
+func (f User_MfaFailedAttempts_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (User_MfaFailedAttempts_Field) _Column() string { return "mfa_failed_attempts" }
+
+type User_MfaFailedAt_Field struct {
+	_set   bool
+	_null  bool
+	_value *time.Time
+}
+
+func User_MfaFailedAt(v time.Time) User_MfaFailedAt_Field {
+	return User_MfaFailedAt_Field{_set: true, _value: &v}
+}
+
+func User_MfaFailedAt_Raw(v *time.Time) User_MfaFailedAt_Field {
+	if v == nil {
+		return User_MfaFailedAt_Null()
+	}
+	return User_MfaFailedAt(*v)
+}
+
+func User_MfaFailedAt_Null() User_MfaFailedAt_Field {
+	return User_MfaFailedAt_Field{_set: true, _null: true}
+}
+
+func (f User_MfaFailedAt_Field) isnull() bool { return !f._set || f._null || f._value == nil }
+
+func (f User_MfaFailedAt_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (User_MfaFailedAt_Field) _Column() string { return "mfa_failed_at" }
+
 type ValueAttribution struct {
 	ProjectId   []byte
 	BucketName  []byte
@@ -5775,6 +6992,116 @@ func (f ValueAttribution_LastUpdated_Field) value() interface{} {
 
 func (ValueAttribution_LastUpdated_Field) _Column() string { return "last_updated" }
 
+type VersionedObject struct {
+	ProjectId      []byte
+	BucketName     []byte
+	EncryptedPath  []byte
+	CurrentVersion int
+	LatestVersion  int
+}
+
+func (VersionedObject) _Table() string { return "versioned_objects" }
+
+type VersionedObject_Update_Fields struct {
+	CurrentVersion VersionedObject_CurrentVersion_Field
+	LatestVersion  VersionedObject_LatestVersion_Field
+}
+
+type VersionedObject_ProjectId_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func VersionedObject_ProjectId(v []byte) VersionedObject_ProjectId_Field {
+	return VersionedObject_ProjectId_Field{_set: true, _value: v}
+}
+
+func (f VersionedObject_ProjectId_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (VersionedObject_ProjectId_Field) _Column() string { return "project_id" }
+
+type VersionedObject_BucketName_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func VersionedObject_BucketName(v []byte) VersionedObject_BucketName_Field {
+	return VersionedObject_BucketName_Field{_set: true, _value: v}
+}
+
+func (f VersionedObject_BucketName_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (VersionedObject_BucketName_Field) _Column() string { return "bucket_name" }
+
+type VersionedObject_EncryptedPath_Field struct {
+	_set   bool
+	_null  bool
+	_value []byte
+}
+
+func VersionedObject_EncryptedPath(v []byte) VersionedObject_EncryptedPath_Field {
+	return VersionedObject_EncryptedPath_Field{_set: true, _value: v}
+}
+
+func (f VersionedObject_EncryptedPath_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (VersionedObject_EncryptedPath_Field) _Column() string { return "encrypted_path" }
+
+type VersionedObject_CurrentVersion_Field struct {
+	_set   bool
+	_null  bool
+	_value int
+}
+
+func VersionedObject_CurrentVersion(v int) VersionedObject_CurrentVersion_Field {
+	return VersionedObject_CurrentVersion_Field{_set: true, _value: v}
+}
+
+func (f VersionedObject_CurrentVersion_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (VersionedObject_CurrentVersion_Field) _Column() string { return "current_version" }
+
+type VersionedObject_LatestVersion_Field struct {
+	_set   bool
+	_null  bool
+	_value int
+}
+
+func VersionedObject_LatestVersion(v int) VersionedObject_LatestVersion_Field {
+	return VersionedObject_LatestVersion_Field{_set: true, _value: v}
+}
+
+func (f VersionedObject_LatestVersion_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (VersionedObject_LatestVersion_Field) _Column() string { return "latest_version" }
+
 type ApiKey struct {
 	Id        []byte
 	ProjectId []byte
@@ -6404,11 +7731,13 @@ type ProjectMember struct {
 	MemberId  []byte
 	ProjectId []byte
 	CreatedAt time.Time
+	Role      int
 }
 
 func (ProjectMember) _Table() string { return "project_members" }
 
 type ProjectMember_Update_Fields struct {
+	Role ProjectMember_Role_Field
 }
 
 type ProjectMember_MemberId_Field struct {
@@ -6468,6 +7797,25 @@ func (f ProjectMember_CreatedAt_Field) value() interface{} {
 
 func (ProjectMember_CreatedAt_Field) _Column() string { return "created_at" }
 
+type ProjectMember_Role_Field struct {
+	_set   bool
+	_null  bool
+	_value int
+}
+
+func ProjectMember_Role(v int) ProjectMember_Role_Field {
+	return ProjectMember_Role_Field{_set: true, _value: v}
+}
+
+func (f ProjectMember_Role_Field) value() interface{} {
+	if !f._set || f._null {
+		return nil
+	}
+	return f._value
+}
+
+func (ProjectMember_Role_Field) _Column() string { return "role" }
+
 type StripecoinpaymentsApplyBalanceIntent struct {
 	TxId      string
 	State     int
@@ -7397,6 +8745,7 @@ func (obj *postgresImpl) Create_User(ctx context.Context,
 	user_normalized_email User_NormalizedEmail_Field,
 	user_full_name User_FullName_Field,
 	user_password_hash User_PasswordHash_Field,
+	user_mfa_enabled User_MfaEnabled_Field,
 	optional User_Create_Fields) (
 	user *User, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -7411,17 +8760,23 @@ func (obj *postgresImpl) Create_User(ctx context.Context,
 	__status_val := int(0)
 	__partner_id_val := optional.PartnerId.value()
 	__created_at_val := __now
+	__mfa_enabled_val := user_mfa_enabled.value()
+	__mfa_secret_key_val := optional.MfaSecretKey.value()
+	__mfa_recovery_codes_val := optional.MfaRecoveryCodes.value()
+	__mfa_passcode_counter_val := int64(0)
+	__mfa_failed_attempts_val := int(0)
+	__mfa_failed_at_val := optional.MfaFailedAt.value()
 
-	var __embed_stmt = __sqlbundle_Literal("INSERT INTO users ( id, email, normalized_email, full_name, short_name, password_hash, status, partner_id, created_at ) VALUES ( ?, ?, ?, ?, ?, ?, ?, ?, ? ) RETURNING users.id, users.email, users.normalized_email, users.full_name, users.short_name, users.password_hash, users.status, users.partner_id, users.created_at")
+	var __embed_stmt = __sqlbundle_Literal("INSERT INTO users ( id, email, normalized_email, full_name, short_name, password_hash, status, partner_id, created_at, mfa_enabled, mfa_secret_key, mfa_recovery_codes, mfa_passcode_counter, mfa_failed_attempts, mfa_failed_at ) VALUES ( ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ? ) RETURNING users.id, users.email, users.normalized_email, users.full_name, users.short_name, users.password_hash, users.status, users.partner_id, users.created_at, users.mfa_enabled, users.mfa_secret_key, users.mfa_recovery_codes, users.mfa_passcode_counter, users.mfa_failed_attempts, users.mfa_failed_at")
 
 	var __values []interface{}
-	__values = append(__values, __id_val, __email_val, __normalized_email_val, __full_name_val, __short_name_val, __password_hash_val, __status_val, __partner_id_val, __created_at_val)
+	__values = append(__values, __id_val, __email_val, __normalized_email_val, __full_name_val, __short_name_val, __password_hash_val, __status_val, __partner_id_val, __created_at_val, __mfa_enabled_val, __mfa_secret_key_val, __mfa_recovery_codes_val, __mfa_passcode_counter_val, __mfa_failed_attempts_val, __mfa_failed_at_val)
 
 	var __stmt = __sqlbundle_Render(obj.dialect, __embed_stmt)
 	obj.logStmt(__stmt, __values...)
 
 	user = &User{}
-	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&user.Id, &user.Email, &user.NormalizedEmail, &user.FullName, &user.ShortName, &user.PasswordHash, &user.Status, &user.PartnerId, &user.CreatedAt)
+	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&user.Id, &user.Email, &user.NormalizedEmail, &user.FullName, &user.ShortName, &user.PasswordHash, &user.Status, &user.PartnerId, &user.CreatedAt, &user.MfaEnabled, &user.MfaSecretKey, &user.MfaRecoveryCodes, &user.MfaPasscodeCounter, &user.MfaFailedAttempts, &user.MfaFailedAt)
 	if err != nil {
 		return nil, obj.makeErr(err)
 	}
@@ -7499,7 +8854,8 @@ func (obj *postgresImpl) Create_ProjectInvoiceStamp(ctx context.Context,
 
 func (obj *postgresImpl) Create_ProjectMember(ctx context.Context,
 	project_member_member_id ProjectMember_MemberId_Field,
-	project_member_project_id ProjectMember_ProjectId_Field) (
+	project_member_project_id ProjectMember_ProjectId_Field,
+	project_member_role ProjectMember_Role_Field) (
 	project_member *ProjectMember, err error) {
 	defer mon.Task()(&ctx)(&err)
 
@@ -7507,17 +8863,18 @@ func (obj *postgresImpl) Create_ProjectMember(ctx context.Context,
 	__member_id_val := project_member_member_id.value()
 	__project_id_val := project_member_project_id.value()
 	__created_at_val := __now
+	__role_val := project_member_role.value()
 
-	var __embed_stmt = __sqlbundle_Literal("INSERT INTO project_members ( member_id, project_id, created_at ) VALUES ( ?, ?, ? ) RETURNING project_members.member_id, project_members.project_id, project_members.created_at")
+	var __embed_stmt = __sqlbundle_Literal("INSERT INTO project_members ( member_id, project_id, created_at, role ) VALUES ( ?, ?, ?, ? ) RETURNING project_members.member_id, project_members.project_id, project_members.created_at, project_members.role")
 
 	var __values []interface{}
-	__values = append(__values, __member_id_val, __project_id_val, __created_at_val)
+	__values = append(__values, __member_id_val, __project_id_val, __created_at_val, __role_val)
 
 	var __stmt = __sqlbundle_Render(obj.dialect, __embed_stmt)
 	obj.logStmt(__stmt, __values...)
 
 	project_member = &ProjectMember{}
-	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&project_member.MemberId, &project_member.ProjectId, &project_member.CreatedAt)
+	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&project_member.MemberId, &project_member.ProjectId, &project_member.CreatedAt, &project_member.Role)
 	if err != nil {
 		return nil, obj.makeErr(err)
 	}
@@ -8675,7 +10032,7 @@ func (obj *postgresImpl) Get_User_By_NormalizedEmail_And_Status_Not_Number(ctx c
 	user *User, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	var __embed_stmt = __sqlbundle_Literal("SELECT users.id, users.email, users.normalized_email, users.full_name, users.short_name, users.password_hash, users.status, users.partner_id, users.created_at FROM users WHERE users.normalized_email = ? AND users.status != 0 LIMIT 2")
+	var __embed_stmt = __sqlbundle_Literal("SELECT users.id, users.email, users.normalized_email, users.full_name, users.short_name, users.password_hash, users.status, users.partner_id, users.created_at, users.mfa_enabled, users.mfa_secret_key, users.mfa_recovery_codes, users.mfa_passcode_counter, users.mfa_failed_attempts, users.mfa_failed_at FROM users WHERE users.normalized_email = ? AND users.status != 0 LIMIT 2")
 
 	var __values []interface{}
 	__values = append(__values, user_normalized_email.value())
@@ -8697,7 +10054,7 @@ func (obj *postgresImpl) Get_User_By_NormalizedEmail_And_Status_Not_Number(ctx c
 	}
 
 	user = &User{}
-	err = __rows.Scan(&user.Id, &user.Email, &user.NormalizedEmail, &user.FullName, &user.ShortName, &user.PasswordHash, &user.Status, &user.PartnerId, &user.CreatedAt)
+	err = __rows.Scan(&user.Id, &user.Email, &user.NormalizedEmail, &user.FullName, &user.ShortName, &user.PasswordHash, &user.Status, &user.PartnerId, &user.CreatedAt, &user.MfaEnabled, &user.MfaSecretKey, &user.MfaRecoveryCodes, &user.MfaPasscodeCounter, &user.MfaFailedAttempts, &user.MfaFailedAt)
 	if err != nil {
 		return nil, obj.makeErr(err)
 	}
@@ -8719,7 +10076,7 @@ func (obj *postgresImpl) Get_User_By_Id(ctx context.Context,
 	user *User, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	var __embed_stmt = __sqlbundle_Literal("SELECT users.id, users.email, users.normalized_email, users.full_name, users.short_name, users.password_hash, users.status, users.partner_id, users.created_at FROM users WHERE users.id = ?")
+	var __embed_stmt = __sqlbundle_Literal("SELECT users.id, users.email, users.normalized_email, users.full_name, users.short_name, users.password_hash, users.status, users.partner_id, users.created_at, users.mfa_enabled, users.mfa_secret_key, users.mfa_recovery_codes, users.mfa_passcode_counter, users.mfa_failed_attempts, users.mfa_failed_at FROM users WHERE users.id = ?")
 
 	var __values []interface{}
 	__values = append(__values, user_id.value())
@@ -8728,7 +10085,7 @@ func (obj *postgresImpl) Get_User_By_Id(ctx context.Context,
 	obj.logStmt(__stmt, __values...)
 
 	user = &User{}
-	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&user.Id, &user.Email, &user.NormalizedEmail, &user.FullName, &user.ShortName, &user.PasswordHash, &user.Status, &user.PartnerId, &user.CreatedAt)
+	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&user.Id, &user.Email, &user.NormalizedEmail, &user.FullName, &user.ShortName, &user.PasswordHash, &user.Status, &user.PartnerId, &user.CreatedAt, &user.MfaEnabled, &user.MfaSecretKey, &user.MfaRecoveryCodes, &user.MfaPasscodeCounter, &user.MfaFailedAttempts, &user.MfaFailedAt)
 	if err != nil {
 		return (*User)(nil), obj.makeErr(err)
 	}
@@ -9035,7 +10392,7 @@ func (obj *postgresImpl) All_ProjectMember_By_MemberId(ctx context.Context,
 	rows []*ProjectMember, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	var __embed_stmt = __sqlbundle_Literal("SELECT project_members.member_id, project_members.project_id, project_members.created_at FROM project_members WHERE project_members.member_id = ?")
+	var __embed_stmt = __sqlbundle_Literal("SELECT project_members.member_id, project_members.project_id, project_members.created_at, project_members.role FROM project_members WHERE project_members.member_id = ?")
 
 	var __values []interface{}
 	__values = append(__values, project_member_member_id.value())
@@ -9051,7 +10408,7 @@ func (obj *postgresImpl) All_ProjectMember_By_MemberId(ctx context.Context,
 
 	for __rows.Next() {
 		project_member := &ProjectMember{}
-		err = __rows.Scan(&project_member.MemberId, &project_member.ProjectId, &project_member.CreatedAt)
+		err = __rows.Scan(&project_member.MemberId, &project_member.ProjectId, &project_member.CreatedAt, &project_member.Role)
 		if err != nil {
 			return nil, obj.makeErr(err)
 		}
@@ -9070,7 +10427,7 @@ func (obj *postgresImpl) Limited_ProjectMember_By_ProjectId(ctx context.Context,
 	rows []*ProjectMember, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	var __embed_stmt = __sqlbundle_Literal("SELECT project_members.member_id, project_members.project_id, project_members.created_at FROM project_members WHERE project_members.project_id = ? LIMIT ? OFFSET ?")
+	var __embed_stmt = __sqlbundle_Literal("SELECT project_members.member_id, project_members.project_id, project_members.created_at, project_members.role FROM project_members WHERE project_members.project_id = ? LIMIT ? OFFSET ?")
 
 	var __values []interface{}
 	__values = append(__values, project_member_project_id.value())
@@ -9088,7 +10445,7 @@ func (obj *postgresImpl) Limited_ProjectMember_By_ProjectId(ctx context.Context,
 
 	for __rows.Next() {
 		project_member := &ProjectMember{}
-		err = __rows.Scan(&project_member.MemberId, &project_member.ProjectId, &project_member.CreatedAt)
+		err = __rows.Scan(&project_member.MemberId, &project_member.ProjectId, &project_member.CreatedAt, &project_member.Role)
 		if err != nil {
 			return nil, obj.makeErr(err)
 		}
@@ -10987,7 +12344,7 @@ func (obj *postgresImpl) Update_User_By_Id(ctx context.Context,
 	defer mon.Task()(&ctx)(&err)
 	var __sets = &__sqlbundle_Hole{}
 
-	var __embed_stmt = __sqlbundle_Literals{Join: "", SQLs: []__sqlbundle_SQL{__sqlbundle_Literal("UPDATE users SET "), __sets, __sqlbundle_Literal(" WHERE users.id = ? RETURNING users.id, users.email, users.normalized_email, users.full_name, users.short_name, users.password_hash, users.status, users.partner_id, users.created_at")}}
+	var __embed_stmt = __sqlbundle_Literals{Join: "", SQLs: []__sqlbundle_SQL{__sqlbundle_Literal("UPDATE users SET "), __sets, __sqlbundle_Literal(" WHERE users.id = ? RETURNING users.id, users.email, users.normalized_email, users.full_name, users.short_name, users.password_hash, users.status, users.partner_id, users.created_at, users.mfa_enabled, users.mfa_secret_key, users.mfa_recovery_codes, users.mfa_passcode_counter, users.mfa_failed_attempts, users.mfa_failed_at")}}
 
 	__sets_sql := __sqlbundle_Literals{Join: ", "}
 	var __values []interface{}
@@ -11023,6 +12380,36 @@ func (obj *postgresImpl) Update_User_By_Id(ctx context.Context,
 		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("status = ?"))
 	}
 
+	if update.MfaEnabled._set {
+		__values = append(__values, update.MfaEnabled.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("mfa_enabled = ?"))
+	}
+
+	if update.MfaSecretKey._set {
+		__values = append(__values, update.MfaSecretKey.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("mfa_secret_key = ?"))
+	}
+
+	if update.MfaRecoveryCodes._set {
+		__values = append(__values, update.MfaRecoveryCodes.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("mfa_recovery_codes = ?"))
+	}
+
+	if update.MfaPasscodeCounter._set {
+		__values = append(__values, update.MfaPasscodeCounter.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("mfa_passcode_counter = ?"))
+	}
+
+	if update.MfaFailedAttempts._set {
+		__values = append(__values, update.MfaFailedAttempts.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("mfa_failed_attempts = ?"))
+	}
+
+	if update.MfaFailedAt._set {
+		__values = append(__values, update.MfaFailedAt.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("mfa_failed_at = ?"))
+	}
+
 	if len(__sets_sql.SQLs) == 0 {
 		return nil, emptyUpdate()
 	}
@@ -11036,7 +12423,7 @@ func (obj *postgresImpl) Update_User_By_Id(ctx context.Context,
 	obj.logStmt(__stmt, __values...)
 
 	user = &User{}
-	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&user.Id, &user.Email, &user.NormalizedEmail, &user.FullName, &user.ShortName, &user.PasswordHash, &user.Status, &user.PartnerId, &user.CreatedAt)
+	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&user.Id, &user.Email, &user.NormalizedEmail, &user.FullName, &user.ShortName, &user.PasswordHash, &user.Status, &user.PartnerId, &user.CreatedAt, &user.MfaEnabled, &user.MfaSecretKey, &user.MfaRecoveryCodes, &user.MfaPasscodeCounter, &user.MfaFailedAttempts, &user.MfaFailedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -11141,28 +12528,66 @@ func (obj *postgresImpl) UpdateNoReturn_PeerIdentity_By_NodeId(ctx context.Conte
 	defer mon.Task()(&ctx)(&err)
 	var __sets = &__sqlbundle_Hole{}
 
-	var __embed_stmt = __sqlbundle_Literals{Join: "", SQLs: []__sqlbundle_SQL{__sqlbundle_Literal("UPDATE peer_identities SET "), __sets, __sqlbundle_Literal(" WHERE peer_identities.node_id = ?")}}
+	var __embed_stmt = __sqlbundle_Literals{Join: "", SQLs: []__sqlbundle_SQL{__sqlbundle_Literal("UPDATE peer_identities SET "), __sets, __sqlbundle_Literal(" WHERE peer_identities.node_id = ?")}}
+
+	__sets_sql := __sqlbundle_Literals{Join: ", "}
+	var __values []interface{}
+	var __args []interface{}
+
+	if update.LeafSerialNumber._set {
+		__values = append(__values, update.LeafSerialNumber.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("leaf_serial_number = ?"))
+	}
+
+	if update.Chain._set {
+		__values = append(__values, update.Chain.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("chain = ?"))
+	}
+
+	__now := obj.db.Hooks.Now().UTC()
+
+	__values = append(__values, __now)
+	__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("updated_at = ?"))
+
+	__args = append(__args, peer_identity_node_id.value())
+
+	__values = append(__values, __args...)
+	__sets.SQL = __sets_sql
+
+	var __stmt = __sqlbundle_Render(obj.dialect, __embed_stmt)
+	obj.logStmt(__stmt, __values...)
+
+	_, err = obj.driver.ExecContext(ctx, __stmt, __values...)
+	if err != nil {
+		return obj.makeErr(err)
+	}
+	return nil
+}
+
+func (obj *postgresImpl) Update_ProjectMember_By_MemberId_And_ProjectId(ctx context.Context,
+	project_member_member_id ProjectMember_MemberId_Field,
+	project_member_project_id ProjectMember_ProjectId_Field,
+	update ProjectMember_Update_Fields) (
+	project_member *ProjectMember, err error) {
+	defer mon.Task()(&ctx)(&err)
+	var __sets = &__sqlbundle_Hole{}
+
+	var __embed_stmt = __sqlbundle_Literals{Join: "", SQLs: []__sqlbundle_SQL{__sqlbundle_Literal("UPDATE project_members SET "), __sets, __sqlbundle_Literal(" WHERE project_members.member_id = ? AND project_members.project_id = ? RETURNING project_members.member_id, project_members.project_id, project_members.created_at, project_members.role")}}
 
 	__sets_sql := __sqlbundle_Literals{Join: ", "}
 	var __values []interface{}
 	var __args []interface{}
 
-	if update.LeafSerialNumber._set {
-		__values = append(__values, update.LeafSerialNumber.value())
-		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("leaf_serial_number = ?"))
+	if update.Role._set {
+		__values = append(__values, update.Role.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("role = ?"))
 	}
 
-	if update.Chain._set {
-		__values = append(__values, update.Chain.value())
-		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("chain = ?"))
+	if len(__sets_sql.SQLs) == 0 {
+		return nil, emptyUpdate()
 	}
 
-	__now := obj.db.Hooks.Now().UTC()
-
-	__values = append(__values, __now)
-	__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("updated_at = ?"))
-
-	__args = append(__args, peer_identity_node_id.value())
+	__args = append(__args, project_member_member_id.value(), project_member_project_id.value())
 
 	__values = append(__values, __args...)
 	__sets.SQL = __sets_sql
@@ -11170,11 +12595,15 @@ func (obj *postgresImpl) UpdateNoReturn_PeerIdentity_By_NodeId(ctx context.Conte
 	var __stmt = __sqlbundle_Render(obj.dialect, __embed_stmt)
 	obj.logStmt(__stmt, __values...)
 
-	_, err = obj.driver.ExecContext(ctx, __stmt, __values...)
+	project_member = &ProjectMember{}
+	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&project_member.MemberId, &project_member.ProjectId, &project_member.CreatedAt, &project_member.Role)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
 	if err != nil {
-		return obj.makeErr(err)
+		return nil, obj.makeErr(err)
 	}
-	return nil
+	return project_member, nil
 }
 
 func (obj *postgresImpl) Update_RegistrationToken_By_Secret(ctx context.Context,
@@ -12366,6 +13795,16 @@ func (obj *postgresImpl) deleteAll(ctx context.Context) (count int64, err error)
 		return 0, obj.makeErr(err)
 	}
 
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM versioned_objects;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
 	__count, err = __res.RowsAffected()
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -12436,6 +13875,16 @@ func (obj *postgresImpl) deleteAll(ctx context.Context) (count int64, err error)
 		return 0, obj.makeErr(err)
 	}
 
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM shared_segments;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
 	__count, err = __res.RowsAffected()
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -12446,6 +13895,16 @@ func (obj *postgresImpl) deleteAll(ctx context.Context) (count int64, err error)
 		return 0, obj.makeErr(err)
 	}
 
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM retain_filters;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
 	__count, err = __res.RowsAffected()
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -12536,6 +13995,36 @@ func (obj *postgresImpl) deleteAll(ctx context.Context) (count int64, err error)
 		return 0, obj.makeErr(err)
 	}
 
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM node_locations;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM metainfo_loop_ranges;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM metainfo_loop_observer_states;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
 	__count, err = __res.RowsAffected()
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -12606,6 +14095,16 @@ func (obj *postgresImpl) deleteAll(ctx context.Context) (count int64, err error)
 		return 0, obj.makeErr(err)
 	}
 
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM bucket_versionings;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
 	__count, err = __res.RowsAffected()
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -12616,6 +14115,16 @@ func (obj *postgresImpl) deleteAll(ctx context.Context) (count int64, err error)
 		return 0, obj.makeErr(err)
 	}
 
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM bucket_lifecycles;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
 	__count, err = __res.RowsAffected()
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -12626,6 +14135,16 @@ func (obj *postgresImpl) deleteAll(ctx context.Context) (count int64, err error)
 		return 0, obj.makeErr(err)
 	}
 
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM admin_audit_records;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
 	__count, err = __res.RowsAffected()
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -12903,6 +14422,7 @@ func (obj *cockroachImpl) Create_User(ctx context.Context,
 	user_normalized_email User_NormalizedEmail_Field,
 	user_full_name User_FullName_Field,
 	user_password_hash User_PasswordHash_Field,
+	user_mfa_enabled User_MfaEnabled_Field,
 	optional User_Create_Fields) (
 	user *User, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -12917,17 +14437,23 @@ func (obj *cockroachImpl) Create_User(ctx context.Context,
 	__status_val := int(0)
 	__partner_id_val := optional.PartnerId.value()
 	__created_at_val := __now
+	__mfa_enabled_val := user_mfa_enabled.value()
+	__mfa_secret_key_val := optional.MfaSecretKey.value()
+	__mfa_recovery_codes_val := optional.MfaRecoveryCodes.value()
+	__mfa_passcode_counter_val := int64(0)
+	__mfa_failed_attempts_val := int(0)
+	__mfa_failed_at_val := optional.MfaFailedAt.value()
 
-	var __embed_stmt = __sqlbundle_Literal("INSERT INTO users ( id, email, normalized_email, full_name, short_name, password_hash, status, partner_id, created_at ) VALUES ( ?, ?, ?, ?, ?, ?, ?, ?, ? ) RETURNING users.id, users.email, users.normalized_email, users.full_name, users.short_name, users.password_hash, users.status, users.partner_id, users.created_at")
+	var __embed_stmt = __sqlbundle_Literal("INSERT INTO users ( id, email, normalized_email, full_name, short_name, password_hash, status, partner_id, created_at, mfa_enabled, mfa_secret_key, mfa_recovery_codes, mfa_passcode_counter, mfa_failed_attempts, mfa_failed_at ) VALUES ( ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ? ) RETURNING users.id, users.email, users.normalized_email, users.full_name, users.short_name, users.password_hash, users.status, users.partner_id, users.created_at, users.mfa_enabled, users.mfa_secret_key, users.mfa_recovery_codes, users.mfa_passcode_counter, users.mfa_failed_attempts, users.mfa_failed_at")
 
 	var __values []interface{}
-	__values = append(__values, __id_val, __email_val, __normalized_email_val, __full_name_val, __short_name_val, __password_hash_val, __status_val, __partner_id_val, __created_at_val)
+	__values = append(__values, __id_val, __email_val, __normalized_email_val, __full_name_val, __short_name_val, __password_hash_val, __status_val, __partner_id_val, __created_at_val, __mfa_enabled_val, __mfa_secret_key_val, __mfa_recovery_codes_val, __mfa_passcode_counter_val, __mfa_failed_attempts_val, __mfa_failed_at_val)
 
 	var __stmt = __sqlbundle_Render(obj.dialect, __embed_stmt)
 	obj.logStmt(__stmt, __values...)
 
 	user = &User{}
-	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&user.Id, &user.Email, &user.NormalizedEmail, &user.FullName, &user.ShortName, &user.PasswordHash, &user.Status, &user.PartnerId, &user.CreatedAt)
+	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&user.Id, &user.Email, &user.NormalizedEmail, &user.FullName, &user.ShortName, &user.PasswordHash, &user.Status, &user.PartnerId, &user.CreatedAt, &user.MfaEnabled, &user.MfaSecretKey, &user.MfaRecoveryCodes, &user.MfaPasscodeCounter, &user.MfaFailedAttempts, &user.MfaFailedAt)
 	if err != nil {
 		return nil, obj.makeErr(err)
 	}
@@ -13005,7 +14531,8 @@ func (obj *cockroachImpl) Create_ProjectInvoiceStamp(ctx context.Context,
 
 func (obj *cockroachImpl) Create_ProjectMember(ctx context.Context,
 	project_member_member_id ProjectMember_MemberId_Field,
-	project_member_project_id ProjectMember_ProjectId_Field) (
+	project_member_project_id ProjectMember_ProjectId_Field,
+	project_member_role ProjectMember_Role_Field) (
 	project_member *ProjectMember, err error) {
 	defer mon.Task()(&ctx)(&err)
 
@@ -13013,17 +14540,18 @@ func (obj *cockroachImpl) Create_ProjectMember(ctx context.Context,
 	__member_id_val := project_member_member_id.value()
 	__project_id_val := project_member_project_id.value()
 	__created_at_val := __now
+	__role_val := project_member_role.value()
 
-	var __embed_stmt = __sqlbundle_Literal("INSERT INTO project_members ( member_id, project_id, created_at ) VALUES ( ?, ?, ? ) RETURNING project_members.member_id, project_members.project_id, project_members.created_at")
+	var __embed_stmt = __sqlbundle_Literal("INSERT INTO project_members ( member_id, project_id, created_at, role ) VALUES ( ?, ?, ?, ? ) RETURNING project_members.member_id, project_members.project_id, project_members.created_at, project_members.role")
 
 	var __values []interface{}
-	__values = append(__values, __member_id_val, __project_id_val, __created_at_val)
+	__values = append(__values, __member_id_val, __project_id_val, __created_at_val, __role_val)
 
 	var __stmt = __sqlbundle_Render(obj.dialect, __embed_stmt)
 	obj.logStmt(__stmt, __values...)
 
 	project_member = &ProjectMember{}
-	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&project_member.MemberId, &project_member.ProjectId, &project_member.CreatedAt)
+	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&project_member.MemberId, &project_member.ProjectId, &project_member.CreatedAt, &project_member.Role)
 	if err != nil {
 		return nil, obj.makeErr(err)
 	}
@@ -14181,7 +15709,7 @@ func (obj *cockroachImpl) Get_User_By_NormalizedEmail_And_Status_Not_Number(ctx
 	user *User, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	var __embed_stmt = __sqlbundle_Literal("SELECT users.id, users.email, users.normalized_email, users.full_name, users.short_name, users.password_hash, users.status, users.partner_id, users.created_at FROM users WHERE users.normalized_email = ? AND users.status != 0 LIMIT 2")
+	var __embed_stmt = __sqlbundle_Literal("SELECT users.id, users.email, users.normalized_email, users.full_name, users.short_name, users.password_hash, users.status, users.partner_id, users.created_at, users.mfa_enabled, users.mfa_secret_key, users.mfa_recovery_codes, users.mfa_passcode_counter, users.mfa_failed_attempts, users.mfa_failed_at FROM users WHERE users.normalized_email = ? AND users.status != 0 LIMIT 2")
 
 	var __values []interface{}
 	__values = append(__values, user_normalized_email.value())
@@ -14203,7 +15731,7 @@ func (obj *cockroachImpl) Get_User_By_NormalizedEmail_And_Status_Not_Number(ctx
 	}
 
 	user = &User{}
-	err = __rows.Scan(&user.Id, &user.Email, &user.NormalizedEmail, &user.FullName, &user.ShortName, &user.PasswordHash, &user.Status, &user.PartnerId, &user.CreatedAt)
+	err = __rows.Scan(&user.Id, &user.Email, &user.NormalizedEmail, &user.FullName, &user.ShortName, &user.PasswordHash, &user.Status, &user.PartnerId, &user.CreatedAt, &user.MfaEnabled, &user.MfaSecretKey, &user.MfaRecoveryCodes, &user.MfaPasscodeCounter, &user.MfaFailedAttempts, &user.MfaFailedAt)
 	if err != nil {
 		return nil, obj.makeErr(err)
 	}
@@ -14225,7 +15753,7 @@ func (obj *cockroachImpl) Get_User_By_Id(ctx context.Context,
 	user *User, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	var __embed_stmt = __sqlbundle_Literal("SELECT users.id, users.email, users.normalized_email, users.full_name, users.short_name, users.password_hash, users.status, users.partner_id, users.created_at FROM users WHERE users.id = ?")
+	var __embed_stmt = __sqlbundle_Literal("SELECT users.id, users.email, users.normalized_email, users.full_name, users.short_name, users.password_hash, users.status, users.partner_id, users.created_at, users.mfa_enabled, users.mfa_secret_key, users.mfa_recovery_codes, users.mfa_passcode_counter, users.mfa_failed_attempts, users.mfa_failed_at FROM users WHERE users.id = ?")
 
 	var __values []interface{}
 	__values = append(__values, user_id.value())
@@ -14234,7 +15762,7 @@ func (obj *cockroachImpl) Get_User_By_Id(ctx context.Context,
 	obj.logStmt(__stmt, __values...)
 
 	user = &User{}
-	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&user.Id, &user.Email, &user.NormalizedEmail, &user.FullName, &user.ShortName, &user.PasswordHash, &user.Status, &user.PartnerId, &user.CreatedAt)
+	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&user.Id, &user.Email, &user.NormalizedEmail, &user.FullName, &user.ShortName, &user.PasswordHash, &user.Status, &user.PartnerId, &user.CreatedAt, &user.MfaEnabled, &user.MfaSecretKey, &user.MfaRecoveryCodes, &user.MfaPasscodeCounter, &user.MfaFailedAttempts, &user.MfaFailedAt)
 	if err != nil {
 		return (*User)(nil), obj.makeErr(err)
 	}
@@ -14541,7 +16069,7 @@ func (obj *cockroachImpl) All_ProjectMember_By_MemberId(ctx context.Context,
 	rows []*ProjectMember, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	var __embed_stmt = __sqlbundle_Literal("SELECT project_members.member_id, project_members.project_id, project_members.created_at FROM project_members WHERE project_members.member_id = ?")
+	var __embed_stmt = __sqlbundle_Literal("SELECT project_members.member_id, project_members.project_id, project_members.created_at, project_members.role FROM project_members WHERE project_members.member_id = ?")
 
 	var __values []interface{}
 	__values = append(__values, project_member_member_id.value())
@@ -14557,7 +16085,7 @@ func (obj *cockroachImpl) All_ProjectMember_By_MemberId(ctx context.Context,
 
 	for __rows.Next() {
 		project_member := &ProjectMember{}
-		err = __rows.Scan(&project_member.MemberId, &project_member.ProjectId, &project_member.CreatedAt)
+		err = __rows.Scan(&project_member.MemberId, &project_member.ProjectId, &project_member.CreatedAt, &project_member.Role)
 		if err != nil {
 			return nil, obj.makeErr(err)
 		}
@@ -14576,7 +16104,7 @@ func (obj *cockroachImpl) Limited_ProjectMember_By_ProjectId(ctx context.Context
 	rows []*ProjectMember, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	var __embed_stmt = __sqlbundle_Literal("SELECT project_members.member_id, project_members.project_id, project_members.created_at FROM project_members WHERE project_members.project_id = ? LIMIT ? OFFSET ?")
+	var __embed_stmt = __sqlbundle_Literal("SELECT project_members.member_id, project_members.project_id, project_members.created_at, project_members.role FROM project_members WHERE project_members.project_id = ? LIMIT ? OFFSET ?")
 
 	var __values []interface{}
 	__values = append(__values, project_member_project_id.value())
@@ -14594,7 +16122,7 @@ func (obj *cockroachImpl) Limited_ProjectMember_By_ProjectId(ctx context.Context
 
 	for __rows.Next() {
 		project_member := &ProjectMember{}
-		err = __rows.Scan(&project_member.MemberId, &project_member.ProjectId, &project_member.CreatedAt)
+		err = __rows.Scan(&project_member.MemberId, &project_member.ProjectId, &project_member.CreatedAt, &project_member.Role)
 		if err != nil {
 			return nil, obj.makeErr(err)
 		}
@@ -16493,7 +18021,7 @@ func (obj *cockroachImpl) Update_User_By_Id(ctx context.Context,
 	defer mon.Task()(&ctx)(&err)
 	var __sets = &__sqlbundle_Hole{}
 
-	var __embed_stmt = __sqlbundle_Literals{Join: "", SQLs: []__sqlbundle_SQL{__sqlbundle_Literal("UPDATE users SET "), __sets, __sqlbundle_Literal(" WHERE users.id = ? RETURNING users.id, users.email, users.normalized_email, users.full_name, users.short_name, users.password_hash, users.status, users.partner_id, users.created_at")}}
+	var __embed_stmt = __sqlbundle_Literals{Join: "", SQLs: []__sqlbundle_SQL{__sqlbundle_Literal("UPDATE users SET "), __sets, __sqlbundle_Literal(" WHERE users.id = ? RETURNING users.id, users.email, users.normalized_email, users.full_name, users.short_name, users.password_hash, users.status, users.partner_id, users.created_at, users.mfa_enabled, users.mfa_secret_key, users.mfa_recovery_codes, users.mfa_passcode_counter, users.mfa_failed_attempts, users.mfa_failed_at")}}
 
 	__sets_sql := __sqlbundle_Literals{Join: ", "}
 	var __values []interface{}
@@ -16529,6 +18057,36 @@ func (obj *cockroachImpl) Update_User_By_Id(ctx context.Context,
 		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("status = ?"))
 	}
 
+	if update.MfaEnabled._set {
+		__values = append(__values, update.MfaEnabled.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("mfa_enabled = ?"))
+	}
+
+	if update.MfaSecretKey._set {
+		__values = append(__values, update.MfaSecretKey.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("mfa_secret_key = ?"))
+	}
+
+	if update.MfaRecoveryCodes._set {
+		__values = append(__values, update.MfaRecoveryCodes.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("mfa_recovery_codes = ?"))
+	}
+
+	if update.MfaPasscodeCounter._set {
+		__values = append(__values, update.MfaPasscodeCounter.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("mfa_passcode_counter = ?"))
+	}
+
+	if update.MfaFailedAttempts._set {
+		__values = append(__values, update.MfaFailedAttempts.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("mfa_failed_attempts = ?"))
+	}
+
+	if update.MfaFailedAt._set {
+		__values = append(__values, update.MfaFailedAt.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("mfa_failed_at = ?"))
+	}
+
 	if len(__sets_sql.SQLs) == 0 {
 		return nil, emptyUpdate()
 	}
@@ -16542,7 +18100,7 @@ func (obj *cockroachImpl) Update_User_By_Id(ctx context.Context,
 	obj.logStmt(__stmt, __values...)
 
 	user = &User{}
-	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&user.Id, &user.Email, &user.NormalizedEmail, &user.FullName, &user.ShortName, &user.PasswordHash, &user.Status, &user.PartnerId, &user.CreatedAt)
+	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&user.Id, &user.Email, &user.NormalizedEmail, &user.FullName, &user.ShortName, &user.PasswordHash, &user.Status, &user.PartnerId, &user.CreatedAt, &user.MfaEnabled, &user.MfaSecretKey, &user.MfaRecoveryCodes, &user.MfaPasscodeCounter, &user.MfaFailedAttempts, &user.MfaFailedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -16683,6 +18241,48 @@ func (obj *cockroachImpl) UpdateNoReturn_PeerIdentity_By_NodeId(ctx context.Cont
 	return nil
 }
 
+func (obj *cockroachImpl) Update_ProjectMember_By_MemberId_And_ProjectId(ctx context.Context,
+	project_member_member_id ProjectMember_MemberId_Field,
+	project_member_project_id ProjectMember_ProjectId_Field,
+	update ProjectMember_Update_Fields) (
+	project_member *ProjectMember, err error) {
+	defer mon.Task()(&ctx)(&err)
+	var __sets = &__sqlbundle_Hole{}
+
+	var __embed_stmt = __sqlbundle_Literals{Join: "", SQLs: []__sqlbundle_SQL{__sqlbundle_Literal("UPDATE project_members SET "), __sets, __sqlbundle_Literal(" WHERE project_members.member_id = ? AND project_members.project_id = ? RETURNING project_members.member_id, project_members.project_id, project_members.created_at, project_members.role")}}
+
+	__sets_sql := __sqlbundle_Literals{Join: ", "}
+	var __values []interface{}
+	var __args []interface{}
+
+	if update.Role._set {
+		__values = append(__values, update.Role.value())
+		__sets_sql.SQLs = append(__sets_sql.SQLs, __sqlbundle_Literal("role = ?"))
+	}
+
+	if len(__sets_sql.SQLs) == 0 {
+		return nil, emptyUpdate()
+	}
+
+	__args = append(__args, project_member_member_id.value(), project_member_project_id.value())
+
+	__values = append(__values, __args...)
+	__sets.SQL = __sets_sql
+
+	var __stmt = __sqlbundle_Render(obj.dialect, __embed_stmt)
+	obj.logStmt(__stmt, __values...)
+
+	project_member = &ProjectMember{}
+	err = obj.driver.QueryRowContext(ctx, __stmt, __values...).Scan(&project_member.MemberId, &project_member.ProjectId, &project_member.CreatedAt, &project_member.Role)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, obj.makeErr(err)
+	}
+	return project_member, nil
+}
+
 func (obj *cockroachImpl) Update_RegistrationToken_By_Secret(ctx context.Context,
 	registration_token_secret RegistrationToken_Secret_Field,
 	update RegistrationToken_Update_Fields) (
@@ -17872,6 +19472,16 @@ func (obj *cockroachImpl) deleteAll(ctx context.Context) (count int64, err error
 		return 0, obj.makeErr(err)
 	}
 
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM versioned_objects;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
 	__count, err = __res.RowsAffected()
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -17942,6 +19552,16 @@ func (obj *cockroachImpl) deleteAll(ctx context.Context) (count int64, err error
 		return 0, obj.makeErr(err)
 	}
 
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM shared_segments;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
 	__count, err = __res.RowsAffected()
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -17952,6 +19572,16 @@ func (obj *cockroachImpl) deleteAll(ctx context.Context) (count int64, err error
 		return 0, obj.makeErr(err)
 	}
 
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM retain_filters;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
 	__count, err = __res.RowsAffected()
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -18042,6 +19672,36 @@ func (obj *cockroachImpl) deleteAll(ctx context.Context) (count int64, err error
 		return 0, obj.makeErr(err)
 	}
 
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM node_locations;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM metainfo_loop_ranges;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM metainfo_loop_observer_states;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
 	__count, err = __res.RowsAffected()
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -18112,6 +19772,16 @@ func (obj *cockroachImpl) deleteAll(ctx context.Context) (count int64, err error
 		return 0, obj.makeErr(err)
 	}
 
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM bucket_versionings;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
 	__count, err = __res.RowsAffected()
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -18122,6 +19792,16 @@ func (obj *cockroachImpl) deleteAll(ctx context.Context) (count int64, err error
 		return 0, obj.makeErr(err)
 	}
 
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM bucket_lifecycles;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
 	__count, err = __res.RowsAffected()
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -18132,6 +19812,16 @@ func (obj *cockroachImpl) deleteAll(ctx context.Context) (count int64, err error
 		return 0, obj.makeErr(err)
 	}
 
+	__count, err = __res.RowsAffected()
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+	count += __count
+	__res, err = obj.driver.ExecContext(ctx, "DELETE FROM admin_audit_records;")
+	if err != nil {
+		return 0, obj.makeErr(err)
+	}
+
 	__count, err = __res.RowsAffected()
 	if err != nil {
 		return 0, obj.makeErr(err)
@@ -18808,13 +20498,14 @@ func (rx *Rx) Create_ProjectInvoiceStamp(ctx context.Context,
 
 func (rx *Rx) Create_ProjectMember(ctx context.Context,
 	project_member_member_id ProjectMember_MemberId_Field,
-	project_member_project_id ProjectMember_ProjectId_Field) (
+	project_member_project_id ProjectMember_ProjectId_Field,
+	project_member_role ProjectMember_Role_Field) (
 	project_member *ProjectMember, err error) {
 	var tx *Tx
 	if tx, err = rx.getTx(ctx); err != nil {
 		return
 	}
-	return tx.Create_ProjectMember(ctx, project_member_member_id, project_member_project_id)
+	return tx.Create_ProjectMember(ctx, project_member_member_id, project_member_project_id, project_member_role)
 
 }
 
@@ -18903,13 +20594,14 @@ func (rx *Rx) Create_User(ctx context.Context,
 	user_normalized_email User_NormalizedEmail_Field,
 	user_full_name User_FullName_Field,
 	user_password_hash User_PasswordHash_Field,
+	user_mfa_enabled User_MfaEnabled_Field,
 	optional User_Create_Fields) (
 	user *User, err error) {
 	var tx *Tx
 	if tx, err = rx.getTx(ctx); err != nil {
 		return
 	}
-	return tx.Create_User(ctx, user_id, user_email, user_normalized_email, user_full_name, user_password_hash, optional)
+	return tx.Create_User(ctx, user_id, user_email, user_normalized_email, user_full_name, user_password_hash, user_mfa_enabled, optional)
 
 }
 
@@ -19837,6 +21529,18 @@ func (rx *Rx) Update_PendingAudits_By_NodeId(ctx context.Context,
 	return tx.Update_PendingAudits_By_NodeId(ctx, pending_audits_node_id, update)
 }
 
+func (rx *Rx) Update_ProjectMember_By_MemberId_And_ProjectId(ctx context.Context,
+	project_member_member_id ProjectMember_MemberId_Field,
+	project_member_project_id ProjectMember_ProjectId_Field,
+	update ProjectMember_Update_Fields) (
+	project_member *ProjectMember, err error) {
+	var tx *Tx
+	if tx, err = rx.getTx(ctx); err != nil {
+		return
+	}
+	return tx.Update_ProjectMember_By_MemberId_And_ProjectId(ctx, project_member_member_id, project_member_project_id, update)
+}
+
 func (rx *Rx) Update_Project_By_Id(ctx context.Context,
 	project_id Project_Id_Field,
 	update Project_Update_Fields) (
@@ -20200,7 +21904,8 @@ type Methods interface {
 
 	Create_ProjectMember(ctx context.Context,
 		project_member_member_id ProjectMember_MemberId_Field,
-		project_member_project_id ProjectMember_ProjectId_Field) (
+		project_member_project_id ProjectMember_ProjectId_Field,
+		project_member_role ProjectMember_Role_Field) (
 		project_member *ProjectMember, err error)
 
 	Create_RegistrationToken(ctx context.Context,
@@ -20246,6 +21951,7 @@ type Methods interface {
 		user_normalized_email User_NormalizedEmail_Field,
 		user_full_name User_FullName_Field,
 		user_password_hash User_PasswordHash_Field,
+		user_mfa_enabled User_MfaEnabled_Field,
 		optional User_Create_Fields) (
 		user *User, err error)
 
@@ -20656,6 +22362,12 @@ type Methods interface {
 		update PendingAudits_Update_Fields) (
 		pending_audits *PendingAudits, err error)
 
+	Update_ProjectMember_By_MemberId_And_ProjectId(ctx context.Context,
+		project_member_member_id ProjectMember_MemberId_Field,
+		project_member_project_id ProjectMember_ProjectId_Field,
+		update ProjectMember_Update_Fields) (
+		project_member *ProjectMember, err error)
+
 	Update_Project_By_Id(ctx context.Context,
 		project_id Project_Id_Field,
 		update Project_Update_Fields) (
@@ -0,0 +1,145 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package satellitedb
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/satellite/metainfo"
+	"storj.io/storj/satellite/satellitedb/dbx"
+)
+
+var _ metainfo.LoopCheckpointsDB = (*loopCheckpointsDB)(nil)
+
+type loopCheckpointsDB struct {
+	db *satelliteDB
+}
+
+// LoopCheckpoints returns database for the progress of the metainfo loop
+func (db *satelliteDB) LoopCheckpoints() metainfo.LoopCheckpointsDB {
+	return &loopCheckpointsDB{db: db}
+}
+
+// GetRanges returns the checkpoints of all ranges of the last generation, ordered by index.
+func (db *loopCheckpointsDB) GetRanges(ctx context.Context) (_ []metainfo.RangeCheckpoint, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := db.db.QueryContext(ctx, `
+		SELECT range_index, range_count, generation, last_key, done, updated_at
+		FROM metainfo_loop_ranges
+		ORDER BY range_index
+	`)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	var checkpoints []metainfo.RangeCheckpoint
+	for rows.Next() {
+		var checkpoint metainfo.RangeCheckpoint
+		var lastKey []byte
+		err = rows.Scan(&checkpoint.Index, &checkpoint.Count, &checkpoint.Generation, &lastKey, &checkpoint.Done, &checkpoint.UpdatedAt)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		checkpoint.LastKey = lastKey
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	return checkpoints, Error.Wrap(rows.Err())
+}
+
+// ResetRanges starts a new generation with count ranges, which haven't been started.
+func (db *loopCheckpointsDB) ResetRanges(ctx context.Context, generation int64, count int) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return Error.Wrap(db.db.WithTx(ctx, func(ctx context.Context, tx *dbx.Tx) error {
+		if _, err := tx.Tx.ExecContext(ctx, `DELETE FROM metainfo_loop_ranges`); err != nil {
+			return err
+		}
+		if _, err := tx.Tx.ExecContext(ctx, db.db.Rebind(`DELETE FROM metainfo_loop_observer_states WHERE generation < ?`), generation); err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		for index := 0; index < count; index++ {
+			_, err := tx.Tx.ExecContext(ctx, db.db.Rebind(`
+				INSERT INTO metainfo_loop_ranges (range_index, range_count, generation, last_key, done, updated_at)
+				VALUES (?, ?, ?, NULL, false, ?)
+			`), index, count, generation, now)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+}
+
+// SetRange stores the checkpoint of a range together with the state of its observers.
+// States which were stored for the range before, but are not part of states, are removed.
+func (db *loopCheckpointsDB) SetRange(ctx context.Context, checkpoint metainfo.RangeCheckpoint, states map[string][]byte) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	names := make([]string, 0, len(states))
+	for name := range states {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return Error.Wrap(db.db.WithTx(ctx, func(ctx context.Context, tx *dbx.Tx) error {
+		result, err := tx.Tx.ExecContext(ctx, db.db.Rebind(`
+			UPDATE metainfo_loop_ranges
+			SET last_key = ?, done = ?, updated_at = ?
+			WHERE range_index = ? AND range_count = ? AND generation = ?
+		`), []byte(checkpoint.LastKey), checkpoint.Done, checkpoint.UpdatedAt.UTC(),
+			checkpoint.Index, checkpoint.Count, checkpoint.Generation)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return errs.New("range %d of generation %d not found", checkpoint.Index, checkpoint.Generation)
+		}
+
+		// the states are only valid together with the last key of the range
+		_, err = tx.Tx.ExecContext(ctx, db.db.Rebind(`DELETE FROM metainfo_loop_observer_states WHERE range_index = ?`), checkpoint.Index)
+		if err != nil {
+			return err
+		}
+
+		for _, name := range names {
+			_, err := tx.Tx.ExecContext(ctx, db.db.Rebind(`
+				INSERT INTO metainfo_loop_observer_states (name, range_index, generation, state)
+				VALUES (?, ?, ?, ?)
+			`), name, checkpoint.Index, checkpoint.Generation, states[name])
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+}
+
+// GetObserverState returns the state an observer stored for a range of the generation, or nil.
+func (db *loopCheckpointsDB) GetObserverState(ctx context.Context, generation int64, name string, index int) (_ []byte, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var state []byte
+	err = db.db.QueryRowContext(ctx, db.db.Rebind(`
+		SELECT state
+		FROM metainfo_loop_observer_states
+		WHERE name = ? AND range_index = ? AND generation = ?
+	`), name, index, generation).Scan(&state)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return state, Error.Wrap(err)
+}
@@ -736,6 +736,160 @@ func (db *satelliteDB) PostgresMigration() *migrate.Migration {
 					`ALTER TABLE projects ADD COLUMN rate_limit integer;`,
 				},
 			},
+			{
+				DB:          db.DB,
+				Description: "Add segment_health column to injuredsegments table",
+				Version:     81,
+				Action: migrate.SQL{
+					`ALTER TABLE injuredsegments ADD COLUMN segment_health double precision NOT NULL DEFAULT 1;`,
+					`CREATE INDEX injuredsegments_segment_health_index ON injuredsegments ( segment_health );`,
+				},
+			},
+			{
+				DB:          db.DB,
+				Description: "Add node_locations table",
+				Version:     82,
+				Action: migrate.SQL{
+					`CREATE TABLE node_locations (
+						node_id bytea NOT NULL,
+						subnet text NOT NULL,
+						asn bigint NOT NULL,
+						region text NOT NULL,
+						updated_at timestamp with time zone NOT NULL,
+						PRIMARY KEY ( node_id )
+					);`,
+				},
+			},
+			{
+				DB:          db.DB,
+				Description: "Add retain_filters table",
+				Version:     83,
+				Action: migrate.SQL{
+					`CREATE TABLE retain_filters (
+						node_id bytea NOT NULL,
+						creation_date timestamp with time zone NOT NULL,
+						piece_count bigint NOT NULL,
+						filter bytea NOT NULL,
+						sent_at timestamp with time zone,
+						send_attempts integer NOT NULL DEFAULT 0,
+						last_attempt_at timestamp with time zone,
+						PRIMARY KEY ( node_id )
+					);`,
+				},
+			},
+			{
+				DB:          db.DB,
+				Description: "Add shared_segments table",
+				Version:     84,
+				Action: migrate.SQL{
+					`CREATE TABLE shared_segments (
+						root_piece_id bytea NOT NULL,
+						reference_count integer NOT NULL,
+						PRIMARY KEY ( root_piece_id )
+					);`,
+				},
+			},
+			{
+				DB:          db.DB,
+				Description: "Add bucket_versionings and versioned_objects tables",
+				Version:     85,
+				Action: migrate.SQL{
+					`CREATE TABLE bucket_versionings (
+						project_id bytea NOT NULL,
+						bucket_name bytea NOT NULL,
+						versioning integer NOT NULL,
+						PRIMARY KEY ( project_id, bucket_name )
+					);`,
+					`CREATE TABLE versioned_objects (
+						project_id bytea NOT NULL,
+						bucket_name bytea NOT NULL,
+						encrypted_path bytea NOT NULL,
+						current_version integer NOT NULL,
+						latest_version integer NOT NULL,
+						PRIMARY KEY ( project_id, bucket_name, encrypted_path )
+					);`,
+				},
+			},
+			{
+				DB:          db.DB,
+				Description: "Add bucket_lifecycles table",
+				Version:     86,
+				Action: migrate.SQL{
+					`CREATE TABLE bucket_lifecycles (
+						project_id bytea NOT NULL,
+						bucket_name bytea NOT NULL,
+						rules bytea NOT NULL,
+						PRIMARY KEY ( project_id, bucket_name )
+					);`,
+				},
+			},
+			{
+				DB:          db.DB,
+				Description: "Add metainfo_loop_ranges and metainfo_loop_observer_states tables",
+				Version:     87,
+				Action: migrate.SQL{
+					`CREATE TABLE metainfo_loop_ranges (
+						range_index integer NOT NULL,
+						range_count integer NOT NULL,
+						generation bigint NOT NULL,
+						last_key bytea,
+						done boolean NOT NULL,
+						updated_at timestamp with time zone NOT NULL,
+						PRIMARY KEY ( range_index )
+					);`,
+					`CREATE TABLE metainfo_loop_observer_states (
+						name text NOT NULL,
+						range_index integer NOT NULL,
+						generation bigint NOT NULL,
+						state bytea NOT NULL,
+						PRIMARY KEY ( name, range_index )
+					);`,
+				},
+			},
+			{
+				DB:          db.DB,
+				Description: "Add admin_audit_records table",
+				Version:     88,
+				Action: migrate.SQL{
+					`CREATE TABLE admin_audit_records (
+						id bytea NOT NULL,
+						operator text NOT NULL,
+						action text NOT NULL,
+						target text NOT NULL,
+						details text NOT NULL,
+						outcome text NOT NULL,
+						created_at timestamp with time zone NOT NULL,
+						PRIMARY KEY ( id )
+					);`,
+					`CREATE INDEX admin_audit_records_created_at_index ON admin_audit_records ( created_at );`,
+				},
+			},
+			{
+				DB:          db.DB,
+				Description: "Add MFA columns to users table",
+				Version:     89,
+				Action: migrate.SQL{
+					`ALTER TABLE users ADD COLUMN mfa_enabled boolean NOT NULL DEFAULT false;`,
+					`ALTER TABLE users ADD COLUMN mfa_secret_key text;`,
+					`ALTER TABLE users ADD COLUMN mfa_recovery_codes text;`,
+					`ALTER TABLE users ADD COLUMN mfa_passcode_counter bigint NOT NULL DEFAULT 0;`,
+					`ALTER TABLE users ADD COLUMN mfa_failed_attempts integer NOT NULL DEFAULT 0;`,
+					`ALTER TABLE users ADD COLUMN mfa_failed_at timestamp with time zone;`,
+				},
+			},
+			{
+				DB:          db.DB,
+				Description: "Add role column to project_members table, project owners become owners (1) and all other existing members become admins (2)",
+				Version:     90,
+				Action: migrate.SQL{
+					`ALTER TABLE project_members ADD COLUMN role integer NOT NULL DEFAULT 2;`,
+					`ALTER TABLE project_members ALTER COLUMN role DROP DEFAULT;`,
+					`UPDATE project_members SET role = 1
+						FROM projects
+						WHERE project_members.project_id = projects.id
+							AND project_members.member_id = projects.owner_id;`,
+				},
+			},
 		},
 	}
 }
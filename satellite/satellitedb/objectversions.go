@@ -0,0 +1,67 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package satellitedb
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/skyrings/skyring-common/tools/uuid"
+
+	"storj.io/storj/satellite/metainfo"
+)
+
+var _ metainfo.ObjectVersionsDB = (*objectVersionsDB)(nil)
+
+type objectVersionsDB struct {
+	db *satelliteDB
+}
+
+// ObjectVersions returns database for version numbers of versioned objects
+func (db *satelliteDB) ObjectVersions() metainfo.ObjectVersionsDB {
+	return &objectVersionsDB{db: db}
+}
+
+// GetCurrent returns the version number of the current object. It is 0 when
+// the current object doesn't have a version number.
+func (db *objectVersionsDB) GetCurrent(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte) (version int32, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = db.db.QueryRowContext(ctx, db.db.Rebind(`
+		SELECT current_version FROM versioned_objects
+		WHERE project_id = ? AND bucket_name = ? AND encrypted_path = ?
+	`), projectID[:], bucket, encryptedPath).Scan(&version)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return version, Error.Wrap(err)
+}
+
+// SetCurrent sets the version number of the current object.
+func (db *objectVersionsDB) SetCurrent(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte, version int32) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	// an object without a row has never been versioned, so there is nothing
+	// to update when the current object doesn't get a version number
+	_, err = db.db.ExecContext(ctx, db.db.Rebind(`
+		UPDATE versioned_objects SET current_version = ?
+		WHERE project_id = ? AND bucket_name = ? AND encrypted_path = ?
+	`), version, projectID[:], bucket, encryptedPath)
+	return Error.Wrap(err)
+}
+
+// Allocate returns a version number which is greater than all version numbers
+// previously allocated for the object.
+func (db *objectVersionsDB) Allocate(ctx context.Context, projectID uuid.UUID, bucket, encryptedPath []byte) (version int32, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = db.db.QueryRowContext(ctx, db.db.Rebind(`
+		INSERT INTO versioned_objects (project_id, bucket_name, encrypted_path, current_version, latest_version)
+		VALUES (?, ?, ?, 0, 1)
+		ON CONFLICT (project_id, bucket_name, encrypted_path)
+		DO UPDATE SET latest_version = versioned_objects.latest_version + 1
+		RETURNING latest_version
+	`), projectID[:], bucket, encryptedPath).Scan(&version)
+	return version, Error.Wrap(err)
+}
@@ -38,8 +38,105 @@ type overlaycache struct {
 	db *satelliteDB
 }
 
+// placementOverselection is how many times more candidates are selected than
+// requested, when the candidates need to be filtered by placement limits.
+const placementOverselection = 3
+
 func (cache *overlaycache) SelectStorageNodes(ctx context.Context, count int, criteria *overlay.NodeCriteria) (nodes []*pb.Node, err error) {
 	defer mon.Task()(&ctx)(&err)
+	return cache.selectWithPlacement(ctx, count, criteria, cache.selectStorageNodes)
+}
+
+func (cache *overlaycache) SelectNewStorageNodes(ctx context.Context, count int, criteria *overlay.NodeCriteria) (nodes []*pb.Node, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return cache.selectWithPlacement(ctx, count, criteria, cache.selectNewStorageNodes)
+}
+
+// selectWithPlacement selects count nodes with selectNodes, such that the placement
+// limits in criteria are not exceeded. Nodes in criteria.ExcludedNodes are assumed
+// to already store a piece of the segment and count towards the limits.
+func (cache *overlaycache) selectWithPlacement(ctx context.Context, count int, criteria *overlay.NodeCriteria,
+	selectNodes func(context.Context, int, *overlay.NodeCriteria) ([]*pb.Node, error)) (_ []*pb.Node, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if criteria.Placement.IsUnlimited() {
+		return selectNodes(ctx, count, criteria)
+	}
+
+	// selectNodes may modify the excluded nodes, so keep a copy of the original ones
+	excludedNodes := append(storj.NodeIDList{}, criteria.ExcludedNodes...)
+
+	candidates, err := selectNodes(ctx, count*placementOverselection, criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeIDs := append(storj.NodeIDList{}, excludedNodes...)
+	for _, candidate := range candidates {
+		nodeIDs = append(nodeIDs, candidate.Id)
+	}
+	locations, err := cache.getLocations(ctx, nodeIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	tracker := overlay.NewPlacementTracker(criteria.Placement)
+	for _, id := range excludedNodes {
+		tracker.Add(locations[id])
+	}
+
+	nodes := make([]*pb.Node, 0, count)
+	for _, candidate := range candidates {
+		if len(nodes) >= count {
+			break
+		}
+		location := locations[candidate.Id]
+		if !tracker.Allowed(location) {
+			mon.Event("node_selection_placement_rejected")
+			continue
+		}
+		tracker.Add(location)
+		nodes = append(nodes, candidate)
+	}
+
+	return nodes, nil
+}
+
+// getLocations returns the known locations of the given nodes.
+func (cache *overlaycache) getLocations(ctx context.Context, nodeIDs storj.NodeIDList) (_ map[storj.NodeID]overlay.NodeLocation, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	locations := make(map[storj.NodeID]overlay.NodeLocation, len(nodeIDs))
+	if len(nodeIDs) == 0 {
+		return locations, nil
+	}
+
+	rows, err := cache.db.Query(ctx, cache.db.Rebind(`
+		SELECT node_id, subnet, asn, region FROM node_locations
+			WHERE node_id = any($1::bytea[])
+		`), postgresNodeIDList(nodeIDs),
+	)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	for rows.Next() {
+		var id storj.NodeID
+		var location overlay.NodeLocation
+		var asn int64
+		err = rows.Scan(&id, &location.Subnet, &asn, &location.Region)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		location.ASN = uint32(asn)
+		locations[id] = location
+	}
+	return locations, Error.Wrap(rows.Err())
+}
+
+func (cache *overlaycache) selectStorageNodes(ctx context.Context, count int, criteria *overlay.NodeCriteria) (nodes []*pb.Node, err error) {
+	defer mon.Task()(&ctx)(&err)
 
 	nodeType := int(pb.NodeType_STORAGE)
 
@@ -94,7 +191,7 @@ func (cache *overlaycache) SelectStorageNodes(ctx context.Context, count int, cr
 	return nodes, nil
 }
 
-func (cache *overlaycache) SelectNewStorageNodes(ctx context.Context, count int, criteria *overlay.NodeCriteria) (nodes []*pb.Node, err error) {
+func (cache *overlaycache) selectNewStorageNodes(ctx context.Context, count int, criteria *overlay.NodeCriteria) (nodes []*pb.Node, err error) {
 	defer mon.Task()(&ctx)(&err)
 
 	nodeType := int(pb.NodeType_STORAGE)
@@ -148,6 +245,64 @@ func (cache *overlaycache) SelectNewStorageNodes(ctx context.Context, count int,
 	return nodes, nil
 }
 
+// SelectAllStorageNodesUpload returns all nodes that qualify to store data, organized as reputable nodes and new nodes.
+func (cache *overlaycache) SelectAllStorageNodesUpload(ctx context.Context, selectionConfig overlay.NodeSelectionConfig) (reputable, new []*overlay.SelectedNode, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	query := `
+		SELECT id, address, last_net, protocol, free_bandwidth, free_disk,
+			total_audit_count, total_uptime_count,
+			COALESCE(subnet, ''), COALESCE(asn, 0), COALESCE(region, '')
+		FROM nodes
+		LEFT JOIN node_locations ON node_locations.node_id = nodes.id
+		WHERE disqualified IS NULL
+		AND exit_initiated_at IS NULL
+		AND type = ?
+		AND last_contact_success > ?`
+	args := []interface{}{int(pb.NodeType_STORAGE), time.Now().Add(-selectionConfig.OnlineWindow)}
+
+	if selectionConfig.MinimumVersion != "" {
+		v, err := version.NewSemVer(selectionConfig.MinimumVersion)
+		if err != nil {
+			return nil, nil, Error.New("invalid node selection criteria version: %v", err)
+		}
+		query += `
+		AND (major > ? OR (major = ? AND (minor > ? OR (minor = ? AND patch >= ?))))
+		AND release`
+		args = append(args, v.Major, v.Major, v.Minor, v.Minor, v.Patch)
+	}
+
+	rows, err := cache.db.Query(ctx, cache.db.Rebind(query), args...)
+	if err != nil {
+		return nil, nil, Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	for rows.Next() {
+		var node overlay.SelectedNode
+		var address string
+		var protocol int
+		var auditCount, uptimeCount int64
+		var asn int64
+		err = rows.Scan(&node.ID, &address, &node.LastNet, &protocol, &node.FreeBandwidth, &node.FreeDisk,
+			&auditCount, &uptimeCount,
+			&node.Location.Subnet, &asn, &node.Location.Region)
+		if err != nil {
+			return nil, nil, Error.Wrap(err)
+		}
+		node.Address = &pb.NodeAddress{Address: address, Transport: pb.NodeTransport(protocol)}
+		node.Location.ASN = uint32(asn)
+
+		if auditCount < selectionConfig.AuditCount || uptimeCount < selectionConfig.UptimeCount {
+			new = append(new, &node)
+		} else {
+			reputable = append(reputable, &node)
+		}
+	}
+
+	return reputable, new, Error.Wrap(rows.Err())
+}
+
 // GetNodeIPs returns a list of node IP addresses. Warning: these node IP addresses might be returned out of order.
 func (cache *overlaycache) GetNodeIPs(ctx context.Context, nodeIDs []storj.NodeID) (nodeIPs []string, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -446,6 +601,29 @@ func (cache *overlaycache) Reliable(ctx context.Context, criteria *overlay.NodeC
 	return nodes, Error.Wrap(rows.Err())
 }
 
+// Disqualified returns all nodes that are disqualified.
+func (cache *overlaycache) Disqualified(ctx context.Context) (nodes storj.NodeIDList, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := cache.db.Query(ctx, `SELECT id FROM nodes WHERE disqualified IS NOT NULL`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = errs.Combine(err, rows.Close())
+	}()
+
+	for rows.Next() {
+		var id storj.NodeID
+		err = rows.Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, id)
+	}
+	return nodes, Error.Wrap(rows.Err())
+}
+
 // Paginate will run through
 func (cache *overlaycache) Paginate(ctx context.Context, offset int64, limit int) (_ []*overlay.NodeDossier, _ bool, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -1486,5 +1664,20 @@ func (cache *overlaycache) UpdateCheckIn(ctx context.Context, node overlay.NodeC
 		return Error.Wrap(err)
 	}
 
+	if node.Location.Subnet != "" {
+		_, err = cache.db.ExecContext(ctx, `
+			INSERT INTO node_locations
+				(node_id, subnet, asn, region, updated_at)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (node_id)
+			DO UPDATE
+			SET subnet=$2, asn=$3, region=$4, updated_at=$5
+			`, node.NodeID.Bytes(), node.Location.Subnet, int64(node.Location.ASN), node.Location.Region, timestamp,
+		)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+	}
+
 	return nil
 }
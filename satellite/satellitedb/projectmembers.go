@@ -5,6 +5,7 @@ package satellitedb
 
 import (
 	"context"
+	"database/sql"
 	"strings"
 
 	"github.com/skyrings/skyring-common/tools/uuid"
@@ -86,7 +87,7 @@ func (pm *projectMembers) GetPagedByProjectID(ctx context.Context, projectID uui
 	}
 	// TODO: LIKE is case-sensitive postgres, however this should be case-insensitive and possibly allow typos
 	reboundQuery := pm.db.Rebind(`
-		SELECT pm.*
+		SELECT pm.member_id, pm.project_id, pm.role, pm.created_at
 			FROM project_members pm
 				INNER JOIN users u ON pm.member_id = u.id
 				WHERE pm.project_id = ?
@@ -120,7 +121,7 @@ func (pm *projectMembers) GetPagedByProjectID(ctx context.Context, projectID uui
 		var memberIDBytes, projectIDBytes []uint8
 		var memberID, projectID uuid.UUID
 
-		err = rows.Scan(&memberIDBytes, &projectIDBytes, &pm.CreatedAt)
+		err = rows.Scan(&memberIDBytes, &projectIDBytes, &pm.Role, &pm.CreatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -158,11 +159,12 @@ func (pm *projectMembers) GetPagedByProjectID(ctx context.Context, projectID uui
 }
 
 // Insert is a method for inserting project member into the database.
-func (pm *projectMembers) Insert(ctx context.Context, memberID, projectID uuid.UUID) (_ *console.ProjectMember, err error) {
+func (pm *projectMembers) Insert(ctx context.Context, memberID, projectID uuid.UUID, role console.ProjectRole) (_ *console.ProjectMember, err error) {
 	defer mon.Task()(&ctx)(&err)
 	createdProjectMember, err := pm.methods.Create_ProjectMember(ctx,
 		dbx.ProjectMember_MemberId(memberID[:]),
-		dbx.ProjectMember_ProjectId(projectID[:]))
+		dbx.ProjectMember_ProjectId(projectID[:]),
+		dbx.ProjectMember_Role(int(role)))
 	if err != nil {
 		return nil, err
 	}
@@ -170,6 +172,25 @@ func (pm *projectMembers) Insert(ctx context.Context, memberID, projectID uuid.U
 	return projectMemberFromDBX(ctx, createdProjectMember)
 }
 
+// UpdateRole is a method for changing the role of a project member.
+func (pm *projectMembers) UpdateRole(ctx context.Context, memberID, projectID uuid.UUID, role console.ProjectRole) (_ *console.ProjectMember, err error) {
+	defer mon.Task()(&ctx)(&err)
+	updatedProjectMember, err := pm.methods.Update_ProjectMember_By_MemberId_And_ProjectId(ctx,
+		dbx.ProjectMember_MemberId(memberID[:]),
+		dbx.ProjectMember_ProjectId(projectID[:]),
+		dbx.ProjectMember_Update_Fields{
+			Role: dbx.ProjectMember_Role(int(role)),
+		})
+	if err != nil {
+		return nil, err
+	}
+	if updatedProjectMember == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	return projectMemberFromDBX(ctx, updatedProjectMember)
+}
+
 // Delete is a method for deleting project member by memberID and projectID from the database.
 func (pm *projectMembers) Delete(ctx context.Context, memberID, projectID uuid.UUID) (err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -202,6 +223,7 @@ func projectMemberFromDBX(ctx context.Context, projectMember *dbx.ProjectMember)
 	return &console.ProjectMember{
 		MemberID:  memberID,
 		ProjectID: projectID,
+		Role:      console.ProjectRole(projectMember.Role),
 		CreatedAt: projectMember.CreatedAt,
 	}, nil
 }
@@ -11,7 +11,7 @@ import (
 
 	"storj.io/common/pb"
 	"storj.io/storj/private/dbutil"
-	"storj.io/storj/private/dbutil/pgutil"
+	"storj.io/storj/satellite/repair/queue"
 	"storj.io/storj/storage"
 )
 
@@ -22,16 +22,15 @@ type repairQueue struct {
 	db *satelliteDB
 }
 
-func (r *repairQueue) Insert(ctx context.Context, seg *pb.InjuredSegment) (err error) {
+func (r *repairQueue) Insert(ctx context.Context, seg *pb.InjuredSegment, segmentHealth float64) (err error) {
 	defer mon.Task()(&ctx)(&err)
-	_, err = r.db.ExecContext(ctx, r.db.Rebind(`INSERT INTO injuredsegments ( path, data ) VALUES ( ?, ? )`), seg.Path, seg)
-	if err != nil {
-		if pgutil.IsConstraintError(err) {
-			return nil // quietly fail on reinsert
-		}
-		return err
-	}
-	return nil
+	// on reinsert only the health is updated, so that the segment keeps its
+	// original data and attempted time
+	_, err = r.db.ExecContext(ctx, r.db.Rebind(`
+		INSERT INTO injuredsegments ( path, data, segment_health ) VALUES ( ?, ?, ? )
+		ON CONFLICT ( path ) DO UPDATE SET segment_health = EXCLUDED.segment_health
+	`), seg.Path, seg, segmentHealth)
+	return Error.Wrap(err)
 }
 
 func (r *repairQueue) Select(ctx context.Context) (seg *pb.InjuredSegment, err error) {
@@ -42,14 +41,14 @@ func (r *repairQueue) Select(ctx context.Context) (seg *pb.InjuredSegment, err e
 				UPDATE injuredsegments SET attempted = now() AT TIME ZONE 'UTC' WHERE path = (
 					SELECT path FROM injuredsegments
 					WHERE attempted IS NULL OR attempted < now() AT TIME ZONE 'UTC' - interval '1 hour'
-					ORDER BY attempted LIMIT 1
+					ORDER BY segment_health ASC, attempted LIMIT 1
 				) RETURNING data`).Scan(&seg)
 	case dbutil.Postgres:
 		err = r.db.QueryRowContext(ctx, `
 				UPDATE injuredsegments SET attempted = now() AT TIME ZONE 'UTC' WHERE path = (
 					SELECT path FROM injuredsegments
 					WHERE attempted IS NULL OR attempted < now() AT TIME ZONE 'UTC' - interval '1 hour'
-					ORDER BY attempted NULLS FIRST FOR UPDATE SKIP LOCKED LIMIT 1
+					ORDER BY segment_health ASC, attempted NULLS FIRST FOR UPDATE SKIP LOCKED LIMIT 1
 				) RETURNING data`).Scan(&seg)
 	default:
 		return seg, errs.New("invalid dbType: %v", r.db.implementation)
@@ -98,3 +97,41 @@ func (r *repairQueue) Count(ctx context.Context) (count int, err error) {
 
 	return count, Error.Wrap(err)
 }
+
+func (r *repairQueue) List(ctx context.Context, cursor []byte, limit int) (segs []queue.QueuedSegment, more bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+	if limit <= 0 || limit > RepairQueueSelectLimit {
+		limit = RepairQueueSelectLimit
+	}
+	if cursor == nil {
+		cursor = []byte{}
+	}
+
+	rows, err := r.db.QueryContext(ctx, r.db.Rebind(`
+		SELECT data, segment_health, attempted FROM injuredsegments
+		WHERE path > ?
+		ORDER BY path
+		LIMIT ?
+	`), cursor, limit+1)
+	if err != nil {
+		return nil, false, Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	for rows.Next() {
+		var seg queue.QueuedSegment
+		err = rows.Scan(&seg.Segment, &seg.SegmentHealth, &seg.Attempted)
+		if err != nil {
+			return segs, false, Error.Wrap(err)
+		}
+		segs = append(segs, seg)
+	}
+	if err := rows.Err(); err != nil {
+		return segs, false, Error.Wrap(err)
+	}
+
+	if len(segs) > limit {
+		return segs[:limit], true, nil
+	}
+	return segs, false, nil
+}
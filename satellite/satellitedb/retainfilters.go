@@ -0,0 +1,141 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package satellitedb
+
+import (
+	"context"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/storj"
+	"storj.io/storj/satellite/gc"
+)
+
+var _ gc.DB = (*retainFiltersDB)(nil)
+
+type retainFiltersDB struct {
+	db *satelliteDB
+}
+
+// SetRetainFilter stores the filter for a node, replacing the filter of an older generation.
+func (db *retainFiltersDB) SetRetainFilter(ctx context.Context, filter gc.RetainFilter) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	_, err = db.db.ExecContext(ctx, db.db.Rebind(`
+		INSERT INTO retain_filters (node_id, creation_date, piece_count, filter)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (node_id)
+		DO UPDATE SET
+			creation_date = EXCLUDED.creation_date,
+			piece_count = EXCLUDED.piece_count,
+			filter = EXCLUDED.filter,
+			sent_at = NULL,
+			send_attempts = 0,
+			last_attempt_at = NULL
+	`), filter.NodeID.Bytes(), filter.CreationDate, int64(filter.PieceCount), filter.Filter)
+	return Error.Wrap(err)
+}
+
+// ListUnsentRetainFilters returns filters that have not been sent yet and were created after createdAfter,
+// ordered by node ID and starting after cursor.
+func (db *retainFiltersDB) ListUnsentRetainFilters(ctx context.Context, cursor storj.NodeID, createdAfter time.Time, limit int) (_ []gc.RetainFilter, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := db.db.QueryContext(ctx, db.db.Rebind(`
+		SELECT node_id, creation_date, piece_count, filter
+		FROM retain_filters
+		WHERE node_id > ?
+			AND sent_at IS NULL
+			AND creation_date > ?
+		ORDER BY node_id
+		LIMIT ?
+	`), cursor.Bytes(), createdAfter, limit)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	var filters []gc.RetainFilter
+	for rows.Next() {
+		var filter gc.RetainFilter
+		var pieceCount int64
+		err = rows.Scan(&filter.NodeID, &filter.CreationDate, &pieceCount, &filter.Filter)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		filter.PieceCount = int(pieceCount)
+		filters = append(filters, filter)
+	}
+	return filters, Error.Wrap(rows.Err())
+}
+
+// RecordRetainFilterSend records an attempt to send the filter of the given generation to a node.
+func (db *retainFiltersDB) RecordRetainFilterSend(ctx context.Context, nodeID storj.NodeID, creationDate time.Time, success bool, attemptedAt time.Time) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	_, err = db.db.ExecContext(ctx, `
+		UPDATE retain_filters SET
+			send_attempts = send_attempts + 1,
+			last_attempt_at = $3::timestamptz,
+			sent_at = CASE WHEN $4::bool THEN $3::timestamptz ELSE sent_at END
+		WHERE node_id = $1
+			AND creation_date = $2
+	`, nodeID.Bytes(), creationDate, attemptedAt, success)
+	return Error.Wrap(err)
+}
+
+// ListRetainFilterStatus returns the status of the stored filters, without the filters themselves,
+// ordered by node ID and starting after cursor.
+func (db *retainFiltersDB) ListRetainFilterStatus(ctx context.Context, cursor storj.NodeID, limit int) (statuses []gc.RetainFilterStatus, more bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := db.db.QueryContext(ctx, db.db.Rebind(`
+		SELECT node_id, creation_date, piece_count, octet_length(filter),
+			sent_at, send_attempts, last_attempt_at
+		FROM retain_filters
+		WHERE node_id > ?
+		ORDER BY node_id
+		LIMIT ?
+	`), cursor.Bytes(), limit+1)
+	if err != nil {
+		return nil, false, Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	for rows.Next() {
+		var status gc.RetainFilterStatus
+		var pieceCount int64
+		err = rows.Scan(&status.NodeID, &status.CreationDate, &pieceCount, &status.FilterSize,
+			&status.SentAt, &status.SendAttempts, &status.LastAttemptAt)
+		if err != nil {
+			return nil, false, Error.Wrap(err)
+		}
+		status.PieceCount = int(pieceCount)
+		statuses = append(statuses, status)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, Error.Wrap(err)
+	}
+
+	if len(statuses) > limit {
+		statuses, more = statuses[:limit], true
+	}
+	return statuses, more, nil
+}
+
+// DeleteExpiredRetainFilters deletes filters created before the given time.
+func (db *retainFiltersDB) DeleteExpiredRetainFilters(ctx context.Context, createdBefore time.Time) (_ int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	result, err := db.db.ExecContext(ctx, db.db.Rebind(`
+		DELETE FROM retain_filters WHERE creation_date < ?
+	`), createdBefore)
+	if err != nil {
+		return 0, Error.Wrap(err)
+	}
+
+	deleted, err := result.RowsAffected()
+	return deleted, Error.Wrap(err)
+}
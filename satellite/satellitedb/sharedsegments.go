@@ -0,0 +1,83 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package satellitedb
+
+import (
+	"context"
+	"database/sql"
+
+	"storj.io/common/storj"
+	"storj.io/storj/satellite/metainfo"
+	"storj.io/storj/satellite/satellitedb/dbx"
+)
+
+var _ metainfo.SharedSegmentsDB = (*sharedSegmentsDB)(nil)
+
+type sharedSegmentsDB struct {
+	db *satelliteDB
+}
+
+// SharedSegments returns database for segments shared between copied objects
+func (db *satelliteDB) SharedSegments() metainfo.SharedSegmentsDB {
+	return &sharedSegmentsDB{db: db}
+}
+
+// AddReferences records one more reference to each of the root piece IDs.
+// Either all references are added or none of them.
+func (db *sharedSegmentsDB) AddReferences(ctx context.Context, rootPieceIDs []storj.PieceID) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = db.db.WithTx(ctx, func(ctx context.Context, tx *dbx.Tx) error {
+		for _, rootPieceID := range rootPieceIDs {
+			_, err := tx.Tx.ExecContext(ctx, db.db.Rebind(`
+				INSERT INTO shared_segments (root_piece_id, reference_count)
+				VALUES (?, 1)
+				ON CONFLICT (root_piece_id)
+				DO UPDATE SET reference_count = shared_segments.reference_count + 1
+			`), rootPieceID.Bytes())
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return Error.Wrap(err)
+}
+
+// RemoveReference removes one reference to the root piece ID. It returns false
+// when there are no other references, so the pieces can be deleted. When it
+// fails no reference was removed.
+func (db *sharedSegmentsDB) RemoveReference(ctx context.Context, rootPieceID storj.PieceID) (shared bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = db.db.WithTx(ctx, func(ctx context.Context, tx *dbx.Tx) error {
+		var references int
+		err := tx.Tx.QueryRowContext(ctx, db.db.Rebind(`
+			UPDATE shared_segments
+			SET reference_count = reference_count - 1
+			WHERE root_piece_id = ?
+			RETURNING reference_count
+		`), rootPieceID.Bytes()).Scan(&references)
+		if err == sql.ErrNoRows {
+			shared = false
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		shared = true
+		if references <= 0 {
+			_, err = tx.Tx.ExecContext(ctx, db.db.Rebind(`
+				DELETE FROM shared_segments
+				WHERE root_piece_id = ? AND reference_count <= 0
+			`), rootPieceID.Bytes())
+		}
+		return err
+	})
+	if err != nil {
+		return false, Error.Wrap(err)
+	}
+	return shared, nil
+}
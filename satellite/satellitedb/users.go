@@ -5,7 +5,9 @@ package satellitedb
 
 import (
 	"context"
+	"encoding/json"
 	"strings"
+	"time"
 
 	"github.com/skyrings/skyring-common/tools/uuid"
 	"github.com/zeebo/errs"
@@ -20,7 +22,8 @@ var _ console.Users = (*users)(nil)
 
 // implementation of Users interface repository using spacemonkeygo/dbx orm
 type users struct {
-	db dbx.Methods
+	db  dbx.Methods
+	sdb *satelliteDB
 }
 
 // Get is a method for querying user from the database by id
@@ -60,6 +63,16 @@ func (users *users) Insert(ctx context.Context, user *console.User) (_ *console.
 	if !user.PartnerID.IsZero() {
 		optional.PartnerId = dbx.User_PartnerId(user.PartnerID[:])
 	}
+	if user.MFASecretKey != "" {
+		optional.MfaSecretKey = dbx.User_MfaSecretKey(user.MFASecretKey)
+	}
+	if len(user.MFARecoveryCodes) > 0 {
+		recoveryCodes, err := json.Marshal(user.MFARecoveryCodes)
+		if err != nil {
+			return nil, err
+		}
+		optional.MfaRecoveryCodes = dbx.User_MfaRecoveryCodes(string(recoveryCodes))
+	}
 
 	createdUser, err := users.db.Create_User(ctx,
 		dbx.User_Id(user.ID[:]),
@@ -67,6 +80,7 @@ func (users *users) Insert(ctx context.Context, user *console.User) (_ *console.
 		dbx.User_NormalizedEmail(normalizeEmail(user.Email)),
 		dbx.User_FullName(user.FullName),
 		dbx.User_PasswordHash(user.PasswordHash),
+		dbx.User_MfaEnabled(user.MFAEnabled),
 		optional,
 	)
 
@@ -98,6 +112,174 @@ func (users *users) Update(ctx context.Context, user *console.User) (err error)
 	return err
 }
 
+// UseMFAPasscode stores the counter of an accepted MFA passcode and resets
+// the failed MFA attempts, unless a passcode with the same or a later counter
+// has been accepted before.
+func (users *users) UseMFAPasscode(ctx context.Context, id uuid.UUID, counter int64) (_ bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	result, err := users.sdb.ExecContext(ctx, users.sdb.Rebind(`
+		UPDATE users
+		SET mfa_passcode_counter = ?, mfa_failed_attempts = 0, mfa_failed_at = NULL
+		WHERE id = ? AND mfa_passcode_counter < ?
+	`), counter, id[:], counter)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// UseMFARecoveryCode replaces the recovery codes with the remaining ones and
+// resets the failed MFA attempts, unless the recovery codes have been changed
+// since they were read, e.g. by using the same code concurrently.
+func (users *users) UseMFARecoveryCode(ctx context.Context, id uuid.UUID, codes, remaining []string) (_ bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	expected, err := json.Marshal(codes)
+	if err != nil {
+		return false, err
+	}
+	var replacement *string
+	if len(remaining) > 0 {
+		data, err := json.Marshal(remaining)
+		if err != nil {
+			return false, err
+		}
+		value := string(data)
+		replacement = &value
+	}
+
+	result, err := users.sdb.ExecContext(ctx, users.sdb.Rebind(`
+		UPDATE users
+		SET mfa_recovery_codes = ?, mfa_failed_attempts = 0, mfa_failed_at = NULL
+		WHERE id = ? AND mfa_recovery_codes = ?
+	`), replacement, id[:], string(expected))
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// CountMFAAttempt records an MFA attempt, unless maxAttempts attempts have
+// been recorded within the window. The attempt is recorded before it's
+// checked, so that concurrent attempts can't exceed the limit.
+func (users *users) CountMFAAttempt(ctx context.Context, id uuid.UUID, now time.Time, window time.Duration, maxAttempts int) (_ bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	windowStart := now.Add(-window).UTC()
+	result, err := users.sdb.ExecContext(ctx, users.sdb.Rebind(`
+		UPDATE users
+		SET mfa_failed_attempts = CASE
+				WHEN mfa_failed_at IS NULL OR mfa_failed_at < ? THEN 1
+				ELSE mfa_failed_attempts + 1
+			END,
+			mfa_failed_at = ?
+		WHERE id = ? AND (mfa_failed_at IS NULL OR mfa_failed_at < ? OR mfa_failed_attempts < ?)
+	`), windowStart, now.UTC(), id[:], windowStart, maxAttempts)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// SetMFASecretKey stores a new MFA secret key, unless MFA is enabled.
+func (users *users) SetMFASecretKey(ctx context.Context, id uuid.UUID, key string) (_ bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	result, err := users.sdb.ExecContext(ctx, users.sdb.Rebind(`
+		UPDATE users
+		SET mfa_secret_key = ?
+		WHERE id = ? AND mfa_enabled = false
+	`), key, id[:])
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// EnableMFA enables MFA with the recovery codes, unless MFA is enabled or the
+// secret key has been changed since it was verified.
+func (users *users) EnableMFA(ctx context.Context, id uuid.UUID, key string, recoveryCodes []string) (_ bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	codes, err := json.Marshal(recoveryCodes)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := users.sdb.ExecContext(ctx, users.sdb.Rebind(`
+		UPDATE users
+		SET mfa_enabled = true, mfa_recovery_codes = ?
+		WHERE id = ? AND mfa_enabled = false AND mfa_secret_key = ?
+	`), string(codes), id[:], key)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// SetMFARecoveryCodes replaces the recovery codes, unless MFA is disabled.
+func (users *users) SetMFARecoveryCodes(ctx context.Context, id uuid.UUID, recoveryCodes []string) (_ bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	codes, err := json.Marshal(recoveryCodes)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := users.sdb.ExecContext(ctx, users.sdb.Rebind(`
+		UPDATE users
+		SET mfa_recovery_codes = ?
+		WHERE id = ? AND mfa_enabled = true
+	`), string(codes), id[:])
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// DisableMFA disables MFA and removes the secret key and the recovery codes.
+func (users *users) DisableMFA(ctx context.Context, id uuid.UUID) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	_, err = users.sdb.ExecContext(ctx, users.sdb.Rebind(`
+		UPDATE users
+		SET mfa_enabled = false, mfa_secret_key = NULL, mfa_recovery_codes = NULL
+		WHERE id = ?
+	`), id[:])
+	return err
+}
+
 // toUpdateUser creates dbx.User_Update_Fields with only non-empty fields as updatable
 func toUpdateUser(user *console.User) dbx.User_Update_Fields {
 	update := dbx.User_Update_Fields{
@@ -135,6 +317,10 @@ func userFromDBX(ctx context.Context, user *dbx.User) (_ *console.User, err erro
 		PasswordHash: user.PasswordHash,
 		Status:       console.UserStatus(user.Status),
 		CreatedAt:    user.CreatedAt,
+		MFAEnabled:   user.MfaEnabled,
+
+		MFAPasscodeCounter: user.MfaPasscodeCounter,
+		MFAFailedAttempts:  user.MfaFailedAttempts,
 	}
 
 	if user.PartnerId != nil {
@@ -148,6 +334,21 @@ func userFromDBX(ctx context.Context, user *dbx.User) (_ *console.User, err erro
 		result.ShortName = *user.ShortName
 	}
 
+	if user.MfaSecretKey != nil {
+		result.MFASecretKey = *user.MfaSecretKey
+	}
+
+	if user.MfaFailedAt != nil {
+		result.MFAFailedAt = *user.MfaFailedAt
+	}
+
+	if user.MfaRecoveryCodes != nil {
+		err = json.Unmarshal([]byte(*user.MfaRecoveryCodes), &result.MFARecoveryCodes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &result, nil
 }
 
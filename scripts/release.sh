@@ -26,9 +26,21 @@ else
   RELEASE=false
 fi
 
-echo Running "go $@"
-exec go "$1" -ldflags \
-	"-s -w -X storj.io/storj/private/version.buildTimestamp=$TIMESTAMP
+LDFLAGS="-s -w -X storj.io/storj/private/version.buildTimestamp=$TIMESTAMP
          -X storj.io/storj/private/version.buildCommitHash=$COMMIT
          -X storj.io/storj/private/version.buildVersion=$VERSION
-         -X storj.io/storj/private/version.buildRelease=$RELEASE" "${@:2}"
+         -X storj.io/storj/private/version.buildRelease=$RELEASE"
+
+# storagenode-updater pins the ed25519 public key which verifies the binaries it downloads
+if [[ "${@: -1}" == */cmd/storagenode-updater ]]; then
+  RELEASE_PUBLIC_KEY=${RELEASE_PUBLIC_KEY:-}
+  if [[ ! "$RELEASE_PUBLIC_KEY" =~ ^[0-9a-fA-F]{64}$ ]]; then
+    echo "RELEASE_PUBLIC_KEY must be set to the hex-encoded ed25519 public key of the release binaries" >&2
+    exit 1
+  fi
+  LDFLAGS="$LDFLAGS
+         -X main.releasePublicKey=$RELEASE_PUBLIC_KEY"
+fi
+
+echo Running "go $@"
+exec go "$1" -ldflags "$LDFLAGS" "${@:2}"
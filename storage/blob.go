@@ -85,6 +85,10 @@ type Blobs interface {
 	RestoreTrash(ctx context.Context, namespace []byte) ([][]byte, error)
 	// EmptyTrash removes all files in trash that were moved to trash prior to trashedBefore and returns the total bytes emptied and keys deleted
 	EmptyTrash(ctx context.Context, namespace []byte, trashedBefore time.Time) (int64, [][]byte, error)
+	// Quarantine moves a damaged blob aside, so that it's no longer served but can still be inspected
+	Quarantine(ctx context.Context, ref BlobRef) error
+	// EmptyQuarantine removes all blobs in quarantine that were quarantined prior to quarantinedBefore and returns the total bytes emptied and keys deleted
+	EmptyQuarantine(ctx context.Context, namespace []byte, quarantinedBefore time.Time) (int64, [][]byte, error)
 	// Stat looks up disk metadata on the blob file
 	Stat(ctx context.Context, ref BlobRef) (BlobInfo, error)
 	// StatWithStorageFormat looks up disk metadata for the blob file with the given storage format
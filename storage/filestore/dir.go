@@ -11,6 +11,7 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -52,6 +53,7 @@ func NewDir(path string) (*Dir, error) {
 		os.MkdirAll(dir.tempdir(), dirPermission),
 		os.MkdirAll(dir.garbagedir(), dirPermission),
 		os.MkdirAll(dir.trashdir(), dirPermission),
+		os.MkdirAll(dir.quarantinedir(), dirPermission),
 	)
 }
 
@@ -70,6 +72,9 @@ func (dir *Dir) garbagedir() string { return filepath.Join(dir.path, "garbage")
 // trashdir contains files staged for deletion for a period of time
 func (dir *Dir) trashdir() string { return filepath.Join(dir.path, "trash") }
 
+// quarantinedir contains damaged files which are kept for inspection
+func (dir *Dir) quarantinedir() string { return filepath.Join(dir.path, "quarantine") }
+
 // CreateTemporaryFile creates a preallocated temporary file in the temp directory
 // prealloc preallocates file to make writing faster
 func (dir *Dir) CreateTemporaryFile(ctx context.Context, prealloc int64) (_ *os.File, err error) {
@@ -352,6 +357,52 @@ func (dir *Dir) TrashWithStorageFormat(ctx context.Context, ref storage.BlobRef,
 	return err
 }
 
+// Quarantine moves the piece specified by ref to the quarantinedir for every format version
+func (dir *Dir) Quarantine(ctx context.Context, ref storage.BlobRef) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return dir.iterateStorageFormatVersions(ctx, ref, dir.quarantineWithStorageFormat)
+}
+
+// quarantineWithStorageFormat moves the piece specified by ref to the quarantinedir for the specified format version
+func (dir *Dir) quarantineWithStorageFormat(ctx context.Context, ref storage.BlobRef, formatVer storage.FormatVersion) (err error) {
+	blobsBasePath, err := dir.blobToBasePath(ref)
+	if err != nil {
+		return err
+	}
+	blobsVerPath := blobPathForFormatVersion(blobsBasePath, formatVer)
+
+	quarantineBasePath, err := dir.refToDirPath(ref, dir.quarantinedir())
+	if err != nil {
+		return err
+	}
+	quarantineVerPath := blobPathForFormatVersion(quarantineBasePath, formatVer)
+
+	// ensure the dirs exist for quarantine path
+	err = os.MkdirAll(filepath.Dir(quarantineVerPath), dirPermission)
+	if err != nil && !os.IsExist(err) {
+		return err
+	}
+
+	// like in the trash, the mtime tells how long the file has been in the
+	// quarantine.
+	now := time.Now()
+	err = os.Chtimes(blobsVerPath, now, now)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	err = rename(blobsVerPath, quarantineVerPath)
+	if os.IsNotExist(err) {
+		// no piece at that path; either it has a different storage format
+		// version or there was a concurrent call.
+		return nil
+	}
+	return err
+}
+
 // ReplaceTrashnow is a helper for tests to replace the trashnow function used
 // when moving files to the trash
 func (dir *Dir) ReplaceTrashnow(trashnow func() time.Time) {
@@ -404,15 +455,29 @@ func (dir *Dir) RestoreTrash(ctx context.Context, namespace []byte) (keysRestore
 // Trash is called.
 func (dir *Dir) EmptyTrash(ctx context.Context, namespace []byte, trashedBefore time.Time) (bytesEmptied int64, deletedKeys [][]byte, err error) {
 	defer mon.Task()(&ctx)(&err)
-	err = dir.walkNamespaceInPath(ctx, namespace, dir.trashdir(), func(blobInfo storage.BlobInfo) error {
+	return dir.emptyPath(ctx, namespace, dir.trashdir(), trashedBefore)
+}
+
+// EmptyQuarantine removes all files in the quarantine that were quarantined
+// prior to quarantinedBefore and returns the total bytes emptied and keys deleted.
+func (dir *Dir) EmptyQuarantine(ctx context.Context, namespace []byte, quarantinedBefore time.Time) (bytesEmptied int64, deletedKeys [][]byte, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return dir.emptyPath(ctx, namespace, dir.quarantinedir(), quarantinedBefore)
+}
+
+// emptyPath removes the files of the namespace in path whose mtime is before
+// the specified time.
+func (dir *Dir) emptyPath(ctx context.Context, namespace []byte, path string, before time.Time) (bytesEmptied int64, deletedKeys [][]byte, err error) {
+	defer mon.Task()(&ctx)(&err)
+	err = dir.walkNamespaceInPath(ctx, namespace, path, func(blobInfo storage.BlobInfo) error {
 		fileInfo, err := blobInfo.Stat(ctx)
 		if err != nil {
 			return err
 		}
 
 		mtime := fileInfo.ModTime()
-		if mtime.Before(trashedBefore) {
-			err = dir.deleteWithStorageFormatInPath(ctx, dir.trashdir(), blobInfo.BlobRef(), blobInfo.StorageFormatVersion())
+		if mtime.Before(before) {
+			err = dir.deleteWithStorageFormatInPath(ctx, path, blobInfo.BlobRef(), blobInfo.StorageFormatVersion())
 			if err != nil {
 				return err
 			}
@@ -622,36 +687,37 @@ func (dir *Dir) walkNamespaceInPath(ctx context.Context, namespace []byte, path
 		return err
 	}
 	defer func() { err = errs.Combine(err, openDir.Close()) }()
-	for {
-		// check for context done both before and after our readdir() call
-		if err := ctx.Err(); err != nil {
-			return err
-		}
-		subdirNames, err := openDir.Readdirnames(nameBatchSize)
-		if err != nil {
-			if err == io.EOF || os.IsNotExist(err) {
-				return nil
-			}
-			return err
-		}
-		if len(subdirNames) == 0 {
+
+	// check for context done both before and after our readdir() call
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	// all names are read and sorted, so that blobs are walked in the same
+	// order every time regardless of the order of the directory entries.
+	subdirNames, err := openDir.Readdirnames(-1)
+	if err != nil {
+		if os.IsNotExist(err) {
 			return nil
 		}
-		if err := ctx.Err(); err != nil {
-			return err
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	sort.Strings(subdirNames)
+
+	for _, keyPrefix := range subdirNames {
+		if len(keyPrefix) != 2 {
+			// just an invalid subdir; could be garbage of many kinds. probably
+			// don't need to pass on this error
+			continue
 		}
-		for _, keyPrefix := range subdirNames {
-			if len(keyPrefix) != 2 {
-				// just an invalid subdir; could be garbage of many kinds. probably
-				// don't need to pass on this error
-				continue
-			}
-			err := walkNamespaceWithPrefix(ctx, namespace, nsDir, keyPrefix, walkFunc)
-			if err != nil {
-				return err
-			}
+		err := walkNamespaceWithPrefix(ctx, namespace, nsDir, keyPrefix, walkFunc)
+		if err != nil {
+			return err
 		}
 	}
+	return nil
 }
 
 func decodeBlobInfo(namespace []byte, keyPrefix, keyDir string, keyInfo os.FileInfo) (info storage.BlobInfo, ok bool) {
@@ -680,39 +746,43 @@ func walkNamespaceWithPrefix(ctx context.Context, namespace []byte, nsDir, keyPr
 		return err
 	}
 	defer func() { err = errs.Combine(err, openDir.Close()) }()
-	for {
-		// check for context done both before and after our readdir() call
-		if err := ctx.Err(); err != nil {
-			return err
+
+	// check for context done both before and after our readdir() call
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	keyInfos, err := openDir.Readdir(-1)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
 		}
-		keyInfos, err := openDir.Readdir(nameBatchSize)
-		if err != nil && err != io.EOF {
-			return err
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	sort.Slice(keyInfos, func(i, k int) bool {
+		return keyInfos[i].Name() < keyInfos[k].Name()
+	})
+
+	for _, keyInfo := range keyInfos {
+		if keyInfo.Mode().IsDir() {
+			continue
 		}
-		if os.IsNotExist(err) || len(keyInfos) == 0 {
-			return nil
+		info, ok := decodeBlobInfo(namespace, keyPrefix, keyDir, keyInfo)
+		if !ok {
+			continue
 		}
-		if err := ctx.Err(); err != nil {
+		err = walkFunc(info)
+		if err != nil {
 			return err
 		}
-		for _, keyInfo := range keyInfos {
-			if keyInfo.Mode().IsDir() {
-				continue
-			}
-			info, ok := decodeBlobInfo(namespace, keyPrefix, keyDir, keyInfo)
-			if !ok {
-				continue
-			}
-			err = walkFunc(info)
-			if err != nil {
-				return err
-			}
-			// also check for context done between every walkFunc callback.
-			if err := ctx.Err(); err != nil {
-				return err
-			}
+		// also check for context done between every walkFunc callback.
+		if err := ctx.Err(); err != nil {
+			return err
 		}
 	}
+	return nil
 }
 
 // removeAllContent deletes everything in the folder
@@ -127,6 +127,19 @@ func (store *blobStore) EmptyTrash(ctx context.Context, namespace []byte, trashe
 	return bytesEmptied, keys, Error.Wrap(err)
 }
 
+// Quarantine moves the ref to a quarantine directory
+func (store *blobStore) Quarantine(ctx context.Context, ref storage.BlobRef) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return Error.Wrap(store.dir.Quarantine(ctx, ref))
+}
+
+// EmptyQuarantine removes all files in the quarantine that have been there longer than quarantinedBefore
+func (store *blobStore) EmptyQuarantine(ctx context.Context, namespace []byte, quarantinedBefore time.Time) (bytesEmptied int64, keys [][]byte, err error) {
+	defer mon.Task()(&ctx)(&err)
+	bytesEmptied, keys, err = store.dir.EmptyQuarantine(ctx, namespace, quarantinedBefore)
+	return bytesEmptied, keys, Error.Wrap(err)
+}
+
 // GarbageCollect tries to delete any files that haven't yet been deleted
 func (store *blobStore) GarbageCollect(ctx context.Context) (err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -24,6 +24,7 @@ import (
 	"storj.io/common/testrand"
 	"storj.io/storj/storage"
 	"storj.io/storj/storage/filestore"
+	"storj.io/storj/storage/testsuite"
 )
 
 const (
@@ -822,3 +823,55 @@ func requireFileMatches(ctx context.Context, t *testing.T, store storage.Blobs,
 
 	require.Equal(t, buf, data)
 }
+
+func TestEmptyQuarantine(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	store, err := filestore.NewAt(zaptest.NewLogger(t), ctx.Dir("store"))
+	require.NoError(t, err)
+	ctx.Check(store.Close)
+
+	namespace := testrand.Bytes(namespaceSize)
+	refs := []storage.BlobRef{
+		{Namespace: namespace, Key: testrand.Bytes(keySize)},
+		{Namespace: namespace, Key: testrand.Bytes(keySize)},
+	}
+	for _, ref := range refs {
+		writer, err := store.Create(ctx, ref, 0)
+		require.NoError(t, err)
+		_, err = writer.Write(testrand.Bytes(memory.KiB))
+		require.NoError(t, err)
+		require.NoError(t, writer.Commit(ctx))
+	}
+
+	require.NoError(t, store.Quarantine(ctx, refs[0]))
+	_, err = store.Stat(ctx, refs[0])
+	require.True(t, os.IsNotExist(errs.Unwrap(err)), err)
+
+	// the piece was quarantined just now
+	emptied, keys, err := store.EmptyQuarantine(ctx, namespace, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Zero(t, emptied)
+	require.Empty(t, keys)
+
+	emptied, keys, err = store.EmptyQuarantine(ctx, namespace, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.NotZero(t, emptied)
+	require.Equal(t, [][]byte{refs[0].Key}, keys)
+
+	// pieces which aren't quarantined are kept
+	_, err = store.Stat(ctx, refs[1])
+	require.NoError(t, err)
+}
+
+func TestBlobs(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	store, err := filestore.NewAt(zaptest.NewLogger(t), ctx.Dir("store"))
+	require.NoError(t, err)
+	ctx.Check(store.Close)
+
+	testsuite.RunBlobsTests(t, store)
+}
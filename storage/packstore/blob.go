@@ -0,0 +1,212 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package packstore
+
+import (
+	"context"
+	"encoding/hex"
+	"io"
+	"os"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/storage"
+)
+
+// blobReader reads a blob from a pack file.
+type blobReader struct {
+	*io.SectionReader
+	file          *os.File
+	formatVersion storage.FormatVersion
+}
+
+// Size returns the size of the blob.
+func (blob *blobReader) Size() (int64, error) {
+	return blob.SectionReader.Size(), nil
+}
+
+// StorageFormatVersion returns the storage format version of the blob.
+func (blob *blobReader) StorageFormatVersion() storage.FormatVersion {
+	return blob.formatVersion
+}
+
+// Close closes the pack file.
+func (blob *blobReader) Close() error {
+	return blob.file.Close()
+}
+
+// blobWriter keeps small blobs in memory until they are appended to a pack
+// file on commit. Once a blob grows larger than the maximum packed size, it's
+// written to a separate file instead.
+type blobWriter struct {
+	ctx           context.Context
+	ref           storage.BlobRef
+	store         *Store
+	formatVersion storage.FormatVersion
+	closed        bool
+
+	buf []byte
+	pos int64
+
+	// file is used instead of buf after the blob grew too large.
+	file storage.BlobWriter
+}
+
+// Write writes data at the current position.
+func (blob *blobWriter) Write(data []byte) (int, error) {
+	if blob.closed {
+		return 0, Error.New("already closed")
+	}
+	if blob.file == nil && blob.pos+int64(len(data)) > blob.store.config.MaxPackedSize.Int64() {
+		if err := blob.spill(); err != nil {
+			return 0, err
+		}
+	}
+	if blob.file != nil {
+		return blob.file.Write(data)
+	}
+
+	end := blob.pos + int64(len(data))
+	if end > int64(len(blob.buf)) {
+		blob.buf = append(blob.buf, make([]byte, end-int64(len(blob.buf)))...)
+	}
+	copy(blob.buf[blob.pos:], data)
+	blob.pos = end
+	return len(data), nil
+}
+
+// Seek sets the position of the next write.
+func (blob *blobWriter) Seek(offset int64, whence int) (int64, error) {
+	if blob.file != nil {
+		return blob.file.Seek(offset, whence)
+	}
+
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += blob.pos
+	case io.SeekEnd:
+		offset += int64(len(blob.buf))
+	default:
+		return blob.pos, Error.New("invalid whence %d", whence)
+	}
+	if offset < 0 {
+		return blob.pos, Error.New("negative position %d", offset)
+	}
+	blob.pos = offset
+	return blob.pos, nil
+}
+
+// spill moves the data written so far to a separate file.
+func (blob *blobWriter) spill() (err error) {
+	file, err := blob.store.files.Create(blob.ctx, blob.ref, -1)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(blob.buf); err != nil {
+		return Error.Wrap(errs.Combine(err, file.Cancel(blob.ctx)))
+	}
+	if _, err := file.Seek(blob.pos, io.SeekStart); err != nil {
+		return Error.Wrap(errs.Combine(err, file.Cancel(blob.ctx)))
+	}
+	blob.file = file
+	blob.buf = nil
+	return nil
+}
+
+// Cancel discards the blob.
+func (blob *blobWriter) Cancel(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	if blob.closed {
+		return nil
+	}
+	blob.closed = true
+	if blob.file != nil {
+		return blob.file.Cancel(ctx)
+	}
+	blob.buf = nil
+	return nil
+}
+
+// Commit appends the blob to a pack file, discarding the data after the
+// current position.
+func (blob *blobWriter) Commit(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	if blob.closed {
+		return Error.New("already closed")
+	}
+	blob.closed = true
+	if blob.file != nil {
+		if err := blob.file.Commit(ctx); err != nil {
+			return err
+		}
+		// the file takes the place of a packed blob with the same reference
+		return Error.Wrap(blob.store.index.Update(func(tx *bolt.Tx) error {
+			return deleteLocation(tx, blobsBucket, blob.ref, blob.formatVersion)
+		}))
+	}
+
+	data := blob.buf
+	if int64(len(data)) > blob.pos {
+		data = data[:blob.pos]
+	}
+	err = blob.store.put(ctx, blob.ref, blob.formatVersion, data, time.Now())
+	blob.buf = nil
+	return err
+}
+
+// Size returns how much has been written so far.
+func (blob *blobWriter) Size() (int64, error) {
+	if blob.file != nil {
+		return blob.file.Size()
+	}
+	return blob.pos, nil
+}
+
+// StorageFormatVersion returns the storage format version of the blob.
+func (blob *blobWriter) StorageFormatVersion() storage.FormatVersion {
+	return blob.formatVersion
+}
+
+// blobInfo describes a packed blob.
+type blobInfo struct {
+	ref           storage.BlobRef
+	formatVersion storage.FormatVersion
+	loc           location
+	path          string
+}
+
+// BlobRef returns the reference of the blob.
+func (info *blobInfo) BlobRef() storage.BlobRef { return info.ref }
+
+// StorageFormatVersion returns the storage format version of the blob.
+func (info *blobInfo) StorageFormatVersion() storage.FormatVersion { return info.formatVersion }
+
+// FullPath returns the path of the pack file containing the blob.
+func (info *blobInfo) FullPath(ctx context.Context) (string, error) { return info.path, nil }
+
+// Stat returns the size and modification time of the blob.
+func (info *blobInfo) Stat(ctx context.Context) (os.FileInfo, error) {
+	return &fileInfo{
+		name:    hex.EncodeToString(info.ref.Key),
+		size:    info.loc.Length,
+		modTime: info.loc.ModTime,
+	}, nil
+}
+
+// fileInfo implements os.FileInfo for a packed blob.
+type fileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+}
+
+func (info *fileInfo) Name() string       { return info.name }
+func (info *fileInfo) Size() int64        { return info.size }
+func (info *fileInfo) Mode() os.FileMode  { return packPermission }
+func (info *fileInfo) ModTime() time.Time { return info.modTime }
+func (info *fileInfo) IsDir() bool        { return false }
+func (info *fileInfo) Sys() interface{}   { return nil }
@@ -0,0 +1,182 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package packstore
+
+import (
+	"context"
+	"os"
+
+	"github.com/boltdb/bolt"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/storj/storage"
+)
+
+// indexBuckets are the buckets of the index which refer to pack files.
+var indexBuckets = [][]byte{blobsBucket, trashBucket, quarantineBucket}
+
+// packedBlob is a blob referred to by the index.
+type packedBlob struct {
+	ref       storage.BlobRef
+	formatVer storage.FormatVersion
+	loc       location
+}
+
+// Compact rewrites the pack files which contain more deleted data than the
+// compaction ratio allows, and removes the pack files without any blobs. The
+// active pack file is never compacted.
+func (store *Store) Compact(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	// pack files from the active one onwards may still get new blobs, and
+	// pack files with blobs which aren't in the index yet would look empty
+	store.mu.Lock()
+	limit := store.nextID
+	if store.active != nil {
+		limit = store.active.id
+	}
+	for id := range store.unindexed {
+		if id < limit {
+			limit = id
+		}
+	}
+	store.mu.Unlock()
+
+	ids, err := listPacks(store.dir)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	live := make(map[uint32][]packedBlob)
+	err = store.index.View(func(tx *bolt.Tx) error {
+		for _, bucket := range indexBuckets {
+			for _, namespace := range listNamespaces(tx, bucket) {
+				err := forEachLocation(tx, bucket, namespace, func(key []byte, formatVer storage.FormatVersion, loc location) error {
+					live[loc.Pack] = append(live[loc.Pack], packedBlob{
+						ref:       storage.BlobRef{Namespace: namespace, Key: key},
+						formatVer: formatVer,
+						loc:       loc,
+					})
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if id >= limit {
+			continue
+		}
+
+		stat, err := os.Stat(store.packPath(id))
+		if err != nil {
+			return Error.Wrap(err)
+		}
+
+		var liveSize int64
+		for _, blob := range live[id] {
+			liveSize += recordSize(blob.ref, blob.loc.Length)
+		}
+		if stat.Size() > 0 && float64(stat.Size()-liveSize)/float64(stat.Size()) < store.config.CompactionRatio {
+			continue
+		}
+
+		if err := store.compactPack(ctx, id, live[id]); err != nil {
+			return err
+		}
+		store.log.Debug("compacted pack file",
+			zap.String("pack", packName(id)),
+			zap.Int64("size", stat.Size()),
+			zap.Int64("live", liveSize))
+	}
+	return nil
+}
+
+// compactPack copies the live blobs of the pack file to the active pack file
+// and removes it.
+func (store *Store) compactPack(ctx context.Context, id uint32, blobs []packedBlob) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if len(blobs) > 0 {
+		moved, err := store.copyBlobs(id, blobs)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+
+		// the blobs may have been deleted, trashed or restored since they
+		// were listed, so the index is updated wherever they are now
+		err = store.index.Update(func(tx *bolt.Tx) error {
+			for i, blob := range blobs {
+				for _, bucket := range indexBuckets {
+					loc, found, err := getLocation(tx, bucket, blob.ref, blob.formatVer)
+					if err != nil {
+						return err
+					}
+					if !found || loc.Pack != id || loc.Offset != blob.loc.Offset {
+						continue
+					}
+					loc.Pack, loc.Offset = moved[i].Pack, moved[i].Offset
+					if err := putLocation(tx, bucket, blob.ref, blob.formatVer, loc); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return Error.Wrap(err)
+		}
+	}
+
+	store.packsMu.Lock()
+	defer store.packsMu.Unlock()
+	return Error.Wrap(os.Remove(store.packPath(id)))
+}
+
+// copyBlobs appends the blobs of the pack file to the active pack file and
+// returns their new locations.
+func (store *Store) copyBlobs(id uint32, blobs []packedBlob) (moved []location, err error) {
+	file, err := os.Open(store.packPath(id))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = errs.Combine(err, file.Close()) }()
+
+	for _, blob := range blobs {
+		data := make([]byte, blob.loc.Length)
+		if _, err := file.ReadAt(data, blob.loc.Offset); err != nil {
+			return nil, err
+		}
+		loc, err := store.appendUnsynced(blob.ref, blob.formatVer, data)
+		if err != nil {
+			return nil, err
+		}
+		moved = append(moved, loc)
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if store.active == nil {
+		return moved, nil
+	}
+	return moved, store.active.file.Sync()
+}
+
+// appendUnsynced appends the blob to the active pack file without syncing it.
+func (store *Store) appendUnsynced(ref storage.BlobRef, formatVer storage.FormatVersion, data []byte) (location, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return store.appendLocked(ref, formatVer, data)
+}
@@ -0,0 +1,144 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package packstore
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/boltdb/bolt"
+
+	"storj.io/storj/storage"
+)
+
+var (
+	// blobsBucket contains a bucket per namespace with the locations of the packed blobs.
+	blobsBucket = []byte("blobs")
+	// trashBucket contains a bucket per namespace with the locations of the trashed blobs.
+	trashBucket = []byte("trash")
+)
+
+// locationSize is the size of an encoded location.
+const locationSize = 4 + 8 + 8 + 8 + 8
+
+// location is the position of a blob in a pack file.
+type location struct {
+	Pack   uint32
+	Offset int64
+	Length int64
+
+	ModTime   time.Time
+	TrashedAt time.Time
+}
+
+// marshal encodes the location.
+func (loc location) marshal() []byte {
+	data := make([]byte, locationSize)
+	binary.BigEndian.PutUint32(data[0:], loc.Pack)
+	binary.BigEndian.PutUint64(data[4:], uint64(loc.Offset))
+	binary.BigEndian.PutUint64(data[12:], uint64(loc.Length))
+	binary.BigEndian.PutUint64(data[20:], uint64(unixNano(loc.ModTime)))
+	binary.BigEndian.PutUint64(data[28:], uint64(unixNano(loc.TrashedAt)))
+	return data
+}
+
+// unmarshalLocation decodes the location.
+func unmarshalLocation(data []byte) (loc location, err error) {
+	if len(data) != locationSize {
+		return loc, Error.New("invalid location size %d", len(data))
+	}
+	loc.Pack = binary.BigEndian.Uint32(data[0:])
+	loc.Offset = int64(binary.BigEndian.Uint64(data[4:]))
+	loc.Length = int64(binary.BigEndian.Uint64(data[12:]))
+	loc.ModTime = fromUnixNano(int64(binary.BigEndian.Uint64(data[20:])))
+	loc.TrashedAt = fromUnixNano(int64(binary.BigEndian.Uint64(data[28:])))
+	return loc, nil
+}
+
+func unixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+func fromUnixNano(nanos int64) time.Time {
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
+// indexKey returns the key of the blob within the namespace bucket. The
+// storage format version comes first, so that the same blob can be stored
+// with several versions.
+func indexKey(key []byte, formatVer storage.FormatVersion) []byte {
+	return append([]byte{byte(formatVer)}, key...)
+}
+
+// parseIndexKey returns the blob key and storage format version of the key
+// within the namespace bucket.
+func parseIndexKey(indexKey []byte) ([]byte, storage.FormatVersion) {
+	return append([]byte{}, indexKey[1:]...), storage.FormatVersion(indexKey[0])
+}
+
+// getLocation returns the location of the blob in the bucket, or false when
+// the bucket doesn't contain it.
+func getLocation(tx *bolt.Tx, bucket []byte, ref storage.BlobRef, formatVer storage.FormatVersion) (location, bool, error) {
+	namespace := tx.Bucket(bucket).Bucket(ref.Namespace)
+	if namespace == nil {
+		return location{}, false, nil
+	}
+	data := namespace.Get(indexKey(ref.Key, formatVer))
+	if data == nil {
+		return location{}, false, nil
+	}
+	loc, err := unmarshalLocation(data)
+	return loc, err == nil, err
+}
+
+// putLocation stores the location of the blob in the bucket.
+func putLocation(tx *bolt.Tx, bucket []byte, ref storage.BlobRef, formatVer storage.FormatVersion, loc location) error {
+	namespace, err := tx.Bucket(bucket).CreateBucketIfNotExists(ref.Namespace)
+	if err != nil {
+		return err
+	}
+	return namespace.Put(indexKey(ref.Key, formatVer), loc.marshal())
+}
+
+// deleteLocation removes the blob from the bucket.
+func deleteLocation(tx *bolt.Tx, bucket []byte, ref storage.BlobRef, formatVer storage.FormatVersion) error {
+	namespace := tx.Bucket(bucket).Bucket(ref.Namespace)
+	if namespace == nil {
+		return nil
+	}
+	return namespace.Delete(indexKey(ref.Key, formatVer))
+}
+
+// forEachLocation calls fn for every blob of the namespace in the bucket.
+func forEachLocation(tx *bolt.Tx, bucket []byte, namespace []byte, fn func(key []byte, formatVer storage.FormatVersion, loc location) error) error {
+	b := tx.Bucket(bucket).Bucket(namespace)
+	if b == nil {
+		return nil
+	}
+	return b.ForEach(func(k, v []byte) error {
+		loc, err := unmarshalLocation(v)
+		if err != nil {
+			return err
+		}
+		key, formatVer := parseIndexKey(k)
+		return fn(key, formatVer, loc)
+	})
+}
+
+// listNamespaces returns the namespaces in the bucket.
+func listNamespaces(tx *bolt.Tx, bucket []byte) (namespaces [][]byte) {
+	_ = tx.Bucket(bucket).ForEach(func(k, v []byte) error {
+		if v == nil {
+			namespaces = append(namespaces, append([]byte{}, k...))
+		}
+		return nil
+	})
+	return namespaces
+}
@@ -0,0 +1,86 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package packstore
+
+import (
+	"context"
+	"io/ioutil"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/storj/storage"
+)
+
+// MigrateFiles moves the blobs which are stored in separate files, but are
+// small enough to be packed, to pack files. It returns the number of blobs
+// moved.
+func (store *Store) MigrateFiles(ctx context.Context) (migrated int, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if !store.config.Enabled {
+		return 0, nil
+	}
+
+	namespaces, err := store.files.ListNamespaces(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, namespace := range namespaces {
+		var small []storage.BlobInfo
+		err := store.files.WalkNamespace(ctx, namespace, func(info storage.BlobInfo) error {
+			stat, err := info.Stat(ctx)
+			if err != nil {
+				// the blob may have been deleted in the meantime
+				store.log.Debug("unable to stat blob", zap.Binary("key", info.BlobRef().Key), zap.Error(err))
+				return nil
+			}
+			if stat.Size() <= store.config.MaxPackedSize.Int64() {
+				small = append(small, info)
+			}
+			return nil
+		})
+		if err != nil {
+			return migrated, err
+		}
+
+		for _, info := range small {
+			if err := ctx.Err(); err != nil {
+				return migrated, err
+			}
+			if err := store.migrateFile(ctx, info); err != nil {
+				return migrated, err
+			}
+			migrated++
+		}
+	}
+	return migrated, nil
+}
+
+// migrateFile moves the blob from its file to a pack file.
+func (store *Store) migrateFile(ctx context.Context, info storage.BlobInfo) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	ref, formatVer := info.BlobRef(), info.StorageFormatVersion()
+
+	stat, err := info.Stat(ctx)
+	if err != nil {
+		return err
+	}
+	reader, err := store.files.OpenWithStorageFormat(ctx, ref, formatVer)
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadAll(reader)
+	err = errs.Combine(err, reader.Close())
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	if err := store.put(ctx, ref, formatVer, data, stat.ModTime()); err != nil {
+		return err
+	}
+	return store.files.DeleteWithStorageFormat(ctx, ref, formatVer)
+}
@@ -0,0 +1,107 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package packstore
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/storage"
+)
+
+const (
+	packPermission = 0600
+	packSuffix     = ".pack"
+
+	// recordHeaderSize is the size of the header preceding every blob in a
+	// pack file: a magic value, the storage format version and the sizes of
+	// the namespace, key and data.
+	recordHeaderSize = 4 + 1 + 2 + 2 + 4
+)
+
+// recordMagic marks the beginning of a record, so that the pack files can be
+// inspected without the index.
+var recordMagic = [4]byte{'s', 'j', 'p', 'k'}
+
+// recordSize returns the size of the record for the blob in the pack file.
+func recordSize(ref storage.BlobRef, length int64) int64 {
+	return recordHeaderSize + int64(len(ref.Namespace)) + int64(len(ref.Key)) + length
+}
+
+// encodeRecord returns the record for the blob.
+func encodeRecord(ref storage.BlobRef, formatVer storage.FormatVersion, data []byte) []byte {
+	record := make([]byte, recordHeaderSize, recordSize(ref, int64(len(data))))
+	copy(record[0:], recordMagic[:])
+	record[4] = byte(formatVer)
+	binary.BigEndian.PutUint16(record[5:], uint16(len(ref.Namespace)))
+	binary.BigEndian.PutUint16(record[7:], uint16(len(ref.Key)))
+	binary.BigEndian.PutUint32(record[9:], uint32(len(data)))
+	record = append(record, ref.Namespace...)
+	record = append(record, ref.Key...)
+	return append(record, data...)
+}
+
+// packName returns the file name of the pack.
+func packName(id uint32) string {
+	return fmt.Sprintf("%08x%s", id, packSuffix)
+}
+
+// listPacks returns the ids of the pack files in the directory.
+func listPacks(dir string) (ids []uint32, err error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || !strings.HasSuffix(name, packSuffix) {
+			continue
+		}
+		var id uint32
+		if _, err := fmt.Sscanf(strings.TrimSuffix(name, packSuffix), "%08x", &id); err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// pack is a pack file which blobs are appended to.
+type pack struct {
+	id   uint32
+	file *os.File
+	size int64
+}
+
+// createPack creates a new pack file in the directory.
+func createPack(dir string, id uint32) (*pack, error) {
+	file, err := os.OpenFile(filepath.Join(dir, packName(id)), os.O_CREATE|os.O_EXCL|os.O_WRONLY, packPermission)
+	if err != nil {
+		return nil, err
+	}
+	return &pack{id: id, file: file}, nil
+}
+
+// append writes the record to the end of the pack file and returns the
+// offset of the data within the file.
+func (pack *pack) append(ref storage.BlobRef, formatVer storage.FormatVersion, data []byte) (offset int64, err error) {
+	record := encodeRecord(ref, formatVer, data)
+	if _, err := pack.file.WriteAt(record, pack.size); err != nil {
+		return 0, err
+	}
+	offset = pack.size + int64(len(record)-len(data))
+	pack.size += int64(len(record))
+	return offset, nil
+}
+
+// close syncs and closes the pack file.
+func (pack *pack) close() error {
+	return errs.Combine(pack.file.Sync(), pack.file.Close())
+}
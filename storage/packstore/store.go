@@ -0,0 +1,759 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package packstore implements a blob store which appends small blobs to
+// large pack files, instead of storing every blob in a separate file.
+package packstore
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/common/memory"
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/filestore"
+)
+
+var (
+	// Error is the default packstore error class.
+	Error = errs.Class("packstore error")
+
+	mon = monkit.Package()
+
+	_ storage.Blobs = (*Store)(nil)
+)
+
+// quarantineBucket contains a bucket per namespace with the locations of the
+// quarantined blobs.
+var quarantineBucket = []byte("quarantine")
+
+const (
+	packsDir      = "packs"
+	indexFile     = "index.db"
+	indexTimeout  = 1 * time.Second
+	walkBatchSize = 1000
+)
+
+// Config defines parameters for the packed blob store.
+type Config struct {
+	Enabled         bool        `help:"whether small pieces are appended to pack files instead of being stored in separate files" default:"false"`
+	MaxPackedSize   memory.Size `help:"pieces up to this size are appended to pack files" default:"64KiB"`
+	PackSize        memory.Size `help:"size after which a new pack file is started" default:"256MiB"`
+	CompactionRatio float64     `help:"ratio of deleted data in a pack file after which it's compacted" default:"0.5"`
+}
+
+// Store is a blob store which appends small blobs to pack files. The
+// locations of the packed blobs are kept in an index. Blobs larger than the
+// maximum packed size are stored in separate files by a filestore in the same
+// directory, which also keeps serving the blobs stored before packing was
+// enabled.
+//
+// architecture: Database
+type Store struct {
+	log    *zap.Logger
+	config Config
+	files  storage.Blobs
+	dir    string
+	index  *bolt.DB
+
+	// mu protects the active pack and the blobs which are waiting to be
+	// added to the index.
+	mu     sync.Mutex
+	active *pack
+	nextID uint32
+
+	// pending are the appended blobs which are added to the index by the
+	// next commit, and committing is whether a batch is being committed.
+	pending    *commitBatch
+	committing bool
+	// unindexed counts the appended blobs per pack file which aren't in the
+	// index yet, so that compaction doesn't remove their pack files.
+	unindexed map[uint32]int
+
+	// packsMu prevents pack files from being removed while a reader is
+	// opening them.
+	packsMu sync.RWMutex
+}
+
+// NewAt creates a packed blob store in the specified directory.
+func NewAt(log *zap.Logger, path string, config Config) (*Store, error) {
+	files, err := filestore.NewAt(log, path)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(path, packsDir)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	ids, err := listPacks(dir)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	var nextID uint32
+	for _, id := range ids {
+		if id >= nextID {
+			nextID = id + 1
+		}
+	}
+
+	index, err := bolt.Open(filepath.Join(dir, indexFile), packPermission, &bolt.Options{Timeout: indexTimeout})
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	err = index.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{blobsBucket, trashBucket, quarantineBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, Error.Wrap(errs.Combine(err, index.Close()))
+	}
+
+	return &Store{
+		log:    log,
+		config: config,
+		files:  files,
+		dir:    dir,
+		index:  index,
+		nextID: nextID,
+
+		unindexed: make(map[uint32]int),
+	}, nil
+}
+
+// Exists returns whether a packed blob store was created in the directory,
+// so that its packed blobs are still served after packing is disabled.
+func Exists(path string) (bool, error) {
+	_, err := os.Stat(filepath.Join(path, packsDir, indexFile))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Close closes the active pack file and the index.
+func (store *Store) Close() error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	var group errs.Group
+	if store.active != nil {
+		group.Add(store.active.close())
+		store.active = nil
+	}
+	group.Add(store.index.Close())
+	return Error.Wrap(group.Err())
+}
+
+// packPath returns the path of the pack file.
+func (store *Store) packPath(id uint32) string {
+	return filepath.Join(store.dir, packName(id))
+}
+
+// appendLocked appends the blob to the active pack file, starting a new
+// one when it's full. store.mu must be held.
+func (store *Store) appendLocked(ref storage.BlobRef, formatVer storage.FormatVersion, data []byte) (location, error) {
+	if store.active != nil && store.active.size+recordSize(ref, int64(len(data))) > store.config.PackSize.Int64() {
+		err := store.active.close()
+		store.active = nil
+		if err != nil {
+			return location{}, err
+		}
+	}
+	if store.active == nil {
+		active, err := createPack(store.dir, store.nextID)
+		if err != nil {
+			return location{}, err
+		}
+		store.nextID++
+		store.active = active
+	}
+
+	offset, err := store.active.append(ref, formatVer, data)
+	if err != nil {
+		return location{}, err
+	}
+	return location{
+		Pack:   store.active.id,
+		Offset: offset,
+		Length: int64(len(data)),
+	}, nil
+}
+
+// commitBatch is a group of appended blobs whose pack file is synced and
+// which are added to the index together. lead is sent to once, when one of the
+// puts of the batch has to commit it.
+type commitBatch struct {
+	blobs []packedBlob
+	lead  chan struct{}
+	done  chan struct{}
+	err   error
+}
+
+// put appends the blob to a pack file and adds it to the index. Concurrent
+// puts are committed together, so that they share the sync of the pack file
+// and the index update.
+func (store *Store) put(ctx context.Context, ref storage.BlobRef, formatVer storage.FormatVersion, data []byte, modTime time.Time) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	store.mu.Lock()
+	loc, err := store.appendLocked(ref, formatVer, data)
+	if err != nil {
+		store.mu.Unlock()
+		return Error.Wrap(err)
+	}
+	loc.ModTime = modTime
+	store.unindexed[loc.Pack]++
+
+	if store.pending == nil {
+		store.pending = &commitBatch{lead: make(chan struct{}, 1), done: make(chan struct{})}
+	}
+	batch := store.pending
+	batch.blobs = append(batch.blobs, packedBlob{ref: ref, formatVer: formatVer, loc: loc})
+
+	// a put commits its own batch when no batch is being committed, the
+	// others wait until their batch is committed or they have to commit it
+	leader := !store.committing
+	store.committing = true
+	store.mu.Unlock()
+
+	if !leader {
+		select {
+		case <-batch.done:
+			return Error.Wrap(batch.err)
+		case <-batch.lead:
+		}
+	}
+
+	store.commitPending()
+	<-batch.done
+	return Error.Wrap(batch.err)
+}
+
+// commitPending syncs the active pack file and adds the pending blobs to the
+// index. Afterwards a put of the next pending batch commits it, so that a put
+// doesn't keep committing the batches of other puts.
+func (store *Store) commitPending() {
+	store.mu.Lock()
+	batch := store.pending
+	store.pending = nil
+	// pack files which were filled up were synced when they were closed
+	var err error
+	if store.active != nil {
+		err = store.active.file.Sync()
+	}
+	store.mu.Unlock()
+
+	if err == nil {
+		err = store.index.Update(func(tx *bolt.Tx) error {
+			for _, blob := range batch.blobs {
+				if err := putLocation(tx, blobsBucket, blob.ref, blob.formatVer, blob.loc); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+
+	store.mu.Lock()
+	for _, blob := range batch.blobs {
+		store.unindexed[blob.loc.Pack]--
+		if store.unindexed[blob.loc.Pack] <= 0 {
+			delete(store.unindexed, blob.loc.Pack)
+		}
+	}
+	next := store.pending
+	if next == nil {
+		store.committing = false
+	}
+	store.mu.Unlock()
+
+	batch.err = err
+	close(batch.done)
+
+	if next != nil {
+		next.lead <- struct{}{}
+	}
+}
+
+// Create creates a new blob that can be written. The size is only used when
+// the blob turns out to be too large to be packed.
+func (store *Store) Create(ctx context.Context, ref storage.BlobRef, size int64) (_ storage.BlobWriter, err error) {
+	defer mon.Task()(&ctx)(&err)
+	if !ref.IsValid() {
+		return nil, storage.ErrInvalidBlobRef.New("")
+	}
+	if !store.config.Enabled {
+		// the packed blobs are still served, but new blobs aren't packed
+		return store.files.Create(ctx, ref, size)
+	}
+	return &blobWriter{
+		ctx:           ctx,
+		ref:           ref,
+		store:         store,
+		formatVersion: filestore.MaxFormatVersionSupported,
+	}, nil
+}
+
+// TestCreateV0 creates a new V0 blob that can be written. This is ONLY appropriate in test situations.
+func (store *Store) TestCreateV0(ctx context.Context, ref storage.BlobRef) (_ storage.BlobWriter, err error) {
+	fStore, ok := store.files.(interface {
+		TestCreateV0(ctx context.Context, ref storage.BlobRef) (_ storage.BlobWriter, err error)
+	})
+	if !ok {
+		return nil, Error.New("V0 blobs can't be created by %T", store.files)
+	}
+	return fStore.TestCreateV0(ctx, ref)
+}
+
+// lookup returns the location of the packed blob with the newest storage
+// format version.
+func (store *Store) lookup(ref storage.BlobRef) (loc location, formatVer storage.FormatVersion, found bool, err error) {
+	err = store.index.View(func(tx *bolt.Tx) error {
+		for formatVer = filestore.MaxFormatVersionSupported; formatVer >= filestore.MinFormatVersionSupported; formatVer-- {
+			loc, found, err = getLocation(tx, blobsBucket, ref, formatVer)
+			if err != nil || found {
+				return err
+			}
+		}
+		return nil
+	})
+	return loc, formatVer, found, Error.Wrap(err)
+}
+
+// lookupWithStorageFormat returns the location of the packed blob with the
+// storage format version.
+func (store *Store) lookupWithStorageFormat(ref storage.BlobRef, formatVer storage.FormatVersion) (loc location, found bool, err error) {
+	err = store.index.View(func(tx *bolt.Tx) error {
+		loc, found, err = getLocation(tx, blobsBucket, ref, formatVer)
+		return err
+	})
+	return loc, found, Error.Wrap(err)
+}
+
+// openPacked opens a reader for the blob at the location.
+func (store *Store) openPacked(loc location, formatVer storage.FormatVersion) (*blobReader, error) {
+	file, err := os.Open(store.packPath(loc.Pack))
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return &blobReader{
+		SectionReader: io.NewSectionReader(file, loc.Offset, loc.Length),
+		file:          file,
+		formatVersion: formatVer,
+	}, nil
+}
+
+// Open opens a reader for the blob with the newest storage format version.
+func (store *Store) Open(ctx context.Context, ref storage.BlobRef) (_ storage.BlobReader, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	store.packsMu.RLock()
+	defer store.packsMu.RUnlock()
+
+	loc, formatVer, found, err := store.lookup(ref)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		return store.openPacked(loc, formatVer)
+	}
+	return store.files.Open(ctx, ref)
+}
+
+// OpenWithStorageFormat opens a reader for the blob with the storage format
+// version.
+func (store *Store) OpenWithStorageFormat(ctx context.Context, ref storage.BlobRef, formatVer storage.FormatVersion) (_ storage.BlobReader, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	store.packsMu.RLock()
+	defer store.packsMu.RUnlock()
+
+	loc, found, err := store.lookupWithStorageFormat(ref, formatVer)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		return store.openPacked(loc, formatVer)
+	}
+	return store.files.OpenWithStorageFormat(ctx, ref, formatVer)
+}
+
+// Stat looks up the blob with the newest storage format version.
+func (store *Store) Stat(ctx context.Context, ref storage.BlobRef) (_ storage.BlobInfo, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	loc, formatVer, found, err := store.lookup(ref)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		return store.info(ref, formatVer, loc), nil
+	}
+	return store.files.Stat(ctx, ref)
+}
+
+// StatWithStorageFormat looks up the blob with the storage format version.
+func (store *Store) StatWithStorageFormat(ctx context.Context, ref storage.BlobRef, formatVer storage.FormatVersion) (_ storage.BlobInfo, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	loc, found, err := store.lookupWithStorageFormat(ref, formatVer)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		return store.info(ref, formatVer, loc), nil
+	}
+	return store.files.StatWithStorageFormat(ctx, ref, formatVer)
+}
+
+// info returns the blob info of a packed blob.
+func (store *Store) info(ref storage.BlobRef, formatVer storage.FormatVersion, loc location) *blobInfo {
+	return &blobInfo{
+		ref:           ref,
+		formatVersion: formatVer,
+		loc:           loc,
+		path:          store.packPath(loc.Pack),
+	}
+}
+
+// Delete deletes the blob with every storage format version. The space is
+// reclaimed when the pack file is compacted.
+func (store *Store) Delete(ctx context.Context, ref storage.BlobRef) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = store.index.Update(func(tx *bolt.Tx) error {
+		for formatVer := filestore.MinFormatVersionSupported; formatVer <= filestore.MaxFormatVersionSupported; formatVer++ {
+			if err := deleteLocation(tx, blobsBucket, ref, formatVer); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return errs.Combine(Error.Wrap(err), store.files.Delete(ctx, ref))
+}
+
+// DeleteWithStorageFormat deletes the blob with the storage format version.
+func (store *Store) DeleteWithStorageFormat(ctx context.Context, ref storage.BlobRef, formatVer storage.FormatVersion) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = store.index.Update(func(tx *bolt.Tx) error {
+		return deleteLocation(tx, blobsBucket, ref, formatVer)
+	})
+	return errs.Combine(Error.Wrap(err), store.files.DeleteWithStorageFormat(ctx, ref, formatVer))
+}
+
+// move moves the blob with every storage format version between buckets of
+// the index, updating the location with fn.
+func (store *Store) move(ref storage.BlobRef, from, to []byte, fn func(*location)) error {
+	return store.index.Update(func(tx *bolt.Tx) error {
+		for formatVer := filestore.MinFormatVersionSupported; formatVer <= filestore.MaxFormatVersionSupported; formatVer++ {
+			loc, found, err := getLocation(tx, from, ref, formatVer)
+			if err != nil {
+				return err
+			}
+			if !found {
+				continue
+			}
+			fn(&loc)
+			if err := putLocation(tx, to, ref, formatVer, loc); err != nil {
+				return err
+			}
+			if err := deleteLocation(tx, from, ref, formatVer); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Trash moves the blob to the trash.
+func (store *Store) Trash(ctx context.Context, ref storage.BlobRef) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	now := time.Now()
+	err = store.move(ref, blobsBucket, trashBucket, func(loc *location) {
+		loc.TrashedAt = now
+	})
+	return errs.Combine(Error.Wrap(err), store.files.Trash(ctx, ref))
+}
+
+// RestoreTrash restores all blobs in the trash of the namespace and returns
+// the keys restored.
+func (store *Store) RestoreTrash(ctx context.Context, namespace []byte) (keysRestored [][]byte, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = store.index.Update(func(tx *bolt.Tx) error {
+		type trashed struct {
+			key       []byte
+			formatVer storage.FormatVersion
+			loc       location
+		}
+		var restore []trashed
+		err := forEachLocation(tx, trashBucket, namespace, func(key []byte, formatVer storage.FormatVersion, loc location) error {
+			restore = append(restore, trashed{key: key, formatVer: formatVer, loc: loc})
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, blob := range restore {
+			ref := storage.BlobRef{Namespace: namespace, Key: blob.key}
+			blob.loc.TrashedAt = time.Time{}
+			if err := putLocation(tx, blobsBucket, ref, blob.formatVer, blob.loc); err != nil {
+				return err
+			}
+			if err := deleteLocation(tx, trashBucket, ref, blob.formatVer); err != nil {
+				return err
+			}
+			keysRestored = append(keysRestored, blob.key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	fileKeys, err := store.files.RestoreTrash(ctx, namespace)
+	return append(keysRestored, fileKeys...), err
+}
+
+// EmptyTrash removes the blobs of the namespace which were trashed before
+// trashedBefore and returns the bytes emptied and the keys deleted.
+func (store *Store) EmptyTrash(ctx context.Context, namespace []byte, trashedBefore time.Time) (bytesEmptied int64, keys [][]byte, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	bytesEmptied, keys, err = store.empty(trashBucket, namespace, trashedBefore)
+	if err != nil {
+		return 0, nil, Error.Wrap(err)
+	}
+
+	fileBytes, fileKeys, err := store.files.EmptyTrash(ctx, namespace, trashedBefore)
+	return bytesEmptied + fileBytes, append(keys, fileKeys...), err
+}
+
+// EmptyQuarantine removes the blobs of the namespace which were quarantined
+// before quarantinedBefore and returns the bytes emptied and the keys deleted.
+func (store *Store) EmptyQuarantine(ctx context.Context, namespace []byte, quarantinedBefore time.Time) (bytesEmptied int64, keys [][]byte, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	bytesEmptied, keys, err = store.empty(quarantineBucket, namespace, quarantinedBefore)
+	if err != nil {
+		return 0, nil, Error.Wrap(err)
+	}
+
+	fileBytes, fileKeys, err := store.files.EmptyQuarantine(ctx, namespace, quarantinedBefore)
+	return bytesEmptied + fileBytes, append(keys, fileKeys...), err
+}
+
+// empty removes the locations of the namespace in the bucket which were moved
+// there before the specified time. The space in the pack files is reclaimed
+// by compaction.
+func (store *Store) empty(bucketName, namespace []byte, before time.Time) (bytesEmptied int64, keys [][]byte, err error) {
+	err = store.index.Update(func(tx *bolt.Tx) error {
+		var indexKeys [][]byte
+		err := forEachLocation(tx, bucketName, namespace, func(key []byte, formatVer storage.FormatVersion, loc location) error {
+			if loc.TrashedAt.Before(before) {
+				indexKeys = append(indexKeys, indexKey(key, formatVer))
+				bytesEmptied += loc.Length
+				keys = append(keys, key)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		bucket := tx.Bucket(bucketName).Bucket(namespace)
+		for _, key := range indexKeys {
+			if err := bucket.Delete(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	return bytesEmptied, keys, nil
+}
+
+// Quarantine moves the blob to the quarantine, where it's kept for
+// inspection.
+func (store *Store) Quarantine(ctx context.Context, ref storage.BlobRef) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	now := time.Now()
+	err = store.move(ref, blobsBucket, quarantineBucket, func(loc *location) {
+		loc.TrashedAt = now
+	})
+	return errs.Combine(Error.Wrap(err), store.files.Quarantine(ctx, ref))
+}
+
+// FreeSpace returns how much space is left in the directory.
+func (store *Store) FreeSpace() (int64, error) {
+	return store.files.FreeSpace()
+}
+
+// spaceUsed adds up the sizes of the packed blobs in the namespaces of the
+// bucket. All namespaces are used when namespace is nil.
+func (store *Store) spaceUsed(bucket, namespace []byte) (total int64, err error) {
+	err = store.index.View(func(tx *bolt.Tx) error {
+		namespaces := [][]byte{namespace}
+		if namespace == nil {
+			namespaces = listNamespaces(tx, bucket)
+		}
+		for _, namespace := range namespaces {
+			err := forEachLocation(tx, bucket, namespace, func(key []byte, formatVer storage.FormatVersion, loc location) error {
+				total += loc.Length
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return total, Error.Wrap(err)
+}
+
+// SpaceUsedForTrash returns the total space used by the trash.
+func (store *Store) SpaceUsedForTrash(ctx context.Context) (_ int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	packed, err := store.spaceUsed(trashBucket, nil)
+	if err != nil {
+		return 0, err
+	}
+	files, err := store.files.SpaceUsedForTrash(ctx)
+	return packed + files, err
+}
+
+// SpaceUsedForBlobs adds up the space used by the blobs in all namespaces.
+func (store *Store) SpaceUsedForBlobs(ctx context.Context) (_ int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	packed, err := store.spaceUsed(blobsBucket, nil)
+	if err != nil {
+		return 0, err
+	}
+	files, err := store.files.SpaceUsedForBlobs(ctx)
+	return packed + files, err
+}
+
+// SpaceUsedForBlobsInNamespace adds up the space used by the blobs in the
+// namespace.
+func (store *Store) SpaceUsedForBlobsInNamespace(ctx context.Context, namespace []byte) (_ int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	packed, err := store.spaceUsed(blobsBucket, namespace)
+	if err != nil {
+		return 0, err
+	}
+	files, err := store.files.SpaceUsedForBlobsInNamespace(ctx, namespace)
+	return packed + files, err
+}
+
+// ListNamespaces finds all namespaces in which blobs might be stored.
+func (store *Store) ListNamespaces(ctx context.Context) (ids [][]byte, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = store.index.View(func(tx *bolt.Tx) error {
+		ids = listNamespaces(tx, blobsBucket)
+		return nil
+	})
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	fileIDs, err := store.files.ListNamespaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range fileIDs {
+		if !containsBytes(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, k int) bool { return bytes.Compare(ids[i], ids[k]) < 0 })
+	return ids, nil
+}
+
+func containsBytes(list [][]byte, value []byte) bool {
+	for _, item := range list {
+		if bytes.Equal(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// WalkNamespace executes walkFunc for each blob in the given namespace. The
+// packed blobs are walked first, in batches, so that walkFunc isn't called
+// while the index is locked.
+func (store *Store) WalkNamespace(ctx context.Context, namespace []byte, walkFunc func(storage.BlobInfo) error) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var after []byte
+	for more := true; more; {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var batch []*blobInfo
+		err := store.index.View(func(tx *bolt.Tx) error {
+			bucket := tx.Bucket(blobsBucket).Bucket(namespace)
+			if bucket == nil {
+				more = false
+				return nil
+			}
+			cursor := bucket.Cursor()
+			k, v := cursor.First()
+			if after != nil {
+				k, v = cursor.Seek(after)
+				if k != nil && bytes.Equal(k, after) {
+					k, v = cursor.Next()
+				}
+			}
+			for ; k != nil && len(batch) < walkBatchSize; k, v = cursor.Next() {
+				after = append(after[:0], k...)
+				key, formatVer := parseIndexKey(k)
+				loc, err := unmarshalLocation(v)
+				if err != nil {
+					return err
+				}
+				batch = append(batch, store.info(storage.BlobRef{Namespace: namespace, Key: key}, formatVer, loc))
+			}
+			more = k != nil
+			return nil
+		})
+		if err != nil {
+			return Error.Wrap(err)
+		}
+
+		for _, info := range batch {
+			if err := walkFunc(info); err != nil {
+				return err
+			}
+		}
+	}
+
+	return store.files.WalkNamespace(ctx, namespace, walkFunc)
+}
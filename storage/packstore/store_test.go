@@ -0,0 +1,285 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package packstore_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	"golang.org/x/sync/errgroup"
+
+	"storj.io/common/memory"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/filestore"
+	"storj.io/storj/storage/packstore"
+	"storj.io/storj/storage/testsuite"
+)
+
+var testConfig = packstore.Config{
+	Enabled:         true,
+	MaxPackedSize:   64 * memory.KiB,
+	PackSize:        256 * memory.KiB,
+	CompactionRatio: 0.5,
+}
+
+func TestBlobs(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	store, err := packstore.NewAt(zaptest.NewLogger(t), ctx.Dir("store"), testConfig)
+	require.NoError(t, err)
+	defer ctx.Check(store.Close)
+
+	testsuite.RunBlobsTests(t, store)
+}
+
+func writeBlob(ctx *testcontext.Context, t *testing.T, store storage.Blobs, ref storage.BlobRef, data []byte) {
+	writer, err := store.Create(ctx, ref, int64(len(data)))
+	require.NoError(t, err)
+	_, err = writer.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, writer.Commit(ctx))
+}
+
+func readBlob(ctx *testcontext.Context, t *testing.T, store storage.Blobs, ref storage.BlobRef) []byte {
+	reader, err := store.Open(ctx, ref)
+	require.NoError(t, err)
+	defer ctx.Check(reader.Close)
+	data, err := ioutil.ReadAll(reader)
+	require.NoError(t, err)
+	return data
+}
+
+func packFiles(t *testing.T, dir string) []string {
+	files, err := filepath.Glob(filepath.Join(dir, "packs", "*.pack"))
+	require.NoError(t, err)
+	return files
+}
+
+func TestReopen(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	dir := ctx.Dir("store")
+	store, err := packstore.NewAt(zaptest.NewLogger(t), dir, testConfig)
+	require.NoError(t, err)
+
+	namespace := testrand.Bytes(32)
+	blobs := map[string][]byte{}
+	for i := 0; i < 10; i++ {
+		ref := storage.BlobRef{Namespace: namespace, Key: testrand.Bytes(32)}
+		data := testrand.Bytes(16 * memory.KiB)
+		writeBlob(ctx, t, store, ref, data)
+		blobs[string(ref.Key)] = data
+	}
+	require.NoError(t, store.Close())
+
+	store, err = packstore.NewAt(zaptest.NewLogger(t), dir, testConfig)
+	require.NoError(t, err)
+	defer ctx.Check(store.Close)
+
+	for key, data := range blobs {
+		require.Equal(t, data, readBlob(ctx, t, store, storage.BlobRef{Namespace: namespace, Key: []byte(key)}))
+	}
+
+	// new blobs don't overwrite the existing pack files
+	ref := storage.BlobRef{Namespace: namespace, Key: testrand.Bytes(32)}
+	data := testrand.Bytes(16 * memory.KiB)
+	writeBlob(ctx, t, store, ref, data)
+	require.Equal(t, data, readBlob(ctx, t, store, ref))
+	for key, data := range blobs {
+		require.Equal(t, data, readBlob(ctx, t, store, storage.BlobRef{Namespace: namespace, Key: []byte(key)}))
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	dir := ctx.Dir("store")
+	exists, err := packstore.Exists(dir)
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	store, err := packstore.NewAt(zaptest.NewLogger(t), dir, testConfig)
+	require.NoError(t, err)
+
+	packed := storage.BlobRef{Namespace: testrand.Bytes(32), Key: testrand.Bytes(32)}
+	packedData := testrand.Bytes(16 * memory.KiB)
+	writeBlob(ctx, t, store, packed, packedData)
+	require.NoError(t, store.Close())
+
+	exists, err = packstore.Exists(dir)
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	// the packed blobs are still served after packing is disabled
+	disabled := testConfig
+	disabled.Enabled = false
+	store, err = packstore.NewAt(zaptest.NewLogger(t), dir, disabled)
+	require.NoError(t, err)
+	defer ctx.Check(store.Close)
+	require.Equal(t, packedData, readBlob(ctx, t, store, packed))
+
+	// but new blobs are stored in separate files
+	packsBefore := packFiles(t, dir)
+	ref := storage.BlobRef{Namespace: packed.Namespace, Key: testrand.Bytes(32)}
+	data := testrand.Bytes(16 * memory.KiB)
+	writeBlob(ctx, t, store, ref, data)
+	require.Equal(t, data, readBlob(ctx, t, store, ref))
+	require.Equal(t, packsBefore, packFiles(t, dir))
+
+	migrated, err := store.MigrateFiles(ctx)
+	require.NoError(t, err)
+	require.Zero(t, migrated)
+}
+
+func TestConcurrentPuts(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	store, err := packstore.NewAt(zaptest.NewLogger(t), ctx.Dir("store"), testConfig)
+	require.NoError(t, err)
+	defer ctx.Check(store.Close)
+
+	namespace := testrand.Bytes(32)
+	refs := make([]storage.BlobRef, 40)
+	blobs := make([][]byte, len(refs))
+	for i := range refs {
+		refs[i] = storage.BlobRef{Namespace: namespace, Key: testrand.Bytes(32)}
+		blobs[i] = testrand.Bytes(16 * memory.KiB)
+	}
+
+	var group errgroup.Group
+	for i := range refs {
+		ref, data := refs[i], blobs[i]
+		group.Go(func() error {
+			writer, err := store.Create(ctx, ref, int64(len(data)))
+			if err != nil {
+				return err
+			}
+			if _, err := writer.Write(data); err != nil {
+				return err
+			}
+			return writer.Commit(ctx)
+		})
+	}
+	require.NoError(t, group.Wait())
+
+	for i, ref := range refs {
+		require.Equal(t, blobs[i], readBlob(ctx, t, store, ref))
+	}
+}
+
+func TestCompact(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	dir := ctx.Dir("store")
+	store, err := packstore.NewAt(zaptest.NewLogger(t), dir, testConfig)
+	require.NoError(t, err)
+	defer ctx.Check(store.Close)
+
+	namespace := testrand.Bytes(32)
+	var refs []storage.BlobRef
+	blobs := map[string][]byte{}
+	for i := 0; i < 40; i++ {
+		ref := storage.BlobRef{Namespace: namespace, Key: testrand.Bytes(32)}
+		data := testrand.Bytes(16 * memory.KiB)
+		writeBlob(ctx, t, store, ref, data)
+		refs = append(refs, ref)
+		blobs[string(ref.Key)] = data
+	}
+	packsBefore := packFiles(t, dir)
+	require.True(t, len(packsBefore) > 2)
+
+	// keep every fourth blob, and trash one of them
+	var kept []storage.BlobRef
+	for i, ref := range refs {
+		if i%4 == 0 {
+			kept = append(kept, ref)
+			continue
+		}
+		require.NoError(t, store.Delete(ctx, ref))
+	}
+	require.NoError(t, store.Trash(ctx, kept[0]))
+
+	require.NoError(t, store.Compact(ctx))
+	require.True(t, len(packFiles(t, dir)) < len(packsBefore))
+
+	for _, ref := range kept[1:] {
+		require.Equal(t, blobs[string(ref.Key)], readBlob(ctx, t, store, ref))
+	}
+	_, err = store.Open(ctx, kept[0])
+	require.Error(t, err)
+
+	// the trashed blob was moved as well
+	restored, err := store.RestoreTrash(ctx, namespace)
+	require.NoError(t, err)
+	require.Len(t, restored, 1)
+	require.Equal(t, blobs[string(kept[0].Key)], readBlob(ctx, t, store, kept[0]))
+
+	used, err := store.SpaceUsedForBlobsInNamespace(ctx, namespace)
+	require.NoError(t, err)
+	require.Equal(t, int64(len(kept))*16*memory.KiB.Int64(), used)
+
+	// pack files without blobs are removed
+	for _, ref := range kept {
+		require.NoError(t, store.Delete(ctx, ref))
+	}
+	require.NoError(t, store.Compact(ctx))
+	require.Len(t, packFiles(t, dir), 1)
+}
+
+func TestMigrateFiles(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	dir := ctx.Dir("store")
+	files, err := filestore.NewAt(zaptest.NewLogger(t), dir)
+	require.NoError(t, err)
+
+	namespace := testrand.Bytes(32)
+	small := storage.BlobRef{Namespace: namespace, Key: testrand.Bytes(32)}
+	smallData := testrand.Bytes(memory.KiB)
+	large := storage.BlobRef{Namespace: namespace, Key: testrand.Bytes(32)}
+	largeData := testrand.Bytes(256 * memory.KiB)
+	writeBlob(ctx, t, files, small, smallData)
+	writeBlob(ctx, t, files, large, largeData)
+	require.NoError(t, files.Close())
+
+	store, err := packstore.NewAt(zaptest.NewLogger(t), dir, testConfig)
+	require.NoError(t, err)
+	defer ctx.Check(store.Close)
+
+	// the blobs are available before the migration
+	require.Equal(t, smallData, readBlob(ctx, t, store, small))
+	require.Equal(t, largeData, readBlob(ctx, t, store, large))
+
+	migrated, err := store.MigrateFiles(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, migrated)
+
+	require.Equal(t, smallData, readBlob(ctx, t, store, small))
+	require.Equal(t, largeData, readBlob(ctx, t, store, large))
+
+	info, err := store.Stat(ctx, small)
+	require.NoError(t, err)
+	path, err := info.FullPath(ctx)
+	require.NoError(t, err)
+	require.Equal(t, ".pack", filepath.Ext(path))
+
+	migrated, err = store.MigrateFiles(ctx)
+	require.NoError(t, err)
+	require.Zero(t, migrated)
+
+	used, err := store.SpaceUsedForBlobsInNamespace(ctx, namespace)
+	require.NoError(t, err)
+	require.Equal(t, int64(len(smallData)+len(largeData)), used)
+}
@@ -0,0 +1,373 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package testsuite
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zeebo/errs"
+
+	"storj.io/common/memory"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/filestore"
+)
+
+// blobSizes are the sizes of the blobs used in the tests. Blob stores may
+// handle small and large blobs differently.
+var blobSizes = []memory.Size{0, 1, memory.KiB, 256 * memory.KiB}
+
+// RunBlobsTests runs common storage.Blobs tests. Every test uses its own
+// namespaces, so the store doesn't need to be empty.
+func RunBlobsTests(t *testing.T, store storage.Blobs) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	t.Run("CRUD", func(t *testing.T) { testBlobsCRUD(t, ctx, store) })
+	t.Run("Seek", func(t *testing.T) { testBlobsSeek(t, ctx, store) })
+	t.Run("Cancel", func(t *testing.T) { testBlobsCancel(t, ctx, store) })
+	t.Run("FormatVersions", func(t *testing.T) { testBlobsFormatVersions(t, ctx, store) })
+	t.Run("Trash", func(t *testing.T) { testBlobsTrash(t, ctx, store) })
+	t.Run("Walk", func(t *testing.T) { testBlobsWalk(t, ctx, store) })
+	t.Run("SpaceUsed", func(t *testing.T) { testBlobsSpaceUsed(t, ctx, store) })
+}
+
+func newBlobRef(namespace []byte) storage.BlobRef {
+	return storage.BlobRef{
+		Namespace: namespace,
+		Key:       testrand.Bytes(32),
+	}
+}
+
+func writeBlob(ctx context.Context, t *testing.T, store storage.Blobs, ref storage.BlobRef, data []byte) {
+	writer, err := store.Create(ctx, ref, int64(len(data)))
+	require.NoError(t, err)
+	_, err = writer.Write(data)
+	require.NoError(t, err)
+	size, err := writer.Size()
+	require.NoError(t, err)
+	require.Equal(t, int64(len(data)), size)
+	require.NoError(t, writer.Commit(ctx))
+}
+
+func writeBlobV0(ctx context.Context, t *testing.T, store storage.Blobs, ref storage.BlobRef, data []byte) {
+	v0Store, ok := store.(interface {
+		TestCreateV0(ctx context.Context, ref storage.BlobRef) (_ storage.BlobWriter, err error)
+	})
+	require.Truef(t, ok, "can't make TestCreateV0 with this blob store (%T)", store)
+	writer, err := v0Store.TestCreateV0(ctx, ref)
+	require.NoError(t, err)
+	_, err = writer.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, writer.Commit(ctx))
+}
+
+func readBlob(ctx context.Context, t *testing.T, reader storage.BlobReader) []byte {
+	size, err := reader.Size()
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	require.Equal(t, size, int64(len(data)))
+	return data
+}
+
+func requireBlob(ctx context.Context, t *testing.T, store storage.Blobs, ref storage.BlobRef, data []byte) {
+	reader, err := store.Open(ctx, ref)
+	require.NoError(t, err)
+	require.Equal(t, data, readBlob(ctx, t, reader))
+}
+
+func requireNoBlob(ctx context.Context, t *testing.T, store storage.Blobs, ref storage.BlobRef) {
+	_, err := store.Open(ctx, ref)
+	require.Error(t, err)
+	_, err = store.Stat(ctx, ref)
+	require.Error(t, err)
+}
+
+func testBlobsCRUD(t *testing.T, ctx *testcontext.Context, store storage.Blobs) {
+	namespace := testrand.Bytes(32)
+
+	for _, size := range blobSizes {
+		ref := newBlobRef(namespace)
+		data := testrand.BytesInt(size.Int())
+
+		requireNoBlob(ctx, t, store, ref)
+
+		writeBlob(ctx, t, store, ref, data)
+		requireBlob(ctx, t, store, ref, data)
+
+		info, err := store.Stat(ctx, ref)
+		require.NoError(t, err)
+		require.Equal(t, ref, info.BlobRef())
+		require.Equal(t, filestore.MaxFormatVersionSupported, info.StorageFormatVersion())
+		stat, err := info.Stat(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(len(data)), stat.Size())
+		require.WithinDuration(t, time.Now(), stat.ModTime(), time.Minute)
+
+		info, err = store.StatWithStorageFormat(ctx, ref, filestore.MaxFormatVersionSupported)
+		require.NoError(t, err)
+		require.Equal(t, ref, info.BlobRef())
+
+		reader, err := store.Open(ctx, ref)
+		require.NoError(t, err)
+		require.Equal(t, filestore.MaxFormatVersionSupported, reader.StorageFormatVersion())
+		if len(data) > 1 {
+			// readers support reading from an offset
+			_, err = reader.Seek(1, io.SeekStart)
+			require.NoError(t, err)
+			rest, err := ioutil.ReadAll(reader)
+			require.NoError(t, err)
+			require.Equal(t, data[1:], rest)
+		}
+		require.NoError(t, reader.Close())
+
+		// overwriting replaces the content
+		replaced := testrand.BytesInt(size.Int())
+		require.NoError(t, store.Delete(ctx, ref))
+		writeBlob(ctx, t, store, ref, replaced)
+		requireBlob(ctx, t, store, ref, replaced)
+
+		require.NoError(t, store.Delete(ctx, ref))
+		requireNoBlob(ctx, t, store, ref)
+
+		// deleting a missing blob isn't an error
+		require.NoError(t, store.Delete(ctx, ref))
+	}
+}
+
+func testBlobsSeek(t *testing.T, ctx *testcontext.Context, store storage.Blobs) {
+	namespace := testrand.Bytes(32)
+
+	for _, size := range blobSizes {
+		if size < 8 {
+			continue
+		}
+		ref := newBlobRef(namespace)
+		header := testrand.Bytes(4)
+		data := testrand.BytesInt(size.Int())
+
+		// write the content after a header placeholder, then go back to
+		// write the header, like pieces do
+		writer, err := store.Create(ctx, ref, -1)
+		require.NoError(t, err)
+		_, err = writer.Write(make([]byte, len(header)))
+		require.NoError(t, err)
+		_, err = writer.Write(data)
+		require.NoError(t, err)
+		end, err := writer.Seek(0, io.SeekCurrent)
+		require.NoError(t, err)
+		_, err = writer.Seek(0, io.SeekStart)
+		require.NoError(t, err)
+		_, err = writer.Write(header)
+		require.NoError(t, err)
+		_, err = writer.Seek(end, io.SeekStart)
+		require.NoError(t, err)
+		require.NoError(t, writer.Commit(ctx))
+
+		requireBlob(ctx, t, store, ref, append(header, data...))
+	}
+}
+
+func testBlobsCancel(t *testing.T, ctx *testcontext.Context, store storage.Blobs) {
+	namespace := testrand.Bytes(32)
+
+	for _, size := range blobSizes {
+		ref := newBlobRef(namespace)
+		writer, err := store.Create(ctx, ref, size.Int64())
+		require.NoError(t, err)
+		_, err = writer.Write(testrand.BytesInt(size.Int()))
+		require.NoError(t, err)
+		require.NoError(t, writer.Cancel(ctx))
+		require.Error(t, writer.Commit(ctx))
+
+		requireNoBlob(ctx, t, store, ref)
+	}
+}
+
+func testBlobsFormatVersions(t *testing.T, ctx *testcontext.Context, store storage.Blobs) {
+	namespace := testrand.Bytes(32)
+
+	for _, size := range blobSizes {
+		ref := newBlobRef(namespace)
+		dataV0 := testrand.BytesInt(size.Int())
+		dataV1 := testrand.BytesInt(size.Int())
+
+		writeBlobV0(ctx, t, store, ref, dataV0)
+		requireBlob(ctx, t, store, ref, dataV0)
+		writeBlob(ctx, t, store, ref, dataV1)
+
+		// the newest version is preferred
+		requireBlob(ctx, t, store, ref, dataV1)
+
+		for formatVer, data := range map[storage.FormatVersion][]byte{
+			filestore.FormatV0: dataV0,
+			filestore.FormatV1: dataV1,
+		} {
+			info, err := store.StatWithStorageFormat(ctx, ref, formatVer)
+			require.NoError(t, err)
+			require.Equal(t, formatVer, info.StorageFormatVersion())
+
+			reader, err := store.OpenWithStorageFormat(ctx, ref, formatVer)
+			require.NoError(t, err)
+			require.Equal(t, formatVer, reader.StorageFormatVersion())
+			require.Equal(t, data, readBlob(ctx, t, reader))
+		}
+
+		require.NoError(t, store.DeleteWithStorageFormat(ctx, ref, filestore.FormatV1))
+		requireBlob(ctx, t, store, ref, dataV0)
+		_, err := store.StatWithStorageFormat(ctx, ref, filestore.FormatV1)
+		require.Error(t, err)
+
+		require.NoError(t, store.Delete(ctx, ref))
+		requireNoBlob(ctx, t, store, ref)
+	}
+}
+
+func testBlobsTrash(t *testing.T, ctx *testcontext.Context, store storage.Blobs) {
+	namespace := testrand.Bytes(32)
+	other := testrand.Bytes(32)
+
+	var refs []storage.BlobRef
+	contents := map[string][]byte{}
+	for _, size := range blobSizes {
+		ref := newBlobRef(namespace)
+		data := testrand.BytesInt(size.Int())
+		writeBlob(ctx, t, store, ref, data)
+		refs = append(refs, ref)
+		contents[string(ref.Key)] = data
+	}
+	kept, keptData := newBlobRef(other), testrand.Bytes(memory.KiB)
+	writeBlob(ctx, t, store, kept, keptData)
+
+	usedBefore, err := store.SpaceUsedForBlobsInNamespace(ctx, namespace)
+	require.NoError(t, err)
+	trashBefore, err := store.SpaceUsedForTrash(ctx)
+	require.NoError(t, err)
+
+	for _, ref := range refs {
+		require.NoError(t, store.Trash(ctx, ref))
+		requireNoBlob(ctx, t, store, ref)
+	}
+	requireBlob(ctx, t, store, kept, keptData)
+
+	used, err := store.SpaceUsedForBlobsInNamespace(ctx, namespace)
+	require.NoError(t, err)
+	require.Zero(t, used)
+	trash, err := store.SpaceUsedForTrash(ctx)
+	require.NoError(t, err)
+	require.True(t, trash-trashBefore >= usedBefore)
+
+	// restoring the trash restores every trashed blob of the namespace
+	restored, err := store.RestoreTrash(ctx, namespace)
+	require.NoError(t, err)
+	require.Len(t, restored, len(refs))
+	for _, ref := range refs {
+		requireBlob(ctx, t, store, ref, contents[string(ref.Key)])
+	}
+	restored, err = store.RestoreTrash(ctx, other)
+	require.NoError(t, err)
+	require.Empty(t, restored)
+
+	// only blobs trashed before the time are emptied
+	for _, ref := range refs {
+		require.NoError(t, store.Trash(ctx, ref))
+	}
+	emptied, keys, err := store.EmptyTrash(ctx, namespace, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Zero(t, emptied)
+	require.Empty(t, keys)
+
+	emptied, keys, err = store.EmptyTrash(ctx, namespace, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, usedBefore, emptied)
+	require.Len(t, keys, len(refs))
+
+	restored, err = store.RestoreTrash(ctx, namespace)
+	require.NoError(t, err)
+	require.Empty(t, restored)
+	for _, ref := range refs {
+		requireNoBlob(ctx, t, store, ref)
+	}
+}
+
+func testBlobsWalk(t *testing.T, ctx *testcontext.Context, store storage.Blobs) {
+	namespace := testrand.Bytes(32)
+
+	var expected []string
+	for i := 0; i < 10; i++ {
+		for _, size := range blobSizes {
+			ref := newBlobRef(namespace)
+			writeBlob(ctx, t, store, ref, testrand.BytesInt(size.Int()))
+			expected = append(expected, string(ref.Key))
+		}
+	}
+	v0 := newBlobRef(namespace)
+	writeBlobV0(ctx, t, store, v0, testrand.Bytes(memory.KiB))
+	expected = append(expected, string(v0.Key))
+
+	namespaces, err := store.ListNamespaces(ctx)
+	require.NoError(t, err)
+	require.Contains(t, namespaces, namespace)
+
+	var walked []string
+	err = store.WalkNamespace(ctx, namespace, func(info storage.BlobInfo) error {
+		require.Equal(t, namespace, info.BlobRef().Namespace)
+		walked = append(walked, string(info.BlobRef().Key))
+		return nil
+	})
+	require.NoError(t, err)
+
+	sort.Strings(expected)
+	sort.Strings(walked)
+	require.Equal(t, expected, walked)
+
+	// the walk stops at the first error
+	stop := errs.New("stop")
+	calls := 0
+	err = store.WalkNamespace(ctx, namespace, func(info storage.BlobInfo) error {
+		calls++
+		return stop
+	})
+	require.Equal(t, stop, err)
+	require.Equal(t, 1, calls)
+
+	err = store.WalkNamespace(ctx, testrand.Bytes(32), func(info storage.BlobInfo) error {
+		return errs.New("unexpected blob")
+	})
+	require.NoError(t, err)
+}
+
+func testBlobsSpaceUsed(t *testing.T, ctx *testcontext.Context, store storage.Blobs) {
+	namespace := testrand.Bytes(32)
+
+	totalBefore, err := store.SpaceUsedForBlobs(ctx)
+	require.NoError(t, err)
+
+	var expected int64
+	for _, size := range blobSizes {
+		writeBlob(ctx, t, store, newBlobRef(namespace), testrand.BytesInt(size.Int()))
+		expected += size.Int64()
+	}
+
+	used, err := store.SpaceUsedForBlobsInNamespace(ctx, namespace)
+	require.NoError(t, err)
+	require.Equal(t, expected, used)
+
+	total, err := store.SpaceUsedForBlobs(ctx)
+	require.NoError(t, err)
+	require.Equal(t, expected, total-totalBefore)
+
+	free, err := store.FreeSpace()
+	require.NoError(t, err)
+	require.True(t, free > 0)
+}
@@ -571,6 +571,38 @@ func TestBandwidthRollup(t *testing.T) {
 	})
 }
 
+func TestSatelliteFirstUsage(t *testing.T) {
+	storagenodedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db storagenode.DB) {
+		testID1 := storj.NodeID{1}
+		testID2 := storj.NodeID{2}
+
+		now := time.Date(2010, 4, 7, 12, 30, 00, 0, time.UTC)
+
+		first, err := db.Bandwidth().SatelliteFirstUsage(ctx, testID1)
+		require.NoError(t, err)
+		require.True(t, first.IsZero())
+
+		err = db.Bandwidth().Add(ctx, testID1, pb.PieceAction_PUT, 1, now.Add(time.Hour*-48))
+		require.NoError(t, err)
+		err = db.Bandwidth().Add(ctx, testID2, pb.PieceAction_GET, 2, now.Add(time.Hour*-72))
+		require.NoError(t, err)
+
+		// the oldest usage is in the rollups, which are truncated to hours
+		err = db.Bandwidth().Rollup(ctx)
+		require.NoError(t, err)
+		err = db.Bandwidth().Add(ctx, testID1, pb.PieceAction_GET, 3, now)
+		require.NoError(t, err)
+
+		first, err = db.Bandwidth().SatelliteFirstUsage(ctx, testID1)
+		require.NoError(t, err)
+		require.Equal(t, now.Add(time.Hour*-48).Truncate(time.Hour), first)
+
+		first, err = db.Bandwidth().SatelliteFirstUsage(ctx, testID2)
+		require.NoError(t, err)
+		require.Equal(t, now.Add(time.Hour*-72).Truncate(time.Hour), first)
+	})
+}
+
 func TestDB_Trivial(t *testing.T) {
 	storagenodedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db storagenode.DB) {
 		{ // Ensure Add works at all
@@ -32,6 +32,9 @@ type DB interface {
 	// SatelliteIngressSummary returns ingress bandwidth usage for a particular satellite.
 	SatelliteIngressSummary(ctx context.Context, satelliteID storj.NodeID, from, to time.Time) (*Usage, error)
 	SummaryBySatellite(ctx context.Context, from, to time.Time) (map[storj.NodeID]*Usage, error)
+	// SatelliteFirstUsage returns when bandwidth was first used for a particular satellite,
+	// zero time when it wasn't used.
+	SatelliteFirstUsage(ctx context.Context, satelliteID storj.NodeID) (time.Time, error)
 	// GetDailyRollups returns slice of daily bandwidth usage rollups for provided time range,
 	// sorted in ascending order.
 	GetDailyRollups(ctx context.Context, from, to time.Time) ([]UsageRollup, error)
@@ -0,0 +1,181 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package consoleorders
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/common/storj"
+	"storj.io/storj/storagenode/orders"
+)
+
+const (
+	contentType = "Content-Type"
+
+	applicationJSON = "application/json"
+	textCSV         = "text/csv"
+)
+
+var mon = monkit.Package()
+
+// Error is error type of storagenode web console.
+var Error = errs.Class("orders console web error")
+
+// Orders represents the order archive API.
+// architecture: Endpoint
+type Orders struct {
+	db orders.DB
+
+	log *zap.Logger
+}
+
+// jsonOutput defines json structure of api response data.
+type jsonOutput struct {
+	Data  interface{} `json:"data"`
+	Error string      `json:"error"`
+}
+
+// NewOrders creates new instance of the order archive API.
+func NewOrders(log *zap.Logger, db orders.DB) *Orders {
+	return &Orders{
+		log: log,
+		db:  db,
+	}
+}
+
+// ListArchived returns the archived orders matching the query parameters
+// satellite, status, action, after, before and limit. The orders are exported
+// as CSV when the format parameter is csv.
+func (controller *Orders) ListArchived(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	defer mon.Task()(&ctx)(nil)
+
+	query := r.URL.Query()
+
+	filter, err := parseFilter(query)
+	if err != nil {
+		controller.writeError(w, http.StatusBadRequest, Error.Wrap(err))
+		return
+	}
+
+	archived, err := controller.db.QueryArchived(ctx, filter)
+	if err != nil {
+		controller.writeError(w, http.StatusInternalServerError, Error.Wrap(err))
+		return
+	}
+
+	switch format := query.Get("format"); format {
+	case "", "json":
+		exported := make([]orders.ArchivedOrder, 0, len(archived))
+		for _, info := range archived {
+			exported = append(exported, info.Export())
+		}
+		controller.writeData(w, exported)
+	case "csv":
+		w.Header().Set(contentType, textCSV)
+		w.Header().Set("Content-Disposition", `attachment; filename="orders.csv"`)
+		w.WriteHeader(http.StatusOK)
+
+		if err := orders.ExportArchiveCSV(w, archived); err != nil {
+			controller.log.Error("csv encoder error", zap.Error(err))
+		}
+	default:
+		controller.writeError(w, http.StatusBadRequest, Error.New("invalid format %q, expected json or csv", format))
+	}
+}
+
+// Totals returns the totals of the archived orders per satellite.
+func (controller *Orders) Totals(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	defer mon.Task()(&ctx)(nil)
+
+	totals, err := controller.db.ArchiveTotals(ctx)
+	if err != nil {
+		controller.writeError(w, http.StatusInternalServerError, Error.Wrap(err))
+		return
+	}
+
+	controller.writeData(w, totals)
+}
+
+// parseFilter parses the archive filter from the query parameters.
+func parseFilter(query url.Values) (filter orders.ArchiveFilter, err error) {
+	if value := query.Get("satellite"); value != "" {
+		satelliteID, err := storj.NodeIDFromString(value)
+		if err != nil {
+			return filter, err
+		}
+		filter.SatelliteID = &satelliteID
+	}
+	if value := query.Get("status"); value != "" {
+		status, err := orders.ParseStatus(value)
+		if err != nil {
+			return filter, err
+		}
+		filter.Status = &status
+	}
+	if value := query.Get("action"); value != "" {
+		action, err := orders.ParseAction(value)
+		if err != nil {
+			return filter, err
+		}
+		filter.Action = &action
+	}
+	if value := query.Get("after"); value != "" {
+		filter.ArchivedAfter, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, err
+		}
+	}
+	if value := query.Get("before"); value != "" {
+		filter.ArchivedBefore, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, err
+		}
+	}
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return filter, err
+		}
+		filter.Limit = int(limit)
+	}
+	return filter, nil
+}
+
+// writeData is helper method to write JSON to http.ResponseWriter and log encoding error.
+func (controller *Orders) writeData(w http.ResponseWriter, data interface{}) {
+	w.Header().Set(contentType, applicationJSON)
+	w.WriteHeader(http.StatusOK)
+
+	output := jsonOutput{Data: data}
+
+	if err := json.NewEncoder(w).Encode(output); err != nil {
+		controller.log.Error("json encoder error", zap.Error(err))
+	}
+}
+
+// writeError writes a JSON error payload to http.ResponseWriter log encoding error.
+func (controller *Orders) writeError(w http.ResponseWriter, status int, err error) {
+	if status >= http.StatusInternalServerError {
+		controller.log.Error("api handler server error", zap.Int("status code", status), zap.Error(err))
+	}
+
+	w.Header().Set(contentType, applicationJSON)
+	w.WriteHeader(status)
+
+	output := jsonOutput{Error: err.Error()}
+
+	if err := json.NewEncoder(w).Encode(output); err != nil {
+		controller.log.Error("json encoder error", zap.Error(err))
+	}
+}
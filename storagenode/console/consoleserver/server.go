@@ -5,10 +5,14 @@ package consoleserver
 
 import (
 	"context"
+	"crypto/subtle"
 	"encoding/json"
+	"mime"
 	"net"
 	"net/http"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/spacemonkeygo/monkit/v3"
@@ -16,10 +20,15 @@ import (
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 
+	"storj.io/common/memory"
 	"storj.io/common/storj"
 	"storj.io/storj/storagenode/console"
 	"storj.io/storj/storagenode/console/consolenotifications"
+	"storj.io/storj/storagenode/console/consoleorders"
 	"storj.io/storj/storagenode/notifications"
+	"storj.io/storj/storagenode/orders"
+	"storj.io/storj/storagenode/payouts"
+	"storj.io/storj/storagenode/shaping"
 )
 
 const (
@@ -38,34 +47,45 @@ var (
 type Config struct {
 	Address   string `help:"server address of the api gateway and frontend app" default:"127.0.0.1:14002"`
 	StaticDir string `help:"path to static resources" default:""`
+	APIToken  string `help:"token required in the Authorization header to change settings through the api, changes are disabled when empty" default:""`
 }
 
 // Server represents storagenode console web server.
 //
 // architecture: Endpoint
 type Server struct {
-	log *zap.Logger
+	log      *zap.Logger
+	apiToken string
 
 	service       *console.Service
+	payouts       *payouts.Service
 	notifications *notifications.Service
+	orders        orders.DB
+	shaper        *shaping.Shaper
 	listener      net.Listener
 
 	server http.Server
 }
 
 // NewServer creates new instance of storagenode console web server.
-func NewServer(logger *zap.Logger, assets http.FileSystem, notifications *notifications.Service, service *console.Service, listener net.Listener) *Server {
+func NewServer(logger *zap.Logger, config Config, assets http.FileSystem, notifications *notifications.Service, service *console.Service, payouts *payouts.Service, orders orders.DB, shaper *shaping.Shaper, listener net.Listener) *Server {
 	server := Server{
 		log:           logger,
+		apiToken:      config.APIToken,
 		service:       service,
+		payouts:       payouts,
 		listener:      listener,
 		notifications: notifications,
+		orders:        orders,
+		shaper:        shaper,
 	}
 
 	router := mux.NewRouter()
 	apiRouter := router.PathPrefix("/api").Subrouter()
 	notificationRouter := router.PathPrefix("/api/notifications").Subrouter()
 	notificationController := consolenotifications.NewNotifications(server.log, server.notifications)
+	ordersRouter := router.PathPrefix("/api/orders").Subrouter()
+	ordersController := consoleorders.NewOrders(server.log, server.orders)
 
 	if assets != nil {
 		fs := http.FileServer(assets)
@@ -81,9 +101,15 @@ func NewServer(logger *zap.Logger, assets http.FileSystem, notifications *notifi
 	apiRouter.Handle("/dashboard", http.HandlerFunc(server.dashboardHandler)).Methods(http.MethodGet)
 	apiRouter.Handle("/satellites", http.HandlerFunc(server.satellitesHandler)).Methods(http.MethodGet)
 	apiRouter.Handle("/satellite/{id}", http.HandlerFunc(server.satelliteHandler)).Methods(http.MethodGet)
+	apiRouter.Handle("/estimated-payout", http.HandlerFunc(server.estimatedPayoutHandler)).Methods(http.MethodGet)
+	apiRouter.Handle("/estimated-payout/{id}", http.HandlerFunc(server.satelliteEstimatedPayoutHandler)).Methods(http.MethodGet)
+	apiRouter.Handle("/bandwidth-limits", http.HandlerFunc(server.bandwidthLimitsHandler)).Methods(http.MethodGet)
+	apiRouter.Handle("/bandwidth-limits", http.HandlerFunc(server.setBandwidthLimitsHandler)).Methods(http.MethodPost)
 	notificationRouter.Handle("/list", http.HandlerFunc(notificationController.ListNotifications)).Methods(http.MethodGet)
 	notificationRouter.Handle("/{id}/read", http.HandlerFunc(notificationController.ReadNotification)).Methods(http.MethodPost)
 	notificationRouter.Handle("/readall", http.HandlerFunc(notificationController.ReadAllNotifications)).Methods(http.MethodPost)
+	ordersRouter.Handle("/archive", http.HandlerFunc(ordersController.ListArchived)).Methods(http.MethodGet)
+	ordersRouter.Handle("/totals", http.HandlerFunc(ordersController.Totals)).Methods(http.MethodGet)
 
 	server.server = http.Server{
 		Handler: router,
@@ -186,6 +212,127 @@ func (server *Server) satelliteHandler(w http.ResponseWriter, r *http.Request) {
 	server.writeData(w, data)
 }
 
+// estimatedPayoutHandler handles estimated payout API requests.
+func (server *Server) estimatedPayoutHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	defer mon.Task()(&ctx)(nil)
+
+	data, err := server.payouts.Estimates(ctx, time.Now())
+	if err != nil {
+		server.writeError(w, http.StatusInternalServerError, Error.Wrap(err))
+		return
+	}
+
+	server.writeData(w, data)
+}
+
+// satelliteEstimatedPayoutHandler handles estimated payout API requests of a
+// satellite.
+func (server *Server) satelliteEstimatedPayoutHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	defer mon.Task()(&ctx)(nil)
+
+	satelliteID, err := storj.NodeIDFromString(mux.Vars(r)["id"])
+	if err != nil {
+		server.writeError(w, http.StatusBadRequest, Error.Wrap(err))
+		return
+	}
+
+	data, err := server.payouts.SatelliteEstimates(ctx, satelliteID, time.Now())
+	if err != nil {
+		server.writeError(w, http.StatusInternalServerError, Error.Wrap(err))
+		return
+	}
+
+	server.writeData(w, data)
+}
+
+// bandwidthLimits contains the schedules of the bandwidth limits and the
+// rates currently applied, 0 meaning unlimited.
+type bandwidthLimits struct {
+	Ingress        string      `json:"ingress"`
+	Egress         string      `json:"egress"`
+	IngressApplied memory.Size `json:"ingressApplied"`
+	EgressApplied  memory.Size `json:"egressApplied"`
+}
+
+// limits returns the current bandwidth limits.
+func (server *Server) limits() bandwidthLimits {
+	ingress, egress := server.shaper.Schedules()
+	ingressApplied, egressApplied := server.shaper.Rates()
+	return bandwidthLimits{
+		Ingress:        ingress.String(),
+		Egress:         egress.String(),
+		IngressApplied: ingressApplied,
+		EgressApplied:  egressApplied,
+	}
+}
+
+// bandwidthLimitsHandler handles bandwidth limits API requests.
+func (server *Server) bandwidthLimitsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	defer mon.Task()(&ctx)(nil)
+
+	server.writeData(w, server.limits())
+}
+
+// setBandwidthLimitsHandler handles requests to change the bandwidth limits
+// until the node is restarted.
+func (server *Server) setBandwidthLimitsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	defer mon.Task()(&ctx)(nil)
+
+	if !server.authorizeChange(w, r) {
+		return
+	}
+
+	var request struct {
+		Ingress string `json:"ingress"`
+		Egress  string `json:"egress"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		server.writeError(w, http.StatusBadRequest, Error.Wrap(err))
+		return
+	}
+
+	var ingress, egress shaping.Schedule
+	if err := ingress.Set(request.Ingress); err != nil {
+		server.writeError(w, http.StatusBadRequest, Error.Wrap(err))
+		return
+	}
+	if err := egress.Set(request.Egress); err != nil {
+		server.writeError(w, http.StatusBadRequest, Error.Wrap(err))
+		return
+	}
+	server.shaper.SetSchedules(ingress, egress)
+
+	server.writeData(w, server.limits())
+}
+
+// authorizeChange checks that a request changing the node settings carries
+// the configured api token and a JSON body, which a cross-site form can't
+// send. It writes the error response when the request isn't authorized.
+func (server *Server) authorizeChange(w http.ResponseWriter, r *http.Request) bool {
+	if server.apiToken == "" {
+		server.writeError(w, http.StatusForbidden, Error.New("changes through the api are disabled, set console.api-token to enable them"))
+		return false
+	}
+
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(server.apiToken)) != 1 {
+		server.writeError(w, http.StatusUnauthorized, Error.New("invalid api token"))
+		return false
+	}
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get(contentType))
+	if err != nil || mediaType != applicationJSON {
+		server.writeError(w, http.StatusUnsupportedMediaType, Error.New("content type must be %s", applicationJSON))
+		return false
+	}
+
+	return true
+}
+
 // cacheMiddleware is a middleware for caching static files.
 func (server *Server) cacheMiddleware(fn http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
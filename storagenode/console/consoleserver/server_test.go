@@ -6,10 +6,12 @@ package consoleserver_test
 import (
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
+	"storj.io/common/memory"
 	"storj.io/common/testcontext"
 	"storj.io/storj/private/testplanet"
 )
@@ -45,6 +47,88 @@ func TestConsole(t *testing.T) {
 				_ = req.Body.Close()
 				require.Equal(t, http.StatusOK, req.StatusCode)
 			})
+
+			t.Run("bandwidth limits", func(t *testing.T) {
+				url := fmt.Sprintf("http://%s/api/bandwidth-limits", console.Listener.Addr())
+
+				post := func(token, contentType, body string) int {
+					request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(body))
+					require.NoError(t, err)
+					request.Header.Set("Authorization", "Bearer "+token)
+					request.Header.Set("Content-Type", contentType)
+
+					resp, err := http.DefaultClient.Do(request)
+					require.NoError(t, err)
+					_ = resp.Body.Close()
+					return resp.StatusCode
+				}
+
+				body := `{"ingress":"10MB@08:00-23:00","egress":"20MB"}`
+				require.Equal(t, http.StatusUnauthorized, post("", "application/json", body))
+				require.Equal(t, http.StatusUnauthorized, post("invalid", "application/json", body))
+				require.Equal(t, http.StatusUnsupportedMediaType, post("testplanet", "text/plain", body))
+				ingress, _ := planet.StorageNodes[0].Storage2.Shaper.Schedules()
+				require.Equal(t, "", ingress.String())
+
+				require.Equal(t, http.StatusOK, post("testplanet", "application/json; charset=utf-8", body))
+
+				ingress, egress := planet.StorageNodes[0].Storage2.Shaper.Schedules()
+				require.Equal(t, "10.0 MB@08:00-23:00", ingress.String())
+				require.Equal(t, "20.0 MB", egress.String())
+				_, egressApplied := planet.StorageNodes[0].Storage2.Shaper.Rates()
+				require.Equal(t, 20*memory.MB, egressApplied)
+
+				require.Equal(t, http.StatusBadRequest, post("testplanet", "application/json", `{"ingress":"fast"}`))
+
+				req, err := http.Get(url)
+				require.NoError(t, err)
+				_ = req.Body.Close()
+				require.Equal(t, http.StatusOK, req.StatusCode)
+			})
+
+			t.Run("estimated payout", func(t *testing.T) {
+				url := fmt.Sprintf("http://%s/api/estimated-payout", console.Listener.Addr())
+
+				req, err := http.Get(url)
+				require.NoError(t, err)
+				_ = req.Body.Close()
+				require.Equal(t, http.StatusOK, req.StatusCode)
+
+				req, err = http.Get(url + "/" + planet.Satellites[0].ID().String())
+				require.NoError(t, err)
+				_ = req.Body.Close()
+				require.Equal(t, http.StatusOK, req.StatusCode)
+
+				req, err = http.Get(url + "/satellite")
+				require.NoError(t, err)
+				_ = req.Body.Close()
+				require.Equal(t, http.StatusBadRequest, req.StatusCode)
+			})
+
+			t.Run("order archive", func(t *testing.T) {
+				url := fmt.Sprintf("http://%s/api/orders", console.Listener.Addr())
+
+				req, err := http.Get(url + "/archive?status=accepted&action=get&limit=10")
+				require.NoError(t, err)
+				_ = req.Body.Close()
+				require.Equal(t, http.StatusOK, req.StatusCode)
+
+				req, err = http.Get(url + "/archive?format=csv")
+				require.NoError(t, err)
+				_ = req.Body.Close()
+				require.Equal(t, http.StatusOK, req.StatusCode)
+				require.Equal(t, "text/csv", req.Header.Get("Content-Type"))
+
+				req, err = http.Get(url + "/archive?status=unknown")
+				require.NoError(t, err)
+				_ = req.Body.Close()
+				require.Equal(t, http.StatusBadRequest, req.StatusCode)
+
+				req, err = http.Get(url + "/totals")
+				require.NoError(t, err)
+				_ = req.Body.Close()
+				require.Equal(t, http.StatusOK, req.StatusCode)
+			})
 		},
 	)
 }
@@ -18,8 +18,10 @@ import (
 	"storj.io/storj/private/version/checker"
 	"storj.io/storj/storagenode/bandwidth"
 	"storj.io/storj/storagenode/contact"
+	"storj.io/storj/storagenode/monitor"
 	"storj.io/storj/storagenode/pieces"
 	"storj.io/storj/storagenode/reputation"
+	"storj.io/storj/storagenode/scrubber"
 	"storj.io/storj/storagenode/storageusage"
 	"storj.io/storj/storagenode/trust"
 )
@@ -40,8 +42,10 @@ type Service struct {
 	bandwidthDB    bandwidth.DB
 	reputationDB   reputation.DB
 	storageUsageDB storageusage.DB
+	scrubberDB     scrubber.DB
 	pieceStore     *pieces.Store
 	contact        *contact.Service
+	monitor        *monitor.Service
 
 	version   *checker.Service
 	pingStats *contact.PingStats
@@ -57,7 +61,7 @@ type Service struct {
 // NewService returns new instance of Service.
 func NewService(log *zap.Logger, bandwidth bandwidth.DB, pieceStore *pieces.Store, version *checker.Service,
 	allocatedBandwidth, allocatedDiskSpace memory.Size, walletAddress string, versionInfo version.Info, trust *trust.Pool,
-	reputationDB reputation.DB, storageUsageDB storageusage.DB, pingStats *contact.PingStats, contact *contact.Service) (*Service, error) {
+	reputationDB reputation.DB, storageUsageDB storageusage.DB, scrubberDB scrubber.DB, pingStats *contact.PingStats, contact *contact.Service, monitor *monitor.Service) (*Service, error) {
 	if log == nil {
 		return nil, errs.New("log can't be nil")
 	}
@@ -81,18 +85,24 @@ func NewService(log *zap.Logger, bandwidth bandwidth.DB, pieceStore *pieces.Stor
 	if contact == nil {
 		return nil, errs.New("contact service can't be nil")
 	}
+
+	if monitor == nil {
+		return nil, errs.New("monitor service can't be nil")
+	}
 	return &Service{
 		log:                log,
 		trust:              trust,
 		bandwidthDB:        bandwidth,
 		reputationDB:       reputationDB,
 		storageUsageDB:     storageUsageDB,
+		scrubberDB:         scrubberDB,
 		pieceStore:         pieceStore,
 		version:            version,
 		pingStats:          pingStats,
 		allocatedBandwidth: allocatedBandwidth,
 		allocatedDiskSpace: allocatedDiskSpace,
 		contact:            contact,
+		monitor:            monitor,
 		walletAddress:      walletAddress,
 		startedAt:          time.Now(),
 		versionInfo:        versionInfo,
@@ -104,6 +114,20 @@ type SatelliteInfo struct {
 	ID           storj.NodeID `json:"id"`
 	URL          string       `json:"url"`
 	Disqualified *time.Time   `json:"disqualified"`
+	// QuarantinedPieces is the number of pieces which failed the integrity check.
+	QuarantinedPieces int64 `json:"quarantinedPieces"`
+	// Quota is nil when the satellite doesn't have its own quota.
+	Quota *SatelliteQuota `json:"quota"`
+}
+
+// SatelliteQuota contains the disk space and monthly bandwidth allocated to a
+// satellite and how much of them is used.
+type SatelliteQuota struct {
+	DiskSpace     int64 `json:"diskSpace"`
+	UsedDiskSpace int64 `json:"usedDiskSpace"`
+	// Bandwidth is 0 when the satellite's bandwidth isn't limited separately.
+	Bandwidth     int64 `json:"bandwidth"`
+	UsedBandwidth int64 `json:"usedBandwidth"`
 }
 
 // Dashboard encapsulates dashboard stale data.
@@ -116,6 +140,8 @@ type Dashboard struct {
 	DiskSpace DiskSpaceInfo `json:"diskSpace"`
 	Bandwidth BandwidthInfo `json:"bandwidth"`
 
+	QuarantinedPieces int64 `json:"quarantinedPieces"`
+
 	LastPinged          time.Time    `json:"lastPinged"`
 	LastPingFromID      storj.NodeID `json:"lastPingFromID"`
 	LastPingFromAddress string       `json:"lastPingFromAddress"`
@@ -145,6 +171,25 @@ func (s *Service) GetDashboardData(ctx context.Context) (_ *Dashboard, err error
 		return nil, SNOServiceErr.Wrap(err)
 	}
 
+	quarantined, err := s.scrubberDB.CountQuarantined(ctx)
+	if err != nil {
+		return nil, SNOServiceErr.Wrap(err)
+	}
+
+	quotaUsages, err := s.monitor.QuotaUsages(ctx)
+	if err != nil {
+		return nil, SNOServiceErr.Wrap(err)
+	}
+	quotas := make(map[storj.NodeID]*SatelliteQuota, len(quotaUsages))
+	for _, usage := range quotaUsages {
+		quotas[usage.SatelliteID] = &SatelliteQuota{
+			DiskSpace:     usage.DiskSpace.Int64(),
+			UsedDiskSpace: usage.UsedDiskSpace,
+			Bandwidth:     usage.Bandwidth.Int64(),
+			UsedBandwidth: usage.UsedBandwidth,
+		}
+	}
+
 	for _, rep := range stats {
 		url, err := s.trust.GetAddress(ctx, rep.SatelliteID)
 		if err != nil {
@@ -153,13 +198,19 @@ func (s *Service) GetDashboardData(ctx context.Context) (_ *Dashboard, err error
 
 		data.Satellites = append(data.Satellites,
 			SatelliteInfo{
-				ID:           rep.SatelliteID,
-				Disqualified: rep.Disqualified,
-				URL:          url,
+				ID:                rep.SatelliteID,
+				Disqualified:      rep.Disqualified,
+				URL:               url,
+				QuarantinedPieces: quarantined[rep.SatelliteID],
+				Quota:             quotas[rep.SatelliteID],
 			},
 		)
 	}
 
+	for _, count := range quarantined {
+		data.QuarantinedPieces += count
+	}
+
 	_, piecesContentSize, err := s.pieceStore.SpaceUsedForPieces(ctx)
 	if err != nil {
 		return nil, SNOServiceErr.Wrap(err)
@@ -16,6 +16,7 @@ import (
 	"storj.io/common/rpc"
 	"storj.io/common/storj"
 	"storj.io/common/sync2"
+	"storj.io/storj/storagenode/notifications"
 	"storj.io/storj/storagenode/trust"
 )
 
@@ -27,7 +28,11 @@ type Chore struct {
 	service *Service
 	dialer  rpc.Dialer
 
-	trust *trust.Pool
+	trust         *trust.Pool
+	notifications *notifications.Service
+
+	offlineMu sync.Mutex
+	offline   map[storj.NodeID]bool
 
 	mu       sync.Mutex
 	cycles   map[storj.NodeID]*sync2.Cycle
@@ -42,13 +47,16 @@ var (
 const initialBackOff = time.Second
 
 // NewChore creates a new contact chore
-func NewChore(log *zap.Logger, interval time.Duration, trust *trust.Pool, dialer rpc.Dialer, service *Service) *Chore {
+func NewChore(log *zap.Logger, interval time.Duration, trust *trust.Pool, dialer rpc.Dialer, service *Service, notifications *notifications.Service) *Chore {
 	return &Chore{
 		log:     log,
 		service: service,
 		dialer:  dialer,
 
-		trust: trust,
+		trust:         trust,
+		notifications: notifications,
+
+		offline: make(map[storj.NodeID]bool),
 
 		cycles:   make(map[storj.NodeID]*sync2.Cycle),
 		interval: interval,
@@ -110,6 +118,10 @@ func (chore *Chore) updateCycles(ctx context.Context, group *errgroup.Group, sat
 			chore.log.Debug("Stopping cycle", zap.Stringer("Satellite ID", satellite))
 			cycle.Close()
 			delete(chore.cycles, satellite)
+
+			chore.offlineMu.Lock()
+			delete(chore.offline, satellite)
+			chore.offlineMu.Unlock()
 		}
 	}
 }
@@ -124,6 +136,7 @@ func (chore *Chore) pingSatellite(ctx context.Context, satellite storj.NodeID) e
 		err := chore.pingSatelliteOnce(ctx, satellite)
 		attempts++
 		if err == nil {
+			chore.setOffline(ctx, satellite, nil)
 			return nil
 		}
 		chore.log.Error("ping satellite failed ", zap.Stringer("Satellite ID", satellite), zap.Int("attempts", attempts), zap.Error(err))
@@ -136,16 +149,40 @@ func (chore *Chore) pingSatellite(ctx context.Context, satellite storj.NodeID) e
 		interval *= 2
 		if interval >= chore.interval {
 			chore.log.Info("retries timed out for this cycle", zap.Stringer("Satellite ID", satellite))
+			chore.setOffline(ctx, satellite, err)
 			return nil
 		}
 	}
 
 }
 
+// setOffline records whether the node failed to check in with the satellite
+// and notifies the operator once when it starts failing.
+func (chore *Chore) setOffline(ctx context.Context, satellite storj.NodeID, checkInErr error) {
+	chore.offlineMu.Lock()
+	wasOffline := chore.offline[satellite]
+	chore.offline[satellite] = checkInErr != nil
+	chore.offlineMu.Unlock()
+
+	if checkInErr == nil || wasOffline {
+		return
+	}
+
+	_, err := chore.notifications.Receive(ctx, notifications.NewNotification{
+		SenderID: satellite,
+		Type:     notifications.TypeOffline,
+		Title:    "Your node may be offline",
+		Message:  "Your node repeatedly failed to check in with the satellite, it may be offline or unreachable: " + checkInErr.Error(),
+	})
+	if err != nil {
+		chore.log.Error("unable to insert notification", zap.Stringer("Satellite ID", satellite), zap.Error(err))
+	}
+}
+
 func (chore *Chore) pingSatelliteOnce(ctx context.Context, id storj.NodeID) (err error) {
 	defer mon.Task()(&ctx, id)(&err)
 
-	self := chore.service.Local()
+	self := chore.service.LocalFor(id)
 	address, err := chore.trust.GetAddress(ctx, id)
 	if err != nil {
 		return errPingSatellite.Wrap(err)
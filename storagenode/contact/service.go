@@ -12,6 +12,7 @@ import (
 	"go.uber.org/zap"
 
 	"storj.io/common/pb"
+	"storj.io/common/storj"
 	"storj.io/common/sync2"
 	"storj.io/storj/satellite/overlay"
 )
@@ -35,6 +36,9 @@ type Service struct {
 
 	mu   sync.Mutex
 	self *overlay.NodeDossier
+	// satelliteCapacities contains the capacity reported to the satellites
+	// with a quota.
+	satelliteCapacities map[storj.NodeID]pb.NodeCapacity
 
 	initialized sync2.Fence
 }
@@ -64,3 +68,23 @@ func (service *Service) UpdateSelf(capacity *pb.NodeCapacity) {
 
 	service.initialized.Release()
 }
+
+// UpdateSatelliteCapacities updates the capacities reported to the satellites
+// which have their own quota. Other satellites are told the capacity of the
+// local node.
+func (service *Service) UpdateSatelliteCapacities(capacities map[storj.NodeID]pb.NodeCapacity) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	service.satelliteCapacities = capacities
+}
+
+// LocalFor returns the storagenode node-dossier as reported to the satellite.
+func (service *Service) LocalFor(satelliteID storj.NodeID) overlay.NodeDossier {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	self := *service.self
+	if capacity, ok := service.satelliteCapacities[satelliteID]; ok {
+		self.Capacity = capacity
+	}
+	return self
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/zeebo/errs"
 	"go.uber.org/zap"
 
+	"storj.io/common/memory"
 	"storj.io/common/pb"
 	"storj.io/common/rpc/rpcstatus"
 	"storj.io/storj/storagenode/bandwidth"
@@ -40,8 +41,10 @@ type Endpoint struct {
 
 	startTime        time.Time
 	pieceStoreConfig piecestore.OldConfig
-	dashboardAddress net.Addr
-	externalAddress  string
+	// allocatedDiskSpace is the space allocated on the disks which are used.
+	allocatedDiskSpace memory.Size
+	dashboardAddress   net.Addr
+	externalAddress    string
 }
 
 // NewEndpoint creates piecestore inspector instance
@@ -52,19 +55,21 @@ func NewEndpoint(
 	pingStats *contact.PingStats,
 	usageDB bandwidth.DB,
 	pieceStoreConfig piecestore.OldConfig,
+	allocatedDiskSpace memory.Size,
 	dashboardAddress net.Addr,
 	externalAddress string) *Endpoint {
 
 	return &Endpoint{
-		log:              log,
-		pieceStore:       pieceStore,
-		contact:          contact,
-		pingStats:        pingStats,
-		usageDB:          usageDB,
-		pieceStoreConfig: pieceStoreConfig,
-		dashboardAddress: dashboardAddress,
-		startTime:        time.Now(),
-		externalAddress:  externalAddress,
+		log:                log,
+		pieceStore:         pieceStore,
+		contact:            contact,
+		pingStats:          pingStats,
+		usageDB:            usageDB,
+		pieceStoreConfig:   pieceStoreConfig,
+		allocatedDiskSpace: allocatedDiskSpace,
+		dashboardAddress:   dashboardAddress,
+		startTime:          time.Now(),
+		externalAddress:    externalAddress,
 	}
 }
 
@@ -93,7 +98,7 @@ func (inspector *Endpoint) retrieveStats(ctx context.Context) (_ *pb.StatSummary
 
 	return &pb.StatSummaryResponse{
 		UsedSpace:          piecesContentSize,
-		AvailableSpace:     inspector.pieceStoreConfig.AllocatedDiskSpace.Int64() - piecesContentSize,
+		AvailableSpace:     inspector.allocatedDiskSpace.Int64() - piecesContentSize,
 		UsedIngress:        ingress,
 		UsedEgress:         egress,
 		UsedBandwidth:      totalUsedBandwidth,
@@ -5,6 +5,8 @@ package monitor
 
 import (
 	"context"
+	"fmt"
+	"math"
 	"time"
 
 	"github.com/spacemonkeygo/monkit/v3"
@@ -13,10 +15,14 @@ import (
 
 	"storj.io/common/memory"
 	"storj.io/common/pb"
+	"storj.io/common/storj"
 	"storj.io/common/sync2"
+	"storj.io/storj/private/date"
 	"storj.io/storj/storagenode/bandwidth"
 	"storj.io/storj/storagenode/contact"
+	"storj.io/storj/storagenode/notifications"
 	"storj.io/storj/storagenode/pieces"
+	"storj.io/storj/storagenode/trust"
 )
 
 var (
@@ -31,6 +37,7 @@ type Config struct {
 	Interval         time.Duration `help:"how frequently Kademlia bucket should be refreshed with node stats" default:"1h0m0s"`
 	MinimumDiskSpace memory.Size   `help:"how much disk space a node at minimum has to advertise" default:"500GB"`
 	MinimumBandwidth memory.Size   `help:"how much bandwidth a node at minimum has to advertise" default:"500GB"`
+	LowDiskSpace     float64       `help:"share of the allocated disk space which has to remain free, the operator is notified when less remains, 0 disables the notification" default:"0.05"`
 }
 
 // Service which monitors disk usage
@@ -40,9 +47,13 @@ type Service struct {
 	log                *zap.Logger
 	store              *pieces.Store
 	contact            *contact.Service
+	notifications      *notifications.Service
+	trust              *trust.Pool
 	usageDB            bandwidth.DB
 	allocatedDiskSpace int64
 	allocatedBandwidth int64
+	quotas             Quotas
+	diskAlmostFull     bool
 	Loop               *sync2.Cycle
 	Config             Config
 }
@@ -50,14 +61,17 @@ type Service struct {
 // TODO: should it be responsible for monitoring actual bandwidth as well?
 
 // NewService creates a new storage node monitoring service.
-func NewService(log *zap.Logger, store *pieces.Store, contact *contact.Service, usageDB bandwidth.DB, allocatedDiskSpace, allocatedBandwidth int64, interval time.Duration, config Config) *Service {
+func NewService(log *zap.Logger, store *pieces.Store, contact *contact.Service, notifications *notifications.Service, trust *trust.Pool, usageDB bandwidth.DB, allocatedDiskSpace, allocatedBandwidth int64, quotas Quotas, interval time.Duration, config Config) *Service {
 	return &Service{
 		log:                log,
 		store:              store,
 		contact:            contact,
+		notifications:      notifications,
+		trust:              trust,
 		usageDB:            usageDB,
 		allocatedDiskSpace: allocatedDiskSpace,
 		allocatedBandwidth: allocatedBandwidth,
+		quotas:             quotas,
 		Loop:               sync2.NewCycle(interval),
 		Config:             config,
 	}
@@ -124,6 +138,8 @@ func (service *Service) Run(ctx context.Context) (err error) {
 		return Error.New("bandwidth requirement not met")
 	}
 
+	service.checkQuotas(ctx)
+
 	return service.Loop.Run(ctx, func(ctx context.Context) error {
 		err := service.updateNodeInformation(ctx)
 		if err != nil {
@@ -152,14 +168,72 @@ func (service *Service) updateNodeInformation(ctx context.Context) (err error) {
 		return Error.Wrap(err)
 	}
 
-	service.contact.UpdateSelf(&pb.NodeCapacity{
+	capacity := pb.NodeCapacity{
 		FreeBandwidth: service.allocatedBandwidth - usedBandwidth,
 		FreeDisk:      service.allocatedDiskSpace - usedSpace,
-	})
+	}
+
+	// satellites with a quota are told the capacity left in their quota
+	satelliteCapacities := make(map[storj.NodeID]pb.NodeCapacity, len(service.quotas))
+	for _, quota := range service.quotas {
+		usage, err := service.quotaUsage(ctx, quota)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		satelliteCapacities[quota.SatelliteID] = pb.NodeCapacity{
+			FreeBandwidth: min(capacity.FreeBandwidth, usage.FreeBandwidth()),
+			FreeDisk:      min(capacity.FreeDisk, usage.FreeDiskSpace()),
+		}
+	}
+
+	service.contact.UpdateSelf(&capacity)
+	service.contact.UpdateSatelliteCapacities(satelliteCapacities)
+
+	service.checkDiskSpace(ctx, capacity.FreeDisk)
 
 	return nil
 }
 
+// checkQuotas warns about the quotas of satellites which aren't trusted. The
+// trust list may change while the node is running, so they aren't rejected,
+// but such a quota is most likely a mistyped satellite ID.
+func (service *Service) checkQuotas(ctx context.Context) {
+	for _, quota := range service.quotas {
+		if err := service.trust.VerifySatelliteID(ctx, quota.SatelliteID); err != nil {
+			service.log.Warn("quota configured for a satellite which isn't trusted", zap.Stringer("Satellite ID", quota.SatelliteID))
+		}
+	}
+}
+
+// checkDiskSpace notifies the operator once when the free allocated disk
+// space drops below the configured share.
+func (service *Service) checkDiskSpace(ctx context.Context, freeDiskSpace int64) {
+	if service.Config.LowDiskSpace <= 0 {
+		return
+	}
+
+	almostFull := float64(freeDiskSpace) < service.Config.LowDiskSpace*float64(service.allocatedDiskSpace)
+	if almostFull == service.diskAlmostFull {
+		return
+	}
+	service.diskAlmostFull = almostFull
+	if !almostFull {
+		return
+	}
+
+	service.log.Warn("Disk space is almost full", zap.Int64("free bytes", freeDiskSpace))
+	_, err := service.notifications.Receive(ctx, notifications.NewNotification{
+		SenderID: service.contact.Local().Id,
+		Type:     notifications.TypeDiskAlmostFull,
+		Title:    "Disk space is almost full",
+		Message: fmt.Sprintf("Only %s of the allocated %s disk space is free. Your node won't receive new pieces when it's full.",
+			memory.Size(freeDiskSpace), memory.Size(service.allocatedDiskSpace)),
+	})
+	if err != nil {
+		service.log.Error("unable to insert notification", zap.Error(err))
+	}
+}
+
 func (service *Service) usedSpace(ctx context.Context) (_ int64, err error) {
 	defer mon.Task()(&ctx)(&err)
 	usedSpace, err := service.store.SpaceUsedForPiecesAndTrash(ctx)
@@ -203,3 +277,105 @@ func (service *Service) AvailableBandwidth(ctx context.Context) (_ int64, err er
 
 	return allocatedBandwidth - usage, nil
 }
+
+// QuotaUsage is the usage of a satellite's quota.
+type QuotaUsage struct {
+	Quota
+
+	UsedDiskSpace int64
+	UsedBandwidth int64
+}
+
+// FreeDiskSpace returns the disk space left in the quota.
+func (usage QuotaUsage) FreeDiskSpace() int64 {
+	return usage.DiskSpace.Int64() - usage.UsedDiskSpace
+}
+
+// FreeBandwidth returns the bandwidth left in the quota, or the maximum
+// value when the quota doesn't limit the bandwidth.
+func (usage QuotaUsage) FreeBandwidth() int64 {
+	if usage.Bandwidth <= 0 {
+		return math.MaxInt64
+	}
+	return usage.Bandwidth.Int64() - usage.UsedBandwidth
+}
+
+// quotaUsage returns the disk space and the bandwidth of the current month
+// used by the satellite.
+func (service *Service) quotaUsage(ctx context.Context, quota Quota) (_ QuotaUsage, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	usage := QuotaUsage{Quota: quota}
+	usage.UsedDiskSpace, _, err = service.store.SpaceUsedBySatellite(ctx, quota.SatelliteID)
+	if err != nil {
+		return QuotaUsage{}, err
+	}
+	if quota.Bandwidth > 0 {
+		now := time.Now()
+		beginningOfMonth, _ := date.MonthBoundary(now.UTC())
+		bandwidthUsage, err := service.usageDB.SatelliteSummary(ctx, quota.SatelliteID, beginningOfMonth, now)
+		if err != nil {
+			return QuotaUsage{}, err
+		}
+		usage.UsedBandwidth = bandwidthUsage.Total()
+	}
+	return usage, nil
+}
+
+// QuotaUsages returns the usage of every satellite quota.
+func (service *Service) QuotaUsages(ctx context.Context) (usages []QuotaUsage, err error) {
+	defer mon.Task()(&ctx)(&err)
+	for _, quota := range service.quotas {
+		usage, err := service.quotaUsage(ctx, quota)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		usages = append(usages, usage)
+	}
+	return usages, nil
+}
+
+// AvailableSpaceForSatellite returns available disk space for uploads from
+// the satellite, which is limited by its quota.
+func (service *Service) AvailableSpaceForSatellite(ctx context.Context, satelliteID storj.NodeID) (_ int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+	available, err := service.AvailableSpace(ctx)
+	if err != nil {
+		return 0, err
+	}
+	quota, ok := service.quotas.Find(satelliteID)
+	if !ok {
+		return available, nil
+	}
+	usage, err := service.quotaUsage(ctx, quota)
+	if err != nil {
+		return 0, Error.Wrap(err)
+	}
+	return min(available, usage.FreeDiskSpace()), nil
+}
+
+// AvailableBandwidthForSatellite returns available bandwidth for uploads and
+// downloads of the satellite, which is limited by its quota.
+func (service *Service) AvailableBandwidthForSatellite(ctx context.Context, satelliteID storj.NodeID) (_ int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+	available, err := service.AvailableBandwidth(ctx)
+	if err != nil {
+		return 0, err
+	}
+	quota, ok := service.quotas.Find(satelliteID)
+	if !ok || quota.Bandwidth <= 0 {
+		return available, nil
+	}
+	usage, err := service.quotaUsage(ctx, quota)
+	if err != nil {
+		return 0, Error.Wrap(err)
+	}
+	return min(available, usage.FreeBandwidth()), nil
+}
+
+func min(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,104 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package monitor
+
+import (
+	"strings"
+
+	"storj.io/common/memory"
+	"storj.io/common/storj"
+	"storj.io/storj/private/flaglist"
+)
+
+// Quota is the disk space and monthly bandwidth allocated to a satellite.
+type Quota struct {
+	SatelliteID storj.NodeID
+	DiskSpace   memory.Size
+	// Bandwidth is 0 when the satellite's bandwidth is only limited by the
+	// bandwidth allocated to the node.
+	Bandwidth memory.Size
+}
+
+// String returns the quota as SATELLITEID=DISK[:BANDWIDTH].
+func (quota Quota) String() string {
+	s := quota.SatelliteID.String() + "=" + quota.DiskSpace.String()
+	if quota.Bandwidth > 0 {
+		s += ":" + quota.Bandwidth.String()
+	}
+	return s
+}
+
+// Quotas is a list of satellite quotas. It's configured as a comma separated
+// list of SATELLITEID=DISK[:BANDWIDTH] entries.
+type Quotas []Quota
+
+// Find returns the quota of the satellite, or false when it has none.
+func (quotas Quotas) Find(satelliteID storj.NodeID) (Quota, bool) {
+	for _, quota := range quotas {
+		if quota.SatelliteID == satelliteID {
+			return quota, true
+		}
+	}
+	return Quota{}, false
+}
+
+// String implements pflag.Value.
+func (quotas Quotas) String() string {
+	values := make([]string, 0, len(quotas))
+	for _, quota := range quotas {
+		values = append(values, quota.String())
+	}
+	return strings.Join(values, ",")
+}
+
+// Set implements pflag.Value.
+func (quotas *Quotas) Set(s string) error {
+	*quotas = nil
+	return flaglist.Parse(s, func(value string) error {
+		satelliteID, sizes, ok := flaglist.Split(value, "=")
+		if !ok {
+			return Error.New("invalid quota %q, expected SATELLITEID=DISK[:BANDWIDTH]", value)
+		}
+
+		var quota Quota
+		var err error
+		quota.SatelliteID, err = storj.NodeIDFromString(satelliteID)
+		if err != nil {
+			return Error.New("invalid quota %q: %v", value, err)
+		}
+		if _, ok := quotas.Find(quota.SatelliteID); ok {
+			return Error.New("invalid quota %q, satellite has several quotas", value)
+		}
+
+		disk, bandwidth, hasBandwidth := flaglist.Split(sizes, ":")
+		if !hasBandwidth {
+			disk = sizes
+		}
+		if quota.DiskSpace, err = parsePositiveSize(disk); err != nil {
+			return Error.New("invalid quota %q, disk space: %v", value, err)
+		}
+		if hasBandwidth {
+			if quota.Bandwidth, err = parsePositiveSize(bandwidth); err != nil {
+				return Error.New("invalid quota %q, bandwidth: %v", value, err)
+			}
+		}
+		*quotas = append(*quotas, quota)
+		return nil
+	})
+}
+
+// Type implements pflag.Value.
+func (*Quotas) Type() string { return "monitor.Quotas" }
+
+// parsePositiveSize parses a memory size, which must be positive.
+func parsePositiveSize(s string) (memory.Size, error) {
+	size, err := flaglist.Size(s)
+	if err != nil {
+		return 0, err
+	}
+	if size <= 0 {
+		return 0, Error.New("size must be positive")
+	}
+	return size, nil
+}
@@ -0,0 +1,96 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package monitor_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/identity/testidentity"
+	"storj.io/common/memory"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/private/testplanet"
+	"storj.io/storj/storagenode"
+	"storj.io/storj/storagenode/monitor"
+)
+
+func TestQuotas(t *testing.T) {
+	first := testidentity.MustPregeneratedSignedIdentity(0, storj.LatestIDVersion()).ID
+	second := testidentity.MustPregeneratedSignedIdentity(1, storj.LatestIDVersion()).ID
+
+	var quotas monitor.Quotas
+	require.NoError(t, quotas.Set(first.String()+"=1TB:2TB, "+second.String()+"=500GB"))
+	require.Equal(t, monitor.Quotas{
+		{SatelliteID: first, DiskSpace: memory.TB, Bandwidth: 2 * memory.TB},
+		{SatelliteID: second, DiskSpace: 500 * memory.GB},
+	}, quotas)
+	require.Equal(t, first.String()+"=1.0 TB:2.0 TB,"+second.String()+"=500.0 GB", quotas.String())
+
+	quota, ok := quotas.Find(second)
+	require.True(t, ok)
+	require.Equal(t, 500*memory.GB, quota.DiskSpace)
+	_, ok = quotas.Find(testrand.NodeID())
+	require.False(t, ok)
+
+	require.NoError(t, quotas.Set(""))
+	require.Empty(t, quotas)
+
+	for _, invalid := range []string{
+		first.String(),
+		"satellite=1TB",
+		first.String() + "=",
+		first.String() + "=lots",
+		first.String() + "=0B",
+		first.String() + "=1TB:2TB:3TB",
+		first.String() + "=1TB," + first.String() + "=2TB",
+	} {
+		require.Error(t, quotas.Set(invalid), invalid)
+	}
+}
+
+func TestSatelliteQuota(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 2, StorageNodeCount: 1, UplinkCount: 1,
+		Reconfigure: testplanet.Reconfigure{
+			StorageNode: func(index int, config *storagenode.Config) {
+				config.Storage.SatelliteQuotas = monitor.Quotas{{
+					SatelliteID: testidentity.MustPregeneratedSignedIdentity(0, storj.LatestIDVersion()).ID,
+					DiskSpace:   100 * memory.KiB,
+					Bandwidth:   memory.MiB,
+				}}
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		limited, unlimited := planet.Satellites[0].ID(), planet.Satellites[1].ID()
+		node := planet.StorageNodes[0]
+		node.Storage2.Monitor.Loop.Pause()
+
+		available, err := node.Storage2.Monitor.AvailableSpaceForSatellite(ctx, limited)
+		require.NoError(t, err)
+		require.Equal(t, (100 * memory.KiB).Int64(), available)
+		available, err = node.Storage2.Monitor.AvailableBandwidthForSatellite(ctx, limited)
+		require.NoError(t, err)
+		require.Equal(t, memory.MiB.Int64(), available)
+
+		total, err := node.Storage2.Monitor.AvailableSpace(ctx)
+		require.NoError(t, err)
+		available, err = node.Storage2.Monitor.AvailableSpaceForSatellite(ctx, unlimited)
+		require.NoError(t, err)
+		require.Equal(t, total, available)
+
+		// the check-in reports the capacity specific to the satellite
+		node.Storage2.Monitor.Loop.TriggerWait()
+		require.Equal(t, (100 * memory.KiB).Int64(), node.Contact.Service.LocalFor(limited).Capacity.FreeDisk)
+		require.Equal(t, node.Local().Capacity.FreeDisk, node.Contact.Service.LocalFor(unlimited).Capacity.FreeDisk)
+
+		usages, err := node.Storage2.Monitor.QuotaUsages(ctx)
+		require.NoError(t, err)
+		require.Len(t, usages, 1)
+		require.Equal(t, limited, usages[0].SatelliteID)
+		require.Zero(t, usages[0].UsedDiskSpace)
+	})
+}
@@ -5,6 +5,7 @@ package nodestats
 
 import (
 	"context"
+	"fmt"
 	"math/rand"
 	"time"
 
@@ -15,6 +16,7 @@ import (
 	"storj.io/common/storj"
 	"storj.io/common/sync2"
 	"storj.io/storj/private/date"
+	"storj.io/storj/storagenode/notifications"
 	"storj.io/storj/storagenode/reputation"
 	"storj.io/storj/storagenode/storageusage"
 	"storj.io/storj/storagenode/trust"
@@ -30,6 +32,7 @@ type Config struct {
 	MaxSleep       time.Duration `help:"maximum duration to wait before requesting data" releaseDefault:"300s" devDefault:"1s"`
 	ReputationSync time.Duration `help:"how often to sync reputation" releaseDefault:"4h" devDefault:"1m"`
 	StorageSync    time.Duration `help:"how often to sync storage" releaseDefault:"12h" devDefault:"2m"`
+	AuditScoreDrop float64       `help:"how much the audit score has to drop between syncs to notify the operator, 0 disables the notification" default:"0.05"`
 }
 
 // CacheStorage encapsulates cache DBs
@@ -45,25 +48,30 @@ type CacheStorage struct {
 type Cache struct {
 	log *zap.Logger
 
-	db      CacheStorage
-	service *Service
-	trust   *trust.Pool
+	db            CacheStorage
+	service       *Service
+	trust         *trust.Pool
+	notifications *notifications.Service
+
+	maxSleep       time.Duration
+	auditScoreDrop float64
 
-	maxSleep   time.Duration
 	Reputation *sync2.Cycle
 	Storage    *sync2.Cycle
 }
 
 // NewCache creates new caching service instance
-func NewCache(log *zap.Logger, config Config, db CacheStorage, service *Service, trust *trust.Pool) *Cache {
+func NewCache(log *zap.Logger, config Config, db CacheStorage, service *Service, trust *trust.Pool, notifications *notifications.Service) *Cache {
 	return &Cache{
-		log:        log,
-		db:         db,
-		service:    service,
-		trust:      trust,
-		maxSleep:   config.MaxSleep,
-		Reputation: sync2.NewCycle(config.ReputationSync),
-		Storage:    sync2.NewCycle(config.StorageSync),
+		log:            log,
+		db:             db,
+		service:        service,
+		trust:          trust,
+		notifications:  notifications,
+		maxSleep:       config.MaxSleep,
+		auditScoreDrop: config.AuditScoreDrop,
+		Reputation:     sync2.NewCycle(config.ReputationSync),
+		Storage:        sync2.NewCycle(config.StorageSync),
 	}
 }
 
@@ -110,14 +118,42 @@ func (cache *Cache) CacheReputationStats(ctx context.Context) (err error) {
 			return err
 		}
 
+		previous, err := cache.db.Reputation.Get(ctx, satellite)
+		if err != nil {
+			return err
+		}
+
 		if err = cache.db.Reputation.Store(ctx, *stats); err != nil {
 			return err
 		}
 
+		cache.checkAuditScore(ctx, *previous, *stats)
 		return nil
 	})
 }
 
+// checkAuditScore notifies the operator when the audit score dropped
+// noticeably since the previous sync.
+func (cache *Cache) checkAuditScore(ctx context.Context, previous, current reputation.Stats) {
+	if cache.auditScoreDrop <= 0 || previous.UpdatedAt.IsZero() {
+		return
+	}
+	if previous.Audit.Score-current.Audit.Score < cache.auditScoreDrop {
+		return
+	}
+
+	_, err := cache.notifications.Receive(ctx, notifications.NewNotification{
+		SenderID: current.SatelliteID,
+		Type:     notifications.TypeAuditScoreDrop,
+		Title:    "Audit score dropped",
+		Message: fmt.Sprintf("Your audit score dropped from %.2f%% to %.2f%%. Your node may be disqualified when it keeps failing audits.",
+			previous.Audit.Score*100, current.Audit.Score*100),
+	})
+	if err != nil {
+		cache.log.Error("unable to insert notification", zap.Stringer("Satellite ID", current.SatelliteID), zap.Error(err))
+	}
+}
+
 // CacheSpaceUsage queries disk space usage from all the satellites
 // known to the storagenode and stores information into db
 func (cache *Cache) CacheSpaceUsage(ctx context.Context) (err error) {
@@ -0,0 +1,109 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"storj.io/storj/private/post"
+)
+
+// EmailConfig defines parameters for forwarding notifications by email.
+type EmailConfig struct {
+	SMTPServerAddress string `user:"true" help:"address of the smtp server which sends the notification emails, disabled when empty" default:""`
+	From              string `user:"true" help:"sender email address of the notification emails" default:""`
+	To                string `user:"true" help:"comma separated recipient email addresses of the notification emails" default:""`
+	AuthType          string `user:"true" help:"smtp authentication type, plain or login" default:"login"`
+	Login             string `user:"true" help:"smtp user login" default:""`
+	Password          string `user:"true" help:"smtp user password" default:""`
+}
+
+// Email sends a batch of notifications in a single email.
+type Email struct {
+	sender *post.SMTPSender
+	to     []post.Address
+}
+
+// NewEmail creates a new email notifier.
+func NewEmail(config EmailConfig) (*Email, error) {
+	host, _, err := net.SplitHostPort(config.SMTPServerAddress)
+	if err != nil {
+		return nil, Error.New("invalid smtp server address %q: %v", config.SMTPServerAddress, err)
+	}
+
+	from, err := mail.ParseAddress(config.From)
+	if err != nil {
+		return nil, Error.New("invalid sender address %q: %v", config.From, err)
+	}
+
+	addresses, err := mail.ParseAddressList(config.To)
+	if err != nil {
+		return nil, Error.New("invalid recipient addresses %q: %v", config.To, err)
+	}
+	to := make([]post.Address, 0, len(addresses))
+	for _, address := range addresses {
+		to = append(to, *address)
+	}
+
+	var auth smtp.Auth
+	switch config.AuthType {
+	case "plain":
+		auth = smtp.PlainAuth("", config.Login, config.Password, host)
+	case "login":
+		auth = post.LoginAuth{
+			Username: config.Login,
+			Password: config.Password,
+		}
+	default:
+		return nil, Error.New("invalid smtp authentication type %q, expected plain or login", config.AuthType)
+	}
+
+	return &Email{
+		sender: &post.SMTPSender{
+			ServerAddress: config.SMTPServerAddress,
+			From:          *from,
+			Auth:          auth,
+		},
+		to: to,
+	}, nil
+}
+
+// Name implements Notifier.
+func (email *Email) Name() string { return "email" }
+
+// Notify implements Notifier.
+func (email *Email) Notify(ctx context.Context, notifications []Notification) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return Error.Wrap(email.sender.SendEmail(ctx, email.message(notifications)))
+}
+
+// message creates the email of the notifications.
+func (email *Email) message(notifications []Notification) *post.Message {
+	subject := fmt.Sprintf("Storage node: %d notifications", len(notifications))
+	if len(notifications) == 1 {
+		subject = "Storage node: " + notifications[0].Title
+	}
+
+	var body strings.Builder
+	for _, notification := range notifications {
+		fmt.Fprintf(&body, "%s\n\n%s\n\nSender: %s\nTime: %s\n\n",
+			notification.Title,
+			notification.Message,
+			notification.SenderID,
+			notification.CreatedAt.UTC().Format(time.RFC1123))
+	}
+
+	return &post.Message{
+		From:      email.sender.From,
+		To:        email.to,
+		Subject:   subject,
+		PlainText: body.String(),
+	}
+}
@@ -36,6 +36,16 @@ const (
 	TypeUptimeCheckFailure Type = 2
 	// TypeDisqualification is a notification type which describes node's disqualification status.
 	TypeDisqualification Type = 3
+	// TypeCorruptPiece is a notification type which describes pieces failing the integrity check.
+	TypeCorruptPiece Type = 4
+	// TypeDiskAlmostFull is a notification type which describes node's disk running out of the allocated space.
+	TypeDiskAlmostFull Type = 5
+	// TypeAuditScoreDrop is a notification type which describes node's audit score dropping.
+	TypeAuditScoreDrop Type = 6
+	// TypeOffline is a notification type which describes node failing to check in with a satellite.
+	TypeOffline Type = 7
+	// TypeOrderSubmissionFailure is a notification type which describes node failing to submit orders to a satellite.
+	TypeOrderSubmissionFailure Type = 8
 )
 
 // NewNotification holds notification entity info which is being received from satellite or local client.
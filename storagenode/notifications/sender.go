@@ -0,0 +1,178 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package notifications
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/common/sync2"
+)
+
+// Config defines parameters for forwarding notifications to the operator.
+type Config struct {
+	Interval        time.Duration `help:"how often the received notifications are forwarded" releaseDefault:"1m0s" devDefault:"10s"`
+	BatchSize       int           `help:"largest number of notifications forwarded at once" default:"50"`
+	QueueSize       int           `help:"largest number of notifications waiting to be forwarded, the oldest ones are dropped" default:"1000"`
+	MaxAttempts     int           `help:"how many times forwarding a batch of notifications is attempted" default:"3"`
+	RetryDelay      time.Duration `help:"how long to wait before retrying to forward notifications, doubled after every attempt" default:"10s"`
+	ShutdownTimeout time.Duration `help:"how long to keep forwarding the queued notifications when the node is shutting down" default:"30s"`
+
+	Webhook WebhookConfig
+	Email   EmailConfig
+}
+
+// Notifier forwards notifications to the operator.
+type Notifier interface {
+	// Name returns the name of the notifier used in logs.
+	Name() string
+	// Notify forwards a batch of notifications.
+	Notify(ctx context.Context, notifications []Notification) error
+}
+
+// NewNotifiers creates the notifiers which are enabled in the config.
+func NewNotifiers(config Config) (notifiers []Notifier, err error) {
+	if config.Webhook.URL != "" {
+		webhook, err := NewWebhook(config.Webhook)
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, webhook)
+	}
+	if config.Email.SMTPServerAddress != "" {
+		email, err := NewEmail(config.Email)
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, email)
+	}
+	return notifiers, nil
+}
+
+// Sender queues the received notifications and forwards them in batches to
+// the notifiers.
+//
+// architecture: Chore
+type Sender struct {
+	log       *zap.Logger
+	config    Config
+	notifiers []Notifier
+
+	mu    sync.Mutex
+	queue []Notification
+
+	Loop *sync2.Cycle
+}
+
+// NewSender creates a new notification sender.
+func NewSender(log *zap.Logger, config Config, notifiers ...Notifier) *Sender {
+	return &Sender{
+		log:       log,
+		config:    config,
+		notifiers: notifiers,
+		Loop:      sync2.NewCycle(config.Interval),
+	}
+}
+
+// Run forwards the queued notifications on an interval.
+func (sender *Sender) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return sender.Loop.Run(ctx, func(ctx context.Context) error {
+		if err := sender.Flush(ctx); err != nil {
+			sender.log.Error("forwarding notifications failed", zap.Error(err))
+		}
+		return nil
+	})
+}
+
+// Close stops forwarding notifications on an interval and forwards the
+// notifications which are still queued, waiting at most the shutdown timeout.
+func (sender *Sender) Close() (err error) {
+	sender.Loop.Close()
+
+	ctx := context.Background()
+	if sender.config.ShutdownTimeout > 0 {
+		var cancel func()
+		ctx, cancel = context.WithTimeout(ctx, sender.config.ShutdownTimeout)
+		defer cancel()
+	}
+	return sender.Flush(ctx)
+}
+
+// Enqueue queues the notification for forwarding. The oldest notifications
+// are dropped when the queue is full.
+func (sender *Sender) Enqueue(notification Notification) {
+	sender.mu.Lock()
+	defer sender.mu.Unlock()
+
+	sender.queue = append(sender.queue, notification)
+	if dropped := len(sender.queue) - sender.config.QueueSize; sender.config.QueueSize > 0 && dropped > 0 {
+		sender.log.Warn("notification queue is full, dropping the oldest notifications", zap.Int("dropped", dropped))
+		mon.Counter("notifications_dropped").Inc(int64(dropped))
+		sender.queue = append(sender.queue[:0], sender.queue[dropped:]...)
+	}
+}
+
+// Flush forwards the queued notifications to every notifier. A batch which
+// still fails after the configured attempts isn't forwarded to the notifier.
+func (sender *Sender) Flush(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	sender.mu.Lock()
+	queue := sender.queue
+	sender.queue = nil
+	sender.mu.Unlock()
+
+	batchSize := sender.config.BatchSize
+	if batchSize <= 0 {
+		batchSize = len(queue)
+	}
+
+	var group errs.Group
+	for len(queue) > 0 {
+		batch := queue
+		if len(batch) > batchSize {
+			batch = batch[:batchSize]
+		}
+		queue = queue[len(batch):]
+
+		for _, notifier := range sender.notifiers {
+			group.Add(sender.notify(ctx, notifier, batch))
+		}
+	}
+	return group.Err()
+}
+
+// notify forwards the batch to the notifier, retrying with a growing delay.
+func (sender *Sender) notify(ctx context.Context, notifier Notifier, batch []Notification) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	delay := sender.config.RetryDelay
+	for attempt := 1; ; attempt++ {
+		err = notifier.Notify(ctx, batch)
+		if err == nil {
+			mon.Counter("notifications_forwarded").Inc(int64(len(batch)))
+			return nil
+		}
+		if attempt >= sender.config.MaxAttempts {
+			break
+		}
+
+		sender.log.Warn("forwarding notifications failed, retrying",
+			zap.String("notifier", notifier.Name()),
+			zap.Int("attempt", attempt),
+			zap.Error(err))
+		if !sync2.Sleep(ctx, delay) {
+			return Error.Wrap(ctx.Err())
+		}
+		delay *= 2
+	}
+
+	mon.Counter("notifications_dropped").Inc(int64(len(batch)))
+	return Error.New("%s: %d notifications not forwarded: %v", notifier.Name(), len(batch), err)
+}
@@ -0,0 +1,122 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package notifications_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/common/testcontext"
+	"storj.io/storj/storagenode/notifications"
+)
+
+type fakeNotifier struct {
+	mu       sync.Mutex
+	failures int
+	attempts int
+	batches  [][]notifications.Notification
+}
+
+func (notifier *fakeNotifier) Name() string { return "fake" }
+
+func (notifier *fakeNotifier) Notify(ctx context.Context, batch []notifications.Notification) error {
+	notifier.mu.Lock()
+	defer notifier.mu.Unlock()
+
+	notifier.attempts++
+	if notifier.failures > 0 {
+		notifier.failures--
+		return errors.New("unavailable")
+	}
+	notifier.batches = append(notifier.batches, batch)
+	return nil
+}
+
+func TestSender(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	config := notifications.Config{
+		BatchSize:   2,
+		QueueSize:   4,
+		MaxAttempts: 2,
+	}
+	enqueue := func(sender *notifications.Sender, titles ...string) {
+		for _, title := range titles {
+			sender.Enqueue(notifications.Notification{Title: title})
+		}
+	}
+	titles := func(batch []notifications.Notification) (titles []string) {
+		for _, notification := range batch {
+			titles = append(titles, notification.Title)
+		}
+		return titles
+	}
+
+	// the oldest notifications are dropped and the rest is sent in batches
+	notifier := &fakeNotifier{failures: 1}
+	sender := notifications.NewSender(zaptest.NewLogger(t), config, notifier)
+	enqueue(sender, "a", "b", "c", "d", "e")
+	require.NoError(t, sender.Flush(ctx))
+	require.Equal(t, 3, notifier.attempts)
+	require.Len(t, notifier.batches, 2)
+	require.Equal(t, []string{"b", "c"}, titles(notifier.batches[0]))
+	require.Equal(t, []string{"d", "e"}, titles(notifier.batches[1]))
+
+	// the queue is empty after flushing
+	require.NoError(t, sender.Flush(ctx))
+	require.Equal(t, 3, notifier.attempts)
+
+	// a failing notifier doesn't stop the others
+	failing := &fakeNotifier{failures: 2}
+	working := &fakeNotifier{}
+	sender = notifications.NewSender(zaptest.NewLogger(t), config, failing, working)
+	enqueue(sender, "a")
+	require.Error(t, sender.Flush(ctx))
+	require.Equal(t, 2, failing.attempts)
+	require.Empty(t, failing.batches)
+	require.Len(t, working.batches, 1)
+
+	// the queued notifications are forwarded on close
+	notifier = &fakeNotifier{}
+	sender = notifications.NewSender(zaptest.NewLogger(t), config, notifier)
+	enqueue(sender, "a", "b", "c")
+	require.NoError(t, sender.Close())
+	require.Len(t, notifier.batches, 2)
+	require.Equal(t, []string{"c"}, titles(notifier.batches[1]))
+}
+
+func TestNewNotifiers(t *testing.T) {
+	notifiers, err := notifications.NewNotifiers(notifications.Config{})
+	require.NoError(t, err)
+	require.Empty(t, notifiers)
+
+	notifiers, err = notifications.NewNotifiers(notifications.Config{
+		Webhook: notifications.WebhookConfig{URL: "https://example.test/hook"},
+		Email: notifications.EmailConfig{
+			SMTPServerAddress: "smtp.example.test:587",
+			From:              "node@example.test",
+			To:                "operator@example.test, Backup <backup@example.test>",
+			AuthType:          "plain",
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, notifiers, 2)
+
+	for _, config := range []notifications.Config{
+		{Webhook: notifications.WebhookConfig{URL: "example.test/hook"}},
+		{Email: notifications.EmailConfig{SMTPServerAddress: "smtp.example.test", From: "a@example.test", To: "b@example.test", AuthType: "login"}},
+		{Email: notifications.EmailConfig{SMTPServerAddress: "smtp.example.test:587", From: "a", To: "b@example.test", AuthType: "login"}},
+		{Email: notifications.EmailConfig{SMTPServerAddress: "smtp.example.test:587", From: "a@example.test", To: "", AuthType: "login"}},
+		{Email: notifications.EmailConfig{SMTPServerAddress: "smtp.example.test:587", From: "a@example.test", To: "b@example.test", AuthType: "oauth2"}},
+	} {
+		_, err := notifications.NewNotifiers(config)
+		require.Error(t, err)
+	}
+}
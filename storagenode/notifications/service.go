@@ -8,25 +8,32 @@ import (
 
 	"github.com/skyrings/skyring-common/tools/uuid"
 	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/zeebo/errs"
 	"go.uber.org/zap"
 )
 
 var (
+	// Error is the default error class for notifications.
+	Error = errs.Class("notifications")
+
 	mon = monkit.Package()
 )
 
 // Service is the notification service between storage nodes and satellites.
 // architecture: Service
 type Service struct {
-	log *zap.Logger
-	db  DB
+	log    *zap.Logger
+	db     DB
+	sender *Sender
 }
 
-// NewService creates a new notification service.
-func NewService(log *zap.Logger, db DB) *Service {
+// NewService creates a new notification service. The sender forwards the
+// received notifications to the operator, it may be nil.
+func NewService(log *zap.Logger, db DB, sender *Sender) *Service {
 	return &Service{
-		log: log,
-		db:  db,
+		log:    log,
+		db:     db,
+		sender: sender,
 	}
 }
 
@@ -37,6 +44,10 @@ func (service *Service) Receive(ctx context.Context, newNotification NewNotifica
 		return Notification{}, err
 	}
 
+	if service.sender != nil {
+		service.sender.Enqueue(notification)
+	}
+
 	return notification, nil
 }
 
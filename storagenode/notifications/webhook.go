@@ -0,0 +1,84 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/zeebo/errs"
+)
+
+// WebhookConfig defines parameters for forwarding notifications to an HTTP endpoint.
+type WebhookConfig struct {
+	URL     string        `user:"true" help:"URL which receives the notifications as JSON in POST requests, disabled when empty" default:""`
+	Timeout time.Duration `help:"timeout of a webhook request" default:"10s"`
+}
+
+// WebhookRequest is the JSON body posted to the webhook.
+type WebhookRequest struct {
+	Notifications []Notification `json:"notifications"`
+}
+
+// Webhook posts notifications as JSON to an HTTP endpoint.
+type Webhook struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhook creates a new webhook notifier.
+func NewWebhook(config WebhookConfig) (*Webhook, error) {
+	parsed, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, Error.New("invalid webhook URL %q: %v", config.URL, err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, Error.New("invalid webhook URL %q, expected http or https scheme", config.URL)
+	}
+
+	return &Webhook{
+		url:    config.URL,
+		client: &http.Client{Timeout: config.Timeout},
+	}, nil
+}
+
+// Name implements Notifier.
+func (webhook *Webhook) Name() string { return "webhook" }
+
+// Notify implements Notifier.
+func (webhook *Webhook) Notify(ctx context.Context, notifications []Notification) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	body, err := json.Marshal(WebhookRequest{Notifications: notifications})
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.url, bytes.NewReader(body))
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := webhook.client.Do(request)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() {
+		// drain the body, so that the connection can be reused
+		_, _ = io.Copy(ioutil.Discard, response.Body)
+		err = errs.Combine(err, Error.Wrap(response.Body.Close()))
+	}()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return Error.New("webhook responded with %q", response.Status)
+	}
+	return nil
+}
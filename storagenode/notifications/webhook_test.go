@@ -0,0 +1,53 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package notifications_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/storagenode/notifications"
+)
+
+func TestWebhook(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	status := http.StatusOK
+	var received notifications.WebhookRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	webhook, err := notifications.NewWebhook(notifications.WebhookConfig{URL: server.URL, Timeout: time.Minute})
+	require.NoError(t, err)
+
+	sent := []notifications.Notification{{
+		SenderID:  testrand.NodeID(),
+		Type:      notifications.TypeDiskAlmostFull,
+		Title:     "Disk space is almost full",
+		Message:   "message",
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}}
+	require.NoError(t, webhook.Notify(ctx, sent))
+	require.Len(t, received.Notifications, 1)
+	require.Equal(t, sent[0].SenderID, received.Notifications[0].SenderID)
+	require.Equal(t, sent[0].Type, received.Notifications[0].Type)
+	require.Equal(t, sent[0].Title, received.Notifications[0].Title)
+	require.True(t, sent[0].CreatedAt.Equal(received.Notifications[0].CreatedAt))
+
+	status = http.StatusServiceUnavailable
+	require.Error(t, webhook.Notify(ctx, sent))
+}
@@ -0,0 +1,187 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package orders
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/pb"
+	"storj.io/common/storj"
+)
+
+// String returns the name of the status.
+func (status Status) String() string {
+	switch status {
+	case StatusUnsent:
+		return "unsent"
+	case StatusAccepted:
+		return "accepted"
+	case StatusRejected:
+		return "rejected"
+	default:
+		return "status(" + strconv.Itoa(int(status)) + ")"
+	}
+}
+
+// ParseStatus parses the name of a status.
+func ParseStatus(s string) (Status, error) {
+	switch strings.ToLower(s) {
+	case "unsent":
+		return StatusUnsent, nil
+	case "accepted":
+		return StatusAccepted, nil
+	case "rejected":
+		return StatusRejected, nil
+	default:
+		return 0, OrderError.New("invalid status %q, expected unsent, accepted or rejected", s)
+	}
+}
+
+// ParseAction parses the name of a piece action, e.g. GET or put_repair.
+func ParseAction(s string) (pb.PieceAction, error) {
+	action, ok := pb.PieceAction_value[strings.ToUpper(s)]
+	if !ok {
+		return 0, OrderError.New("invalid action %q", s)
+	}
+	return pb.PieceAction(action), nil
+}
+
+// ArchiveFilter selects archived orders. Unset fields match every order.
+type ArchiveFilter struct {
+	SatelliteID *storj.NodeID
+	Status      *Status
+	Action      *pb.PieceAction
+	// ArchivedAfter and ArchivedBefore limit when the orders were archived.
+	ArchivedAfter  time.Time
+	ArchivedBefore time.Time
+	// Limit is the largest number of orders returned, 0 for all.
+	Limit int
+}
+
+// Match returns whether the archived order matches the filter.
+func (filter *ArchiveFilter) Match(info *ArchivedInfo) bool {
+	switch {
+	case filter.SatelliteID != nil && info.Limit.SatelliteId != *filter.SatelliteID:
+		return false
+	case filter.Status != nil && info.Status != *filter.Status:
+		return false
+	case filter.Action != nil && info.Limit.Action != *filter.Action:
+		return false
+	case !filter.ArchivedAfter.IsZero() && info.ArchivedAt.Before(filter.ArchivedAfter):
+		return false
+	case !filter.ArchivedBefore.IsZero() && !info.ArchivedAt.Before(filter.ArchivedBefore):
+		return false
+	default:
+		return true
+	}
+}
+
+// SatelliteTotals contains the number of orders and bytes settled with a
+// satellite. Totals of the orders deleted from the archive are kept in
+// rollups.
+type SatelliteTotals struct {
+	SatelliteID    storj.NodeID `json:"satelliteId"`
+	AcceptedOrders int64        `json:"acceptedOrders"`
+	AcceptedBytes  int64        `json:"acceptedBytes"`
+	RejectedOrders int64        `json:"rejectedOrders"`
+	RejectedBytes  int64        `json:"rejectedBytes"`
+}
+
+// Include adds the orders with the status to the totals.
+func (totals *SatelliteTotals) Include(status Status, count, amount int64) {
+	switch status {
+	case StatusAccepted:
+		totals.AcceptedOrders += count
+		totals.AcceptedBytes += amount
+	case StatusRejected:
+		totals.RejectedOrders += count
+		totals.RejectedBytes += amount
+	}
+}
+
+// ArchivedOrder is the exported form of an archived order.
+type ArchivedOrder struct {
+	SatelliteID     storj.NodeID `json:"satelliteId"`
+	SerialNumber    string       `json:"serialNumber"`
+	PieceID         string       `json:"pieceId"`
+	Action          string       `json:"action"`
+	Limit           int64        `json:"limit"`
+	Amount          int64        `json:"amount"`
+	OrderCreation   time.Time    `json:"orderCreation"`
+	OrderExpiration time.Time    `json:"orderExpiration"`
+	Status          string       `json:"status"`
+	Reason          string       `json:"reason"`
+	ArchivedAt      time.Time    `json:"archivedAt"`
+}
+
+// Export returns the exported form of the archived order.
+func (info *ArchivedInfo) Export() ArchivedOrder {
+	return ArchivedOrder{
+		SatelliteID:     info.Limit.SatelliteId,
+		SerialNumber:    info.Limit.SerialNumber.String(),
+		PieceID:         info.Limit.PieceId.String(),
+		Action:          info.Limit.Action.String(),
+		Limit:           info.Limit.Limit,
+		Amount:          info.Order.Amount,
+		OrderCreation:   info.Limit.OrderCreation.UTC(),
+		OrderExpiration: info.Limit.OrderExpiration.UTC(),
+		Status:          info.Status.String(),
+		Reason:          info.Reason,
+		ArchivedAt:      info.ArchivedAt.UTC(),
+	}
+}
+
+// ExportArchiveJSON writes the archived orders as a JSON array.
+func ExportArchiveJSON(w io.Writer, infos []*ArchivedInfo) error {
+	exported := make([]ArchivedOrder, 0, len(infos))
+	for _, info := range infos {
+		exported = append(exported, info.Export())
+	}
+	return OrderError.Wrap(json.NewEncoder(w).Encode(exported))
+}
+
+// ExportArchiveCSV writes the archived orders as CSV with a header row.
+func ExportArchiveCSV(w io.Writer, infos []*ArchivedInfo) (err error) {
+	writer := csv.NewWriter(w)
+	defer func() {
+		writer.Flush()
+		err = errs.Combine(err, OrderError.Wrap(writer.Error()))
+	}()
+
+	err = writer.Write([]string{
+		"satellite_id", "serial_number", "piece_id", "action", "limit", "amount",
+		"order_creation", "order_expiration", "status", "reason", "archived_at",
+	})
+	if err != nil {
+		return OrderError.Wrap(err)
+	}
+
+	for _, info := range infos {
+		order := info.Export()
+		err := writer.Write([]string{
+			order.SatelliteID.String(),
+			order.SerialNumber,
+			order.PieceID,
+			order.Action,
+			strconv.FormatInt(order.Limit, 10),
+			strconv.FormatInt(order.Amount, 10),
+			order.OrderCreation.Format(time.RFC3339),
+			order.OrderExpiration.Format(time.RFC3339),
+			order.Status,
+			order.Reason,
+			order.ArchivedAt.Format(time.RFC3339),
+		})
+		if err != nil {
+			return OrderError.Wrap(err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package orders_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/pb"
+	"storj.io/common/testrand"
+	"storj.io/storj/storagenode/orders"
+)
+
+func TestParseStatus(t *testing.T) {
+	for _, status := range []orders.Status{orders.StatusUnsent, orders.StatusAccepted, orders.StatusRejected} {
+		parsed, err := orders.ParseStatus(status.String())
+		require.NoError(t, err)
+		require.Equal(t, status, parsed)
+	}
+
+	_, err := orders.ParseStatus("settled")
+	require.Error(t, err)
+
+	action, err := orders.ParseAction("put_repair")
+	require.NoError(t, err)
+	require.Equal(t, pb.PieceAction_PUT_REPAIR, action)
+
+	_, err = orders.ParseAction("upload")
+	require.Error(t, err)
+}
+
+func TestExportArchive(t *testing.T) {
+	archivedAt := time.Date(2020, 2, 1, 12, 0, 0, 0, time.UTC)
+	infos := []*orders.ArchivedInfo{{
+		Limit: &pb.OrderLimit{
+			SatelliteId:     testrand.NodeID(),
+			SerialNumber:    testrand.SerialNumber(),
+			PieceId:         testrand.PieceID(),
+			Action:          pb.PieceAction_GET,
+			Limit:           100,
+			OrderCreation:   archivedAt.Add(-time.Hour),
+			OrderExpiration: archivedAt.Add(time.Hour),
+		},
+		Order:      &pb.Order{Amount: 50},
+		Status:     orders.StatusRejected,
+		ArchivedAt: archivedAt,
+		Reason:     "rejected by the satellite",
+	}}
+
+	var buffer bytes.Buffer
+	require.NoError(t, orders.ExportArchiveCSV(&buffer, infos))
+
+	records, err := csv.NewReader(&buffer).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	require.Equal(t, []string{
+		infos[0].Limit.SatelliteId.String(),
+		infos[0].Limit.SerialNumber.String(),
+		infos[0].Limit.PieceId.String(),
+		"GET", "100", "50",
+		"2020-02-01T11:00:00Z", "2020-02-01T13:00:00Z",
+		"rejected", "rejected by the satellite", "2020-02-01T12:00:00Z",
+	}, records[1])
+
+	buffer.Reset()
+	require.NoError(t, orders.ExportArchiveJSON(&buffer, infos))
+
+	var exported []orders.ArchivedOrder
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &exported))
+	require.Equal(t, []orders.ArchivedOrder{infos[0].Export()}, exported)
+}
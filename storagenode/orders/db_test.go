@@ -216,7 +216,7 @@ func TestDB_Trivial(t *testing.T) {
 		}
 
 		{ // Ensure Archive works at all
-			err := db.Orders().Archive(ctx, time.Now().UTC(), orders.ArchiveRequest{satelliteID, serial, orders.StatusAccepted})
+			err := db.Orders().Archive(ctx, time.Now().UTC(), orders.ArchiveRequest{Satellite: satelliteID, Serial: serial, Status: orders.StatusAccepted})
 			require.NoError(t, err)
 		}
 
@@ -232,3 +232,124 @@ func TestDB_Trivial(t *testing.T) {
 		}
 	})
 }
+
+func TestDB_QueryArchived(t *testing.T) {
+	storagenodedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db storagenode.DB) {
+		ordersdb := db.Orders()
+
+		satellite0, satellite1 := testrand.NodeID(), testrand.NodeID()
+		archivedAt := time.Now().UTC().Truncate(time.Second)
+
+		enqueue := func(satelliteID storj.NodeID, action pb.PieceAction, amount int64) *orders.Info {
+			info := &orders.Info{
+				Limit: &pb.OrderLimit{
+					SatelliteId:     satelliteID,
+					SerialNumber:    testrand.SerialNumber(),
+					PieceId:         testrand.PieceID(),
+					Action:          action,
+					Limit:           amount,
+					OrderExpiration: archivedAt,
+				},
+				Order: &pb.Order{Amount: amount},
+			}
+			require.NoError(t, ordersdb.Enqueue(ctx, info))
+			return info
+		}
+		archive := func(info *orders.Info, status orders.Status, at time.Time) {
+			request := orders.ArchiveRequest{
+				Satellite: info.Limit.SatelliteId,
+				Serial:    info.Limit.SerialNumber,
+				Status:    status,
+			}
+			if status == orders.StatusRejected {
+				request.Reason = "rejected by the satellite"
+			}
+			require.NoError(t, ordersdb.Archive(ctx, at, request))
+		}
+
+		accepted := enqueue(satellite0, pb.PieceAction_GET, 100)
+		archive(accepted, orders.StatusAccepted, archivedAt.Add(-2*time.Hour))
+		rejected := enqueue(satellite0, pb.PieceAction_PUT, 200)
+		archive(rejected, orders.StatusRejected, archivedAt.Add(-time.Hour))
+		other := enqueue(satellite1, pb.PieceAction_GET, 300)
+		archive(other, orders.StatusAccepted, archivedAt)
+
+		serials := func(infos []*orders.ArchivedInfo) []storj.SerialNumber {
+			var serials []storj.SerialNumber
+			for _, info := range infos {
+				serials = append(serials, info.Limit.SerialNumber)
+			}
+			return serials
+		}
+		statusRejected := orders.StatusRejected
+		actionGet := pb.PieceAction_GET
+
+		for _, test := range []struct {
+			name     string
+			filter   orders.ArchiveFilter
+			expected []*orders.Info
+		}{
+			{"all", orders.ArchiveFilter{}, []*orders.Info{other, rejected, accepted}},
+			{"satellite", orders.ArchiveFilter{SatelliteID: &satellite0}, []*orders.Info{rejected, accepted}},
+			{"status", orders.ArchiveFilter{Status: &statusRejected}, []*orders.Info{rejected}},
+			{"action", orders.ArchiveFilter{Action: &actionGet}, []*orders.Info{other, accepted}},
+			{"after", orders.ArchiveFilter{ArchivedAfter: archivedAt.Add(-time.Hour)}, []*orders.Info{other, rejected}},
+			{"before", orders.ArchiveFilter{ArchivedBefore: archivedAt.Add(-time.Hour)}, []*orders.Info{accepted}},
+			{"limit", orders.ArchiveFilter{Action: &actionGet, Limit: 1}, []*orders.Info{other}},
+		} {
+			archived, err := ordersdb.QueryArchived(ctx, test.filter)
+			require.NoError(t, err, test.name)
+
+			var expected []storj.SerialNumber
+			for _, info := range test.expected {
+				expected = append(expected, info.Limit.SerialNumber)
+			}
+			require.Equal(t, expected, serials(archived), test.name)
+		}
+
+		{ // the rejection reason is archived with the order
+			archived, err := ordersdb.QueryArchived(ctx, orders.ArchiveFilter{Status: &statusRejected})
+			require.NoError(t, err)
+			require.Len(t, archived, 1)
+			require.Equal(t, "rejected by the satellite", archived[0].Reason)
+		}
+
+		expectedTotals := []orders.SatelliteTotals{
+			{SatelliteID: satellite0, AcceptedOrders: 1, AcceptedBytes: 100, RejectedOrders: 1, RejectedBytes: 200},
+			{SatelliteID: satellite1, AcceptedOrders: 1, AcceptedBytes: 300},
+		}
+		if satellite1.Less(satellite0) {
+			expectedTotals[0], expectedTotals[1] = expectedTotals[1], expectedTotals[0]
+		}
+
+		totals, err := ordersdb.ArchiveTotals(ctx)
+		require.NoError(t, err)
+		require.Equal(t, expectedTotals, totals)
+
+		// the totals of the deleted orders are kept in the rollups
+		n, err := ordersdb.CleanArchive(ctx, time.Nanosecond)
+		require.NoError(t, err)
+		require.Equal(t, 3, n)
+
+		archived, err := ordersdb.QueryArchived(ctx, orders.ArchiveFilter{})
+		require.NoError(t, err)
+		require.Empty(t, archived)
+
+		totals, err = ordersdb.ArchiveTotals(ctx)
+		require.NoError(t, err)
+		require.Equal(t, expectedTotals, totals)
+
+		// newly archived orders are added to the rollups
+		more := enqueue(satellite0, pb.PieceAction_GET, 50)
+		archive(more, orders.StatusAccepted, archivedAt)
+
+		totals, err = ordersdb.ArchiveTotals(ctx)
+		require.NoError(t, err)
+		for _, satellite := range totals {
+			if satellite.SatelliteID == satellite0 {
+				require.EqualValues(t, 2, satellite.AcceptedOrders)
+				require.EqualValues(t, 150, satellite.AcceptedBytes)
+			}
+		}
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"io"
 	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/spacemonkeygo/monkit/v3"
@@ -18,6 +19,7 @@ import (
 	"storj.io/common/rpc"
 	"storj.io/common/storj"
 	"storj.io/common/sync2"
+	"storj.io/storj/storagenode/notifications"
 	"storj.io/storj/storagenode/trust"
 )
 
@@ -43,6 +45,8 @@ type ArchivedInfo struct {
 
 	Status     Status
 	ArchivedAt time.Time
+	// Reason describes why the order was rejected.
+	Reason string
 }
 
 // Status is the archival status of the order.
@@ -60,6 +64,7 @@ type ArchiveRequest struct {
 	Satellite storj.NodeID
 	Serial    storj.SerialNumber
 	Status    Status
+	Reason    string
 }
 
 // DB implements storing orders for sending to the satellite.
@@ -77,7 +82,11 @@ type DB interface {
 	Archive(ctx context.Context, archivedAt time.Time, requests ...ArchiveRequest) error
 	// ListArchived returns orders that have been sent.
 	ListArchived(ctx context.Context, limit int) ([]*ArchivedInfo, error)
-	// CleanArchive deletes all entries older than ttl
+	// QueryArchived returns the archived orders matching the filter, most recently archived first.
+	QueryArchived(ctx context.Context, filter ArchiveFilter) ([]*ArchivedInfo, error)
+	// ArchiveTotals returns the totals of the archived orders per satellite, including the deleted ones.
+	ArchiveTotals(ctx context.Context) ([]SatelliteTotals, error)
+	// CleanArchive deletes all entries older than ttl, adding them to the archive totals.
 	CleanArchive(ctx context.Context, ttl time.Duration) (int, error)
 }
 
@@ -98,22 +107,29 @@ type Service struct {
 	log    *zap.Logger
 	config Config
 
-	dialer rpc.Dialer
-	orders DB
-	trust  *trust.Pool
+	dialer        rpc.Dialer
+	orders        DB
+	trust         *trust.Pool
+	notifications *notifications.Service
+
+	failingMu sync.Mutex
+	failing   map[storj.NodeID]bool
 
 	Sender  *sync2.Cycle
 	Cleanup *sync2.Cycle
 }
 
 // NewService creates an order service.
-func NewService(log *zap.Logger, dialer rpc.Dialer, orders DB, trust *trust.Pool, config Config) *Service {
+func NewService(log *zap.Logger, dialer rpc.Dialer, orders DB, trust *trust.Pool, notifications *notifications.Service, config Config) *Service {
 	return &Service{
-		log:    log,
-		dialer: dialer,
-		orders: orders,
-		config: config,
-		trust:  trust,
+		log:           log,
+		dialer:        dialer,
+		orders:        orders,
+		config:        config,
+		trust:         trust,
+		notifications: notifications,
+
+		failing: make(map[storj.NodeID]bool),
 
 		Sender:  sync2.NewCycle(config.SenderInterval),
 		Cleanup: sync2.NewCycle(config.CleanupInterval),
@@ -221,6 +237,30 @@ func (service *Service) Settle(ctx context.Context, satelliteID storj.NodeID, or
 	if err != nil {
 		log.Error("failed to settle orders", zap.Error(err))
 	}
+	service.setFailing(ctx, satelliteID, err)
+}
+
+// setFailing records whether settling the orders with the satellite failed
+// and notifies the operator once when it starts failing.
+func (service *Service) setFailing(ctx context.Context, satelliteID storj.NodeID, settleErr error) {
+	service.failingMu.Lock()
+	wasFailing := service.failing[satelliteID]
+	service.failing[satelliteID] = settleErr != nil
+	service.failingMu.Unlock()
+
+	if settleErr == nil || wasFailing {
+		return
+	}
+
+	_, err := service.notifications.Receive(ctx, notifications.NewNotification{
+		SenderID: satelliteID,
+		Type:     notifications.TypeOrderSubmissionFailure,
+		Title:    "Failed to submit orders",
+		Message:  "Your node couldn't submit its orders to the satellite, it will retry later. Unsubmitted orders aren't paid when they expire: " + settleErr.Error(),
+	})
+	if err != nil {
+		service.log.Error("unable to insert notification", zap.Stringer("Satellite ID", satelliteID), zap.Error(err))
+	}
 }
 
 func (service *Service) handleBatches(ctx context.Context, requests chan ArchiveRequest) (err error) {
@@ -287,6 +327,13 @@ func (service *Service) settle(ctx context.Context, log *zap.Logger, satelliteID
 		return OrderError.New("failed to start settlement: %w", err)
 	}
 
+	// the satellite doesn't tell why it rejects an order, keep what the
+	// node knows about it
+	expirations := make(map[storj.SerialNumber]time.Time, len(orders))
+	for _, order := range orders {
+		expirations[order.Limit.SerialNumber] = order.Limit.OrderExpiration
+	}
+
 	var group errgroup.Group
 	var sendErrors errs.Group
 
@@ -333,11 +380,13 @@ func (service *Service) settle(ctx context.Context, log *zap.Logger, satelliteID
 		}
 
 		var status Status
+		var reason string
 		switch response.Status {
 		case pb.SettlementResponse_ACCEPTED:
 			status = StatusAccepted
 		case pb.SettlementResponse_REJECTED:
 			status = StatusRejected
+			reason = rejectionReason(expirations[response.SerialNumber], time.Now())
 		default:
 			err := OrderError.New("unexpected settlement status response: %d", response.Status)
 			log.Error("rpc client received an unexpected new orders settlement status",
@@ -351,6 +400,7 @@ func (service *Service) settle(ctx context.Context, log *zap.Logger, satelliteID
 			Satellite: satelliteID,
 			Serial:    response.SerialNumber,
 			Status:    status,
+			Reason:    reason,
 		}
 	}
 
@@ -361,6 +411,15 @@ func (service *Service) settle(ctx context.Context, log *zap.Logger, satelliteID
 	return errList.Err()
 }
 
+// rejectionReason returns the reason for the satellite rejecting an order
+// with the expiration.
+func rejectionReason(expiration time.Time, now time.Time) string {
+	if !expiration.IsZero() && expiration.Before(now) {
+		return "order expired before it was settled"
+	}
+	return "rejected by the satellite"
+}
+
 // sleep for random interval in [0;maxSleep)
 // returns error if context was cancelled
 func (service *Service) sleep(ctx context.Context) error {
@@ -0,0 +1,163 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package payouts
+
+import (
+	"strconv"
+	"strings"
+
+	"storj.io/common/storj"
+	"storj.io/storj/private/flaglist"
+)
+
+// Rates are the amounts in USD paid to the storage node. Egress is paid per
+// TB and disk space per TB-month.
+type Rates struct {
+	Egress       float64 `json:"egress"`
+	RepairEgress float64 `json:"repairEgress"`
+	AuditEgress  float64 `json:"auditEgress"`
+	DiskSpace    float64 `json:"diskSpace"`
+}
+
+// String implements pflag.Value.
+func (rates Rates) String() string {
+	values := []float64{rates.Egress, rates.RepairEgress, rates.AuditEgress, rates.DiskSpace}
+	formatted := make([]string, 0, len(values))
+	for _, value := range values {
+		formatted = append(formatted, strconv.FormatFloat(value, 'f', -1, 64))
+	}
+	return strings.Join(formatted, "/")
+}
+
+// Set implements pflag.Value. The rates are formatted as
+// EGRESS/REPAIR/AUDIT/DISK, e.g. 20/10/10/1.5.
+func (rates *Rates) Set(s string) error {
+	values := strings.Split(s, "/")
+	if len(values) != 4 {
+		return Error.New("invalid rates %q, expected EGRESS/REPAIR/AUDIT/DISK", s)
+	}
+
+	parsed := make([]float64, 0, len(values))
+	for _, value := range values {
+		rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return Error.New("invalid rates %q: %v", s, err)
+		}
+		if rate < 0 {
+			return Error.New("invalid rates %q, rates can't be negative", s)
+		}
+		parsed = append(parsed, rate)
+	}
+
+	*rates = Rates{
+		Egress:       parsed[0],
+		RepairEgress: parsed[1],
+		AuditEgress:  parsed[2],
+		DiskSpace:    parsed[3],
+	}
+	return nil
+}
+
+// Type implements pflag.Value.
+func (*Rates) Type() string { return "payouts.Rates" }
+
+// SatelliteRate are the rates paid by a satellite.
+type SatelliteRate struct {
+	SatelliteID storj.NodeID
+	Rates       Rates
+}
+
+// SatelliteRates are the rates of single satellites. They're configured as a
+// comma separated list of SATELLITEID=EGRESS/REPAIR/AUDIT/DISK entries.
+type SatelliteRates []SatelliteRate
+
+// Find returns the rates of the satellite, or false when it has none.
+func (satelliteRates SatelliteRates) Find(satelliteID storj.NodeID) (Rates, bool) {
+	for _, rate := range satelliteRates {
+		if rate.SatelliteID == satelliteID {
+			return rate.Rates, true
+		}
+	}
+	return Rates{}, false
+}
+
+// String implements pflag.Value.
+func (satelliteRates SatelliteRates) String() string {
+	values := make([]string, 0, len(satelliteRates))
+	for _, rate := range satelliteRates {
+		values = append(values, rate.SatelliteID.String()+"="+rate.Rates.String())
+	}
+	return strings.Join(values, ",")
+}
+
+// Set implements pflag.Value.
+func (satelliteRates *SatelliteRates) Set(s string) error {
+	*satelliteRates = nil
+	return flaglist.Parse(s, func(value string) error {
+		satelliteID, rates, ok := flaglist.Split(value, "=")
+		if !ok {
+			return Error.New("invalid satellite rates %q, expected SATELLITEID=EGRESS/REPAIR/AUDIT/DISK", value)
+		}
+
+		var rate SatelliteRate
+		var err error
+		rate.SatelliteID, err = storj.NodeIDFromString(satelliteID)
+		if err != nil {
+			return Error.New("invalid satellite rates %q: %v", value, err)
+		}
+		if _, ok := satelliteRates.Find(rate.SatelliteID); ok {
+			return Error.New("invalid satellite rates %q, satellite has several rates", value)
+		}
+		if err := rate.Rates.Set(rates); err != nil {
+			return err
+		}
+		*satelliteRates = append(*satelliteRates, rate)
+		return nil
+	})
+}
+
+// Type implements pflag.Value.
+func (*SatelliteRates) Type() string { return "payouts.SatelliteRates" }
+
+// HeldBack are the percentages of the payout held back in the months after
+// the node started working with a satellite, e.g. 75,75,75,50,50,50,25,25,25.
+// Nothing is held back in the later months.
+type HeldBack []float64
+
+// Percent returns the percentage held back in the month of the node age,
+// starting from 0.
+func (heldBack HeldBack) Percent(month int) float64 {
+	if month < 0 || month >= len(heldBack) {
+		return 0
+	}
+	return heldBack[month]
+}
+
+// String implements pflag.Value.
+func (heldBack HeldBack) String() string {
+	values := make([]string, 0, len(heldBack))
+	for _, percent := range heldBack {
+		values = append(values, strconv.FormatFloat(percent, 'f', -1, 64))
+	}
+	return strings.Join(values, ",")
+}
+
+// Set implements pflag.Value.
+func (heldBack *HeldBack) Set(s string) error {
+	*heldBack = nil
+	return flaglist.Parse(s, func(value string) error {
+		percent, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return Error.New("invalid held back percentages %q: %v", s, err)
+		}
+		if percent < 0 || percent > 100 {
+			return Error.New("invalid held back percentages %q, %v is not between 0 and 100", s, percent)
+		}
+		*heldBack = append(*heldBack, percent)
+		return nil
+	})
+}
+
+// Type implements pflag.Value.
+func (*HeldBack) Type() string { return "payouts.HeldBack" }
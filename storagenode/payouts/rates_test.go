@@ -0,0 +1,67 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package payouts_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/testrand"
+	"storj.io/storj/storagenode/payouts"
+)
+
+func TestRates(t *testing.T) {
+	var rates payouts.Rates
+	require.NoError(t, rates.Set("20/10/ 10/1.5"))
+	require.Equal(t, payouts.Rates{Egress: 20, RepairEgress: 10, AuditEgress: 10, DiskSpace: 1.5}, rates)
+	require.Equal(t, "20/10/10/1.5", rates.String())
+
+	for _, invalid := range []string{"", "20/10/10", "20/10/10/1.5/1", "20/ten/10/1.5", "20/-10/10/1.5"} {
+		require.Error(t, rates.Set(invalid), invalid)
+	}
+
+	first, second := testrand.NodeID(), testrand.NodeID()
+	var satelliteRates payouts.SatelliteRates
+	require.NoError(t, satelliteRates.Set(first.String()+"=20/10/10/1.5, "+second.String()+"=1/2/3/4"))
+	require.Len(t, satelliteRates, 2)
+	require.Equal(t, first.String()+"=20/10/10/1.5,"+second.String()+"=1/2/3/4", satelliteRates.String())
+
+	found, ok := satelliteRates.Find(second)
+	require.True(t, ok)
+	require.Equal(t, payouts.Rates{Egress: 1, RepairEgress: 2, AuditEgress: 3, DiskSpace: 4}, found)
+	_, ok = satelliteRates.Find(testrand.NodeID())
+	require.False(t, ok)
+
+	require.NoError(t, satelliteRates.Set(""))
+	require.Empty(t, satelliteRates)
+
+	for _, invalid := range []string{
+		first.String(),
+		"satellite=20/10/10/1.5",
+		first.String() + "=20/10",
+		first.String() + "=1/1/1/1," + first.String() + "=2/2/2/2",
+	} {
+		require.Error(t, satelliteRates.Set(invalid), invalid)
+	}
+}
+
+func TestHeldBack(t *testing.T) {
+	var heldBack payouts.HeldBack
+	require.NoError(t, heldBack.Set("75,75,50,25"))
+	require.Equal(t, payouts.HeldBack{75, 75, 50, 25}, heldBack)
+	require.Equal(t, "75,75,50,25", heldBack.String())
+
+	require.Equal(t, 75.0, heldBack.Percent(0))
+	require.Equal(t, 25.0, heldBack.Percent(3))
+	require.Zero(t, heldBack.Percent(4))
+	require.Zero(t, heldBack.Percent(-1))
+
+	require.NoError(t, heldBack.Set(""))
+	require.Empty(t, heldBack)
+
+	require.Error(t, heldBack.Set("75,half"))
+	require.Error(t, heldBack.Set("75,101"))
+	require.Error(t, heldBack.Set("-1"))
+}
@@ -0,0 +1,298 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package payouts implements estimating the payouts of the storage node.
+package payouts
+
+import (
+	"context"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/common/storj"
+	"storj.io/storj/private/date"
+	"storj.io/storj/storagenode/bandwidth"
+	"storj.io/storj/storagenode/storageusage"
+	"storj.io/storj/storagenode/trust"
+)
+
+var (
+	// Error is the default error class for payouts.
+	Error = errs.Class("payouts")
+
+	mon = monkit.Package()
+)
+
+// tb is the number of bytes the rates are paid for.
+const tb = 1e12
+
+// Config defines parameters for estimating payouts.
+type Config struct {
+	Rates          Rates          `help:"payout rates in USD as EGRESS/REPAIR/AUDIT/DISK, egress is paid per TB and disk space per TB-month" default:"20/10/10/1.5"`
+	SatelliteRates SatelliteRates `user:"true" help:"payout rates of single satellites, e.g. SATELLITEID=20/10/10/1.5" default:""`
+	HeldBack       HeldBack       `help:"percentages of the payout held back in the months after the node started working with a satellite" default:"75,75,75,50,50,50,25,25,25"`
+}
+
+// Usage is the usage the storage node is paid for.
+type Usage struct {
+	Egress       int64 `json:"egress"`
+	RepairEgress int64 `json:"repairEgress"`
+	AuditEgress  int64 `json:"auditEgress"`
+	// DiskSpace is in byte-hours.
+	DiskSpace float64 `json:"diskSpace"`
+}
+
+// Payout contains amounts in USD.
+type Payout struct {
+	Egress       float64 `json:"egress"`
+	RepairEgress float64 `json:"repairEgress"`
+	AuditEgress  float64 `json:"auditEgress"`
+	DiskSpace    float64 `json:"diskSpace"`
+
+	Gross float64 `json:"gross"`
+	Held  float64 `json:"held"`
+	Net   float64 `json:"net"`
+}
+
+// Add adds another payout to this one.
+func (payout *Payout) Add(other Payout) {
+	payout.Egress += other.Egress
+	payout.RepairEgress += other.RepairEgress
+	payout.AuditEgress += other.AuditEgress
+	payout.DiskSpace += other.DiskSpace
+	payout.Gross += other.Gross
+	payout.Held += other.Held
+	payout.Net += other.Net
+}
+
+// Scale returns the payout multiplied by the factor.
+func (payout Payout) Scale(factor float64) Payout {
+	return Payout{
+		Egress:       payout.Egress * factor,
+		RepairEgress: payout.RepairEgress * factor,
+		AuditEgress:  payout.AuditEgress * factor,
+		DiskSpace:    payout.DiskSpace * factor,
+		Gross:        payout.Gross * factor,
+		Held:         payout.Held * factor,
+		Net:          payout.Net * factor,
+	}
+}
+
+// Estimate is the estimated payout of a satellite for a month.
+type Estimate struct {
+	SatelliteID storj.NodeID `json:"satelliteId"`
+	// Age is the number of months since the node started working with the
+	// satellite, starting from 0.
+	Age         int     `json:"age"`
+	HeldPercent float64 `json:"heldPercent"`
+
+	Usage  Usage  `json:"usage"`
+	Payout Payout `json:"payout"`
+}
+
+// MonthEstimate is the estimated payout of a month.
+type MonthEstimate struct {
+	Month      time.Time  `json:"month"`
+	Satellites []Estimate `json:"satellites"`
+	Total      Payout     `json:"total"`
+}
+
+// Estimates contains the estimated payouts of the current and the previous
+// month.
+type Estimates struct {
+	CurrentMonth MonthEstimate `json:"currentMonth"`
+	// CurrentMonthProjected is the payout of the current month when the
+	// usage continues at the same pace until the end of the month.
+	CurrentMonthProjected Payout        `json:"currentMonthProjected"`
+	PreviousMonth         MonthEstimate `json:"previousMonth"`
+}
+
+// Service estimates payouts from the bandwidth and the disk space used for
+// the satellites.
+//
+// architecture: Service
+type Service struct {
+	log    *zap.Logger
+	config Config
+
+	bandwidthDB    bandwidth.DB
+	storageUsageDB storageusage.DB
+	trust          *trust.Pool
+}
+
+// NewService creates a new payout estimation service.
+func NewService(log *zap.Logger, config Config, bandwidthDB bandwidth.DB, storageUsageDB storageusage.DB, trust *trust.Pool) *Service {
+	return &Service{
+		log:            log,
+		config:         config,
+		bandwidthDB:    bandwidthDB,
+		storageUsageDB: storageUsageDB,
+		trust:          trust,
+	}
+}
+
+// Estimates returns the estimated payouts of the trusted satellites and the
+// satellites which the node worked with in the current or the previous month.
+func (service *Service) Estimates(ctx context.Context, now time.Time) (_ *Estimates, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	now = now.UTC()
+	previousMonth, _ := date.MonthBoundary(previousMonth(now))
+	usages, err := service.bandwidthDB.SummaryBySatellite(ctx, previousMonth, now)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	satellites := service.trust.GetSatellites(ctx)
+	for satelliteID := range usages {
+		if !containsID(satellites, satelliteID) {
+			satellites = append(satellites, satelliteID)
+		}
+	}
+
+	return service.estimates(ctx, satellites, now)
+}
+
+// SatelliteEstimates returns the estimated payouts of a satellite.
+func (service *Service) SatelliteEstimates(ctx context.Context, satelliteID storj.NodeID, now time.Time) (_ *Estimates, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return service.estimates(ctx, []storj.NodeID{satelliteID}, now.UTC())
+}
+
+// estimates returns the estimated payouts of the satellites.
+func (service *Service) estimates(ctx context.Context, satellites []storj.NodeID, now time.Time) (_ *Estimates, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	current, err := service.estimateMonth(ctx, satellites, now, now)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := service.estimateMonth(ctx, satellites, previousMonth(now), now)
+	if err != nil {
+		return nil, err
+	}
+
+	estimates := &Estimates{
+		CurrentMonth:  current,
+		PreviousMonth: previous,
+	}
+	if elapsed := now.Sub(current.Month); elapsed > 0 {
+		estimates.CurrentMonthProjected = current.Total.Scale(monthHours(current.Month) / elapsed.Hours())
+	}
+	return estimates, nil
+}
+
+// estimateMonth estimates the payouts of the satellites for the month of the
+// time, with the usage until now.
+func (service *Service) estimateMonth(ctx context.Context, satellites []storj.NodeID, month, now time.Time) (_ MonthEstimate, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	from, to := date.MonthBoundary(month)
+	if to.After(now) {
+		to = now
+	}
+
+	estimate := MonthEstimate{
+		Month:      from,
+		Satellites: []Estimate{},
+	}
+	for _, satelliteID := range satellites {
+		satellite, err := service.estimate(ctx, satelliteID, from, to)
+		if err != nil {
+			return MonthEstimate{}, err
+		}
+		estimate.Satellites = append(estimate.Satellites, satellite)
+		estimate.Total.Add(satellite.Payout)
+	}
+	return estimate, nil
+}
+
+// estimate estimates the payout of the satellite for the usage between from
+// and to, which are in the same month.
+func (service *Service) estimate(ctx context.Context, satelliteID storj.NodeID, from, to time.Time) (_ Estimate, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	egress, err := service.bandwidthDB.SatelliteEgressSummary(ctx, satelliteID, from, to)
+	if err != nil {
+		return Estimate{}, Error.Wrap(err)
+	}
+	diskSpace, err := service.storageUsageDB.SatelliteSummary(ctx, satelliteID, from, to)
+	if err != nil {
+		return Estimate{}, Error.Wrap(err)
+	}
+	firstUsage, err := service.bandwidthDB.SatelliteFirstUsage(ctx, satelliteID)
+	if err != nil {
+		return Estimate{}, Error.Wrap(err)
+	}
+
+	estimate := Estimate{
+		SatelliteID: satelliteID,
+		Age:         monthsBetween(firstUsage, from),
+		Usage: Usage{
+			Egress:       egress.Get,
+			RepairEgress: egress.GetRepair,
+			AuditEgress:  egress.GetAudit,
+			DiskSpace:    diskSpace,
+		},
+	}
+	estimate.HeldPercent = service.config.HeldBack.Percent(estimate.Age)
+
+	rates, ok := service.config.SatelliteRates.Find(satelliteID)
+	if !ok {
+		rates = service.config.Rates
+	}
+	estimate.Payout = calculatePayout(estimate.Usage, rates, monthHours(from), estimate.HeldPercent)
+	return estimate, nil
+}
+
+// calculatePayout calculates the payout of the usage in a month with the
+// hours.
+func calculatePayout(usage Usage, rates Rates, hours float64, heldPercent float64) Payout {
+	payout := Payout{
+		Egress:       float64(usage.Egress) / tb * rates.Egress,
+		RepairEgress: float64(usage.RepairEgress) / tb * rates.RepairEgress,
+		AuditEgress:  float64(usage.AuditEgress) / tb * rates.AuditEgress,
+		DiskSpace:    usage.DiskSpace / hours / tb * rates.DiskSpace,
+	}
+	payout.Gross = payout.Egress + payout.RepairEgress + payout.AuditEgress + payout.DiskSpace
+	payout.Held = payout.Gross * heldPercent / 100
+	payout.Net = payout.Gross - payout.Held
+	return payout
+}
+
+// previousMonth returns a time in the month before the time.
+func previousMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month()-1, 1, 0, 0, 0, 0, t.Location())
+}
+
+// monthHours returns the number of hours in the month of the time.
+func monthHours(t time.Time) float64 {
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	return start.AddDate(0, 1, 0).Sub(start).Hours()
+}
+
+// monthsBetween returns the number of months from the month of first to the
+// month of t, 0 when first is zero or later than t.
+func monthsBetween(first, t time.Time) int {
+	if first.IsZero() {
+		return 0
+	}
+	months := (t.Year()-first.Year())*12 + int(t.Month()) - int(first.Month())
+	if months < 0 {
+		return 0
+	}
+	return months
+}
+
+// containsID returns whether the id is in the ids.
+func containsID(ids []storj.NodeID, id storj.NodeID) bool {
+	for _, other := range ids {
+		if other == id {
+			return true
+		}
+	}
+	return false
+}
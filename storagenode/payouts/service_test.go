@@ -0,0 +1,97 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package payouts_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/common/pb"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/storagenode"
+	"storj.io/storj/storagenode/payouts"
+	"storj.io/storj/storagenode/storagenodedb/storagenodedbtest"
+	"storj.io/storj/storagenode/storageusage"
+)
+
+func TestSatelliteEstimates(t *testing.T) {
+	storagenodedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db storagenode.DB) {
+		const tb = 1e12
+		satelliteID := testrand.NodeID()
+		service := payouts.NewService(zaptest.NewLogger(t), payouts.Config{
+			Rates:    payouts.Rates{Egress: 20, RepairEgress: 10, AuditEgress: 10, DiskSpace: 1.5},
+			HeldBack: payouts.HeldBack{75, 50, 25},
+		}, db.Bandwidth(), db.StorageUsage(), nil)
+
+		// the node started working with the satellite in January
+		require.NoError(t, db.Bandwidth().Add(ctx, satelliteID, pb.PieceAction_PUT, tb, time.Date(2020, 1, 5, 0, 0, 0, 0, time.UTC)))
+
+		// in February it sent 1TB, repaired 1TB, was audited for 0.5TB and
+		// stored 1TB for the whole month
+		february := time.Date(2020, 2, 10, 0, 0, 0, 0, time.UTC)
+		require.NoError(t, db.Bandwidth().Add(ctx, satelliteID, pb.PieceAction_GET, tb, february))
+		require.NoError(t, db.Bandwidth().Add(ctx, satelliteID, pb.PieceAction_GET_REPAIR, tb, february))
+		require.NoError(t, db.Bandwidth().Add(ctx, satelliteID, pb.PieceAction_GET_AUDIT, tb/2, february))
+		var stamps []storageusage.Stamp
+		for day := 1; day <= 29; day++ {
+			stamps = append(stamps, storageusage.Stamp{
+				SatelliteID:   satelliteID,
+				AtRestTotal:   24 * tb,
+				IntervalStart: time.Date(2020, 2, day, 0, 0, 0, 0, time.UTC),
+			})
+		}
+		require.NoError(t, db.StorageUsage().Store(ctx, stamps))
+
+		// in the first half of March it sent 1TB
+		require.NoError(t, db.Bandwidth().Add(ctx, satelliteID, pb.PieceAction_GET, tb, time.Date(2020, 3, 2, 0, 0, 0, 0, time.UTC)))
+
+		now := time.Date(2020, 3, 16, 12, 0, 0, 0, time.UTC)
+		estimates, err := service.SatelliteEstimates(ctx, satelliteID, now)
+		require.NoError(t, err)
+
+		previous := estimates.PreviousMonth
+		require.Equal(t, time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC), previous.Month)
+		require.Len(t, previous.Satellites, 1)
+		require.Equal(t, satelliteID, previous.Satellites[0].SatelliteID)
+		require.Equal(t, 1, previous.Satellites[0].Age)
+		require.Equal(t, 50.0, previous.Satellites[0].HeldPercent)
+		require.Equal(t, payouts.Usage{
+			Egress:       tb,
+			RepairEgress: tb,
+			AuditEgress:  tb / 2,
+			DiskSpace:    29 * 24 * tb,
+		}, previous.Satellites[0].Usage)
+		require.InDelta(t, 20, previous.Total.Egress, 1e-9)
+		require.InDelta(t, 10, previous.Total.RepairEgress, 1e-9)
+		require.InDelta(t, 5, previous.Total.AuditEgress, 1e-9)
+		require.InDelta(t, 1.5, previous.Total.DiskSpace, 1e-9)
+		require.InDelta(t, 36.5, previous.Total.Gross, 1e-9)
+		require.InDelta(t, 18.25, previous.Total.Held, 1e-9)
+		require.InDelta(t, 18.25, previous.Total.Net, 1e-9)
+
+		current := estimates.CurrentMonth
+		require.Equal(t, time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC), current.Month)
+		require.Equal(t, 2, current.Satellites[0].Age)
+		require.InDelta(t, 20, current.Total.Gross, 1e-9)
+		require.InDelta(t, 5, current.Total.Held, 1e-9)
+		require.InDelta(t, 15, current.Total.Net, 1e-9)
+
+		// half of March has passed
+		require.InDelta(t, 40, estimates.CurrentMonthProjected.Gross, 1e-9)
+
+		// the satellite specific rates are used instead of the default ones
+		service = payouts.NewService(zaptest.NewLogger(t), payouts.Config{
+			Rates:          payouts.Rates{Egress: 20, RepairEgress: 10, AuditEgress: 10, DiskSpace: 1.5},
+			SatelliteRates: payouts.SatelliteRates{{SatelliteID: satelliteID, Rates: payouts.Rates{Egress: 40}}},
+		}, db.Bandwidth(), db.StorageUsage(), nil)
+		estimates, err = service.SatelliteEstimates(ctx, satelliteID, now)
+		require.NoError(t, err)
+		require.InDelta(t, 40, estimates.PreviousMonth.Total.Gross, 1e-9)
+		require.Zero(t, estimates.PreviousMonth.Total.Held)
+	})
+}
@@ -16,6 +16,7 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"storj.io/common/identity"
+	"storj.io/common/memory"
 	"storj.io/common/pb"
 	"storj.io/common/peertls/extensions"
 	"storj.io/common/peertls/tlsopts"
@@ -41,12 +42,15 @@ import (
 	"storj.io/storj/storagenode/nodestats"
 	"storj.io/storj/storagenode/notifications"
 	"storj.io/storj/storagenode/orders"
+	"storj.io/storj/storagenode/payouts"
 	"storj.io/storj/storagenode/pieces"
 	"storj.io/storj/storagenode/piecestore"
 	"storj.io/storj/storagenode/preflight"
 	"storj.io/storj/storagenode/reputation"
 	"storj.io/storj/storagenode/retain"
 	"storj.io/storj/storagenode/satellites"
+	"storj.io/storj/storagenode/scrubber"
+	"storj.io/storj/storagenode/shaping"
 	"storj.io/storj/storagenode/storageusage"
 	"storj.io/storj/storagenode/trust"
 )
@@ -76,6 +80,7 @@ type DB interface {
 	StorageUsage() storageusage.DB
 	Satellites() satellites.DB
 	Notifications() notifications.DB
+	Scrubber() scrubber.DB
 }
 
 // Config is all the configuration parameters for a Storage Node
@@ -96,6 +101,8 @@ type Config struct {
 
 	Retain retain.Config
 
+	Scrubber scrubber.Config
+
 	Nodestats nodestats.Config
 
 	Console consoleserver.Config
@@ -105,6 +112,10 @@ type Config struct {
 	Bandwidth bandwidth.Config
 
 	GracefulExit gracefulexit.Config
+
+	Notifications notifications.Config
+
+	Payouts payouts.Config
 }
 
 // Verify verifies whether configuration is consistent and acceptable.
@@ -191,6 +202,9 @@ type Peer struct {
 		TrashChore    *pieces.TrashChore
 		BlobsCache    *pieces.BlobsUsageCache
 		CacheService  *pieces.CacheService
+		PoolService   *pieces.PoolService
+		PackService   *pieces.PackService
+		Shaper        *shaping.Shaper
 		RetainService *retain.Service
 		Endpoint      *piecestore.Endpoint
 		Inspector     *inspector.Endpoint
@@ -200,6 +214,8 @@ type Peer struct {
 
 	Collector *collector.Service
 
+	Scrubber *scrubber.Chore
+
 	NodeStats struct {
 		Service *nodestats.Service
 		Cache   *nodestats.Cache
@@ -209,6 +225,7 @@ type Peer struct {
 	Console struct {
 		Listener net.Listener
 		Service  *console.Service
+		Payouts  *payouts.Service
 		Endpoint *consoleserver.Server
 	}
 
@@ -219,6 +236,7 @@ type Peer struct {
 
 	Notifications struct {
 		Service *notifications.Service
+		Sender  *notifications.Sender
 	}
 
 	Bandwidth *bandwidth.Service
@@ -313,7 +331,22 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, revocationDB exten
 	}
 
 	{ // setup notification service.
-		peer.Notifications.Service = notifications.NewService(peer.Log, peer.DB.Notifications())
+		notifiers, err := notifications.NewNotifiers(config.Notifications)
+		if err != nil {
+			return nil, errs.Combine(err, peer.Close())
+		}
+		if len(notifiers) > 0 {
+			peer.Notifications.Sender = notifications.NewSender(peer.Log.Named("notifications:sender"), config.Notifications, notifiers...)
+			peer.Services.Add(lifecycle.Item{
+				Name:  "notifications:sender",
+				Run:   peer.Notifications.Sender.Run,
+				Close: peer.Notifications.Sender.Close,
+			})
+			peer.Debug.Server.Panel.Add(
+				debug.Cycle("Notifications Sender", peer.Notifications.Sender.Loop))
+		}
+
+		peer.Notifications.Service = notifications.NewService(peer.Log, peer.DB.Notifications(), peer.Notifications.Sender)
 	}
 
 	{ // setup contact service
@@ -344,7 +377,7 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, revocationDB exten
 		peer.Contact.PingStats = new(contact.PingStats)
 		peer.Contact.Service = contact.NewService(peer.Log.Named("contact:service"), self)
 
-		peer.Contact.Chore = contact.NewChore(peer.Log.Named("contact:chore"), config.Contact.Interval, peer.Storage2.Trust, peer.Dialer, peer.Contact.Service)
+		peer.Contact.Chore = contact.NewChore(peer.Log.Named("contact:chore"), config.Contact.Interval, peer.Storage2.Trust, peer.Dialer, peer.Contact.Service, peer.Notifications.Service)
 		peer.Services.Add(lifecycle.Item{
 			Name:  "contact:chore",
 			Run:   peer.Contact.Chore.Run,
@@ -357,6 +390,13 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, revocationDB exten
 
 	}
 
+	// additional disks which couldn't be opened aren't part of the pool, so
+	// the allocated space only includes the disks which are used
+	allocatedDiskSpace := config.Storage.TotalAllocatedDiskSpace()
+	if pool, ok := peer.DB.Pieces().(*pieces.BlobsPool); ok {
+		allocatedDiskSpace = memory.Size(pool.Allocated())
+	}
+
 	{ // setup storage
 		peer.Storage2.BlobsCache = pieces.NewBlobsUsageCache(peer.Log.Named("blobscache"), peer.DB.Pieces())
 
@@ -394,13 +434,47 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, revocationDB exten
 		peer.Debug.Server.Panel.Add(
 			debug.Cycle("Piecestore Cache", peer.Storage2.CacheService.Loop))
 
+		if pool, ok := peer.DB.Pieces().(*pieces.BlobsPool); ok {
+			peer.Storage2.PoolService = pieces.NewPoolService(
+				log.Named("piecestore:pool"),
+				pool,
+				config.Storage2.DiskCheckInterval,
+			)
+			peer.Services.Add(lifecycle.Item{
+				Name:  "piecestore:pool",
+				Run:   peer.Storage2.PoolService.Run,
+				Close: peer.Storage2.PoolService.Close,
+			})
+			peer.Debug.Server.Panel.Add(
+				debug.Cycle("Piecestore Pool", peer.Storage2.PoolService.Loop))
+		}
+
+		// pack files written before packing was disabled are still compacted
+		if packer, ok := peer.DB.Pieces().(pieces.Packer); ok {
+			peer.Storage2.PackService = pieces.NewPackService(
+				log.Named("piecestore:pack"),
+				packer,
+				config.Storage2.PackCompactionInterval,
+			)
+			peer.Services.Add(lifecycle.Item{
+				Name:  "piecestore:pack",
+				Run:   peer.Storage2.PackService.Run,
+				Close: peer.Storage2.PackService.Close,
+			})
+			peer.Debug.Server.Panel.Add(
+				debug.Cycle("Piecestore Pack", peer.Storage2.PackService.Loop))
+		}
+
 		peer.Storage2.Monitor = monitor.NewService(
 			log.Named("piecestore:monitor"),
 			peer.Storage2.Store,
 			peer.Contact.Service,
+			peer.Notifications.Service,
+			peer.Storage2.Trust,
 			peer.DB.Bandwidth(),
-			config.Storage.AllocatedDiskSpace.Int64(),
+			allocatedDiskSpace.Int64(),
 			config.Storage.AllocatedBandwidth.Int64(),
+			config.Storage.SatelliteQuotas,
 			//TODO use config.Storage.Monitor.Interval, but for some reason is not set
 			config.Storage.KBucketRefreshInterval,
 			config.Storage2.Monitor,
@@ -424,6 +498,18 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, revocationDB exten
 			Close: peer.Storage2.RetainService.Close,
 		})
 
+		peer.Storage2.Shaper, err = shaping.NewShaper(peer.Log.Named("piecestore:shaping"), config.Storage2.Shaping)
+		if err != nil {
+			return nil, errs.Combine(err, peer.Close())
+		}
+		peer.Services.Add(lifecycle.Item{
+			Name:  "piecestore:shaping",
+			Run:   peer.Storage2.Shaper.Run,
+			Close: peer.Storage2.Shaper.Close,
+		})
+		peer.Debug.Server.Panel.Add(
+			debug.Cycle("Piecestore Shaping", peer.Storage2.Shaper.Loop))
+
 		peer.Storage2.Endpoint, err = piecestore.NewEndpoint(
 			peer.Log.Named("piecestore"),
 			signing.SignerFromFullIdentity(peer.Identity),
@@ -435,6 +521,7 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, revocationDB exten
 			peer.DB.Orders(),
 			peer.DB.Bandwidth(),
 			peer.DB.UsedSerials(),
+			peer.Storage2.Shaper,
 			config.Storage2,
 		)
 		if err != nil {
@@ -459,6 +546,7 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, revocationDB exten
 			dialer,
 			peer.DB.Orders(),
 			peer.Storage2.Trust,
+			peer.Notifications.Service,
 			config.Storage2.Orders,
 		)
 		peer.Services.Add(lifecycle.Item{
@@ -488,6 +576,7 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, revocationDB exten
 			},
 			peer.NodeStats.Service,
 			peer.Storage2.Trust,
+			peer.Notifications.Service,
 		)
 		peer.Services.Add(lifecycle.Item{
 			Name:  "nodestats:cache",
@@ -507,19 +596,29 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, revocationDB exten
 			peer.Storage2.Store,
 			peer.Version,
 			config.Storage.AllocatedBandwidth,
-			config.Storage.AllocatedDiskSpace,
+			allocatedDiskSpace,
 			config.Operator.Wallet,
 			versionInfo,
 			peer.Storage2.Trust,
 			peer.DB.Reputation(),
 			peer.DB.StorageUsage(),
+			peer.DB.Scrubber(),
 			peer.Contact.PingStats,
 			peer.Contact.Service,
+			peer.Storage2.Monitor,
 		)
 		if err != nil {
 			return nil, errs.Combine(err, peer.Close())
 		}
 
+		peer.Console.Payouts = payouts.NewService(
+			peer.Log.Named("console:payouts"),
+			config.Payouts,
+			peer.DB.Bandwidth(),
+			peer.DB.StorageUsage(),
+			peer.Storage2.Trust,
+		)
+
 		peer.Console.Listener, err = net.Listen("tcp", config.Console.Address)
 		if err != nil {
 			return nil, errs.Combine(err, peer.Close())
@@ -533,9 +632,13 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, revocationDB exten
 
 		peer.Console.Endpoint = consoleserver.NewServer(
 			peer.Log.Named("console:endpoint"),
+			config.Console,
 			assets,
 			peer.Notifications.Service,
 			peer.Console.Service,
+			peer.Console.Payouts,
+			peer.DB.Orders(),
+			peer.Storage2.Shaper,
 			peer.Console.Listener,
 		)
 		peer.Services.Add(lifecycle.Item{
@@ -553,6 +656,7 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, revocationDB exten
 			peer.Contact.PingStats,
 			peer.DB.Bandwidth(),
 			config.Storage,
+			allocatedDiskSpace,
 			peer.Console.Listener.Addr(),
 			config.Contact.ExternalAddress,
 		)
@@ -596,6 +700,17 @@ func New(log *zap.Logger, full *identity.FullIdentity, db DB, revocationDB exten
 	peer.Debug.Server.Panel.Add(
 		debug.Cycle("Collector", peer.Collector.Loop))
 
+	if config.Scrubber.Enabled {
+		peer.Scrubber = scrubber.NewChore(peer.Log.Named("scrubber"), peer.Storage2.Store, peer.Storage2.Trust, peer.DB.Scrubber(), peer.Notifications.Service, config.Scrubber)
+		peer.Services.Add(lifecycle.Item{
+			Name:  "scrubber",
+			Run:   peer.Scrubber.Run,
+			Close: peer.Scrubber.Close,
+		})
+		peer.Debug.Server.Panel.Add(
+			debug.Cycle("Scrubber", peer.Scrubber.Loop))
+	}
+
 	peer.Bandwidth = bandwidth.NewService(peer.Log.Named("bandwidth"), peer.DB.Bandwidth(), config.Bandwidth)
 	peer.Services.Add(lifecycle.Item{
 		Name:  "bandwidth",
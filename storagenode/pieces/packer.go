@@ -0,0 +1,101 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pieces
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/common/sync2"
+)
+
+// Packer is implemented by blob stores which pack small blobs into larger
+// files, such as packstore.Store.
+type Packer interface {
+	// MigrateFiles moves the small blobs stored in separate files to pack
+	// files and returns the number of blobs moved.
+	MigrateFiles(ctx context.Context) (int, error)
+	// Compact reclaims the space of the deleted blobs in the pack files.
+	Compact(ctx context.Context) error
+}
+
+var _ Packer = (*BlobsPool)(nil)
+
+// MigrateFiles moves the small blobs to pack files on the disks which pack
+// blobs.
+func (pool *BlobsPool) MigrateFiles(ctx context.Context) (migrated int, err error) {
+	defer mon.Task()(&ctx)(&err)
+	err = pool.each("migrate", func(disk *poolDisk) error {
+		packer, ok := disk.Blobs.(Packer)
+		if !ok {
+			return nil
+		}
+		count, err := packer.MigrateFiles(ctx)
+		migrated += count
+		return err
+	})
+	return migrated, err
+}
+
+// Compact compacts the pack files on the disks which pack blobs.
+func (pool *BlobsPool) Compact(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return pool.each("compact", func(disk *poolDisk) error {
+		packer, ok := disk.Blobs.(Packer)
+		if !ok {
+			return nil
+		}
+		return packer.Compact(ctx)
+	})
+}
+
+// PackService moves the small pieces stored in separate files to pack files
+// on startup and periodically compacts the pack files.
+//
+// architecture: Chore
+type PackService struct {
+	log    *zap.Logger
+	packer Packer
+	Loop   *sync2.Cycle
+}
+
+// NewPackService creates a new service for the pack files of the packer.
+func NewPackService(log *zap.Logger, packer Packer, interval time.Duration) *PackService {
+	return &PackService{
+		log:    log,
+		packer: packer,
+		Loop:   sync2.NewCycle(interval),
+	}
+}
+
+// Run migrates the small pieces and compacts the pack files on an interval.
+func (service *PackService) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	migrated, err := service.packer.MigrateFiles(ctx)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+		service.log.Error("error moving pieces to pack files", zap.Error(err))
+	} else if migrated > 0 {
+		service.log.Info("moved pieces to pack files", zap.Int("count", migrated))
+	}
+
+	return service.Loop.Run(ctx, func(ctx context.Context) error {
+		if err := service.packer.Compact(ctx); err != nil {
+			service.log.Error("error compacting pack files", zap.Error(err))
+		}
+		return nil
+	})
+}
+
+// Close stops the service.
+func (service *PackService) Close() (err error) {
+	service.Loop.Close()
+	return nil
+}
@@ -0,0 +1,101 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pieces_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/common/identity/testidentity"
+	"storj.io/common/memory"
+	"storj.io/common/pb"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/storage/filestore"
+	"storj.io/storj/storage/packstore"
+	"storj.io/storj/storagenode/pieces"
+)
+
+func TestPackedPieces(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	log := zaptest.NewLogger(t)
+	config := packstore.Config{
+		Enabled:         true,
+		MaxPackedSize:   64 * memory.KiB,
+		PackSize:        memory.MiB,
+		CompactionRatio: 0.5,
+	}
+
+	// pieces stored before packing was enabled
+	files, err := filestore.NewAt(log, ctx.Dir("packed"))
+	require.NoError(t, err)
+	filesOnly, err := filestore.NewAt(log, ctx.Dir("files"))
+	require.NoError(t, err)
+
+	satelliteID := testidentity.MustPregeneratedSignedIdentity(0, storj.LatestIDVersion()).ID
+	write := func(store *pieces.Store, pieceID storj.PieceID, data []byte) {
+		writer, err := store.Writer(ctx, satelliteID, pieceID)
+		require.NoError(t, err)
+		_, err = io.Copy(writer, bytes.NewReader(data))
+		require.NoError(t, err)
+		require.NoError(t, writer.Commit(ctx, &pb.PieceHeader{OrderLimit: pb.OrderLimit{PieceId: pieceID}}))
+	}
+	read := func(store *pieces.Store, pieceID storj.PieceID) []byte {
+		reader, err := store.Reader(ctx, satelliteID, pieceID)
+		require.NoError(t, err)
+		defer ctx.Check(reader.Close)
+		header, err := reader.GetPieceHeader()
+		require.NoError(t, err)
+		require.Equal(t, pieceID, header.OrderLimit.PieceId)
+		data, err := ioutil.ReadAll(reader)
+		require.NoError(t, err)
+		return data
+	}
+
+	data := map[storj.PieceID][]byte{}
+	oldPiece := testrand.PieceID()
+	data[oldPiece] = testrand.Bytes(10 * memory.KiB)
+	write(pieces.NewStore(log, files, nil, nil, nil), oldPiece, data[oldPiece])
+	require.NoError(t, files.Close())
+
+	packed, err := packstore.NewAt(log, ctx.Dir("packed"), config)
+	require.NoError(t, err)
+	pool := pieces.NewBlobsPool(log, []pieces.PoolDisk{
+		{Path: ctx.Dir("packed"), Blobs: packed, Allocated: memory.GiB.Int64()},
+		{Path: ctx.Dir("files"), Blobs: filesOnly},
+	})
+	defer ctx.Check(pool.Close)
+	store := pieces.NewStore(log, pool, nil, nil, nil)
+
+	// small pieces are packed, large ones are stored in files
+	for _, size := range []memory.Size{memory.KiB, 10 * memory.KiB, 256 * memory.KiB} {
+		pieceID := testrand.PieceID()
+		data[pieceID] = testrand.Bytes(size)
+		write(store, pieceID, data[pieceID])
+	}
+
+	migrated, err := pool.MigrateFiles(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, migrated)
+	require.NoError(t, pool.Compact(ctx))
+
+	for pieceID, expected := range data {
+		require.Equal(t, expected, read(store, pieceID))
+	}
+
+	var walked []storj.PieceID
+	require.NoError(t, store.WalkSatellitePieces(ctx, satelliteID, func(access pieces.StoredPieceAccess) error {
+		walked = append(walked, access.PieceID())
+		return nil
+	}))
+	require.Len(t, walked, len(data))
+}
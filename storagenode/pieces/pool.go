@@ -0,0 +1,666 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pieces
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/common/memory"
+	"storj.io/common/sync2"
+	"storj.io/storj/private/flaglist"
+	"storj.io/storj/storage"
+)
+
+// DiskConfig is an additional directory for storing pieces.
+type DiskConfig struct {
+	Path      string
+	Allocated memory.Size
+}
+
+// DiskConfigs is a list of additional directories for storing pieces. It's
+// configured as a comma separated list of PATH=ALLOCATED pairs.
+type DiskConfigs []DiskConfig
+
+// String implements pflag.Value.
+func (disks DiskConfigs) String() string {
+	values := make([]string, 0, len(disks))
+	for _, disk := range disks {
+		values = append(values, disk.Path+"="+disk.Allocated.String())
+	}
+	return strings.Join(values, ",")
+}
+
+// Set implements pflag.Value.
+func (disks *DiskConfigs) Set(s string) error {
+	*disks = nil
+	return flaglist.Parse(s, func(value string) error {
+		// the path may contain the separator, the allocated space can't
+		separator := strings.LastIndex(value, "=")
+		if separator < 0 {
+			return Error.New("invalid disk %q, expected PATH=ALLOCATED", value)
+		}
+
+		disk := DiskConfig{Path: strings.TrimSpace(value[:separator])}
+		if disk.Path == "" {
+			return Error.New("invalid disk %q, path is missing", value)
+		}
+		var err error
+		if disk.Allocated, err = flaglist.Size(value[separator+1:]); err != nil {
+			return Error.New("invalid disk %q, allocated space: %v", value, err)
+		}
+		*disks = append(*disks, disk)
+		return nil
+	})
+}
+
+// Type implements pflag.Value.
+func (*DiskConfigs) Type() string { return "pieces.DiskConfigs" }
+
+// PoolDisk is a single disk of a BlobsPool.
+type PoolDisk struct {
+	// Path is the directory of the blobs, it's used for checking whether
+	// the disk is writable.
+	Path  string
+	Blobs storage.Blobs
+	// Allocated is the space allocated on the disk, 0 means that only the
+	// free space of the disk limits it.
+	Allocated int64
+}
+
+// poolDisk keeps track of the usage and the health of a disk.
+type poolDisk struct {
+	PoolDisk
+
+	mu sync.Mutex
+	// used is the space used by blobs and trash on the disk.
+	used int64
+	// failed is the last error from writing to the disk, nil when the disk
+	// is writable.
+	failed error
+}
+
+func (disk *poolDisk) addUsed(delta int64) {
+	disk.mu.Lock()
+	defer disk.mu.Unlock()
+	disk.used += delta
+	if disk.used < 0 {
+		disk.used = 0
+	}
+}
+
+func (disk *poolDisk) setUsed(used int64) {
+	disk.mu.Lock()
+	defer disk.mu.Unlock()
+	disk.used = used
+}
+
+func (disk *poolDisk) writable() bool {
+	disk.mu.Lock()
+	defer disk.mu.Unlock()
+	return disk.failed == nil
+}
+
+// available returns the space which can be used on the disk.
+func (disk *poolDisk) available() (int64, error) {
+	free, err := disk.Blobs.FreeSpace()
+	if err != nil {
+		return 0, err
+	}
+
+	disk.mu.Lock()
+	defer disk.mu.Unlock()
+	if disk.Allocated > 0 && disk.Allocated-disk.used < free {
+		return disk.Allocated - disk.used, nil
+	}
+	return free, nil
+}
+
+// BlobsPool is a blob storage which places blobs on several disks.
+//
+// New blobs are written to the writable disk with the most available space.
+// Reads, trash and iteration are done on all of the disks. A disk which fails
+// to write isn't used for new blobs until it's writable again.
+//
+// architecture: Database
+type BlobsPool struct {
+	log   *zap.Logger
+	disks []*poolDisk
+}
+
+var _ storage.Blobs = (*BlobsPool)(nil)
+
+// NewBlobsPool creates a blob storage on the disks.
+func NewBlobsPool(log *zap.Logger, disks []PoolDisk) *BlobsPool {
+	pool := &BlobsPool{log: log}
+	for _, disk := range disks {
+		pool.disks = append(pool.disks, &poolDisk{PoolDisk: disk})
+	}
+	return pool
+}
+
+// DiskStatus contains the usage and health of a disk.
+type DiskStatus struct {
+	Path      string
+	Allocated int64
+	Used      int64
+	Writable  bool
+}
+
+// Disks returns the status of every disk.
+func (pool *BlobsPool) Disks() []DiskStatus {
+	statuses := make([]DiskStatus, 0, len(pool.disks))
+	for _, disk := range pool.disks {
+		disk.mu.Lock()
+		statuses = append(statuses, DiskStatus{
+			Path:      disk.Path,
+			Allocated: disk.Allocated,
+			Used:      disk.used,
+			Writable:  disk.failed == nil,
+		})
+		disk.mu.Unlock()
+	}
+	return statuses
+}
+
+// Allocated returns the space allocated on the disks of the pool. Only the
+// disks which were opened are part of the pool, so a configured disk which
+// couldn't be opened doesn't count towards it.
+func (pool *BlobsPool) Allocated() (total int64) {
+	for _, disk := range pool.disks {
+		total += disk.Allocated
+	}
+	return total
+}
+
+// fail marks the disk as not writable.
+func (pool *BlobsPool) fail(disk *poolDisk, err error) {
+	disk.mu.Lock()
+	defer disk.mu.Unlock()
+	if disk.failed == nil {
+		pool.log.Error("disk failed, not storing new pieces on it", zap.String("path", disk.Path), zap.Error(err))
+	}
+	disk.failed = err
+}
+
+// recover marks the disk as writable again.
+func (pool *BlobsPool) recover(disk *poolDisk) {
+	disk.mu.Lock()
+	defer disk.mu.Unlock()
+	if disk.failed != nil {
+		pool.log.Info("disk is writable again", zap.String("path", disk.Path))
+	}
+	disk.failed = nil
+}
+
+// placement returns the writable disks ordered by their available space.
+func (pool *BlobsPool) placement() []*poolDisk {
+	type candidate struct {
+		disk      *poolDisk
+		available int64
+	}
+
+	var candidates []candidate
+	for _, disk := range pool.disks {
+		if !disk.writable() {
+			continue
+		}
+		available, err := disk.available()
+		if err != nil {
+			pool.fail(disk, err)
+			continue
+		}
+		candidates = append(candidates, candidate{disk: disk, available: available})
+	}
+
+	sort.SliceStable(candidates, func(i, k int) bool {
+		return candidates[i].available > candidates[k].available
+	})
+
+	disks := make([]*poolDisk, 0, len(candidates))
+	for _, candidate := range candidates {
+		disks = append(disks, candidate.disk)
+	}
+	return disks
+}
+
+// Create creates a new blob on the writable disk with the most available space.
+func (pool *BlobsPool) Create(ctx context.Context, ref storage.BlobRef, size int64) (_ storage.BlobWriter, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var group errs.Group
+	for _, disk := range pool.placement() {
+		writer, err := disk.Blobs.Create(ctx, ref, size)
+		if err != nil {
+			pool.fail(disk, err)
+			group.Add(err)
+			continue
+		}
+		return &poolWriter{BlobWriter: writer, pool: pool, disk: disk}, nil
+	}
+
+	if err := group.Err(); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return nil, Error.New("no writable disks")
+}
+
+// poolWriter updates the usage of the disk when the blob is committed.
+type poolWriter struct {
+	storage.BlobWriter
+	pool *BlobsPool
+	disk *poolDisk
+}
+
+// Commit commits the blob and accounts for its size on the disk.
+func (writer *poolWriter) Commit(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	// the blob is truncated to the current position when it's committed
+	size, err := writer.BlobWriter.Size()
+	if err != nil {
+		return err
+	}
+
+	err = writer.BlobWriter.Commit(ctx)
+	if err != nil {
+		writer.pool.fail(writer.disk, err)
+		return err
+	}
+
+	writer.disk.addUsed(size)
+	return nil
+}
+
+// find calls fn on the disks until it finds the blob. It returns the error of a
+// failed disk when the blob isn't found on the others, since the blob may be
+// stored on it.
+func (pool *BlobsPool) find(fn func(disk *poolDisk) error) error {
+	var notFound, failed error
+	for _, disk := range pool.disks {
+		err := fn(disk)
+		switch {
+		case err == nil:
+			return nil
+		case isNotExist(err):
+			notFound = err
+		default:
+			failed = err
+		}
+	}
+
+	if failed != nil {
+		return failed
+	}
+	if notFound != nil {
+		return notFound
+	}
+	return os.ErrNotExist
+}
+
+// Open opens a reader for the blob from the disk storing it.
+func (pool *BlobsPool) Open(ctx context.Context, ref storage.BlobRef) (reader storage.BlobReader, err error) {
+	defer mon.Task()(&ctx)(&err)
+	err = pool.find(func(disk *poolDisk) (err error) {
+		reader, err = disk.Blobs.Open(ctx, ref)
+		return err
+	})
+	return reader, err
+}
+
+// OpenWithStorageFormat opens a reader for the blob with the storage format
+// from the disk storing it.
+func (pool *BlobsPool) OpenWithStorageFormat(ctx context.Context, ref storage.BlobRef, formatVer storage.FormatVersion) (reader storage.BlobReader, err error) {
+	defer mon.Task()(&ctx)(&err)
+	err = pool.find(func(disk *poolDisk) (err error) {
+		reader, err = disk.Blobs.OpenWithStorageFormat(ctx, ref, formatVer)
+		return err
+	})
+	return reader, err
+}
+
+// Stat looks up disk metadata of the blob on the disk storing it.
+func (pool *BlobsPool) Stat(ctx context.Context, ref storage.BlobRef) (info storage.BlobInfo, err error) {
+	defer mon.Task()(&ctx)(&err)
+	err = pool.find(func(disk *poolDisk) (err error) {
+		info, err = disk.Blobs.Stat(ctx, ref)
+		return err
+	})
+	return info, err
+}
+
+// StatWithStorageFormat looks up disk metadata of the blob with the storage
+// format on the disk storing it.
+func (pool *BlobsPool) StatWithStorageFormat(ctx context.Context, ref storage.BlobRef, formatVer storage.FormatVersion) (info storage.BlobInfo, err error) {
+	defer mon.Task()(&ctx)(&err)
+	err = pool.find(func(disk *poolDisk) (err error) {
+		info, err = disk.Blobs.StatWithStorageFormat(ctx, ref, formatVer)
+		return err
+	})
+	return info, err
+}
+
+// Delete deletes the blob from the disk storing it.
+func (pool *BlobsPool) Delete(ctx context.Context, ref storage.BlobRef) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return pool.delete(ctx, func(disk *poolDisk) (storage.BlobInfo, error) {
+		return disk.Blobs.Stat(ctx, ref)
+	}, func(disk *poolDisk) error {
+		return disk.Blobs.Delete(ctx, ref)
+	})
+}
+
+// DeleteWithStorageFormat deletes the blob with the storage format from the
+// disk storing it.
+func (pool *BlobsPool) DeleteWithStorageFormat(ctx context.Context, ref storage.BlobRef, formatVer storage.FormatVersion) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return pool.delete(ctx, func(disk *poolDisk) (storage.BlobInfo, error) {
+		return disk.Blobs.StatWithStorageFormat(ctx, ref, formatVer)
+	}, func(disk *poolDisk) error {
+		return disk.Blobs.DeleteWithStorageFormat(ctx, ref, formatVer)
+	})
+}
+
+// delete deletes the blob from every disk where stat finds it. Like the
+// underlying blob stores, it doesn't fail when the blob doesn't exist.
+func (pool *BlobsPool) delete(ctx context.Context, stat func(*poolDisk) (storage.BlobInfo, error), del func(*poolDisk) error) error {
+	var group errs.Group
+	for _, disk := range pool.disks {
+		info, err := stat(disk)
+		if err != nil {
+			if !isNotExist(err) {
+				group.Add(err)
+			}
+			continue
+		}
+
+		var size int64
+		if fileInfo, err := info.Stat(ctx); err == nil {
+			size = fileInfo.Size()
+		}
+
+		if err := del(disk); err != nil {
+			group.Add(err)
+			continue
+		}
+		disk.addUsed(-size)
+	}
+	return group.Err()
+}
+
+// Trash moves the blob to the trash of the disk storing it.
+func (pool *BlobsPool) Trash(ctx context.Context, ref storage.BlobRef) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	for _, disk := range pool.disks {
+		_, err := disk.Blobs.Stat(ctx, ref)
+		if err != nil {
+			continue
+		}
+		return disk.Blobs.Trash(ctx, ref)
+	}
+	// let the first disk report the missing blob
+	return pool.disks[0].Blobs.Trash(ctx, ref)
+}
+
+// Quarantine moves the blob to the quarantine of every disk storing it.
+func (pool *BlobsPool) Quarantine(ctx context.Context, ref storage.BlobRef) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return pool.delete(ctx, func(disk *poolDisk) (storage.BlobInfo, error) {
+		return disk.Blobs.Stat(ctx, ref)
+	}, func(disk *poolDisk) error {
+		return disk.Blobs.Quarantine(ctx, ref)
+	})
+}
+
+// EmptyQuarantine empties the quarantine of the namespace on all disks.
+func (pool *BlobsPool) EmptyQuarantine(ctx context.Context, namespace []byte, quarantinedBefore time.Time) (bytesEmptied int64, keys [][]byte, err error) {
+	defer mon.Task()(&ctx)(&err)
+	err = pool.each("empty quarantine", func(disk *poolDisk) error {
+		diskBytes, diskKeys, err := disk.Blobs.EmptyQuarantine(ctx, namespace, quarantinedBefore)
+		bytesEmptied += diskBytes
+		keys = append(keys, diskKeys...)
+		return err
+	})
+	return bytesEmptied, keys, err
+}
+
+// RestoreTrash restores the trash of the namespace on all disks.
+func (pool *BlobsPool) RestoreTrash(ctx context.Context, namespace []byte) (keysRestored [][]byte, err error) {
+	defer mon.Task()(&ctx)(&err)
+	err = pool.each("restore trash", func(disk *poolDisk) error {
+		keys, err := disk.Blobs.RestoreTrash(ctx, namespace)
+		keysRestored = append(keysRestored, keys...)
+		return err
+	})
+	return keysRestored, err
+}
+
+// EmptyTrash empties the trash of the namespace on all disks.
+func (pool *BlobsPool) EmptyTrash(ctx context.Context, namespace []byte, trashedBefore time.Time) (bytesEmptied int64, keys [][]byte, err error) {
+	defer mon.Task()(&ctx)(&err)
+	err = pool.each("empty trash", func(disk *poolDisk) error {
+		diskBytes, diskKeys, err := disk.Blobs.EmptyTrash(ctx, namespace, trashedBefore)
+		disk.addUsed(-diskBytes)
+		bytesEmptied += diskBytes
+		keys = append(keys, diskKeys...)
+		return err
+	})
+	return bytesEmptied, keys, err
+}
+
+// FreeSpace returns the free space of the writable disks. The free space of a
+// disk is limited by the space allocated on it.
+func (pool *BlobsPool) FreeSpace() (total int64, err error) {
+	err = pool.each("free space", func(disk *poolDisk) error {
+		if !disk.writable() {
+			return nil
+		}
+		available, err := disk.available()
+		if available > 0 {
+			total += available
+		}
+		return err
+	})
+	return total, err
+}
+
+// SpaceUsedForTrash returns the space used by the trash of all disks.
+func (pool *BlobsPool) SpaceUsedForTrash(ctx context.Context) (total int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+	err = pool.each("space used for trash", func(disk *poolDisk) error {
+		used, err := disk.Blobs.SpaceUsedForTrash(ctx)
+		total += used
+		return err
+	})
+	return total, err
+}
+
+// SpaceUsedForBlobs returns the space used by blobs on all disks.
+func (pool *BlobsPool) SpaceUsedForBlobs(ctx context.Context) (total int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+	err = pool.each("space used for blobs", func(disk *poolDisk) error {
+		used, err := disk.Blobs.SpaceUsedForBlobs(ctx)
+		total += used
+		return err
+	})
+	return total, err
+}
+
+// SpaceUsedForBlobsInNamespace returns the space used by blobs in the
+// namespace on all disks.
+func (pool *BlobsPool) SpaceUsedForBlobsInNamespace(ctx context.Context, namespace []byte) (total int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+	err = pool.each("space used for blobs in namespace", func(disk *poolDisk) error {
+		used, err := disk.Blobs.SpaceUsedForBlobsInNamespace(ctx, namespace)
+		total += used
+		return err
+	})
+	return total, err
+}
+
+// ListNamespaces returns the namespaces stored on any of the disks.
+func (pool *BlobsPool) ListNamespaces(ctx context.Context) (namespaces [][]byte, err error) {
+	defer mon.Task()(&ctx)(&err)
+	seen := map[string]struct{}{}
+	err = pool.each("list namespaces", func(disk *poolDisk) error {
+		diskNamespaces, err := disk.Blobs.ListNamespaces(ctx)
+		for _, namespace := range diskNamespaces {
+			if _, ok := seen[string(namespace)]; ok {
+				continue
+			}
+			seen[string(namespace)] = struct{}{}
+			namespaces = append(namespaces, namespace)
+		}
+		return err
+	})
+	return namespaces, err
+}
+
+// WalkNamespace walks the blobs of the namespace on all disks, one disk after
+// another.
+func (pool *BlobsPool) WalkNamespace(ctx context.Context, namespace []byte, walkFunc func(storage.BlobInfo) error) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var walkErr error
+	err = pool.each("walk namespace", func(disk *poolDisk) error {
+		if walkErr != nil {
+			return nil
+		}
+		return disk.Blobs.WalkNamespace(ctx, namespace, func(info storage.BlobInfo) error {
+			walkErr = walkFunc(info)
+			return walkErr
+		})
+	})
+	if walkErr != nil {
+		return walkErr
+	}
+	return err
+}
+
+// each calls fn for every disk. Errors of single disks are logged and skipped,
+// so that a failed disk doesn't stop the others from being used. An error is
+// only returned when every disk failed.
+func (pool *BlobsPool) each(operation string, fn func(disk *poolDisk) error) error {
+	var group errs.Group
+	for _, disk := range pool.disks {
+		if err := fn(disk); err != nil {
+			if errors.Is(err, context.Canceled) {
+				return err
+			}
+			pool.log.Warn("disk failed", zap.String("operation", operation), zap.String("path", disk.Path), zap.Error(err))
+			group.Add(err)
+		}
+	}
+	if len(group) == len(pool.disks) {
+		return group.Err()
+	}
+	return nil
+}
+
+// Refresh recalculates the space used on every disk.
+func (pool *BlobsPool) Refresh(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return pool.each("refresh", func(disk *poolDisk) error {
+		blobsUsed, err := disk.Blobs.SpaceUsedForBlobs(ctx)
+		if err != nil {
+			return err
+		}
+		trashUsed, err := disk.Blobs.SpaceUsedForTrash(ctx)
+		if err != nil {
+			return err
+		}
+		disk.setUsed(blobsUsed + trashUsed)
+		return nil
+	})
+}
+
+// CheckDisks checks whether the disks are writable by writing a small file to
+// them. A disk which failed before is used for new blobs again once the check
+// succeeds.
+func (pool *BlobsPool) CheckDisks(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	for _, disk := range pool.disks {
+		if err := checkWritable(disk.Path); err != nil {
+			pool.fail(disk, err)
+			continue
+		}
+		pool.recover(disk)
+	}
+	return nil
+}
+
+// checkWritable writes and removes a file in the directory.
+func checkWritable(dir string) (err error) {
+	file, err := ioutil.TempFile(dir, "write-check-")
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Combine(err, os.Remove(file.Name())) }()
+
+	_, err = file.Write([]byte{1})
+	if err == nil {
+		err = file.Sync()
+	}
+	return errs.Combine(err, file.Close())
+}
+
+// Close closes the blob stores of all disks.
+func (pool *BlobsPool) Close() error {
+	var group errs.Group
+	for _, disk := range pool.disks {
+		group.Add(disk.Blobs.Close())
+	}
+	return group.Err()
+}
+
+func isNotExist(err error) bool {
+	return os.IsNotExist(err) || errors.Is(err, os.ErrNotExist)
+}
+
+// PoolService calculates the space used on the disks of a BlobsPool on startup
+// and periodically checks whether the disks are writable.
+//
+// architecture: Chore
+type PoolService struct {
+	log  *zap.Logger
+	pool *BlobsPool
+	Loop *sync2.Cycle
+}
+
+// NewPoolService creates a new service for the disks of the pool.
+func NewPoolService(log *zap.Logger, pool *BlobsPool, interval time.Duration) *PoolService {
+	return &PoolService{
+		log:  log,
+		pool: pool,
+		Loop: sync2.NewCycle(interval),
+	}
+}
+
+// Run calculates the space used on the disks and checks the disks on an interval.
+func (service *PoolService) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if err := service.pool.Refresh(ctx); err != nil {
+		service.log.Error("error calculating the space used on disks", zap.Error(err))
+	}
+
+	return service.Loop.Run(ctx, func(ctx context.Context) error {
+		return service.pool.CheckDisks(ctx)
+	})
+}
+
+// Close stops the service.
+func (service *PoolService) Close() (err error) {
+	service.Loop.Close()
+	return nil
+}
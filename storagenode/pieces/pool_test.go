@@ -0,0 +1,180 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pieces_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/common/identity/testidentity"
+	"storj.io/common/memory"
+	"storj.io/common/pb"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/filestore"
+	"storj.io/storj/storagenode/pieces"
+)
+
+func TestDiskConfigs(t *testing.T) {
+	var disks pieces.DiskConfigs
+	require.NoError(t, disks.Set("/mnt/disk2=2TB, /mnt/disk=3=500GB"))
+	require.Equal(t, pieces.DiskConfigs{
+		{Path: "/mnt/disk2", Allocated: 2 * memory.TB},
+		{Path: "/mnt/disk=3", Allocated: 500 * memory.GB},
+	}, disks)
+
+	require.NoError(t, disks.Set(""))
+	require.Empty(t, disks)
+
+	require.Error(t, disks.Set("/mnt/disk2"))
+	require.Error(t, disks.Set("=2TB"))
+	require.Error(t, disks.Set("/mnt/disk2="))
+}
+
+// failingBlobs is a blob storage which fails to create new blobs.
+type failingBlobs struct {
+	storage.Blobs
+}
+
+func (failingBlobs) Create(ctx context.Context, ref storage.BlobRef, size int64) (storage.BlobWriter, error) {
+	return nil, errors.New("read-only file system")
+}
+
+func TestBlobsPool(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	log := zaptest.NewLogger(t)
+
+	newDisk := func(name string, allocated memory.Size) pieces.PoolDisk {
+		blobs, err := filestore.NewAt(log, ctx.Dir(name))
+		require.NoError(t, err)
+		return pieces.PoolDisk{Path: ctx.Dir(name), Blobs: blobs, Allocated: allocated.Int64()}
+	}
+
+	small := newDisk("small", 20*memory.KiB)
+	large := newDisk("large", 1*memory.GiB)
+	pool := pieces.NewBlobsPool(log, []pieces.PoolDisk{small, large})
+	defer ctx.Check(pool.Close)
+
+	store := pieces.NewStore(log, pool, nil, nil, nil)
+	satelliteID := testidentity.MustPregeneratedSignedIdentity(0, storj.LatestIDVersion()).ID
+
+	write := func(pieceID storj.PieceID, data []byte) {
+		writer, err := store.Writer(ctx, satelliteID, pieceID)
+		require.NoError(t, err)
+		_, err = io.Copy(writer, bytes.NewReader(data))
+		require.NoError(t, err)
+		require.NoError(t, writer.Commit(ctx, &pb.PieceHeader{}))
+	}
+	read := func(pieceID storj.PieceID) []byte {
+		reader, err := store.Reader(ctx, satelliteID, pieceID)
+		require.NoError(t, err)
+		defer ctx.Check(reader.Close)
+		data, err := ioutil.ReadAll(reader)
+		require.NoError(t, err)
+		return data
+	}
+	ref := func(pieceID storj.PieceID) storage.BlobRef {
+		return storage.BlobRef{Namespace: satelliteID.Bytes(), Key: pieceID.Bytes()}
+	}
+	stored := func(disk pieces.PoolDisk, pieceID storj.PieceID) bool {
+		_, err := disk.Blobs.Stat(ctx, ref(pieceID))
+		return err == nil
+	}
+
+	// the large disk has more space available
+	pieceIDs := []storj.PieceID{testrand.PieceID(), testrand.PieceID(), testrand.PieceID()}
+	data := map[storj.PieceID][]byte{}
+	for _, pieceID := range pieceIDs {
+		data[pieceID] = testrand.Bytes(10 * memory.KiB)
+		write(pieceID, data[pieceID])
+		require.True(t, stored(large, pieceID))
+		require.False(t, stored(small, pieceID))
+	}
+
+	// after refreshing, the small disk has more space available than the
+	// large one has left
+	pool = pieces.NewBlobsPool(log, []pieces.PoolDisk{small, {Path: large.Path, Blobs: large.Blobs, Allocated: 40 * memory.KiB.Int64()}})
+	require.NoError(t, pool.Refresh(ctx))
+	store = pieces.NewStore(log, pool, nil, nil, nil)
+
+	// the free space of a disk is capped by its allocation
+	require.Equal(t, 60*memory.KiB.Int64(), pool.Allocated())
+	free, err := pool.FreeSpace()
+	require.NoError(t, err)
+	require.True(t, free <= 60*memory.KiB.Int64(), free)
+
+	onSmall := testrand.PieceID()
+	data[onSmall] = testrand.Bytes(5 * memory.KiB)
+	write(onSmall, data[onSmall])
+	require.True(t, stored(small, onSmall))
+	pieceIDs = append(pieceIDs, onSmall)
+
+	// reads and walking fan out to all disks
+	for _, pieceID := range pieceIDs {
+		require.Equal(t, data[pieceID], read(pieceID))
+	}
+
+	var walked []storj.PieceID
+	require.NoError(t, store.WalkSatellitePieces(ctx, satelliteID, func(access pieces.StoredPieceAccess) error {
+		walked = append(walked, access.PieceID())
+		return nil
+	}))
+	require.ElementsMatch(t, pieceIDs, walked)
+
+	used, err := pool.SpaceUsedForBlobs(ctx)
+	require.NoError(t, err)
+	require.True(t, used >= 35*memory.KiB.Int64(), used)
+
+	namespaces, err := pool.ListNamespaces(ctx)
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{satelliteID.Bytes()}, namespaces)
+
+	// trash is kept on the disk of the piece
+	require.NoError(t, pool.Trash(ctx, ref(onSmall)))
+	trashUsed, err := small.Blobs.SpaceUsedForTrash(ctx)
+	require.NoError(t, err)
+	require.NotZero(t, trashUsed)
+	restored, err := pool.RestoreTrash(ctx, satelliteID.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{onSmall.Bytes()}, restored)
+	require.Equal(t, data[onSmall], read(onSmall))
+
+	require.NoError(t, pool.Delete(ctx, ref(pieceIDs[0])))
+	require.False(t, stored(large, pieceIDs[0]))
+	_, err = store.Reader(ctx, satelliteID, pieceIDs[0])
+	require.Error(t, err)
+
+	// a disk which fails to write isn't used, but it's still read from
+	failing := pieces.PoolDisk{Path: large.Path, Blobs: failingBlobs{large.Blobs}}
+	pool = pieces.NewBlobsPool(log, []pieces.PoolDisk{failing, small})
+	store = pieces.NewStore(log, pool, nil, nil, nil)
+
+	onFallback := testrand.PieceID()
+	write(onFallback, testrand.Bytes(memory.KiB))
+	require.True(t, stored(small, onFallback))
+	require.Equal(t, data[pieceIDs[1]], read(pieceIDs[1]))
+
+	var writable []bool
+	for _, disk := range pool.Disks() {
+		writable = append(writable, disk.Writable)
+	}
+	require.Equal(t, []bool{false, true}, writable)
+
+	// the disk is used again once it's writable
+	require.NoError(t, pool.CheckDisks(ctx))
+	for _, disk := range pool.Disks() {
+		require.True(t, disk.Writable)
+	}
+}
@@ -322,6 +322,38 @@ func (store *Store) Trash(ctx context.Context, satellite storj.NodeID, pieceID s
 	return Error.Wrap(err)
 }
 
+// Quarantine moves the specified piece to the blob quarantine, where it's kept
+// for inspection. The piece is no longer served and its expiration and v0
+// piece info records are removed.
+func (store *Store) Quarantine(ctx context.Context, satellite storj.NodeID, pieceID storj.PieceID) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	err = store.blobs.Quarantine(ctx, storage.BlobRef{
+		Namespace: satellite.Bytes(),
+		Key:       pieceID.Bytes(),
+	})
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	if store.expirationInfo != nil {
+		_, err = store.expirationInfo.DeleteExpiration(ctx, satellite, pieceID)
+	}
+	if store.v0PieceInfo != nil {
+		err = errs.Combine(err, store.v0PieceInfo.Delete(ctx, satellite, pieceID))
+	}
+
+	return Error.Wrap(err)
+}
+
+// EmptyQuarantine deletes the quarantined pieces of the satellite which were
+// quarantined before quarantinedBefore. Their other records were already
+// removed when they were quarantined.
+func (store *Store) EmptyQuarantine(ctx context.Context, satelliteID storj.NodeID, quarantinedBefore time.Time) (bytesEmptied int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+	bytesEmptied, _, err = store.blobs.EmptyQuarantine(ctx, satelliteID.Bytes(), quarantinedBefore)
+	return bytesEmptied, Error.Wrap(err)
+}
+
 // EmptyTrash deletes pieces in the trash that have been in there longer than trashExpiryInterval
 func (store *Store) EmptyTrash(ctx context.Context, satelliteID storj.NodeID, trashedBefore time.Time) (err error) {
 	defer mon.Task()(&ctx)(&err)
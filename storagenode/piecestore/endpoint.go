@@ -27,11 +27,13 @@ import (
 	"storj.io/common/storj"
 	"storj.io/common/sync2"
 	"storj.io/storj/private/context2"
+	"storj.io/storj/storage/packstore"
 	"storj.io/storj/storagenode/bandwidth"
 	"storj.io/storj/storagenode/monitor"
 	"storj.io/storj/storagenode/orders"
 	"storj.io/storj/storagenode/pieces"
 	"storj.io/storj/storagenode/retain"
+	"storj.io/storj/storagenode/shaping"
 	"storj.io/storj/storagenode/trust"
 )
 
@@ -43,11 +45,24 @@ var _ pb.PiecestoreServer = (*Endpoint)(nil)
 
 // OldConfig contains everything necessary for a server
 type OldConfig struct {
-	Path                   string         `help:"path to store data in" default:"$CONFDIR/storage"`
-	WhitelistedSatellites  storj.NodeURLs `help:"a comma-separated list of approved satellite node urls (unused)" devDefault:"" releaseDefault:""`
-	AllocatedDiskSpace     memory.Size    `user:"true" help:"total allocated disk space in bytes" default:"1TB"`
-	AllocatedBandwidth     memory.Size    `user:"true" help:"total allocated bandwidth in bytes" default:"2TB"`
-	KBucketRefreshInterval time.Duration  `help:"how frequently Kademlia bucket should be refreshed with node stats" default:"1h0m0s"`
+	Path                   string             `help:"path to store data in" default:"$CONFDIR/storage"`
+	WhitelistedSatellites  storj.NodeURLs     `help:"a comma-separated list of approved satellite node urls (unused)" devDefault:"" releaseDefault:""`
+	AllocatedDiskSpace     memory.Size        `user:"true" help:"total allocated disk space in bytes" default:"1TB"`
+	AdditionalDisks        pieces.DiskConfigs `user:"true" help:"additional directories to store data in with the disk space allocated on each of them, e.g. /mnt/disk2=2TB,/mnt/disk3=4TB" default:""`
+	SatelliteQuotas        monitor.Quotas     `user:"true" help:"disk space and monthly bandwidth allocated to single trusted satellites, e.g. SATELLITEID=1TB:2TB,SATELLITEID=500GB" default:""`
+	AllocatedBandwidth     memory.Size        `user:"true" help:"total allocated bandwidth in bytes" default:"2TB"`
+	KBucketRefreshInterval time.Duration      `help:"how frequently Kademlia bucket should be refreshed with node stats" default:"1h0m0s"`
+	Packed                 packstore.Config
+}
+
+// TotalAllocatedDiskSpace returns the disk space allocated in the storage
+// directory and on the additional disks.
+func (config OldConfig) TotalAllocatedDiskSpace() memory.Size {
+	total := config.AllocatedDiskSpace
+	for _, disk := range config.AdditionalDisks {
+		total += disk.Allocated
+	}
+	return total
 }
 
 // Config defines parameters for piecestore endpoint.
@@ -56,6 +71,8 @@ type Config struct {
 	MaxConcurrentRequests  int           `help:"how many concurrent requests are allowed, before uploads are rejected. 0 represents unlimited." default:"0"`
 	OrderLimitGracePeriod  time.Duration `help:"how long after OrderLimit creation date are OrderLimits no longer accepted" default:"24h0m0s"`
 	CacheSyncInterval      time.Duration `help:"how often the space used cache is synced to persistent storage" releaseDefault:"1h0m0s" devDefault:"0h1m0s"`
+	DiskCheckInterval      time.Duration `help:"how often the disks are checked whether they are writable, when storing data on additional disks" releaseDefault:"5m0s" devDefault:"0h1m0s"`
+	PackCompactionInterval time.Duration `help:"how often the pack files are compacted, when small pieces are packed" releaseDefault:"24h0m0s" devDefault:"0h1m0s"`
 	StreamOperationTimeout time.Duration `help:"how long to spend waiting for a stream operation before canceling" default:"30m"`
 	RetainTimeBuffer       time.Duration `help:"allows for small differences in the satellite and storagenode clocks" default:"48h0m0s"`
 
@@ -63,6 +80,7 @@ type Config struct {
 
 	Monitor monitor.Config
 	Orders  orders.Config
+	Shaping shaping.Config
 }
 
 type pingStatsSource interface {
@@ -87,6 +105,7 @@ type Endpoint struct {
 	orders      orders.DB
 	usage       bandwidth.DB
 	usedSerials UsedSerials
+	shaper      *shaping.Shaper
 
 	// liveRequests tracks the total number of incoming rpc requests. For gRPC
 	// requests only, this number is compared to config.MaxConcurrentRequests
@@ -102,7 +121,7 @@ type drpcEndpoint struct{ *Endpoint }
 func (endpoint *Endpoint) DRPC() pb.DRPCPiecestoreServer { return &drpcEndpoint{Endpoint: endpoint} }
 
 // NewEndpoint creates a new piecestore endpoint.
-func NewEndpoint(log *zap.Logger, signer signing.Signer, trust *trust.Pool, monitor *monitor.Service, retain *retain.Service, pingStats pingStatsSource, store *pieces.Store, orders orders.DB, usage bandwidth.DB, usedSerials UsedSerials, config Config) (*Endpoint, error) {
+func NewEndpoint(log *zap.Logger, signer signing.Signer, trust *trust.Pool, monitor *monitor.Service, retain *retain.Service, pingStats pingStatsSource, store *pieces.Store, orders orders.DB, usage bandwidth.DB, usedSerials UsedSerials, shaper *shaping.Shaper, config Config) (*Endpoint, error) {
 	// If config.MaxConcurrentRequests is set we want to repsect it for grpc.
 	// However, if it is 0 (unlimited) we force a limit.
 	grpcReqLimit := config.MaxConcurrentRequests
@@ -125,6 +144,7 @@ func NewEndpoint(log *zap.Logger, signer signing.Signer, trust *trust.Pool, moni
 		orders:      orders,
 		usage:       usage,
 		usedSerials: usedSerials,
+		shaper:      shaper,
 
 		liveRequests: 0,
 	}, nil
@@ -311,12 +331,12 @@ func (endpoint *Endpoint) doUpload(stream uploadStream, requestLimit int) (err e
 		return err
 	}
 
-	availableBandwidth, err := endpoint.monitor.AvailableBandwidth(ctx)
+	availableBandwidth, err := endpoint.monitor.AvailableBandwidthForSatellite(ctx, limit.SatelliteId)
 	if err != nil {
 		return rpcstatus.Wrap(rpcstatus.Internal, err)
 	}
 
-	availableSpace, err := endpoint.monitor.AvailableSpace(ctx)
+	availableSpace, err := endpoint.monitor.AvailableSpaceForSatellite(ctx, limit.SatelliteId)
 	if err != nil {
 		return rpcstatus.Wrap(rpcstatus.Internal, err)
 	}
@@ -430,6 +450,10 @@ func (endpoint *Endpoint) doUpload(stream uploadStream, requestLimit int) (err e
 				return rpcstatus.Error(rpcstatus.Internal, "out of space")
 			}
 
+			if err := endpoint.shaper.Wait(ctx, shaping.Ingress, limit.Action, chunkSize); err != nil {
+				return rpcstatus.Wrap(rpcstatus.Canceled, err)
+			}
+
 			if _, err := pieceWriter.Write(message.Chunk.Data); err != nil {
 				return rpcstatus.Wrap(rpcstatus.Internal, err)
 			}
@@ -624,7 +648,7 @@ func (endpoint *Endpoint) doDownload(stream downloadStream) (err error) {
 			chunk.Offset+chunk.ChunkSize, pieceReader.Size())
 	}
 
-	availableBandwidth, err := endpoint.monitor.AvailableBandwidth(ctx)
+	availableBandwidth, err := endpoint.monitor.AvailableBandwidthForSatellite(ctx, limit.SatelliteId)
 	if err != nil {
 		endpoint.log.Error("error getting available bandwidth", zap.Error(err))
 		return rpcstatus.Wrap(rpcstatus.Internal, err)
@@ -649,6 +673,10 @@ func (endpoint *Endpoint) doDownload(stream downloadStream) (err error) {
 				return nil
 			}
 
+			if err := endpoint.shaper.Wait(ctx, shaping.Egress, limit.Action, chunkSize); err != nil {
+				return rpcstatus.Wrap(rpcstatus.Canceled, err)
+			}
+
 			chunkData := make([]byte, chunkSize)
 			_, err = pieceReader.Seek(currentOffset, io.SeekStart)
 			if err != nil {
@@ -0,0 +1,398 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package scrubber implements verifying the integrity of the pieces stored on
+// the storage node.
+package scrubber
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+
+	"storj.io/common/memory"
+	"storj.io/common/pkcrypto"
+	"storj.io/common/signing"
+	"storj.io/common/storj"
+	"storj.io/common/sync2"
+	"storj.io/storj/storage/filestore"
+	"storj.io/storj/storagenode/notifications"
+	"storj.io/storj/storagenode/pieces"
+	"storj.io/storj/storagenode/trust"
+)
+
+var (
+	// Error is the default error class for the scrubber.
+	Error = errs.Class("scrubber")
+
+	mon = monkit.Package()
+)
+
+// Config defines parameters for the piece scrubber.
+type Config struct {
+	Enabled  bool          `help:"whether stored pieces are periodically verified" default:"true"`
+	Interval time.Duration `help:"how frequently a pass over all stored pieces is started" default:"168h0m0s"`
+	ReadRate memory.Size   `help:"maximum amount of piece data read per second, 0 for unlimited" default:"4MiB"`
+
+	QuarantineRetention time.Duration `help:"how long quarantined pieces are kept for inspection before they're deleted, 0 keeps them forever" default:"720h0m0s"`
+}
+
+const (
+	// maxReadBurst is the largest amount of data read at once.
+	maxReadBurst = 256 * memory.KiB
+	// cursorInterval is how often the cursor is persisted during a pass.
+	cursorInterval = time.Minute
+	// verifyAttempts is how many times a piece which can't be read is tried
+	// before it's skipped until the next pass.
+	verifyAttempts = 3
+	// retryDelay is the time between attempts to read a piece.
+	retryDelay = time.Second
+)
+
+// Chore walks all stored pieces and verifies them against their piece hash
+// and order limit. Pieces which fail verification are quarantined.
+//
+// architecture: Chore
+type Chore struct {
+	log           *zap.Logger
+	store         *pieces.Store
+	trust         *trust.Pool
+	db            DB
+	notifications *notifications.Service
+	limiter       *rate.Limiter
+	retention     time.Duration
+
+	Loop *sync2.Cycle
+}
+
+// NewChore creates a new piece scrubber.
+func NewChore(log *zap.Logger, store *pieces.Store, trust *trust.Pool, db DB, notifications *notifications.Service, config Config) *Chore {
+	limiter := rate.NewLimiter(rate.Inf, maxReadBurst.Int())
+	if config.ReadRate > 0 {
+		burst := maxReadBurst
+		if config.ReadRate < burst {
+			burst = config.ReadRate
+		}
+		limiter = rate.NewLimiter(rate.Limit(config.ReadRate.Int64()), burst.Int())
+	}
+
+	return &Chore{
+		log:           log,
+		store:         store,
+		trust:         trust,
+		db:            db,
+		notifications: notifications,
+		limiter:       limiter,
+		retention:     config.QuarantineRetention,
+		Loop:          sync2.NewCycle(config.Interval),
+	}
+}
+
+// Run runs the scrubber.
+func (chore *Chore) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return chore.Loop.Run(ctx, func(ctx context.Context) error {
+		err := chore.Scrub(ctx)
+		if err != nil {
+			chore.log.Error("error during scrubbing pieces", zap.Error(err))
+		}
+		err = chore.EmptyQuarantine(ctx)
+		if err != nil {
+			chore.log.Error("error emptying the quarantine", zap.Error(err))
+		}
+		return nil
+	})
+}
+
+// Close stops the scrubber.
+func (chore *Chore) Close() error {
+	chore.Loop.Close()
+	return nil
+}
+
+// Scrub verifies the pieces of all trusted satellites. It continues from the
+// persisted cursor, so that an interrupted pass doesn't start over.
+func (chore *Chore) Scrub(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	cursor, err := chore.db.GetCursor(ctx)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	if cursor.IsZero() {
+		cursor.StartedAt = time.Now().UTC()
+	} else {
+		chore.log.Info("resuming scrub", zap.Stringer("Satellite ID", cursor.SatelliteID), zap.Stringer("Piece ID", cursor.PieceID))
+	}
+
+	satellites := chore.trust.GetSatellites(ctx)
+	sort.Slice(satellites, func(i, k int) bool {
+		return satellites[i].Less(satellites[k])
+	})
+
+	for _, satelliteID := range satellites {
+		if satelliteID.Less(cursor.SatelliteID) {
+			continue
+		}
+
+		var after storj.PieceID
+		if satelliteID == cursor.SatelliteID {
+			after = cursor.PieceID
+		}
+
+		if err := chore.scrubSatellite(ctx, satelliteID, after, cursor.StartedAt); err != nil {
+			return err
+		}
+	}
+
+	chore.log.Info("scrub finished", zap.Time("started", cursor.StartedAt))
+	return Error.Wrap(chore.db.SetCursor(ctx, Cursor{}))
+}
+
+// EmptyQuarantine deletes the pieces of the trusted satellites which have
+// been in the quarantine longer than the retention.
+func (chore *Chore) EmptyQuarantine(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if chore.retention <= 0 {
+		return nil
+	}
+
+	quarantinedBefore := time.Now().Add(-chore.retention)
+	var group errs.Group
+	for _, satelliteID := range chore.trust.GetSatellites(ctx) {
+		emptied, err := chore.store.EmptyQuarantine(ctx, satelliteID, quarantinedBefore)
+		if err != nil {
+			group.Add(err)
+			continue
+		}
+		if emptied > 0 {
+			chore.log.Info("emptied quarantine", zap.Stringer("Satellite ID", satelliteID), zap.Int64("bytes", emptied))
+		}
+	}
+	return Error.Wrap(group.Err())
+}
+
+// scrubSatellite verifies the pieces of the satellite stored after the
+// piece with the specified ID.
+func (chore *Chore) scrubSatellite(ctx context.Context, satelliteID storj.NodeID, after storj.PieceID, startedAt time.Time) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	signee, err := chore.trust.GetSignee(ctx, satelliteID)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		// the satellite is tried again in the next pass
+		chore.log.Error("unable to get signee, skipping satellite", zap.Stringer("Satellite ID", satelliteID), zap.Error(err))
+		return nil
+	}
+
+	var quarantined int
+	defer func() {
+		if quarantined > 0 {
+			chore.notify(ctx, satelliteID, quarantined)
+		}
+	}()
+
+	// the cursor is only persisted periodically, and when the walk stops, so
+	// that verifying a piece doesn't need a database write.
+	var verified storj.PieceID
+	lastSaved := time.Now()
+	saveCursor := func() error {
+		if verified.IsZero() {
+			return nil
+		}
+		lastSaved = time.Now()
+		return Error.Wrap(chore.db.SetCursor(ctx, Cursor{
+			SatelliteID: satelliteID,
+			PieceID:     verified,
+			StartedAt:   startedAt,
+		}))
+	}
+	defer func() { err = errs.Combine(err, saveCursor()) }()
+
+	// pieces are walked in the same order as long as the directories don't
+	// change, so the pieces up to the cursor are skipped without reading them.
+	skipping := !after.IsZero()
+	walk := func(access pieces.StoredPieceAccess) error {
+		pieceID := access.PieceID()
+		if skipping {
+			skipping = pieceID != after
+			return nil
+		}
+
+		reason, err := chore.verifyWithRetries(ctx, signee, satelliteID, pieceID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			// the piece may be readable later, so it's only skipped until the
+			// next pass.
+			chore.log.Warn("unable to read piece, skipping it", zap.Stringer("Satellite ID", satelliteID), zap.Stringer("Piece ID", pieceID), zap.Error(err))
+			mon.Meter("scrub_read_failures").Mark(1) //locked
+		}
+		if reason != "" {
+			if err := chore.quarantine(ctx, satelliteID, pieceID, reason); err != nil {
+				return err
+			}
+			quarantined++
+		}
+
+		verified = pieceID
+		if time.Since(lastSaved) >= cursorInterval {
+			return saveCursor()
+		}
+		return nil
+	}
+
+	err = chore.store.WalkSatellitePieces(ctx, satelliteID, walk)
+	if err != nil || !skipping {
+		return err
+	}
+
+	// the piece at the cursor has been deleted in the meantime, so the
+	// satellite is verified from the beginning.
+	chore.log.Info("scrub cursor not found, starting satellite over", zap.Stringer("Satellite ID", satelliteID))
+	return chore.store.WalkSatellitePieces(ctx, satelliteID, walk)
+}
+
+// verifyWithRetries verifies the piece, trying again when it can't be read.
+func (chore *Chore) verifyWithRetries(ctx context.Context, signee signing.Signee, satelliteID storj.NodeID, pieceID storj.PieceID) (reason string, err error) {
+	for attempt := 1; ; attempt++ {
+		reason, err = chore.verify(ctx, signee, satelliteID, pieceID)
+		if err == nil || attempt >= verifyAttempts || ctx.Err() != nil {
+			return reason, err
+		}
+		if !sync2.Sleep(ctx, retryDelay) {
+			return "", ctx.Err()
+		}
+	}
+}
+
+// verify reads the piece and checks it against the piece hash and order
+// limit in its header. It returns the reason why the piece is corrupt, or an
+// empty string when the piece is fine. Failures to read the piece, which may
+// be transient, are returned as errors and don't make the piece corrupt.
+func (chore *Chore) verify(ctx context.Context, signee signing.Signee, satelliteID storj.NodeID, pieceID storj.PieceID) (reason string, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	reader, err := chore.store.Reader(ctx, satelliteID, pieceID)
+	if err != nil {
+		if errs.Is(err, os.ErrNotExist) {
+			// the piece has been deleted in the meantime
+			return "", nil
+		}
+		return "", Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, reader.Close()) }()
+
+	hash, limit, err := chore.store.GetHashAndLimit(ctx, satelliteID, pieceID, reader)
+	if err != nil {
+		if isReadFailure(err) || reader.StorageFormatVersion() < filestore.FormatV1 {
+			// the header of v0 pieces is stored in the database
+			return "", Error.Wrap(err)
+		}
+		return fmt.Sprintf("unable to read piece header: %v", err), nil
+	}
+
+	if limit.SatelliteId != satelliteID || limit.PieceId != pieceID {
+		return "order limit doesn't match the piece", nil
+	}
+	if err := signing.VerifyOrderLimitSignature(ctx, signee, &limit); err != nil {
+		return "invalid order limit signature", nil
+	}
+	if err := signing.VerifyUplinkPieceHashSignature(ctx, limit.UplinkPublicKey, &hash); err != nil {
+		return "invalid piece hash signature", nil
+	}
+
+	h := pkcrypto.NewHash()
+	n, err := io.Copy(h, &limitedReader{ctx: ctx, limiter: chore.limiter, reader: reader})
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		if isReadFailure(err) {
+			return "", Error.Wrap(err)
+		}
+		return fmt.Sprintf("unable to read piece: %v", err), nil
+	}
+	mon.IntVal("scrubbed_bytes").Observe(n) //locked
+
+	if n != hash.PieceSize {
+		return fmt.Sprintf("piece size %d doesn't match the piece hash size %d", n, hash.PieceSize), nil
+	}
+	if !bytes.Equal(h.Sum(nil), hash.Hash) {
+		return "piece content doesn't match the piece hash", nil
+	}
+	return "", nil
+}
+
+// isReadFailure returns whether the error comes from the file system rather
+// than from the content of the piece.
+func isReadFailure(err error) bool {
+	var pathErr *os.PathError
+	return errors.As(err, &pathErr)
+}
+
+// quarantine moves the piece to the quarantine and records it.
+func (chore *Chore) quarantine(ctx context.Context, satelliteID storj.NodeID, pieceID storj.PieceID, reason string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	chore.log.Warn("quarantining corrupt piece", zap.Stringer("Satellite ID", satelliteID), zap.Stringer("Piece ID", pieceID), zap.String("reason", reason))
+	mon.Meter("quarantined_pieces").Mark(1) //locked
+
+	if err := chore.store.Quarantine(ctx, satelliteID, pieceID); err != nil {
+		return Error.Wrap(err)
+	}
+
+	return Error.Wrap(chore.db.Quarantine(ctx, QuarantinedPiece{
+		SatelliteID:   satelliteID,
+		PieceID:       pieceID,
+		Reason:        reason,
+		QuarantinedAt: time.Now().UTC(),
+	}))
+}
+
+// notify tells the operator about the pieces quarantined for the satellite.
+func (chore *Chore) notify(ctx context.Context, satelliteID storj.NodeID, quarantined int) {
+	_, err := chore.notifications.Receive(ctx, notifications.NewNotification{
+		SenderID: satelliteID,
+		Type:     notifications.TypeCorruptPiece,
+		Title:    "Corrupt pieces found",
+		Message:  fmt.Sprintf("%d pieces failed the integrity check and were quarantined. Please check the health of your disks.", quarantined),
+	})
+	if err != nil {
+		chore.log.Error("unable to insert notification", zap.Stringer("Satellite ID", satelliteID), zap.Error(err))
+	}
+}
+
+// limitedReader limits the rate at which data is read.
+type limitedReader struct {
+	ctx     context.Context
+	limiter *rate.Limiter
+	reader  io.Reader
+}
+
+// Read waits until the limiter allows reading and reads at most the burst
+// size of the limiter.
+func (reader *limitedReader) Read(p []byte) (int, error) {
+	if burst := reader.limiter.Burst(); len(p) > burst {
+		p = p[:burst]
+	}
+	if err := reader.limiter.WaitN(reader.ctx, len(p)); err != nil {
+		return 0, err
+	}
+	return reader.reader.Read(p)
+}
@@ -0,0 +1,50 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package scrubber
+
+import (
+	"context"
+	"time"
+
+	"storj.io/common/storj"
+)
+
+// DB stores the scrubber progress and the quarantined pieces.
+//
+// architecture: Database
+type DB interface {
+	// GetCursor returns the last verified piece. It returns a zero cursor when
+	// no pass is in progress.
+	GetCursor(ctx context.Context) (Cursor, error)
+	// SetCursor stores the last verified piece.
+	SetCursor(ctx context.Context, cursor Cursor) error
+	// Quarantine records a piece which failed verification.
+	Quarantine(ctx context.Context, piece QuarantinedPiece) error
+	// ListQuarantined returns the most recently quarantined pieces.
+	ListQuarantined(ctx context.Context, limit int) ([]QuarantinedPiece, error)
+	// CountQuarantined returns the number of quarantined pieces per satellite.
+	CountQuarantined(ctx context.Context) (map[storj.NodeID]int64, error)
+}
+
+// Cursor is the position of the scrubber within a pass over all pieces.
+type Cursor struct {
+	SatelliteID storj.NodeID
+	PieceID     storj.PieceID
+	// StartedAt is when the pass started.
+	StartedAt time.Time
+}
+
+// IsZero returns whether the cursor is at the beginning of a pass.
+func (cursor Cursor) IsZero() bool {
+	return cursor.SatelliteID.IsZero() && cursor.PieceID.IsZero()
+}
+
+// QuarantinedPiece is a piece which failed verification and was moved to
+// the quarantine.
+type QuarantinedPiece struct {
+	SatelliteID   storj.NodeID  `json:"satelliteId"`
+	PieceID       storj.PieceID `json:"pieceId"`
+	Reason        string        `json:"reason"`
+	QuarantinedAt time.Time     `json:"quarantinedAt"`
+}
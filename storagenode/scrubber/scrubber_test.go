@@ -0,0 +1,185 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package scrubber_test
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/identity/testidentity"
+	"storj.io/common/memory"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/private/testplanet"
+	"storj.io/storj/storage"
+	"storj.io/storj/storagenode"
+	"storj.io/storj/storagenode/notifications"
+	"storj.io/storj/storagenode/pieces"
+	"storj.io/storj/storagenode/scrubber"
+	"storj.io/storj/storagenode/storagenodedb/storagenodedbtest"
+)
+
+func TestDB(t *testing.T) {
+	storagenodedbtest.Run(t, func(ctx *testcontext.Context, t *testing.T, db storagenode.DB) {
+		scrubberDB := db.Scrubber()
+
+		satellite0 := testidentity.MustPregeneratedSignedIdentity(0, storj.LatestIDVersion()).ID
+		satellite1 := testidentity.MustPregeneratedSignedIdentity(1, storj.LatestIDVersion()).ID
+
+		cursor, err := scrubberDB.GetCursor(ctx)
+		require.NoError(t, err)
+		require.True(t, cursor.IsZero())
+
+		expected := scrubber.Cursor{
+			SatelliteID: satellite0,
+			PieceID:     testrand.PieceID(),
+			StartedAt:   time.Now().UTC().Truncate(time.Second),
+		}
+		require.NoError(t, scrubberDB.SetCursor(ctx, expected))
+		expected.PieceID = testrand.PieceID()
+		require.NoError(t, scrubberDB.SetCursor(ctx, expected))
+
+		cursor, err = scrubberDB.GetCursor(ctx)
+		require.NoError(t, err)
+		require.Equal(t, expected.SatelliteID, cursor.SatelliteID)
+		require.Equal(t, expected.PieceID, cursor.PieceID)
+		require.True(t, expected.StartedAt.Equal(cursor.StartedAt))
+
+		require.NoError(t, scrubberDB.SetCursor(ctx, scrubber.Cursor{}))
+		cursor, err = scrubberDB.GetCursor(ctx)
+		require.NoError(t, err)
+		require.True(t, cursor.IsZero())
+
+		now := time.Now().UTC()
+		older := scrubber.QuarantinedPiece{
+			SatelliteID:   satellite0,
+			PieceID:       testrand.PieceID(),
+			Reason:        "piece content doesn't match the piece hash",
+			QuarantinedAt: now.Add(-time.Hour),
+		}
+		newer := scrubber.QuarantinedPiece{
+			SatelliteID:   satellite1,
+			PieceID:       testrand.PieceID(),
+			Reason:        "invalid piece hash signature",
+			QuarantinedAt: now,
+		}
+		require.NoError(t, scrubberDB.Quarantine(ctx, older))
+		require.NoError(t, scrubberDB.Quarantine(ctx, newer))
+
+		quarantined, err := scrubberDB.ListQuarantined(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, quarantined, 2)
+		require.Equal(t, newer.PieceID, quarantined[0].PieceID)
+		require.Equal(t, newer.Reason, quarantined[0].Reason)
+		require.Equal(t, older.PieceID, quarantined[1].PieceID)
+
+		quarantined, err = scrubberDB.ListQuarantined(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, quarantined, 1)
+
+		counts, err := scrubberDB.CountQuarantined(ctx)
+		require.NoError(t, err)
+		require.Equal(t, map[storj.NodeID]int64{satellite0: 1, satellite1: 1}, counts)
+	})
+}
+
+func TestChore(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+		Reconfigure: testplanet.Reconfigure{
+			StorageNode: func(index int, config *storagenode.Config) {
+				config.Scrubber.Enabled = true
+				config.Scrubber.ReadRate = memory.MiB
+			},
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		node := planet.StorageNodes[0]
+		node.Scrubber.Loop.Pause()
+
+		for i := 0; i < 5; i++ {
+			err := planet.Uplinks[0].Upload(ctx, satellite, "testbucket", testrand.Path(), testrand.Bytes(10*memory.KiB))
+			require.NoError(t, err)
+		}
+
+		var pieceIDs []storj.PieceID
+		err := node.Storage2.Store.WalkSatellitePieces(ctx, satellite.ID(), func(access pieces.StoredPieceAccess) error {
+			pieceIDs = append(pieceIDs, access.PieceID())
+			return nil
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, pieceIDs)
+
+		// corrupt the content of a piece, keeping its header
+		corrupted := pieceIDs[0]
+		blobRef := storage.BlobRef{
+			Namespace: satellite.ID().Bytes(),
+			Key:       corrupted.Bytes(),
+		}
+		reader, err := node.Storage2.BlobsCache.Open(ctx, blobRef)
+		require.NoError(t, err)
+		pieceSize, err := reader.Size()
+		require.NoError(t, err)
+		pieceData := make([]byte, pieceSize)
+		_, err = io.ReadFull(reader, pieceData)
+		require.NoError(t, err)
+		require.NoError(t, reader.Close())
+		require.NoError(t, node.Storage2.BlobsCache.Delete(ctx, blobRef))
+
+		pieceData[pieceSize-1]++
+		writer, err := node.Storage2.BlobsCache.Create(ctx, blobRef, pieceSize)
+		require.NoError(t, err)
+		_, err = writer.Write(pieceData)
+		require.NoError(t, err)
+		require.NoError(t, writer.Commit(ctx))
+
+		node.Scrubber.Loop.TriggerWait()
+
+		// only the corrupted piece is quarantined
+		_, err = node.Storage2.Store.Reader(ctx, satellite.ID(), corrupted)
+		require.Error(t, err)
+		for _, pieceID := range pieceIDs[1:] {
+			reader, err := node.Storage2.Store.Reader(ctx, satellite.ID(), pieceID)
+			require.NoError(t, err)
+			require.NoError(t, reader.Close())
+		}
+
+		quarantined, err := node.DB.Scrubber().ListQuarantined(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, quarantined, 1)
+		require.Equal(t, corrupted, quarantined[0].PieceID)
+		require.Equal(t, satellite.ID(), quarantined[0].SatelliteID)
+		require.Equal(t, "piece content doesn't match the piece hash", quarantined[0].Reason)
+
+		page, err := node.Notifications.Service.List(ctx, notifications.Cursor{Limit: 10, Page: 1})
+		require.NoError(t, err)
+		require.Len(t, page.Notifications, 1)
+		require.Equal(t, notifications.TypeCorruptPiece, page.Notifications[0].Type)
+
+		// the pass is finished
+		cursor, err := node.DB.Scrubber().GetCursor(ctx)
+		require.NoError(t, err)
+		require.True(t, cursor.IsZero())
+
+		// an interrupted pass continues after the cursor
+		require.NoError(t, node.DB.Scrubber().SetCursor(ctx, scrubber.Cursor{
+			SatelliteID: satellite.ID(),
+			PieceID:     pieceIDs[len(pieceIDs)-1],
+			StartedAt:   time.Now(),
+		}))
+		node.Scrubber.Loop.TriggerWait()
+
+		cursor, err = node.DB.Scrubber().GetCursor(ctx)
+		require.NoError(t, err)
+		require.True(t, cursor.IsZero())
+
+		dashboard, err := node.Console.Service.GetDashboardData(ctx)
+		require.NoError(t, err)
+		require.EqualValues(t, 1, dashboard.QuarantinedPieces)
+	})
+}
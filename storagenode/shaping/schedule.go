@@ -0,0 +1,124 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package shaping
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"storj.io/common/memory"
+	"storj.io/storj/private/flaglist"
+)
+
+// Rule limits the rate during a time of day. A rule whose start equals its
+// end applies all day. The time of day may wrap around midnight, e.g.
+// 22:00-06:00.
+type Rule struct {
+	// Rate is the amount of data allowed per second.
+	Rate memory.Size
+	// Start and End are offsets since midnight.
+	Start time.Duration
+	End   time.Duration
+}
+
+// AllDay returns whether the rule applies all day.
+func (rule Rule) AllDay() bool { return rule.Start == rule.End }
+
+// Matches returns whether the rule applies at the time of day.
+func (rule Rule) Matches(t time.Time) bool {
+	if rule.AllDay() {
+		return true
+	}
+	offset := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+	if rule.Start < rule.End {
+		return rule.Start <= offset && offset < rule.End
+	}
+	return rule.Start <= offset || offset < rule.End
+}
+
+// String returns the rule as RATE[@HH:MM-HH:MM].
+func (rule Rule) String() string {
+	if rule.AllDay() {
+		return rule.Rate.String()
+	}
+	return rule.Rate.String() + "@" + formatTimeOfDay(rule.Start) + "-" + formatTimeOfDay(rule.End)
+}
+
+// Schedule is a list of rate limits. It's configured as a comma separated
+// list of RATE[@HH:MM-HH:MM] rules, e.g. "10MB@08:00-23:00,50MB". The first
+// rule matching the local time of day applies, and there is no limit when no
+// rule matches.
+type Schedule []Rule
+
+// RateAt returns the rate at the time, or false when it isn't limited.
+func (schedule Schedule) RateAt(t time.Time) (memory.Size, bool) {
+	for _, rule := range schedule {
+		if rule.Matches(t) {
+			return rule.Rate, true
+		}
+	}
+	return 0, false
+}
+
+// String implements pflag.Value.
+func (schedule Schedule) String() string {
+	values := make([]string, 0, len(schedule))
+	for _, rule := range schedule {
+		values = append(values, rule.String())
+	}
+	return strings.Join(values, ",")
+}
+
+// Set implements pflag.Value.
+func (schedule *Schedule) Set(s string) error {
+	*schedule = nil
+	return flaglist.Parse(s, func(value string) error {
+		rate, window, ok := flaglist.Split(value, "@")
+		if !ok {
+			rate = value
+		}
+
+		var rule Rule
+		var err error
+		if rule.Rate, err = flaglist.Size(rate); err != nil {
+			return Error.New("invalid rule %q, rate: %v", value, err)
+		}
+		if rule.Rate <= 0 {
+			return Error.New("invalid rule %q, rate must be positive", value)
+		}
+
+		if ok {
+			start, end, ok := flaglist.Split(window, "-")
+			if !ok || strings.Contains(end, "-") {
+				return Error.New("invalid rule %q, expected RATE@HH:MM-HH:MM", value)
+			}
+			if rule.Start, err = parseTimeOfDay(start); err != nil {
+				return Error.New("invalid rule %q: %v", value, err)
+			}
+			if rule.End, err = parseTimeOfDay(end); err != nil {
+				return Error.New("invalid rule %q: %v", value, err)
+			}
+		}
+		*schedule = append(*schedule, rule)
+		return nil
+	})
+}
+
+// Type implements pflag.Value.
+func (*Schedule) Type() string { return "shaping.Schedule" }
+
+// parseTimeOfDay parses HH:MM into an offset since midnight.
+func parseTimeOfDay(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// formatTimeOfDay formats an offset since midnight as HH:MM.
+func formatTimeOfDay(offset time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(offset/time.Hour), int(offset%time.Hour/time.Minute))
+}
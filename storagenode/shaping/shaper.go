@@ -0,0 +1,284 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package shaping implements limiting the rate of the piece data the storage
+// node receives and sends.
+package shaping
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+
+	"storj.io/common/memory"
+	"storj.io/common/pb"
+	"storj.io/common/sync2"
+)
+
+var (
+	// Error is the default error class for bandwidth shaping.
+	Error = errs.Class("shaping")
+
+	mon = monkit.Package()
+)
+
+// Config defines parameters for bandwidth shaping.
+type Config struct {
+	Ingress        Schedule      `user:"true" help:"rate limits of the data received, e.g. 10MB@08:00-23:00,50MB, no limit when empty" default:""`
+	Egress         Schedule      `user:"true" help:"rate limits of the data sent, e.g. 10MB@08:00-23:00,50MB, no limit when empty" default:""`
+	ProtectedShare float64       `help:"share of a limited rate which is reserved for audit and repair traffic" default:"0.2"`
+	Burst          memory.Size   `help:"largest amount of data transferred at once when the rate is limited" default:"256KiB"`
+	Interval       time.Duration `help:"how often the schedules are applied" default:"1m0s"`
+}
+
+// Direction is the direction of the data.
+type Direction int
+
+const (
+	// Ingress is the data received by the storage node.
+	Ingress Direction = iota
+	// Egress is the data sent by the storage node.
+	Egress
+)
+
+// String returns the name of the direction.
+func (direction Direction) String() string {
+	if direction == Ingress {
+		return "ingress"
+	}
+	return "egress"
+}
+
+// Protected returns whether the traffic of the action uses the protected
+// share of the rate. Audits and repairs affect the reputation of the node, so
+// they shouldn't be starved by customer traffic.
+func Protected(action pb.PieceAction) bool {
+	switch action {
+	case pb.PieceAction_GET_AUDIT, pb.PieceAction_GET_REPAIR, pb.PieceAction_PUT_REPAIR:
+		return true
+	default:
+		return false
+	}
+}
+
+// Shaper limits the rate of the piece data with token buckets. The rates
+// follow the schedules, which can be changed while the node is running.
+//
+// architecture: Service
+type Shaper struct {
+	log    *zap.Logger
+	config Config
+
+	mu        sync.Mutex
+	schedules [2]Schedule
+	limiters  [2]*limiter
+
+	Loop *sync2.Cycle
+}
+
+// NewShaper creates a new bandwidth shaper with the configured schedules.
+func NewShaper(log *zap.Logger, config Config) (*Shaper, error) {
+	if config.ProtectedShare < 0 || config.ProtectedShare >= 1 {
+		return nil, Error.New("protected share must be at least 0 and less than 1, got %v", config.ProtectedShare)
+	}
+	if config.Burst <= 0 {
+		return nil, Error.New("burst must be positive, got %v", config.Burst)
+	}
+
+	shaper := &Shaper{
+		log:       log,
+		config:    config,
+		schedules: [2]Schedule{config.Ingress, config.Egress},
+		limiters:  [2]*limiter{newLimiter(config.Burst.Int()), newLimiter(config.Burst.Int())},
+		Loop:      sync2.NewCycle(config.Interval),
+	}
+	shaper.Apply(time.Now())
+	return shaper, nil
+}
+
+// Run applies the schedules on an interval, so that the rates follow the
+// time of day.
+func (shaper *Shaper) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return shaper.Loop.Run(ctx, func(ctx context.Context) error {
+		shaper.Apply(time.Now())
+		return nil
+	})
+}
+
+// Close stops applying the schedules.
+func (shaper *Shaper) Close() error {
+	shaper.Loop.Close()
+	return nil
+}
+
+// Apply sets the rates of the schedules at the time.
+func (shaper *Shaper) Apply(now time.Time) {
+	shaper.mu.Lock()
+	defer shaper.mu.Unlock()
+
+	for direction, schedule := range shaper.schedules {
+		limit, limited := schedule.RateAt(now)
+		if !limited {
+			limit = 0
+		}
+		if shaper.limiters[direction].setRate(limit, shaper.config.ProtectedShare) {
+			shaper.log.Info("bandwidth limit changed",
+				zap.Stringer("direction", Direction(direction)),
+				zap.Stringer("rate", limit))
+		}
+	}
+}
+
+// Schedules returns the current schedules.
+func (shaper *Shaper) Schedules() (ingress, egress Schedule) {
+	shaper.mu.Lock()
+	defer shaper.mu.Unlock()
+	return shaper.schedules[Ingress], shaper.schedules[Egress]
+}
+
+// SetSchedules replaces the schedules and applies them immediately. The
+// change isn't persisted, the configured schedules are used after a restart.
+func (shaper *Shaper) SetSchedules(ingress, egress Schedule) {
+	shaper.mu.Lock()
+	shaper.schedules = [2]Schedule{ingress, egress}
+	shaper.mu.Unlock()
+
+	shaper.Apply(time.Now())
+}
+
+// Rates returns the current rates, 0 when a direction isn't limited.
+func (shaper *Shaper) Rates() (ingress, egress memory.Size) {
+	return shaper.limiters[Ingress].rate(), shaper.limiters[Egress].rate()
+}
+
+// Wait blocks until n bytes of the action's data may be transferred in the
+// direction.
+func (shaper *Shaper) Wait(ctx context.Context, direction Direction, action pb.PieceAction, n int64) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return shaper.limiters[direction].wait(ctx, Protected(action), n)
+}
+
+// limiter is a token bucket whose rate is split between the normal and the
+// protected traffic. Each kind of traffic may use the unused share of the
+// other one as well, so that the whole rate is used when only one of them is
+// transferring data.
+type limiter struct {
+	burst int
+
+	mu        sync.Mutex
+	limit     memory.Size
+	normal    *rate.Limiter
+	protected *rate.Limiter
+}
+
+func newLimiter(burst int) *limiter {
+	return &limiter{
+		burst:     burst,
+		normal:    rate.NewLimiter(rate.Inf, burst),
+		protected: rate.NewLimiter(rate.Inf, burst),
+	}
+}
+
+// setRate sets the rate, 0 for unlimited, and returns whether it changed.
+func (limiter *limiter) setRate(limit memory.Size, protectedShare float64) bool {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	if limit == limiter.limit {
+		return false
+	}
+	limiter.limit = limit
+
+	if limit <= 0 {
+		limiter.normal.SetLimit(rate.Inf)
+		limiter.protected.SetLimit(rate.Inf)
+		return true
+	}
+	limiter.normal.SetLimit(rate.Limit(float64(limit) * (1 - protectedShare)))
+	limiter.protected.SetLimit(rate.Limit(float64(limit) * protectedShare))
+	return true
+}
+
+// rate returns the current rate, 0 when unlimited.
+func (limiter *limiter) rate() memory.Size {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	return limiter.limit
+}
+
+// wait blocks until n bytes may be transferred.
+func (limiter *limiter) wait(ctx context.Context, protected bool, n int64) error {
+	for n > 0 {
+		part := n
+		if part > int64(limiter.burst) {
+			part = int64(limiter.burst)
+		}
+
+		var err error
+		switch {
+		case !protected:
+			err = limiter.waitNormal(ctx, int(part))
+		case limiter.normal.AllowN(time.Now(), int(part)):
+		case limiter.protected.Limit() == 0:
+			// no rate is reserved, so the protected traffic shares the
+			// normal rate
+			err = limiter.normal.WaitN(ctx, int(part))
+		default:
+			err = limiter.protected.WaitN(ctx, int(part))
+		}
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		n -= part
+	}
+	return nil
+}
+
+// waitNormal blocks until n bytes of normal traffic may be transferred. The
+// tokens of the protected share are used when protected traffic doesn't use
+// them, since waiting protected traffic reserves them.
+func (limiter *limiter) waitNormal(ctx context.Context, n int) error {
+	retried := false
+	for {
+		now := time.Now()
+		protectedLimit := limiter.protected.Limit()
+		if protectedLimit > 0 && limiter.protected.AllowN(now, n) {
+			return nil
+		}
+
+		reservation := limiter.normal.ReserveN(now, n)
+		if !reservation.OK() {
+			return Error.New("unable to wait for %d bytes", n)
+		}
+		delay := reservation.DelayFrom(now)
+		if delay == 0 {
+			return nil
+		}
+
+		// when the protected share refills before the normal one, it's
+		// tried once more before waiting for the normal share
+		if !retried && protectedLimit > 0 && protectedLimit != rate.Inf {
+			refill := time.Duration(float64(n) / float64(protectedLimit) * float64(time.Second))
+			if refill < delay {
+				reservation.CancelAt(now)
+				retried = true
+				if !sync2.Sleep(ctx, refill) {
+					return ctx.Err()
+				}
+				continue
+			}
+		}
+
+		if !sync2.Sleep(ctx, delay) {
+			reservation.Cancel()
+			return ctx.Err()
+		}
+		return nil
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package shaping_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"storj.io/common/memory"
+	"storj.io/common/pb"
+	"storj.io/common/testcontext"
+	"storj.io/storj/storagenode/shaping"
+)
+
+func TestSchedule(t *testing.T) {
+	var schedule shaping.Schedule
+	require.NoError(t, schedule.Set("10MB@08:00-23:00, 2MB@23:00-01:30,50MB"))
+	require.Equal(t, shaping.Schedule{
+		{Rate: 10 * memory.MB, Start: 8 * time.Hour, End: 23 * time.Hour},
+		{Rate: 2 * memory.MB, Start: 23 * time.Hour, End: time.Hour + 30*time.Minute},
+		{Rate: 50 * memory.MB},
+	}, schedule)
+	require.Equal(t, "10.0 MB@08:00-23:00,2.0 MB@23:00-01:30,50.0 MB", schedule.String())
+
+	at := func(hour, minute int) time.Time {
+		return time.Date(2020, 1, 1, hour, minute, 0, 0, time.Local)
+	}
+	for _, test := range []struct {
+		time time.Time
+		rate memory.Size
+	}{
+		{at(8, 0), 10 * memory.MB},
+		{at(22, 59), 10 * memory.MB},
+		{at(23, 0), 2 * memory.MB},
+		{at(0, 0), 2 * memory.MB},
+		{at(1, 29), 2 * memory.MB},
+		{at(1, 30), 50 * memory.MB},
+		{at(7, 59), 50 * memory.MB},
+	} {
+		rate, limited := schedule.RateAt(test.time)
+		require.True(t, limited)
+		require.Equal(t, test.rate, rate, test.time)
+	}
+
+	require.NoError(t, schedule.Set("1MB@08:00-09:00"))
+	_, limited := schedule.RateAt(at(10, 0))
+	require.False(t, limited)
+
+	require.NoError(t, schedule.Set(""))
+	require.Empty(t, schedule)
+
+	require.Error(t, schedule.Set("fast"))
+	require.Error(t, schedule.Set("0MB"))
+	require.Error(t, schedule.Set("1MB@08:00"))
+	require.Error(t, schedule.Set("1MB@08:00-25:00"))
+}
+
+func TestShaper(t *testing.T) {
+	ctx := testcontext.New(t)
+	defer ctx.Cleanup()
+
+	_, err := shaping.NewShaper(zaptest.NewLogger(t), shaping.Config{ProtectedShare: 1, Burst: memory.KiB})
+	require.Error(t, err)
+
+	shaper, err := shaping.NewShaper(zaptest.NewLogger(t), shaping.Config{
+		ProtectedShare: 0.5,
+		Burst:          memory.KiB,
+		Interval:       time.Hour,
+	})
+	require.NoError(t, err)
+
+	// unlimited by default
+	ingress, egress := shaper.Rates()
+	require.Zero(t, ingress)
+	require.Zero(t, egress)
+	require.NoError(t, shaper.Wait(ctx, shaping.Egress, pb.PieceAction_GET, memory.GiB.Int64()))
+
+	var limit shaping.Schedule
+	require.NoError(t, limit.Set("10KiB"))
+	shaper.SetSchedules(nil, limit)
+	ingress, egress = shaper.Rates()
+	require.Zero(t, ingress)
+	require.Equal(t, 10*memory.KiB, egress)
+
+	// the ingress isn't limited
+	require.NoError(t, shaper.Wait(ctx, shaping.Ingress, pb.PieceAction_PUT, memory.GiB.Int64()))
+
+	// normal downloads get half of the egress rate and the unused protected
+	// share, which takes about a second for 10KiB after the bursts are used
+	timeout, cancel := context.WithTimeout(ctx, 500*time.Millisecond)
+	defer cancel()
+	require.Error(t, shaper.Wait(timeout, shaping.Egress, pb.PieceAction_GET, 10*memory.KiB.Int64()))
+
+	// audits and repairs still have their reserved share
+	for _, action := range []pb.PieceAction{pb.PieceAction_GET_AUDIT, pb.PieceAction_GET_REPAIR} {
+		timeout, cancel := context.WithTimeout(ctx, 5*time.Second)
+		require.NoError(t, shaper.Wait(timeout, shaping.Egress, action, memory.KiB.Int64()))
+		cancel()
+	}
+
+	// normal traffic uses the unused protected share, 10KiB would take about
+	// 10 seconds with only the normal share
+	mostlyProtected, err := shaping.NewShaper(zaptest.NewLogger(t), shaping.Config{
+		ProtectedShare: 0.9,
+		Burst:          memory.KiB,
+		Interval:       time.Hour,
+		Egress:         shaping.Schedule{{Rate: 10 * memory.KiB}},
+	})
+	require.NoError(t, err)
+	timeout, cancel = context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	require.NoError(t, mostlyProtected.Wait(timeout, shaping.Egress, pb.PieceAction_GET, 10*memory.KiB.Int64()))
+
+	// the schedules are applied for the time of day
+	require.NoError(t, limit.Set("1MB@00:00-12:00,2MB"))
+	shaper.SetSchedules(limit, limit)
+	shaper.Apply(time.Date(2020, 1, 1, 6, 0, 0, 0, time.Local))
+	ingress, egress = shaper.Rates()
+	require.Equal(t, memory.MB, ingress)
+	require.Equal(t, memory.MB, egress)
+	shaper.Apply(time.Date(2020, 1, 1, 18, 0, 0, 0, time.Local))
+	ingress, _ = shaper.Rates()
+	require.Equal(t, 2*memory.MB, ingress)
+
+	shaper.SetSchedules(nil, nil)
+	ingress, egress = shaper.Rates()
+	require.Zero(t, ingress)
+	require.Zero(t, egress)
+}
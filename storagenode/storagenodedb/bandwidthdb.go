@@ -286,6 +286,31 @@ func (db *bandwidthDB) SummaryBySatellite(ctx context.Context, from, to time.Tim
 	return entries, ErrBandwidth.Wrap(rows.Err())
 }
 
+// SatelliteFirstUsage returns when bandwidth was first used for a particular satellite.
+func (db *bandwidthDB) SatelliteFirstUsage(ctx context.Context, satelliteID storj.NodeID) (_ time.Time, err error) {
+	defer mon.Task()(&ctx, satelliteID)(&err)
+
+	query := `SELECT MIN(first) FROM (
+			SELECT DATETIME(MIN(created_at)) first
+				FROM bandwidth_usage
+				WHERE satellite_id = ?
+			UNION ALL
+			SELECT DATETIME(MIN(interval_start)) first
+				FROM bandwidth_usage_rollups
+				WHERE satellite_id = ?
+		);`
+
+	var first dbutil.NullTime
+	err = db.QueryRowContext(ctx, query, satelliteID, satelliteID).Scan(&first)
+	if err != nil {
+		return time.Time{}, ErrBandwidth.Wrap(err)
+	}
+	if !first.Valid {
+		return time.Time{}, nil
+	}
+	return first.Time.UTC(), nil
+}
+
 // Rollup bandwidth_usage data earlier than the current hour, then delete the rolled up records.
 func (db *bandwidthDB) Rollup(ctx context.Context) (err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -10,18 +10,21 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/gogo/protobuf/proto"
 	"github.com/google/go-cmp/cmp"
 	_ "github.com/mattn/go-sqlite3" // used indirectly.
 	"github.com/spacemonkeygo/monkit/v3"
 	"github.com/zeebo/errs"
 	"go.uber.org/zap"
 
+	"storj.io/common/pb"
 	"storj.io/storj/private/dbutil"
 	"storj.io/storj/private/dbutil/sqliteutil"
 	"storj.io/storj/private/migrate"
 	"storj.io/storj/private/tagsql"
 	"storj.io/storj/storage"
 	"storj.io/storj/storage/filestore"
+	"storj.io/storj/storage/packstore"
 	"storj.io/storj/storagenode"
 	"storj.io/storj/storagenode/bandwidth"
 	"storj.io/storj/storagenode/notifications"
@@ -30,6 +33,7 @@ import (
 	"storj.io/storj/storagenode/piecestore"
 	"storj.io/storj/storagenode/reputation"
 	"storj.io/storj/storagenode/satellites"
+	"storj.io/storj/storagenode/scrubber"
 	"storj.io/storj/storagenode/storageusage"
 )
 
@@ -80,6 +84,18 @@ type Config struct {
 	Info2   string
 	Driver  string // if unset, uses sqlite3
 	Pieces  string
+
+	// PiecesAllocated is the disk space allocated in Pieces. It's only used
+	// when there are AdditionalPieces directories.
+	PiecesAllocated  int64
+	AdditionalPieces pieces.DiskConfigs
+
+	// Packed configures packing small pieces into pack files.
+	Packed packstore.Config
+	// SkipPacked opens the pieces directories without their pack index,
+	// which is locked by a running node. It's used by commands which only
+	// need the databases, and packed pieces aren't served then.
+	SkipPacked bool
 }
 
 // DB contains access to different database tables
@@ -102,17 +118,20 @@ type DB struct {
 	usedSerialsDB     *usedSerialsDB
 	satellitesDB      *satellitesDB
 	notificationsDB   *notificationDB
+	scrubberDB        *scrubberDB
 
 	SQLDBs map[string]DBContainer
 }
 
 // New creates a new master database for storage node
 func New(log *zap.Logger, config Config) (*DB, error) {
-	piecesDir, err := filestore.NewDir(config.Pieces)
+	blobs, err := openBlobs(log, config.Pieces, config)
 	if err != nil {
 		return nil, err
 	}
-	pieces := filestore.New(log, piecesDir)
+	if len(config.AdditionalPieces) > 0 {
+		blobs = newBlobsPool(log, config, blobs)
+	}
 
 	deprecatedInfoDB := &deprecatedInfoDB{}
 	v0PieceInfoDB := &v0PieceInfoDB{}
@@ -125,12 +144,13 @@ func New(log *zap.Logger, config Config) (*DB, error) {
 	usedSerialsDB := &usedSerialsDB{}
 	satellitesDB := &satellitesDB{}
 	notificationsDB := &notificationDB{}
+	scrubberDB := &scrubberDB{}
 
 	db := &DB{
 		log:    log,
 		config: config,
 
-		pieces: pieces,
+		pieces: blobs,
 
 		dbDirectory: filepath.Dir(config.Info2),
 
@@ -145,6 +165,7 @@ func New(log *zap.Logger, config Config) (*DB, error) {
 		usedSerialsDB:     usedSerialsDB,
 		satellitesDB:      satellitesDB,
 		notificationsDB:   notificationsDB,
+		scrubberDB:        scrubberDB,
 
 		SQLDBs: map[string]DBContainer{
 			DeprecatedInfoDBName:  deprecatedInfoDB,
@@ -158,6 +179,7 @@ func New(log *zap.Logger, config Config) (*DB, error) {
 			UsedSerialsDBName:     usedSerialsDB,
 			SatellitesDBName:      satellitesDB,
 			NotificationsDBName:   notificationsDB,
+			ScrubberDBName:        scrubberDB,
 		},
 	}
 
@@ -168,6 +190,55 @@ func New(log *zap.Logger, config Config) (*DB, error) {
 	return db, nil
 }
 
+// openBlobs opens the blob storage in the directory, which packs small pieces
+// when enabled. The pack index is also opened when packing was disabled after
+// pieces were packed, so that they remain accessible.
+func openBlobs(log *zap.Logger, path string, config Config) (storage.Blobs, error) {
+	if !config.SkipPacked {
+		packed := config.Packed.Enabled
+		if !packed {
+			exists, err := packstore.Exists(path)
+			if err != nil {
+				return nil, err
+			}
+			packed = exists
+		}
+		if packed {
+			return packstore.NewAt(log.Named("packstore"), path, config.Packed)
+		}
+	}
+	dir, err := filestore.NewDir(path)
+	if err != nil {
+		return nil, err
+	}
+	return filestore.New(log, dir), nil
+}
+
+// newBlobsPool creates a blob storage which stores pieces in the pieces
+// directory and the additional directories. An additional directory which
+// can't be opened is left out, so that it doesn't prevent the node from
+// starting.
+func newBlobsPool(log *zap.Logger, config Config, blobs storage.Blobs) storage.Blobs {
+	disks := []pieces.PoolDisk{{
+		Path:      config.Pieces,
+		Blobs:     blobs,
+		Allocated: config.PiecesAllocated,
+	}}
+	for _, disk := range config.AdditionalPieces {
+		diskBlobs, err := openBlobs(log, disk.Path, config)
+		if err != nil {
+			log.Error("unable to open disk, not using it", zap.String("path", disk.Path), zap.Error(err))
+			continue
+		}
+		disks = append(disks, pieces.PoolDisk{
+			Path:      disk.Path,
+			Blobs:     diskBlobs,
+			Allocated: disk.Allocated.Int64(),
+		})
+	}
+	return pieces.NewBlobsPool(log.Named("pool"), disks)
+}
+
 // openDatabases opens all the SQLite3 storage node databases and returns if any fails to open successfully.
 func (db *DB) openDatabases() error {
 	// These objects have a Configure method to allow setting the underlining SQLDB connection
@@ -228,6 +299,11 @@ func (db *DB) openDatabases() error {
 	if err != nil {
 		return errs.Combine(err, db.closeDatabases())
 	}
+
+	err = db.openDatabase(ScrubberDBName)
+	if err != nil {
+		return errs.Combine(err, db.closeDatabases())
+	}
 	return nil
 }
 
@@ -352,7 +428,7 @@ func (db *DB) Preflight(ctx context.Context) (err error) {
 
 // Close closes any resources.
 func (db *DB) Close() error {
-	return db.closeDatabases()
+	return errs.Combine(db.closeDatabases(), db.pieces.Close())
 }
 
 // closeDatabases closes all the SQLite database connections and removes them from the associated maps.
@@ -429,6 +505,11 @@ func (db *DB) Notifications() notifications.DB {
 	return db.notificationsDB
 }
 
+// Scrubber returns the instance of the Scrubber database.
+func (db *DB) Scrubber() scrubber.DB {
+	return db.scrubberDB
+}
+
 // RawDatabases are required for testing purposes
 func (db *DB) RawDatabases() map[string]DBContainer {
 	return db.SQLDBs
@@ -1043,6 +1124,92 @@ func (db *DB) Migration(ctx context.Context) *migrate.Migration {
 					`UPDATE piece_space_used SET content_size = 0 WHERE content_size < 0`,
 				},
 			},
+			{
+				DB:          db.scrubberDB,
+				Description: "Create scrub_cursor and quarantined_pieces tables",
+				Version:     32,
+				Action: migrate.SQL{
+					`CREATE TABLE scrub_cursor (
+						id           INTEGER NOT NULL,
+						satellite_id BLOB NOT NULL,
+						piece_id     BLOB NOT NULL,
+						started_at   TIMESTAMP NOT NULL,
+						PRIMARY KEY (id)
+					)`,
+					`CREATE TABLE quarantined_pieces (
+						satellite_id   BLOB NOT NULL,
+						piece_id       BLOB NOT NULL,
+						reason         TEXT NOT NULL,
+						quarantined_at TIMESTAMP NOT NULL,
+						PRIMARY KEY (satellite_id, piece_id)
+					)`,
+				},
+			},
+			{
+				DB:          db.ordersDB,
+				Description: "Create order_archive_rollups table",
+				Version:     33,
+				Action: migrate.SQL{
+					`CREATE TABLE order_archive_rollups (
+						satellite_id BLOB NOT NULL,
+						status       INTEGER NOT NULL,
+						order_count  INTEGER NOT NULL,
+						amount       INTEGER NOT NULL,
+						PRIMARY KEY (satellite_id, status)
+					)`,
+				},
+			},
+			{
+				DB:          db.ordersDB,
+				Description: "Add amount and rejection reason to order_archive_",
+				Version:     34,
+				Action: migrate.Func(func(ctx context.Context, log *zap.Logger, _ tagsql.DB, tx tagsql.Tx) error {
+					_, err := tx.ExecContext(ctx, `
+						ALTER TABLE order_archive_ ADD COLUMN amount INTEGER NOT NULL DEFAULT 0;
+						ALTER TABLE order_archive_ ADD COLUMN reason TEXT NOT NULL DEFAULT '';
+					`)
+					if err != nil {
+						return ErrDatabase.Wrap(err)
+					}
+
+					// the amount was only stored in the serialized order
+					type archivedAmount struct {
+						rowid  int64
+						amount int64
+					}
+					var amounts []archivedAmount
+
+					rows, err := tx.QueryContext(ctx, `SELECT rowid, order_serialized FROM order_archive_`)
+					if err != nil {
+						return ErrDatabase.Wrap(err)
+					}
+					for rows.Next() {
+						var rowid int64
+						var orderSerialized []byte
+						if err := rows.Scan(&rowid, &orderSerialized); err != nil {
+							return ErrDatabase.Wrap(errs.Combine(err, rows.Close()))
+						}
+
+						var order pb.Order
+						if err := proto.Unmarshal(orderSerialized, &order); err != nil {
+							log.Warn("unable to read the amount of an archived order", zap.Error(err))
+							continue
+						}
+						amounts = append(amounts, archivedAmount{rowid: rowid, amount: order.Amount})
+					}
+					if err := errs.Combine(rows.Err(), rows.Close()); err != nil {
+						return ErrDatabase.Wrap(err)
+					}
+
+					for _, archived := range amounts {
+						_, err := tx.ExecContext(ctx, `UPDATE order_archive_ SET amount = ? WHERE rowid = ?`, archived.amount, archived.rowid)
+						if err != nil {
+							return ErrDatabase.Wrap(err)
+						}
+					}
+					return nil
+				}),
+			},
 		},
 	}
 }
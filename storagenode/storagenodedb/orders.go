@@ -6,6 +6,7 @@ package storagenodedb
 import (
 	"context"
 	"database/sql"
+	"strings"
 	"time"
 
 	"github.com/gogo/protobuf/proto"
@@ -218,38 +219,47 @@ func (db *ordersDB) Archive(ctx context.Context, archivedAt time.Time, requests
 func (db *ordersDB) archiveOne(ctx context.Context, tx tagsql.Tx, archivedAt time.Time, req orders.ArchiveRequest) (err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	result, err := tx.ExecContext(ctx, `
+	var orderSerialized []byte
+	err = tx.QueryRowContext(ctx, `
+		SELECT order_serialized
+		FROM unsent_order
+		WHERE satellite_id = ? AND serial_number = ?
+	`, req.Satellite, req.Serial).Scan(&orderSerialized)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return orders.OrderNotFoundError.New("satellite: %s, serial number: %s",
+				req.Satellite.String(), req.Serial.String(),
+			)
+		}
+		return ErrOrders.Wrap(err)
+	}
+
+	// the amount is stored separately to sum it in queries
+	var order pb.Order
+	if err := proto.Unmarshal(orderSerialized, &order); err != nil {
+		return ErrOrders.Wrap(err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
 		INSERT INTO order_archive_ (
 			satellite_id, serial_number,
 			order_limit_serialized, order_serialized,
 			uplink_cert_id,
-			status, archived_at
+			status, archived_at,
+			amount, reason
 		) SELECT
 			satellite_id, serial_number,
 			order_limit_serialized, order_serialized,
 			uplink_cert_id,
+			?, ?,
 			?, ?
 		FROM unsent_order
 		WHERE satellite_id = ? AND serial_number = ?;
 
 		DELETE FROM unsent_order
 		WHERE satellite_id = ? AND serial_number = ?;
-	`, int(req.Status), archivedAt, req.Satellite, req.Serial, req.Satellite, req.Serial)
-	if err != nil {
-		return ErrOrders.Wrap(err)
-	}
-
-	count, err := result.RowsAffected()
-	if err != nil {
-		return ErrOrders.Wrap(err)
-	}
-	if count == 0 {
-		return orders.OrderNotFoundError.New("satellite: %s, serial number: %s",
-			req.Satellite.String(), req.Serial.String(),
-		)
-	}
-
-	return nil
+	`, int(req.Status), archivedAt, order.Amount, req.Reason, req.Satellite, req.Serial, req.Satellite, req.Serial)
+	return ErrOrders.Wrap(err)
 }
 
 // ListArchived returns orders that have been sent.
@@ -257,7 +267,7 @@ func (db *ordersDB) ListArchived(ctx context.Context, limit int) (_ []*orders.Ar
 	defer mon.Task()(&ctx)(&err)
 
 	rows, err := db.QueryContext(ctx, `
-		SELECT order_limit_serialized, order_serialized, status, archived_at
+		SELECT order_limit_serialized, order_serialized, status, archived_at, reason
 		FROM order_archive_
 		LIMIT ?
 	`, limit)
@@ -271,53 +281,156 @@ func (db *ordersDB) ListArchived(ctx context.Context, limit int) (_ []*orders.Ar
 
 	var infos []*orders.ArchivedInfo
 	for rows.Next() {
-		var limitSerialized []byte
-		var orderSerialized []byte
-
-		var status int
-		var archivedAt time.Time
-
-		err := rows.Scan(&limitSerialized, &orderSerialized, &status, &archivedAt)
+		info, err := scanArchivedInfo(rows)
 		if err != nil {
-			return nil, ErrOrders.Wrap(err)
+			return nil, err
 		}
+		infos = append(infos, info)
+	}
 
-		var info orders.ArchivedInfo
-		info.Limit = &pb.OrderLimit{}
-		info.Order = &pb.Order{}
+	return infos, ErrOrders.Wrap(rows.Err())
+}
 
-		info.Status = orders.Status(status)
-		info.ArchivedAt = archivedAt
+// QueryArchived returns the archived orders matching the filter, most
+// recently archived first.
+func (db *ordersDB) QueryArchived(ctx context.Context, filter orders.ArchiveFilter) (_ []*orders.ArchivedInfo, err error) {
+	defer mon.Task()(&ctx)(&err)
 
-		err = proto.Unmarshal(limitSerialized, info.Limit)
+	var conditions []string
+	var args []interface{}
+	if filter.SatelliteID != nil {
+		conditions = append(conditions, "satellite_id = ?")
+		args = append(args, *filter.SatelliteID)
+	}
+	if filter.Status != nil {
+		conditions = append(conditions, "status = ?")
+		args = append(args, int(*filter.Status))
+	}
+	if !filter.ArchivedAfter.IsZero() {
+		conditions = append(conditions, "archived_at >= ?")
+		args = append(args, filter.ArchivedAfter.UTC())
+	}
+	if !filter.ArchivedBefore.IsZero() {
+		conditions = append(conditions, "archived_at < ?")
+		args = append(args, filter.ArchivedBefore.UTC())
+	}
+
+	query := `SELECT order_limit_serialized, order_serialized, status, archived_at, reason FROM order_archive_`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += ` ORDER BY archived_at DESC`
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, ErrOrders.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	infos := []*orders.ArchivedInfo{}
+	for rows.Next() {
+		info, err := scanArchivedInfo(rows)
 		if err != nil {
-			return nil, ErrOrders.Wrap(err)
+			return nil, err
 		}
 
-		err = proto.Unmarshal(orderSerialized, info.Order)
-		if err != nil {
-			return nil, ErrOrders.Wrap(err)
+		// the action is only stored in the serialized order limit
+		if !filter.Match(info) {
+			continue
 		}
 
-		infos = append(infos, &info)
+		infos = append(infos, info)
+		if filter.Limit > 0 && len(infos) >= filter.Limit {
+			break
+		}
 	}
 
 	return infos, ErrOrders.Wrap(rows.Err())
 }
 
-// CleanArchive deletes all entries older than ttl
+// ArchiveTotals returns the totals of the archived orders per satellite,
+// including the ones deleted from the archive.
+func (db *ordersDB) ArchiveTotals(ctx context.Context) (_ []orders.SatelliteTotals, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT satellite_id, status, SUM(order_count), SUM(amount)
+		FROM (
+			SELECT satellite_id, status, order_count, amount
+			FROM order_archive_rollups
+			UNION ALL
+			SELECT satellite_id, status, COUNT(*), SUM(amount)
+			FROM order_archive_
+			GROUP BY satellite_id, status
+		)
+		GROUP BY satellite_id, status
+		ORDER BY satellite_id
+	`)
+	if err != nil {
+		return nil, ErrOrders.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	var totals []orders.SatelliteTotals
+	for rows.Next() {
+		var satelliteID storj.NodeID
+		var status int
+		var count, amount int64
+		if err := rows.Scan(&satelliteID, &status, &count, &amount); err != nil {
+			return nil, ErrOrders.Wrap(err)
+		}
+
+		if len(totals) == 0 || totals[len(totals)-1].SatelliteID != satelliteID {
+			totals = append(totals, orders.SatelliteTotals{SatelliteID: satelliteID})
+		}
+		totals[len(totals)-1].Include(orders.Status(status), count, amount)
+	}
+
+	if totals == nil {
+		totals = []orders.SatelliteTotals{}
+	}
+	return totals, ErrOrders.Wrap(rows.Err())
+}
+
+// CleanArchive deletes all entries older than ttl, adding them to the
+// archive rollups.
 func (db *ordersDB) CleanArchive(ctx context.Context, ttl time.Duration) (_ int, err error) {
 	defer mon.Task()(&ctx)(&err)
 
 	deleteBefore := time.Now().UTC().Add(-1 * ttl)
-	result, err := db.ExecContext(ctx, `
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, ErrOrders.Wrap(err)
+	}
+	defer func() {
+		if err == nil {
+			err = ErrOrders.Wrap(tx.Commit())
+		} else {
+			err = errs.Combine(err, tx.Rollback())
+		}
+	}()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO order_archive_rollups (satellite_id, status, order_count, amount)
+		SELECT satellite_id, status, COUNT(*), SUM(amount)
+		FROM order_archive_
+		WHERE archived_at <= ?
+		GROUP BY satellite_id, status
+		ON CONFLICT(satellite_id, status)
+		DO UPDATE SET
+			order_count = order_archive_rollups.order_count + excluded.order_count,
+			amount = order_archive_rollups.amount + excluded.amount
+	`, deleteBefore)
+	if err != nil {
+		return 0, ErrOrders.Wrap(err)
+	}
+
+	result, err := tx.ExecContext(ctx, `
 		DELETE FROM order_archive_
 		WHERE archived_at <= ?
 	`, deleteBefore)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return 0, nil
-		}
 		return 0, ErrOrders.Wrap(err)
 	}
 	count, err := result.RowsAffected()
@@ -326,3 +439,40 @@ func (db *ordersDB) CleanArchive(ctx context.Context, ttl time.Duration) (_ int,
 	}
 	return int(count), nil
 }
+
+// scanArchivedInfo scans an archived order from the row with the columns
+// order_limit_serialized, order_serialized, status, archived_at and reason.
+func scanArchivedInfo(rows *sql.Rows) (*orders.ArchivedInfo, error) {
+	var limitSerialized []byte
+	var orderSerialized []byte
+
+	var status int
+	var archivedAt time.Time
+
+	var reason string
+
+	err := rows.Scan(&limitSerialized, &orderSerialized, &status, &archivedAt, &reason)
+	if err != nil {
+		return nil, ErrOrders.Wrap(err)
+	}
+
+	info := &orders.ArchivedInfo{
+		Limit:      &pb.OrderLimit{},
+		Order:      &pb.Order{},
+		Status:     orders.Status(status),
+		ArchivedAt: archivedAt,
+		Reason:     reason,
+	}
+
+	err = proto.Unmarshal(limitSerialized, info.Limit)
+	if err != nil {
+		return nil, ErrOrders.Wrap(err)
+	}
+
+	err = proto.Unmarshal(orderSerialized, info.Order)
+	if err != nil {
+		return nil, ErrOrders.Wrap(err)
+	}
+
+	return info, nil
+}
@@ -118,6 +118,11 @@ func Schema() map[string]*dbschema.Schema {
 				&dbschema.Table{
 					Name: "order_archive_",
 					Columns: []*dbschema.Column{
+						&dbschema.Column{
+							Name:       "amount",
+							Type:       "INTEGER",
+							IsNullable: false,
+						},
 						&dbschema.Column{
 							Name:       "archived_at",
 							Type:       "TIMESTAMP",
@@ -133,6 +138,11 @@ func Schema() map[string]*dbschema.Schema {
 							Type:       "BLOB",
 							IsNullable: false,
 						},
+						&dbschema.Column{
+							Name:       "reason",
+							Type:       "TEXT",
+							IsNullable: false,
+						},
 						&dbschema.Column{
 							Name:       "satellite_id",
 							Type:       "BLOB",
@@ -156,6 +166,32 @@ func Schema() map[string]*dbschema.Schema {
 						},
 					},
 				},
+				&dbschema.Table{
+					Name:       "order_archive_rollups",
+					PrimaryKey: []string{"satellite_id", "status"},
+					Columns: []*dbschema.Column{
+						&dbschema.Column{
+							Name:       "amount",
+							Type:       "INTEGER",
+							IsNullable: false,
+						},
+						&dbschema.Column{
+							Name:       "order_count",
+							Type:       "INTEGER",
+							IsNullable: false,
+						},
+						&dbschema.Column{
+							Name:       "satellite_id",
+							Type:       "BLOB",
+							IsNullable: false,
+						},
+						&dbschema.Column{
+							Name:       "status",
+							Type:       "INTEGER",
+							IsNullable: false,
+						},
+					},
+				},
 				&dbschema.Table{
 					Name: "unsent_order",
 					Columns: []*dbschema.Column{
@@ -460,6 +496,62 @@ func Schema() map[string]*dbschema.Schema {
 				},
 			},
 		},
+		"scrubber": &dbschema.Schema{
+			Tables: []*dbschema.Table{
+				&dbschema.Table{
+					Name:       "quarantined_pieces",
+					PrimaryKey: []string{"piece_id", "satellite_id"},
+					Columns: []*dbschema.Column{
+						&dbschema.Column{
+							Name:       "piece_id",
+							Type:       "BLOB",
+							IsNullable: false,
+						},
+						&dbschema.Column{
+							Name:       "quarantined_at",
+							Type:       "TIMESTAMP",
+							IsNullable: false,
+						},
+						&dbschema.Column{
+							Name:       "reason",
+							Type:       "TEXT",
+							IsNullable: false,
+						},
+						&dbschema.Column{
+							Name:       "satellite_id",
+							Type:       "BLOB",
+							IsNullable: false,
+						},
+					},
+				},
+				&dbschema.Table{
+					Name:       "scrub_cursor",
+					PrimaryKey: []string{"id"},
+					Columns: []*dbschema.Column{
+						&dbschema.Column{
+							Name:       "id",
+							Type:       "INTEGER",
+							IsNullable: false,
+						},
+						&dbschema.Column{
+							Name:       "piece_id",
+							Type:       "BLOB",
+							IsNullable: false,
+						},
+						&dbschema.Column{
+							Name:       "satellite_id",
+							Type:       "BLOB",
+							IsNullable: false,
+						},
+						&dbschema.Column{
+							Name:       "started_at",
+							Type:       "TIMESTAMP",
+							IsNullable: false,
+						},
+					},
+				},
+			},
+		},
 		"storage_usage": &dbschema.Schema{
 			Tables: []*dbschema.Table{
 				&dbschema.Table{
@@ -0,0 +1,128 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storagenodedb
+
+import (
+	"context"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/storj"
+	"storj.io/storj/storagenode/scrubber"
+)
+
+// ensures that scrubberDB implements scrubber.DB interface.
+var _ scrubber.DB = (*scrubberDB)(nil)
+
+// ErrScrubberDB represents errors from the scrubber database.
+var ErrScrubberDB = errs.Class("scrubberdb error")
+
+// ScrubberDBName represents the database name.
+const ScrubberDBName = "scrubber"
+
+// scrubberDB stores the scrubber progress and the quarantined pieces.
+//
+// architecture: Database
+type scrubberDB struct {
+	dbContainerImpl
+}
+
+// GetCursor returns the last verified piece.
+func (db *scrubberDB) GetCursor(ctx context.Context) (cursor scrubber.Cursor, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := db.QueryContext(ctx, `SELECT satellite_id, piece_id, started_at FROM scrub_cursor WHERE id = 0`)
+	if err != nil {
+		return cursor, ErrScrubberDB.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	if rows.Next() {
+		err := rows.Scan(&cursor.SatelliteID, &cursor.PieceID, &cursor.StartedAt)
+		if err != nil {
+			return cursor, ErrScrubberDB.Wrap(err)
+		}
+	}
+	return cursor, ErrScrubberDB.Wrap(rows.Err())
+}
+
+// SetCursor stores the last verified piece. A zero cursor clears it.
+func (db *scrubberDB) SetCursor(ctx context.Context, cursor scrubber.Cursor) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if cursor.IsZero() {
+		_, err = db.ExecContext(ctx, `DELETE FROM scrub_cursor`)
+		return ErrScrubberDB.Wrap(err)
+	}
+
+	_, err = db.ExecContext(ctx, `
+		INSERT OR REPLACE INTO scrub_cursor (id, satellite_id, piece_id, started_at)
+		VALUES (0, ?, ?, ?)
+	`, cursor.SatelliteID, cursor.PieceID, cursor.StartedAt.UTC())
+	return ErrScrubberDB.Wrap(err)
+}
+
+// Quarantine records a piece which failed verification.
+func (db *scrubberDB) Quarantine(ctx context.Context, piece scrubber.QuarantinedPiece) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	_, err = db.ExecContext(ctx, `
+		INSERT OR REPLACE INTO quarantined_pieces (satellite_id, piece_id, reason, quarantined_at)
+		VALUES (?, ?, ?, ?)
+	`, piece.SatelliteID, piece.PieceID, piece.Reason, piece.QuarantinedAt.UTC())
+	return ErrScrubberDB.Wrap(err)
+}
+
+// ListQuarantined returns the most recently quarantined pieces.
+func (db *scrubberDB) ListQuarantined(ctx context.Context, limit int) (_ []scrubber.QuarantinedPiece, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT satellite_id, piece_id, reason, quarantined_at
+		FROM quarantined_pieces
+		ORDER BY quarantined_at DESC
+		LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, ErrScrubberDB.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	var quarantined []scrubber.QuarantinedPiece
+	for rows.Next() {
+		var piece scrubber.QuarantinedPiece
+		err := rows.Scan(&piece.SatelliteID, &piece.PieceID, &piece.Reason, &piece.QuarantinedAt)
+		if err != nil {
+			return nil, ErrScrubberDB.Wrap(err)
+		}
+		quarantined = append(quarantined, piece)
+	}
+	return quarantined, ErrScrubberDB.Wrap(rows.Err())
+}
+
+// CountQuarantined returns the number of quarantined pieces per satellite.
+func (db *scrubberDB) CountQuarantined(ctx context.Context) (_ map[storj.NodeID]int64, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT satellite_id, COUNT(*)
+		FROM quarantined_pieces
+		GROUP BY satellite_id
+	`)
+	if err != nil {
+		return nil, ErrScrubberDB.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, rows.Close()) }()
+
+	counts := make(map[storj.NodeID]int64)
+	for rows.Next() {
+		var satelliteID storj.NodeID
+		var count int64
+		if err := rows.Scan(&satelliteID, &count); err != nil {
+			return nil, ErrScrubberDB.Wrap(err)
+		}
+		counts[satelliteID] = count
+	}
+	return counts, ErrScrubberDB.Wrap(rows.Err())
+}
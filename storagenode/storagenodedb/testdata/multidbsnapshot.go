@@ -46,6 +46,9 @@ var States = MultiDBStates{
 		&v29,
 		&v30,
 		&v31,
+		&v32,
+		&v33,
+		&v34,
 	},
 }
 
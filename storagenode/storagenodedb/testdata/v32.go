@@ -0,0 +1,49 @@
+// Copyright (C) 2020 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package testdata
+
+import (
+	"storj.io/storj/storagenode/storagenodedb"
+)
+
+var v32 = MultiDBState{
+	Version: 32,
+	DBStates: DBStates{
+		storagenodedb.UsedSerialsDBName:     v31.DBStates[storagenodedb.UsedSerialsDBName],
+		storagenodedb.StorageUsageDBName:    v31.DBStates[storagenodedb.StorageUsageDBName],
+		storagenodedb.ReputationDBName:      v31.DBStates[storagenodedb.ReputationDBName],
+		storagenodedb.PieceSpaceUsedDBName:  v31.DBStates[storagenodedb.PieceSpaceUsedDBName],
+		storagenodedb.PieceInfoDBName:       v31.DBStates[storagenodedb.PieceInfoDBName],
+		storagenodedb.PieceExpirationDBName: v31.DBStates[storagenodedb.PieceExpirationDBName],
+		storagenodedb.OrdersDBName:          v31.DBStates[storagenodedb.OrdersDBName],
+		storagenodedb.BandwidthDBName:       v31.DBStates[storagenodedb.BandwidthDBName],
+		storagenodedb.SatellitesDBName:      v31.DBStates[storagenodedb.SatellitesDBName],
+		storagenodedb.DeprecatedInfoDBName:  v31.DBStates[storagenodedb.DeprecatedInfoDBName],
+		storagenodedb.NotificationsDBName:   v31.DBStates[storagenodedb.NotificationsDBName],
+		storagenodedb.ScrubberDBName: &DBState{
+			SQL: `
+				-- table to hold the position of the piece scrubber
+				CREATE TABLE scrub_cursor (
+					id           INTEGER NOT NULL,
+					satellite_id BLOB NOT NULL,
+					piece_id     BLOB NOT NULL,
+					started_at   TIMESTAMP NOT NULL,
+					PRIMARY KEY (id)
+				);
+				-- table to hold the pieces which failed verification
+				CREATE TABLE quarantined_pieces (
+					satellite_id   BLOB NOT NULL,
+					piece_id       BLOB NOT NULL,
+					reason         TEXT NOT NULL,
+					quarantined_at TIMESTAMP NOT NULL,
+					PRIMARY KEY (satellite_id, piece_id)
+				);
+			`,
+			NewData: `
+				INSERT INTO scrub_cursor VALUES(0, X'0ed28abb2813e184a1e98b0f6605c4911ea468c7e8433eb583e0fca7ceac3000', X'0ed28abb2813e184a1e98b0f6605c4911ea468c7e8433eb583e0fca7ceac3000', '2020-02-01 12:00:00+00:00');
+				INSERT INTO quarantined_pieces VALUES(X'0ed28abb2813e184a1e98b0f6605c4911ea468c7e8433eb583e0fca7ceac3000', X'0ed28abb2813e184a1e98b0f6605c4911ea468c7e8433eb583e0fca7ceac3001', 'piece content doesn''t match the piece hash', '2020-02-01 12:00:00+00:00');
+			`,
+		},
+	},
+}
@@ -68,6 +68,8 @@ type ProcessConfig struct {
 type VersionConfig struct {
 	Version string `user:"true" help:"peer version" default:"v0.0.1"`
 	URL     string `user:"true" help:"URL for specific binary" default:""`
+	// Binaries are the digests and signatures of the binaries at the URL.
+	Binaries version.Binaries `user:"true" help:"SHA-256 digests and ed25519 signatures of the binary archives per platform, e.g. linux_amd64=SHA256:SIGNATURE" default:""`
 }
 
 // RolloutConfig represents the state of a version rollout configuration of a process.
@@ -274,12 +276,14 @@ func (rollout RolloutConfig) Validate() error {
 func configToProcess(binary ProcessConfig) (version.Process, error) {
 	process := version.Process{
 		Minimum: version.Version{
-			Version: binary.Minimum.Version,
-			URL:     binary.Minimum.URL,
+			Version:  binary.Minimum.Version,
+			URL:      binary.Minimum.URL,
+			Binaries: binary.Minimum.Binaries,
 		},
 		Suggested: version.Version{
-			Version: binary.Suggested.Version,
-			URL:     binary.Suggested.URL,
+			Version:  binary.Suggested.Version,
+			URL:      binary.Suggested.URL,
+			Binaries: binary.Suggested.Binaries,
 		},
 		Rollout: version.Rollout{
 			Cursor: version.PercentageToCursor(binary.Rollout.Cursor),